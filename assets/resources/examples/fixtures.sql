@@ -4,31 +4,41 @@ INSERT INTO cameras (id, camera_slug, camera_model, camera_make, camera_type, ca
 INSERT INTO cameras (id, camera_slug, camera_model, camera_make, camera_type, camera_description, camera_notes, created_at, updated_at, deleted_at) VALUES (5, 'canon-eos-6d', 'EOS 6D', 'Canon', '', '', '', '2020-01-06 02:06:35', '2020-01-06 02:06:54', null);
 INSERT INTO cameras (id, camera_slug, camera_model, camera_make, camera_type, camera_description, camera_notes, created_at, updated_at, deleted_at) VALUES (6, 'apple-iphone-6', 'iPhone 6', 'Apple', '', '', '', '2020-01-06 02:06:42', '2020-01-06 02:06:42', null);
 INSERT INTO cameras (id, camera_slug, camera_model, camera_make, camera_type, camera_description, camera_notes, created_at, updated_at, deleted_at) VALUES (7, 'apple-iphone-7', 'iPhone 7', 'Apple', '', '', '', '2020-01-06 02:06:51', '2020-01-06 02:06:51', null);
+INSERT INTO lenses (id, lens_slug, lens_model, lens_make, lens_type, lens_owner, lens_description, lens_notes, created_at, updated_at, deleted_at) VALUES (2, 'canon-ef-35mm-f-2-is-usm', 'EF35mm f/2 IS USM', 'Canon', '', '', '', '', '2020-01-06 02:06:32', '2020-01-06 02:06:32', null);
+INSERT INTO lenses (id, lens_slug, lens_model, lens_make, lens_type, lens_owner, lens_description, lens_notes, created_at, updated_at, deleted_at) VALUES (3, 'apple-iphone-se-back-camera-4-15mm-f-2-2', 'iPhone SE back camera 4.15mm f/2.2', 'Apple', '', '', '', '', '2020-01-06 02:06:42', '2020-01-06 02:06:42', null);
 INSERT INTO countries (id, country_slug, country_name, country_description, country_notes, country_photo_id) VALUES ('de', 'germany', 'Germany', 'Country Description', 'Country Notes', 0);
-INSERT INTO albums (id, album_uuid, album_name, album_slug, album_favorite) VALUES (2, '3', 'Christmas2030', 'christmas2030', 0);
-INSERT INTO albums (id, album_uuid, cover_uuid, album_name, album_slug, album_favorite) VALUES (1, '4', '654', 'Holiday2030', 'holiday-2030', 1);
-INSERT INTO albums (id, album_uuid, cover_uuid, album_name, album_slug, album_favorite) VALUES (3, '5', '654', 'Berlin2019', 'berlin-2019', 0);
+INSERT INTO albums (id, album_uuid, album_name, album_search, album_slug, album_favorite) VALUES (2, '3', 'Christmas2030', 'christmas2030', 'christmas2030', 0);
+INSERT INTO albums (id, album_uuid, cover_uuid, album_name, album_search, album_slug, album_favorite) VALUES (1, '4', '654', 'Holiday2030', 'holiday2030', 'holiday-2030', 1);
+INSERT INTO albums (id, album_uuid, cover_uuid, album_name, album_search, album_slug, album_favorite) VALUES (3, '5', '654', 'Berlin2019', 'berlin2019', 'berlin-2019', 0);
 INSERT INTO links (link_token, link_password, link_expires, share_uuid, can_comment, can_edit, created_at, updated_at) VALUES ('1jxf3jfn2k', 'somepassword', '2050-03-06 02:06:51', '4', 1, 0, '2020-03-06 02:06:51', '2020-03-28 14:06:00');
 INSERT INTO photos_albums (album_uuid, photo_uuid) VALUES ('4', '654');
 INSERT INTO photos_albums (album_uuid, photo_uuid) VALUES ('5', '658');
 INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing) VALUES (1, '1', '654', 'fq8es39w45bnlqdw', 'exampleFileName.jpg', 1, '123xxx', 0);
 INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing) VALUES (2, '2', '655', 'fq8ev8o1tl6umi0s', 'exampleDNGFile.dng', 1, '124xxx', 0);
 INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing) VALUES (3, '2', '655', 'fq8ev8t1x0bwje4e', 'exampleXmpFile.xmp', 0, '125xxx', 0);
-INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing) VALUES (4, '5', '658', 'fq8ev9c3sp88uwzq', 'bridge.jpg', 1, '126xxx', 0);
-INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing) VALUES (5, '6', '659', 'fq8evan3urz3i48d', 'reunion.jpg', 1, '127xxx', 0);
+INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing, file_type) VALUES (4, '5', '658', 'fq8ev9c3sp88uwzq', 'bridge.jpg', 1, '126xxx', 0, 'jpg');
+INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing, file_type) VALUES (5, '6', '659', 'fq8evan3urz3i48d', 'reunion.jpg', 1, '127xxx', 0, 'jpg');
+INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing, file_type) VALUES (6, '7', '660', 'fq8evb7ikt2nv5tj', 'taveuni.jpg', 1, '128xxx', 0, 'jpg');
 INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, photo_year, photo_month, photo_lat, photo_lng) VALUES (1, '654', 0, 3, 2790, 2, 48.519234, 9.057997);
 INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, photo_year, photo_month, photo_lat, photo_lng) VALUES (2, '655', 0, 3, 2790, 2, 48.519234, 9.057997);
 INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, photo_year, photo_month, photo_lat, photo_lng) VALUES (3, '656', 0, 3, 1990, 3, 48.519234, 9.057997);
 INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, photo_year, photo_month, photo_lat, photo_lng) VALUES (4, '657', 0, 3, 1990, 4, 48.519234, 9.057997);
-INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, taken_at, photo_lat, photo_lng, photo_title) VALUES (5, '658', 0, 3, '2014-07-17 15:42:12', 48.519235, 9.05799666, 'Neckarbrücke');
-INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, taken_at, photo_lat, photo_lng, photo_title) VALUES (6, '659', 0, 3, '2015-11-11 09:07:18', -21.342636, 55.466944, 'Reunion');
+INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, taken_at, photo_lat, photo_lng, photo_title, title_search, camera_id, lens_id, camera_serial, location_id) VALUES (5, '658', 0, 3, '2014-07-17 15:42:12', 48.519235, 9.05799666, 'Neckarbrücke', 'neckarbrucke', 5, 2, '032021001234', '4799fad2322c');
+INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, taken_at, photo_lat, photo_lng, photo_title, title_search, camera_id, lens_id, camera_serial, location_id) VALUES (6, '659', 0, 3, '2015-11-11 09:07:18', -21.342636, 55.466944, 'Reunion', 'reunion', 2, 3, 'F17QK1ABGRY6', '2182a0caf8c4');
+INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, taken_at, photo_lat, photo_lng, photo_title, title_search, camera_id, lens_id, location_id) VALUES (7, '660', 0, 3, '2016-05-10 10:00:00', -16.8, 179.95, 'Taveuni', 'taveuni', 1, 1, '6e1fe3c69e4c');
 INSERT INTO keywords (id, keyword, skip) VALUES (1, 'bridge', 0);
 INSERT INTO keywords (id, keyword, skip) VALUES (2, 'beach', 0);
+INSERT INTO keywords (id, keyword, skip) VALUES (3, 'neckarbrücke', 0);
+INSERT INTO keywords (id, keyword, skip) VALUES (4, 'neckarbrucke', 0);
 INSERT INTO photos_keywords (photo_id, keyword_id) VALUES (5, 1);
+INSERT INTO photos_keywords (photo_id, keyword_id) VALUES (5, 3);
+INSERT INTO photos_keywords (photo_id, keyword_id) VALUES (5, 4);
 INSERT INTO categories (label_id, category_id) VALUES ('1', '1');
-INSERT INTO labels (id, label_uuid, label_slug, custom_slug, label_name, label_priority, label_favorite) VALUES ('1', '12', 'flower', 'flower', 'Flower', 1, 1);
-INSERT INTO labels (id, label_uuid, label_slug, custom_slug, label_name, label_priority, label_favorite) VALUES ('2', '13', 'cake', 'kuchen', 'Cake', 5, 0);
-INSERT INTO labels (id, label_uuid, label_slug, custom_slug, label_name, label_priority, label_favorite) VALUES ('3', '14', 'cow', 'kuh', 'COW', -1, 1);
+INSERT INTO labels (id, label_uuid, label_slug, custom_slug, label_name, label_search, label_priority, label_favorite) VALUES ('1', '12', 'flower', 'flower', 'Flower', 'flower', 1, 1);
+INSERT INTO labels (id, label_uuid, label_slug, custom_slug, label_name, label_search, label_priority, label_favorite) VALUES ('2', '13', 'cake', 'kuchen', 'Cake', 'cake', 5, 0);
+INSERT INTO labels (id, label_uuid, label_slug, custom_slug, label_name, label_search, label_priority, label_favorite) VALUES ('3', '14', 'cow', 'kuh', 'COW', 'cow', -1, 1);
+INSERT INTO labels (id, label_uuid, label_slug, custom_slug, label_name, label_search, label_priority, label_favorite) VALUES ('4', '15', 'jane-doe', 'jane-doe', 'Jane Doe', 'jane doe', 0, 0);
 INSERT INTO photos_labels (photo_id, label_id, uncertainty, label_src) VALUES ('1', '1', '38', 'image');
 INSERT INTO photos_labels (photo_id, label_id, uncertainty, label_src) VALUES ('1', '2', '10', 'image');
+INSERT INTO photos_labels (photo_id, label_id, uncertainty, label_src) VALUES ('1', '4', '0', 'xmp');
 INSERT INTO accounts (id, acc_name, acc_owner, acc_url, acc_type, acc_key, acc_user, acc_pass, acc_error, acc_share, acc_sync, retry_limit, share_path, share_size, share_expires, sync_path, sync_interval, sync_upload, sync_download, sync_raw, created_at, updated_at, deleted_at) VALUES (1, 'Test Account', 'Admin', 'http://webdav-dummy/', 'webdav', '', 'admin', 'photoprism', null, true, false, 3, '/Photos', null, null, null, null, null, null, null, '2020-03-06 02:06:51', '2020-03-28 14:06:00', null);
@@ -10,6 +10,8 @@ import (
 )
 
 var version = "development"
+var commit = ""
+var built = ""
 var log = event.Log
 
 func main() {
@@ -17,6 +19,7 @@ func main() {
 	app.Name = "PhotoPrism"
 	app.Usage = "Browse your life in pictures"
 	app.Version = version
+	app.Metadata = map[string]interface{}{"commit": commit, "built": built}
 	app.Copyright = "(c) 2018-2020 PhotoPrism.org <hello@photoprism.org>"
 	app.EnableBashCompletion = true
 	app.Flags = config.GlobalFlags
@@ -33,6 +36,17 @@ func main() {
 		commands.ConfigCommand,
 		commands.VersionCommand,
 		commands.StatusCommand,
+		commands.DoctorCommand,
+		commands.InspectCommand,
+		commands.UsersCommand,
+		commands.TokensCommand,
+		commands.BackupCommand,
+		commands.RestoreCommand,
+		commands.PurgeCommand,
+		commands.CleanupCountsCommand,
+		commands.TitlesCommand,
+		commands.QualityCommand,
+		commands.PlacesCommand,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -0,0 +1,28 @@
+let downloadToken = "";
+
+/**
+ * Sets the token that must be added to thumbnail and download URLs if download tokens are required.
+ *
+ * @param {string} token
+ */
+export function setDownloadToken(token) {
+    downloadToken = token ? token : "";
+}
+
+export function getDownloadToken() {
+    return downloadToken;
+}
+
+/**
+ * Returns the URL with the current download token, if any.
+ *
+ * @param {string} url
+ * @returns {string}
+ */
+export function downloadUrl(url) {
+    if (!downloadToken) {
+        return url;
+    }
+
+    return url + (url.indexOf("?") === -1 ? "?" : "&") + "t=" + encodeURIComponent(downloadToken);
+}
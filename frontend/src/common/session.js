@@ -2,6 +2,7 @@ import Api from "./api";
 import Event from "pubsub-js";
 import User from "../model/user";
 import Socket from "./websocket";
+import {setDownloadToken} from "./download";
 
 export default class Session {
     /**
@@ -25,6 +26,8 @@ export default class Session {
 
         if (this.isUser()) {
             this.auth = true;
+            setDownloadToken(this.storage.getItem("download_token"));
+            this.refreshDownloadToken();
         }
 
         Event.subscribe("session.logout", () => {
@@ -78,9 +81,39 @@ export default class Session {
         this.session_token = null;
         this.storage.removeItem("session_token");
         delete Api.defaults.headers.common["X-Session-Token"];
+        this.deleteDownloadToken();
         this.deleteUser();
     }
 
+    setDownloadToken(token) {
+        this.storage.setItem("download_token", token);
+        setDownloadToken(token);
+    }
+
+    deleteDownloadToken() {
+        clearTimeout(this.downloadTimer);
+        this.storage.removeItem("download_token");
+        setDownloadToken("");
+    }
+
+    refreshDownloadToken() {
+        clearTimeout(this.downloadTimer);
+
+        if (!this.config.get("downloadTokens") || !this.getToken()) {
+            return Promise.resolve();
+        }
+
+        return Api.get("download-token").then(
+            (result) => {
+                this.setDownloadToken(result.data.token);
+
+                // Tokens are renewed when half of their lifetime has passed.
+                const ttl = new Date(result.data.expires).getTime() - Date.now();
+                this.downloadTimer = setTimeout(() => this.refreshDownloadToken(), Math.max(ttl / 2, 60000));
+            }
+        );
+    }
+
     setUser(user) {
         this.user = user;
         this.storage.setItem("user", JSON.stringify(user.getValues()));
@@ -156,6 +189,7 @@ export default class Session {
                 this.setConfig(result.data.config);
                 this.setToken(result.data.token);
                 this.setUser(new User(result.data.user));
+                this.refreshDownloadToken();
                 this.sendClientInfo();
             }
         );
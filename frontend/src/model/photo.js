@@ -1,5 +1,6 @@
 import RestModel from "model/rest";
 import Api from "common/api";
+import {downloadUrl} from "common/download";
 import {DateTime} from "luxon";
 
 const SrcAuto = "";
@@ -146,11 +147,11 @@ class Photo extends RestModel {
             return "/api/v1/svg/photo";
         }
 
-        return "/api/v1/thumbnails/" + hash + "/" + type;
+        return downloadUrl("/api/v1/thumbnails/" + hash + "/" + type);
     }
 
     getDownloadUrl() {
-        return "/api/v1/download/" + this.mainFileHash();
+        return downloadUrl("/api/v1/download/" + this.mainFileHash());
     }
 
     getThumbnailSrcset() {
@@ -1,5 +1,6 @@
 import Model from "./model";
 import Api from "../common/api";
+import {downloadUrl} from "../common/download";
 
 const thumbs = window.clientConfig.thumbnails;
 
@@ -44,7 +45,7 @@ class Thumb extends Model {
             uuid: photo.PhotoUUID,
             title: photo.PhotoTitle,
             favorite: photo.PhotoFavorite,
-            download_url: "/api/v1/download/" + photo.FileHash,
+            download_url: downloadUrl("/api/v1/download/" + photo.FileHash),
             original_w: photo.FileWidth,
             original_h: photo.FileHeight,
         };
@@ -67,7 +68,7 @@ class Thumb extends Model {
             uuid: photo.PhotoUUID,
             title: photo.PhotoTitle,
             favorite: photo.PhotoFavorite,
-            download_url: "/api/v1/download/" + file.FileHash,
+            download_url: downloadUrl("/api/v1/download/" + file.FileHash),
             original_w: file.FileWidth,
             original_h: file.FileHeight,
         };
@@ -130,7 +131,7 @@ class Thumb extends Model {
 
         }
 
-        return "/api/v1/thumbnails/" + file.FileHash + "/" + type;
+        return downloadUrl("/api/v1/thumbnails/" + file.FileHash + "/" + type);
     }
 }
 
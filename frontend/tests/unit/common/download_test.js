@@ -0,0 +1,27 @@
+import {downloadUrl, getDownloadToken, setDownloadToken} from "common/download";
+
+let chai = require("../../../node_modules/chai/chai");
+let assert = chai.assert;
+
+describe("common/download", () => {
+    afterEach(() => {
+        setDownloadToken("");
+    });
+
+    it("should return url without token",  () => {
+        assert.equal(downloadUrl("/api/v1/download/abc"), "/api/v1/download/abc");
+    });
+
+    it("should add token to url",  () => {
+        setDownloadToken("sabc.kb5f2a.xyz");
+        assert.equal(getDownloadToken(), "sabc.kb5f2a.xyz");
+        assert.equal(downloadUrl("/api/v1/download/abc"), "/api/v1/download/abc?t=sabc.kb5f2a.xyz");
+        assert.equal(downloadUrl("/api/v1/thumbnails/abc/fit_720?download=1"), "/api/v1/thumbnails/abc/fit_720?download=1&t=sabc.kb5f2a.xyz");
+    });
+
+    it("should clear token",  () => {
+        setDownloadToken("sabc.kb5f2a.xyz");
+        setDownloadToken(null);
+        assert.equal(downloadUrl("/api/v1/download/abc"), "/api/v1/download/abc");
+    });
+});
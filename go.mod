@@ -2,6 +2,7 @@ module github.com/photoprism/photoprism
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.3.3 // indirect
+	github.com/alicebob/miniredis/v2 v2.11.4
 	github.com/araddon/dateparse v0.0.0-20190622164848-0fb0a474d195
 	github.com/codahale/hdrhistogram v0.0.0-20161010025455-3a0bb77429bd // indirect
 	github.com/coreos/etcd v3.3.10+incompatible // indirect
@@ -14,15 +15,17 @@ require (
 	github.com/djherbis/times v1.2.0
 	github.com/dsoprea/go-exif/v2 v2.0.0-20200321225314-640175a69fe4
 	github.com/dsoprea/go-jpeg-image-structure v0.0.0-20200419165912-75b7a4f392e6
-	github.com/dsoprea/go-logging v0.0.0-20200401235223-7e979d0e0d02 // indirect
+	github.com/dsoprea/go-logging v0.0.0-20200401235223-7e979d0e0d02
 	github.com/dsoprea/go-png-image-structure v0.0.0-20200402000326-c0fdb803026f
 	github.com/dsoprea/go-utility v0.0.0-20200412174200-5aee815e0920 // indirect
 	github.com/dustin/go-humanize v1.0.0 // indirect
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/gin-gonic/gin v1.6.2
 	github.com/go-errors/errors v1.0.2 // indirect
 	github.com/golang/geo v0.0.0-20200319012246-673a6f80352d
 	github.com/golang/protobuf v1.3.5 // indirect
 	github.com/golang/snappy v0.0.1 // indirect
+	github.com/gomodule/redigo v1.8.2
 	github.com/google/go-cmp v0.3.1 // indirect
 	github.com/google/open-location-code/go v0.0.0-20191230190541-a6eb95b4d2f9
 	github.com/gorilla/websocket v1.4.2
@@ -51,6 +54,7 @@ require (
 	github.com/pingcap/tidb-tools v2.1.3-0.20190116051332-34c808eef588+incompatible
 	github.com/pkg/errors v0.8.1
 	github.com/prometheus/client_golang v0.9.3-0.20190127221311-3c4408c8b829
+	github.com/rainycape/unidecode v0.0.0-20150907023854-cb7f23ec59be
 	github.com/remyoudompheng/bigfft v0.0.0-20190512091148-babf20351dd7 // indirect
 	github.com/satori/go.uuid v1.2.0
 	github.com/sevlyar/go-daemon v0.1.5
@@ -75,10 +79,10 @@ require (
 	github.com/urfave/cli v1.22.4
 	go.uber.org/atomic v1.4.0 // indirect
 	golang.org/x/crypto v0.0.0-20200323165209-0ec3e9974c59
-	golang.org/x/image v0.0.0-20200119044424-58c23975cae1 // indirect
+	golang.org/x/image v0.0.0-20200119044424-58c23975cae1
 	golang.org/x/net v0.0.0-20200421231249-e086a090c8fd
 	golang.org/x/sys v0.0.0-20200331124033-c3d80250170d // indirect
-	golang.org/x/time v0.0.0-20191024005414-555d28b269f0 // indirect
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 	golang.org/x/tools v0.0.0-20200401192744-099440627f01 // indirect
 	gopkg.in/mgo.v2 v2.0.0-20180705113604-9856a29383ce // indirect
 	gopkg.in/stretchr/testify.v1 v1.2.2 // indirect
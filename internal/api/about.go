@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+// GET /api/v1/about
+//
+// Returns the version, build, runtime, enabled features, external binaries and database of this
+// instance for support requests. Passwords are masked.
+func GetAbout(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/about", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		c.JSON(http.StatusOK, conf.About())
+	})
+}
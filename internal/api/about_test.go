@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestGetAbout(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetAbout(router, conf)
+
+		result := performAdminRequest(app, "GET", "/api/v1/about", "")
+		body := result.Body.String()
+
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, conf.Version(), gjson.Get(body, "version").String())
+		assert.True(t, gjson.Get(body, "runtime.go").Exists())
+		assert.True(t, gjson.Get(body, "features.nsfw").Exists())
+		assert.Equal(t, "exiftool", gjson.Get(body, "tools.0.name").String())
+		assert.Equal(t, conf.DatabaseDriver(), gjson.Get(body, "database.driver").String())
+		assert.Equal(t, "photoprism:***@tcp(photoprism-db:4001)/photoprism?parseTime=true", gjson.Get(body, "database.dsn").String())
+		assert.NotContains(t, body, "photoprism:photoprism@")
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetAbout(router, conf)
+
+		result := performRoleRequest(app, "viewer", "GET", "/api/v1/about", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
@@ -114,7 +114,7 @@ func ShareWithAccount(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		id := ParseUint(c.Param("id"))
 
 		m, err := q.AccountByID(id)
@@ -131,6 +131,11 @@ func ShareWithAccount(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if q.PhotosVisibleCount(f.Photos) < len(f.Photos) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
 		dst := f.Destination
 		files, err := q.FilesByUUID(f.Photos, 1000, 0)
 
@@ -167,6 +172,11 @@ func CreateAccount(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if err := f.Validate(); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
 		if err := f.ServiceDiscovery(); err != nil {
 			log.Error(err)
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
@@ -227,6 +237,11 @@ func UpdateAccount(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if err := f.Validate(); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
 		// 3) Save model with values from form
 		if err := m.Save(f, conf.Db()); err != nil {
 			log.Error(err)
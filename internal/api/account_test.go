@@ -39,3 +39,12 @@ func TestGetAccount(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 }
+
+func TestUpdateAccount(t *testing.T) {
+	t.Run("invalid sync window", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		UpdateAccount(router, conf)
+		result := PerformRequestWithBody(app, "PUT", "/api/v1/accounts/1", `{"SyncWindow": "night"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
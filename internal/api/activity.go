@@ -0,0 +1,48 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/mutex"
+)
+
+// activityRelease is the context key of the function that stops counting a request as activity.
+const activityRelease = "activity.release"
+
+// TrackActivity returns a middleware that counts authorized api requests in progress, so that background
+// workers can pause while users are browsing. Websocket connections are ignored, as they remain open, and
+// so are anonymous requests, unless they contain a valid download token.
+func TrackActivity(conf *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		if Unauthorized(c, conf) && (c.Query("t") == "" || !DownloadAuthorized(c, conf)) {
+			c.Next()
+			return
+		}
+
+		var once sync.Once
+
+		release := func() { once.Do(mutex.Interactive.Done) }
+
+		mutex.Interactive.Start()
+		defer release()
+
+		c.Set(activityRelease, release)
+
+		c.Next()
+	}
+}
+
+// ReleaseActivity stops counting the current request as activity. Handlers that run background workers
+// like indexing or importing must call it first, as the workers would otherwise wait for themselves.
+func ReleaseActivity(c *gin.Context) {
+	if release, ok := c.Get(activityRelease); ok {
+		release.(func())()
+	}
+}
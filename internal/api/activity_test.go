@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackActivity(t *testing.T) {
+	defer mutex.Interactive.Configure(2*time.Second, nil)
+
+	t.Run("authorized", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		mutex.Interactive.Configure(time.Hour, nil)
+		router.Use(TrackActivity(conf))
+
+		var busy bool
+
+		router.GET("/photos", func(c *gin.Context) {
+			busy = mutex.Interactive.Busy()
+			c.Status(http.StatusOK)
+		})
+
+		r := PerformRequest(app, "GET", "/api/v1/photos")
+
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.True(t, busy)
+
+		// Workers wait for the idle delay after the last request.
+		assert.True(t, mutex.Interactive.Busy())
+
+		mutex.Interactive.Configure(0, nil)
+		assert.False(t, mutex.Interactive.Busy())
+	})
+	t.Run("anonymous", func(t *testing.T) {
+		app, router, conf, cleanup := newDownloadTokenTest(t, false)
+		defer cleanup()
+
+		mutex.Interactive.Configure(time.Hour, nil)
+		router.Use(TrackActivity(conf))
+
+		var busy bool
+
+		router.GET("/photos", func(c *gin.Context) {
+			busy = mutex.Interactive.Busy()
+			c.Status(http.StatusUnauthorized)
+		})
+
+		PerformRequest(app, "GET", "/api/v1/photos")
+
+		assert.False(t, busy)
+
+		performRoleRequest(app, entity.RoleAdmin, "GET", "/api/v1/photos", "")
+
+		assert.True(t, busy)
+		mutex.Interactive.Configure(0, nil)
+	})
+	t.Run("released", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		mutex.Interactive.Configure(time.Hour, nil)
+		router.Use(TrackActivity(conf))
+
+		var paused time.Duration
+
+		// Handlers running workers in the request must not wait for themselves.
+		router.POST("/index", func(c *gin.Context) {
+			ReleaseActivity(c)
+			mutex.Interactive.Configure(0, nil)
+			paused = mutex.Interactive.Pause(func() bool { return false })
+			c.Status(http.StatusOK)
+		})
+
+		r := PerformRequest(app, "POST", "/api/v1/index")
+
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, time.Duration(0), paused)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/photoprism/photoprism/internal/cache"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
@@ -35,7 +36,7 @@ func GetAlbums(router *gin.RouterGroup, conf *config.Config) {
 
 		var f form.AlbumSearch
 
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		err := c.MustBindWith(&f, binding.Form)
 
 		if err != nil {
@@ -49,10 +50,17 @@ func GetAlbums(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		// TODO c.Header("X-Count", strconv.Itoa(count))
-		c.Header("X-Limit", strconv.Itoa(f.Count))
+		limit := query.Limit(f.Count)
+
+		c.Header("X-Count", strconv.Itoa(len(result)))
+		c.Header("X-Limit", strconv.Itoa(limit))
 		c.Header("X-Offset", strconv.Itoa(f.Offset))
 
+		if err := AddResultHeaders(c, len(result), f.Offset, limit, func() (int, error) { return q.AlbumsTotal(f) }); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
 		c.JSON(http.StatusOK, result)
 	})
 }
@@ -61,7 +69,7 @@ func GetAlbums(router *gin.RouterGroup, conf *config.Config) {
 func GetAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/albums/:uuid", func(c *gin.Context) {
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		m, err := q.AlbumByUUID(id)
 
 		if err != nil {
@@ -91,6 +99,17 @@ func CreateAlbum(router *gin.RouterGroup, conf *config.Config) {
 		q := query.New(conf.Db())
 		m := entity.NewAlbum(f.AlbumName)
 		m.AlbumFavorite = f.AlbumFavorite
+		m.AlbumRoot = f.AlbumRoot
+
+		if err := m.SetType(f.AlbumType, f.AlbumPath, f.AlbumFilter); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if !albumFolderExists(conf, m) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrFolderNotFound)
+			return
+		}
 
 		log.Debugf("create album: %+v %+v", f, m)
 
@@ -143,6 +162,17 @@ func UpdateAlbum(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		// The type can't be changed, but the folder path and search filter are validated like on create.
+		check := entity.Album{AlbumRoot: f.AlbumRoot}
+
+		if err := check.SetType(m.AlbumType, f.AlbumPath, f.AlbumFilter); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		} else if !albumFolderExists(conf, &check) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrFolderNotFound)
+			return
+		}
+
 		if err := m.Save(f, conf.Db()); err != nil {
 			log.Error(err)
 			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrSaveFailed)
@@ -176,9 +206,11 @@ func DeleteAlbum(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		PublishAlbumEvent(EntityDeleted, id, c, q)
-
-		conf.Db().Delete(&m)
+		if err := entity.DeleteAlbums(conf.Db(), []string{m.AlbumUUID}); err != nil {
+			log.Errorf("album: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrSaveFailed)
+			return
+		}
 
 		event.Publish("config.updated", event.Data(conf.ClientConfig()))
 		event.Success(fmt.Sprintf("album \"%s\" deleted", m.AlbumName))
@@ -272,6 +304,11 @@ func AddPhotosToAlbum(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if !a.IsManual() {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrAlbumNotManual)
+			return
+		}
+
 		photos, err := q.PhotoSelection(f)
 
 		if err != nil {
@@ -328,6 +365,11 @@ func RemovePhotosFromAlbum(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if !a.IsManual() {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrAlbumNotManual)
+			return
+		}
+
 		db := conf.Db()
 
 		db.Where("album_uuid = ? AND photo_uuid IN (?)", a.AlbumUUID, f.Photos).Delete(&entity.PhotoAlbum{})
@@ -345,7 +387,7 @@ func DownloadAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/albums/:uuid/download", func(c *gin.Context) {
 		start := time.Now()
 
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		a, err := q.AlbumByUUID(c.Param("uuid"))
 
 		if err != nil {
@@ -389,7 +431,7 @@ func DownloadAlbum(router *gin.RouterGroup, conf *config.Config) {
 		defer zipWriter.Close()
 
 		for _, f := range p {
-			fileName := path.Join(conf.OriginalsPath(), f.FileName)
+			fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 			fileAlias := f.ShareFileName()
 
 			if fs.FileExists(fileName) {
@@ -442,18 +484,22 @@ func AlbumThumbnail(router *gin.RouterGroup, conf *config.Config) {
 
 		if !ok {
 			log.Errorf("album: invalid thumb type %s", typeName)
-			c.Data(http.StatusBadRequest, "image/svg+xml", photoIconSvg)
+			c.Data(http.StatusNotFound, "image/svg+xml", photoIconSvg)
 			return
 		}
 
-		q := query.New(conf.Db())
+		viewer := SessionViewer(c, conf)
+		q := query.New(conf.Db()).As(viewer)
 
+		// Thumbnails are cached per viewer, as albums and photos may not be visible to everyone.
 		gc := conf.Cache()
-		cacheKey := fmt.Sprintf("album-thumbnail:%s:%s", uuid, typeName)
+		cacheKey := fmt.Sprintf("album-thumbnail:%s:%s:%s:%s:%t", uuid, typeName, viewer.Role, viewer.User, viewer.Public)
+
+		var cacheData []byte
 
-		if cacheData, ok := gc.Get(cacheKey); ok {
+		if cache.Load(gc, cacheKey, &cacheData) {
 			log.Debugf("album: %s cache hit [%s]", cacheKey, time.Since(start))
-			c.Data(http.StatusOK, "image/jpeg", cacheData.([]byte))
+			c.Data(http.StatusOK, http.DetectContentType(cacheData), cacheData)
 			return
 		}
 
@@ -465,7 +511,7 @@ func AlbumThumbnail(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		fileName := path.Join(conf.OriginalsPath(), f.FileName)
+		fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 
 		if !fs.FileExists(fileName) {
 			log.Errorf("album: could not find original for %s", fileName)
@@ -481,12 +527,18 @@ func AlbumThumbnail(router *gin.RouterGroup, conf *config.Config) {
 		if thumbType.ExceedsLimit() && c.Query("download") == "" {
 			log.Debugf("album: using original, thumbnail size exceeds limit (width %d, height %d)", thumbType.Width, thumbType.Height)
 
-			c.File(fileName)
+			serveOriginal(c, fileName)
+
+			return
+		}
+
+		thumbnail, err := renderThumbnail(c, conf, fileName, f.FileHash, typeName)
 
+		if abortBusy(c, err) {
 			return
 		}
 
-		if thumbnail, err := thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...); err == nil {
+		if err == nil {
 			if c.Query("download") != "" {
 				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", f.ShareFileName()))
 			}
@@ -503,10 +555,19 @@ func AlbumThumbnail(router *gin.RouterGroup, conf *config.Config) {
 
 			log.Debugf("album: %s cached [%s]", cacheKey, time.Since(start))
 
-			c.Data(http.StatusOK, "image/jpeg", thumbData)
+			c.Data(http.StatusOK, thumb.ContentType(thumbnail), thumbData)
 		} else {
 			log.Errorf("album: %s", err)
 			c.Data(http.StatusBadRequest, "image/svg+xml", photoIconSvg)
 		}
 	})
 }
+
+// albumFolderExists returns false if the album is a folder album and the folder doesn't exist in originals.
+func albumFolderExists(conf *config.Config, m *entity.Album) bool {
+	if !m.IsFolder() {
+		return true
+	}
+
+	return fs.PathExists(conf.OriginalsFileName(m.AlbumRoot, m.AlbumPath))
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/albums/:uuid/access
+//
+// Returns the visibility of an album and the roles or users it's restricted to.
+func GetAlbumAccess(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/albums/:uuid/access", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		db := conf.Db()
+		q := query.New(db)
+
+		m, err := q.AlbumByUUID(c.Param("uuid"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, m.Access(db))
+	})
+}
+
+// PUT /api/v1/albums/:uuid/access
+//
+// Changes the visibility of an album, e.g. {"visibility": "roles", "roles": ["family"]}.
+func UpdateAlbumAccess(router *gin.RouterGroup, conf *config.Config) {
+	router.PUT("/albums/:uuid/access", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		var f form.AlbumAccess
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		db := conf.Db()
+		q := query.New(db)
+
+		m, err := q.AlbumByUUID(c.Param("uuid"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			return
+		}
+
+		if err := m.SetAccess(db, entity.AlbumAccess(f)); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		event.Success("album permissions saved")
+
+		PublishAlbumEvent(EntityUpdated, m.AlbumUUID, c, q)
+
+		c.JSON(http.StatusOK, m.Access(db))
+	})
+}
+
+// POST /api/v1/batch/albums/access
+//
+// Applies a visibility template to many albums, e.g. {"albums": [...], "template": {"visibility": "everyone"}}.
+func BatchAlbumsAccess(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/batch/albums/access", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		var f form.AlbumAccessBatch
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if len(f.Albums) == 0 {
+			log.Error("no albums selected")
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst("no albums selected")})
+			return
+		}
+
+		template := entity.AlbumAccess(f.Template)
+
+		if err := template.Validate(); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		log.Infof("albums: changing visibility of %#v to %s", f.Albums, template.Visibility)
+
+		db := conf.Db()
+
+		var albums []entity.Album
+
+		if err := db.Where("album_uuid IN (?)", f.Albums).Find(&albums).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		for _, m := range albums {
+			if err := m.SetAccess(db, template); err != nil {
+				log.Errorf("albums: %s", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, ErrSaveFailed)
+				return
+			}
+		}
+
+		event.EntitiesUpdated("albums", f.Albums)
+
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("visibility of %d albums changed", len(albums))})
+	})
+}
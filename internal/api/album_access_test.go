@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// performAdminRequest performs an API request with an admin session token.
+func performAdminRequest(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	service.SetConfig(config.TestConfig())
+
+	token := service.Session().Create(gin.H{"UserName": "admin", "Role": "admin"})
+	defer service.Session().Delete(token)
+
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-Session-Token", token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAlbumAccess(t *testing.T) {
+	t.Run("anonymous", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetAlbumAccess(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/albums/4/access")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("admin", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetAlbumAccess(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/albums/4/access", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "\"visibility\":\"everyone\"")
+	})
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetAlbumAccess(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/albums/xxx/access", "")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
+
+func TestUpdateAlbumAccess(t *testing.T) {
+	t.Run("anonymous", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		UpdateAlbumAccess(router, ctx)
+		result := PerformRequestWithBody(app, "PUT", "/api/v1/albums/4/access", `{"visibility": "everyone"}`)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("invalid visibility", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		UpdateAlbumAccess(router, ctx)
+		result := performAdminRequest(app, "PUT", "/api/v1/albums/4/access", `{"visibility": "friends"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("everyone", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		UpdateAlbumAccess(router, ctx)
+		result := performAdminRequest(app, "PUT", "/api/v1/albums/4/access", `{"visibility": "everyone"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
+
+func TestBatchAlbumsAccess(t *testing.T) {
+	t.Run("no albums", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		BatchAlbumsAccess(router, ctx)
+		result := performAdminRequest(app, "POST", "/api/v1/batch/albums/access", `{"albums": [], "template": {"visibility": "everyone"}}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("invalid template", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		BatchAlbumsAccess(router, ctx)
+		result := performAdminRequest(app, "POST", "/api/v1/batch/albums/access", `{"albums": ["4"], "template": {"visibility": "roles"}}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("everyone", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		BatchAlbumsAccess(router, ctx)
+		result := performAdminRequest(app, "POST", "/api/v1/batch/albums/access", `{"albums": ["4"], "template": {"visibility": "everyone"}}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
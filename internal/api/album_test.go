@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestGetAlbums(t *testing.T) {
@@ -40,6 +42,49 @@ func TestGetAlbum(t *testing.T) {
 	})
 }
 
+func TestCreateAlbum(t *testing.T) {
+	t.Run("invalid type", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateAlbum(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/albums", `{"AlbumName": "Invalid", "AlbumType": "moment"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("smart album without filter", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateAlbum(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/albums", `{"AlbumName": "Smart", "AlbumType": "smart"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("folder not found", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateAlbum(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/albums", `{"AlbumName": "Folder", "AlbumType": "folder", "AlbumPath": "does-not-exist"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+		assert.Equal(t, "Folder not found", gjson.Get(result.Body.String(), "error").String())
+	})
+	t.Run("smart album", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateAlbum(router, conf)
+		AddPhotosToAlbum(router, conf)
+		RemovePhotosFromAlbum(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/albums", `{"AlbumName": "Smart Favorites", "AlbumType": "smart", "AlbumFilter": "favorite:true"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		uuid := gjson.Get(result.Body.String(), "AlbumUUID").String()
+
+		defer entity.DeleteAlbums(conf.Db(), []string{uuid})
+
+		assert.Equal(t, "smart", gjson.Get(result.Body.String(), "AlbumType").String())
+
+		// Photos of smart albums can't be added or removed manually.
+		result = PerformRequestWithBody(app, "POST", "/api/v1/albums/"+uuid+"/photos", `{"photos": ["654"]}`)
+		assert.Equal(t, http.StatusForbidden, result.Code)
+
+		result = PerformRequestWithBody(app, "DELETE", "/api/v1/albums/"+uuid+"/photos", `{"photos": ["654"]}`)
+		assert.Equal(t, http.StatusForbidden, result.Code)
+	})
+}
+
 func TestDeleteAlbum(t *testing.T) {
 	t.Run("delete existing album", func(t *testing.T) {
 		app, router, conf := NewApiTest()
@@ -128,7 +173,7 @@ func TestAlbumThumbnail(t *testing.T) {
 		AlbumThumbnail(router, ctx)
 		result := PerformRequest(app, "GET", "/api/v1/albums/1/thumbnail/xxx")
 
-		assert.Equal(t, http.StatusBadRequest, result.Code)
+		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 	t.Run("album has no photo (because is not existing)", func(t *testing.T) {
 		app, router, ctx := NewApiTest()
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gosimple/slug"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// albumExportSizes maps the size parameter to the thumbnail type used, an empty type means original files.
+var albumExportSizes = map[string]string{
+	"":         "",
+	"original": "",
+	"2048":     "fit_2048",
+}
+
+// albumExportSkipped is the name of the file listing files that were skipped because their
+// meta data couldn't be removed.
+const albumExportSkipped = "skipped.txt"
+
+// GET /api/v1/albums/:uuid/dl?size=original|2048&meta=keep|strip
+//
+// Streams a ZIP archive with all photos of an album, the archive is created on the fly.
+// Location and camera details are removed if meta is "strip", other formats than JPEG require exiftool.
+// Files that can't be stripped are skipped and listed in skipped.txt.
+func ExportAlbum(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/albums/:uuid/dl", func(c *gin.Context) {
+		link, ok := downloadAccess(c, conf)
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		start := time.Now()
+
+		typeName, ok := albumExportSizes[c.Query("size")]
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(fmt.Sprintf("invalid size %s, use original or 2048", c.Query("size")))})
+			return
+		}
+
+		var strip bool
+
+		switch c.Query("meta") {
+		case "", "keep":
+			strip = false
+		case "strip":
+			strip = true
+		default:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(fmt.Sprintf("invalid meta %s, use keep or strip", c.Query("meta")))})
+			return
+		}
+
+		// Visitors of a sharing link may only export the shared album.
+		if link != nil && link.ShareUUID != c.Param("uuid") {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			return
+		}
+
+		q := downloadQuery(c, conf, link)
+		a, err := q.AlbumByUUID(c.Param("uuid"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			return
+		}
+
+		p, _, err := q.Photos(form.PhotoSearch{
+			Album:  a.AlbumUUID,
+			Count:  10000,
+			Offset: 0,
+		})
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.zip", strings.Title(a.AlbumSlug)))
+		c.Status(http.StatusOK)
+
+		zipWriter := zip.NewWriter(c.Writer)
+		names := make(map[string]bool, len(p))
+		ctx := c.Request.Context()
+		count := 0
+
+		// Files with meta data that couldn't be removed are listed in the archive.
+		var skipped []string
+
+		for _, photo := range p {
+			// Stop if the client has gone away.
+			if err := ctx.Err(); err != nil {
+				log.Infof("album: export of %s canceled after %d files", a.AlbumUUID, count)
+				return
+			}
+
+			fileName := conf.OriginalsFileName(photo.FileRoot, photo.FileName)
+
+			if !fs.FileExists(fileName) {
+				log.Warnf("album: \"%s\" is missing", photo.FileName)
+				continue
+			}
+
+			if typeName != "" {
+				if fileName, err = renderThumbnail(c, conf, fileName, photo.FileHash, typeName); err != nil {
+					log.Errorf("album: can't export %s (%s)", photo.FileName, err)
+					continue
+				}
+			}
+
+			var data []byte
+
+			// Thumbnails only contain the orientation and copyright, originals must be stripped.
+			if strip && typeName == "" {
+				if fs.GetFileType(fileName) == fs.TypeJpeg {
+					data, err = strippedJpeg(fileName)
+				} else {
+					data, err = meta.Strip(fileName, thumb.Copyright)
+				}
+
+				if err != nil {
+					log.Errorf("album: can't export %s (%s)", photo.FileName, err)
+					skipped = append(skipped, fmt.Sprintf("%s: %s", photo.FileName, err))
+					continue
+				}
+			}
+
+			alias := exportFileName(photo, filepath.Ext(fileName), names)
+
+			if err := addExportFile(zipWriter, fileName, alias, photo.TakenAt, data); err != nil {
+				log.Errorf("album: can't add \"%s\" to zip (%s)", photo.FileName, err)
+				return
+			}
+
+			c.Writer.Flush()
+			count++
+		}
+
+		if len(skipped) > 0 {
+			report := []byte(strings.Join(skipped, "\n") + "\n")
+
+			if err := addExportFile(zipWriter, "", albumExportSkipped, time.Now(), report); err != nil {
+				log.Errorf("album: can't add \"%s\" to zip (%s)", albumExportSkipped, err)
+				return
+			}
+		}
+
+		if err := zipWriter.Close(); err != nil {
+			log.Errorf("album: %s", err)
+			return
+		}
+
+		log.Infof("album: exported %d files of %s in %s", count, a.AlbumUUID, time.Since(start))
+	})
+}
+
+// exportFileName returns a unique file name based on the taken date and title of a photo.
+func exportFileName(p query.PhotoResult, ext string, names map[string]bool) string {
+	var name string
+
+	if p.PhotoTitle != "" {
+		name = strings.Title(slug.MakeLang(p.PhotoTitle, "en"))
+	} else {
+		name = p.PhotoUUID
+	}
+
+	base := fmt.Sprintf("%s-%s", p.TakenAtLocal.Format("20060102-150405"), name)
+	ext = strings.ToLower(ext)
+	result := base + ext
+
+	for i := 2; names[result]; i++ {
+		result = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+
+	names[result] = true
+
+	return result
+}
+
+// addExportFile adds a file to a zip archive, data is used instead of the file contents if not nil.
+func addExportFile(zipWriter *zip.Writer, fileName, alias string, modified time.Time, data []byte) error {
+	// Images are compressed already.
+	header := &zip.FileHeader{
+		Name:     alias,
+		Method:   zip.Store,
+		Modified: modified,
+	}
+
+	writer, err := zipWriter.CreateHeader(header)
+
+	if err != nil {
+		return err
+	}
+
+	if data != nil {
+		_, err = writer.Write(data)
+
+		return err
+	}
+
+	file, err := os.Open(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+
+	return err
+}
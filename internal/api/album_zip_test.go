@@ -0,0 +1,215 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportAlbum(t *testing.T) {
+	app, router, conf := NewApiTest()
+	ExportAlbum(router, conf)
+
+	db := conf.Db()
+	dir := filepath.Join(conf.OriginalsPath(), "export")
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	album := entity.NewAlbum("Export")
+
+	if err := db.Create(album).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Both photos have the same title and taken date, so their names must get a suffix.
+	takenAt := time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	for i, name := range []string{"ferriswheel_colorful.jpg", "elephants.jpg"} {
+		data, err := ioutil.ReadFile(filepath.Join(conf.ExamplesPath(), name))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		photo := &entity.Photo{PhotoTitle: "Family Trip", TakenAt: takenAt, TakenAtLocal: takenAt, CameraID: 2, LensID: 2, PhotoQuality: 3}
+
+		if err := db.Create(photo).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		file := &entity.File{
+			PhotoID:     photo.ID,
+			PhotoUUID:   photo.PhotoUUID,
+			FileName:    "export/" + name,
+			FileHash:    fs.Hash(filepath.Join(dir, name)),
+			FileType:    "jpg",
+			FilePrimary: true,
+		}
+
+		if err := db.Create(file).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if err := db.Create(entity.NewPhotoAlbum(photo.PhotoUUID, album.AlbumUUID)).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if i == 0 {
+			// Make sure the test image contains a location.
+			data, err := meta.Exif(filepath.Join(dir, name))
+
+			if err != nil || data.Lat == 0 {
+				t.Fatalf("%s has no gps coordinates", name)
+			}
+		}
+	}
+
+	// archive returns the files of a zip archive by name.
+	archive := func(body []byte) map[string][]byte {
+		r, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := make(map[string][]byte)
+
+		for _, f := range r.File {
+			rc, err := f.Open()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := ioutil.ReadAll(rc)
+			rc.Close()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result[f.Name] = data
+		}
+
+		return result
+	}
+
+	// lat returns the gps latitude of jpeg data, or 0 if there is none.
+	lat := func(data []byte) float32 {
+		fileName := filepath.Join(dir, "check.jpg")
+
+		if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(fileName)
+
+		if exif, err := meta.Exif(fileName); err == nil {
+			return exif.Lat
+		}
+
+		return 0
+	}
+
+	t.Run("originals", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/albums/"+album.AlbumUUID+"/dl")
+
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "application/zip", result.Header().Get("Content-Type"))
+
+		files := archive(result.Body.Bytes())
+
+		if assert.Len(t, files, 2) {
+			assert.Contains(t, files, "20200501-123000-Family-Trip.jpg")
+			assert.Contains(t, files, "20200501-123000-Family-Trip-2.jpg")
+		}
+
+		original, err := ioutil.ReadFile(filepath.Join(dir, "ferriswheel_colorful.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Files are unchanged and still contain their location.
+		for _, data := range files {
+			if bytes.Equal(data, original) {
+				assert.NotEqual(t, float32(0), lat(data))
+				return
+			}
+		}
+
+		t.Error("original file not found in archive")
+	})
+	t.Run("strip", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/albums/"+album.AlbumUUID+"/dl?size=original&meta=strip")
+
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		files := archive(result.Body.Bytes())
+
+		assert.Len(t, files, 2)
+
+		for name, data := range files {
+			assert.Equal(t, float32(0), lat(data), name)
+		}
+	})
+	t.Run("2048", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/albums/"+album.AlbumUUID+"/dl?size=2048&meta=strip")
+
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		files := archive(result.Body.Bytes())
+
+		assert.Len(t, files, 2)
+
+		for name, data := range files {
+			assert.Equal(t, float32(0), lat(data), name)
+		}
+	})
+	t.Run("invalid size", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/albums/"+album.AlbumUUID+"/dl?size=999")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("invalid meta", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/albums/"+album.AlbumUUID+"/dl?meta=none")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("album not found", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/albums/xxx/dl")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
+
+func TestExportFileName(t *testing.T) {
+	names := make(map[string]bool)
+
+	assert.Equal(t, "20200501-123000-Xyz.jpg", exportFileName(photoResult("xyz", "Xyz"), ".JPG", names))
+	assert.Equal(t, "20200501-123000-Xyz-2.jpg", exportFileName(photoResult("xyz", "Xyz"), ".jpg", names))
+	assert.Equal(t, "20200501-123000-abc.webp", exportFileName(photoResult("abc", ""), ".webp", names))
+}
+
+// photoResult returns a search result for testing export file names.
+func photoResult(uuid, title string) query.PhotoResult {
+	takenAt := time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	return query.PhotoResult{PhotoUUID: uuid, PhotoTitle: title, TakenAt: takenAt, TakenAtLocal: takenAt}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/txt"
 
@@ -80,6 +81,13 @@ func BatchPhotosRestore(router *gin.RouterGroup, conf *config.Config) {
 
 		log.Infof("restoring photos: %#v", f.Photos)
 
+		// Photos in the trash need their files moved back first.
+		if _, err := photoprism.NewTrash(conf).Restore(f.Photos); err != nil {
+			log.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
 		db := conf.Db()
 
 		db.Unscoped().Model(&entity.Photo{}).Where("photo_uuid IN (?)", f.Photos).
@@ -95,6 +103,50 @@ func BatchPhotosRestore(router *gin.RouterGroup, conf *config.Config) {
 	})
 }
 
+// POST /api/v1/batch/photos/delete
+func BatchPhotosDelete(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/batch/photos/delete", func(c *gin.Context) {
+		if Unauthorized(c, conf) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		start := time.Now()
+
+		var f form.Selection
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if len(f.Photos) == 0 {
+			log.Error("no photos selected")
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst("no photos selected")})
+			return
+		}
+
+		log.Infof("photos: deleting %#v", f.Photos)
+
+		deleted, err := photoprism.NewTrash(conf).Delete(f.Photos)
+
+		if len(deleted) > 0 {
+			event.Publish("config.updated", event.Data(conf.ClientConfig()))
+			event.EntitiesDeleted("photos", deleted)
+		}
+
+		if err != nil {
+			log.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		elapsed := int(time.Since(start).Seconds())
+
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("photos deleted in %d s", elapsed), "photos": deleted})
+	})
+}
+
 // POST /api/v1/batch/albums/delete
 func BatchAlbumsDelete(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/batch/albums/delete", func(c *gin.Context) {
@@ -118,15 +170,14 @@ func BatchAlbumsDelete(router *gin.RouterGroup, conf *config.Config) {
 
 		log.Infof("albums: deleting %#v", f.Albums)
 
-		db := conf.Db()
-
-		db.Where("album_uuid IN (?)", f.Albums).Delete(&entity.Album{})
-		db.Where("album_uuid IN (?)", f.Albums).Delete(&entity.PhotoAlbum{})
+		if err := entity.DeleteAlbums(conf.Db(), f.Albums); err != nil {
+			log.Errorf("albums: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrSaveFailed)
+			return
+		}
 
 		event.Publish("config.updated", event.Data(conf.ClientConfig()))
 
-		event.EntitiesDeleted("albums", f.Albums)
-
 		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("albums deleted")})
 	})
 }
@@ -237,14 +288,14 @@ func BatchLabelsDelete(router *gin.RouterGroup, conf *config.Config) {
 
 		log.Infof("labels: deleting %#v", f.Labels)
 
-		db := conf.Db()
-
-		db.Where("label_uuid IN (?)", f.Labels).Delete(&entity.Label{})
+		if err := entity.DeleteLabels(conf.Db(), f.Labels); err != nil {
+			log.Errorf("labels: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrSaveFailed)
+			return
+		}
 
 		event.Publish("config.updated", event.Data(conf.ClientConfig()))
 
-		event.EntitiesDeleted("labels", f.Labels)
-
 		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("labels deleted")})
 	})
 }
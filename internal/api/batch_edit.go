@@ -0,0 +1,305 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/maps"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// batchEditChunk is the max number of photos updated in a single transaction.
+var batchEditChunk = 100
+
+// BatchEditResult contains the updated photos and the reason why other photos were not updated.
+type BatchEditResult struct {
+	Updated []string          `json:"updated"`
+	Errors  map[string]string `json:"errors"`
+}
+
+// POST /api/v1/batch/photos/edit
+func BatchPhotosEdit(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/batch/photos/edit", func(c *gin.Context) {
+		if Unauthorized(c, conf) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		start := time.Now()
+
+		var f form.BatchPhotosEdit
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if err := f.Validate(); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if f.Values.PhotoCountry != nil {
+			if _, ok := maps.CountryNames[*f.Values.PhotoCountry]; !ok {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(fmt.Sprintf("unknown country %s", *f.Values.PhotoCountry))})
+				return
+			}
+		}
+
+		db := conf.Db()
+
+		// Permissions are checked once for the album and all selected photos.
+		q := query.New(db).As(SessionViewer(c, conf))
+
+		if f.Values.AddToAlbum != "" {
+			if a, err := q.AlbumByUUID(f.Values.AddToAlbum); err != nil {
+				c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+				return
+			} else if !a.IsManual() {
+				c.AbortWithStatusJSON(http.StatusForbidden, ErrAlbumNotManual)
+				return
+			}
+		}
+
+		photos, err := q.PhotosByUUIDs(f.Photos)
+
+		if err != nil {
+			log.Errorf("photos: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+			return
+		}
+
+		result := BatchEditResult{Updated: []string{}, Errors: make(map[string]string)}
+		found := make(map[string]entity.Photo, len(photos))
+
+		for _, p := range photos {
+			found[p.PhotoUUID] = p
+		}
+
+		var valid []entity.Photo
+
+		for _, uuid := range f.Photos {
+			p, ok := found[uuid]
+
+			switch {
+			case !ok:
+				result.Errors[uuid] = "photo not found"
+			case p.DeletedAt != nil:
+				result.Errors[uuid] = "photo is archived"
+			default:
+				valid = append(valid, p)
+				delete(found, uuid)
+			}
+		}
+
+		if len(valid) == 0 {
+			c.JSON(http.StatusOK, result)
+			return
+		}
+
+		addLabels := batchAddLabels(db, f.Values.AddLabels)
+		removeLabels := batchRemoveLabels(db, f.Values.RemoveLabels)
+
+		for i := 0; i < len(valid); i += batchEditChunk {
+			end := i + batchEditChunk
+
+			if end > len(valid) {
+				end = len(valid)
+			}
+
+			chunk := valid[i:end]
+			uuids := make([]string, len(chunk))
+
+			for j, p := range chunk {
+				uuids[j] = p.PhotoUUID
+			}
+
+			if err := batchEditPhotos(db, chunk, f.Values, addLabels, removeLabels); err != nil {
+				log.Errorf("photos: %s", err)
+
+				for _, uuid := range uuids {
+					result.Errors[uuid] = "changes could not be saved"
+				}
+
+				continue
+			}
+
+			result.Updated = append(result.Updated, uuids...)
+
+			if entities, err := query.New(db).PhotoSelection(form.Selection{Photos: uuids}); err == nil {
+				event.EntitiesUpdated("photos", entities)
+			}
+		}
+
+		if len(result.Updated) > 0 {
+			labelIDs := append([]uint(nil), removeLabels...)
+
+			for _, lm := range addLabels {
+				labelIDs = append(labelIDs, lm.ID)
+			}
+
+			if len(labelIDs) > 0 {
+				if _, err := query.New(db).UpdateLabelCounts(labelIDs...); err != nil {
+					log.Errorf("photos: %s", err)
+				}
+			}
+
+			event.Publish("config.updated", event.Data(conf.ClientConfig()))
+		}
+
+		log.Infof("photos: %d updated, %d failed in %s", len(result.Updated), len(result.Errors), time.Since(start))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// batchAddLabels returns the labels with the given names, labels that don't exist yet are created.
+func batchAddLabels(db *gorm.DB, names []string) (result []*entity.Label) {
+	for _, name := range names {
+		lm := entity.NewLabel(name, 0).FirstOrCreate(db)
+
+		if lm.ID == 0 {
+			continue
+		}
+
+		if lm.New {
+			event.Publish("count.labels", event.Data{
+				"count": 1,
+			})
+		}
+
+		result = append(result, lm)
+	}
+
+	return result
+}
+
+// batchRemoveLabels returns the ids of existing labels with the given names.
+func batchRemoveLabels(db *gorm.DB, names []string) (result []uint) {
+	if len(names) == 0 {
+		return result
+	}
+
+	slugs := make([]string, len(names))
+
+	for i, name := range names {
+		slugs[i] = entity.NewLabel(name, 0).LabelSlug
+	}
+
+	if err := db.Model(&entity.Label{}).Where("label_slug IN (?) OR custom_slug IN (?)", slugs, slugs).Pluck("id", &result).Error; err != nil {
+		log.Errorf("photos: %s", err)
+	}
+
+	return result
+}
+
+// batchEditPhotos applies the changes to a chunk of photos in a single transaction.
+func batchEditPhotos(db *gorm.DB, photos []entity.Photo, v form.BatchPhotoValues, addLabels []*entity.Label, removeLabels []uint) error {
+	ids := make([]uint, len(photos))
+
+	for i, p := range photos {
+		ids[i] = p.ID
+	}
+
+	tx := db.Begin()
+
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	updates := make(map[string]interface{})
+
+	if v.PhotoPrivate != nil {
+		updates["photo_private"] = *v.PhotoPrivate
+	}
+
+	if v.PhotoFavorite != nil {
+		updates["photo_favorite"] = *v.PhotoFavorite
+	}
+
+	if v.PhotoCountry != nil {
+		updates["photo_country"] = *v.PhotoCountry
+	}
+
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now().UTC()
+
+		if err := tx.Model(&entity.Photo{}).Where("id IN (?)", ids).UpdateColumns(updates).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, p := range photos {
+		for _, lm := range addLabels {
+			plm := entity.NewPhotoLabel(p.ID, lm.ID, 0, entity.SrcManual)
+
+			if err := tx.Where("photo_id = ? AND label_id = ?", p.ID, lm.ID).FirstOrCreate(plm).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			if plm.Uncertainty > 0 {
+				plm.Uncertainty = 0
+				plm.LabelSrc = entity.SrcManual
+
+				if err := tx.Save(plm).Error; err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+		}
+
+		if v.AddToAlbum != "" {
+			if err := tx.Where("photo_uuid = ? AND album_uuid = ?", p.PhotoUUID, v.AddToAlbum).
+				FirstOrCreate(entity.NewPhotoAlbum(p.PhotoUUID, v.AddToAlbum)).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	if len(removeLabels) > 0 {
+		// Same as removing a single label: manually added labels are deleted, others get the max uncertainty.
+		if err := tx.Where("photo_id IN (?) AND label_id IN (?) AND label_src = ?", ids, removeLabels, entity.SrcManual).
+			Delete(&entity.PhotoLabel{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Model(&entity.PhotoLabel{}).Where("photo_id IN (?) AND label_id IN (?)", ids, removeLabels).
+			UpdateColumn("uncertainty", 100).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// Titles and keywords depend on labels.
+	if len(addLabels) > 0 || len(removeLabels) > 0 {
+		q := query.New(tx)
+
+		for _, p := range photos {
+			m, err := q.PreloadPhotoByUUID(p.PhotoUUID)
+
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			if err := m.Save(tx); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit().Error
+}
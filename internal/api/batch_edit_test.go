@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestBatchPhotosEdit(t *testing.T) {
+	t.Run("invalid request", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchPhotosEdit(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/edit", `{"photos": ["658"], "values": {}}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+		assert.Equal(t, "No changes", gjson.Get(result.Body.String(), "error").String())
+	})
+	t.Run("unknown country", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchPhotosEdit(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/edit", `{"photos": ["658"], "values": {"PhotoCountry": "xy"}}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("album not found", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchPhotosEdit(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/edit", `{"photos": ["658"], "values": {"AddToAlbum": "xxx"}}`)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("partial failure and label counts", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchPhotosEdit(router, conf)
+
+		chunk := batchEditChunk
+		batchEditChunk = 1
+		defer func() { batchEditChunk = chunk }()
+
+		body := `{"photos": ["658", "659", "xxx"], "values": {"PhotoFavorite": true, "AddLabels": ["Batch Label"]}}`
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/edit", body)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, `["658","659"]`, gjson.Get(result.Body.String(), "updated").Raw)
+		assert.Equal(t, "photo not found", gjson.Get(result.Body.String(), "errors.xxx").String())
+
+		// Adding the same label again must neither create a new label nor duplicate photo labels.
+		result = PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/edit", body)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		db := conf.Db()
+
+		var labels []entity.Label
+
+		if err := db.Where("label_slug = ?", "batch-label").Find(&labels).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if !assert.Len(t, labels, 1) {
+			return
+		}
+
+		var count int
+
+		db.Model(&entity.PhotoLabel{}).Where("label_id = ?", labels[0].ID).Count(&count)
+		assert.Equal(t, 2, count)
+
+		var photo entity.Photo
+
+		db.Where("photo_uuid = ?", "659").First(&photo)
+		assert.True(t, photo.PhotoFavorite)
+
+		result = PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/edit", `{"photos": ["658", "659"], "values": {"PhotoFavorite": false, "RemoveLabels": ["Batch Label"]}}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Empty(t, gjson.Get(result.Body.String(), "errors").Map())
+
+		db.Model(&entity.PhotoLabel{}).Where("label_id = ?", labels[0].ID).Count(&count)
+		assert.Equal(t, 0, count)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		BatchPhotosEdit(router, conf)
+		result := performRoleRequest(app, entity.RoleViewer, "POST", "/api/v1/batch/photos/edit", `{"photos": ["658"], "values": {"PhotoFavorite": true}}`)
+		assert.Equal(t, http.StatusForbidden, result.Code)
+	})
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchLabelsDelete(t *testing.T) {
+	t.Run("no labels selected", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchLabelsDelete(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/labels/delete", `{"labels": []}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("photo and category associations", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchLabelsDelete(router, conf)
+		db := conf.Db()
+
+		label := entity.NewLabel("Batch Delete", 0)
+		category := entity.NewLabel("Batch Delete Category", 0)
+
+		for _, m := range []*entity.Label{label, category} {
+			if err := db.Create(m).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		defer db.Unscoped().Where("id IN (?)", []uint{label.ID, category.ID}).Delete(&entity.Label{})
+
+		db.Create(entity.NewPhotoLabel(1, label.ID, 20, entity.SrcManual))
+		db.Create(entity.NewPhotoLabel(2, label.ID, 20, entity.SrcManual))
+		db.Create(entity.NewPhotoLabel(1, category.ID, 20, entity.SrcManual))
+		db.Create(&entity.Category{LabelID: label.ID, CategoryID: category.ID})
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/labels/delete", `{"labels": ["`+label.LabelUUID+`"]}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		var count int
+
+		db.Model(&entity.PhotoLabel{}).Where("label_id = ?", label.ID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		db.Model(&entity.Category{}).Where("label_id = ? OR category_id = ?", label.ID, label.ID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		db.Model(&entity.Label{}).Where("id = ?", label.ID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		// Other labels of the same photos are not affected.
+		db.Model(&entity.PhotoLabel{}).Where("label_id = ?", category.ID).Count(&count)
+		assert.Equal(t, 1, count)
+
+		db.Where("label_id = ?", category.ID).Delete(&entity.PhotoLabel{})
+	})
+}
+
+func TestBatchAlbumsDelete(t *testing.T) {
+	t.Run("no albums selected", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchAlbumsDelete(router, conf)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/albums/delete", `{"albums": []}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("album photos and permissions", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		BatchAlbumsDelete(router, conf)
+		db := conf.Db()
+
+		album := entity.NewAlbum("Batch Delete")
+
+		if err := db.Create(album).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		defer db.Unscoped().Delete(album)
+
+		if err := album.SetAccess(db, entity.AlbumAccess{Visibility: entity.VisibleRoles, Roles: []string{"family"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		db.Create(entity.NewPhotoAlbum("654", album.AlbumUUID))
+		db.Create(entity.NewPhotoAlbum("655", album.AlbumUUID))
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/batch/albums/delete", `{"albums": ["`+album.AlbumUUID+`"]}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		var count int
+
+		db.Model(&entity.PhotoAlbum{}).Where("album_uuid = ?", album.AlbumUUID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		db.Model(&entity.AlbumPermission{}).Where("album_uuid = ?", album.AlbumUUID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		db.Model(&entity.Album{}).Where("album_uuid = ?", album.AlbumUUID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		// Photos remain in other albums.
+		db.Model(&entity.PhotoAlbum{}).Where("album_uuid = ? AND photo_uuid = ?", "4", "654").Count(&count)
+		assert.Equal(t, 1, count)
+	})
+}
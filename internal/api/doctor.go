@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+// GET /api/v1/doctor
+//
+// Query:
+//   skip: string Comma separated list of checks to skip
+func GetDoctor(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/doctor", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		results := conf.Doctor(c.QueryArray("skip")...)
+
+		c.JSON(http.StatusOK, gin.H{
+			"checks":   results,
+			"warnings": results.Warnings(),
+			"failed":   results.Failed(),
+		})
+	})
+}
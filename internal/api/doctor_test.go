@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestGetDoctor(t *testing.T) {
+	t.Run("skip database and clock", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetDoctor(router, conf)
+
+		result := performAdminRequest(app, "GET", "/api/v1/doctor?skip=database,clock", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "paths", gjson.Get(result.Body.String(), "checks.0.check").String())
+		assert.NotContains(t, result.Body.String(), `"check":"database"`)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetDoctor(router, conf)
+
+		result := performRoleRequest(app, "viewer", "GET", "/api/v1/doctor", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
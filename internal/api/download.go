@@ -2,10 +2,9 @@ package api
 
 import (
 	"fmt"
-	"path"
+	"net/http"
 
 	"github.com/photoprism/photoprism/internal/config"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/fs"
 
 	"github.com/gin-gonic/gin"
@@ -21,17 +20,23 @@ import (
 //   hash: string The file hash as returned by the search API
 func GetDownload(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/download/:hash", func(c *gin.Context) {
+		link, ok := downloadAccess(c, conf)
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
 		fileHash := c.Param("hash")
 
-		q := query.New(conf.Db())
-		f, err := q.FileByHash(fileHash)
+		f, err := downloadFile(c, conf, link, fileHash)
 
 		if err != nil {
 			c.AbortWithStatusJSON(404, gin.H{"error": err.Error()})
 			return
 		}
 
-		fileName := path.Join(conf.OriginalsPath(), f.FileName)
+		fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 
 		if !fs.FileExists(fileName) {
 			log.Errorf("could not find original: %s", fileHash)
@@ -16,6 +16,20 @@ func TestGetDownload(t *testing.T) {
 		result := PerformRequest(app, "GET", "/api/v1/download/123xxx")
 		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
+	t.Run("private photo", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetDownload(router, conf)
+
+		fileHash, cleanup := createPrivatePhoto(t, conf)
+		defer cleanup()
+
+		result := PerformRequest(app, "GET", "/api/v1/download/"+fileHash)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = performAdminRequest(app, "GET", "/api/v1/download/"+fileHash, "")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
 	t.Run("download existing not existing file", func(t *testing.T) {
 		app, router, conf := NewApiTest()
 
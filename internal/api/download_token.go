@@ -0,0 +1,133 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/internal/session"
+)
+
+// downloadToken returns a new download token for the subject, or an empty string if download tokens
+// are not required.
+func downloadToken(conf *config.Config, subject string) string {
+	if !conf.DownloadTokens() || subject == "" {
+		return ""
+	}
+
+	return session.NewDownloadToken(conf.DownloadSecret(), subject, time.Now().Add(conf.DownloadTokenTTL()))
+}
+
+// downloadSubject returns the subject of download tokens for the request, which is the hash of the
+// session token for users and the link token for visitors of a sharing link.
+func downloadSubject(c *gin.Context) string {
+	if token := c.GetHeader("X-Session-Token"); service.Session().Exists(token) {
+		return session.SubjectSession + entity.SessionHash(token)
+	}
+
+	if linkToken, ok := shareAccess.Get(c.GetHeader("X-Share-Token")); ok {
+		return session.SubjectLink + linkToken.(string)
+	}
+
+	return ""
+}
+
+// DownloadAuthorized returns true if thumbnails and downloads may be sent. If download tokens are
+// required, requests without session or API token must contain a valid token in the "t" query
+// parameter, which is rejected as soon as its session was deleted or its link was revoked.
+func DownloadAuthorized(c *gin.Context, conf *config.Config) bool {
+	_, ok := downloadAccess(c, conf)
+
+	return ok
+}
+
+// downloadAccess is like DownloadAuthorized, and also returns the sharing link if access was granted
+// by a link token, so that only the shared album or photo may be downloaded.
+func downloadAccess(c *gin.Context, conf *config.Config) (link *entity.Link, ok bool) {
+	if !conf.DownloadTokens() {
+		return nil, true
+	}
+
+	if _, ok := requestApiToken(c); ok {
+		return nil, true
+	}
+
+	if service.Session().Exists(c.GetHeader("X-Session-Token")) {
+		return nil, true
+	}
+
+	subject, err := session.ParseDownloadToken(conf.DownloadSecret(), c.Query("t"), time.Now())
+
+	if err != nil {
+		log.Debugf("download: %s", err)
+		return nil, false
+	}
+
+	switch {
+	case strings.HasPrefix(subject, session.SubjectSession):
+		return nil, service.Session().ExistsHash(strings.TrimPrefix(subject, session.SubjectSession))
+	case strings.HasPrefix(subject, session.SubjectLink):
+		link, err := entity.FindLink(conf.Db(), strings.TrimPrefix(subject, session.SubjectLink))
+
+		if err != nil || link.Expired() {
+			return nil, false
+		}
+
+		return link, true
+	}
+
+	return nil, false
+}
+
+// downloadQuery returns the query for files and albums that may be downloaded, which are limited to
+// public photos of the shared album or photo for visitors of a sharing link.
+func downloadQuery(c *gin.Context, conf *config.Config, link *entity.Link) *query.Query {
+	if link != nil {
+		return query.New(conf.Db()).As(query.LinkViewer)
+	}
+
+	return query.New(conf.Db()).As(SessionViewer(c, conf))
+}
+
+// downloadFile returns the file with the hash if it may be downloaded, see downloadQuery.
+func downloadFile(c *gin.Context, conf *config.Config, link *entity.Link, fileHash string) (f entity.File, err error) {
+	q := downloadQuery(c, conf, link)
+
+	if f, err = q.FileByHash(fileHash); err != nil {
+		return f, err
+	}
+
+	if link != nil && !q.PhotoShared(link.ShareUUID, f.PhotoUUID) {
+		return entity.File{}, gorm.ErrRecordNotFound
+	}
+
+	return f, nil
+}
+
+// GET /api/v1/download-token
+//
+// Returns a new download token for the current session or sharing link, which must be added to
+// thumbnail and download URLs as "t" query parameter if download tokens are required.
+func GetDownloadToken(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/download-token", func(c *gin.Context) {
+		if !conf.DownloadTokens() {
+			c.JSON(http.StatusOK, gin.H{"token": ""})
+			return
+		}
+
+		subject := downloadSubject(c)
+
+		if subject == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"token": downloadToken(conf, subject), "expires": time.Now().Add(conf.DownloadTokenTTL()).UTC()})
+	})
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+// newDownloadTokenTest returns an API test router using a config with download tokens enabled.
+func newDownloadTokenTest(t *testing.T, public bool) (app *gin.Engine, router *gin.RouterGroup, conf *config.Config, cleanup func()) {
+	dir, err := ioutil.TempDir("", "config")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf = config.TestConfigDownloadTokens(dir, public)
+	service.SetConfig(conf)
+
+	gin.SetMode(gin.TestMode)
+	app = gin.New()
+	router = app.Group("/api/v1")
+
+	return app, router, conf, func() {
+		service.SetConfig(config.TestConfig())
+		os.RemoveAll(dir)
+	}
+}
+
+func TestDownloadAuthorized(t *testing.T) {
+	app, router, conf, cleanup := newDownloadTokenTest(t, false)
+	defer cleanup()
+
+	GetThumbnail(router, conf)
+	GetDownload(router, conf)
+
+	token := service.Session().Create(gin.H{"UserName": "admin", "Role": "admin"})
+	defer service.Session().Delete(token)
+
+	subject := session.SubjectSession + entity.SessionHash(token)
+
+	t.Run("no token", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+
+		result = PerformRequest(app, "GET", "/api/v1/download/123xxx")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("valid token", func(t *testing.T) {
+		dl := downloadToken(conf, subject)
+
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500?t="+dl)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = PerformRequest(app, "GET", "/api/v1/download/123xxx?t="+dl)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("expired token", func(t *testing.T) {
+		dl := session.NewDownloadToken(conf.DownloadSecret(), subject, time.Now().Add(-time.Second))
+
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500?t="+dl)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("tampered signature", func(t *testing.T) {
+		dl := downloadToken(conf, subject)
+		dl = dl[:len(dl)-4] + "AAAA"
+
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500?t="+dl)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("other secret", func(t *testing.T) {
+		dl := session.NewDownloadToken([]byte("other secret"), subject, time.Now().Add(time.Hour))
+
+		result := PerformRequest(app, "GET", "/api/v1/download/123xxx?t="+dl)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("session header", func(t *testing.T) {
+		result := performAdminRequest(app, "GET", "/api/v1/download/123xxx", "")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("deleted session", func(t *testing.T) {
+		other := service.Session().Create(gin.H{"UserName": "admin", "Role": "admin"})
+		dl := downloadToken(conf, session.SubjectSession+entity.SessionHash(other))
+
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500?t="+dl)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		service.Session().Delete(other)
+
+		result = PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500?t="+dl)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("revoked link", func(t *testing.T) {
+		db := conf.Db()
+		linkToken := createShareLink(t, db, "at9lxuqxpogaaba7", "", 0, 0)
+		dl := downloadToken(conf, session.SubjectLink+linkToken)
+
+		result := PerformRequest(app, "GET", "/api/v1/download/123xxx?t="+dl)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		db.Where("link_token = ?", linkToken).Delete(&entity.Link{})
+
+		result = PerformRequest(app, "GET", "/api/v1/download/123xxx?t="+dl)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
+
+func TestDownloadAuthorized_Link(t *testing.T) {
+	app, router, conf, cleanup := newDownloadTokenTest(t, false)
+	defer cleanup()
+
+	GetDownload(router, conf)
+	GetThumbnail(router, conf)
+
+	db := conf.Db()
+	fileName := filepath.Join(conf.OriginalsPath(), "link-download.jpg")
+
+	data, err := ioutil.ReadFile(filepath.Join(conf.ExamplesPath(), "clock_purple.jpg"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(fileName)
+
+	photo := &entity.Photo{PhotoTitle: "Not Shared", CameraID: 2, LensID: 2, PhotoQuality: 3}
+
+	if err := db.Create(photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Unscoped().Delete(photo)
+
+	file := &entity.File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "link-download.jpg", FileHash: fs.Hash(fileName), FileType: "jpg", FilePrimary: true}
+
+	if err := db.Create(file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Unscoped().Delete(file)
+
+	t.Run("other album", func(t *testing.T) {
+		dl := downloadToken(conf, session.SubjectLink+createShareLink(t, db, "at9lxuqxpogaaba7", "", 0, 0))
+
+		result := PerformRequest(app, "GET", "/api/v1/download/"+file.FileHash+"?t="+dl)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = PerformRequest(app, "GET", "/api/v1/thumbnails/"+file.FileHash+"/tile_50?t="+dl)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("shared photo", func(t *testing.T) {
+		dl := downloadToken(conf, session.SubjectLink+createShareLink(t, db, photo.PhotoUUID, "", 0, 0))
+
+		result := PerformRequest(app, "GET", "/api/v1/download/"+file.FileHash+"?t="+dl)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
+
+func TestDownloadAuthorized_Public(t *testing.T) {
+	app, router, conf, cleanup := newDownloadTokenTest(t, true)
+	defer cleanup()
+
+	GetThumbnail(router, conf)
+	GetDownloadToken(router, conf)
+
+	assert.False(t, conf.DownloadTokens())
+
+	t.Run("no token", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/123xxx/tile_500?t=invalid")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("issue token", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/download-token")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.JSONEq(t, `{"token": ""}`, result.Body.String())
+	})
+}
+
+func TestGetDownloadToken(t *testing.T) {
+	app, router, conf, cleanup := newDownloadTokenTest(t, false)
+	defer cleanup()
+
+	GetDownloadToken(router, conf)
+	GetDownload(router, conf)
+
+	t.Run("unauthorized", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/download-token")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("session", func(t *testing.T) {
+		result := performAdminRequest(app, "GET", "/api/v1/download-token", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		var body struct {
+			Token   string    `json:"token"`
+			Expires time.Time `json:"expires"`
+		}
+
+		if err := json.Unmarshal(result.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotEmpty(t, body.Token)
+		assert.True(t, body.Expires.After(time.Now()))
+
+		// The admin session of performAdminRequest was deleted after the request.
+		result = PerformRequest(app, "GET", "/api/v1/download/123xxx?t="+body.Token)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
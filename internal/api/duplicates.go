@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/duplicates
+//
+// Query:
+//   distance: int Max perceptual hash distance, defaults to the duplicate-distance config option
+//   burst:    int Photos taken less than this number of seconds apart are not grouped, defaults to duplicate-burst
+//   count:    int Max number of clusters (optional)
+//   offset:   int Number of clusters to skip (optional)
+func GetDuplicates(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/duplicates", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		distance := conf.DuplicateDistance()
+		burst := int(conf.DuplicateBurst() / time.Second)
+
+		if s := c.Query("distance"); s != "" {
+			i, err := strconv.Atoi(s)
+
+			if err != nil || i < 0 || i > 64 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid distance"})
+				return
+			}
+
+			distance = i
+		}
+
+		if s := c.Query("burst"); s != "" {
+			i, err := strconv.Atoi(s)
+
+			if err != nil || i < 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid burst interval"})
+				return
+			}
+
+			burst = i
+		}
+
+		count, err := strconv.Atoi(c.DefaultQuery("count", "0"))
+
+		if err != nil || count < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid count"})
+			return
+		}
+
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+		if err != nil || offset < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+
+		q := query.New(conf.Db())
+		clusters, err := q.Duplicates(distance, time.Duration(burst)*time.Second)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		limit := query.Limit(count)
+		total := len(clusters)
+
+		if offset > total {
+			offset = total
+		}
+
+		if end := offset + limit; end < total {
+			clusters = clusters[offset:end]
+		} else {
+			clusters = clusters[offset:]
+		}
+
+		c.Header("X-Count", strconv.Itoa(len(clusters)))
+		c.Header("X-Limit", strconv.Itoa(limit))
+		c.Header("X-Offset", strconv.Itoa(offset))
+
+		if err := AddResultHeaders(c, len(clusters), offset, limit, func() (int, error) { return total, nil }); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.JSON(http.StatusOK, clusters)
+	})
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDuplicates(t *testing.T) {
+	t.Run("default distance", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetDuplicates(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/duplicates", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("burst interval", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetDuplicates(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/duplicates?distance=2&burst=3", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("paging", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetDuplicates(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/duplicates?count=1&offset=1", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "1", result.Header().Get("X-Limit"))
+		assert.NotEmpty(t, result.Header().Get("X-Result-Total"))
+	})
+	t.Run("invalid offset", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetDuplicates(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/duplicates?offset=-1", "")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("invalid distance", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetDuplicates(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/duplicates?distance=abc", "")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetDuplicates(router, conf)
+		result := performRoleRequest(app, "viewer", "GET", "/api/v1/duplicates", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
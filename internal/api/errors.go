@@ -15,10 +15,31 @@ var (
 	ErrAccountNotFound  = gin.H{"code": http.StatusNotFound, "error": "Account not found"}
 	ErrConnectionFailed = gin.H{"code": http.StatusConflict, "error": "Failed to connect"}
 	ErrAlbumNotFound    = gin.H{"code": http.StatusNotFound, "error": "Album not found"}
+	ErrAlbumNotManual   = gin.H{"code": http.StatusForbidden, "error": "Photos can't be added to or removed from folder and smart albums"}
+	ErrFolderNotFound   = gin.H{"code": http.StatusBadRequest, "error": "Folder not found"}
 	ErrPhotoNotFound    = gin.H{"code": http.StatusNotFound, "error": "Photo not found"}
 	ErrLabelNotFound    = gin.H{"code": http.StatusNotFound, "error": "Label not found"}
+	ErrFileNotFound     = gin.H{"code": http.StatusNotFound, "error": "File not found"}
 	ErrUnexpectedError  = gin.H{"code": http.StatusInternalServerError, "error": "Unexpected error"}
 	ErrSaveFailed       = gin.H{"code": http.StatusInternalServerError, "error": "Changes could not be saved"}
 	ErrFormInvalid      = gin.H{"code": http.StatusBadRequest, "error": "Changes could not be saved"}
 	ErrFeatureDisabled  = gin.H{"code": http.StatusForbidden, "error": "Feature disabled"}
+	ErrThumbBusy        = gin.H{"code": http.StatusServiceUnavailable, "error": "Too many thumbnail requests, please try again later"}
+	ErrVideoNotFound    = gin.H{"code": http.StatusNotFound, "error": "Video not found"}
+	ErrTranscodeFailed  = gin.H{"code": http.StatusInternalServerError, "error": "Video could not be transcoded"}
+	ErrLinkNotFound     = gin.H{"code": http.StatusNotFound, "error": "Link not found"}
+	ErrLinkExpired      = gin.H{"code": http.StatusGone, "error": "Link expired"}
+	ErrPasswordRequired = gin.H{"code": http.StatusUnauthorized, "error": "Password required"}
+	ErrInvalidPassword  = gin.H{"code": http.StatusUnauthorized, "error": "Invalid password"}
+	ErrTooManyAttempts  = gin.H{"code": http.StatusTooManyRequests, "error": "Too many attempts, please try again later"}
+	ErrUserNotFound     = gin.H{"code": http.StatusNotFound, "error": "User not found"}
+	ErrPermissionDenied = gin.H{"code": http.StatusForbidden, "error": "Permission denied"}
+	ErrTokenNotFound    = gin.H{"code": http.StatusNotFound, "error": "Token not found"}
+	ErrUploadNotFound   = gin.H{"code": http.StatusNotFound, "error": "Upload not found"}
+	ErrUploadBusy       = gin.H{"code": http.StatusConflict, "error": "Upload in progress, please try again later"}
+	ErrUploadOffset     = gin.H{"code": http.StatusConflict, "error": "Upload offset does not match"}
+	ErrUploadIncomplete = gin.H{"code": http.StatusConflict, "error": "Upload incomplete"}
+	ErrUploadTooLarge   = gin.H{"code": http.StatusRequestEntityTooLarge, "error": "File too large"}
+	ErrSetupRequired    = gin.H{"code": http.StatusServiceUnavailable, "error": "Setup required", "setup": true}
+	ErrSetupCompleted   = gin.H{"code": http.StatusForbidden, "error": txt.UcFirst(config.ErrSetupCompleted.Error())}
 )
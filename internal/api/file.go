@@ -66,3 +66,31 @@ func LinkFile(router *gin.RouterGroup, conf *config.Config) {
 		c.JSON(http.StatusOK, m)
 	})
 }
+
+// GET /api/v1/files/:hash/timeline
+//
+// Returns the processing steps of the last indexing run.
+//
+// Parameters:
+//   hash: string SHA-1 hash or UUID of the file
+func GetFileTimeline(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/files/:hash/timeline", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		q := query.New(conf.Db())
+		f, err := q.FileByUUID(c.Param("hash"))
+
+		if err != nil {
+			f, err = q.FileByHash(c.Param("hash"))
+		}
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrFileNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"uuid": f.FileUUID, "name": f.FileName, "timeline": f.Timeline()})
+	})
+}
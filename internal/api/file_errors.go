@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/errors
+//
+// Returns files with errors recorded when they were last indexed, converted or rendered as thumbnail.
+//
+// Query:
+//   stage:  string Processing stage like "metadata", "thumbnail" or "convert", all stages by default
+//   root:   string Originals root name, all roots by default
+//   folder: string Folder relative to the root, including sub-folders
+//   count:  int    Max number of results, 100 by default
+//   offset: int    Number of results to skip
+func GetErrors(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/errors", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		var f form.FileErrorSearch
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		result, err := query.New(conf.Db()).FileErrors(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.Header("X-Count", fmt.Sprintf("%d", len(result)))
+		c.Header("X-Offset", fmt.Sprintf("%d", f.Offset))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
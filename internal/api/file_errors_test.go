@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetErrors(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetErrors(router, conf)
+
+		file := &entity.File{FileRoot: entity.RootDefault, FileName: "api-errors/broken.jpg", FileType: "jpg", FileHash: "api-errors-broken"}
+		file.SetError(entity.StageMetadata, errors.New("unexpected EOF"))
+
+		if err := conf.Db().Create(file).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		defer conf.Db().Unscoped().Delete(file)
+
+		result := performAdminRequest(app, "GET", "/api/v1/errors?stage=metadata&folder=api-errors", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "1", result.Header().Get("X-Count"))
+
+		var files []query.FileErrorResult
+
+		if err := json.Unmarshal(result.Body.Bytes(), &files); err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, files, 1) {
+			assert.Equal(t, "api-errors/broken.jpg", files[0].FileName)
+			assert.Equal(t, "unexpected EOF", files[0].FileError)
+		}
+	})
+	t.Run("other stage", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetErrors(router, conf)
+		result := performAdminRequest(app, "GET", "/api/v1/errors?stage=convert&folder=api-errors", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "0", result.Header().Get("X-Count"))
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetErrors(router, conf)
+		result := performRoleRequest(app, "viewer", "GET", "/api/v1/errors", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
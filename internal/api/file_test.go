@@ -22,3 +22,26 @@ func TestGetFile(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 }
+
+func TestGetFileTimeline(t *testing.T) {
+	t.Run("existing file", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetFileTimeline(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/files/123xxx/timeline", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "\"name\":\"exampleFileName.jpg\"")
+		assert.Contains(t, result.Body.String(), "\"timeline\"")
+	})
+	t.Run("not existing file", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetFileTimeline(router, ctx)
+		result := performAdminRequest(app, "GET", "/api/v1/files/111/timeline", "")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetFileTimeline(router, conf)
+		result := performRoleRequest(app, "viewer", "GET", "/api/v1/files/123xxx/timeline", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
@@ -24,7 +24,7 @@ func GetGeo(router *gin.RouterGroup, conf *config.Config) {
 
 		var f form.GeoSearch
 
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		err := c.MustBindWith(&f, binding.Form)
 
 		if err != nil {
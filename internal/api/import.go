@@ -26,12 +26,15 @@ func StartImport(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		if conf.ReadOnly() || !conf.Settings().Features.Import {
-			c.AbortWithStatusJSON(http.StatusForbidden, ErrFeatureDisabled)
+		if conf.ReadOnly() {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrReadOnly)
 			return
 		}
 
-		start := time.Now()
+		if !conf.Settings().Features.Import {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrFeatureDisabled)
+			return
+		}
 
 		var f form.ImportOptions
 
@@ -51,37 +54,57 @@ func StartImport(router *gin.RouterGroup, conf *config.Config) {
 
 		path = filepath.Clean(path)
 
-		imp := service.Import()
+		// Importing runs in this request, so it must not wait for itself.
+		ReleaseActivity(c)
 
-		var opt photoprism.ImportOptions
+		elapsed := importPath(conf, path, f)
 
-		if f.Move {
-			event.Info(fmt.Sprintf("moving files from \"%s\"", filepath.Base(path)))
-			opt = photoprism.ImportOptionsMove(path)
-		} else {
-			event.Info(fmt.Sprintf("copying files from \"%s\"", filepath.Base(path)))
-			opt = photoprism.ImportOptionsCopy(path)
-		}
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("import completed in %d s", elapsed)})
+	})
+}
 
-		imp.Start(opt)
+// importPath imports files from path and removes it if it's an empty sub folder of the import path afterwards.
+// Returns the duration in seconds.
+func importPath(conf *config.Config, path string, f form.ImportOptions) int {
+	start := time.Now()
+	imp := service.Import()
 
-		if subPath != "" && path != conf.ImportPath() && fs.IsEmpty(path) {
-			if err := os.Remove(path); err != nil {
-				log.Errorf("import: could not deleted empty directory \"%s\": %s", path, err)
-			} else {
-				log.Infof("import: deleted empty directory \"%s\"", path)
-			}
-		}
+	var opt photoprism.ImportOptions
 
-		elapsed := int(time.Since(start).Seconds())
+	if f.Move || conf.ImportMove() {
+		event.Info(fmt.Sprintf("moving files from \"%s\"", filepath.Base(path)))
+		opt = photoprism.ImportOptionsMove(path)
+	} else {
+		event.Info(fmt.Sprintf("copying files from \"%s\"", filepath.Base(path)))
+		opt = photoprism.ImportOptionsCopy(path)
+	}
 
-		event.Success(fmt.Sprintf("import completed in %d s", elapsed))
-		event.Publish("import.completed", event.Data{"path": path, "seconds": elapsed})
-		event.Publish("index.completed", event.Data{"path": path, "seconds": elapsed})
-		event.Publish("config.updated", event.Data(conf.ClientConfig()))
+	opt.Force = f.Force
+	opt.Takeout = f.Takeout
 
-		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("import completed in %d s", elapsed)})
+	res := imp.Start(opt)
+
+	if path != conf.ImportPath() && fs.IsEmpty(path) {
+		if err := os.Remove(path); err != nil {
+			log.Errorf("import: could not deleted empty directory \"%s\": %s", path, err)
+		} else {
+			log.Infof("import: deleted empty directory \"%s\"", path)
+		}
+	}
+
+	elapsed := int(time.Since(start).Seconds())
+
+	event.Success(fmt.Sprintf("import completed in %d s", elapsed))
+	event.Publish("import.completed", event.Data{
+		"path":       path,
+		"seconds":    elapsed,
+		"added":      res.Added,
+		"duplicates": res.Duplicates,
 	})
+	event.Publish("index.completed", event.Data{"path": path, "seconds": elapsed})
+	event.Publish("config.updated", event.Data(conf.ClientConfig()))
+
+	return elapsed
 }
 
 // DELETE /api/v1/import
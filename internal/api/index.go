@@ -12,6 +12,7 @@ import (
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -23,6 +24,9 @@ func StartIndexing(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		// Indexing runs in this request, so it must not wait for itself.
+		ReleaseActivity(c)
+
 		start := time.Now()
 
 		var f form.IndexOptions
@@ -32,7 +36,23 @@ func StartIndexing(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		path := conf.OriginalsPath()
+		path, err := originalsSubPath(conf, f.Root, f.Path)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		// All roots are indexed if neither a root nor a sub-folder is given.
+		paths := []string{path}
+
+		if f.Root == "" && f.Path == "" {
+			paths = paths[:0]
+
+			for _, root := range conf.OriginalsRoots() {
+				paths = append(paths, root.Path)
+			}
+		}
 
 		event.Info(fmt.Sprintf("indexing photos in \"%s\"", filepath.Base(path)))
 
@@ -42,12 +62,14 @@ func StartIndexing(router *gin.RouterGroup, conf *config.Config) {
 			c.JSON(http.StatusOK, gin.H{"error": err.Error()})
 		}
 
-		if f.ConvertRaw && !conf.ReadOnly() {
+		if f.ConvertRaw {
 			convert := service.Convert()
 
-			if err := convert.Start(conf.OriginalsPath()); err != nil {
-				cancel(err)
-				return
+			for _, convertPath := range paths {
+				if err := convert.Start(convertPath); err != nil {
+					cancel(err)
+					return
+				}
 			}
 		}
 
@@ -62,10 +84,25 @@ func StartIndexing(router *gin.RouterGroup, conf *config.Config) {
 
 		ind := service.Index()
 
+		var opt photoprism.IndexOptions
+
 		if f.CompleteRescan {
-			ind.Start(photoprism.IndexOptionsAll())
+			opt = photoprism.IndexOptionsAll()
 		} else {
-			ind.Start(photoprism.IndexOptionsNone())
+			opt = photoprism.IndexOptionsNone()
+		}
+
+		opt.Root = f.Root
+		opt.Path = f.Path
+
+		ind.Start(opt)
+
+		if f.Cleanup {
+			if deleted, err := ind.Cleanup(); err != nil {
+				log.Errorf("index: %s", err)
+			} else if deleted > 0 {
+				event.Info(fmt.Sprintf("removed %d ignored files from index", deleted))
+			}
 		}
 
 		elapsed := int(time.Since(start).Seconds())
@@ -93,3 +130,15 @@ func CancelIndexing(router *gin.RouterGroup, conf *config.Config) {
 		c.JSON(http.StatusOK, gin.H{"message": "indexing canceled"})
 	})
 }
+
+// originalsSubPath returns the absolute path of a sub-folder in an originals root, sub-folders are
+// relative to the default root if no root is given.
+func originalsSubPath(conf *config.Config, root, subPath string) (string, error) {
+	rootPath := conf.OriginalsRootPath(root)
+
+	if rootPath == "" {
+		return "", fmt.Errorf("originals root \"%s\" not found", root)
+	}
+
+	return fs.SubPath(rootPath, subPath)
+}
@@ -4,12 +4,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"path"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/cache"
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
@@ -43,10 +43,17 @@ func GetLabels(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		// TODO c.Header("X-Count", strconv.Itoa(count))
-		c.Header("X-Limit", strconv.Itoa(f.Count))
+		limit := query.Limit(f.Count)
+
+		c.Header("X-Count", strconv.Itoa(len(result)))
+		c.Header("X-Limit", strconv.Itoa(limit))
 		c.Header("X-Offset", strconv.Itoa(f.Offset))
 
+		if err := AddResultHeaders(c, len(result), f.Offset, limit, func() (int, error) { return q.LabelsTotal(f) }); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
 		c.JSON(http.StatusOK, result)
 	})
 }
@@ -176,18 +183,22 @@ func LabelThumbnail(router *gin.RouterGroup, conf *config.Config) {
 
 		if !ok {
 			log.Errorf("label: invalid thumb type \"%s\"", typeName)
-			c.Data(http.StatusOK, "image/svg+xml", labelIconSvg)
+			c.Data(http.StatusNotFound, "image/svg+xml", labelIconSvg)
 			return
 		}
 
-		q := query.New(conf.Db())
+		viewer := SessionViewer(c, conf)
+		q := query.New(conf.Db()).As(viewer)
 
+		// Thumbnails are cached per viewer, as photos may not be visible to everyone.
 		gc := conf.Cache()
-		cacheKey := fmt.Sprintf("label-thumbnail:%s:%s", labelUUID, typeName)
+		cacheKey := fmt.Sprintf("label-thumbnail:%s:%s:%s:%s:%t", labelUUID, typeName, viewer.Role, viewer.User, viewer.Public)
+
+		var cacheData []byte
 
-		if cacheData, ok := gc.Get(cacheKey); ok {
+		if cache.Load(gc, cacheKey, &cacheData) {
 			log.Debugf("label: %s cache hit [%s]", cacheKey, time.Since(start))
-			c.Data(http.StatusOK, "image/jpeg", cacheData.([]byte))
+			c.Data(http.StatusOK, http.DetectContentType(cacheData), cacheData)
 			return
 		}
 
@@ -199,7 +210,7 @@ func LabelThumbnail(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		fileName := path.Join(conf.OriginalsPath(), f.FileName)
+		fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 
 		if !fs.FileExists(fileName) {
 			log.Errorf("label: could not find original for %s", fileName)
@@ -215,12 +226,18 @@ func LabelThumbnail(router *gin.RouterGroup, conf *config.Config) {
 		if thumbType.ExceedsLimit() {
 			log.Debugf("label: using original, thumbnail size exceeds limit (width %d, height %d)", thumbType.Width, thumbType.Height)
 
-			c.File(fileName)
+			serveOriginal(c, fileName)
+
+			return
+		}
+
+		thumbnail, err := renderThumbnail(c, conf, fileName, f.FileHash, typeName)
 
+		if abortBusy(c, err) {
 			return
 		}
 
-		if thumbnail, err := thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...); err == nil {
+		if err == nil {
 			thumbData, err := ioutil.ReadFile(thumbnail)
 
 			if err != nil {
@@ -233,7 +250,7 @@ func LabelThumbnail(router *gin.RouterGroup, conf *config.Config) {
 
 			log.Debugf("label: %s cached [%s]", cacheKey, time.Since(start))
 
-			c.Data(http.StatusOK, "image/jpeg", thumbData)
+			c.Data(http.StatusOK, thumb.ContentType(thumbnail), thumbData)
 		} else {
 			log.Errorf("label: %s", err)
 
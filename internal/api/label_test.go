@@ -79,7 +79,7 @@ func TestLabelThumbnail(t *testing.T) {
 		LabelThumbnail(router, ctx)
 		result := PerformRequest(app, "GET", "/api/v1/labels/dog/thumbnail/xxx")
 
-		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 	t.Run("invalid label", func(t *testing.T) {
 		app, router, ctx := NewApiTest()
@@ -21,7 +21,12 @@ func newLink(c *gin.Context) (link entity.Link, err error) {
 		return link, err
 	}
 
-	link = entity.NewLink(f.Password, f.CanComment, f.CanEdit)
+	link = entity.NewLink(f.CanComment, f.CanEdit)
+	link.MaxViews = f.MaxViews
+
+	if err := link.SetPassword(f.Password); err != nil {
+		return link, err
+	}
 
 	if f.Expires > 0 {
 		expires := time.Now().Add(time.Duration(f.Expires) * time.Second)
@@ -40,7 +45,7 @@ func LinkAlbum(router *gin.RouterGroup, conf *config.Config) {
 		}
 
 		db := conf.Db()
-		q := query.New(db)
+		q := query.New(db).As(SessionViewer(c, conf))
 
 		m, err := q.AlbumByUUID(c.Param("uuid"))
 
@@ -71,7 +76,7 @@ func LinkPhoto(router *gin.RouterGroup, conf *config.Config) {
 		}
 
 		db := conf.Db()
-		q := query.New(db)
+		q := query.New(db).As(SessionViewer(c, conf))
 
 		m, err := q.PhotoByUUID(c.Param("uuid"))
 
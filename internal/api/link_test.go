@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/stretchr/testify/assert"
@@ -31,12 +32,22 @@ func TestLinkAlbum(t *testing.T) {
 
 		link := album.Links[0]
 
-		assert.Equal(t, "foobar", link.LinkPassword)
+		// Passwords are stored as hash and not returned.
+		assert.Empty(t, link.LinkPassword)
+
+		stored, err := entity.FindLink(ctx.Db(), link.LinkToken)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, stored.CheckPassword("foobar"))
+		assert.False(t, stored.CheckPassword("foo"))
 		assert.Nil(t, link.LinkExpires)
 		assert.False(t, link.CanComment)
 		assert.True(t, link.CanEdit)
 
-		result2 := PerformRequestWithBody(app, "POST", "/api/v1/albums/3/link", `{"password": "", "expires": 3600}`)
+		result2 := PerformRequestWithBody(app, "POST", "/api/v1/albums/3/link", `{"password": "", "expires": 3600, "max_views": 5}`)
 
 		assert.Equal(t, http.StatusOK, result2.Code)
 
@@ -50,5 +61,17 @@ func TestLinkAlbum(t *testing.T) {
 		if len(album.Links) != 2 {
 			t.Fatal("two links expected")
 		}
+
+		for _, link := range album.Links {
+			if link.MaxViews == 0 {
+				continue
+			}
+
+			assert.Equal(t, uint(5), link.MaxViews)
+
+			if assert.NotNil(t, link.LinkExpires) {
+				assert.True(t, link.LinkExpires.After(time.Now().Add(59*time.Minute)))
+			}
+		}
 	})
 }
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/txt"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /api/v1/logs
+//
+// Returns the most recent log entries, oldest first. New entries are sent to websocket clients as "log.*" events.
+//
+// Query:
+//   level: string Min log level like "warn", all levels by default
+//   count: int    Max number of entries, 100 by default
+func GetLogs(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/logs", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		var f form.LogSearch
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		level := logrus.TraceLevel
+
+		if f.Level != "" {
+			l, err := logrus.ParseLevel(f.Level)
+
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown log level \"%s\"", f.Level)})
+				return
+			}
+
+			level = l
+		}
+
+		if f.Count <= 0 {
+			f.Count = 100
+		}
+
+		result := event.Logs.Entries(level, f.Count)
+
+		c.Header("X-Count", fmt.Sprintf("%d", len(result)))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLogs(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetLogs(router, conf)
+
+		log.Warn("logs: test warning")
+		log.Info("logs: test info")
+
+		result := performAdminRequest(app, "GET", "/api/v1/logs?level=warn&count=200", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		var entries []event.LogEntry
+
+		if err := json.Unmarshal(result.Body.Bytes(), &entries); err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.NotEmpty(t, entries) {
+			last := entries[len(entries)-1]
+			assert.Equal(t, "logs: test warning", last.Message)
+			assert.Equal(t, "logs", last.Component)
+		}
+
+		for _, e := range entries {
+			assert.NotEqual(t, "info", e.Level)
+		}
+	})
+	t.Run("count", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetLogs(router, conf)
+
+		log.Info("logs: first")
+		log.Info("logs: second")
+
+		result := performAdminRequest(app, "GET", "/api/v1/logs?count=1", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "1", result.Header().Get("X-Count"))
+	})
+	t.Run("unknown level", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetLogs(router, conf)
+		result := performAdminRequest(app, "GET", "/api/v1/logs?level=verbose", "")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetLogs(router, conf)
+		result := performRoleRequest(app, "viewer", "GET", "/api/v1/logs", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
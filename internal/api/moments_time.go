@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/query"
@@ -26,6 +27,46 @@ func GetMomentsTime(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		s := conf.Settings()
+		tpl := s.MonthTemplate()
+
+		for i, m := range result {
+			if m.PhotoYear > 0 && m.PhotoMonth > 0 {
+				date := time.Date(m.PhotoYear, time.Month(m.PhotoMonth), 1, 0, 0, 0, 0, time.UTC)
+				result[i].Title = tpl.Format(date, s.WeekStart(), s.Language)
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// GET /api/v1/moments/weeks
+//
+// Returns photo counts per week, named with the week template and starting on the configured week day.
+func GetMomentsWeeks(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/moments/weeks", func(c *gin.Context) {
+		if Unauthorized(c, conf) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		s := conf.Settings()
+		q := query.New(conf.Db())
+
+		result, err := q.GetMomentsWeeks(s.WeekStart())
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		tpl := s.WeekTemplate()
+
+		for i, m := range result {
+			result[i].Title = tpl.Format(m.First, s.WeekStart(), s.Language)
+		}
+
 		c.JSON(http.StatusOK, result)
 	})
 }
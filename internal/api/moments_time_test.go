@@ -17,3 +17,14 @@ func TestGetMomentsTime(t *testing.T) {
 		assert.Equal(t, http.StatusOK, result.Code)
 	})
 }
+
+func TestGetMomentsWeeks(t *testing.T) {
+	t.Run("weeks", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetMomentsWeeks(router, conf)
+
+		result := PerformRequest(app, "GET", "/api/v1/moments/weeks")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
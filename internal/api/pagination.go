@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// AddResultHeaders adds the X-Result-Count header and RFC 5988 Link headers for the next and previous
+// page. The X-Result-Total header is added unless the client passed total=false, since counting
+// all results requires another query.
+func AddResultHeaders(c *gin.Context, count, offset, limit int, total func() (int, error)) error {
+	c.Header("X-Result-Count", strconv.Itoa(count))
+
+	// Without a total, another page may exist if the current one is full.
+	hasNext := count >= limit
+
+	if txt.Bool(c.DefaultQuery("total", "true")) {
+		n, err := total()
+
+		if err != nil {
+			return err
+		}
+
+		c.Header("X-Result-Total", strconv.Itoa(n))
+
+		hasNext = offset+count < n
+	}
+
+	var links []string
+
+	if hasNext {
+		links = append(links, pageLink(c, offset+limit, limit, "next"))
+	}
+
+	if offset > 0 {
+		prev := offset - limit
+
+		if prev < 0 {
+			prev = 0
+		}
+
+		links = append(links, pageLink(c, prev, limit, "prev"))
+	}
+
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
+	return nil
+}
+
+// pageLink returns a Link header value for the current request with a different offset.
+func pageLink(c *gin.Context, offset, limit int, rel string) string {
+	values := c.Request.URL.Query()
+
+	values.Set("count", strconv.Itoa(limit))
+	values.Set("offset", strconv.Itoa(offset))
+
+	return fmt.Sprintf("<%s?%s>; rel=\"%s\"", c.Request.URL.Path, values.Encode(), rel)
+}
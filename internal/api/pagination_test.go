@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddResultHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// headers performs a request and returns the response headers for a page of 25 results.
+	headers := func(url string, count, offset int, total func() (int, error)) http.Header {
+		app := gin.New()
+
+		app.GET("/api/v1/photos", func(c *gin.Context) {
+			if err := AddResultHeaders(c, count, offset, 25, total); err != nil {
+				c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{})
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		app.ServeHTTP(w, req)
+
+		return w.Header()
+	}
+
+	total := func() (int, error) { return 60, nil }
+
+	t.Run("first page", func(t *testing.T) {
+		h := headers("/api/v1/photos?count=25&q=cat", 25, 0, total)
+
+		assert.Equal(t, "25", h.Get("X-Result-Count"))
+		assert.Equal(t, "60", h.Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=25&offset=25&q=cat>; rel="next"`, h.Get("Link"))
+	})
+	t.Run("middle page", func(t *testing.T) {
+		h := headers("/api/v1/photos?count=25&offset=25", 25, 25, total)
+
+		assert.Equal(t, "25", h.Get("X-Result-Count"))
+		assert.Equal(t, "60", h.Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=25&offset=50>; rel="next", </api/v1/photos?count=25&offset=0>; rel="prev"`, h.Get("Link"))
+	})
+	t.Run("last page", func(t *testing.T) {
+		h := headers("/api/v1/photos?count=25&offset=50", 10, 50, total)
+
+		assert.Equal(t, "10", h.Get("X-Result-Count"))
+		assert.Equal(t, "60", h.Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=25&offset=25>; rel="prev"`, h.Get("Link"))
+	})
+	t.Run("last page full", func(t *testing.T) {
+		h := headers("/api/v1/photos?count=25&offset=25", 25, 25, func() (int, error) { return 50, nil })
+
+		assert.Equal(t, `</api/v1/photos?count=25&offset=0>; rel="prev"`, h.Get("Link"))
+	})
+	t.Run("offset not aligned", func(t *testing.T) {
+		h := headers("/api/v1/photos?count=25&offset=10", 25, 10, total)
+
+		assert.Equal(t, `</api/v1/photos?count=25&offset=35>; rel="next", </api/v1/photos?count=25&offset=0>; rel="prev"`, h.Get("Link"))
+	})
+	t.Run("total=false", func(t *testing.T) {
+		counted := false
+
+		h := headers("/api/v1/photos?count=25&offset=25&total=false", 25, 25, func() (int, error) {
+			counted = true
+			return 60, nil
+		})
+
+		assert.False(t, counted)
+		assert.Equal(t, "", h.Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=25&offset=50&total=false>; rel="next", </api/v1/photos?count=25&offset=0&total=false>; rel="prev"`, h.Get("Link"))
+
+		h = headers("/api/v1/photos?count=25&offset=50&total=false", 10, 50, total)
+
+		assert.Equal(t, `</api/v1/photos?count=25&offset=25&total=false>; rel="prev"`, h.Get("Link"))
+	})
+	t.Run("error", func(t *testing.T) {
+		h := headers("/api/v1/photos?count=25", 25, 0, func() (int, error) { return 0, errors.New("failed") })
+
+		assert.Equal(t, "", h.Get("X-Result-Total"))
+		assert.Equal(t, "", h.Get("Link"))
+	})
+}
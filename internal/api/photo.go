@@ -3,15 +3,17 @@ package api
 import (
 	"fmt"
 	"net/http"
-	"path"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
 // GET /api/v1/photos/:uuid
@@ -25,7 +27,7 @@ func GetPhoto(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		p, err := q.PreloadPhotoByUUID(c.Param("uuid"))
 
 		if err != nil {
@@ -91,6 +93,14 @@ func UpdatePhoto(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if conf.WriteMetadata() {
+			if f, err := q.FileByPhotoUUID(uuid); err != nil {
+				log.Error(err)
+			} else if _, err := photoprism.SaveMetadata(conf, p, f); err != nil {
+				log.Error(err)
+			}
+		}
+
 		c.JSON(http.StatusOK, p)
 	})
 }
@@ -101,15 +111,19 @@ func UpdatePhoto(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string PhotoUUID as returned by the API
 func GetPhotoDownload(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/photos/:uuid/download", func(c *gin.Context) {
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		f, err := q.FileByPhotoUUID(c.Param("uuid"))
 
+		if err == nil && q.PhotosVisibleCount([]string{f.PhotoUUID}) == 0 {
+			err = fmt.Errorf("photo %s not visible", f.PhotoUUID)
+		}
+
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
 			return
 		}
 
-		fileName := path.Join(conf.OriginalsPath(), f.FileName)
+		fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 
 		if !fs.FileExists(fileName) {
 			log.Errorf("could not find original: %s", c.Param("uuid"))
@@ -215,8 +229,11 @@ func SetPhotoPrimary(router *gin.RouterGroup, conf *config.Config) {
 		q := query.New(db)
 		err := q.SetPhotoPrimary(uuid, fileUUID)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+		if gorm.IsRecordNotFoundError(err) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrFileNotFound)
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
 			return
 		}
 
@@ -234,3 +251,47 @@ func SetPhotoPrimary(router *gin.RouterGroup, conf *config.Config) {
 		c.JSON(http.StatusOK, p)
 	})
 }
+
+// POST /api/v1/photos/:uuid/unstack/:file_uuid
+//
+// Moves a stacked file to a new photo and returns the new photo.
+//
+// Parameters:
+//   uuid: string PhotoUUID as returned by the API
+//   file_uuid: string FileUUID of the file to unstack
+func UnstackFile(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/photos/:uuid/unstack/:file_uuid", func(c *gin.Context) {
+		if Unauthorized(c, conf) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		uuid := c.Param("uuid")
+		fileUUID := c.Param("file_uuid")
+		q := query.New(conf.Db())
+
+		photo, err := q.UnstackFile(uuid, fileUUID)
+
+		if gorm.IsRecordNotFoundError(err) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrFileNotFound)
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		PublishPhotoEvent(EntityUpdated, uuid, c, q)
+		PublishPhotoEvent(EntityCreated, photo.PhotoUUID, c, q)
+
+		event.Success("file unstacked")
+
+		p, err := q.PreloadPhotoByUUID(photo.PhotoUUID)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, p)
+	})
+}
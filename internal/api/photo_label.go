@@ -61,6 +61,10 @@ func AddPhotoLabel(router *gin.RouterGroup, conf *config.Config) {
 
 		db.Save(&lm)
 
+		if _, err := q.UpdateLabelCounts(lm.ID); err != nil {
+			log.Errorf("label: %s", err)
+		}
+
 		p, err := q.PreloadPhotoByUUID(c.Param("uuid"))
 
 		if err != nil {
@@ -123,6 +127,10 @@ func RemovePhotoLabel(router *gin.RouterGroup, conf *config.Config) {
 			db.Save(&label)
 		}
 
+		if _, err := q.UpdateLabelCounts(label.LabelID); err != nil {
+			log.Errorf("label: %s", err)
+		}
+
 		p, err := q.PreloadPhotoByUUID(c.Param("uuid"))
 
 		if err != nil {
@@ -190,6 +198,10 @@ func UpdatePhotoLabel(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if _, err := q.UpdateLabelCounts(label.LabelID); err != nil {
+			log.Errorf("label: %s", err)
+		}
+
 		p, err := q.PreloadPhotoByUUID(c.Param("uuid"))
 
 		if err != nil {
@@ -22,11 +22,17 @@ import (
 //   country:   string Country code
 //   camera:    int    UpdateCamera ID
 //   order:     string Sort order
-//   count:     int    Max result count (required)
+//   count:     int    Max result count (required, limited to 1000, see X-Limit header)
 //   offset:    int    Result offset
 //   before:    date   Find photos taken before (format: "2006-01-02")
 //   after:     date   Find photos taken after (format: "2006-01-02")
 //   favorites: bool   Find favorites only
+//   s2:        string Find photos in S2 cell (token)
+//   bbox:      string Find photos in bounding box (format: "west,south,east,north")
+//   center:    string Center for radius search and distance sort order (format: "lat,lng")
+//   radius:    float  Find photos within radius around center in km
+//   merged:    bool   Combine the files of a photo into one result, count and offset then refer to photos
+//   total:     bool   Add X-Result-Total header (default: true)
 func GetPhotos(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/photos", func(c *gin.Context) {
 		if Unauthorized(c, conf) {
@@ -36,7 +42,7 @@ func GetPhotos(router *gin.RouterGroup, conf *config.Config) {
 
 		var f form.PhotoSearch
 
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		err := c.MustBindWith(&f, binding.Form)
 
 		if err != nil {
@@ -51,10 +57,17 @@ func GetPhotos(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		limit := query.Limit(f.Count)
+
 		c.Header("X-Count", strconv.Itoa(count))
-		c.Header("X-Limit", strconv.Itoa(f.Count))
+		c.Header("X-Limit", strconv.Itoa(limit))
 		c.Header("X-Offset", strconv.Itoa(f.Offset))
 
+		if err := AddResultHeaders(c, count, f.Offset, limit, func() (int, error) { return q.PhotosTotal(f) }); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
 		c.JSON(http.StatusOK, result)
 	})
 }
@@ -23,4 +23,40 @@ func TestGetPhotos(t *testing.T) {
 		result := PerformRequest(app, "GET", "/api/v1/photos?xxx=10")
 		assert.Equal(t, http.StatusBadRequest, result.Code)
 	})
+	t.Run("first page", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetPhotos(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/photos?count=1&offset=0")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "1", result.Header().Get("X-Result-Count"))
+		assert.Equal(t, "3", result.Header().Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=1&offset=1>; rel="next"`, result.Header().Get("Link"))
+	})
+	t.Run("middle page", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetPhotos(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/photos?count=1&offset=1")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "1", result.Header().Get("X-Result-Count"))
+		assert.Equal(t, "3", result.Header().Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=1&offset=2>; rel="next", </api/v1/photos?count=1&offset=0>; rel="prev"`, result.Header().Get("Link"))
+	})
+	t.Run("last page", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetPhotos(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/photos?count=2&offset=2")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "1", result.Header().Get("X-Result-Count"))
+		assert.Equal(t, "3", result.Header().Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=2&offset=0>; rel="prev"`, result.Header().Get("Link"))
+	})
+	t.Run("without total", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetPhotos(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/photos?count=2&offset=2&total=false")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "1", result.Header().Get("X-Result-Count"))
+		assert.Equal(t, "", result.Header().Get("X-Result-Total"))
+		assert.Equal(t, `</api/v1/photos?count=2&offset=0&total=false>; rel="prev"`, result.Header().Get("Link"))
+	})
 }
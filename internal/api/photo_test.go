@@ -69,3 +69,33 @@ func TestDislikePhoto(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 }
+
+func TestSetPhotoPrimary(t *testing.T) {
+	t.Run("not a jpeg", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		SetPhotoPrimary(router, ctx)
+		result := PerformRequest(app, "POST", "/api/v1/photos/655/primary/fq8ev8t1x0bwje4e")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("file of other photo", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		SetPhotoPrimary(router, ctx)
+		result := PerformRequest(app, "POST", "/api/v1/photos/655/primary/fq8es39w45bnlqdw")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
+
+func TestUnstackFile(t *testing.T) {
+	t.Run("only file", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		UnstackFile(router, ctx)
+		result := PerformRequest(app, "POST", "/api/v1/photos/654/unstack/fq8es39w45bnlqdw")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("not existing file", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		UnstackFile(router, ctx)
+		result := PerformRequest(app, "POST", "/api/v1/photos/654/unstack/xxx")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
@@ -1,12 +1,16 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
-	"path"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/meta"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
@@ -19,6 +23,13 @@ import (
 //   type: string Thumbnail type, see photoprism.ThumbnailTypes
 func GetThumbnail(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/thumbnails/:hash/:type", func(c *gin.Context) {
+		link, ok := downloadAccess(c, conf)
+
+		if !ok {
+			c.Data(http.StatusUnauthorized, "image/svg+xml", brokenIconSvg)
+			return
+		}
+
 		fileHash := c.Param("hash")
 		typeName := c.Param("type")
 
@@ -26,25 +37,25 @@ func GetThumbnail(router *gin.RouterGroup, conf *config.Config) {
 
 		if !ok {
 			log.Errorf("photo: invalid thumb type \"%s\"", typeName)
-			c.Data(http.StatusBadRequest, "image/svg+xml", photoIconSvg)
+			c.Data(http.StatusNotFound, "image/svg+xml", photoIconSvg)
 			return
 		}
 
 		db := conf.Db()
-		q := query.New(db)
-		f, err := q.FileByHash(fileHash)
+		f, err := downloadFile(c, conf, link, fileHash)
 
 		if err != nil {
 			c.Data(http.StatusNotFound, "image/svg+xml", photoIconSvg)
 			return
 		}
 
-		if f.FileError != "" {
+		// Metadata and conversion errors don't affect thumbnails of the file itself.
+		if f.HasError(entity.StageThumbnail) {
 			c.Data(http.StatusBadRequest, "image/svg+xml", brokenIconSvg)
 			return
 		}
 
-		fileName := path.Join(conf.OriginalsPath(), f.FileName)
+		fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 
 		if !fs.FileExists(fileName) {
 			log.Errorf("photo: could not find original for %s", fileName)
@@ -60,24 +71,119 @@ func GetThumbnail(router *gin.RouterGroup, conf *config.Config) {
 		if thumbType.ExceedsLimit() && c.Query("download") == "" {
 			log.Debugf("photo: using original, thumbnail size exceeds limit (width %d, height %d)", thumbType.Width, thumbType.Height)
 
-			c.File(fileName)
+			serveOriginal(c, fileName)
+
+			return
+		}
+
+		thumbnail, err := renderThumbnail(c, conf, fileName, f.FileHash, typeName)
 
+		if abortBusy(c, err) {
 			return
 		}
 
-		if thumbnail, err := thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...); err == nil {
+		if err == nil {
 			if c.Query("download") != "" {
 				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", f.ShareFileName()))
 			}
 
+			c.Header("Content-Type", thumb.ContentType(thumbnail))
 			c.File(thumbnail)
 		} else {
 			log.Errorf("photo: %s", err)
 
-			f.FileError = err.Error()
+			f.SetError(entity.StageThumbnail, err)
 			db.Save(&f)
 
 			c.Data(http.StatusBadRequest, "image/svg+xml", brokenIconSvg)
 		}
 	})
 }
+
+// thumbFromFile renders thumbnails on demand, it may be replaced in tests.
+var thumbFromFile = thumb.FromFile
+
+// renderThumbnail returns the thumbnail file name and creates it if it doesn't exist yet. The number of thumbnails
+// rendered at the same time is limited and concurrent requests for the same thumbnail wait for a single render.
+func renderThumbnail(c *gin.Context, conf *config.Config, fileName, fileHash, typeName string) (string, error) {
+	thumbType := thumb.Types[typeName]
+
+	if existing, ok := thumb.Existing(fileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...); ok {
+		return existing, nil
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), conf.ThumbTimeout())
+	defer cancel()
+
+	result, _, err := conf.ThumbLimiter().Do(ctx, fileHash+":"+typeName, func() (string, error) {
+		// Use the crop window chosen while indexing, so that thumbnails look the same when rendered again.
+		if thumb.SmartCrop && thumbType.Centered() {
+			if f, err := query.New(conf.Db()).FileByHash(fileHash); err == nil {
+				if crop, ok := thumb.ParseCrop(f.FileCrop); ok {
+					return thumb.FromFileCrop(fileName, fileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, crop, thumbType.Options...)
+				}
+			}
+		}
+
+		return thumbFromFile(fileName, fileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...)
+	})
+
+	return result, err
+}
+
+// abortBusy aborts the request and returns true if a thumbnail could not be rendered in time
+// or the client has gone away.
+func abortBusy(c *gin.Context, err error) bool {
+	switch err {
+	case context.DeadlineExceeded:
+		log.Warnf("thumbs: timeout while waiting for %s", c.Request.URL.Path)
+		c.Header("Retry-After", "5")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrThumbBusy)
+		return true
+	case context.Canceled:
+		c.Abort()
+		return true
+	default:
+		return false
+	}
+}
+
+// serveOriginal sends an original instead of a thumbnail. JPEG meta data is removed except for the
+// orientation and copyright, so that location and camera details are not exposed.
+func serveOriginal(c *gin.Context, fileName string) {
+	if fs.GetFileType(fileName) != fs.TypeJpeg {
+		c.File(fileName)
+		return
+	}
+
+	data, err := strippedJpeg(fileName)
+
+	if os.IsNotExist(err) {
+		log.Errorf("photo: %s", err)
+		c.Data(http.StatusNotFound, "image/svg+xml", photoIconSvg)
+		return
+	} else if err != nil {
+		log.Errorf("photo: %s", err)
+		c.Data(http.StatusBadRequest, "image/svg+xml", brokenIconSvg)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/jpeg", data)
+}
+
+// strippedJpeg returns the data of a JPEG file without meta data except for the orientation and copyright.
+func strippedJpeg(fileName string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	orientation := 1
+
+	if exif, err := meta.Exif(fileName); err == nil {
+		orientation = exif.Orientation
+	}
+
+	return thumb.StripMetadata(data, orientation, thumb.Copyright)
+}
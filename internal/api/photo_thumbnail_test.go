@@ -1,19 +1,77 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/stretchr/testify/assert"
 )
 
+// createPrivatePhoto indexes a copy of an example image as private photo and returns its file hash.
+func createPrivatePhoto(t *testing.T, conf *config.Config) (fileHash string, cleanup func()) {
+	db := conf.Db()
+	fileName := filepath.Join(conf.OriginalsPath(), "private.jpg")
+
+	data, err := ioutil.ReadFile(filepath.Join(conf.ExamplesPath(), "clock_purple.jpg"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	photo := &entity.Photo{PhotoTitle: "Private", CameraID: 2, LensID: 2, PhotoQuality: 3, PhotoPrivate: true}
+
+	if err := db.Create(photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	file := &entity.File{
+		PhotoID:     photo.ID,
+		PhotoUUID:   photo.PhotoUUID,
+		FileName:    "private.jpg",
+		FileHash:    fs.Hash(fileName),
+		FileType:    "jpg",
+		FilePrimary: true,
+	}
+
+	if err := db.Create(file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return file.FileHash, func() {
+		db.Unscoped().Delete(file)
+		db.Unscoped().Delete(photo)
+		os.Remove(fileName)
+	}
+}
+
 func TestGetThumbnail(t *testing.T) {
 	t.Run("invalid type", func(t *testing.T) {
 		app, router, ctx := NewApiTest()
 		GetThumbnail(router, ctx)
 		result := PerformRequest(app, "GET", "/api/v1/thumbnails/1/xxx")
 
-		assert.Equal(t, http.StatusBadRequest, result.Code)
+		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 	t.Run("invalid hash", func(t *testing.T) {
 		app, router, ctx := NewApiTest()
@@ -22,6 +80,19 @@ func TestGetThumbnail(t *testing.T) {
 
 		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
+	t.Run("private photo", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumbnail(router, conf)
+
+		fileHash, cleanup := createPrivatePhoto(t, conf)
+		defer cleanup()
+
+		result := PerformRequest(app, "GET", "/api/v1/thumbnails/"+fileHash+"/tile_50")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = performAdminRequest(app, "GET", "/api/v1/thumbnails/"+fileHash+"/tile_50", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
 	t.Run("could not find original", func(t *testing.T) {
 		app, router, ctx := NewApiTest()
 		GetThumbnail(router, ctx)
@@ -30,3 +101,170 @@ func TestGetThumbnail(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 }
+
+func TestRenderThumbnail(t *testing.T) {
+	app, router, conf := NewApiTest()
+
+	var renders int32
+	release := make(chan struct{})
+
+	thumbFromFile = func(imageFilename string, hash string, thumbPath string, width, height int, opts ...thumb.ResampleOption) (string, error) {
+		atomic.AddInt32(&renders, 1)
+		<-release
+		return "/tmp/" + hash + ".jpg", nil
+	}
+
+	defer func() { thumbFromFile = thumb.FromFile }()
+
+	router.GET("/render/:hash", func(c *gin.Context) {
+		thumbnail, err := renderThumbnail(c, conf, "original.jpg", c.Param("hash"), "tile_500")
+
+		if abortBusy(c, err) {
+			return
+		}
+
+		c.String(http.StatusOK, thumbnail)
+	})
+
+	t.Run("parallel requests", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		results := make([]*httptest.ResponseRecorder, 10)
+
+		for i := range results {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+				results[i] = PerformRequest(app, "GET", "/api/v1/render/fa3a6c7ad1d1fb2d3ba8d9a4929e7f4d144e8b53")
+			}(i)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		release <- struct{}{}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), renders)
+
+		for _, result := range results {
+			assert.Equal(t, http.StatusOK, result.Code)
+			assert.Equal(t, "/tmp/fa3a6c7ad1d1fb2d3ba8d9a4929e7f4d144e8b53.jpg", result.Body.String())
+		}
+	})
+	t.Run("timeout", func(t *testing.T) {
+		go PerformRequest(app, "GET", "/api/v1/render/bc3a6c7ad1d1fb2d3ba8d9a4929e7f4d144e8b53")
+
+		time.Sleep(20 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", "/api/v1/render/bc3a6c7ad1d1fb2d3ba8d9a4929e7f4d144e8b53", nil)
+		result := httptest.NewRecorder()
+		app.ServeHTTP(result, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, result.Code)
+		assert.Equal(t, "5", result.Header().Get("Retry-After"))
+
+		close(release)
+	})
+}
+
+func TestServeOriginal(t *testing.T) {
+	app, router, _ := NewApiTest()
+
+	thumb.Copyright = "© Jane Doe"
+
+	defer func() { thumb.Copyright = "" }()
+
+	router.GET("/original", func(c *gin.Context) {
+		serveOriginal(c, "../meta/testdata/photoshop.jpg")
+	})
+
+	original, err := meta.Exif("../meta/testdata/photoshop.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEqual(t, float32(0), original.Lat)
+
+	result := PerformRequest(app, "GET", "/api/v1/original")
+
+	assert.Equal(t, http.StatusOK, result.Code)
+	assert.Equal(t, "image/jpeg", result.Header().Get("Content-Type"))
+
+	tmpFile, err := ioutil.TempFile("", "original*.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(result.Body.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpFile.Close()
+
+	data, err := meta.Exif(tmpFile.Name())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, float32(0), data.Lat)
+	assert.Equal(t, float32(0), data.Lng)
+	assert.Equal(t, "", data.CameraModel)
+	assert.Equal(t, "© Jane Doe", data.Copyright)
+	assert.Equal(t, original.Orientation, data.Orientation)
+}
+
+func TestServeOriginal_Jfif(t *testing.T) {
+	app, router, _ := NewApiTest()
+
+	thumb.Copyright = "© Jane Doe"
+
+	defer func() { thumb.Copyright = "" }()
+
+	var buf bytes.Buffer
+
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	jfif := []byte{0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00, 0x01, 0x01, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00}
+	data := append(append([]byte{0xFF, 0xD8}, jfif...), buf.Bytes()[2:]...)
+
+	tmpFile, err := ioutil.TempFile("", "jfif*.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpFile.Close()
+
+	router.GET("/jfif", func(c *gin.Context) {
+		serveOriginal(c, tmpFile.Name())
+	})
+
+	result := PerformRequest(app, "GET", "/api/v1/jfif")
+
+	assert.Equal(t, http.StatusOK, result.Code)
+
+	// The start of image marker must be followed by JFIF, and the Exif segment comes after it.
+	body := result.Body.Bytes()
+
+	if assert.True(t, len(body) > 2+len(jfif)+10) {
+		assert.Equal(t, jfif, body[2:2+len(jfif)])
+		assert.Equal(t, []byte{0xFF, 0xE1}, body[2+len(jfif):4+len(jfif)])
+		assert.Equal(t, []byte("Exif\x00\x00"), body[6+len(jfif):12+len(jfif)])
+	}
+}
@@ -63,7 +63,7 @@ func GetPreview(router *gin.RouterGroup, conf *config.Config) {
 		thumbType, _ := thumb.Types["tile_224"]
 
 		for _, f := range p {
-			fileName := path.Join(conf.OriginalsPath(), f.FileName)
+			fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 
 			if !fs.FileExists(fileName) {
 				log.Errorf("could not find original for thumbnail: %s", fileName)
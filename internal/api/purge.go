@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// POST /api/v1/purge
+//
+// Flags files as missing if they don't exist anymore, deletes photos without existing files and
+// removes orphaned label and album associations. Affected records are returned.
+//
+// Parameters:
+//   path:   string Only check files in this sub-folder of originals
+//   hard:   bool   Permanently delete photos
+//   dryRun: bool   Only return affected records
+//   force:  bool   Purge even if the originals root is empty or most files are missing
+func StartPurge(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/purge", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		var f form.PurgeOptions
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if _, err := originalsSubPath(conf, f.Root, f.Path); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		opt := photoprism.PurgeOptions{Root: f.Root, Path: f.Path, Hard: f.Hard, DryRun: f.DryRun, Force: f.Force}
+
+		result, err := photoprism.NewPurge(conf).Start(opt)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if !f.DryRun {
+			event.Info(fmt.Sprintf("purge flagged %d files as missing and deleted %d photos", len(result.Files), len(result.Photos)))
+			event.Publish("config.updated", event.Data(conf.ClientConfig()))
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
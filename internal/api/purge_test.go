@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartPurge(t *testing.T) {
+	t.Run("dry run", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		StartPurge(router, conf)
+
+		result := performAdminRequest(app, "POST", "/api/v1/purge", `{"dryRun": true}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		var purged photoprism.PurgeResult
+
+		if err := json.Unmarshal(result.Body.Bytes(), &purged); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("invalid path", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		StartPurge(router, conf)
+
+		result := performAdminRequest(app, "POST", "/api/v1/purge", `{"path": "../.."}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		StartPurge(router, conf)
+
+		result := performRoleRequest(app, "viewer", "POST", "/api/v1/purge", `{"dryRun": true}`)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
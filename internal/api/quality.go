@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// qualityEventSize is the number of updated photos published per event.
+const qualityEventSize = 100
+
+// POST /api/v1/quality
+//
+// Calculates the quality score of photos again without indexing, e.g. after the weights were changed.
+// Only photos matching the search filter in "q" are updated if it's not empty. Returns the number of
+// updated photos.
+func UpdateQuality(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/quality", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		q := query.New(conf.Db())
+
+		updated, err := q.UpdateQuality(c.Query("q"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		for i := 0; i < len(updated); i += qualityEventSize {
+			j := i + qualityEventSize
+
+			if j > len(updated) {
+				j = len(updated)
+			}
+
+			if entities, err := q.PhotoSelection(form.Selection{Photos: updated[i:j]}); err == nil {
+				event.EntitiesUpdated("photos", entities)
+			}
+		}
+
+		event.Info(fmt.Sprintf("updated %d photo quality scores", len(updated)))
+
+		c.JSON(http.StatusOK, gin.H{"updated": len(updated)})
+	})
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestUpdateQuality(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		UpdateQuality(router, conf)
+
+		result := performAdminRequest(app, "POST", "/api/v1/quality", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.True(t, gjson.Get(result.Body.String(), "updated").Exists())
+	})
+	t.Run("filter", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		UpdateQuality(router, conf)
+
+		result := performAdminRequest(app, "POST", "/api/v1/quality?q=label:xxx", "")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		UpdateQuality(router, conf)
+
+		result := performRoleRequest(app, "viewer", "POST", "/api/v1/quality", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
@@ -2,11 +2,15 @@ package api
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/internal/session"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -20,18 +24,27 @@ func CreateSession(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		if !conf.CheckPassword(f.Password) {
+		u, err := conf.Authenticate(f.UserName, f.Password)
+
+		if err != nil {
 			c.AbortWithStatusJSON(400, gin.H{"error": "Invalid password"})
 			return
 		}
 
-		user := gin.H{"ID": 1, "FirstName": "Admin", "LastName": "", "Role": "admin", "Email": "photoprism@localhost"}
+		user := gin.H{"ID": u.ID, "UserName": u.UserName, "FirstName": strings.Title(u.UserName), "LastName": "", "Role": u.UserRole, "Email": "photoprism@localhost"}
 
 		token := service.Session().Create(user)
 
 		c.Header("X-Session-Token", token)
 
-		s := gin.H{"token": token, "user": user, "config": conf.ClientConfig()}
+		cfg := conf.ClientConfig()
+		cfg["settings"] = conf.UserSettings(u.ID)
+
+		s := gin.H{"token": token, "user": user, "config": cfg}
+
+		if conf.DownloadTokens() {
+			s["downloadToken"] = downloadToken(conf, session.SubjectSession+entity.SessionHash(token))
+		}
 
 		c.JSON(http.StatusOK, s)
 	})
@@ -55,9 +68,148 @@ func Unauthorized(c *gin.Context, conf *config.Config) bool {
 		return false
 	}
 
-	// Get session token from HTTP header
+	// Requests with a valid API token are authorized
+	if _, ok := requestApiToken(c); ok {
+		return false
+	}
+
+	// Check if session token is valid and the user still exists
+	_, ok := sessionUser(c, conf)
+
+	return !ok
+}
+
+// SessionViewer returns the user id, name and role of the current session for album permissions.
+// Anonymous users get the configured public role, API tokens the role matching their scope. In public mode,
+// anonymous users may not see private photos and photos in review.
+func SessionViewer(c *gin.Context, conf *config.Config) query.Viewer {
+	if m, ok := requestApiToken(c); ok {
+		return query.Viewer{ID: m.UserID, Role: m.Role()}
+	}
+
+	if viewer, ok := sessionUser(c, conf); ok {
+		return viewer
+	}
+
+	return query.Viewer{Role: conf.PublicRole(), Public: conf.Public()}
+}
+
+// sessionUser returns the user of the session token in the request header. The name and role are loaded
+// from the database, so that removed users and role changes apply to existing sessions immediately.
+func sessionUser(c *gin.Context, conf *config.Config) (viewer query.Viewer, ok bool) {
 	token := c.GetHeader("X-Session-Token")
 
-	// Check if session token is valid
-	return !service.Session().Exists(token)
+	if token == "" {
+		return viewer, false
+	}
+
+	data, ok := service.Session().Get(token)
+
+	if !ok {
+		return viewer, false
+	}
+
+	var user map[string]interface{}
+
+	switch d := data.(type) {
+	case gin.H:
+		user = d
+	case map[string]interface{}:
+		user = d
+	default:
+		return viewer, false
+	}
+
+	name, _ := user["UserName"].(string)
+	role, _ := user["Role"].(string)
+
+	var id uint
+
+	// Numbers may be float64 if the session data was decoded from JSON.
+	switch n := user["ID"].(type) {
+	case uint:
+		id = n
+	case int:
+		id = uint(n)
+	case float64:
+		id = uint(n)
+	}
+
+	if id == 0 {
+		return query.Viewer{User: name, Role: role}, true
+	}
+
+	m, err := entity.FindUser(conf.Db(), id)
+
+	if err != nil && id == entity.AdminUserID {
+		// The default admin may log in with the config password before it was created.
+		admin := entity.Admin
+		m, err = &admin, nil
+	}
+
+	if err != nil {
+		log.Debugf("session: user %d not found", id)
+		return viewer, false
+	}
+
+	return query.Viewer{ID: m.ID, User: m.UserName, Role: m.UserRole}, true
+}
+
+// AdminOnly returns true and aborts the request if the session doesn't have the admin role.
+func AdminOnly(c *gin.Context, conf *config.Config) bool {
+	if Unauthorized(c, conf) || !SessionViewer(c, conf).Admin() {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+		return true
+	}
+
+	return false
+}
+
+// Routes that viewers may still send write requests to, e.g. to log in and change their settings.
+var viewerWriteRoutes = []string{"/session", "/session/:token", "/settings", "/s/:token"}
+
+// Routes that uploaders may send write requests to in addition to viewer routes.
+var uploaderWriteRoutes = []string{"/upload/:path", "/uploads", "/uploads/:id", "/uploads/:id/complete", "/import/*path"}
+
+// RoleAccess returns a middleware that rejects requests modifying photos, albums, labels and other
+// data if the session has the viewer or uploader role. Uploaders may upload and import files.
+// API tokens with read scope may only send read requests.
+func RoleAccess(conf *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		if m, ok := requestApiToken(c); ok && m.TokenScope == entity.ScopeRead {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrPermissionDenied)
+			return
+		}
+
+		role := SessionViewer(c, conf).Role
+
+		if role != entity.RoleViewer && role != entity.RoleUploader {
+			c.Next()
+			return
+		}
+
+		if matchRoute(c.FullPath(), viewerWriteRoutes) || role == entity.RoleUploader && matchRoute(c.FullPath(), uploaderWriteRoutes) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrPermissionDenied)
+	}
+}
+
+// matchRoute returns true if the route path ends with one of the given routes.
+func matchRoute(fullPath string, routes []string) bool {
+	for _, route := range routes {
+		if strings.HasSuffix(fullPath, route) {
+			return true
+		}
+	}
+
+	return false
 }
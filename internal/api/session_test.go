@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// performRoleRequest performs an API request with a session token of a user with the given role.
+func performRoleRequest(r http.Handler, role, method, path, body string) *httptest.ResponseRecorder {
+	conf := config.TestConfig()
+	service.SetConfig(conf)
+
+	// Sessions use the current role from the database.
+	user := entity.User{ID: 1020, UserName: "role-test", UserRole: role}
+
+	if err := user.Save(conf.Db()); err != nil {
+		panic(err)
+	}
+
+	token := service.Session().Create(gin.H{"ID": user.ID, "UserName": user.UserName, "Role": role})
+	defer service.Session().Delete(token)
+
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-Session-Token", token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRoleAccess(t *testing.T) {
+	t.Run("viewer update photo", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		UpdatePhoto(router, conf)
+		result := performRoleRequest(app, entity.RoleViewer, "PUT", "/api/v1/photos/654", `{"PhotoTitle": "Changed"}`)
+		assert.Equal(t, http.StatusForbidden, result.Code)
+	})
+	t.Run("uploader update photo", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		UpdatePhoto(router, conf)
+		result := performRoleRequest(app, entity.RoleUploader, "PUT", "/api/v1/photos/654", `{"PhotoTitle": "Changed"}`)
+		assert.Equal(t, http.StatusForbidden, result.Code)
+	})
+	t.Run("admin update photo", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		UpdatePhoto(router, conf)
+		result := performRoleRequest(app, entity.RoleAdmin, "PUT", "/api/v1/photos/xxx", `{"PhotoTitle": "Changed"}`)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("viewer delete photos", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		BatchPhotosArchive(router, conf)
+		result := performRoleRequest(app, entity.RoleViewer, "POST", "/api/v1/batch/photos/archive", `{"photos": ["654"]}`)
+		assert.Equal(t, http.StatusForbidden, result.Code)
+	})
+	t.Run("admin delete photos", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		BatchPhotosArchive(router, conf)
+		result := performRoleRequest(app, entity.RoleAdmin, "POST", "/api/v1/batch/photos/archive", `{"photos": []}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("viewer read photo", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		GetPhoto(router, conf)
+		result := performRoleRequest(app, entity.RoleViewer, "GET", "/api/v1/photos/654", "")
+		assert.NotEqual(t, http.StatusForbidden, result.Code)
+	})
+	t.Run("viewer settings", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		SaveSettings(router, conf)
+		result := performRoleRequest(app, entity.RoleViewer, "POST", "/api/v1/settings", `{"theme": "lavendel"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("uploader upload", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		router.Use(RoleAccess(conf))
+		router.POST("/upload/:path", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		assert.Equal(t, http.StatusNoContent, performRoleRequest(app, entity.RoleUploader, "POST", "/api/v1/upload/abc", "").Code)
+		assert.Equal(t, http.StatusForbidden, performRoleRequest(app, entity.RoleViewer, "POST", "/api/v1/upload/abc", "").Code)
+	})
+}
+
+func TestSessionUser(t *testing.T) {
+	app, router, conf, cleanup := newDownloadTokenTest(t, false)
+	defer cleanup()
+
+	router.GET("/viewer", func(c *gin.Context) {
+		if Unauthorized(c, conf) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		c.String(http.StatusOK, SessionViewer(c, conf).Role)
+	})
+
+	user := entity.User{ID: 1021, UserName: "session-test", UserRole: entity.RoleAdmin}
+
+	if err := user.Save(conf.Db()); err != nil {
+		t.Fatal(err)
+	}
+
+	token := service.Session().Create(gin.H{"ID": user.ID, "UserName": user.UserName, "Role": user.UserRole})
+	defer service.Session().Delete(token)
+
+	request := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/api/v1/viewer", nil)
+		req.Header.Set("X-Session-Token", token)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("admin", func(t *testing.T) {
+		result := request()
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, entity.RoleAdmin, result.Body.String())
+	})
+	t.Run("role changed", func(t *testing.T) {
+		user.UserRole = entity.RoleViewer
+
+		if err := user.Save(conf.Db()); err != nil {
+			t.Fatal(err)
+		}
+
+		result := request()
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, entity.RoleViewer, result.Body.String())
+	})
+	t.Run("removed", func(t *testing.T) {
+		if err := user.Delete(conf.Db()); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, http.StatusUnauthorized, request().Code)
+	})
+}
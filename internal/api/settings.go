@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/photoprism/photoprism/internal/config"
@@ -10,6 +11,8 @@ import (
 )
 
 // GET /api/v1/settings
+//
+// Returns the settings of the current user, or the global settings without user session.
 func GetSettings(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/settings", func(c *gin.Context) {
 		if Unauthorized(c, conf) {
@@ -17,13 +20,16 @@ func GetSettings(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		s := conf.Settings()
+		s := conf.UserSettings(SessionViewer(c, conf).ID)
 
 		c.JSON(http.StatusOK, s)
 	})
 }
 
 // POST /api/v1/settings
+//
+// Saves the settings of the current user. Global settings like features and library options are
+// saved by admins, anonymous users in public mode save all global settings.
 func SaveSettings(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/settings", func(c *gin.Context) {
 		if conf.DisableSettings() || Unauthorized(c, conf) {
@@ -31,21 +37,112 @@ func SaveSettings(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		s := conf.Settings()
+		v := SessionViewer(c, conf)
+		s := conf.UserSettings(v.ID)
 
 		if err := c.BindJSON(s); err != nil {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
 			return
 		}
 
-		if err := s.Save(conf.SettingsFile()); err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		if err := s.Validate(); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
 			return
 		}
 
+		if v.ID == 0 || v.Admin() {
+			global := conf.Settings()
+
+			// Personal settings like theme and language of admins are saved as user settings.
+			if v.ID == 0 {
+				*global = *s
+			} else {
+				global.SetGlobal(s)
+			}
+
+			if err := global.Save(conf.SettingsFile()); err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+				return
+			}
+
+			global.Propagate()
+		}
+
+		if v.ID > 0 {
+			if err := conf.SaveUserSettings(v.ID, s); err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+				return
+			}
+		}
+
 		event.Publish("config.updated", event.Data(conf.ClientConfig()))
 		log.Infof("settings saved")
 
 		c.JSON(http.StatusOK, s)
 	})
 }
+
+// GET /api/v1/users/:uid/settings
+func GetUserSettings(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/users/:uid/settings", func(c *gin.Context) {
+		id, ok := settingsUserID(c, conf)
+
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, conf.UserSettings(id))
+	})
+}
+
+// POST /api/v1/users/:uid/settings
+func SaveUserSettings(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/users/:uid/settings", func(c *gin.Context) {
+		if conf.DisableSettings() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		id, ok := settingsUserID(c, conf)
+
+		if !ok {
+			return
+		}
+
+		s := conf.UserSettings(id)
+
+		if err := c.BindJSON(s); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if err := conf.SaveUserSettings(id, s); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		log.Infof("settings saved for user %d", id)
+
+		c.JSON(http.StatusOK, conf.UserSettings(id))
+	})
+}
+
+// settingsUserID returns the user id from the request path and aborts the request if the
+// session user is neither the same user nor an admin.
+func settingsUserID(c *gin.Context, conf *config.Config) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("uid"), 10, 32)
+
+	if err != nil || id == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrUserNotFound)
+		return 0, false
+	}
+
+	v := SessionViewer(c, conf)
+
+	if Unauthorized(c, conf) || v.ID == 0 || (v.ID != uint(id) && !v.Admin()) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+		return 0, false
+	}
+
+	return uint(id), true
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// performUserRequest performs an API request with a session token of a regular user.
+func performUserRequest(r http.Handler, id uint, method, path, body string) *httptest.ResponseRecorder {
+	conf := config.TestConfig()
+	service.SetConfig(conf)
+
+	user := entity.User{ID: id, UserName: fmt.Sprintf("user%d", id), UserRole: entity.RoleViewer}
+
+	if err := user.Save(conf.Db()); err != nil {
+		panic(err)
+	}
+
+	token := service.Session().Create(gin.H{"ID": id, "UserName": user.UserName, "Role": user.UserRole})
+	defer service.Session().Delete(token)
+
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-Session-Token", token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetSettings(t *testing.T) {
+	t.Run("public", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetSettings(router, conf)
+		result := PerformRequest(app, "GET", "/api/v1/settings")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "\"theme\":\""+conf.Settings().Theme+"\"")
+	})
+}
+
+func TestSaveUserSettings(t *testing.T) {
+	t.Run("separate users", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetSettings(router, conf)
+		SaveSettings(router, conf)
+
+		result := performUserRequest(app, 1011, "POST", "/api/v1/settings", `{"theme": "lavendel"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = performUserRequest(app, 1012, "POST", "/api/v1/settings", `{"theme": "mint"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = performUserRequest(app, 1011, "GET", "/api/v1/settings", "")
+		assert.Contains(t, result.Body.String(), "\"theme\":\"lavendel\"")
+
+		result = performUserRequest(app, 1012, "GET", "/api/v1/settings", "")
+		assert.Contains(t, result.Body.String(), "\"theme\":\"mint\"")
+
+		assert.NotEqual(t, "lavendel", conf.Settings().Theme)
+		assert.NotEqual(t, "mint", conf.Settings().Theme)
+	})
+	t.Run("admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetSettings(router, conf)
+		SaveSettings(router, conf)
+
+		theme := conf.Settings().Theme
+		upload := conf.Settings().Features.Upload
+
+		defer func() {
+			conf.Settings().Features.Upload = upload
+			_ = conf.Settings().Save(conf.SettingsFile())
+		}()
+
+		body := fmt.Sprintf(`{"theme": "onyx", "features": {"upload": %t}}`, !upload)
+		result := performRoleRequest(app, entity.RoleAdmin, "POST", "/api/v1/settings", body)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		// Only global sections like features are copied to the global settings.
+		assert.Equal(t, theme, conf.Settings().Theme)
+		assert.Equal(t, !upload, conf.Settings().Features.Upload)
+
+		result = performRoleRequest(app, entity.RoleAdmin, "GET", "/api/v1/settings", "")
+		assert.Contains(t, result.Body.String(), "\"theme\":\"onyx\"")
+	})
+	t.Run("same user", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		SaveUserSettings(router, conf)
+		GetUserSettings(router, conf)
+
+		result := performUserRequest(app, 1013, "POST", "/api/v1/users/1013/settings", `{"language": "de"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = performUserRequest(app, 1013, "GET", "/api/v1/users/1013/settings", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "\"language\":\"de\"")
+	})
+	t.Run("other user", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetUserSettings(router, conf)
+		result := performUserRequest(app, 1014, "GET", "/api/v1/users/1013/settings", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("anonymous", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetUserSettings(router, conf)
+		result := PerformRequest(app, "GET", "/api/v1/users/1013/settings")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("invalid id", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetUserSettings(router, conf)
+		result := performUserRequest(app, 1013, "GET", "/api/v1/users/abc/settings", "")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
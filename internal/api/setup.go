@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/setup
+//
+// Returns whether setup is required and the current site title and storage paths as defaults.
+func GetSetup(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/setup", func(c *gin.Context) {
+		required := conf.SetupRequired()
+		result := gin.H{"required": required}
+
+		if required {
+			result["title"] = conf.Title()
+			result["originalsPath"] = conf.OriginalsPath()
+			result["importPath"] = conf.ImportPath()
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// POST /api/v1/setup
+//
+// Sets the admin password, site title and storage paths. Only available until setup is completed.
+func CompleteSetup(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/setup", func(c *gin.Context) {
+		if !conf.SetupRequired() {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrSetupCompleted)
+			return
+		}
+
+		var f config.SetupOptions
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if err := conf.Setup(f); err == config.ErrSetupCompleted {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrSetupCompleted)
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		event.Publish("config.updated", event.Data(conf.ClientConfig()))
+		event.Publish("config.setup", event.Data{"originalsPath": conf.OriginalsPath(), "importPath": conf.ImportPath()})
+
+		c.JSON(http.StatusOK, gin.H{"message": "setup completed"})
+	})
+}
+
+// RequireSetup returns a middleware that rejects requests with 503 Service Unavailable
+// until setup is completed, so that nobody can log in with the default admin password.
+func RequireSetup(conf *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if conf.SetupRequired() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrSetupRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	// A separate config is used, so that completing the setup doesn't affect other tests.
+	conf := config.NewTestConfig()
+	gin.SetMode(gin.TestMode)
+	app := gin.New()
+	router := app.Group("/api/v1")
+
+	service.SetConfig(conf)
+
+	defer func() {
+		service.SetConfig(config.TestConfig())
+		os.Remove(conf.OptionsFile())
+		conf.Db().Model(&entity.User{}).Where("id = ?", entity.AdminUserID).Update("password_hash", "")
+	}()
+
+	GetSetup(router, conf)
+	CompleteSetup(router, conf)
+	router.Use(RequireSetup(conf))
+	CreateSession(router, conf)
+	GetPhotos(router, conf)
+
+	if !conf.SetupRequired() {
+		t.Fatal("setup should be required")
+	}
+
+	t.Run("locked", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/photos?count=10")
+		assert.Equal(t, http.StatusServiceUnavailable, result.Code)
+		assert.Contains(t, result.Body.String(), "Setup required")
+
+		result = PerformRequestWithBody(app, "POST", "/api/v1/session", `{"username": "admin", "password": "photoprism"}`)
+		assert.Equal(t, http.StatusServiceUnavailable, result.Code)
+
+		result = PerformRequest(app, "GET", "/api/v1/setup")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "\"required\":true")
+	})
+	t.Run("invalid paths", func(t *testing.T) {
+		body := fmt.Sprintf(`{"password": "secret", "originalsPath": "%s", "importPath": "%s/import"}`, conf.OriginalsPath(), conf.OriginalsPath())
+		result := PerformRequestWithBody(app, "POST", "/api/v1/setup", body)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+
+		result = PerformRequest(app, "GET", "/api/v1/photos?count=10")
+		assert.Equal(t, http.StatusServiceUnavailable, result.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		body := fmt.Sprintf(`{"password": "secret", "title": "Family Photos", "originalsPath": "%s", "importPath": "%s"}`, conf.OriginalsPath(), conf.ImportPath())
+		result := PerformRequestWithBody(app, "POST", "/api/v1/setup", body)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "Family Photos", conf.Title())
+		assert.FileExists(t, conf.OptionsFile())
+
+		result = PerformRequest(app, "GET", "/api/v1/photos?count=10")
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = PerformRequestWithBody(app, "POST", "/api/v1/session", `{"username": "admin", "password": "photoprism"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+
+		result = PerformRequestWithBody(app, "POST", "/api/v1/session", `{"username": "admin", "password": "secret"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("completed", func(t *testing.T) {
+		result := PerformRequestWithBody(app, "POST", "/api/v1/setup", `{"password": "other"}`)
+		assert.Equal(t, http.StatusForbidden, result.Code)
+
+		result = PerformRequest(app, "GET", "/api/v1/setup")
+		assert.Equal(t, "{\"required\":false}", result.Body.String())
+	})
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/pkg/txt"
+
+	gc "github.com/patrickmn/go-cache"
+)
+
+// shareAccess maps access tokens of visitors who opened a link to the link token. Access tokens
+// are kept separate from user sessions, so they don't grant access to other API endpoints.
+var shareAccess = gc.New(entity.LinkAccessTTL, time.Hour)
+
+// LinkMaxAttempts is the number of wrong passwords after which a link can't be opened for LinkAttemptsTTL.
+const LinkMaxAttempts = 5
+
+// LinkAttemptsTTL is the time wrong passwords are counted after the last attempt.
+const LinkAttemptsTTL = 15 * time.Minute
+
+// shareAttempts counts wrong passwords by link token, so that passwords can't be guessed.
+var shareAttempts = gc.New(LinkAttemptsTTL, time.Hour)
+
+// shareAttemptsMutex makes sure that concurrent wrong passwords are all counted.
+var shareAttemptsMutex sync.Mutex
+
+// linkAttempts returns the number of wrong passwords for a link.
+func linkAttempts(linkToken string) int {
+	if n, ok := shareAttempts.Get(linkToken); ok {
+		return n.(int)
+	}
+
+	return 0
+}
+
+// addLinkAttempt counts a wrong password for a link.
+func addLinkAttempt(linkToken string) {
+	shareAttemptsMutex.Lock()
+	defer shareAttemptsMutex.Unlock()
+
+	shareAttempts.SetDefault(linkToken, linkAttempts(linkToken)+1)
+}
+
+// openLink counts a view and returns an access token if the link can be opened with the password.
+func openLink(c *gin.Context, conf *config.Config, password string) {
+	db := conf.Db()
+	link, err := entity.FindLink(db, c.Param("token"))
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrLinkNotFound)
+		return
+	}
+
+	if link.Expired() || link.Exhausted() {
+		c.AbortWithStatusJSON(http.StatusGone, ErrLinkExpired)
+		return
+	}
+
+	if link.HasPassword() && password == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrPasswordRequired)
+		return
+	}
+
+	if link.HasPassword() && linkAttempts(link.LinkToken) >= LinkMaxAttempts {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrTooManyAttempts)
+		return
+	}
+
+	if !link.CheckPassword(password) {
+		addLinkAttempt(link.LinkToken)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrInvalidPassword)
+		return
+	}
+
+	if ok, err := link.AddView(db); err != nil {
+		log.Errorf("share: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+		return
+	} else if !ok {
+		c.AbortWithStatusJSON(http.StatusGone, ErrLinkExpired)
+		return
+	}
+
+	access := session.Token()
+	shareAccess.SetDefault(access, link.LinkToken)
+
+	result := gin.H{"token": access, "link": link}
+
+	if conf.DownloadTokens() {
+		result["downloadToken"] = downloadToken(conf, session.SubjectLink+link.LinkToken)
+	}
+
+	if album, err := query.New(db).AlbumByUUID(link.ShareUUID); err == nil {
+		result["album"] = album
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GET /api/v1/s/:token
+//
+// Opens a sharing link without password and returns an access token for the X-Share-Token header.
+func GetShare(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/s/:token", func(c *gin.Context) {
+		openLink(c, conf, "")
+	})
+}
+
+// POST /api/v1/s/:token
+//
+// Opens a password protected sharing link and returns an access token for the X-Share-Token header.
+func UnlockShare(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/s/:token", func(c *gin.Context) {
+		var f form.SharePassword
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		openLink(c, conf, f.Password)
+	})
+}
+
+// GET /api/v1/s/:token/photos
+//
+// Returns public photos of a shared album or photo, requires the access token returned when opening the link.
+//
+// Query:
+//   count:  int Max result count (required)
+//   offset: int Result offset
+func GetSharePhotos(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/s/:token/photos", func(c *gin.Context) {
+		token := c.Param("token")
+
+		if linkToken, ok := shareAccess.Get(c.GetHeader("X-Share-Token")); !ok || linkToken != token {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		db := conf.Db()
+		link, err := entity.FindLink(db, token)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrLinkNotFound)
+			return
+		}
+
+		if link.Expired() {
+			c.AbortWithStatusJSON(http.StatusGone, ErrLinkExpired)
+			return
+		}
+
+		var f form.PhotoSearch
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		q := query.New(db).As(query.LinkViewer)
+
+		// Only count and offset may be chosen by visitors.
+		f = form.PhotoSearch{Count: f.Count, Offset: f.Offset}
+
+		if _, err := q.AlbumByUUID(link.ShareUUID); err == nil {
+			f.Album = link.ShareUUID
+		} else {
+			f.ID = link.ShareUUID
+		}
+
+		result, _, err := q.Photos(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.Header("X-Count", strconv.Itoa(len(result)))
+		c.Header("X-Limit", strconv.Itoa(query.Limit(f.Count)))
+		c.Header("X-Offset", strconv.Itoa(f.Offset))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
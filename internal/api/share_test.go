@@ -0,0 +1,262 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+// createShareLink creates a sharing link for testing and returns its token.
+func createShareLink(t *testing.T, db *gorm.DB, shareUUID, password string, expires time.Duration, maxViews uint) string {
+	link := entity.NewLink(false, false)
+	link.ShareUUID = shareUUID
+	link.MaxViews = maxViews
+
+	if expires != 0 {
+		expiresAt := time.Now().Add(expires)
+		link.LinkExpires = &expiresAt
+	}
+
+	if err := link.SetPassword(password); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Create(&link).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return link.LinkToken
+}
+
+// shareAccessToken returns the access token of an opened link.
+func shareAccessToken(t *testing.T, result *httptest.ResponseRecorder) string {
+	var body struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.Unmarshal(result.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	return body.Token
+}
+
+func TestGetShare(t *testing.T) {
+	app, router, conf := NewApiTest()
+	GetShare(router, conf)
+	db := conf.Db()
+
+	t.Run("not found", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/api/v1/s/xxx")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("valid", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "", time.Hour, 0)
+		result := PerformRequest(app, "GET", "/api/v1/s/"+token)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "Berlin2019")
+		assert.NotEmpty(t, shareAccessToken(t, result))
+	})
+	t.Run("expired", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "", -time.Second, 0)
+		result := PerformRequest(app, "GET", "/api/v1/s/"+token)
+		assert.Equal(t, http.StatusGone, result.Code)
+		assert.NotContains(t, result.Body.String(), "Berlin2019")
+	})
+	t.Run("about to expire", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "", 5*time.Second, 0)
+		result := PerformRequest(app, "GET", "/api/v1/s/"+token)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("password required", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "secret", time.Hour, 0)
+		result := PerformRequest(app, "GET", "/api/v1/s/"+token)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+		assert.Contains(t, result.Body.String(), "Password required")
+		assert.NotContains(t, result.Body.String(), "Berlin2019")
+	})
+	t.Run("max views", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "", 0, 2)
+
+		assert.Equal(t, http.StatusOK, PerformRequest(app, "GET", "/api/v1/s/"+token).Code)
+		assert.Equal(t, http.StatusOK, PerformRequest(app, "GET", "/api/v1/s/"+token).Code)
+		assert.Equal(t, http.StatusGone, PerformRequest(app, "GET", "/api/v1/s/"+token).Code)
+
+		link, err := entity.FindLink(db, token)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, uint(2), link.LinkViews)
+	})
+	t.Run("concurrent views", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "", 0, 3)
+
+		var wg sync.WaitGroup
+		codes := make(chan int, 10)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				codes <- PerformRequest(app, "GET", "/api/v1/s/"+token).Code
+			}()
+		}
+
+		wg.Wait()
+		close(codes)
+
+		opened := 0
+
+		for code := range codes {
+			if code == http.StatusOK {
+				opened++
+			}
+		}
+
+		assert.Equal(t, 3, opened)
+
+		link, err := entity.FindLink(db, token)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, uint(3), link.LinkViews)
+	})
+}
+
+func TestUnlockShare(t *testing.T) {
+	app, router, conf := NewApiTest()
+	UnlockShare(router, conf)
+	db := conf.Db()
+
+	t.Run("wrong password", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "secret", time.Hour, 0)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/s/"+token, `{"password": "Secret"}`)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+		assert.Contains(t, result.Body.String(), "Invalid password")
+		assert.NotContains(t, result.Body.String(), "Berlin2019")
+	})
+	t.Run("password", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "secret", time.Hour, 0)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/s/"+token, `{"password": "secret"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "Berlin2019")
+		assert.NotContains(t, result.Body.String(), "secret")
+		assert.NotEmpty(t, shareAccessToken(t, result))
+	})
+	t.Run("plain text password", func(t *testing.T) {
+		result := PerformRequestWithBody(app, "POST", "/api/v1/s/1jxf3jfn2k", `{"password": "somepassword"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("expired", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "secret", -time.Second, 0)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/s/"+token, `{"password": "secret"}`)
+		assert.Equal(t, http.StatusGone, result.Code)
+	})
+	t.Run("too many attempts", func(t *testing.T) {
+		token := createShareLink(t, db, "5", "secret", time.Hour, 0)
+
+		for i := 0; i < LinkMaxAttempts; i++ {
+			result := PerformRequestWithBody(app, "POST", "/api/v1/s/"+token, `{"password": "guess"}`)
+			assert.Equal(t, http.StatusUnauthorized, result.Code)
+		}
+
+		// The correct password is rejected as well until the attempts expire.
+		result := PerformRequestWithBody(app, "POST", "/api/v1/s/"+token, `{"password": "secret"}`)
+		assert.Equal(t, http.StatusTooManyRequests, result.Code)
+		assert.NotContains(t, result.Body.String(), "Berlin2019")
+
+		shareAttempts.Delete(token)
+
+		result = PerformRequestWithBody(app, "POST", "/api/v1/s/"+token, `{"password": "secret"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
+
+func TestGetSharePhotos(t *testing.T) {
+	app, router, conf := NewApiTest()
+	GetShare(router, conf)
+	GetSharePhotos(router, conf)
+	db := conf.Db()
+
+	// Private photo in the shared album.
+	db.Exec("INSERT INTO photos (id, photo_uuid, photo_private, photo_quality, taken_at, photo_title, camera_id, lens_id) VALUES (100, 'pt_share_private', 1, 3, '2016-01-01 10:00:00', 'Private Share', 1, 1)")
+	db.Exec("INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_hash, file_missing, file_type) VALUES (100, '100', 'pt_share_private', 'ft_share_private', 'private.jpg', 1, 'share_private', 0, 'jpg')")
+	db.Exec("INSERT INTO photos_albums (album_uuid, photo_uuid) VALUES ('5', 'pt_share_private')")
+
+	token := createShareLink(t, db, "5", "", time.Hour, 0)
+	access := shareAccessToken(t, PerformRequest(app, "GET", "/api/v1/s/"+token))
+
+	photos := func(access string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/api/v1/s/"+token+"/photos?count=10", nil)
+		req.Header.Set("X-Share-Token", access)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("public photos only", func(t *testing.T) {
+		result := photos(access)
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		var photos query.PhotoResults
+
+		if err := json.Unmarshal(result.Body.Bytes(), &photos); err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, photos, 1) {
+			assert.Equal(t, "658", photos[0].PhotoUUID)
+		}
+
+		assert.NotContains(t, result.Body.String(), "Private Share")
+	})
+	t.Run("no access token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, photos("").Code)
+		assert.Equal(t, http.StatusUnauthorized, photos("xxx").Code)
+	})
+	t.Run("access token of other link", func(t *testing.T) {
+		other := createShareLink(t, db, "4", "", time.Hour, 0)
+		otherAccess := shareAccessToken(t, PerformRequest(app, "GET", "/api/v1/s/"+other))
+		assert.Equal(t, http.StatusUnauthorized, photos(otherAccess).Code)
+	})
+	t.Run("expired", func(t *testing.T) {
+		db.Model(&entity.Link{}).Where("link_token = ?", token).Update("link_expires", time.Now().Add(-time.Second))
+
+		result := photos(access)
+		assert.Equal(t, http.StatusGone, result.Code)
+		assert.NotContains(t, result.Body.String(), "Private Share")
+		assert.NotContains(t, result.Body.String(), "Neckarbrücke")
+	})
+}
+
+func TestDeleteExpiredLinks(t *testing.T) {
+	_, _, conf := NewApiTest()
+	db := conf.Db()
+
+	expired := createShareLink(t, db, "5", "", -time.Minute, 0)
+	valid := createShareLink(t, db, "5", "", time.Hour, 0)
+
+	n, err := entity.DeleteExpiredLinks(db)
+
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, n, int64(1))
+
+	_, err = entity.FindLink(db, expired)
+	assert.Error(t, err)
+
+	_, err = entity.FindLink(db, valid)
+	assert.Nil(t, err)
+}
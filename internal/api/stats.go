@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/cache"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// Stats are cached as counting and summing up files is expensive for large libraries.
+const (
+	statsCacheKey = "stats"
+	statsCacheTTL = 5 * time.Minute
+)
+
+// GET /api/v1/stats
+//
+// Returns photo counts, storage and index statistics. Requires the admin role, unless the site is public.
+func GetStats(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/stats", func(c *gin.Context) {
+		if !conf.Public() && AdminOnly(c, conf) {
+			return
+		}
+
+		start := time.Now()
+		gc := conf.Cache()
+
+		var stats query.Stats
+
+		if cache.Load(gc, statsCacheKey, &stats) {
+			log.Debugf("stats: cache hit [%s]", time.Since(start))
+			c.JSON(http.StatusOK, stats)
+			return
+		}
+
+		q := query.New(conf.Db())
+		stats, err := q.Stats()
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if fs.PathExists(conf.CachePath()) {
+			if stats.CacheBytes, err = fs.DirSize(conf.CachePath()); err != nil {
+				log.Warnf("stats: %s", err)
+			}
+		}
+
+		gc.Set(statsCacheKey, stats, statsCacheTTL)
+
+		log.Debugf("stats: cached [%s]", time.Since(start))
+
+		c.JSON(http.StatusOK, stats)
+	})
+}
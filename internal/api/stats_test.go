@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestGetStats(t *testing.T) {
+	t.Run("public", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.Cache().Delete(statsCacheKey)
+		GetStats(router, conf)
+		result := PerformRequest(app, "GET", "/api/v1/stats")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.LessOrEqual(t, int64(1), gjson.Get(result.Body.String(), "Photos").Int())
+		assert.True(t, gjson.Get(result.Body.String(), "Index.FilesMissing").Exists())
+	})
+	t.Run("cached", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetStats(router, conf)
+		first := PerformRequest(app, "GET", "/api/v1/stats")
+		second := PerformRequest(app, "GET", "/api/v1/stats")
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.JSONEq(t, first.Body.String(), second.Body.String())
+	})
+}
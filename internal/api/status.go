@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,3 +14,35 @@ func GetStatus(router *gin.RouterGroup, conf *config.Config) {
 		c.JSON(http.StatusOK, gin.H{"status": "operational"})
 	})
 }
+
+// GET /api/v1/ready
+//
+// Returns 503 Service Unavailable while subsystems like the database are still initializing.
+func GetReady(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/ready", func(c *gin.Context) {
+		status := http.StatusOK
+
+		if !conf.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+
+		c.JSON(status, gin.H{"ready": status == http.StatusOK, "subsystems": conf.Subsystems()})
+	})
+}
+
+// RequireReady returns a middleware that rejects requests with 503 Service Unavailable
+// until the subsystems they depend on are initialized.
+func RequireReady(conf *config.Config, subsystems ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if name := conf.NotReady(subsystems...); name != "" {
+			c.Header("Retry-After", "5")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":     fmt.Sprintf("Service unavailable, %s is not ready yet", name),
+				"subsystem": name,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
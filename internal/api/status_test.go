@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatus(t *testing.T) {
+	app, router, conf := NewApiTest()
+	GetStatus(router, conf)
+	result := PerformRequest(app, "GET", "/api/v1/status")
+
+	assert.Equal(t, http.StatusOK, result.Code)
+	assert.Contains(t, result.Body.String(), "operational")
+}
+
+func TestGetReady(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetReady(router, conf)
+		result := PerformRequest(app, "GET", "/api/v1/ready")
+
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "\"ready\":true")
+	})
+	t.Run("warming", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetReady(router, conf)
+
+		conf.Warming(config.SubsystemModels)
+		defer conf.SetReady(config.SubsystemModels, nil)
+
+		result := PerformRequest(app, "GET", "/api/v1/ready")
+
+		assert.Equal(t, http.StatusServiceUnavailable, result.Code)
+		assert.Contains(t, result.Body.String(), "\"name\":\"models\",\"ready\":false")
+	})
+}
+
+func TestRequireReady(t *testing.T) {
+	app, router, conf := NewApiTest()
+	models := router.Group("", RequireReady(conf, config.SubsystemModels))
+	GetStatus(models, conf)
+
+	conf.Warming(config.SubsystemModels)
+
+	result := PerformRequest(app, "GET", "/api/v1/status")
+	assert.Equal(t, http.StatusServiceUnavailable, result.Code)
+	assert.Equal(t, "5", result.Header().Get("Retry-After"))
+	assert.Contains(t, result.Body.String(), "\"subsystem\":\"models\"")
+
+	conf.SetReady(config.SubsystemModels, errors.New("model not found"))
+
+	result = PerformRequest(app, "GET", "/api/v1/status")
+	assert.Equal(t, http.StatusServiceUnavailable, result.Code)
+
+	conf.Warming(config.SubsystemModels)
+	conf.SetReady(config.SubsystemModels, nil)
+
+	result = PerformRequest(app, "GET", "/api/v1/status")
+	assert.Equal(t, http.StatusOK, result.Code)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// POST /api/v1/titles
+//
+// Generates new titles for photos with generated titles, e.g. after the title template was changed.
+// Manually edited titles are kept. Returns the number of updated photos.
+func UpdateTitles(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/titles", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		updated, err := query.New(conf.Db()).UpdateTitles()
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		event.Info(fmt.Sprintf("updated %d photo titles", updated))
+
+		c.JSON(http.StatusOK, gin.H{"updated": updated})
+	})
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestUpdateTitles(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		UpdateTitles(router, conf)
+
+		result := performAdminRequest(app, "POST", "/api/v1/titles", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.True(t, gjson.Get(result.Body.String(), "updated").Exists())
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		UpdateTitles(router, conf)
+
+		result := performRoleRequest(app, "viewer", "POST", "/api/v1/titles", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
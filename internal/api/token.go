@@ -0,0 +1,142 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// apiTokenKey is the gin context key of the API token used for a request.
+const apiTokenKey = "apiToken"
+
+// ApiTokenAuth returns a middleware that checks "Authorization: Bearer <token>" headers. Requests
+// with invalid or revoked tokens are rejected, valid tokens are used instead of a session.
+func ApiTokenAuth(conf *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+
+		if !strings.HasPrefix(header, "Bearer ") {
+			c.Next()
+			return
+		}
+
+		m, err := entity.FindApiToken(conf.Db(), strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		if err := m.Touch(conf.Db()); err != nil {
+			log.Errorf("token: %s", err)
+		}
+
+		c.Set(apiTokenKey, m)
+		c.Next()
+	}
+}
+
+// requestApiToken returns the API token of the request, if any.
+func requestApiToken(c *gin.Context) (*entity.ApiToken, bool) {
+	if v, ok := c.Get(apiTokenKey); ok {
+		m, ok := v.(*entity.ApiToken)
+		return m, ok
+	}
+
+	return nil, false
+}
+
+// GET /api/v1/tokens
+//
+// Returns all API tokens, the tokens themselves can't be retrieved after creation.
+func GetApiTokens(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/tokens", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		var result []entity.ApiToken
+
+		if err := conf.Db().Order("id").Find(&result).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// POST /api/v1/tokens
+//
+// Creates an API token, e.g. {"label": "Upload script", "scope": "upload"}. The response contains the
+// token, which is not shown again.
+func CreateApiToken(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/tokens", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		var f form.ApiToken
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		m, token, err := entity.NewApiToken(f.Label, f.Scope, SessionViewer(c, conf).ID)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if err := m.Save(conf.Db()); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrSaveFailed)
+			return
+		}
+
+		log.Infof("token: created %s (%s)", m.TokenPrefix, m.TokenScope)
+
+		c.JSON(http.StatusOK, gin.H{"token": token, "info": m})
+	})
+}
+
+// DELETE /api/v1/tokens/:id
+//
+// Revokes an API token.
+func DeleteApiToken(router *gin.RouterGroup, conf *config.Config) {
+	router.DELETE("/tokens/:id", func(c *gin.Context) {
+		if AdminOnly(c, conf) {
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrTokenNotFound)
+			return
+		}
+
+		var m entity.ApiToken
+
+		if err := conf.Db().First(&m, id).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrTokenNotFound)
+			return
+		}
+
+		if err := m.Delete(conf.Db()); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrSaveFailed)
+			return
+		}
+
+		log.Infof("token: revoked %s", m.TokenPrefix)
+
+		c.JSON(http.StatusOK, m)
+	})
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// performTokenRequest performs an API request with an API token.
+func performTokenRequest(r http.Handler, token, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestApiTokenAuth(t *testing.T) {
+	app, router, conf := NewApiTest()
+	router.Use(ApiTokenAuth(conf), RoleAccess(conf))
+	GetPhotos(router, conf)
+	DeleteApiToken(router, conf)
+	router.POST("/upload/:path", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.POST("/settings", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.POST("/users/:uid/settings", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	newToken := func(scope string) (*entity.ApiToken, string) {
+		m, token, err := entity.NewApiToken("Test "+scope, scope, entity.AdminUserID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.Save(conf.Db()); err != nil {
+			t.Fatal(err)
+		}
+
+		return m, token
+	}
+
+	read, readToken := newToken(entity.ScopeRead)
+	defer read.Delete(conf.Db())
+
+	upload, uploadToken := newToken(entity.ScopeUpload)
+	defer upload.Delete(conf.Db())
+
+	t.Run("read scope", func(t *testing.T) {
+		result := performTokenRequest(app, readToken, "GET", "/api/v1/photos?count=1", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = performTokenRequest(app, readToken, "POST", "/api/v1/upload/abc", "")
+		assert.Equal(t, http.StatusForbidden, result.Code)
+
+		// Settings can't be changed, although viewers may change their own settings.
+		result = performTokenRequest(app, readToken, "POST", "/api/v1/settings", "{}")
+		assert.Equal(t, http.StatusForbidden, result.Code)
+
+		result = performTokenRequest(app, readToken, "POST", "/api/v1/users/1/settings", "{}")
+		assert.Equal(t, http.StatusForbidden, result.Code)
+	})
+	t.Run("upload scope", func(t *testing.T) {
+		result := performTokenRequest(app, uploadToken, "GET", "/api/v1/photos?count=1", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = performTokenRequest(app, uploadToken, "POST", "/api/v1/upload/abc", "")
+		assert.Equal(t, http.StatusNoContent, result.Code)
+	})
+	t.Run("last used", func(t *testing.T) {
+		m, err := entity.FindApiToken(conf.Db(), readToken)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, m.LastUsed)
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		result := performTokenRequest(app, "xxx"+readToken, "GET", "/api/v1/photos?count=1", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+	t.Run("revoked", func(t *testing.T) {
+		m, token := newToken(entity.ScopeFull)
+
+		result := performTokenRequest(app, token, "GET", "/api/v1/photos?count=1", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = performTokenRequest(app, token, "DELETE", fmt.Sprintf("/api/v1/tokens/%d", m.ID), "")
+		assert.Equal(t, http.StatusOK, result.Code)
+
+		result = performTokenRequest(app, token, "GET", "/api/v1/photos?count=1", "")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
+
+func TestCreateApiToken(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateApiToken(router, conf)
+		GetApiTokens(router, conf)
+
+		result := performAdminRequest(app, "POST", "/api/v1/tokens", `{"label": "Upload script", "scope": "upload"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "\"Scope\":\"upload\"")
+		assert.NotContains(t, result.Body.String(), "TokenHash")
+
+		result = performAdminRequest(app, "GET", "/api/v1/tokens", "")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "Upload script")
+		assert.NotContains(t, result.Body.String(), "\"token\"")
+	})
+	t.Run("invalid scope", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateApiToken(router, conf)
+		result := performAdminRequest(app, "POST", "/api/v1/tokens", `{"label": "Upload script", "scope": "write"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateApiToken(router, conf)
+		result := performRoleRequest(app, entity.RoleViewer, "POST", "/api/v1/tokens", `{"label": "Upload script"}`)
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
@@ -19,11 +19,16 @@ import (
 // POST /api/v1/upload/:path
 func Upload(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/upload/:path", func(c *gin.Context) {
-		if conf.ReadOnly() || !conf.Settings().Features.Upload {
+		if conf.ReadOnly() {
 			c.AbortWithStatusJSON(http.StatusForbidden, ErrReadOnly)
 			return
 		}
 
+		if !conf.Settings().Features.Upload {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrFeatureDisabled)
+			return
+		}
+
 		if Unauthorized(c, conf) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
 			return
@@ -65,44 +70,54 @@ func Upload(router *gin.RouterGroup, conf *config.Config) {
 			uploads = append(uploads, filename)
 		}
 
-		if !conf.UploadNSFW() {
-			nd := service.NsfwDetector()
+		if containsNSFW(conf, uploads) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrUploadNSFW)
+			return
+		}
 
-			containsNSFW := false
+		elapsed := time.Since(start)
 
-			for _, filename := range uploads {
-				labels, err := nd.File(filename)
+		log.Infof("%d files uploaded in %s", uploaded, elapsed)
 
-				if err != nil {
-					log.Debug(err)
-					continue
-				}
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%d files uploaded in %s", uploaded, elapsed)})
+	})
+}
 
-				if labels.IsSafe() {
-					continue
-				}
+// containsNSFW returns true and deletes the uploaded files if any of them might be offensive,
+// unless uploading NSFW content is allowed.
+func containsNSFW(conf *config.Config, uploads []string) bool {
+	if conf.UploadNSFW() {
+		return false
+	}
 
-				log.Infof("nsfw: \"%s\" might be offensive", filename)
+	nd := service.NsfwDetector()
 
-				containsNSFW = true
-			}
+	result := false
 
-			if containsNSFW {
-				for _, filename := range uploads {
-					if err := os.Remove(filename); err != nil {
-						log.Errorf("nsfw: could not delete \"%s\"", filename)
-					}
-				}
+	for _, filename := range uploads {
+		labels, err := nd.File(filename)
 
-				c.AbortWithStatusJSON(http.StatusForbidden, ErrUploadNSFW)
-				return
-			}
+		if err != nil {
+			log.Debug(err)
+			continue
 		}
 
-		elapsed := time.Since(start)
+		if labels.IsSafe() {
+			continue
+		}
 
-		log.Infof("%d files uploaded in %s", uploaded, elapsed)
+		log.Infof("nsfw: \"%s\" might be offensive", filename)
 
-		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%d files uploaded in %s", uploaded, elapsed)})
-	})
+		result = true
+	}
+
+	if result {
+		for _, filename := range uploads {
+			if err := os.Remove(filename); err != nil {
+				log.Errorf("nsfw: could not delete \"%s\"", filename)
+			}
+		}
+	}
+
+	return result
 }
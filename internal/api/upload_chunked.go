@@ -0,0 +1,220 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/internal/upload"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// Headers of chunked uploads, the offset is the number of bytes received so far.
+const (
+	uploadOffsetHeader = "Upload-Offset"
+	uploadLengthHeader = "Upload-Length"
+)
+
+// uploadAllowed returns true if chunked uploads are possible, otherwise it aborts the request.
+func uploadAllowed(c *gin.Context, conf *config.Config) bool {
+	if Unauthorized(c, conf) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+		return false
+	}
+
+	if conf.ReadOnly() {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrReadOnly)
+		return false
+	}
+
+	if !conf.Settings().Features.Upload {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrFeatureDisabled)
+		return false
+	}
+
+	return true
+}
+
+// uploadHeaders sets the headers containing the size and offset of an upload.
+func uploadHeaders(c *gin.Context, u upload.Session) {
+	c.Header(uploadOffsetHeader, strconv.FormatInt(u.Offset, 10))
+	c.Header(uploadLengthHeader, strconv.FormatInt(u.Size, 10))
+	c.Header("Cache-Control", "no-store")
+}
+
+// uploadError aborts the request with a status matching the upload error.
+func uploadError(c *gin.Context, err error) {
+	switch err {
+	case upload.ErrNotFound:
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrUploadNotFound)
+	case upload.ErrBusy:
+		c.AbortWithStatusJSON(http.StatusConflict, ErrUploadBusy)
+	case upload.ErrOffset:
+		c.AbortWithStatusJSON(http.StatusConflict, ErrUploadOffset)
+	case upload.ErrIncomplete:
+		c.AbortWithStatusJSON(http.StatusConflict, ErrUploadIncomplete)
+	case upload.ErrTooLarge:
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrUploadTooLarge)
+	case upload.ErrInvalidName, upload.ErrInvalidSize:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+	default:
+		log.Errorf("upload: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+	}
+}
+
+// POST /api/v1/uploads
+//
+// Creates a chunked upload of a file, the request body must contain its name and size. Only the user
+// who created an upload may resume, complete or cancel it.
+func CreateUpload(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/uploads", func(c *gin.Context) {
+		if !uploadAllowed(c, conf) {
+			return
+		}
+
+		var f form.UploadOptions
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		u, err := service.Upload().Create(SessionViewer(c, conf).ID, f.Name, f.Path, f.Size)
+
+		if err != nil {
+			uploadError(c, err)
+			return
+		}
+
+		log.Debugf("upload: created \"%s\" for \"%s\"", u.ID, u.Name)
+
+		uploadHeaders(c, u)
+		c.Header("Location", path.Join(c.Request.URL.Path, u.ID))
+		c.JSON(http.StatusCreated, u)
+	})
+}
+
+// HEAD /api/v1/uploads/:id
+//
+// Returns the offset of a chunked upload, so that interrupted uploads can be resumed.
+func UploadOffset(router *gin.RouterGroup, conf *config.Config) {
+	router.HEAD("/uploads/:id", func(c *gin.Context) {
+		if !uploadAllowed(c, conf) {
+			return
+		}
+
+		u, err := service.Upload().Find(SessionViewer(c, conf).ID, c.Param("id"))
+
+		if err != nil {
+			uploadError(c, err)
+			return
+		}
+
+		uploadHeaders(c, u)
+		c.Status(http.StatusOK)
+	})
+}
+
+// PATCH /api/v1/uploads/:id
+//
+// Appends the request body to a chunked upload. The Upload-Offset header must match the number of bytes
+// received so far, chunks for the same upload can not be sent concurrently.
+func AppendUpload(router *gin.RouterGroup, conf *config.Config) {
+	router.PATCH("/uploads/:id", func(c *gin.Context) {
+		if !uploadAllowed(c, conf) {
+			return
+		}
+
+		offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+
+		if err != nil || offset < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid upload offset"})
+			return
+		}
+
+		u, err := service.Upload().Append(SessionViewer(c, conf).ID, c.Param("id"), offset, c.Request.Body)
+
+		if err != nil {
+			if u.ID != "" {
+				uploadHeaders(c, u)
+			}
+
+			uploadError(c, err)
+			return
+		}
+
+		uploadHeaders(c, u)
+		c.JSON(http.StatusOK, u)
+	})
+}
+
+// DELETE /api/v1/uploads/:id
+//
+// Cancels a chunked upload.
+func DeleteUpload(router *gin.RouterGroup, conf *config.Config) {
+	router.DELETE("/uploads/:id", func(c *gin.Context) {
+		if !uploadAllowed(c, conf) {
+			return
+		}
+
+		if err := service.Upload().Delete(SessionViewer(c, conf).ID, c.Param("id")); err != nil {
+			uploadError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "upload canceled"})
+	})
+}
+
+// POST /api/v1/uploads/:id/complete
+//
+// Completes a chunked upload and imports the file.
+func CompleteUpload(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/uploads/:id/complete", func(c *gin.Context) {
+		if !uploadAllowed(c, conf) {
+			return
+		}
+
+		if !conf.Settings().Features.Import {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrFeatureDisabled)
+			return
+		}
+
+		var f form.ImportOptions
+
+		if c.Request.ContentLength > 0 {
+			if err := c.BindJSON(&f); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+				return
+			}
+		}
+
+		fileName, err := service.Upload().Complete(SessionViewer(c, conf).ID, c.Param("id"), filepath.Join(conf.ImportPath(), "upload"))
+
+		if err != nil {
+			uploadError(c, err)
+			return
+		}
+
+		log.Infof("upload: received \"%s\"", filepath.Base(fileName))
+
+		if containsNSFW(conf, []string{fileName}) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrUploadNSFW)
+			return
+		}
+
+		// Importing runs in this request, so it must not wait for itself.
+		ReleaseActivity(c)
+
+		elapsed := importPath(conf, filepath.Dir(fileName), f)
+
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("import completed in %d s", elapsed)})
+	})
+}
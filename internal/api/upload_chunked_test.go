@@ -0,0 +1,179 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/internal/upload"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+// performChunkRequest sends a chunk of an upload starting at offset.
+func performChunkRequest(r http.Handler, id string, offset int, chunk []byte) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PATCH", "/api/v1/uploads/"+id, strings.NewReader(string(chunk)))
+	req.Header.Set("Upload-Offset", strconv.Itoa(offset))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestChunkedUpload(t *testing.T) {
+	t.Run("interrupted and resumed", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		service.SetConfig(conf)
+
+		CreateUpload(router, conf)
+		UploadOffset(router, conf)
+		AppendUpload(router, conf)
+		DeleteUpload(router, conf)
+
+		data := make([]byte, 50000)
+		rand.New(rand.NewSource(1)).Read(data)
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/uploads", `{"name": "video.mp4", "size": 50000}`)
+		assert.Equal(t, http.StatusCreated, result.Code)
+
+		var u upload.Session
+
+		if err := json.Unmarshal(result.Body.Bytes(), &u); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "video.mp4", u.Name)
+		assert.Equal(t, "0", result.Header().Get("Upload-Offset"))
+
+		result = performChunkRequest(app, u.ID, 0, data[:20000])
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "20000", result.Header().Get("Upload-Offset"))
+
+		// The client resends a chunk that was already received, e.g. after a timeout.
+		result = performChunkRequest(app, u.ID, 0, data[:20000])
+		assert.Equal(t, http.StatusConflict, result.Code)
+
+		// The client asks for the offset to resume the upload.
+		result = PerformRequest(app, "HEAD", "/api/v1/uploads/"+u.ID)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "20000", result.Header().Get("Upload-Offset"))
+		assert.Equal(t, "50000", result.Header().Get("Upload-Length"))
+
+		offset, err := strconv.Atoi(result.Header().Get("Upload-Offset"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result = performChunkRequest(app, u.ID, offset, data[offset:])
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "50000", result.Header().Get("Upload-Offset"))
+
+		fileName, err := service.Upload().Complete(0, u.ID, conf.ImportPath()+"/upload")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(filepath.Dir(fileName))
+
+		expected := sha1.Sum(data)
+		assert.Equal(t, hex.EncodeToString(expected[:]), fs.Hash(fileName))
+	})
+	t.Run("other user", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		service.SetConfig(conf)
+
+		CreateUpload(router, conf)
+		UploadOffset(router, conf)
+		DeleteUpload(router, conf)
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/uploads", `{"name": "video.mp4", "size": 500}`)
+		assert.Equal(t, http.StatusCreated, result.Code)
+
+		var u upload.Session
+
+		if err := json.Unmarshal(result.Body.Bytes(), &u); err != nil {
+			t.Fatal(err)
+		}
+
+		// Uploads of other users are not found.
+		result = performRoleRequest(app, entity.RoleUploader, "HEAD", "/api/v1/uploads/"+u.ID, "")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = performRoleRequest(app, entity.RoleUploader, "DELETE", "/api/v1/uploads/"+u.ID, "")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = PerformRequest(app, "DELETE", "/api/v1/uploads/"+u.ID)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("too large", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		service.SetConfig(conf)
+
+		CreateUpload(router, conf)
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/uploads", `{"name": "video.mp4", "size": 100000000000}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.Code)
+	})
+	t.Run("invalid offset", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		service.SetConfig(conf)
+
+		AppendUpload(router, conf)
+
+		req, _ := http.NewRequest("PATCH", "/api/v1/uploads/uqxetse3cy5eo9z2", strings.NewReader("foo"))
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("not found", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		service.SetConfig(conf)
+
+		UploadOffset(router, conf)
+		DeleteUpload(router, conf)
+		CompleteUpload(router, conf)
+
+		result := PerformRequest(app, "HEAD", "/api/v1/uploads/uqxetse3cy5eo9z2")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = PerformRequest(app, "DELETE", "/api/v1/uploads/uqxetse3cy5eo9z2")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = PerformRequest(app, "POST", "/api/v1/uploads/uqxetse3cy5eo9z2/complete")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("incomplete", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		service.SetConfig(conf)
+
+		CreateUpload(router, conf)
+		DeleteUpload(router, conf)
+		CompleteUpload(router, conf)
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/uploads", `{"name": "video.mp4", "size": 1000}`)
+		assert.Equal(t, http.StatusCreated, result.Code)
+
+		var u upload.Session
+
+		if err := json.Unmarshal(result.Body.Bytes(), &u); err != nil {
+			t.Fatal(err)
+		}
+
+		result = PerformRequest(app, "POST", "/api/v1/uploads/"+u.ID+"/complete")
+		assert.Equal(t, http.StatusConflict, result.Code)
+
+		result = PerformRequest(app, "DELETE", "/api/v1/uploads/"+u.ID)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// GET /api/v1/videos/:hash/:type
+//
+// Parameters:
+//   hash: string The file hash as returned by the search API
+//   type: string Video format, currently only "avc" for H.264
+func GetVideo(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/videos/:hash/:type", func(c *gin.Context) {
+		link, ok := downloadAccess(c, conf)
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		fileHash := c.Param("hash")
+
+		if c.Param("type") != string(fs.TypeAvc) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrVideoNotFound)
+			return
+		}
+
+		db := conf.Db()
+		f, err := downloadFile(c, conf, link, fileHash)
+
+		if err != nil || !f.FileVideo {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrVideoNotFound)
+			return
+		}
+
+		fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
+
+		if !fs.FileExists(fileName) {
+			log.Errorf("video: could not find original for %s", fileName)
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrVideoNotFound)
+
+			// Set missing flag so that the file doesn't show up in search results anymore
+			f.FileMissing = true
+			db.Save(&f)
+			return
+		}
+
+		video, err := photoprism.NewMediaFile(fileName)
+
+		if err != nil {
+			log.Errorf("video: %s", err)
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrVideoNotFound)
+			return
+		}
+
+		// Transcodes are created on demand if they don't exist yet, concurrent requests wait for the same result.
+		avc, err := service.Convert().ToAvc(video)
+
+		if err != nil {
+			log.Errorf("video: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrTranscodeFailed)
+			return
+		}
+
+		if avc.HasFileType(fs.TypeAvc) {
+			c.Header("Content-Type", "video/mp4")
+		}
+
+		// Range requests are supported for seeking.
+		c.File(avc.FileName())
+	})
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,11 +22,35 @@ var wsConnection = websocket.Upgrader{
 }
 var wsTimeout = 90 * time.Second
 
+// wsTopics are the event topics sent to websocket clients unless they subscribe to specific topics.
+var wsTopics = []string{"log.*", "notify.*", "index.*", "upload.*", "import.*", "config.*", "count.*", "photos.*", "albums.*", "labels.*", "sync.*", "thumbs.*"}
+
+// wsBuffer is the number of events buffered per client, further events are dropped for slow clients.
+var wsBuffer = 100
+
+var wsStream *event.Stream
+var wsStreamOnce sync.Once
+
+// wsEvents returns the event stream shared by all websocket clients.
+func wsEvents(conf *config.Config) *event.Stream {
+	wsStreamOnce.Do(func() {
+		wsStream = event.NewStream(event.SharedHub(), conf.EventBuffer(), wsTopics...)
+	})
+
+	return wsStream
+}
+
+// clientInfo is sent by clients to authenticate, and to subscribe to topic patterns like "index.*".
+// Events published after the sequence number since are replayed when subscribing, if still available.
+// All recent events are replayed if the epoch doesn't match, e.g. because the server was restarted.
 type clientInfo struct {
-	SessionToken string `json:"session"`
-	JsHash       string `json:"js"`
-	CssHash      string `json:"css"`
-	Version      string `json:"version"`
+	SessionToken string   `json:"session"`
+	JsHash       string   `json:"js"`
+	CssHash      string   `json:"css"`
+	Version      string   `json:"version"`
+	Subscribe    []string `json:"subscribe"`
+	Epoch        string   `json:"epoch"`
+	Since        uint64   `json:"since"`
 }
 
 var wsAuth = struct {
@@ -33,7 +58,7 @@ var wsAuth = struct {
 	mutex         sync.RWMutex
 }{authenticated: make(map[string]bool)}
 
-func wsReader(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, conf *config.Config) {
+func wsReader(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, client *event.Client, replay chan<- []event.Event, conf *config.Config) {
 	defer ws.Close()
 
 	ws.SetReadLimit(512)
@@ -69,19 +94,66 @@ func wsReader(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, conf *c
 				}
 				writeMutex.Unlock()
 			}
+
+			wsAuth.mutex.RLock()
+			auth := wsAuth.authenticated[connId]
+			wsAuth.mutex.RUnlock()
+
+			if auth && len(info.Subscribe) > 0 {
+				log.Debugf("websocket: subscribed to %s", strings.Join(info.Subscribe, ", "))
+				select {
+				case replay <- wsEvents(conf).Resubscribe(client, info.Epoch, info.Since, info.Subscribe...):
+				default:
+					log.Warn("websocket: replay skipped, previous replay still pending")
+				}
+			}
 		}
 	}
 }
 
-func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string) {
+func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, client *event.Client, replay <-chan []event.Event, conf *config.Config) {
 	pingTicker := time.NewTicker(15 * time.Second)
-	s := event.Subscribe("log.*", "notify.*", "index.*", "upload.*", "import.*", "config.*", "count.*", "photos.*", "albums.*", "labels.*", "sync.*")
+
+	// Events with a sequence number up to lastSeq were sent already, e.g. when replayed.
+	var lastSeq uint64
+
+	write := func(ev event.Event) bool {
+		if ev.Seq <= lastSeq {
+			return true
+		}
+
+		wsAuth.mutex.RLock()
+		auth := wsAuth.authenticated[connId]
+		wsAuth.mutex.RUnlock()
+
+		if !auth {
+			return true
+		}
+
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+
+		ws.SetWriteDeadline(time.Now().Add(30 * time.Second))
+
+		if err := ws.WriteJSON(ev); err != nil {
+			log.Debug(err)
+			return false
+		}
+
+		lastSeq = ev.Seq
+
+		return true
+	}
 
 	defer func() {
 		pingTicker.Stop()
-		event.Unsubscribe(s)
+		wsEvents(conf).Unsubscribe(client)
 		ws.Close()
 
+		if n := client.Dropped(); n > 0 {
+			log.Debugf("websocket: dropped %d events for slow client", n)
+		}
+
 		wsAuth.mutex.Lock()
 		wsAuth.authenticated[connId] = false
 		wsAuth.mutex.Unlock()
@@ -94,27 +166,24 @@ func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string) {
 			if err := ws.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 				return
 			}
-		case msg := <-s.Receiver:
-			wsAuth.mutex.RLock()
-			auth := wsAuth.authenticated[connId]
-			wsAuth.mutex.RUnlock()
-
-			if auth {
-				writeMutex.Lock()
-				ws.SetWriteDeadline(time.Now().Add(30 * time.Second))
-
-				if err := ws.WriteJSON(gin.H{"event": msg.Name, "data": msg.Fields}); err != nil {
-					writeMutex.Unlock()
-					log.Debug(err)
+		case events := <-replay:
+			for _, ev := range events {
+				if !write(ev) {
 					return
 				}
-				writeMutex.Unlock()
+			}
+		case ev := <-client.C:
+			if !write(ev) {
+				return
 			}
 		}
 	}
 }
 
 // GET /api/v1/ws
+//
+// Sends events as {"epoch": "...", "seq": 1, "event": "photos.updated", "data": {...}}, clients may send
+// {"subscribe": ["index.*"], "epoch": "...", "since": 1} to only receive matching events.
 func Websocket(router *gin.RouterGroup, conf *config.Config) {
 	if router == nil {
 		log.Error("websocket: router is nil")
@@ -150,8 +219,11 @@ func Websocket(router *gin.RouterGroup, conf *config.Config) {
 
 		log.Debug("websocket: connected")
 
-		go wsWriter(ws, &writeMutex, connId)
+		client := wsEvents(conf).Subscribe(wsBuffer, wsTopics...)
+		replay := make(chan []event.Event, 1)
+
+		go wsWriter(ws, &writeMutex, connId, client, replay, conf)
 
-		wsReader(ws, &writeMutex, connId, conf)
+		wsReader(ws, &writeMutex, connId, client, replay, conf)
 	})
 }
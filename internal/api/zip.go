@@ -47,12 +47,15 @@ func CreateZip(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		q := query.New(conf.Db())
+		q := query.New(conf.Db()).As(SessionViewer(c, conf))
 		files, err := q.FilesByUUID(f.Photos, 1000, 0)
 
 		if err != nil {
 			c.AbortWithStatusJSON(404, gin.H{"error": err.Error()})
 			return
+		} else if len(files) == 0 {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrFileNotFound)
+			return
 		}
 
 		zipPath := path.Join(conf.TempPath(), "zip")
@@ -81,7 +84,7 @@ func CreateZip(router *gin.RouterGroup, conf *config.Config) {
 		defer zipWriter.Close()
 
 		for _, f := range files {
-			fileName := path.Join(conf.OriginalsPath(), f.FileName)
+			fileName := conf.OriginalsFileName(f.FileRoot, f.FileName)
 			fileAlias := f.ShareFileName()
 
 			if fs.FileExists(fileName) {
@@ -109,6 +112,11 @@ func CreateZip(router *gin.RouterGroup, conf *config.Config) {
 // GET /api/v1/zip/:filename
 func DownloadZip(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/zip/:filename", func(c *gin.Context) {
+		if !DownloadAuthorized(c, conf) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
 		zipBaseName := filepath.Base(c.Param("filename"))
 		zipPath := path.Join(conf.TempPath(), "zip")
 		zipFileName := path.Join(zipPath, zipBaseName)
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateZip(t *testing.T) {
+	t.Run("no photos selected", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateZip(router, conf)
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/zip", `{"photos": []}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("private photo", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		CreateZip(router, conf)
+
+		fileHash, cleanup := createPrivatePhoto(t, conf)
+		defer cleanup()
+
+		file, err := query.New(conf.Db()).FileByHash(fileHash)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := `{"photos": ["` + file.PhotoUUID + `"]}`
+
+		result := PerformRequestWithBody(app, "POST", "/api/v1/zip", body)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+
+		result = performAdminRequest(app, "POST", "/api/v1/zip", body)
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
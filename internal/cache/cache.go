@@ -0,0 +1,102 @@
+/*
+Package cache provides a small key-value cache abstraction with expiration.
+
+By default, values are kept in process memory using go-cache. Multiple instances can share
+a Redis server instead, values are serialized explicitly in this case, see Encoded.
+
+Additional information can be found in our Developer Guide:
+
+https://github.com/photoprism/photoprism/wiki
+*/
+package cache
+
+import (
+	"reflect"
+	"time"
+
+	gc "github.com/patrickmn/go-cache"
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+var log = event.Log
+
+// Supported cache drivers.
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// Expiration values with a special meaning, same as in go-cache.
+const (
+	DefaultExpiration = gc.DefaultExpiration
+	NoExpiration      = gc.NoExpiration
+)
+
+// Cache stores values with an expiration time, *gc.Cache satisfies this interface. Values
+// should be read with Load, so that serialized values from shared caches are decoded.
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration)
+	Delete(key string)
+}
+
+// New returns a new cache for the given driver. An in-memory cache is returned if the driver is
+// empty or if Redis is unreachable, so that a missing cache server doesn't prevent startup.
+func New(driver, dsn string, ttl, cleanup time.Duration) Cache {
+	switch driver {
+	case "", DriverMemory:
+		return gc.New(ttl, cleanup)
+	case DriverRedis:
+		r, err := NewRedis(dsn, ttl)
+
+		if err != nil {
+			log.Warnf("cache: %s, using in-memory cache", err)
+			return gc.New(ttl, cleanup)
+		}
+
+		log.Infof("cache: connected to redis at %s", r.addr)
+
+		return r
+	default:
+		log.Errorf("cache: unknown driver %s, using in-memory cache", driver)
+		return gc.New(ttl, cleanup)
+	}
+}
+
+// Load copies the cached value for key into target, which must be a pointer. Values from the memory
+// cache are assigned as they are, serialized values are decoded. Returns false if there is no value
+// or if it doesn't match the target type.
+func Load(c Cache, key string, target interface{}) bool {
+	v, ok := c.Get(key)
+
+	if !ok {
+		return false
+	}
+
+	if data, ok := v.(Encoded); ok {
+		if err := data.Decode(target); err != nil {
+			log.Warnf("cache: can't decode %s (%s)", key, err)
+			return false
+		}
+
+		return true
+	}
+
+	dst := reflect.ValueOf(target)
+
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		log.Errorf("cache: target for %s must be a pointer", key)
+		return false
+	}
+
+	val := reflect.ValueOf(v)
+
+	if !val.IsValid() || !val.Type().AssignableTo(dst.Elem().Type()) {
+		log.Warnf("cache: unexpected value type %T for %s", v, key)
+		return false
+	}
+
+	dst.Elem().Set(val)
+
+	return true
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	gc "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		assert.IsType(t, &gc.Cache{}, New("", "", time.Hour, time.Minute))
+		assert.IsType(t, &gc.Cache{}, New(DriverMemory, "", time.Hour, time.Minute))
+	})
+	t.Run("redis", func(t *testing.T) {
+		srv := newTestRedis(t, "")
+
+		defer srv.Close()
+
+		c := New(DriverRedis, srv.Addr(), time.Hour, time.Minute)
+
+		if assert.IsType(t, &Redis{}, c) {
+			c.(*Redis).Close()
+		}
+	})
+	t.Run("redis unreachable", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		addr := l.Addr().String()
+		l.Close()
+
+		c := New(DriverRedis, addr, time.Hour, time.Minute)
+
+		assert.IsType(t, &gc.Cache{}, c)
+
+		c.Set("foo", "bar", DefaultExpiration)
+
+		var value string
+
+		assert.True(t, Load(c, "foo", &value))
+		assert.Equal(t, "bar", value)
+	})
+	t.Run("unknown driver", func(t *testing.T) {
+		assert.IsType(t, &gc.Cache{}, New("memcache", "", time.Hour, time.Minute))
+	})
+}
+
+func TestLoad(t *testing.T) {
+	type item struct {
+		Name string
+	}
+
+	c := gc.New(time.Hour, time.Minute)
+
+	t.Run("pointer", func(t *testing.T) {
+		stored := &item{Name: "foo"}
+		c.Set("item", stored, DefaultExpiration)
+
+		var result *item
+
+		assert.True(t, Load(c, "item", &result))
+
+		// Values in memory are not copied.
+		assert.Same(t, stored, result)
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		var result string
+
+		assert.False(t, Load(c, "item", &result))
+	})
+	t.Run("not found", func(t *testing.T) {
+		var result *item
+
+		assert.False(t, Load(c, "missing", &result))
+		assert.Nil(t, result)
+	})
+	t.Run("encoded", func(t *testing.T) {
+		data, err := Encode(item{Name: "bar"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c.Set("encoded", data, DefaultExpiration)
+
+		var result item
+
+		assert.True(t, Load(c, "encoded", &result))
+		assert.Equal(t, "bar", result.Name)
+	})
+}
+
+func TestEncoded_Decode(t *testing.T) {
+	t.Run("bytes", func(t *testing.T) {
+		data, _ := Encode([]byte("jpeg"))
+
+		var result []byte
+
+		assert.NoError(t, data.Decode(&result))
+		assert.Equal(t, "jpeg", string(result))
+
+		var s string
+
+		assert.EqualError(t, data.Decode(&s), "can't decode bytes into *string")
+	})
+	t.Run("json", func(t *testing.T) {
+		data, _ := Encode(map[string]interface{}{"ID": 5})
+
+		var result map[string]interface{}
+
+		assert.NoError(t, data.Decode(&result))
+
+		// Numbers are float64 after decoding JSON.
+		assert.Equal(t, float64(5), result["ID"])
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var result string
+
+		assert.Error(t, Encoded{}.Decode(&result))
+		assert.EqualError(t, Encoded("x").Decode(&result), "unknown format 'x'")
+	})
+}
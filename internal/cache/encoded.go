@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Serialization formats, stored as first byte of an encoded value.
+const (
+	formatRaw  = 'r'
+	formatJSON = 'j'
+)
+
+// Encoded is a serialized cache value. Byte slices are stored as they are, all other values as JSON,
+// so that they can be shared between instances without relying on in-memory pointers.
+type Encoded []byte
+
+// Encode serializes a value for a shared cache.
+func Encode(v interface{}) (Encoded, error) {
+	if b, ok := v.([]byte); ok {
+		return append(Encoded{formatRaw}, b...), nil
+	}
+
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(Encoded{formatJSON}, b...), nil
+}
+
+// Decode deserializes the value into target, which must be a pointer.
+func (e Encoded) Decode(target interface{}) error {
+	if len(e) == 0 {
+		return errors.New("empty value")
+	}
+
+	switch e[0] {
+	case formatRaw:
+		b, ok := target.(*[]byte)
+
+		if !ok {
+			return fmt.Errorf("can't decode bytes into %T", target)
+		}
+
+		*b = append([]byte(nil), e[1:]...)
+
+		return nil
+	case formatJSON:
+		return json.Unmarshal(e[1:], target)
+	default:
+		return fmt.Errorf("unknown format %q", e[0])
+	}
+}
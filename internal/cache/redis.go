@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// KeyPrefix is added to all Redis keys, so that a server can be shared with other applications.
+const KeyPrefix = "photoprism:"
+
+// Redis connection pool settings, idle connections are checked before they are used again.
+var (
+	RedisMaxIdle     = 8
+	RedisIdleTimeout = 5 * time.Minute
+	RedisTimeout     = 5 * time.Second
+)
+
+// Redis is a cache backed by a Redis server that can be shared by multiple instances.
+type Redis struct {
+	pool *redis.Pool
+	addr string
+	ttl  time.Duration
+}
+
+// redisOptions contains the server address, password and database parsed from a data source name.
+type redisOptions struct {
+	addr     string
+	password string
+	db       int
+}
+
+// NewRedis connects to a Redis server and returns a new cache. The data source name is either
+// an address like "localhost:6379" or an URL like "redis://:password@localhost:6379/0".
+// Values without expiration time expire after ttl, unless ttl is NoExpiration.
+func NewRedis(dsn string, ttl time.Duration) (*Redis, error) {
+	opt, err := parseRedisDSN(dsn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &redis.Pool{
+		MaxIdle:     RedisMaxIdle,
+		IdleTimeout: RedisIdleTimeout,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", opt.addr,
+				redis.DialPassword(opt.password),
+				redis.DialDatabase(opt.db),
+				redis.DialConnectTimeout(RedisTimeout),
+				redis.DialReadTimeout(RedisTimeout),
+				redis.DialWriteTimeout(RedisTimeout),
+			)
+		},
+		TestOnBorrow: func(c redis.Conn, idle time.Time) error {
+			if time.Since(idle) < time.Minute {
+				return nil
+			}
+
+			_, err := c.Do("PING")
+
+			return err
+		},
+	}
+
+	r := &Redis{pool: pool, addr: opt.addr, ttl: ttl}
+
+	if _, err := r.do("PING"); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("redis at %s is unreachable (%s)", opt.addr, err)
+	}
+
+	return r, nil
+}
+
+// parseRedisDSN returns the connection options for a data source name.
+func parseRedisDSN(dsn string) (opt redisOptions, err error) {
+	opt.addr = "localhost:6379"
+
+	if dsn == "" {
+		return opt, nil
+	} else if !strings.Contains(dsn, "://") {
+		opt.addr = dsn
+		return opt, nil
+	}
+
+	u, err := url.Parse(dsn)
+
+	if err != nil {
+		return opt, fmt.Errorf("invalid redis dsn (%s)", err)
+	} else if u.Scheme != "redis" {
+		return opt, fmt.Errorf("invalid redis dsn, unsupported scheme %s", u.Scheme)
+	}
+
+	if u.Host != "" {
+		opt.addr = u.Host
+	}
+
+	if !strings.Contains(opt.addr, ":") {
+		opt.addr += ":6379"
+	}
+
+	if u.User != nil {
+		opt.password, _ = u.User.Password()
+	}
+
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if opt.db, err = strconv.Atoi(db); err != nil {
+			return opt, fmt.Errorf("invalid redis database %s", db)
+		}
+	}
+
+	return opt, nil
+}
+
+// do runs a command on a connection from the pool. Broken connections are discarded, e.g. after
+// the server was restarted, and the command is sent again on another connection. This is safe as
+// all commands used by the cache are idempotent.
+func (r *Redis) do(cmd string, args ...interface{}) (interface{}, error) {
+	for i := 0; ; i++ {
+		conn := r.pool.Get()
+
+		if err := conn.Err(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		reply, err := conn.Do(cmd, args...)
+		broken := conn.Err() != nil
+		conn.Close()
+
+		if !broken || i >= r.pool.MaxIdle {
+			return reply, err
+		}
+	}
+}
+
+// Get returns the encoded value for key, errors are logged and reported as cache miss.
+func (r *Redis) Get(key string) (interface{}, bool) {
+	b, err := redis.Bytes(r.do("GET", KeyPrefix+key))
+
+	if err == redis.ErrNil {
+		return nil, false
+	} else if err != nil {
+		log.Debugf("cache: %s", err)
+		return nil, false
+	}
+
+	return Encoded(b), true
+}
+
+// Set serializes and stores a value, a ttl of zero means the default expiration time.
+func (r *Redis) Set(key string, value interface{}, ttl time.Duration) {
+	data, err := Encode(value)
+
+	if err != nil {
+		log.Errorf("cache: can't encode %s (%s)", key, err)
+		return
+	}
+
+	if ttl == DefaultExpiration {
+		ttl = r.ttl
+	}
+
+	args := []interface{}{KeyPrefix + key, []byte(data)}
+
+	if ttl > 0 {
+		args = append(args, "PX", int64(ttl/time.Millisecond))
+	}
+
+	if _, err := r.do("SET", args...); err != nil {
+		log.Errorf("cache: %s", err)
+	}
+}
+
+// Delete removes a value from the cache.
+func (r *Redis) Delete(key string) {
+	if _, err := r.do("DEL", KeyPrefix+key); err != nil {
+		log.Errorf("cache: %s", err)
+	}
+}
+
+// Close closes all connections to the server.
+func (r *Redis) Close() error {
+	return r.pool.Close()
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRedis starts an in-process Redis server that requires a password if not empty.
+func newTestRedis(t *testing.T, password string) *miniredis.Miniredis {
+	srv, err := miniredis.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if password != "" {
+		srv.RequireAuth(password)
+	}
+
+	return srv
+}
+
+func TestParseRedisDSN(t *testing.T) {
+	t.Run("address", func(t *testing.T) {
+		opt, err := parseRedisDSN("redis.local:6380")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "redis.local:6380", opt.addr)
+	})
+	t.Run("url", func(t *testing.T) {
+		opt, err := parseRedisDSN("redis://:secret@redis.local/2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "redis.local:6379", opt.addr)
+		assert.Equal(t, "secret", opt.password)
+		assert.Equal(t, 2, opt.db)
+	})
+	t.Run("default", func(t *testing.T) {
+		opt, err := parseRedisDSN("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "localhost:6379", opt.addr)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseRedisDSN("memcache://localhost")
+		assert.EqualError(t, err, "invalid redis dsn, unsupported scheme memcache")
+
+		_, err = parseRedisDSN("redis://localhost/db")
+		assert.EqualError(t, err, "invalid redis database db")
+	})
+}
+
+func TestRedis(t *testing.T) {
+	srv := newTestRedis(t, "secret")
+
+	defer srv.Close()
+
+	r, err := NewRedis(fmt.Sprintf("redis://:secret@%s/1", srv.Addr()), time.Hour)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer r.Close()
+
+	t.Run("bytes", func(t *testing.T) {
+		r.Set("thumb", []byte{0xff, 0xd8, 0x00}, DefaultExpiration)
+
+		var data []byte
+
+		assert.True(t, Load(r, "thumb", &data))
+		assert.Equal(t, []byte{0xff, 0xd8, 0x00}, data)
+	})
+	t.Run("json", func(t *testing.T) {
+		type location struct {
+			ID   string
+			Name string
+		}
+
+		r.Set("places:4799e4a5", location{ID: "4799e4a5", Name: "Berlin"}, NoExpiration)
+
+		var result location
+
+		assert.True(t, Load(r, "places:4799e4a5", &result))
+		assert.Equal(t, "Berlin", result.Name)
+
+		// Keys are prefixed on the server and stored in the selected database.
+		assert.True(t, srv.DB(1).Exists(KeyPrefix+"places:4799e4a5"))
+		assert.False(t, srv.DB(0).Exists(KeyPrefix+"places:4799e4a5"))
+	})
+	t.Run("delete", func(t *testing.T) {
+		r.Set("session:abc", map[string]interface{}{"ID": 1}, DefaultExpiration)
+		r.Delete("session:abc")
+
+		_, ok := r.Get("session:abc")
+
+		assert.False(t, ok)
+	})
+	t.Run("ttl", func(t *testing.T) {
+		r.Set("short", "value", time.Minute)
+		r.Set("default", "value", DefaultExpiration)
+		r.Set("forever", "value", NoExpiration)
+
+		srv.FastForward(59 * time.Second)
+
+		_, ok := r.Get("short")
+		assert.True(t, ok)
+
+		srv.FastForward(time.Second)
+
+		_, ok = r.Get("short")
+		assert.False(t, ok)
+
+		_, ok = r.Get("default")
+		assert.True(t, ok)
+
+		srv.FastForward(time.Hour)
+
+		_, ok = r.Get("default")
+		assert.False(t, ok)
+
+		_, ok = r.Get("forever")
+		assert.True(t, ok)
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				key := fmt.Sprintf("concurrent:%d", i)
+
+				r.Set(key, i, DefaultExpiration)
+
+				var value int
+
+				assert.True(t, Load(r, key, &value))
+				assert.Equal(t, i, value)
+			}(i)
+		}
+
+		wg.Wait()
+	})
+	t.Run("reconnect", func(t *testing.T) {
+		r.Set("reconnect", "value", DefaultExpiration)
+
+		// Pooled connections break while the server is down, commands should reconnect afterwards.
+		srv.Close()
+
+		_, ok := r.Get("reconnect")
+		assert.False(t, ok)
+
+		if err := srv.Restart(); err != nil {
+			t.Fatal(err)
+		}
+
+		var value string
+
+		assert.True(t, Load(r, "reconnect", &value))
+		assert.Equal(t, "value", value)
+	})
+}
+
+func TestNewRedis(t *testing.T) {
+	t.Run("unreachable", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		addr := l.Addr().String()
+		l.Close()
+
+		_, err = NewRedis(addr, time.Hour)
+
+		assert.Error(t, err)
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		srv := newTestRedis(t, "secret")
+
+		defer srv.Close()
+
+		_, err := NewRedis(fmt.Sprintf("redis://:wrong@%s", srv.Addr()), time.Hour)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), fmt.Sprintf("redis at %s is unreachable", srv.Addr()))
+		}
+	})
+}
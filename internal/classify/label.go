@@ -30,6 +30,11 @@ func LocationLabel(name string, uncertainty int, priority int) Label {
 	return label
 }
 
+// PersonLabel returns a new label for a person named in the face regions of the image metadata.
+func PersonLabel(name string) Label {
+	return Label{Name: name, Source: "xmp", Uncertainty: 0, Priority: 0, Categories: []string{"people"}}
+}
+
 // Title returns a formatted label title as string.
 func (l Label) Title() string {
 	return txt.Title(txt.Clip(l.Name, txt.ClipDefault))
@@ -29,3 +29,11 @@ func TestLabel_NewLocationLabel(t *testing.T) {
 		assert.Equal(t, "locationtest", LocLabel.Name)
 	})
 }
+
+func TestPersonLabel(t *testing.T) {
+	label := PersonLabel("Jane Doe")
+	assert.Equal(t, "xmp", label.Source)
+	assert.Equal(t, 0, label.Uncertainty)
+	assert.Equal(t, "Jane Doe", label.Name)
+	assert.Equal(t, []string{"people"}, label.Categories)
+}
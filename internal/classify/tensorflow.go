@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/disintegration/imaging"
 	tf "github.com/tensorflow/tensorflow/tensorflow/go"
@@ -25,6 +26,7 @@ type TensorFlow struct {
 	modelName  string
 	modelTags  []string
 	labels     []string
+	mutex      sync.Mutex
 }
 
 // New returns new TensorFlow instance with Nasnet model.
@@ -132,6 +134,9 @@ func (t *TensorFlow) loadLabels(path string) error {
 }
 
 func (t *TensorFlow) loadModel() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	if t.model != nil {
 		// Already loaded
 		return nil
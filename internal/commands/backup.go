@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/urfave/cli"
+)
+
+// BackupCommand is used to register the backup cli command
+var BackupCommand = cli.Command{
+	Name:  "backup",
+	Usage: "Creates a backup of the index database including albums, labels and settings",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "path, p",
+			Usage: "backup file name, default is photoprism-backup-<date>.zip",
+		},
+	},
+	Action: backupAction,
+}
+
+// backupAction writes all tables to a zip archive
+func backupAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	fileName := ctx.String("path")
+
+	if fileName == "" {
+		fileName = fmt.Sprintf("photoprism-backup-%s.zip", start.Format("20060102-150405"))
+	}
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		f, err := os.Create(fileName)
+
+		if err != nil {
+			return err
+		}
+
+		manifest, err := photoprism.Backup(conf.Db(), f, conf.Version())
+
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			os.Remove(fileName)
+			return err
+		}
+
+		if abs, err := filepath.Abs(fileName); err == nil {
+			fileName = abs
+		}
+
+		log.Infof("backup: saved %d photos to %s in %s", manifest.Tables["photos"], fileName, time.Since(start))
+
+		return nil
+	})
+}
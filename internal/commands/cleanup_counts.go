@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/urfave/cli"
+)
+
+// CleanupCountsCommand is used to register the cleanup-counts cli command
+var CleanupCountsCommand = cli.Command{
+	Name:   "cleanup-counts",
+	Usage:  "Removes label and album entries of deleted photos, labels and albums and recomputes label counts",
+	Action: cleanupCountsAction,
+}
+
+// cleanupCountsAction recomputes label and album counts from the association tables
+func cleanupCountsAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		result, err := query.New(conf.Db()).CleanupCounts()
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%-22s%d\n", "orphaned photo labels", result.PhotoLabels)
+		fmt.Printf("%-22s%d\n", "orphaned album photos", result.PhotoAlbums)
+		fmt.Printf("%-22s%d\n", "updated label counts", result.Labels)
+
+		log.Infof("counts updated in %s", time.Since(start))
+
+		return nil
+	})
+}
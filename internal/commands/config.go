@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
@@ -22,6 +23,15 @@ func configAction(ctx *cli.Context) error {
 	fmt.Printf("NAME                  VALUE\n")
 	fmt.Printf("admin-password        %s\n", conf.AdminPassword())
 	fmt.Printf("webdav-password       %s\n", conf.WebDAVPassword())
+	fmt.Printf("webdav-role           %s\n", conf.WebDAVRole())
+	fmt.Printf("session-timeout       %d\n", conf.SessionTimeout()/time.Second)
+	fmt.Printf("session-maxage        %d\n", conf.SessionMaxAge()/time.Second)
+	fmt.Printf("download-tokens       %t\n", conf.DownloadTokens())
+	fmt.Printf("download-token-ttl    %d\n", conf.DownloadTokenTTL()/time.Second)
+	fmt.Printf("event-buffer          %d\n", conf.EventBuffer())
+	fmt.Printf("log-buffer            %d\n", conf.LogBuffer())
+	fmt.Printf("cache-driver          %s\n", conf.CacheDriver())
+	fmt.Printf("cache-dsn             %s\n", config.MaskDsn(conf.CacheDSN()))
 	fmt.Printf("name                  %s\n", conf.Name())
 	fmt.Printf("url                   %s\n", conf.Url())
 	fmt.Printf("title                 %s\n", conf.Title())
@@ -33,10 +43,23 @@ func configAction(ctx *cli.Context) error {
 	fmt.Printf("copyright             %s\n", conf.Copyright())
 	fmt.Printf("debug                 %t\n", conf.Debug())
 	fmt.Printf("read-only             %t\n", conf.ReadOnly())
+	fmt.Printf("auto-migrate          %t\n", conf.AutoMigrate())
+	fmt.Printf("ignore-patterns       %s\n", strings.Join(conf.IgnorePatterns(), ", "))
+	fmt.Printf("trash-retention-days  %d\n", conf.TrashRetentionDays())
+	fmt.Printf("stack-suffixes        %s\n", strings.Join(conf.StackSuffixes(), ", "))
+	fmt.Printf("stack-primary         %s\n", conf.StackPrimary())
+	fmt.Printf("webhook-url           %s\n", strings.Join(conf.WebhookURLs(), ", "))
+	fmt.Printf("webhook-events        %s\n", strings.Join(conf.WebhookEvents(), ", "))
 	fmt.Printf("public                %t\n", conf.Public())
+	fmt.Printf("public-role           %s\n", conf.PublicRole())
 	fmt.Printf("experimental          %t\n", conf.Experimental())
 	fmt.Printf("workers               %d\n", conf.Workers())
+	fmt.Printf("index-batch-size      %d\n", conf.IndexBatchSize())
+	fmt.Printf("worker-nice           %s\n", conf.WorkerNice())
+	fmt.Printf("worker-nice-load      %.2f\n", conf.WorkerNiceLoad())
 	fmt.Printf("wakeup-interval       %d\n", conf.WakeupInterval()/time.Second)
+	fmt.Printf("auto-index-watch      %t\n", conf.AutoIndexWatch())
+	fmt.Printf("auto-index-settle     %d\n", conf.AutoIndexSettle()/time.Second)
 	fmt.Printf("log-level             %s\n", conf.LogLevel())
 	fmt.Printf("log-filename          %s\n", conf.LogFilename())
 	fmt.Printf("pid-filename          %s\n", conf.PIDFilename())
@@ -45,7 +68,21 @@ func configAction(ctx *cli.Context) error {
 
 	fmt.Printf("assets-path           %s\n", conf.AssetsPath())
 	fmt.Printf("originals-path        %s\n", conf.OriginalsPath())
+
+	for i, root := range conf.OriginalsRoots() {
+		if i == 0 {
+			continue
+		}
+
+		fmt.Printf("originals-root        %s=%s\n", root.Name, root.Path)
+	}
+
+	fmt.Printf("originals-limit       %d\n", conf.OriginalsLimit())
+	fmt.Printf("resolution-limit      %d\n", conf.ResolutionLimit())
 	fmt.Printf("import-path           %s\n", conf.ImportPath())
+	fmt.Printf("import-move           %t\n", conf.ImportMove())
+	fmt.Printf("import-path-pattern   %s\n", conf.ImportPathPattern())
+	fmt.Printf("sidecar-path          %s\n", conf.SidecarPath())
 	fmt.Printf("temp-path             %s\n", conf.TempPath())
 	fmt.Printf("cache-path            %s\n", conf.CachePath())
 	fmt.Printf("thumbnails-path       %s\n", conf.ThumbnailsPath())
@@ -68,20 +105,74 @@ func configAction(ctx *cli.Context) error {
 	fmt.Printf("http-host             %s\n", conf.HttpServerHost())
 	fmt.Printf("http-port             %d\n", conf.HttpServerPort())
 	fmt.Printf("http-mode             %s\n", conf.HttpServerMode())
+	fmt.Printf("http-socket           %s\n", conf.HttpServerSocket())
+	fmt.Printf("http-socket-mode      %#o\n", conf.HttpSocketMode())
+	fmt.Printf("http-cert             %s\n", conf.HttpServerCert())
+	fmt.Printf("http-key              %s\n", conf.HttpServerKey())
 
 	fmt.Printf("sips-bin              %s\n", conf.SipsBin())
 	fmt.Printf("darktable-bin         %s\n", conf.DarktableBin())
+	fmt.Printf("darktable-args        %s\n", strings.Join(conf.DarktableArgs(), " "))
+	fmt.Printf("rawtherapee-bin       %s\n", conf.RawTherapeeBin())
+	fmt.Printf("rawtherapee-args      %s\n", strings.Join(conf.RawTherapeeArgs(), " "))
+	fmt.Printf("raw-converters        %s\n", strings.Join(conf.RawConverters(), ", "))
+	fmt.Printf("raw-presets           %t\n", conf.RawPresets())
 	fmt.Printf("exiftool-bin          %s\n", conf.ExifToolBin())
 	fmt.Printf("heifconvert-bin       %s\n", conf.HeifConvertBin())
+	fmt.Printf("cwebp-bin             %s\n", conf.CWebPBin())
+	fmt.Printf("jpegtran-bin          %s\n", conf.JpegTranBin())
+	fmt.Printf("ffmpeg-bin            %s\n", conf.FFmpegBin())
+	fmt.Printf("ffmpeg-bitrate        %d\n", conf.FFmpegBitrate())
+	fmt.Printf("ffmpeg-timeout        %d\n", conf.FFmpegTimeout()/time.Second)
 
 	fmt.Printf("detect-nsfw           %t\n", conf.DetectNSFW())
 	fmt.Printf("upload-nsfw           %t\n", conf.UploadNSFW())
+	fmt.Printf("upload-limit          %d\n", conf.UploadLimit())
+	fmt.Printf("write-metadata        %t\n", conf.WriteMetadata())
+	fmt.Printf("duplicate-distance    %d\n", conf.DuplicateDistance())
+	fmt.Printf("duplicate-burst       %d\n", conf.DuplicateBurst()/time.Second)
 	fmt.Printf("geocoding-api         %s\n", conf.GeoCodingApi())
+	fmt.Printf("geocoding-ttl         %d\n", conf.GeoCodingTTL()/(24*time.Hour))
+	fmt.Printf("geocoding-endpoint    %s\n", conf.GeoCodingEndpoint())
+	fmt.Printf("geocoding-timeout     %d\n", conf.GeoCodingTimeout()/time.Second)
+	fmt.Printf("places-url            %s\n", conf.PlacesUrl())
+	fmt.Printf("http-proxy            %s\n", conf.HttpProxy())
 	fmt.Printf("thumb-quality         %d\n", conf.ThumbQuality())
 	fmt.Printf("thumb-size            %d\n", conf.ThumbSize())
 	fmt.Printf("thumb-limit           %d\n", conf.ThumbLimit())
+	fmt.Printf("thumb-concurrency     %d\n", conf.ThumbConcurrency())
+	fmt.Printf("thumb-timeout         %d\n", conf.ThumbTimeout()/time.Second)
 	fmt.Printf("thumb-filter          %s\n", conf.ThumbFilter())
 
+	for name, filter := range conf.ThumbFilters() {
+		fmt.Printf("thumb-filter          %s %s\n", name, filter)
+	}
+
+	fmt.Printf("thumb-sharpen         %.1f\n", conf.ThumbSharpen())
+	fmt.Printf("thumb-format          %s\n", conf.ThumbFormat())
+
+	if sizes, err := conf.ThumbSizes(); err != nil {
+		fmt.Printf("thumb-sizes           %s\n", err)
+	} else {
+		var names []string
+
+		for _, s := range sizes {
+			if s.Remove {
+				names = append(names, "-"+s.Name)
+			} else {
+				names = append(names, fmt.Sprintf("%s %dx%d", s.Name, s.Width, s.Height))
+			}
+		}
+
+		fmt.Printf("thumb-sizes           %s\n", strings.Join(names, ", "))
+	}
+
+	fmt.Printf("thumb-use-embedded    %t\n", conf.ThumbUseEmbedded())
+	fmt.Printf("thumb-color-management %t\n", conf.ThumbColorManagement())
+	fmt.Printf("thumb-progressive     %t\n", conf.ThumbProgressive())
+	fmt.Printf("thumb-smart-crop      %t\n", conf.ThumbSmartCrop())
+	fmt.Printf("thumb-copyright       %s\n", conf.ThumbCopyright())
+
 	fmt.Printf("disable-tf            %t\n", conf.DisableTensorFlow())
 	fmt.Printf("disable-settings      %t\n", conf.DisableSettings())
 
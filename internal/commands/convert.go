@@ -23,19 +23,21 @@ func convertAction(ctx *cli.Context) error {
 	service.SetConfig(conf)
 
 	if conf.ReadOnly() {
-		return config.ErrReadOnly
+		log.Infof("read-only mode enabled, converted files are saved in %s", conf.SidecarPath())
 	}
 
 	if err := conf.CreateDirectories(); err != nil {
 		return err
 	}
 
-	log.Infof("converting RAW images in %s to JPEG", conf.OriginalsPath())
-
 	convert := service.Convert()
 
-	if err := convert.Start(conf.OriginalsPath()); err != nil {
-		log.Error(err)
+	for _, root := range conf.OriginalsRoots() {
+		log.Infof("converting RAW images in %s to JPEG and videos to H.264", root.Path)
+
+		if err := convert.Start(root.Path); err != nil {
+			log.Error(err)
+		}
 	}
 
 	elapsed := time.Since(start)
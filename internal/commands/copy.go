@@ -18,6 +18,7 @@ var CopyCommand = cli.Command{
 	Name:    "copy",
 	Aliases: []string{"cp"},
 	Usage:   "Copies files to originals path, converts and indexes them as needed",
+	Flags:   importFlags,
 	Action:  copyAction,
 }
 
@@ -68,6 +69,8 @@ func copyAction(ctx *cli.Context) error {
 
 	imp := service.Import()
 	opt := photoprism.ImportOptionsCopy(sourcePath)
+	opt.Force = ctx.Bool("force")
+	opt.Takeout = ctx.Bool("takeout")
 
 	imp.Start(opt)
 
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/urfave/cli"
+)
+
+// DoctorCommand is used to register the doctor cli command
+var DoctorCommand = cli.Command{
+	Name:   "doctor",
+	Usage:  "Diagnoses common setup problems",
+	Flags:  doctorFlags,
+	Action: doctorAction,
+}
+
+var doctorFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "skip",
+		Usage: fmt.Sprintf("skip checks (%s)", strings.Join(config.Checks, ", ")),
+	},
+}
+
+// doctorAction performs setup checks and exits with a non-zero status if one fails
+func doctorAction(ctx *cli.Context) error {
+	conf := config.NewConfig(ctx)
+
+	results := conf.Doctor(ctx.StringSlice("skip")...)
+
+	fmt.Printf("STATUS  CHECK                   RESULT\n")
+
+	for _, res := range results {
+		fmt.Printf("%-8s%-24s%s\n", res.Status, res.Check+" "+res.Name, res.Message)
+
+		if res.Hint != "" {
+			fmt.Printf("%-32s%s\n", "", res.Hint)
+		}
+	}
+
+	fmt.Printf("\n%d checks, %d warnings, %d failed\n", len(results), results.Warnings(), results.Failed())
+
+	if failed := results.Failed(); failed > 0 {
+		return cli.NewExitError(fmt.Sprintf("doctor: %d checks failed", failed), 1)
+	}
+
+	return nil
+}
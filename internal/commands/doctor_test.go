@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/capture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctorCommand(t *testing.T) {
+	var err error
+
+	ctx := config.CliTestContext()
+
+	output := capture.Output(func() {
+		err = DoctorCommand.Run(ctx)
+	})
+
+	assert.Contains(t, output, "STATUS  CHECK                   RESULT")
+	assert.Contains(t, output, "paths originals")
+	assert.Contains(t, output, "tools exiftool")
+	assert.Contains(t, output, "password admin")
+
+	if err != nil {
+		assert.Contains(t, err.Error(), "checks failed")
+	}
+}
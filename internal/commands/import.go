@@ -18,9 +18,25 @@ var ImportCommand = cli.Command{
 	Name:    "import",
 	Aliases: []string{"mv"},
 	Usage:   "Moves files to originals path, converts and indexes them as needed",
+	Flags:   importFlags,
 	Action:  importAction,
 }
 
+var importFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "force, f",
+		Usage: "import files even if they have been indexed before",
+	},
+	cli.BoolFlag{
+		Name:  "takeout",
+		Usage: "treat all json files as Google Takeout metadata",
+	},
+	cli.BoolFlag{
+		Name:  "resume",
+		Usage: "skip folders completed by the last run if it was interrupted",
+	},
+}
+
 // importAction moves photos to originals path. Default import path is used if no path argument provided
 func importAction(ctx *cli.Context) error {
 	start := time.Now()
@@ -68,6 +84,9 @@ func importAction(ctx *cli.Context) error {
 
 	imp := service.Import()
 	opt := photoprism.ImportOptionsMove(sourcePath)
+	opt.Force = ctx.Bool("force")
+	opt.Takeout = ctx.Bool("takeout")
+	opt.Resume = ctx.Bool("resume")
 
 	imp.Start(opt)
 
@@ -2,11 +2,13 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/urfave/cli"
 )
 
@@ -23,6 +25,26 @@ var indexFlags = []cli.Flag{
 		Name:  "all, a",
 		Usage: "re-index all originals, including unchanged files",
 	},
+	cli.StringFlag{
+		Name:  "root",
+		Usage: "only index an originals root `NAME`, all roots are indexed by default",
+	},
+	cli.StringFlag{
+		Name:  "path",
+		Usage: "only index a sub-folder, relative to the originals root",
+	},
+	cli.BoolFlag{
+		Name:  "retry-errors",
+		Usage: "only re-index files with errors recorded in a previous run",
+	},
+	cli.BoolFlag{
+		Name:  "resume",
+		Usage: "skip folders completed by the last run if it was interrupted",
+	},
+	cli.BoolFlag{
+		Name:  "cleanup",
+		Usage: "remove files from the index that match ignore patterns",
+	},
 }
 
 // indexAction indexes all photos in originals directory (photo library)
@@ -43,7 +65,26 @@ func indexAction(ctx *cli.Context) error {
 	}
 
 	conf.MigrateDb()
-	log.Infof("indexing photos in %s", conf.OriginalsPath())
+
+	if ctx.String("root") != "" || ctx.String("path") != "" {
+		rootPath := conf.OriginalsRootPath(ctx.String("root"))
+
+		if rootPath == "" {
+			return fmt.Errorf("originals root \"%s\" not found", ctx.String("root"))
+		}
+
+		indexPath, err := fs.SubPath(rootPath, ctx.String("path"))
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("indexing photos in %s", indexPath)
+	} else {
+		for _, root := range conf.OriginalsRoots() {
+			log.Infof("indexing photos in %s", root.Path)
+		}
+	}
 
 	if conf.ReadOnly() {
 		log.Infof("read-only mode enabled")
@@ -53,17 +94,31 @@ func indexAction(ctx *cli.Context) error {
 
 	var opt photoprism.IndexOptions
 
-	if ctx.Bool("all") {
+	if ctx.Bool("retry-errors") {
+		opt = photoprism.IndexOptionsRetry()
+	} else if ctx.Bool("all") {
 		opt = photoprism.IndexOptionsAll()
 	} else {
 		opt = photoprism.IndexOptionsNone()
 	}
 
+	opt.Root = ctx.String("root")
+	opt.Path = ctx.String("path")
+	opt.Resume = ctx.Bool("resume")
+
 	files := ind.Start(opt)
 	elapsed := time.Since(start)
 
 	log.Infof("indexed %d files in %s", len(files), elapsed)
 
+	if ctx.Bool("cleanup") {
+		if deleted, err := ind.Cleanup(); err != nil {
+			log.Errorf("index: %s", err)
+		} else {
+			log.Infof("removed %d ignored files from index", deleted)
+		}
+	}
+
 	conf.Shutdown()
 
 	return nil
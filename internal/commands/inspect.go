@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/urfave/cli"
+)
+
+// InspectCommand is used to register the inspect cli command
+var InspectCommand = cli.Command{
+	Name:      "inspect",
+	Usage:     "Shows the processing steps of the last indexing run for a file",
+	ArgsUsage: "[path or uuid]",
+	Action:    inspectAction,
+}
+
+// inspectAction prints the timeline of a file identified by its name or UUID
+func inspectAction(ctx *cli.Context) error {
+	arg := strings.TrimSpace(ctx.Args().First())
+
+	if arg == "" {
+		return errors.New("inspect: file path or uuid required")
+	}
+
+	conf := config.NewConfig(ctx)
+	cctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := conf.Init(cctx); err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	file, err := inspectFile(query.New(conf.Db()), conf.OriginalsRoots(), arg)
+
+	if err != nil {
+		return cli.NewExitError(fmt.Sprintf("inspect: file \"%s\" not found", arg), 1)
+	}
+
+	fmt.Printf("%-12s%s\n", "Root", file.Root())
+	fmt.Printf("%-12s%s\n", "Name", file.FileName)
+	fmt.Printf("%-12s%s\n", "UUID", file.FileUUID)
+	fmt.Printf("%-12s%s\n", "Photo", file.PhotoUUID)
+	fmt.Printf("%-12s%s\n\n", "Hash", file.FileHash)
+
+	timeline := file.Timeline()
+
+	if len(timeline) == 0 {
+		fmt.Println("no processing steps recorded, index the file again to create them")
+		return nil
+	}
+
+	fmt.Printf("TIME      STAGE     MESSAGE\n")
+
+	for _, entry := range timeline {
+		fmt.Printf("%-10s%-10s%s\n", entry.Time.Format("15:04:05"), entry.Stage, entry.Message)
+	}
+
+	return nil
+}
+
+// inspectFile finds a file by UUID, by name relative to the default originals root or by absolute path
+func inspectFile(q *query.Query, roots config.Roots, arg string) (entity.File, error) {
+	if file, err := q.FileByUUID(arg); err == nil {
+		return file, nil
+	}
+
+	if abs, err := filepath.Abs(arg); err == nil {
+		if root, fileName, ok := roots.Find(abs); ok && fileName != "" {
+			return q.FileByRootName(root.Name, fileName)
+		}
+	}
+
+	return q.FileByName(arg)
+}
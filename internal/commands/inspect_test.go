@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectCommand(t *testing.T) {
+	t.Run("no argument", func(t *testing.T) {
+		ctx := config.CliTestContext()
+
+		err := InspectCommand.Run(ctx)
+
+		if assert.Error(t, err) {
+			assert.Equal(t, "inspect: file path or uuid required", err.Error())
+		}
+	})
+}
@@ -2,39 +2,137 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/urfave/cli"
 )
 
 // MigrateCommand is used to register the migrate cli command
 var MigrateCommand = cli.Command{
-	Name:   "migrate",
-	Usage:  "Automatically initializes and migrates the database",
+	Name:  "migrate",
+	Usage: "Initializes the database and applies pending schema migrations",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run, n",
+			Usage: "show pending migrations without applying them",
+		},
+	},
 	Action: migrateAction,
+	Subcommands: []cli.Command{
+		{
+			Name:   "status",
+			Usage:  "Lists applied and pending migrations",
+			Action: migrateStatusAction,
+		},
+		{
+			Name:  "rollback",
+			Usage: "Reverts the last applied migration",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "dry-run, n",
+					Usage: "show the migration without reverting it",
+				},
+			},
+			Action: migrateRollbackAction,
+		},
+	},
 }
 
 // migrateAction automatically migrates or initializes database
 func migrateAction(ctx *cli.Context) error {
 	start := time.Now()
 
+	return withMigrations(ctx, func(conf *config.Config) error {
+		if ctx.Bool("dry-run") {
+			pending, err := entity.PendingMigrations(conf.Db())
+
+			if err != nil {
+				return err
+			}
+
+			for _, step := range pending {
+				fmt.Printf("pending %-6d%s\n", step.ID, step.Name)
+			}
+
+			log.Infof("%d pending migrations", len(pending))
+
+			return nil
+		}
+
+		log.Infoln("migrating database")
+
+		if err := conf.MigrateDb(); err != nil {
+			return err
+		}
+
+		log.Infof("database migration completed in %s", time.Since(start))
+
+		return nil
+	})
+}
+
+// migrateStatusAction lists applied and pending migrations
+func migrateStatusAction(ctx *cli.Context) error {
+	return withMigrations(ctx, func(conf *config.Config) error {
+		applied, err := entity.AppliedMigrations(conf.Db())
+
+		if err != nil {
+			return err
+		}
+
+		done := make(map[int]entity.Migration, len(applied))
+
+		for _, m := range applied {
+			done[m.ID] = m
+		}
+
+		fmt.Printf("%-6s%-32s%s\n", "ID", "NAME", "APPLIED")
+
+		for _, step := range entity.MigrationSteps {
+			status := "pending"
+
+			if m, ok := done[step.ID]; ok {
+				status = m.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Printf("%-6d%-32s%s\n", step.ID, step.Name, status)
+		}
+
+		return nil
+	})
+}
+
+// migrateRollbackAction reverts the last applied migration
+func migrateRollbackAction(ctx *cli.Context) error {
+	return withMigrations(ctx, func(conf *config.Config) error {
+		step, err := entity.Rollback(conf.Db(), ctx.Bool("dry-run"))
+
+		if err != nil {
+			return err
+		}
+
+		if ctx.Bool("dry-run") {
+			fmt.Printf("rollback %-6d%s\n", step.ID, step.Name)
+		}
+
+		return nil
+	})
+}
+
+// withMigrations connects to the database without refusing pending migrations and calls f
+func withMigrations(ctx *cli.Context, f func(conf *config.Config) error) error {
 	conf := config.NewConfig(ctx)
 	cctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	if err := conf.Init(cctx); err != nil {
+
+	if err := conf.Connect(cctx); err != nil {
 		return err
 	}
 
-	log.Infoln("migrating database")
-
-	conf.MigrateDb()
-
-	elapsed := time.Since(start)
-
-	log.Infof("database migration completed in %s", elapsed)
-
-	conf.Shutdown()
+	defer conf.Shutdown()
 
-	return nil
+	return f(conf)
 }
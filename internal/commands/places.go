@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/urfave/cli"
+)
+
+// PlacesCommand is used to register the places cli command
+var PlacesCommand = cli.Command{
+	Name:  "places",
+	Usage: "Manages places and locations",
+	Subcommands: []cli.Command{
+		{
+			Name:   "refresh",
+			Usage:  "Resolves locations found by the offline geocoder again using the geocoding api",
+			Action: placesRefreshAction,
+		},
+	},
+}
+
+// placesRefreshAction resolves offline locations again and updates generated titles of their photos
+func placesRefreshAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		api := conf.GeoCodingApi()
+
+		if api == "" || api == entity.LocSourceOffline {
+			return errors.New("geocoding api must be osm or places to refresh locations")
+		}
+
+		q := query.New(conf.Db())
+		result, err := q.RefreshPlaces(api)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("refreshed %d locations in %s", result.Updated, time.Since(start))
+
+		if result.Failed > 0 {
+			log.Warnf("%d locations could not be refreshed, see log for details", result.Failed)
+		}
+
+		if result.Updated == 0 {
+			return nil
+		}
+
+		titles, err := q.UpdateTitles()
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("updated %d photo titles", titles)
+
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/urfave/cli"
+)
+
+// PurgeCommand is used to register the purge cli command
+var PurgeCommand = cli.Command{
+	Name:  "purge",
+	Usage: "Removes missing files and orphaned records from the index",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "root",
+			Usage: "only check files in an originals root `NAME`, all roots are checked by default",
+		},
+		cli.StringFlag{
+			Name:  "path",
+			Usage: "only check files in a sub-folder, relative to the originals root",
+		},
+		cli.BoolFlag{
+			Name:  "hard",
+			Usage: "permanently delete photos instead of flagging them as deleted",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run, n",
+			Usage: "list affected records without changing anything",
+		},
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "purge even if an originals root is empty or most files are missing",
+		},
+	},
+	Action: purgeAction,
+}
+
+// purgeAction removes missing files and orphaned records from the index
+func purgeAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		opt := photoprism.PurgeOptions{
+			Root:   ctx.String("root"),
+			Path:   ctx.String("path"),
+			Hard:   ctx.Bool("hard"),
+			DryRun: ctx.Bool("dry-run"),
+			Force:  ctx.Bool("force"),
+		}
+
+		result, err := photoprism.NewPurge(conf).Start(opt)
+
+		if err != nil {
+			return err
+		}
+
+		if opt.DryRun {
+			for _, fileName := range result.Files {
+				fmt.Printf("missing file  %s\n", fileName)
+			}
+
+			for _, photoUUID := range result.Photos {
+				fmt.Printf("delete photo  %s\n", photoUUID)
+			}
+		}
+
+		fmt.Printf("%-22s%d\n", "missing files", len(result.Files))
+		fmt.Printf("%-22s%d\n", "deleted photos", len(result.Photos))
+		fmt.Printf("%-22s%d\n", "orphaned photo labels", result.PhotoLabels)
+		fmt.Printf("%-22s%d\n", "orphaned album photos", result.PhotoAlbums)
+
+		if opt.DryRun {
+			log.Infof("purge: dry run completed in %s, nothing was changed", time.Since(start))
+		} else {
+			log.Infof("purge: completed in %s", time.Since(start))
+		}
+
+		return nil
+	})
+}
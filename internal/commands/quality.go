@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/urfave/cli"
+)
+
+// QualityCommand is used to register the quality cli command
+var QualityCommand = cli.Command{
+	Name:      "quality",
+	Usage:     "Calculates photo quality scores again without indexing, e.g. after the weights were changed",
+	ArgsUsage: "[search filter]",
+	Action:    qualityAction,
+}
+
+// qualityAction updates the quality scores of all photos or of photos matching a search filter
+func qualityAction(ctx *cli.Context) error {
+	start := time.Now()
+	filter := strings.TrimSpace(strings.Join(ctx.Args(), " "))
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		updated, err := query.New(conf.Db()).UpdateQuality(filter)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("updated %d photo quality scores in %s", len(updated), time.Since(start))
+
+		return nil
+	})
+}
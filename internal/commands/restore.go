@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/urfave/cli"
+)
+
+// RestoreCommand is used to register the restore cli command
+var RestoreCommand = cli.Command{
+	Name:  "restore",
+	Usage: "Restores the index database from a backup",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "path, p",
+			Usage: "backup file name",
+		},
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "replace an existing index",
+		},
+	},
+	Action: restoreAction,
+}
+
+// restoreAction imports all tables from a zip archive
+func restoreAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	fileName := ctx.String("path")
+
+	if fileName == "" {
+		return errors.New("restore: backup file name required")
+	}
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		manifest, err := photoprism.Restore(conf.Db(), fileName, ctx.Bool("force"))
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("restore: restored %d photos from %s (version %s, created %s) in %s", manifest.Tables["photos"], fileName, manifest.Version, manifest.Created.Format(time.RFC3339), time.Since(start))
+
+		return nil
+	})
+}
@@ -60,6 +60,7 @@ func startAction(ctx *cli.Context) error {
 		fmt.Printf("http-host             %s\n", conf.HttpServerHost())
 		fmt.Printf("http-port             %d\n", conf.HttpServerPort())
 		fmt.Printf("http-mode             %s\n", conf.HttpServerMode())
+		fmt.Printf("http-socket           %s\n", conf.HttpServerSocket())
 
 		return nil
 	}
@@ -67,21 +68,18 @@ func startAction(ctx *cli.Context) error {
 	// pass this context down the chain
 	cctx, cancel := context.WithCancel(context.Background())
 
-	if conf.HttpServerPort() < 1 || conf.HttpServerPort() > 65535 {
+	if conf.HttpServerTcp() && (conf.HttpServerPort() < 1 || conf.HttpServerPort() > 65535) {
 		log.Fatal("server port must be a number between 1 and 65535")
 	}
 
-	if err := conf.CreateDirectories(); err != nil {
+	if err := conf.Validate(); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := conf.Init(cctx); err != nil {
+	if err := conf.CreateDirectories(); err != nil {
 		log.Fatal(err)
 	}
 
-	// initialize the database
-	conf.MigrateDb()
-
 	// check if daemon is running, if not initialize the daemon
 	dctx := new(daemon.Context)
 	dctx.LogFileName = conf.LogFilename()
@@ -116,11 +114,37 @@ func startAction(ctx *cli.Context) error {
 		log.Infof("read-only mode enabled")
 	}
 
-	// start web server
+	start := time.Now()
+
+	conf.Propagate()
+	conf.Warming(config.SubsystemDatabase)
+	conf.Warming(config.SubsystemModels)
+
+	// start web server right away, requests depending on subsystems
+	// that are not ready yet get a 503 Service Unavailable response
 	go server.Start(cctx, conf)
 
-	// start share & sync workers
-	workers.Start(conf)
+	go func() {
+		// initialize the database
+		if err := conf.Init(cctx); err != nil {
+			conf.SetReady(config.SubsystemDatabase, err)
+			log.Fatal(err)
+		}
+
+		if err := conf.MigrateDb(); err != nil {
+			conf.SetReady(config.SubsystemDatabase, err)
+			log.Fatal(err)
+		}
+
+		conf.SetReady(config.SubsystemDatabase, nil)
+
+		// start share & sync workers
+		workers.Start(conf)
+
+		conf.SetReady(config.SubsystemModels, service.InitModels())
+
+		log.Infof("startup: completed in %s (%s)", time.Since(start).Round(time.Millisecond), conf.Subsystems())
+	}()
 
 	// set up proper shutdown of daemon and web server
 	quit := make(chan os.Signal)
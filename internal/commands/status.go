@@ -1,8 +1,10 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"time"
 
@@ -22,8 +24,20 @@ var StatusCommand = cli.Command{
 func statusAction(ctx *cli.Context) error {
 	conf := config.NewConfig(ctx)
 	client := &http.Client{Timeout: 10 * time.Second}
+	addr := fmt.Sprintf("%s:%d", conf.HttpServerHost(), conf.HttpServerPort())
+	url := fmt.Sprintf("http://%s/api/v1/status", addr)
 
-	url := fmt.Sprintf("http://%s:%d/api/v1/status", conf.HttpServerHost(), conf.HttpServerPort())
+	// Connect to the unix socket if the server doesn't listen on a port.
+	if !conf.HttpServerTcp() {
+		addr = conf.HttpServerSocket()
+		url = "http://localhost/api/v1/status"
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", addr)
+			},
+		}
+	}
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 
@@ -34,9 +48,9 @@ func statusAction(ctx *cli.Context) error {
 	var status string
 
 	if resp, err := client.Do(req); err != nil {
-		return fmt.Errorf("can't connect to %s:%d", conf.HttpServerHost(), conf.HttpServerPort())
+		return fmt.Errorf("can't connect to %s", addr)
 	} else if resp.StatusCode != 200 {
-		return fmt.Errorf("server running at %s:%d, bad status %d\n", conf.HttpServerHost(), conf.HttpServerPort(), resp.StatusCode)
+		return fmt.Errorf("server running at %s, bad status %d\n", addr, resp.StatusCode)
 	} else if body, err := ioutil.ReadAll(resp.Body); err != nil {
 		return err
 	} else {
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/service"
 	"github.com/urfave/cli"
 )
@@ -15,7 +16,7 @@ var ThumbsCommand = cli.Command{
 	Flags: []cli.Flag{
 		cli.BoolFlag{
 			Name:  "force, f",
-			Usage: "re-create existing thumbnails",
+			Usage: "re-create existing thumbnails, even if they are complete for the current settings",
 		},
 	},
 	Action: thumbsAction,
@@ -34,9 +35,39 @@ func thumbsAction(ctx *cli.Context) error {
 
 	log.Infof("creating thumbnails in \"%s\"", conf.ThumbnailsPath())
 
+	// Show progress while thumbnails are created, events are dropped if logging is too slow.
+	sub := event.SharedHub().NonBlockingSubscribe(100, "thumbs.progress")
+	stop := make(chan bool)
+
+	go func() {
+		var logged time.Time
+
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-sub.Receiver:
+				folderDone := msg.Fields["done"] == msg.Fields["total"]
+
+				if !folderDone && time.Since(logged) < time.Second {
+					continue
+				}
+
+				logged = time.Now()
+
+				log.Infof("thumbs: %v of %v files done, %v of %v in \"%v\"", msg.Fields["filesDone"], msg.Fields["filesTotal"],
+					msg.Fields["done"], msg.Fields["total"], msg.Fields["folder"])
+			}
+		}
+	}()
+
 	rs := service.Resample()
+	err := rs.Start(ctx.Bool("force"))
+
+	event.SharedHub().Unsubscribe(sub)
+	close(stop)
 
-	if err := rs.Start(ctx.Bool("force")); err != nil {
+	if err != nil {
 		log.Error(err)
 		return err
 	}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/urfave/cli"
+)
+
+// TitlesCommand is used to register the titles cli command
+var TitlesCommand = cli.Command{
+	Name:   "titles",
+	Usage:  "Generates new titles for photos with generated titles, e.g. after the title template was changed",
+	Action: titlesAction,
+}
+
+// titlesAction updates generated photo titles, manually edited titles are kept
+func titlesAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		updated, err := query.New(conf.Db()).UpdateTitles()
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("updated %d photo titles in %s", updated, time.Since(start))
+
+		return nil
+	})
+}
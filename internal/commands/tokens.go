@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/urfave/cli"
+)
+
+// TokensCommand is used to register the tokens cli command
+var TokensCommand = cli.Command{
+	Name:  "tokens",
+	Usage: "Manages API tokens for non-interactive clients",
+	Subcommands: []cli.Command{
+		{
+			Name:   "ls",
+			Usage:  "Lists all API tokens",
+			Action: tokensListAction,
+		},
+		{
+			Name:      "add",
+			Usage:     "Creates an API token and shows it once",
+			ArgsUsage: "[label]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "scope, s",
+					Usage: "token scope: " + strings.Join(entity.ApiTokenScopes, ", "),
+					Value: entity.ScopeRead,
+				},
+			},
+			Action: tokensAddAction,
+		},
+		{
+			Name:      "remove",
+			Usage:     "Revokes an API token",
+			ArgsUsage: "[id or prefix]",
+			Action:    tokensRemoveAction,
+		},
+	},
+}
+
+// tokensListAction prints all API tokens without the tokens themselves
+func tokensListAction(ctx *cli.Context) error {
+	return withDatabase(ctx, func(conf *config.Config) error {
+		var tokens []entity.ApiToken
+
+		if err := conf.Db().Order("id").Find(&tokens).Error; err != nil {
+			return err
+		}
+
+		fmt.Printf("%-6s%-12s%-8s%-20s%s\n", "ID", "PREFIX", "SCOPE", "LAST USED", "LABEL")
+
+		for _, token := range tokens {
+			lastUsed := "never"
+
+			if token.LastUsed != nil {
+				lastUsed = token.LastUsed.Format("2006-01-02 15:04:05")
+			}
+
+			fmt.Printf("%-6d%-12s%-8s%-20s%s\n", token.ID, token.TokenPrefix, token.TokenScope, lastUsed, token.TokenLabel)
+		}
+
+		return nil
+	})
+}
+
+// tokensAddAction creates a new API token
+func tokensAddAction(ctx *cli.Context) error {
+	label := strings.TrimSpace(strings.Join(ctx.Args(), " "))
+
+	if label == "" {
+		return errors.New("tokens: label required")
+	}
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		m, token, err := entity.NewApiToken(label, ctx.String("scope"), entity.AdminUserID)
+
+		if err != nil {
+			return fmt.Errorf("tokens: %s", err)
+		}
+
+		if err := m.Save(conf.Db()); err != nil {
+			return err
+		}
+
+		log.Infof("tokens: created token %d with scope %s, it won't be shown again", m.ID, m.TokenScope)
+
+		fmt.Println(token)
+
+		return nil
+	})
+}
+
+// tokensRemoveAction revokes an API token identified by id or prefix
+func tokensRemoveAction(ctx *cli.Context) error {
+	arg := strings.TrimSpace(ctx.Args().First())
+
+	if arg == "" {
+		return errors.New("tokens: id or prefix required")
+	}
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		var m entity.ApiToken
+
+		q := conf.Db().Where("token_prefix = ?", arg)
+
+		if id, err := strconv.ParseUint(arg, 10, 32); err == nil {
+			q = conf.Db().Where("id = ? OR token_prefix = ?", id, arg)
+		}
+
+		if err := q.First(&m).Error; err != nil {
+			return fmt.Errorf("tokens: %s not found", arg)
+		}
+
+		if err := m.Delete(conf.Db()); err != nil {
+			return err
+		}
+
+		log.Infof("tokens: revoked token %d (%s)", m.ID, m.TokenPrefix)
+
+		return nil
+	})
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/urfave/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// UsersCommand is used to register the users cli command
+var UsersCommand = cli.Command{
+	Name:  "users",
+	Usage: "Manages user accounts for the web UI and WebDAV",
+	Subcommands: []cli.Command{
+		{
+			Name:   "ls",
+			Usage:  "Lists all user accounts",
+			Action: usersListAction,
+		},
+		{
+			Name:      "add",
+			Usage:     "Adds a user account",
+			ArgsUsage: "[username]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "role, r",
+					Usage: "user role: " + strings.Join(entity.UserRoles, ", "),
+					Value: entity.RoleViewer,
+				},
+				cli.StringFlag{
+					Name:  "password, p",
+					Usage: "user password, asks for the password if empty",
+				},
+			},
+			Action: usersAddAction,
+		},
+		{
+			Name:      "remove",
+			Usage:     "Removes a user account",
+			ArgsUsage: "[username]",
+			Action:    usersRemoveAction,
+		},
+		{
+			Name:      "passwd",
+			Usage:     "Changes the password of a user account",
+			ArgsUsage: "[username]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "password, p",
+					Usage: "new password, asks for the password if empty",
+				},
+			},
+			Action: usersPasswdAction,
+		},
+	},
+}
+
+// usersListAction prints all user accounts
+func usersListAction(ctx *cli.Context) error {
+	return withDatabase(ctx, func(conf *config.Config) error {
+		var users []entity.User
+
+		if err := conf.Db().Order("user_name").Find(&users).Error; err != nil {
+			return err
+		}
+
+		fmt.Printf("%-6s%-24s%-10s%s\n", "ID", "NAME", "ROLE", "PASSWORD")
+
+		for _, user := range users {
+			password := "set"
+
+			if !user.HasPassword() {
+				password = "none"
+
+				if user.ID == entity.AdminUserID {
+					password = "admin password"
+				}
+			}
+
+			fmt.Printf("%-6d%-24s%-10s%s\n", user.ID, user.UserName, user.UserRole, password)
+		}
+
+		return nil
+	})
+}
+
+// usersAddAction creates a new user account
+func usersAddAction(ctx *cli.Context) error {
+	userName := strings.TrimSpace(ctx.Args().First())
+
+	if userName == "" {
+		return errors.New("users: username required")
+	}
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		if _, err := entity.FindUserByName(conf.Db(), userName); err == nil {
+			return fmt.Errorf("users: %s already exists", userName)
+		}
+
+		password, err := passwordFlag(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		user, err := entity.NewUser(userName, password, ctx.String("role"))
+
+		if err != nil {
+			return fmt.Errorf("users: %s", err)
+		}
+
+		if err := user.Save(conf.Db()); err != nil {
+			return err
+		}
+
+		log.Infof("users: added %s with role %s", user.UserName, user.UserRole)
+
+		return nil
+	})
+}
+
+// usersRemoveAction deletes a user account
+func usersRemoveAction(ctx *cli.Context) error {
+	userName := strings.TrimSpace(ctx.Args().First())
+
+	if userName == "" {
+		return errors.New("users: username required")
+	}
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		user, err := entity.FindUserByName(conf.Db(), userName)
+
+		if err != nil {
+			return fmt.Errorf("users: %s not found", userName)
+		}
+
+		if err := user.Delete(conf.Db()); err != nil {
+			return fmt.Errorf("users: %s", err)
+		}
+
+		log.Infof("users: removed %s", user.UserName)
+
+		return nil
+	})
+}
+
+// usersPasswdAction changes the password of a user account
+func usersPasswdAction(ctx *cli.Context) error {
+	userName := strings.TrimSpace(ctx.Args().First())
+
+	if userName == "" {
+		return errors.New("users: username required")
+	}
+
+	return withDatabase(ctx, func(conf *config.Config) error {
+		user, err := entity.FindUserByName(conf.Db(), userName)
+
+		if err != nil {
+			return fmt.Errorf("users: %s not found", userName)
+		}
+
+		password, err := passwordFlag(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if err := user.SetPassword(password); err != nil {
+			return fmt.Errorf("users: %s", err)
+		}
+
+		if err := user.Save(conf.Db()); err != nil {
+			return err
+		}
+
+		log.Infof("users: changed password of %s", user.UserName)
+
+		return nil
+	})
+}
+
+// withDatabase initializes the config and migrates the database before running f
+func withDatabase(ctx *cli.Context, f func(conf *config.Config) error) error {
+	conf := config.NewConfig(ctx)
+	cctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := conf.Init(cctx); err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	if err := conf.MigrateDb(); err != nil {
+		return err
+	}
+
+	return f(conf)
+}
+
+// passwordFlag returns the password flag value or asks for a password if empty
+func passwordFlag(ctx *cli.Context) (string, error) {
+	if password := ctx.String("password"); password != "" {
+		return password, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if !terminal.IsTerminal(fd) {
+		return "", errors.New("users: password required")
+	}
+
+	fmt.Print("Password: ")
+	password, err := terminal.ReadPassword(fd)
+	fmt.Println()
+
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Print("Retype password: ")
+	retyped, err := terminal.ReadPassword(fd)
+	fmt.Println()
+
+	if err != nil {
+		return "", err
+	}
+
+	if string(password) != string(retyped) {
+		return "", errors.New("users: passwords don't match")
+	}
+
+	return string(password), nil
+}
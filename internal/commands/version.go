@@ -12,13 +12,52 @@ var VersionCommand = cli.Command{
 	Name:   "version",
 	Usage:  "Shows version information",
 	Action: versionAction,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "verbose, v",
+			Usage: "show build, runtime, features and external binaries",
+		},
+	},
 }
 
 // versionAction prints the current version
 func versionAction(ctx *cli.Context) error {
 	conf := config.NewConfig(ctx)
 
-	fmt.Println(conf.Version())
+	if !ctx.Bool("verbose") {
+		fmt.Println(conf.Version())
+		return nil
+	}
+
+	about := conf.About()
+
+	fmt.Printf("NAME                  VALUE\n")
+	fmt.Printf("name                  %s\n", about.Name)
+	fmt.Printf("version               %s\n", about.Version)
+	fmt.Printf("commit                %s\n", about.Commit)
+	fmt.Printf("built                 %s\n", about.Built)
+	fmt.Printf("go                    %s\n", about.Runtime.Go)
+	fmt.Printf("platform              %s/%s\n", about.Runtime.OS, about.Runtime.Arch)
+	fmt.Printf("cpus                  %d\n", about.Runtime.CPUs)
+	fmt.Printf("experimental          %t\n", about.Features.Experimental)
+	fmt.Printf("detect-nsfw           %t\n", about.Features.DetectNSFW)
+	fmt.Printf("read-only             %t\n", about.Features.ReadOnly)
+	fmt.Printf("public                %t\n", about.Features.Public)
+	fmt.Printf("tensorflow            %s\n", about.Features.TensorFlow)
+	fmt.Printf("geocoding-api         %s\n", about.Features.GeoCoding)
+	fmt.Printf("database-driver       %s\n", about.Database.Driver)
+	fmt.Printf("database-dsn          %s\n", about.Database.Dsn)
+
+	for _, t := range about.Tools {
+		switch {
+		case t.Bin == "":
+			fmt.Printf("%-21s %s\n", t.Name, "not found")
+		case t.Version == "":
+			fmt.Printf("%-21s %s\n", t.Name, t.Bin)
+		default:
+			fmt.Printf("%-21s %s (%s)\n", t.Name, t.Bin, t.Version)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// About contains build, runtime and capability information for support requests.
+type About struct {
+	Name     string        `json:"name"`
+	Version  string        `json:"version"`
+	Commit   string        `json:"commit"`
+	Built    string        `json:"built"`
+	Runtime  AboutRuntime  `json:"runtime"`
+	Features AboutFeatures `json:"features"`
+	Tools    []AboutTool   `json:"tools"`
+	Database AboutDatabase `json:"database"`
+}
+
+// AboutRuntime contains the Go runtime version and platform.
+type AboutRuntime struct {
+	Go   string `json:"go"`
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+	CPUs int    `json:"cpus"`
+}
+
+// AboutFeatures contains the enabled features.
+type AboutFeatures struct {
+	Experimental bool   `json:"experimental"`
+	DetectNSFW   bool   `json:"nsfw"`
+	ReadOnly     bool   `json:"readonly"`
+	Public       bool   `json:"public"`
+	TensorFlow   string `json:"tensorflow"`
+	GeoCoding    string `json:"geocoding"`
+}
+
+// AboutTool contains the file name and version of an external binary, both are empty if not found.
+type AboutTool struct {
+	Name    string `json:"name"`
+	Bin     string `json:"bin"`
+	Version string `json:"version"`
+}
+
+// AboutDatabase contains the database driver, server version and data source name without password.
+type AboutDatabase struct {
+	Driver  string `json:"driver"`
+	Version string `json:"version"`
+	Dsn     string `json:"dsn"`
+}
+
+// toolVersions caches the versions of external binaries by file name, as running them takes a while.
+var toolVersions = struct {
+	sync.Mutex
+	m map[string]string
+}{m: make(map[string]string)}
+
+// cachedToolVersion returns the version of an external binary, running it only once.
+func cachedToolVersion(bin, arg string) string {
+	toolVersions.Lock()
+	defer toolVersions.Unlock()
+
+	if version, ok := toolVersions.m[bin]; ok {
+		return version
+	}
+
+	version, err := toolVersion(bin, arg)
+
+	if err != nil {
+		log.Debugf("about: %s (%s)", err, bin)
+		version = ""
+	}
+
+	toolVersions.m[bin] = version
+
+	return version
+}
+
+// About returns build, runtime and capability information. The database version is only included if
+// the database is connected.
+func (c *Config) About() About {
+	result := About{
+		Name:    c.Name(),
+		Version: c.Version(),
+		Commit:  c.params.Commit,
+		Built:   c.params.Built,
+		Runtime: AboutRuntime{
+			Go:   runtime.Version(),
+			OS:   runtime.GOOS,
+			Arch: runtime.GOARCH,
+			CPUs: runtime.NumCPU(),
+		},
+		Features: AboutFeatures{
+			Experimental: c.Experimental(),
+			DetectNSFW:   c.DetectNSFW(),
+			ReadOnly:     c.ReadOnly(),
+			Public:       c.Public(),
+			TensorFlow:   c.TensorFlowVersion(),
+			GeoCoding:    c.GeoCodingApi(),
+		},
+		Database: AboutDatabase{
+			Driver: c.DatabaseDriver(),
+			Dsn:    MaskDsn(c.DatabaseDsn()),
+		},
+	}
+
+	for _, t := range c.externalTools() {
+		tool := AboutTool{Name: t.name, Bin: t.bin}
+
+		if t.bin != "" && t.version != "" {
+			tool.Version = cachedToolVersion(t.bin, t.version)
+		}
+
+		result.Tools = append(result.Tools, tool)
+	}
+
+	if c.db != nil && c.db.Dialect().GetName() == DbMySQL {
+		var version struct {
+			Version string
+		}
+
+		if err := c.db.Raw("SELECT VERSION() AS version").Scan(&version).Error; err != nil {
+			log.Debugf("about: %s", err)
+		} else {
+			result.Database.Version = version.Version
+		}
+	}
+
+	return result
+}
+
+// MaskDsn replaces the password in a data source name like "user:pass@tcp(host:port)/name" or
+// "redis://:pass@host:port/0".
+func MaskDsn(dsn string) string {
+	at := strings.LastIndex(dsn, "@")
+
+	if at < 0 {
+		return dsn
+	}
+
+	start := 0
+
+	if i := strings.Index(dsn[:at], "://"); i >= 0 {
+		start = i + 3
+	}
+
+	if colon := strings.Index(dsn[start:at], ":"); colon >= 0 {
+		return dsn[:start+colon+1] + "***" + dsn[at:]
+	}
+
+	return dsn
+}
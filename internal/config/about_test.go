@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_About(t *testing.T) {
+	dir, err := ioutil.TempDir("", "about")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	exiftool := filepath.Join(dir, "exiftool")
+	ffmpeg := filepath.Join(dir, "ffmpeg")
+
+	for _, fileName := range []string{exiftool, ffmpeg} {
+		if err := ioutil.WriteFile(fileName, []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := 0
+	stub := toolVersion
+
+	toolVersion = func(bin, arg string) (string, error) {
+		calls++
+
+		if bin == ffmpeg {
+			return "", errors.New("exit status 1")
+		}
+
+		return "12.00", nil
+	}
+
+	defer func() { toolVersion = stub }()
+
+	params := *NewTestParams()
+	params.ExifToolBin = exiftool
+	params.FFmpegBin = ffmpeg
+	params.DarktableBin = filepath.Join(dir, "darktable-cli")
+	params.DatabaseDriver = DbMySQL
+	params.DatabaseDsn = "photoprism:secret@tcp(photoprism-db:4001)/photoprism?parseTime=true"
+	params.Commit = "17dcbf3"
+	params.Built = "2020-08-06T12:00:00Z"
+
+	c := &Config{params: &params}
+
+	about := c.About()
+
+	assert.Equal(t, "17dcbf3", about.Commit)
+	assert.Equal(t, "2020-08-06T12:00:00Z", about.Built)
+	assert.NotEmpty(t, about.Runtime.Go)
+	assert.Equal(t, DbMySQL, about.Database.Driver)
+	assert.Equal(t, "photoprism:***@tcp(photoprism-db:4001)/photoprism?parseTime=true", about.Database.Dsn)
+	assert.Empty(t, about.Database.Version)
+	assert.Contains(t, about.Tools, AboutTool{Name: "exiftool", Bin: exiftool, Version: "12.00"})
+	assert.Contains(t, about.Tools, AboutTool{Name: "ffmpeg", Bin: ffmpeg})
+	assert.Contains(t, about.Tools, AboutTool{Name: "darktable-cli"})
+
+	data, err := json.Marshal(about)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotContains(t, string(data), "secret")
+
+	var result map[string]interface{}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"name", "version", "commit", "built", "runtime", "features", "tools", "database"} {
+		assert.Contains(t, result, key)
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		n := calls
+		c.About()
+		assert.Equal(t, n, calls)
+	})
+}
+
+func TestMaskDsn(t *testing.T) {
+	assert.Equal(t, "root:***@tcp(localhost:4000)/photoprism", MaskDsn("root:p@ss:word@tcp(localhost:4000)/photoprism"))
+	assert.Equal(t, "root@tcp(localhost:4000)/photoprism", MaskDsn("root@tcp(localhost:4000)/photoprism"))
+	assert.Equal(t, "/srv/photoprism/index.db", MaskDsn("/srv/photoprism/index.db"))
+	assert.Equal(t, "redis://:***@redis.local:6379/1", MaskDsn("redis://:secret@redis.local:6379/1"))
+	assert.Equal(t, "redis.local:6379", MaskDsn("redis.local:6379"))
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"regexp"
+	"strings"
 
+	"github.com/photoprism/photoprism/internal/entity"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -25,3 +27,34 @@ func (c *Config) CheckPassword(p string) bool {
 
 	return ap == p
 }
+
+// Authenticate returns the user with the given name if the password is correct. The default admin
+// user logs in with the admin password until another password was set, an empty name defaults to admin.
+func (c *Config) Authenticate(userName, password string) (*entity.User, error) {
+	userName = strings.ToLower(strings.TrimSpace(userName))
+
+	if userName == "" {
+		userName = entity.Admin.UserName
+	}
+
+	user, err := entity.FindUserByName(c.Db(), userName)
+
+	if err != nil {
+		if userName != entity.Admin.UserName {
+			return nil, ErrInvalidLogin
+		}
+
+		admin := entity.Admin
+		user = &admin
+	}
+
+	if user.HasPassword() {
+		if !user.CheckPassword(password) {
+			return nil, ErrInvalidLogin
+		}
+	} else if user.ID != entity.AdminUserID || !c.CheckPassword(password) {
+		return nil, ErrInvalidLogin
+	}
+
+	return user, nil
+}
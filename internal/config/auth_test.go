@@ -3,6 +3,7 @@ package config
 import (
 	"testing"
 
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -38,3 +39,45 @@ func TestUtils_isBcrypt(t *testing.T) {
 	p = "admin"
 	assert.False(t, isBcrypt(p))
 }
+
+func TestConfig_Authenticate(t *testing.T) {
+	c := TestConfig()
+
+	adminPassword := c.params.AdminPassword
+	c.params.AdminPassword = "photoprism"
+	defer func() { c.params.AdminPassword = adminPassword }()
+
+	viewer, err := entity.NewUser("config-viewer", "secret", entity.RoleViewer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := viewer.Save(c.Db()); err != nil {
+		t.Fatal(err)
+	}
+
+	defer viewer.Delete(c.Db())
+
+	t.Run("admin password", func(t *testing.T) {
+		user, err := c.Authenticate("", "photoprism")
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.AdminUserID, user.ID)
+		assert.True(t, user.Admin())
+	})
+	t.Run("viewer", func(t *testing.T) {
+		user, err := c.Authenticate("Config-Viewer", "secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleViewer, user.UserRole)
+	})
+	t.Run("admin password for other user", func(t *testing.T) {
+		_, err := c.Authenticate("config-viewer", "photoprism")
+		assert.Equal(t, ErrInvalidLogin, err)
+	})
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := c.Authenticate("unknown", "photoprism")
+		assert.Equal(t, ErrInvalidLogin, err)
+	})
+}
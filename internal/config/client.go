@@ -35,12 +35,17 @@ func (c *Config) Flags() (flags []string) {
 		flags = append(flags, "settings")
 	}
 
+	if c.SetupRequired() {
+		flags = append(flags, "setup")
+	}
+
 	return flags
 }
 
-// PublicClientConfig returns reduced config values for non-public sites.
+// PublicClientConfig returns reduced config values for non-public sites,
+// while the database is not ready and until setup is completed.
 func (c *Config) PublicClientConfig() ClientConfig {
-	if c.Public() {
+	if c.Public() && c.Ready(SubsystemDatabase) && !c.SetupRequired() {
 		return c.ClientConfig()
 	}
 
@@ -83,13 +88,14 @@ func (c *Config) PublicClientConfig() ClientConfig {
 		"readonly":        c.ReadOnly(),
 		"uploadNSFW":      c.UploadNSFW(),
 		"public":          c.Public(),
+		"downloadTokens":  c.DownloadTokens(),
 		"experimental":    c.Experimental(),
 		"disableSettings": c.DisableSettings(),
 		"albums":          []string{},
 		"cameras":         []string{},
 		"lenses":          []string{},
 		"countries":       []string{},
-		"thumbnails":      Thumbnails,
+		"thumbnails":      c.Thumbnails(),
 		"jsHash":          jsHash,
 		"cssHash":         cssHash,
 		"count":           count,
@@ -105,6 +111,10 @@ func (c *Config) PublicClientConfig() ClientConfig {
 
 // ClientConfig returns a loaded and set configuration entity.
 func (c *Config) ClientConfig() ClientConfig {
+	if !c.Ready(SubsystemDatabase) {
+		return c.PublicClientConfig()
+	}
+
 	db := c.Db()
 
 	var cameras []*entity.Camera
@@ -228,13 +238,14 @@ func (c *Config) ClientConfig() ClientConfig {
 		"readonly":        c.ReadOnly(),
 		"uploadNSFW":      c.UploadNSFW(),
 		"public":          c.Public(),
+		"downloadTokens":  c.DownloadTokens(),
 		"experimental":    c.Experimental(),
 		"disableSettings": c.DisableSettings(),
 		"albums":          albums,
 		"cameras":         cameras,
 		"lenses":          lenses,
 		"countries":       countries,
-		"thumbnails":      Thumbnails,
+		"thumbnails":      c.Thumbnails(),
 		"jsHash":          jsHash,
 		"cssHash":         cssHash,
 		"settings":        c.Settings(),
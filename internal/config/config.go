@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"runtime"
 	"strings"
 	"sync"
@@ -10,10 +12,16 @@ import (
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
-	gc "github.com/patrickmn/go-cache"
+	"github.com/photoprism/photoprism/internal/cache"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/maps/httpclient"
+	"github.com/photoprism/photoprism/internal/maps/osm"
+	"github.com/photoprism/photoprism/internal/maps/places"
+	"github.com/photoprism/photoprism/internal/meta"
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
@@ -25,19 +33,19 @@ var once sync.Once
 type Config struct {
 	once     sync.Once
 	db       *gorm.DB
-	cache    *gc.Cache
+	cache    cache.Cache
 	params   *Params
 	settings *Settings
-}
+	ready    readiness
+	setup    setupState
 
-func init() {
-	// initialize the Thumbnails global variable
-	for name, t := range thumb.Types {
-		if t.Public {
-			thumbnail := Thumbnail{Name: name, Width: t.Width, Height: t.Height}
-			Thumbnails = append(Thumbnails, thumbnail)
-		}
-	}
+	instanceOnce sync.Once
+	instanceKey  []byte
+
+	limiterOnce sync.Once
+	limiter     *mutex.Limiter
+
+	cacheOnce sync.Once
 }
 
 func initLogger(debug bool) {
@@ -75,13 +83,63 @@ func (c *Config) Propagate() {
 	thumb.JpegQuality = c.ThumbQuality()
 	thumb.PreRenderSize = c.ThumbSize()
 	thumb.MaxRenderSize = c.ThumbLimit()
+	thumb.SizeLimit = c.OriginalsLimit()
+	thumb.ResolutionLimit = c.ResolutionLimit()
 	thumb.Filter = c.ThumbFilter()
+	thumb.TypeFilters = c.ThumbFilters()
+	thumb.Sharpen = c.ThumbSharpen()
+	thumb.UseEmbedded = c.ThumbUseEmbedded()
+	thumb.ColorManagement = c.ThumbColorManagement()
+	thumb.Progressive = c.ThumbProgressive()
+	thumb.SmartCrop = c.ThumbSmartCrop()
+	thumb.JpegTranBin = c.JpegTranBin()
+	thumb.Copyright = c.ThumbCopyright()
+	thumb.Format = c.ThumbFormat()
+	thumb.CWebPBin = c.CWebPBin()
+
+	if sizes, err := c.ThumbSizes(); err != nil {
+		log.Errorf("config: %s, using default thumbnail sizes", err)
+		thumb.Install(nil)
+	} else {
+		thumb.Install(sizes)
+	}
+
+	event.Logs.Configure(c.LogBuffer(), c.Debug())
+
+	meta.ExifToolBin = c.ExifToolBin()
+	entity.GeoCacheTTL = c.GeoCodingTTL()
+	mutex.Interactive.Configure(c.WorkerNice(), c.workerLoad)
+
+	if err := httpclient.Configure(c.HttpProxy(), c.GeoCodingTimeout(), c.UserAgent()); err != nil {
+		log.Errorf("config: %s", err)
+	}
+
+	switch c.GeoCodingApi() {
+	case "osm":
+		osm.SetEndpoint(c.GeoCodingEndpoint())
+	case "places":
+		places.SetEndpoint(c.PlacesUrl())
+	}
+
+	// Geocoding results are shared with other instances, the in-memory cache stays private.
+	if c.CacheDriver() != cache.DriverMemory {
+		places.SetCache(c.Cache())
+	}
 
 	c.Settings().Propagate()
 }
 
 // Init initialises the database connection and dependencies.
 func (c *Config) Init(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	return c.checkMigrations()
+}
+
+// Connect initialises dependencies and connects to the database without checking for pending migrations.
+func (c *Config) Connect(ctx context.Context) error {
 	c.Propagate()
 	return c.connectToDatabase(ctx)
 }
@@ -149,6 +207,15 @@ func (c *Config) Public() bool {
 	return c.params.Public
 }
 
+// PublicRole returns the pseudo-role of anonymous users in public mode, "guest" by default.
+func (c *Config) PublicRole() string {
+	if c.params.PublicRole == "" {
+		return "guest"
+	}
+
+	return strings.ToLower(c.params.PublicRole)
+}
+
 // Experimental returns true if experimental features should be enabled.
 func (c *Config) Experimental() bool {
 	return c.params.Experimental
@@ -159,6 +226,33 @@ func (c *Config) ReadOnly() bool {
 	return c.params.ReadOnly
 }
 
+// AutoMigrate returns true if pending database migrations should be applied on startup.
+func (c *Config) AutoMigrate() bool {
+	return c.params.AutoMigrate
+}
+
+// IgnorePatterns returns the default patterns of files and folders to skip when indexing and importing.
+// Additional patterns may be added to .ppignore files in each folder.
+func (c *Config) IgnorePatterns() (result []string) {
+	for _, p := range strings.Split(c.params.IgnorePatterns, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// TrashRetentionDays returns the number of days after which deleted photos are permanently removed
+// from the trash, 0 means they are kept until restored.
+func (c *Config) TrashRetentionDays() int {
+	if c.params.TrashRetentionDays < 0 {
+		return 0
+	}
+
+	return c.params.TrashRetentionDays
+}
+
 // DetectNSFW returns true if NSFW photos should be detected and flagged.
 func (c *Config) DetectNSFW() bool {
 	return c.params.DetectNSFW
@@ -169,6 +263,20 @@ func (c *Config) UploadNSFW() bool {
 	return c.params.UploadNSFW
 }
 
+// UploadLimit returns the max size of chunked uploads in MB, 0 means unlimited.
+func (c *Config) UploadLimit() int {
+	if c.params.UploadLimit < 0 {
+		return 0
+	}
+
+	return c.params.UploadLimit
+}
+
+// WriteMetadata returns true if metadata changes should be written back to originals or sidecar files.
+func (c *Config) WriteMetadata() bool {
+	return c.params.WriteMetadata
+}
+
 // AdminPassword returns the admin password.
 func (c *Config) AdminPassword() string {
 	if c.params.AdminPassword == "" {
@@ -183,6 +291,69 @@ func (c *Config) WebDAVPassword() string {
 	return c.params.WebDAVPassword
 }
 
+// WebDAVRole returns the role of WebDAV clients for album permissions, "admin" by default.
+func (c *Config) WebDAVRole() string {
+	if c.params.WebDAVRole == "" {
+		return "admin"
+	}
+
+	return strings.ToLower(c.params.WebDAVRole)
+}
+
+// SessionTimeout returns the time of inactivity after which users are logged out, 7 days by default.
+func (c *Config) SessionTimeout() time.Duration {
+	if c.params.SessionTimeout <= 0 {
+		return 168 * time.Hour
+	}
+
+	return time.Duration(c.params.SessionTimeout) * time.Second
+}
+
+// SessionMaxAge returns the max age of sessions, 30 days by default.
+func (c *Config) SessionMaxAge() time.Duration {
+	if c.params.SessionMaxAge <= 0 {
+		return 720 * time.Hour
+	}
+
+	return time.Duration(c.params.SessionMaxAge) * time.Second
+}
+
+// DownloadTokens returns true if thumbnails and downloads require a download token when not in public mode.
+func (c *Config) DownloadTokens() bool {
+	return c.params.DownloadTokens && !c.params.Public
+}
+
+// DownloadTokenTTL returns the time after which download tokens expire, 1 hour by default.
+func (c *Config) DownloadTokenTTL() time.Duration {
+	if c.params.DownloadTokenTTL <= 0 {
+		return time.Hour
+	}
+
+	return time.Duration(c.params.DownloadTokenTTL) * time.Second
+}
+
+// EventBuffer returns the number of recent events per topic kept for websocket clients that reconnect.
+func (c *Config) EventBuffer() int {
+	if c.params.EventBuffer < 0 {
+		return 0
+	}
+
+	if c.params.EventBuffer > 1000 {
+		return 1000
+	}
+
+	return c.params.EventBuffer
+}
+
+// LogBuffer returns the number of recent log entries kept in memory for the log viewer.
+func (c *Config) LogBuffer() int {
+	if c.params.LogBuffer <= 0 {
+		return 1000
+	}
+
+	return c.params.LogBuffer
+}
+
 // LogLevel returns the logrus log level.
 func (c *Config) LogLevel() logrus.Level {
 	if c.Debug() {
@@ -196,11 +367,26 @@ func (c *Config) LogLevel() logrus.Level {
 	}
 }
 
-// Cache returns the in-memory cache.
-func (c *Config) Cache() *gc.Cache {
-	if c.cache == nil {
-		c.cache = gc.New(336*time.Hour, 30*time.Minute)
+// CacheDriver returns the cache driver, either memory (default) or redis.
+func (c *Config) CacheDriver() string {
+	switch strings.ToLower(strings.TrimSpace(c.params.CacheDriver)) {
+	case cache.DriverRedis:
+		return cache.DriverRedis
+	default:
+		return cache.DriverMemory
 	}
+}
+
+// CacheDSN returns the cache data source name, for example the Redis server address.
+func (c *Config) CacheDSN() string {
+	return c.params.CacheDSN
+}
+
+// Cache returns the cache, which is in-memory unless another driver was configured.
+func (c *Config) Cache() cache.Cache {
+	c.cacheOnce.Do(func() {
+		c.cache = cache.New(c.CacheDriver(), c.CacheDSN(), 336*time.Hour, 30*time.Minute)
+	})
 
 	return c.cache
 }
@@ -216,6 +402,12 @@ func (c *Config) Shutdown() {
 	} else {
 		log.Info("closed database connection")
 	}
+
+	if closer, ok := c.cache.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Errorf("could not close cache connection: %s", err)
+		}
+	}
 }
 
 // Workers returns the number of workers e.g. for indexing files.
@@ -233,6 +425,16 @@ func (c *Config) Workers() int {
 	return 1
 }
 
+// IndexBatchSize returns the max number of photos of a folder written to the database in one transaction,
+// 100 by default. Batching is disabled if it's 1.
+func (c *Config) IndexBatchSize() int {
+	if c.params.IndexBatchSize <= 0 {
+		return 100
+	}
+
+	return c.params.IndexBatchSize
+}
+
 // WakeupInterval returns the background worker wakeup interval.
 func (c *Config) WakeupInterval() time.Duration {
 	if c.params.WakeupInterval <= 0 {
@@ -242,6 +444,20 @@ func (c *Config) WakeupInterval() time.Duration {
 	return time.Duration(c.params.WakeupInterval) * time.Second
 }
 
+// AutoIndexWatch returns true if originals should be watched for changes and indexed automatically.
+func (c *Config) AutoIndexWatch() bool {
+	return c.params.AutoIndexWatch
+}
+
+// AutoIndexSettle returns the time to wait after the last change in a folder before indexing it.
+func (c *Config) AutoIndexSettle() time.Duration {
+	if c.params.AutoIndexSettle <= 0 {
+		return 10 * time.Second
+	}
+
+	return time.Duration(c.params.AutoIndexSettle) * time.Second
+}
+
 // ThumbQuality returns the thumbnail jpeg quality setting (25-100).
 func (c *Config) ThumbQuality() int {
 	if c.params.ThumbQuality > 100 {
@@ -281,29 +497,207 @@ func (c *Config) ThumbLimit() int {
 	return c.params.ThumbLimit
 }
 
-// ThumbFilter returns the thumbnail resample filter (blackman, lanczos, cubic or linear).
+// OriginalsLimit returns the original file size limit in MB, 0 means unlimited.
+func (c *Config) OriginalsLimit() int {
+	if c.params.OriginalsLimit < 0 {
+		return 0
+	}
+
+	return c.params.OriginalsLimit
+}
+
+// ResolutionLimit returns the original image resolution limit in megapixels, 0 means unlimited.
+func (c *Config) ResolutionLimit() int {
+	if c.params.ResolutionLimit < 0 {
+		return 0
+	}
+
+	return c.params.ResolutionLimit
+}
+
+// ThumbConcurrency returns the max number of thumbnails rendered on demand at the same time.
+func (c *Config) ThumbConcurrency() int {
+	if c.params.ThumbConcurrency > 0 {
+		return c.params.ThumbConcurrency
+	}
+
+	return c.Workers()
+}
+
+// ThumbTimeout returns the max time a request waits for an on-demand thumbnail.
+func (c *Config) ThumbTimeout() time.Duration {
+	if c.params.ThumbTimeout <= 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(c.params.ThumbTimeout) * time.Second
+}
+
+// ThumbLimiter returns the limiter for rendering thumbnails on demand.
+func (c *Config) ThumbLimiter() *mutex.Limiter {
+	c.limiterOnce.Do(func() {
+		c.limiter = mutex.NewLimiter(c.ThumbConcurrency())
+	})
+
+	return c.limiter
+}
+
+// ThumbFilter returns the default thumbnail resample filter (blackman, lanczos, cubic or linear).
 func (c *Config) ThumbFilter() thumb.ResampleFilter {
-	switch strings.ToLower(c.params.ThumbFilter) {
-	case "blackman":
-		return thumb.ResampleBlackman
-	case "lanczos":
-		return thumb.ResampleLanczos
-	case "cubic":
-		return thumb.ResampleCubic
-	case "linear":
-		return thumb.ResampleLinear
-	default:
-		return thumb.ResampleCubic
+	if filter, _ := thumb.ParseFilters(c.params.ThumbFilter); filter != "" {
+		return filter
 	}
+
+	return thumb.ResampleCubic
 }
 
-// GeoCodingApi returns the preferred geo coding api (none, osm or places).
+// ThumbFilters returns resample filters for individual thumbnail types, e.g. "tile_224:lanczos".
+func (c *Config) ThumbFilters() map[string]thumb.ResampleFilter {
+	_, filters := thumb.ParseFilters(c.params.ThumbFilter)
+
+	return filters
+}
+
+// ThumbSharpen returns the sigma of the unsharp mask applied to thumbnails (0-5), 0 disables sharpening.
+func (c *Config) ThumbSharpen() float64 {
+	if c.params.ThumbSharpen > 5 {
+		return 5
+	}
+
+	if c.params.ThumbSharpen < 0 {
+		return 0
+	}
+
+	return c.params.ThumbSharpen
+}
+
+// DuplicateDistance returns the max perceptual hash distance of visually identical photos (0-16).
+func (c *Config) DuplicateDistance() int {
+	if c.params.DuplicateDistance > 16 {
+		return 16
+	}
+
+	if c.params.DuplicateDistance < 0 {
+		return 0
+	}
+
+	return c.params.DuplicateDistance
+}
+
+// DuplicateBurst returns the interval in which photos are considered burst shots instead of duplicates.
+func (c *Config) DuplicateBurst() time.Duration {
+	if c.params.DuplicateBurst <= 0 {
+		return 0
+	}
+
+	return time.Duration(c.params.DuplicateBurst) * time.Second
+}
+
+// ThumbFormat returns the thumbnail file format (jpg or webp).
+// JPEG is used as fallback if cwebp is not installed.
+func (c *Config) ThumbFormat() fs.FileType {
+	if strings.ToLower(c.params.ThumbFormat) == "webp" && c.CWebPBin() != "" {
+		return fs.TypeWebP
+	}
+
+	return fs.TypeJpeg
+}
+
+// ThumbUseEmbedded returns true if small thumbnails may be created from embedded preview images.
+func (c *Config) ThumbUseEmbedded() bool {
+	return c.params.ThumbUseEmbedded
+}
+
+// ThumbProgressive returns true if large jpeg thumbnails should be encoded as progressive jpeg.
+// Baseline encoding is used as fallback if jpegtran is not installed.
+func (c *Config) ThumbProgressive() bool {
+	return c.params.ThumbProgressive && c.JpegTranBin() != ""
+}
+
+// ThumbSmartCrop returns true if the crop window of centered fill thumbnails should be chosen based on the image content.
+func (c *Config) ThumbSmartCrop() bool {
+	return c.params.ThumbSmartCrop
+}
+
+// ThumbCopyright returns the copyright notice added to thumbnails.
+func (c *Config) ThumbCopyright() string {
+	return strings.TrimSpace(c.params.ThumbCopyright)
+}
+
+// ThumbColorManagement returns true if images with an embedded color profile should be converted to sRGB.
+func (c *Config) ThumbColorManagement() bool {
+	return c.params.ThumbColorMgmt
+}
+
+// FFmpegBitrate returns the max video bitrate in Mbit/s when transcoding to H.264.
+func (c *Config) FFmpegBitrate() int {
+	if c.params.FFmpegBitrate <= 0 {
+		return 50
+	}
+
+	return c.params.FFmpegBitrate
+}
+
+// FFmpegTimeout returns the max time a video transcode may take before ffmpeg is stopped.
+func (c *Config) FFmpegTimeout() time.Duration {
+	if c.params.FFmpegTimeout <= 0 {
+		return time.Hour
+	}
+
+	return time.Duration(c.params.FFmpegTimeout) * time.Second
+}
+
+// GeoCodingApi returns the preferred geo coding api (none, osm, places or offline).
 func (c *Config) GeoCodingApi() string {
 	switch c.params.GeoCodingApi {
 	case "places":
 		return "places"
 	case "osm":
 		return "osm"
+	case "offline":
+		return "offline"
 	}
 	return ""
 }
+
+// GeoCodingEndpoint returns the base url of the geocoding api, empty for the default.
+func (c *Config) GeoCodingEndpoint() string {
+	return strings.TrimSpace(c.params.GeoCodingEndpoint)
+}
+
+// PlacesUrl returns the base url of the places service, empty for the default.
+func (c *Config) PlacesUrl() string {
+	if u := strings.TrimSpace(c.params.PlacesUrl); u != "" {
+		return u
+	}
+
+	return c.GeoCodingEndpoint()
+}
+
+// GeoCodingTimeout returns the timeout of geocoding requests.
+func (c *Config) GeoCodingTimeout() time.Duration {
+	if c.params.GeoCodingTimeout <= 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(c.params.GeoCodingTimeout) * time.Second
+}
+
+// HttpProxy returns the proxy url for outbound requests, empty if the environment should be used.
+func (c *Config) HttpProxy() string {
+	return strings.TrimSpace(c.params.HttpProxy)
+}
+
+// UserAgent returns the user agent of outbound requests, including the version as required by the Nominatim usage policy.
+func (c *Config) UserAgent() string {
+	return fmt.Sprintf("%s/%s (+https://photoprism.org/)", c.Name(), c.Version())
+}
+
+// GeoCodingTTL returns the max age of cached reverse geocoding results, zero means no limit.
+func (c *Config) GeoCodingTTL() time.Duration {
+	if c.params.GeoCodingTTL < 0 {
+		return 0
+	}
+
+	return time.Duration(c.params.GeoCodingTTL) * 24 * time.Hour
+}
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	gc "github.com/patrickmn/go-cache"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/stretchr/testify/assert"
 )
@@ -151,6 +155,47 @@ func TestConfig_HttpServerPort(t *testing.T) {
 	assert.Equal(t, 2342, port)
 }
 
+func TestConfig_HttpServerSocket(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, "", c.HttpServerSocket())
+	assert.Equal(t, os.FileMode(0660), c.HttpSocketMode())
+	assert.True(t, c.HttpServerTcp())
+
+	c.params.HttpServerSocket = "/run/photoprism/http.sock"
+	c.params.HttpSocketMode = "0600"
+	c.params.HttpServerPort = 0
+
+	assert.Equal(t, "/run/photoprism/http.sock", c.HttpServerSocket())
+	assert.Equal(t, os.FileMode(0600), c.HttpSocketMode())
+	assert.False(t, c.HttpServerTcp())
+
+	// Both listeners are used if the port is set as well.
+	c.params.HttpServerPort = 2342
+	assert.True(t, c.HttpServerTcp())
+
+	c.params.HttpSocketMode = "rw"
+	assert.Equal(t, os.FileMode(0660), c.HttpSocketMode())
+}
+
+func TestConfig_HttpServerTls(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, "", c.HttpServerCert())
+	assert.Equal(t, "", c.HttpServerKey())
+	assert.False(t, c.HttpServerTls())
+
+	c.params.HttpServerCert = "/etc/photoprism/cert.pem"
+	assert.False(t, c.HttpServerTls())
+
+	c.params.HttpServerKey = "/etc/photoprism/key.pem"
+	assert.Equal(t, "/etc/photoprism/cert.pem", c.HttpServerCert())
+	assert.Equal(t, "/etc/photoprism/key.pem", c.HttpServerKey())
+	assert.True(t, c.HttpServerTls())
+}
+
 func TestConfig_HttpServerMode(t *testing.T) {
 	ctx := CliTestContext()
 	c := NewConfig(ctx)
@@ -167,6 +212,114 @@ func TestConfig_HttpServerPassword(t *testing.T) {
 	assert.Equal(t, "", password)
 }
 
+func TestConfig_PublicRole(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, "guest", c.PublicRole())
+}
+
+func TestConfig_WebDAVRole(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, "admin", c.WebDAVRole())
+}
+
+func TestConfig_AutoMigrate(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.False(t, c.AutoMigrate())
+
+	c.params.AutoMigrate = true
+
+	assert.True(t, c.AutoMigrate())
+}
+
+func TestConfig_checkMigrations(t *testing.T) {
+	c := TestConfig()
+
+	c.params.AutoMigrate = false
+	defer func() { c.params.AutoMigrate = true }()
+
+	assert.NoError(t, c.checkMigrations())
+}
+
+func TestConfig_SessionTimeout(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 168*time.Hour, c.SessionTimeout())
+
+	c.params.SessionTimeout = 3600
+	assert.Equal(t, time.Hour, c.SessionTimeout())
+
+	c.params.SessionTimeout = -1
+	assert.Equal(t, 168*time.Hour, c.SessionTimeout())
+}
+
+func TestConfig_EventBuffer(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	c.params.EventBuffer = 50
+	assert.Equal(t, 50, c.EventBuffer())
+
+	c.params.EventBuffer = -1
+	assert.Equal(t, 0, c.EventBuffer())
+
+	c.params.EventBuffer = 5000
+	assert.Equal(t, 1000, c.EventBuffer())
+}
+
+func TestConfig_CacheDriver(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, "memory", c.CacheDriver())
+	assert.Equal(t, "", c.CacheDSN())
+
+	c.params.CacheDriver = " Redis"
+	c.params.CacheDSN = "redis://localhost:6379/1"
+
+	assert.Equal(t, "redis", c.CacheDriver())
+	assert.Equal(t, "redis://localhost:6379/1", c.CacheDSN())
+
+	c.params.CacheDriver = "memcache"
+
+	assert.Equal(t, "memory", c.CacheDriver())
+}
+
+func TestConfig_Cache(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.IsType(t, &gc.Cache{}, c.Cache())
+	assert.Same(t, c.Cache(), c.Cache())
+}
+
+func TestConfig_LogBuffer(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	c.params.LogBuffer = 0
+	assert.Equal(t, 1000, c.LogBuffer())
+
+	c.params.LogBuffer = 200
+	assert.Equal(t, 200, c.LogBuffer())
+}
+
+func TestConfig_SessionMaxAge(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 720*time.Hour, c.SessionMaxAge())
+
+	c.params.SessionMaxAge = 86400
+	assert.Equal(t, 24*time.Hour, c.SessionMaxAge())
+}
+
 func TestConfig_OriginalsPath(t *testing.T) {
 	ctx := CliTestContext()
 	c := NewConfig(ctx)
@@ -185,6 +338,13 @@ func TestConfig_ImportPath(t *testing.T) {
 	assert.True(t, strings.HasSuffix(result, "assets/testdata/import"))
 }
 
+func TestConfig_UploadsPath(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, c.ImportPath()+"/.uploads", c.UploadsPath())
+}
+
 func TestConfig_SipsBin(t *testing.T) {
 	ctx := CliTestContext()
 	c := NewConfig(ctx)
@@ -209,6 +369,111 @@ func TestConfig_HeifConvertBin(t *testing.T) {
 	assert.Equal(t, "/usr/bin/heif-convert", bin)
 }
 
+func TestConfig_ThumbFormat(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, fs.TypeJpeg, c.ThumbFormat())
+
+	c.params.ThumbFormat = "webp"
+
+	if c.CWebPBin() == "" {
+		assert.Equal(t, fs.TypeJpeg, c.ThumbFormat())
+	} else {
+		assert.Equal(t, fs.TypeWebP, c.ThumbFormat())
+	}
+}
+
+func TestConfig_ThumbConcurrency(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, c.Workers(), c.ThumbConcurrency())
+
+	c.params.ThumbConcurrency = 2
+	assert.Equal(t, 2, c.ThumbConcurrency())
+	assert.Equal(t, 2, c.ThumbLimiter().Size())
+}
+
+func TestConfig_OriginalsLimit(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 0, c.OriginalsLimit())
+
+	c.params.OriginalsLimit = 800
+	assert.Equal(t, 800, c.OriginalsLimit())
+
+	c.params.OriginalsLimit = -1
+	assert.Equal(t, 0, c.OriginalsLimit())
+}
+
+func TestConfig_UploadLimit(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 0, c.UploadLimit())
+
+	c.params.UploadLimit = 4096
+	assert.Equal(t, 4096, c.UploadLimit())
+
+	c.params.UploadLimit = -1
+	assert.Equal(t, 0, c.UploadLimit())
+}
+
+func TestConfig_ResolutionLimit(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 0, c.ResolutionLimit())
+
+	c.params.ResolutionLimit = 100
+	assert.Equal(t, 100, c.ResolutionLimit())
+
+	c.params.ResolutionLimit = -5
+	assert.Equal(t, 0, c.ResolutionLimit())
+}
+
+func TestConfig_ThumbTimeout(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 30*time.Second, c.ThumbTimeout())
+
+	c.params.ThumbTimeout = 5
+	assert.Equal(t, 5*time.Second, c.ThumbTimeout())
+}
+
+func TestConfig_FFmpegTimeout(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, time.Hour, c.FFmpegTimeout())
+
+	c.params.FFmpegTimeout = 120
+	assert.Equal(t, 2*time.Minute, c.FFmpegTimeout())
+}
+
+func TestConfig_ThumbColorManagement(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.False(t, c.ThumbColorManagement())
+
+	c.params.ThumbColorMgmt = true
+	assert.True(t, c.ThumbColorManagement())
+}
+
+func TestConfig_ThumbSmartCrop(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.False(t, c.ThumbSmartCrop())
+
+	c.params.ThumbSmartCrop = true
+	assert.True(t, c.ThumbSmartCrop())
+}
+
 func TestConfig_ExifToolBin(t *testing.T) {
 	ctx := CliTestContext()
 	c := NewConfig(ctx)
@@ -217,6 +482,105 @@ func TestConfig_ExifToolBin(t *testing.T) {
 	assert.Equal(t, "/usr/bin/exiftool", bin)
 }
 
+func TestConfig_IgnorePatterns(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	c.params.IgnorePatterns = "@eaDir/, .*,,*.tmp "
+	assert.Equal(t, []string{"@eaDir/", ".*", "*.tmp"}, c.IgnorePatterns())
+
+	c.params.IgnorePatterns = ""
+	assert.Empty(t, c.IgnorePatterns())
+}
+
+func TestConfig_FFmpegBitrate(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 50, c.FFmpegBitrate())
+
+	c.params.FFmpegBitrate = 8
+	assert.Equal(t, 8, c.FFmpegBitrate())
+
+	c.params.FFmpegBitrate = -1
+	assert.Equal(t, 50, c.FFmpegBitrate())
+}
+
+func TestConfig_AutoIndexSettle(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.False(t, c.AutoIndexWatch())
+	assert.Equal(t, 10*time.Second, c.AutoIndexSettle())
+
+	c.params.AutoIndexSettle = 2
+	assert.Equal(t, 2*time.Second, c.AutoIndexSettle())
+
+	c.params.AutoIndexSettle = -1
+	assert.Equal(t, 10*time.Second, c.AutoIndexSettle())
+}
+
+func TestConfig_GeoCodingApi(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	c.params.GeoCodingApi = "offline"
+	assert.Equal(t, "offline", c.GeoCodingApi())
+
+	c.params.GeoCodingApi = "foo"
+	assert.Equal(t, "", c.GeoCodingApi())
+}
+
+func TestConfig_GeoCodingTTL(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	c.params.GeoCodingTTL = 7
+	assert.Equal(t, 7*24*time.Hour, c.GeoCodingTTL())
+
+	c.params.GeoCodingTTL = -1
+	assert.Equal(t, time.Duration(0), c.GeoCodingTTL())
+}
+
+func TestConfig_GeoCodingEndpoint(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, "", c.GeoCodingEndpoint())
+	assert.Equal(t, 30*time.Second, c.GeoCodingTimeout())
+	assert.Equal(t, "", c.HttpProxy())
+
+	c.params.GeoCodingEndpoint = " http://nominatim.local "
+	c.params.GeoCodingTimeout = 5
+	c.params.HttpProxy = "http://proxy:3128"
+
+	assert.Equal(t, "http://nominatim.local", c.GeoCodingEndpoint())
+	assert.Equal(t, 5*time.Second, c.GeoCodingTimeout())
+	assert.Equal(t, "http://proxy:3128", c.HttpProxy())
+}
+
+func TestConfig_PlacesUrl(t *testing.T) {
+	c := &Config{params: &Params{}}
+
+	assert.Equal(t, "", c.PlacesUrl())
+
+	c.params.GeoCodingEndpoint = "http://geocoding.local"
+	assert.Equal(t, "http://geocoding.local", c.PlacesUrl())
+
+	c.params.PlacesUrl = " http://places.local "
+	assert.Equal(t, "http://places.local", c.PlacesUrl())
+}
+
+func TestConfig_UserAgent(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	c.params.Name = "PhotoPrism"
+	c.params.Version = "200501-Test"
+
+	assert.Equal(t, "PhotoPrism/200501-Test (+https://photoprism.org/)", c.UserAgent())
+}
+
 func TestConfig_DatabaseDriver(t *testing.T) {
 	ctx := CliTestContext()
 	c := NewConfig(ctx)
@@ -240,6 +604,26 @@ func TestConfig_CachePath(t *testing.T) {
 	assert.True(t, strings.HasSuffix(c.CachePath(), "assets/testdata/cache"))
 }
 
+func TestConfig_TrashPath(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, filepath.Join(c.OriginalsPath(), ".trash"), c.TrashPath())
+}
+
+func TestConfig_TrashRetentionDays(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	assert.Equal(t, 0, c.TrashRetentionDays())
+
+	c.params.TrashRetentionDays = 30
+	assert.Equal(t, 30, c.TrashRetentionDays())
+
+	c.params.TrashRetentionDays = -1
+	assert.Equal(t, 0, c.TrashRetentionDays())
+}
+
 func TestConfig_ThumbnailsPath(t *testing.T) {
 	ctx := CliTestContext()
 	c := NewConfig(ctx)
@@ -373,3 +757,9 @@ func TestConfig_Workers(t *testing.T) {
 
 	assert.GreaterOrEqual(t, c.Workers(), 1)
 }
+
+func TestConfig_IndexBatchSize(t *testing.T) {
+	assert.Equal(t, 100, (&Config{params: &Params{}}).IndexBatchSize())
+	assert.Equal(t, 1, (&Config{params: &Params{IndexBatchSize: 1}}).IndexBatchSize())
+	assert.Equal(t, 500, (&Config{params: &Params{IndexBatchSize: 500}}).IndexBatchSize())
+}
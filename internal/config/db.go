@@ -56,37 +56,49 @@ func (c *Config) CloseDb() error {
 	return nil
 }
 
-// MigrateDb will start a migration process.
-func (c *Config) MigrateDb() {
+// MigrateDb applies pending schema migrations and creates default entities.
+func (c *Config) MigrateDb() error {
 	db := c.Db()
 
-	db.AutoMigrate(
-		&entity.Account{},
-		&entity.File{},
-		&entity.FileShare{},
-		&entity.FileSync{},
-		&entity.Photo{},
-		&entity.Description{},
-		&entity.Event{},
-		&entity.Place{},
-		&entity.Location{},
-		&entity.Camera{},
-		&entity.Lens{},
-		&entity.Country{},
-		&entity.Album{},
-		&entity.PhotoAlbum{},
-		&entity.Label{},
-		&entity.Category{},
-		&entity.PhotoLabel{},
-		&entity.Keyword{},
-		&entity.PhotoKeyword{},
-		&entity.Link{},
-	)
+	if _, err := entity.Migrate(db, false); err != nil {
+		log.Errorf("config: %s", err)
+		return err
+	}
 
 	entity.CreateUnknownPlace(db)
 	entity.CreateUnknownCountry(db)
 	entity.CreateUnknownCamera(db)
 	entity.CreateUnknownLens(db)
+	entity.CreateDefaultUsers(db)
+
+	c.importSettings()
+
+	return nil
+}
+
+// checkMigrations returns an error if there are pending migrations for an existing database,
+// unless they should be applied automatically. Empty databases are always initialized, and so are
+// databases without migrations table, as their schema was updated on every start before.
+func (c *Config) checkMigrations() error {
+	db := c.Db()
+
+	if c.AutoMigrate() || !db.HasTable(&entity.Photo{}) || !db.HasTable(&entity.Migration{}) {
+		return nil
+	}
+
+	pending, err := entity.PendingMigrations(db)
+
+	if err != nil {
+		return err
+	} else if len(pending) > 0 {
+		for _, step := range pending {
+			log.Warnf("config: pending database migration %d (%s)", step.ID, step.Name)
+		}
+
+		return ErrMigrations
+	}
+
+	return nil
 }
 
 // DropTables drops all tables in the currently configured database (be careful!).
@@ -99,28 +111,7 @@ func (c *Config) DropTables() {
 	db.SetLogger(log)
 	db.LogMode(false)
 
-	db.DropTableIfExists(
-		&entity.Account{},
-		&entity.File{},
-		&entity.FileShare{},
-		&entity.FileSync{},
-		&entity.Photo{},
-		&entity.Description{},
-		&entity.Event{},
-		&entity.Place{},
-		&entity.Location{},
-		&entity.Camera{},
-		&entity.Lens{},
-		&entity.Country{},
-		&entity.Album{},
-		&entity.PhotoAlbum{},
-		&entity.Label{},
-		&entity.Category{},
-		&entity.PhotoLabel{},
-		&entity.Keyword{},
-		&entity.PhotoKeyword{},
-		&entity.Link{},
-	)
+	db.DropTableIfExists(entity.Entities...)
 
 	log.SetLevel(logLevel)
 }
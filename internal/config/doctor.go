@@ -0,0 +1,504 @@
+package config
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Setup check result status.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// Free disk space thresholds in bytes.
+const (
+	DiskSpaceWarn uint64 = 5 * 1024 * 1024 * 1024
+	DiskSpaceFail uint64 = 1024 * 1024 * 1024
+)
+
+// Maximum clock offset compared to the database server.
+const (
+	ClockOffsetWarn = time.Minute
+	ClockOffsetFail = 5 * time.Minute
+)
+
+// Remaining validity of the TLS certificate before a warning is shown.
+const CertExpiryWarn = 30 * 24 * time.Hour
+
+// CheckResult represents the outcome of a single setup check.
+type CheckResult struct {
+	Check   string `json:"check"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// CheckResults is a list of setup check results.
+type CheckResults []CheckResult
+
+// Failed returns the number of failed checks.
+func (r CheckResults) Failed() (count int) {
+	for _, res := range r {
+		if res.Status == CheckFail {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Warnings returns the number of checks with warnings.
+func (r CheckResults) Warnings() (count int) {
+	for _, res := range r {
+		if res.Status == CheckWarn {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Checks lists the names of all setup checks in the order they are performed.
+var Checks = []string{"paths", "tools", "database", "disk", "tls", "password", "clock"}
+
+// Doctor performs all setup checks that are not skipped and returns the results.
+func (c *Config) Doctor(skip ...string) (results CheckResults) {
+	skipped := make(map[string]bool)
+
+	for _, s := range skip {
+		for _, name := range strings.Split(s, ",") {
+			skipped[strings.ToLower(strings.TrimSpace(name))] = true
+		}
+	}
+
+	for _, name := range Checks {
+		if skipped[name] {
+			continue
+		}
+
+		switch name {
+		case "paths":
+			results = append(results, c.checkPaths()...)
+		case "tools":
+			results = append(results, c.checkTools()...)
+		case "database":
+			results = append(results, c.checkDatabase()...)
+		case "disk":
+			results = append(results, c.checkDisk()...)
+		case "tls":
+			results = append(results, c.checkTls()...)
+		case "password":
+			results = append(results, c.checkPassword()...)
+		case "clock":
+			results = append(results, c.checkClock()...)
+		}
+	}
+
+	return results
+}
+
+// checkPaths verifies that storage paths exist and have the required permissions.
+func (c *Config) checkPaths() (results CheckResults) {
+	type checkPath struct {
+		name     string
+		path     string
+		writable bool
+	}
+
+	paths := []checkPath{{"originals", c.OriginalsPath(), !c.ReadOnly()}}
+
+	for _, root := range c.originalsRootsExtra() {
+		paths = append(paths, checkPath{"originals:" + root.Name, root.Path, !c.ReadOnly()})
+	}
+
+	paths = append(paths,
+		checkPath{"import", c.ImportPath(), !c.ReadOnly()},
+		checkPath{"cache", c.CachePath(), true},
+		checkPath{"temp", c.TempPath(), true},
+	)
+
+	for _, p := range paths {
+		res := CheckResult{Check: "paths", Name: p.name, Status: CheckPass}
+
+		if p.path == "" {
+			res.Status = CheckFail
+			res.Message = "path not configured"
+			res.Hint = fmt.Sprintf("set --%s-path or PHOTOPRISM_%s_PATH", p.name, strings.ToUpper(p.name))
+		} else if !fs.PathExists(p.path) {
+			res.Status = CheckFail
+			res.Message = fmt.Sprintf("%s does not exist", p.path)
+			res.Hint = "create the directory or check your configuration"
+		} else if _, err := ioutil.ReadDir(p.path); err != nil {
+			res.Status = CheckFail
+			res.Message = fmt.Sprintf("%s is not readable", p.path)
+			res.Hint = fmt.Sprintf("run: chmod -R u+rX %s", p.path)
+		} else if p.writable && !writable(p.path) {
+			res.Status = CheckFail
+			res.Message = fmt.Sprintf("%s is not writable", p.path)
+			res.Hint = fmt.Sprintf("run: chown -R %d %s", os.Getuid(), p.path)
+		} else if uid, ok := fileOwner(p.path); ok && uid != os.Getuid() {
+			res.Status = CheckWarn
+			res.Message = fmt.Sprintf("%s is owned by uid %d, running as uid %d", p.path, uid, os.Getuid())
+			res.Hint = "files created by photoprism may not be accessible to the owner"
+		} else {
+			res.Message = p.path
+		}
+
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// writable tests if files can be created in a directory.
+func writable(dir string) bool {
+	f, err := ioutil.TempFile(dir, ".doctor")
+
+	if err != nil {
+		return false
+	}
+
+	f.Close()
+	os.Remove(f.Name())
+
+	return true
+}
+
+// externalTool represents an external binary, the argument that prints its version and an installation hint.
+type externalTool struct {
+	name    string
+	bin     string
+	version string
+	hint    string
+}
+
+// externalTools returns the external binaries used for converting and reading files.
+func (c *Config) externalTools() []externalTool {
+	return []externalTool{
+		{"exiftool", c.ExifToolBin(), "-ver", "install exiftool to read metadata from videos and sidecar files"},
+		{"darktable-cli", c.DarktableBin(), "--version", "install darktable to convert RAW files"},
+		{"rawtherapee-cli", c.RawTherapeeBin(), "-v", "install rawtherapee to convert RAW files"},
+		{"heif-convert", c.HeifConvertBin(), "", "install libheif-examples to convert HEIF images"},
+		{"cwebp", c.CWebPBin(), "-version", "install webp to create thumbnails in WebP format"},
+		{"jpegtran", c.JpegTranBin(), "", "install libjpeg-turbo-progs to create progressive thumbnails"},
+		{"ffmpeg", c.FFmpegBin(), "-version", "install ffmpeg to play videos that are not H.264 encoded"},
+	}
+}
+
+// toolVersion runs a binary with the given argument and returns the first line of its output.
+var toolVersion = func(bin, arg string) (string, error) {
+	out, err := exec.Command(bin, arg).Output()
+
+	return firstLine(string(out)), err
+}
+
+// checkTools verifies that external tools are installed and returns their versions.
+func (c *Config) checkTools() (results CheckResults) {
+	tools := c.externalTools()
+
+	for _, t := range tools {
+		res := CheckResult{Check: "tools", Name: t.name, Status: CheckPass}
+
+		if t.bin == "" {
+			res.Status = CheckWarn
+			res.Message = "not found"
+			res.Hint = t.hint
+		} else if t.version == "" {
+			res.Message = t.bin
+		} else if version, err := toolVersion(t.bin, t.version); err != nil {
+			res.Status = CheckWarn
+			res.Message = fmt.Sprintf("%s failed: %s", t.bin, err)
+			res.Hint = "check if the installed version works on this system"
+		} else {
+			res.Message = fmt.Sprintf("%s %s", t.bin, version)
+		}
+
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// firstLine returns the first non-empty line of a command output.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// doctorDb returns the current database connection or tries to open a new one.
+func (c *Config) doctorDb() (db *gorm.DB, err error) {
+	if c.db != nil {
+		return c.db, nil
+	}
+
+	driver := c.DatabaseDriver()
+
+	if driver == DbTiDB {
+		driver = DbMySQL
+	}
+
+	return gorm.Open(driver, c.DatabaseDsn())
+}
+
+// checkDatabase verifies the database connection, version and character set.
+func (c *Config) checkDatabase() (results CheckResults) {
+	res := CheckResult{Check: "database", Name: "connection", Status: CheckPass}
+
+	db, err := c.doctorDb()
+
+	if err != nil {
+		res.Status = CheckFail
+		res.Message = fmt.Sprintf("can't connect to %s database", c.DatabaseDriver())
+		res.Hint = "check --database-dsn and make sure the database server is running"
+
+		if c.DatabaseDriver() == DbTiDB {
+			res.Status = CheckWarn
+			res.Hint = "the built-in database server only runs while photoprism is started"
+		}
+
+		return append(results, res)
+	}
+
+	if db != c.db {
+		defer db.Close()
+	}
+
+	if db.Dialect().GetName() != DbMySQL {
+		res.Message = db.Dialect().GetName()
+		return append(results, res)
+	}
+
+	var version struct {
+		Version string
+	}
+
+	if err := db.Raw("SELECT VERSION() AS version").Scan(&version).Error; err != nil {
+		res.Status = CheckFail
+		res.Message = err.Error()
+		return append(results, res)
+	}
+
+	res.Message = fmt.Sprintf("%s %s", c.DatabaseDriver(), version.Version)
+	results = append(results, res)
+
+	var charset struct {
+		Charset   string
+		Collation string
+	}
+
+	res = CheckResult{Check: "database", Name: "charset", Status: CheckPass}
+
+	if err := db.Raw("SELECT @@character_set_database AS charset, @@collation_database AS collation").Scan(&charset).Error; err != nil {
+		res.Status = CheckWarn
+		res.Message = err.Error()
+	} else {
+		res.Message = fmt.Sprintf("%s (%s)", charset.Charset, charset.Collation)
+
+		switch {
+		case charset.Charset == "utf8mb4":
+		case strings.HasPrefix(charset.Charset, "utf8"):
+			res.Status = CheckWarn
+			res.Hint = "4-byte characters like emojis can't be stored, run: ALTER DATABASE photoprism CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
+		default:
+			res.Status = CheckFail
+			res.Hint = "run: ALTER DATABASE photoprism CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
+		}
+	}
+
+	return append(results, res)
+}
+
+// checkDisk verifies that there is enough free disk space for originals and cache files.
+func (c *Config) checkDisk() (results CheckResults) {
+	type checkPath struct {
+		name string
+		path string
+	}
+
+	paths := []checkPath{{"originals", c.OriginalsPath()}}
+
+	for _, root := range c.originalsRootsExtra() {
+		paths = append(paths, checkPath{"originals:" + root.Name, root.Path})
+	}
+
+	paths = append(paths, checkPath{"cache", c.CachePath()})
+
+	for _, p := range paths {
+		res := CheckResult{Check: "disk", Name: p.name, Status: CheckPass}
+
+		free, err := diskFree(p.path)
+
+		if err != nil {
+			res.Status = CheckWarn
+			res.Message = fmt.Sprintf("free space unknown: %s", err)
+		} else {
+			res.Message = fmt.Sprintf("%d MB free in %s", free/1024/1024, p.path)
+
+			if free < DiskSpaceFail {
+				res.Status = CheckFail
+				res.Hint = "free up disk space or move the storage path to a larger volume"
+			} else if free < DiskSpaceWarn {
+				res.Status = CheckWarn
+				res.Hint = "less than 5 GB free, thumbnails and imports may fail soon"
+			}
+		}
+
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// checkTls verifies that the configured TLS certificate can be read and is not about to expire.
+func (c *Config) checkTls() (results CheckResults) {
+	res := CheckResult{Check: "tls", Name: "certificate", Status: CheckPass}
+
+	certFile := c.HttpServerCert()
+
+	if certFile == "" {
+		res.Message = "not configured"
+		return append(results, res)
+	}
+
+	cert, err := readCert(certFile)
+
+	if err != nil {
+		res.Status = CheckFail
+		res.Message = fmt.Sprintf("%s: %s", certFile, err)
+		res.Hint = "check --http-cert, the file must contain a PEM encoded certificate"
+		return append(results, res)
+	}
+
+	remaining := time.Until(cert.NotAfter)
+
+	if remaining <= 0 {
+		res.Status = CheckFail
+		res.Message = fmt.Sprintf("%s expired on %s", certFile, cert.NotAfter.UTC().Format(time.RFC3339))
+		res.Hint = "renew the certificate and restart photoprism"
+	} else if remaining < CertExpiryWarn {
+		res.Status = CheckWarn
+		res.Message = fmt.Sprintf("%s expires in %d days", certFile, remaining/(24*time.Hour))
+		res.Hint = "renew the certificate and restart photoprism"
+	} else {
+		res.Message = fmt.Sprintf("%s valid until %s", certFile, cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	if res.Status == CheckPass && c.HttpServerKey() == "" {
+		res.Status = CheckWarn
+		res.Hint = "set --http-key as well, https is disabled without a private key"
+	}
+
+	return append(results, res)
+}
+
+// readCert returns the first certificate of a PEM encoded file.
+func readCert(fileName string) (*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var block *pem.Block
+
+		if block, data = pem.Decode(data); block == nil {
+			return nil, fmt.Errorf("no certificate found")
+		} else if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// checkPassword verifies that the default admin password was changed.
+func (c *Config) checkPassword() (results CheckResults) {
+	res := CheckResult{Check: "password", Name: "admin", Status: CheckPass, Message: "custom password"}
+
+	if c.SetupRequired() {
+		res.Status = CheckWarn
+		res.Message = "setup required"
+		res.Hint = "complete the setup in the web interface or set --admin-password"
+	} else if c.Public() {
+		res.Message = "public mode, authentication disabled"
+	} else if c.CheckPassword("photoprism") {
+		res.Status = CheckWarn
+		res.Message = "default password in use"
+		res.Hint = "set a new password with --admin-password or PHOTOPRISM_ADMIN_PASSWORD"
+	}
+
+	return append(results, res)
+}
+
+// checkClock compares the local time with the database server time.
+func (c *Config) checkClock() (results CheckResults) {
+	res := CheckResult{Check: "clock", Name: "time", Status: CheckPass}
+
+	now := time.Now().UTC()
+
+	if now.Year() < 2020 {
+		res.Status = CheckFail
+		res.Message = fmt.Sprintf("system time is %s", now.Format(time.RFC3339))
+		res.Hint = "enable time synchronization (NTP) on this system"
+
+		return append(results, res)
+	}
+
+	res.Message = now.Format(time.RFC3339)
+
+	db, err := c.doctorDb()
+
+	if err != nil {
+		return append(results, res)
+	}
+
+	if db != c.db {
+		defer db.Close()
+	}
+
+	if db.Dialect().GetName() != DbMySQL {
+		return append(results, res)
+	}
+
+	var server struct {
+		Unix int64
+	}
+
+	if err := db.Raw("SELECT UNIX_TIMESTAMP() AS unix").Scan(&server).Error; err != nil {
+		return append(results, res)
+	}
+
+	offset := time.Since(time.Unix(server.Unix, 0))
+
+	if offset < 0 {
+		offset = -offset
+	}
+
+	res.Message = fmt.Sprintf("%s, %s offset to database server", now.Format(time.RFC3339), offset.Round(time.Second))
+
+	if offset > ClockOffsetFail {
+		res.Status = CheckFail
+		res.Hint = "enable time synchronization (NTP) on this system and the database server"
+	} else if offset > ClockOffsetWarn {
+		res.Status = CheckWarn
+		res.Hint = "enable time synchronization (NTP) on this system and the database server"
+	}
+
+	return append(results, res)
+}
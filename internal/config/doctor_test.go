@@ -0,0 +1,146 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Doctor(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	t.Run("skip", func(t *testing.T) {
+		results := c.Doctor("database,clock", "disk")
+
+		for _, res := range results {
+			assert.NotEqual(t, "database", res.Check)
+			assert.NotEqual(t, "clock", res.Check)
+			assert.NotEqual(t, "disk", res.Check)
+		}
+
+		assert.Equal(t, "paths", results[0].Check)
+	})
+
+	t.Run("password", func(t *testing.T) {
+		results := c.Doctor("paths", "tools", "database", "disk", "tls", "clock")
+
+		assert.Len(t, results, 1)
+		assert.Equal(t, "password", results[0].Check)
+	})
+}
+
+// writeTestCert creates a self-signed certificate that expires at the given time.
+func writeTestCert(t *testing.T, dir string, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "photoprism.test"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tpl, &tpl, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(dir, "cert.pem")
+
+	if err := ioutil.WriteFile(fileName, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func TestConfig_checkTls(t *testing.T) {
+	ctx := CliTestContext()
+	c := NewConfig(ctx)
+
+	dir, err := ioutil.TempDir("", "doctor-tls")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	t.Run("not configured", func(t *testing.T) {
+		results := c.checkTls()
+
+		assert.Len(t, results, 1)
+		assert.Equal(t, CheckPass, results[0].Status)
+		assert.Equal(t, "not configured", results[0].Message)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		c.params.HttpServerCert = writeTestCert(t, dir, time.Now().Add(90*24*time.Hour))
+		c.params.HttpServerKey = filepath.Join(dir, "key.pem")
+
+		results := c.checkTls()
+
+		assert.Equal(t, CheckPass, results[0].Status)
+		assert.Contains(t, results[0].Message, "valid until")
+	})
+
+	t.Run("expires soon", func(t *testing.T) {
+		c.params.HttpServerCert = writeTestCert(t, dir, time.Now().Add(10*24*time.Hour))
+
+		results := c.checkTls()
+
+		assert.Equal(t, CheckWarn, results[0].Status)
+		assert.Contains(t, results[0].Message, "expires in 9 days")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c.params.HttpServerCert = writeTestCert(t, dir, time.Now().Add(-time.Hour))
+
+		results := c.checkTls()
+
+		assert.Equal(t, CheckFail, results[0].Status)
+		assert.Contains(t, results[0].Message, "expired on")
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		c.params.HttpServerCert = c.params.HttpServerKey
+
+		results := c.checkTls()
+
+		assert.Equal(t, CheckFail, results[0].Status)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		for _, res := range c.Doctor("paths", "tools", "database", "disk", "tls", "password", "clock") {
+			assert.NotEqual(t, "tls", res.Check)
+		}
+	})
+}
+
+func TestCheckResults_Failed(t *testing.T) {
+	results := CheckResults{
+		{Check: "paths", Name: "originals", Status: CheckPass},
+		{Check: "tools", Name: "exiftool", Status: CheckWarn},
+		{Check: "disk", Name: "cache", Status: CheckFail},
+	}
+
+	assert.Equal(t, 1, results.Failed())
+	assert.Equal(t, 1, results.Warnings())
+}
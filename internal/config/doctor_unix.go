@@ -0,0 +1,34 @@
+// +build !windows
+
+package config
+
+import (
+	"os"
+	"syscall"
+)
+
+// diskFree returns the number of bytes available to unprivileged users on the volume containing path.
+func diskFree(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
+
+// fileOwner returns the uid of the file owner, if available.
+func fileOwner(path string) (int, bool) {
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return 0, false
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,15 @@
+package config
+
+import (
+	"errors"
+)
+
+// diskFree is not implemented on Windows.
+func diskFree(path string) (uint64, error) {
+	return 0, errors.New("not supported on windows")
+}
+
+// fileOwner is not implemented on Windows.
+func fileOwner(path string) (int, bool) {
+	return 0, false
+}
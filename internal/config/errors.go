@@ -6,7 +6,11 @@ import (
 
 // Define photoprism specific errors
 var (
-	ErrReadOnly     = errors.New("not available in read-only mode")
-	ErrUnauthorized = errors.New("please log in and try again")
-	ErrUploadNSFW   = errors.New("upload might be offensive")
+	ErrReadOnly       = errors.New("not available in read-only mode")
+	ErrUnauthorized   = errors.New("please log in and try again")
+	ErrUploadNSFW     = errors.New("upload might be offensive")
+	ErrInvalidLogin   = errors.New("invalid user name or password")
+	ErrMigrations     = errors.New("database schema is outdated, run \"photoprism migrate\" once to upgrade it, or start with --auto-migrate")
+	ErrSetupCompleted = errors.New("setup already completed")
+	ErrDbNotConnected = errors.New("database not connected, please try again")
 )
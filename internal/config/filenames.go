@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
@@ -41,8 +42,10 @@ func (c *Config) CreateDirectories() error {
 		return result
 	}
 
-	if err := os.MkdirAll(c.OriginalsPath(), os.ModePerm); err != nil {
-		return createError(c.OriginalsPath(), err)
+	for _, root := range c.OriginalsRoots() {
+		if err := os.MkdirAll(root.Path, os.ModePerm); err != nil {
+			return createError(root.Path, err)
+		}
 	}
 
 	if err := os.MkdirAll(c.ImportPath(), os.ModePerm); err != nil {
@@ -94,6 +97,11 @@ func (c *Config) SettingsFile() string {
 	return c.ConfigPath() + "/settings.yml"
 }
 
+// OptionsFile returns the name of the file containing options saved by the setup wizard.
+func (c *Config) OptionsFile() string {
+	return c.ConfigPath() + "/options.yml"
+}
+
 // ConfigPath returns the config path.
 func (c *Config) ConfigPath() string {
 	if c.params.ConfigPath == "" {
@@ -121,9 +129,9 @@ func (c *Config) LogFilename() string {
 	return fs.Abs(c.params.LogFilename)
 }
 
-// OriginalsPath returns the originals.
+// OriginalsPath returns the default originals root, see OriginalsRoots for all roots.
 func (c *Config) OriginalsPath() string {
-	return fs.Abs(c.params.OriginalsPath)
+	return c.OriginalsRootPath(entity.RootDefault)
 }
 
 // ImportPath returns the import directory.
@@ -131,6 +139,25 @@ func (c *Config) ImportPath() string {
 	return fs.Abs(c.params.ImportPath)
 }
 
+// UploadsPath returns the path to unfinished chunked uploads, a hidden folder in the import path.
+func (c *Config) UploadsPath() string {
+	return filepath.Join(c.ImportPath(), ".uploads")
+}
+
+// SidecarPath returns the storage path for XMP sidecar files.
+func (c *Config) SidecarPath() string {
+	if c.params.SidecarPath == "" {
+		return c.CachePath() + "/sidecar"
+	}
+
+	return fs.Abs(c.params.SidecarPath)
+}
+
+// TrashPath returns the storage path for files of deleted photos, relative paths in originals are preserved.
+func (c *Config) TrashPath() string {
+	return filepath.Join(c.OriginalsPath(), ".trash")
+}
+
 // SipsBin returns the sips binary file name.
 func (c *Config) SipsBin() string {
 	return findExecutable(c.params.SipsBin, "sips")
@@ -141,11 +168,31 @@ func (c *Config) DarktableBin() string {
 	return findExecutable(c.params.DarktableBin, "darktable-cli")
 }
 
+// RawTherapeeBin returns the rawtherapee-cli binary file name.
+func (c *Config) RawTherapeeBin() string {
+	return findExecutable(c.params.RawTherapeeBin, "rawtherapee-cli")
+}
+
 // HeifConvertBin returns the heif-convert binary file name.
 func (c *Config) HeifConvertBin() string {
 	return findExecutable(c.params.HeifConvertBin, "heif-convert")
 }
 
+// CWebPBin returns the cwebp binary file name.
+func (c *Config) CWebPBin() string {
+	return findExecutable(c.params.CWebPBin, "cwebp")
+}
+
+// JpegTranBin returns the jpegtran binary file name.
+func (c *Config) JpegTranBin() string {
+	return findExecutable(c.params.JpegTranBin, "jpegtran")
+}
+
+// FFmpegBin returns the ffmpeg binary file name.
+func (c *Config) FFmpegBin() string {
+	return findExecutable(c.params.FFmpegBin, "ffmpeg")
+}
+
 // ExifToolBin returns the exiftool binary file name.
 func (c *Config) ExifToolBin() string {
 	return findExecutable(c.params.ExifToolBin, "exiftool")
@@ -8,8 +8,7 @@ import (
 var GlobalFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:   "admin-password",
-		Usage:  "admin password",
-		Value:  "photoprism",
+		Usage:  "admin password, setup is required on first start if empty",
 		EnvVar: "PHOTOPRISM_ADMIN_PASSWORD",
 	},
 	cli.StringFlag{
@@ -18,6 +17,58 @@ var GlobalFlags = []cli.Flag{
 		Value:  "",
 		EnvVar: "PHOTOPRISM_WEBDAV_PASSWORD",
 	},
+	cli.StringFlag{
+		Name:   "webdav-role",
+		Usage:  "role of WebDAV clients, files only in albums hidden from this role are not shown",
+		Value:  "admin",
+		EnvVar: "PHOTOPRISM_WEBDAV_ROLE",
+	},
+	cli.IntFlag{
+		Name:   "session-timeout",
+		Usage:  "seconds of inactivity after which users are logged out",
+		Value:  604800,
+		EnvVar: "PHOTOPRISM_SESSION_TIMEOUT",
+	},
+	cli.IntFlag{
+		Name:   "session-maxage",
+		Usage:  "max session age in seconds, users must log in again afterwards",
+		Value:  2592000,
+		EnvVar: "PHOTOPRISM_SESSION_MAXAGE",
+	},
+	cli.BoolFlag{
+		Name:   "download-tokens",
+		Usage:  "require short-lived signed tokens for thumbnails and downloads if not public",
+		EnvVar: "PHOTOPRISM_DOWNLOAD_TOKENS",
+	},
+	cli.IntFlag{
+		Name:   "download-token-ttl",
+		Usage:  "seconds after which download tokens expire",
+		Value:  3600,
+		EnvVar: "PHOTOPRISM_DOWNLOAD_TOKEN_TTL",
+	},
+	cli.IntFlag{
+		Name:   "event-buffer",
+		Usage:  "number of recent events per topic kept for websocket clients that reconnect",
+		Value:  20,
+		EnvVar: "PHOTOPRISM_EVENT_BUFFER",
+	},
+	cli.IntFlag{
+		Name:   "log-buffer",
+		Usage:  "number of recent log entries kept in memory for the log viewer",
+		Value:  1000,
+		EnvVar: "PHOTOPRISM_LOG_BUFFER",
+	},
+	cli.StringFlag{
+		Name:   "cache-driver",
+		Usage:  "cache `DRIVER` for sessions, thumbnails and geocoding results (memory or redis)",
+		Value:  "memory",
+		EnvVar: "PHOTOPRISM_CACHE_DRIVER",
+	},
+	cli.StringFlag{
+		Name:   "cache-dsn",
+		Usage:  "cache data source name, e.g. redis://:password@localhost:6379/0",
+		EnvVar: "PHOTOPRISM_CACHE_DSN",
+	},
 	cli.BoolFlag{
 		Name:   "debug",
 		Usage:  "run in debug mode",
@@ -28,11 +79,62 @@ var GlobalFlags = []cli.Flag{
 		Usage:  "run in read-only mode",
 		EnvVar: "PHOTOPRISM_READ_ONLY",
 	},
+	cli.BoolFlag{
+		Name:   "auto-migrate",
+		Usage:  "apply pending database migrations on startup",
+		EnvVar: "PHOTOPRISM_AUTO_MIGRATE",
+	},
+	cli.StringFlag{
+		Name:   "ignore-patterns",
+		Usage:  "comma separated `PATTERNS` of files and folders to skip when indexing and importing, see .ppignore",
+		Value:  "@eaDir/, .*, *.tmp",
+		EnvVar: "PHOTOPRISM_IGNORE_PATTERNS",
+	},
+	cli.IntFlag{
+		Name:   "trash-retention-days",
+		Usage:  "number of `DAYS` after which deleted photos are permanently removed from the trash (0 to keep them)",
+		Value:  30,
+		EnvVar: "PHOTOPRISM_TRASH_RETENTION_DAYS",
+	},
+	cli.StringFlag{
+		Name:   "stack-suffixes",
+		Usage:  "comma separated file name `SUFFIXES` of edited versions to stack with the original, e.g. _edit for IMG_1234_edit.jpg",
+		Value:  "_edit, -edit",
+		EnvVar: "PHOTOPRISM_STACK_SUFFIXES",
+	},
+	cli.StringFlag{
+		Name:   "stack-primary",
+		Usage:  "primary file `PREFERENCE` of stacks: jpeg-first for edited versions, raw-first for the JPEG of the RAW file",
+		Value:  "jpeg-first",
+		EnvVar: "PHOTOPRISM_STACK_PRIMARY",
+	},
+	cli.StringSliceFlag{
+		Name:   "webhook-url",
+		Usage:  "`URL` to notify of events with a JSON POST request, may be repeated for multiple targets",
+		EnvVar: "PHOTOPRISM_WEBHOOK_URL",
+	},
+	cli.StringFlag{
+		Name:   "webhook-secret",
+		Usage:  "`SECRET` for signing webhook requests with HMAC-SHA256, see the X-PhotoPrism-Signature header",
+		EnvVar: "PHOTOPRISM_WEBHOOK_SECRET",
+	},
+	cli.StringFlag{
+		Name:   "webhook-events",
+		Usage:  "comma separated `EVENTS` sent to webhooks, * may be used as wildcard",
+		Value:  "import.completed, index.completed, notify.error",
+		EnvVar: "PHOTOPRISM_WEBHOOK_EVENTS",
+	},
 	cli.BoolFlag{
 		Name:   "public, p",
 		Usage:  "no authentication required",
 		EnvVar: "PHOTOPRISM_PUBLIC",
 	},
+	cli.StringFlag{
+		Name:   "public-role",
+		Usage:  "pseudo-role of anonymous users in public mode for album permissions",
+		Value:  "guest",
+		EnvVar: "PHOTOPRISM_PUBLIC_ROLE",
+	},
 	cli.BoolFlag{
 		Name:   "experimental, e",
 		Usage:  "enable experimental features",
@@ -43,11 +145,39 @@ var GlobalFlags = []cli.Flag{
 		Usage:  "number of workers for indexing",
 		EnvVar: "PHOTOPRISM_WORKERS",
 	},
+	cli.IntFlag{
+		Name:   "index-batch-size",
+		Usage:  "max number of photos of a folder written to the database in one transaction, 1 disables batching",
+		Value:  100,
+		EnvVar: "PHOTOPRISM_INDEX_BATCH_SIZE",
+	},
+	cli.IntFlag{
+		Name:   "worker-nice",
+		Usage:  "seconds background workers wait after the last api request before they continue, -1 to disable",
+		Value:  2,
+		EnvVar: "PHOTOPRISM_WORKER_NICE",
+	},
+	cli.Float64Flag{
+		Name:   "worker-nice-load",
+		Usage:  "load average per cpu above which background workers pause, 0 to disable",
+		EnvVar: "PHOTOPRISM_WORKER_NICE_LOAD",
+	},
 	cli.IntFlag{
 		Name:   "wakeup-interval",
 		Usage:  "background worker wakeup interval in seconds",
 		EnvVar: "PHOTOPRISM_WAKEUP_INTERVAL",
 	},
+	cli.BoolFlag{
+		Name:   "auto-index-watch",
+		Usage:  "watch originals for changes and index them automatically",
+		EnvVar: "PHOTOPRISM_AUTO_INDEX_WATCH",
+	},
+	cli.IntFlag{
+		Name:   "auto-index-settle",
+		Usage:  "seconds to wait after the last change in a folder before indexing it",
+		Value:  10,
+		EnvVar: "PHOTOPRISM_AUTO_INDEX_SETTLE",
+	},
 	cli.StringFlag{
 		Name:   "url",
 		Usage:  "canonical site URL",
@@ -119,18 +249,46 @@ var GlobalFlags = []cli.Flag{
 		Usage:  "resources `PATH`",
 		EnvVar: "PHOTOPRISM_RESOURCES_PATH",
 	},
-	cli.StringFlag{
+	cli.GenericFlag{
 		Name:   "originals-path",
-		Usage:  "originals `PATH`",
-		Value:  "~/Pictures/Originals",
+		Usage:  "originals `PATH`, may be repeated or comma separated, additional roots need a name like archive=/mnt/photos",
+		Value:  &PathList{Value: "~/Pictures/Originals"},
 		EnvVar: "PHOTOPRISM_ORIGINALS_PATH",
 	},
+	cli.IntFlag{
+		Name:   "originals-limit",
+		Usage:  "original file size limit in `MB`, larger files are indexed without decoding them (0 for unlimited)",
+		Value:  1000,
+		EnvVar: "PHOTOPRISM_ORIGINALS_LIMIT",
+	},
+	cli.IntFlag{
+		Name:   "resolution-limit",
+		Usage:  "original image resolution limit in `MEGAPIXELS`, larger images are indexed without decoding them (0 for unlimited)",
+		Value:  150,
+		EnvVar: "PHOTOPRISM_RESOLUTION_LIMIT",
+	},
 	cli.StringFlag{
 		Name:   "import-path",
 		Usage:  "import `PATH`",
 		Value:  "~/Pictures/Import",
 		EnvVar: "PHOTOPRISM_IMPORT_PATH",
 	},
+	cli.BoolFlag{
+		Name:   "import-move",
+		Usage:  "move files to originals when importing instead of copying them",
+		EnvVar: "PHOTOPRISM_IMPORT_MOVE",
+	},
+	cli.StringFlag{
+		Name:   "import-path-pattern",
+		Usage:  "destination `PATTERN` of imported files with {year}, {month}, {day}, {camera}, {filename} and {canonical}",
+		Value:  "{year}/{month}/{canonical}",
+		EnvVar: "PHOTOPRISM_IMPORT_PATH_PATTERN",
+	},
+	cli.StringFlag{
+		Name:   "sidecar-path",
+		Usage:  "storage `PATH` for XMP sidecar files if originals can't be modified",
+		EnvVar: "PHOTOPRISM_SIDECAR_PATH",
+	},
 	cli.StringFlag{
 		Name:   "temp-path",
 		Usage:  "temporary `PATH` for uploads and downloads",
@@ -178,6 +336,32 @@ var GlobalFlags = []cli.Flag{
 		Value:  "darktable-cli",
 		EnvVar: "PHOTOPRISM_DARKTABLE_BIN",
 	},
+	cli.StringFlag{
+		Name:   "darktable-args",
+		Usage:  "additional darktable cli `ARGS`, e.g. \"--hq false\"",
+		EnvVar: "PHOTOPRISM_DARKTABLE_ARGS",
+	},
+	cli.StringFlag{
+		Name:   "rawtherapee-bin",
+		Usage:  "rawtherapee cli binary `FILENAME`",
+		Value:  "rawtherapee-cli",
+		EnvVar: "PHOTOPRISM_RAWTHERAPEE_BIN",
+	},
+	cli.StringFlag{
+		Name:   "rawtherapee-args",
+		Usage:  "additional rawtherapee cli `ARGS`, e.g. \"-q\" for faster conversion",
+		EnvVar: "PHOTOPRISM_RAWTHERAPEE_ARGS",
+	},
+	cli.StringFlag{
+		Name:   "raw-converter",
+		Usage:  "preferred raw to jpeg converter (darktable, rawtherapee or sips), others are used as fallback",
+		EnvVar: "PHOTOPRISM_RAW_CONVERTER",
+	},
+	cli.BoolFlag{
+		Name:   "raw-presets",
+		Usage:  "apply user presets when converting raw files with darktable",
+		EnvVar: "PHOTOPRISM_RAW_PRESETS",
+	},
 	cli.StringFlag{
 		Name:   "exiftool-bin",
 		Usage:  "exiftool cli binary `FILENAME`",
@@ -190,6 +374,36 @@ var GlobalFlags = []cli.Flag{
 		Value:  "heif-convert",
 		EnvVar: "PHOTOPRISM_HEIFCONVERT_BIN",
 	},
+	cli.StringFlag{
+		Name:   "cwebp-bin",
+		Usage:  "webp encoder cli binary `FILENAME`",
+		Value:  "cwebp",
+		EnvVar: "PHOTOPRISM_CWEBP_BIN",
+	},
+	cli.StringFlag{
+		Name:   "jpegtran-bin",
+		Usage:  "jpegtran cli binary `FILENAME` for progressive thumbnails",
+		Value:  "jpegtran",
+		EnvVar: "PHOTOPRISM_JPEGTRAN_BIN",
+	},
+	cli.StringFlag{
+		Name:   "ffmpeg-bin",
+		Usage:  "ffmpeg cli binary `FILENAME` for video transcoding",
+		Value:  "ffmpeg",
+		EnvVar: "PHOTOPRISM_FFMPEG_BIN",
+	},
+	cli.IntFlag{
+		Name:   "ffmpeg-bitrate",
+		Usage:  "max video bitrate in `MBIT/S` when transcoding to H.264",
+		Value:  50,
+		EnvVar: "PHOTOPRISM_FFMPEG_BITRATE",
+	},
+	cli.IntFlag{
+		Name:   "ffmpeg-timeout",
+		Usage:  "max time in `SECONDS` a video transcode may take before ffmpeg is stopped",
+		Value:  3600,
+		EnvVar: "PHOTOPRISM_FFMPEG_TIMEOUT",
+	},
 	cli.IntFlag{
 		Name:   "http-port",
 		Usage:  "HTTP server port",
@@ -205,6 +419,27 @@ var GlobalFlags = []cli.Flag{
 		Usage:  "debug, release or test",
 		EnvVar: "PHOTOPRISM_HTTP_MODE",
 	},
+	cli.StringFlag{
+		Name:   "http-socket",
+		Usage:  "HTTP server unix socket `FILENAME`, host and port are only used as well if set",
+		EnvVar: "PHOTOPRISM_HTTP_SOCKET",
+	},
+	cli.StringFlag{
+		Name:   "http-socket-mode",
+		Usage:  "HTTP server unix socket permissions as octal `MODE`",
+		Value:  "0660",
+		EnvVar: "PHOTOPRISM_HTTP_SOCKET_MODE",
+	},
+	cli.StringFlag{
+		Name:   "http-cert",
+		Usage:  "TLS certificate `FILENAME` in PEM format, enables HTTPS together with http-key",
+		EnvVar: "PHOTOPRISM_HTTP_CERT",
+	},
+	cli.StringFlag{
+		Name:   "http-key",
+		Usage:  "TLS private key `FILENAME` in PEM format",
+		EnvVar: "PHOTOPRISM_HTTP_KEY",
+	},
 	cli.IntFlag{
 		Name:   "sql-port",
 		Usage:  "built-in SQL server port",
@@ -230,12 +465,62 @@ var GlobalFlags = []cli.Flag{
 		Usage:  "allow uploads that may be offensive",
 		EnvVar: "PHOTOPRISM_UPLOAD_NSFW",
 	},
+	cli.IntFlag{
+		Name:   "upload-limit",
+		Usage:  "max size of chunked uploads in `MB` (0 for unlimited)",
+		Value:  10000,
+		EnvVar: "PHOTOPRISM_UPLOAD_LIMIT",
+	},
+	cli.BoolFlag{
+		Name:   "write-metadata",
+		Usage:  "write metadata changes back to originals or XMP sidecar files",
+		EnvVar: "PHOTOPRISM_WRITE_METADATA",
+	},
+	cli.IntFlag{
+		Name:   "duplicate-distance",
+		Usage:  "max perceptual hash distance of visually identical photos (0-16)",
+		Value:  4,
+		EnvVar: "PHOTOPRISM_DUPLICATE_DISTANCE",
+	},
+	cli.IntFlag{
+		Name:   "duplicate-burst",
+		Usage:  "photos taken less than this number of `SECONDS` apart are burst shots, not duplicates (0 to disable)",
+		Value:  5,
+		EnvVar: "PHOTOPRISM_DUPLICATE_BURST",
+	},
 	cli.StringFlag{
 		Name:   "geocoding-api, g",
-		Usage:  "geocoding api (none, osm or places)",
+		Usage:  "geocoding api (none, osm, places or offline)",
 		Value:  "places",
 		EnvVar: "PHOTOPRISM_GEOCODING_API",
 	},
+	cli.IntFlag{
+		Name:   "geocoding-ttl",
+		Usage:  "days to cache reverse geocoding results, 0 keeps them forever",
+		Value:  30,
+		EnvVar: "PHOTOPRISM_GEOCODING_TTL",
+	},
+	cli.StringFlag{
+		Name:   "geocoding-endpoint",
+		Usage:  "base url of the geocoding api, e.g. of a self-hosted Nominatim instance without rate limit",
+		EnvVar: "PHOTOPRISM_GEOCODING_ENDPOINT",
+	},
+	cli.IntFlag{
+		Name:   "geocoding-timeout",
+		Usage:  "geocoding request timeout in seconds",
+		Value:  30,
+		EnvVar: "PHOTOPRISM_GEOCODING_TIMEOUT",
+	},
+	cli.StringFlag{
+		Name:   "places-url",
+		Usage:  "base url of a self-hosted places service, uses the geocoding endpoint if empty",
+		EnvVar: "PHOTOPRISM_PLACES_URL",
+	},
+	cli.StringFlag{
+		Name:   "http-proxy",
+		Usage:  "proxy url for outbound requests, uses HTTP_PROXY and HTTPS_PROXY if empty",
+		EnvVar: "PHOTOPRISM_HTTP_PROXY",
+	},
 	cli.IntFlag{
 		Name:   "thumb-quality, q",
 		Usage:  "jpeg quality of thumbnails (25-100)",
@@ -254,12 +539,64 @@ var GlobalFlags = []cli.Flag{
 		Value:  3840,
 		EnvVar: "PHOTOPRISM_THUMB_LIMIT",
 	},
+	cli.IntFlag{
+		Name:   "thumb-concurrency",
+		Usage:  "max number of thumbnails rendered on demand at the same time (default: number of workers)",
+		EnvVar: "PHOTOPRISM_THUMB_CONCURRENCY",
+	},
+	cli.IntFlag{
+		Name:   "thumb-timeout",
+		Usage:  "max time in seconds a request waits for an on-demand thumbnail",
+		Value:  30,
+		EnvVar: "PHOTOPRISM_THUMB_TIMEOUT",
+	},
 	cli.StringFlag{
 		Name:   "thumb-filter, f",
-		Usage:  "resample filter (blackman, lanczos, cubic or linear)",
+		Usage:  "resample filter (blackman, lanczos, cubic or linear), optionally per type like \"tile_224:lanczos,default:cubic\"",
 		Value:  "lanczos",
 		EnvVar: "PHOTOPRISM_THUMB_FILTER",
 	},
+	cli.Float64Flag{
+		Name:   "thumb-sharpen",
+		Usage:  "sigma of the unsharp mask applied after downscaling (0-5, 0 to disable)",
+		EnvVar: "PHOTOPRISM_THUMB_SHARPEN",
+	},
+	cli.StringFlag{
+		Name:   "thumb-format",
+		Usage:  "thumbnail file format (jpeg or webp, requires cwebp)",
+		Value:  "jpeg",
+		EnvVar: "PHOTOPRISM_THUMB_FORMAT",
+	},
+	cli.StringFlag{
+		Name:   "thumb-sizes",
+		Usage:  "custom thumbnail sizes, e.g. \"tv_1080:1920x1080:fit:public,-fit_3840\"",
+		EnvVar: "PHOTOPRISM_THUMB_SIZES",
+	},
+	cli.BoolFlag{
+		Name:   "thumb-use-embedded",
+		Usage:  "create small thumbnails from embedded preview images if possible",
+		EnvVar: "PHOTOPRISM_THUMB_USE_EMBEDDED",
+	},
+	cli.BoolFlag{
+		Name:   "thumb-color-management",
+		Usage:  "convert wide gamut images like Display P3 or Adobe RGB to sRGB (uses more CPU)",
+		EnvVar: "PHOTOPRISM_THUMB_COLOR_MANAGEMENT",
+	},
+	cli.BoolFlag{
+		Name:   "thumb-progressive",
+		Usage:  "use progressive encoding for jpeg thumbnails of 720 pixels and more (requires jpegtran)",
+		EnvVar: "PHOTOPRISM_THUMB_PROGRESSIVE",
+	},
+	cli.BoolFlag{
+		Name:   "thumb-smart-crop",
+		Usage:  "choose the crop window of square tile thumbnails based on the image content instead of the center",
+		EnvVar: "PHOTOPRISM_THUMB_SMART_CROP",
+	},
+	cli.StringFlag{
+		Name:   "thumb-copyright",
+		Usage:  "copyright `NOTICE` added to thumbnails, other meta data is always removed",
+		EnvVar: "PHOTOPRISM_THUMB_COPYRIGHT",
+	},
 	cli.BoolFlag{
 		Name:   "disable-tf",
 		Usage:  "don't use TensorFlow for image classification",
@@ -271,3 +608,28 @@ var GlobalFlags = []cli.Flag{
 		EnvVar: "PHOTOPRISM_DISABLE_SETTINGS",
 	},
 }
+
+// PathList is a comma separated list of paths, the flag may be repeated to add more paths.
+// The default value is replaced when it's set for the first time.
+type PathList struct {
+	Value string
+	set   bool
+}
+
+// Set adds one or more comma separated paths.
+func (l *PathList) Set(value string) error {
+	if l.set && l.Value != "" {
+		l.Value += "," + value
+	} else {
+		l.Value = value
+	}
+
+	l.set = true
+
+	return nil
+}
+
+// String returns the paths as comma separated list.
+func (l *PathList) String() string {
+	return l.Value
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultImportPathPattern is the default destination of imported files relative to the originals path.
+const DefaultImportPathPattern = "{year}/{month}/{canonical}"
+
+// ImportPathTokens lists the tokens supported in import path patterns.
+var ImportPathTokens = []string{"year", "month", "day", "camera", "filename", "canonical"}
+
+var importPathToken = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// ImportMove returns true if files should be moved instead of copied when importing.
+func (c *Config) ImportMove() bool {
+	return c.params.ImportMove
+}
+
+// ImportPathPattern returns the destination of imported files relative to the originals path,
+// without file extension. The canonical name is appended if the pattern contains no file name.
+func (c *Config) ImportPathPattern() string {
+	pattern := strings.Trim(strings.TrimSpace(c.params.ImportPathPattern), "/")
+
+	if pattern == "" {
+		return DefaultImportPathPattern
+	}
+
+	for _, m := range importPathToken.FindAllStringSubmatch(pattern, -1) {
+		if !isImportPathToken(m[1]) {
+			log.Warnf("config: unknown token %s in import path pattern, using default", m[0])
+			return DefaultImportPathPattern
+		}
+	}
+
+	if !strings.Contains(pattern, "{filename}") && !strings.Contains(pattern, "{canonical}") {
+		pattern += "/{canonical}"
+	}
+
+	return pattern
+}
+
+func isImportPathToken(token string) bool {
+	for _, t := range ImportPathTokens {
+		if t == token {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ImportMove(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ImportMove())
+
+	c.params.ImportMove = true
+	assert.True(t, c.ImportMove())
+}
+
+func TestConfig_ImportPathPattern(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, DefaultImportPathPattern, c.ImportPathPattern())
+
+	c.params.ImportPathPattern = "/{year}/{year}-{month}-{day} {camera}/{filename}"
+	assert.Equal(t, "{year}/{year}-{month}-{day} {camera}/{filename}", c.ImportPathPattern())
+
+	c.params.ImportPathPattern = "{year}/{month}/"
+	assert.Equal(t, "{year}/{month}/{canonical}", c.ImportPathPattern())
+
+	c.params.ImportPathPattern = "{year}/{event}"
+	assert.Equal(t, DefaultImportPathPattern, c.ImportPathPattern())
+}
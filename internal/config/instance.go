@@ -0,0 +1,61 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InstanceKeyFile returns the name of the file containing the secret key of this instance.
+func (c *Config) InstanceKeyFile() string {
+	return c.ConfigPath() + "/instance.key"
+}
+
+// InstanceKey returns the secret key of this instance, which is created when it's needed for the
+// first time and stored in the config path, so that it survives restarts. If it can't be stored,
+// a temporary key is used until the next restart.
+func (c *Config) InstanceKey() []byte {
+	c.instanceOnce.Do(func() {
+		fileName := c.InstanceKeyFile()
+
+		if data, err := ioutil.ReadFile(fileName); err == nil {
+			if key, err := hex.DecodeString(strings.TrimSpace(string(data))); err == nil && len(key) >= 32 {
+				c.instanceKey = key
+				return
+			}
+
+			log.Warnf("config: invalid instance key in %s, creating a new one", fileName)
+		}
+
+		key := make([]byte, 32)
+
+		if _, err := rand.Read(key); err != nil {
+			log.Fatal(err)
+		}
+
+		c.instanceKey = key
+
+		if err := os.MkdirAll(c.ConfigPath(), os.ModePerm); err != nil {
+			log.Errorf("config: %s", err)
+		} else if err := ioutil.WriteFile(fileName, []byte(hex.EncodeToString(key)), 0600); err != nil {
+			log.Errorf("config: %s", err)
+		} else {
+			log.Infof("config: created instance key %s", filepath.Base(fileName))
+		}
+	})
+
+	return c.instanceKey
+}
+
+// DownloadSecret returns the secret for signing download tokens, which is derived from the instance key.
+func (c *Config) DownloadSecret() []byte {
+	mac := hmac.New(sha256.New, c.InstanceKey())
+	mac.Write([]byte("download-token"))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_InstanceKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "instance")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	c := &Config{params: &Params{ConfigPath: dir}}
+	key := c.InstanceKey()
+
+	assert.Len(t, key, 32)
+	assert.Equal(t, key, c.InstanceKey())
+
+	info, err := os.Stat(c.InstanceKeyFile())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	t.Run("restarted", func(t *testing.T) {
+		restarted := &Config{params: &Params{ConfigPath: dir}}
+
+		assert.Equal(t, key, restarted.InstanceKey())
+		assert.Equal(t, c.DownloadSecret(), restarted.DownloadSecret())
+	})
+	t.Run("other instance", func(t *testing.T) {
+		other, err := ioutil.TempDir("", "instance")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(other)
+
+		c2 := &Config{params: &Params{ConfigPath: other}}
+
+		assert.NotEqual(t, key, c2.InstanceKey())
+		assert.NotEqual(t, c.DownloadSecret(), c2.DownloadSecret())
+	})
+	t.Run("secret differs from key", func(t *testing.T) {
+		assert.Len(t, c.DownloadSecret(), 32)
+		assert.NotEqual(t, key, c.DownloadSecret())
+	})
+}
+
+func TestConfig_DownloadTokens(t *testing.T) {
+	assert.False(t, (&Config{params: &Params{}}).DownloadTokens())
+	assert.True(t, (&Config{params: &Params{DownloadTokens: true}}).DownloadTokens())
+	assert.False(t, (&Config{params: &Params{DownloadTokens: true, Public: true}}).DownloadTokens())
+}
+
+func TestConfig_DownloadTokenTTL(t *testing.T) {
+	assert.Equal(t, "1h0m0s", (&Config{params: &Params{}}).DownloadTokenTTL().String())
+	assert.Equal(t, "5m0s", (&Config{params: &Params{DownloadTokenTTL: 300}}).DownloadTokenTTL().String())
+}
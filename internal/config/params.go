@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"reflect"
 
 	_ "github.com/jinzhu/gorm/dialects/mysql"
@@ -30,6 +31,15 @@ const (
 type Params struct {
 	AdminPassword      string `yaml:"admin-password" flag:"admin-password"`
 	WebDAVPassword     string `yaml:"webdav-password" flag:"webdav-password"`
+	WebDAVRole         string `yaml:"webdav-role" flag:"webdav-role"`
+	SessionTimeout     int    `yaml:"session-timeout" flag:"session-timeout"`
+	SessionMaxAge      int    `yaml:"session-maxage" flag:"session-maxage"`
+	DownloadTokens     bool   `yaml:"download-tokens" flag:"download-tokens"`
+	DownloadTokenTTL   int    `yaml:"download-token-ttl" flag:"download-token-ttl"`
+	EventBuffer        int    `yaml:"event-buffer" flag:"event-buffer"`
+	LogBuffer          int    `yaml:"log-buffer" flag:"log-buffer"`
+	CacheDriver        string `yaml:"cache-driver" flag:"cache-driver"`
+	CacheDSN           string `yaml:"cache-dsn" flag:"cache-dsn"`
 	Name               string
 	Url                string `yaml:"url" flag:"url"`
 	Title              string `yaml:"title" flag:"title"`
@@ -38,48 +48,102 @@ type Params struct {
 	Author             string `yaml:"author" flag:"author"`
 	Twitter            string `yaml:"twitter" flag:"twitter"`
 	Version            string
+	Commit             string
+	Built              string
 	Copyright          string
-	Debug              bool   `yaml:"debug" flag:"debug"`
-	ReadOnly           bool   `yaml:"read-only" flag:"read-only"`
-	Public             bool   `yaml:"public" flag:"public"`
-	Experimental       bool   `yaml:"experimental" flag:"experimental"`
-	Workers            int    `yaml:"workers" flag:"workers"`
-	WakeupInterval     int    `yaml:"wakeup-interval" flag:"wakeup-interval"`
-	LogLevel           string `yaml:"log-level" flag:"log-level"`
+	Debug              bool     `yaml:"debug" flag:"debug"`
+	ReadOnly           bool     `yaml:"read-only" flag:"read-only"`
+	AutoMigrate        bool     `yaml:"auto-migrate" flag:"auto-migrate"`
+	IgnorePatterns     string   `yaml:"ignore-patterns" flag:"ignore-patterns"`
+	TrashRetentionDays int      `yaml:"trash-retention-days" flag:"trash-retention-days"`
+	StackSuffixes      string   `yaml:"stack-suffixes" flag:"stack-suffixes"`
+	StackPrimary       string   `yaml:"stack-primary" flag:"stack-primary"`
+	WebhookURL         []string `yaml:"webhook-url" flag:"webhook-url"`
+	WebhookSecret      string   `yaml:"webhook-secret" flag:"webhook-secret"`
+	WebhookEvents      string   `yaml:"webhook-events" flag:"webhook-events"`
+	Public             bool     `yaml:"public" flag:"public"`
+	PublicRole         string   `yaml:"public-role" flag:"public-role"`
+	Experimental       bool     `yaml:"experimental" flag:"experimental"`
+	Workers            int      `yaml:"workers" flag:"workers"`
+	IndexBatchSize     int      `yaml:"index-batch-size" flag:"index-batch-size"`
+	WorkerNice         int      `yaml:"worker-nice" flag:"worker-nice"`
+	WorkerNiceLoad     float64  `yaml:"worker-nice-load" flag:"worker-nice-load"`
+	WakeupInterval     int      `yaml:"wakeup-interval" flag:"wakeup-interval"`
+	AutoIndexWatch     bool     `yaml:"auto-index-watch" flag:"auto-index-watch"`
+	AutoIndexSettle    int      `yaml:"auto-index-settle" flag:"auto-index-settle"`
+	LogLevel           string   `yaml:"log-level" flag:"log-level"`
 	ConfigFile         string
-	ConfigPath         string `yaml:"config-path" flag:"config-path"`
-	TempPath           string `yaml:"temp-path" flag:"temp-path"`
-	CachePath          string `yaml:"cache-path" flag:"cache-path"`
-	OriginalsPath      string `yaml:"originals-path" flag:"originals-path"`
-	ImportPath         string `yaml:"import-path" flag:"import-path"`
-	AssetsPath         string `yaml:"assets-path" flag:"assets-path"`
-	ResourcesPath      string `yaml:"resources-path" flag:"resources-path"`
-	DatabasePath       string `yaml:"database-path" flag:"database-path"`
-	DatabaseDriver     string `yaml:"database-driver" flag:"database-driver"`
-	DatabaseDsn        string `yaml:"database-dsn" flag:"database-dsn"`
-	SqlServerHost      string `yaml:"sql-host" flag:"sql-host"`
-	SqlServerPort      uint   `yaml:"sql-port" flag:"sql-port"`
-	SqlServerPassword  string `yaml:"sql-password" flag:"sql-password"`
-	HttpServerHost     string `yaml:"http-host" flag:"http-host"`
-	HttpServerPort     int    `yaml:"http-port" flag:"http-port"`
-	HttpServerMode     string `yaml:"http-mode" flag:"http-mode"`
-	HttpServerPassword string `yaml:"http-password" flag:"http-password"`
-	SipsBin            string `yaml:"sips-bin" flag:"sips-bin"`
-	DarktableBin       string `yaml:"darktable-bin" flag:"darktable-bin"`
-	ExifToolBin        string `yaml:"exiftool-bin" flag:"exiftool-bin"`
-	HeifConvertBin     string `yaml:"heifconvert-bin" flag:"heifconvert-bin"`
-	PIDFilename        string `yaml:"pid-filename" flag:"pid-filename"`
-	LogFilename        string `yaml:"log-filename" flag:"log-filename"`
-	DetachServer       bool   `yaml:"detach-server" flag:"detach-server"`
-	DetectNSFW         bool   `yaml:"detect-nsfw" flag:"detect-nsfw"`
-	UploadNSFW         bool   `yaml:"upload-nsfw" flag:"upload-nsfw"`
-	GeoCodingApi       string `yaml:"geocoding-api" flag:"geocoding-api"`
-	ThumbQuality       int    `yaml:"thumb-quality" flag:"thumb-quality"`
-	ThumbSize          int    `yaml:"thumb-size" flag:"thumb-size"`
-	ThumbLimit         int    `yaml:"thumb-limit" flag:"thumb-limit"`
-	ThumbFilter        string `yaml:"thumb-filter" flag:"thumb-filter"`
-	DisableTensorFlow  bool   `yaml:"disable-tf" flag:"disable-tf"`
-	DisableSettings    bool   `yaml:"disable-settings" flag:"disable-settings"`
+	ConfigPath         string  `yaml:"config-path" flag:"config-path"`
+	TempPath           string  `yaml:"temp-path" flag:"temp-path"`
+	CachePath          string  `yaml:"cache-path" flag:"cache-path"`
+	OriginalsPath      string  `yaml:"originals-path" flag:"originals-path"`
+	OriginalsLimit     int     `yaml:"originals-limit" flag:"originals-limit"`
+	ResolutionLimit    int     `yaml:"resolution-limit" flag:"resolution-limit"`
+	ImportPath         string  `yaml:"import-path" flag:"import-path"`
+	ImportMove         bool    `yaml:"import-move" flag:"import-move"`
+	ImportPathPattern  string  `yaml:"import-path-pattern" flag:"import-path-pattern"`
+	SidecarPath        string  `yaml:"sidecar-path" flag:"sidecar-path"`
+	AssetsPath         string  `yaml:"assets-path" flag:"assets-path"`
+	ResourcesPath      string  `yaml:"resources-path" flag:"resources-path"`
+	DatabasePath       string  `yaml:"database-path" flag:"database-path"`
+	DatabaseDriver     string  `yaml:"database-driver" flag:"database-driver"`
+	DatabaseDsn        string  `yaml:"database-dsn" flag:"database-dsn"`
+	SqlServerHost      string  `yaml:"sql-host" flag:"sql-host"`
+	SqlServerPort      uint    `yaml:"sql-port" flag:"sql-port"`
+	SqlServerPassword  string  `yaml:"sql-password" flag:"sql-password"`
+	HttpServerHost     string  `yaml:"http-host" flag:"http-host"`
+	HttpServerPort     int     `yaml:"http-port" flag:"http-port"`
+	HttpServerMode     string  `yaml:"http-mode" flag:"http-mode"`
+	HttpServerSocket   string  `yaml:"http-socket" flag:"http-socket"`
+	HttpSocketMode     string  `yaml:"http-socket-mode" flag:"http-socket-mode"`
+	HttpServerCert     string  `yaml:"http-cert" flag:"http-cert"`
+	HttpServerKey      string  `yaml:"http-key" flag:"http-key"`
+	HttpServerPassword string  `yaml:"http-password" flag:"http-password"`
+	SipsBin            string  `yaml:"sips-bin" flag:"sips-bin"`
+	DarktableBin       string  `yaml:"darktable-bin" flag:"darktable-bin"`
+	DarktableArgs      string  `yaml:"darktable-args" flag:"darktable-args"`
+	RawTherapeeBin     string  `yaml:"rawtherapee-bin" flag:"rawtherapee-bin"`
+	RawTherapeeArgs    string  `yaml:"rawtherapee-args" flag:"rawtherapee-args"`
+	RawConverter       string  `yaml:"raw-converter" flag:"raw-converter"`
+	RawPresets         bool    `yaml:"raw-presets" flag:"raw-presets"`
+	ExifToolBin        string  `yaml:"exiftool-bin" flag:"exiftool-bin"`
+	HeifConvertBin     string  `yaml:"heifconvert-bin" flag:"heifconvert-bin"`
+	CWebPBin           string  `yaml:"cwebp-bin" flag:"cwebp-bin"`
+	JpegTranBin        string  `yaml:"jpegtran-bin" flag:"jpegtran-bin"`
+	FFmpegBin          string  `yaml:"ffmpeg-bin" flag:"ffmpeg-bin"`
+	FFmpegBitrate      int     `yaml:"ffmpeg-bitrate" flag:"ffmpeg-bitrate"`
+	FFmpegTimeout      int     `yaml:"ffmpeg-timeout" flag:"ffmpeg-timeout"`
+	PIDFilename        string  `yaml:"pid-filename" flag:"pid-filename"`
+	LogFilename        string  `yaml:"log-filename" flag:"log-filename"`
+	DetachServer       bool    `yaml:"detach-server" flag:"detach-server"`
+	DetectNSFW         bool    `yaml:"detect-nsfw" flag:"detect-nsfw"`
+	UploadNSFW         bool    `yaml:"upload-nsfw" flag:"upload-nsfw"`
+	UploadLimit        int     `yaml:"upload-limit" flag:"upload-limit"`
+	WriteMetadata      bool    `yaml:"write-metadata" flag:"write-metadata"`
+	DuplicateDistance  int     `yaml:"duplicate-distance" flag:"duplicate-distance"`
+	DuplicateBurst     int     `yaml:"duplicate-burst" flag:"duplicate-burst"`
+	GeoCodingApi       string  `yaml:"geocoding-api" flag:"geocoding-api"`
+	GeoCodingTTL       int     `yaml:"geocoding-ttl" flag:"geocoding-ttl"`
+	GeoCodingEndpoint  string  `yaml:"geocoding-endpoint" flag:"geocoding-endpoint"`
+	GeoCodingTimeout   int     `yaml:"geocoding-timeout" flag:"geocoding-timeout"`
+	PlacesUrl          string  `yaml:"places-url" flag:"places-url"`
+	HttpProxy          string  `yaml:"http-proxy" flag:"http-proxy"`
+	ThumbQuality       int     `yaml:"thumb-quality" flag:"thumb-quality"`
+	ThumbSize          int     `yaml:"thumb-size" flag:"thumb-size"`
+	ThumbLimit         int     `yaml:"thumb-limit" flag:"thumb-limit"`
+	ThumbConcurrency   int     `yaml:"thumb-concurrency" flag:"thumb-concurrency"`
+	ThumbTimeout       int     `yaml:"thumb-timeout" flag:"thumb-timeout"`
+	ThumbFilter        string  `yaml:"thumb-filter" flag:"thumb-filter"`
+	ThumbSharpen       float64 `yaml:"thumb-sharpen" flag:"thumb-sharpen"`
+	ThumbFormat        string  `yaml:"thumb-format" flag:"thumb-format"`
+	ThumbSizes         string  `yaml:"thumb-sizes" flag:"thumb-sizes"`
+	ThumbUseEmbedded   bool    `yaml:"thumb-use-embedded" flag:"thumb-use-embedded"`
+	ThumbColorMgmt     bool    `yaml:"thumb-color-management" flag:"thumb-color-management"`
+	ThumbProgressive   bool    `yaml:"thumb-progressive" flag:"thumb-progressive"`
+	ThumbSmartCrop     bool    `yaml:"thumb-smart-crop" flag:"thumb-smart-crop"`
+	ThumbCopyright     string  `yaml:"thumb-copyright" flag:"thumb-copyright"`
+	DisableTensorFlow  bool    `yaml:"disable-tf" flag:"disable-tf"`
+	DisableSettings    bool    `yaml:"disable-settings" flag:"disable-settings"`
 }
 
 // NewParams creates a new configuration entity by using two methods:
@@ -94,12 +158,25 @@ func NewParams(ctx *cli.Context) *Params {
 	c.Name = ctx.App.Name
 	c.Copyright = ctx.App.Copyright
 	c.Version = ctx.App.Version
+	c.Commit, _ = ctx.App.Metadata["commit"].(string)
+	c.Built, _ = ctx.App.Metadata["built"].(string)
 	c.ConfigFile = fs.Abs(ctx.GlobalString("config-file"))
 
 	if err := c.Load(c.ConfigFile); err != nil {
 		log.Debug(err)
 	}
 
+	// Options saved by the setup wizard override the config file, but not command line flags.
+	if configPath := c.ConfigPath; configPath != "" || ctx.GlobalString("config-path") != "" {
+		if configPath == "" {
+			configPath = ctx.GlobalString("config-path")
+		}
+
+		if err := c.Load(filepath.Join(fs.Abs(configPath), "options.yml")); err != nil {
+			log.Debug(err)
+		}
+	}
+
 	if err := c.SetContext(ctx); err != nil {
 		log.Error(err)
 	}
@@ -113,7 +190,7 @@ func (c *Params) expandFilenames() {
 	c.ResourcesPath = fs.Abs(c.ResourcesPath)
 	c.AssetsPath = fs.Abs(c.AssetsPath)
 	c.CachePath = fs.Abs(c.CachePath)
-	c.OriginalsPath = fs.Abs(c.OriginalsPath)
+	c.OriginalsPath = ParseRoots(c.OriginalsPath).String()
 	c.ImportPath = fs.Abs(c.ImportPath)
 	c.TempPath = fs.Abs(c.TempPath)
 	c.DatabasePath = fs.Abs(c.DatabasePath)
@@ -168,6 +245,15 @@ func (c *Params) SetContext(ctx *cli.Context) error {
 					f := ctx.GlobalUint64(tagValue)
 					fieldValue.SetUint(f)
 				}
+			case float64:
+				// Only if explicitly set or current value is empty (use default)
+				if ctx.IsSet(tagValue) {
+					f := ctx.Float64(tagValue)
+					fieldValue.SetFloat(f)
+				} else if ctx.GlobalIsSet(tagValue) || fieldValue.Float() == 0 {
+					f := ctx.GlobalFloat64(tagValue)
+					fieldValue.SetFloat(f)
+				}
 			case string:
 				// Only if explicitly set or current value is empty (use default)
 				if ctx.IsSet(tagValue) {
@@ -177,6 +263,15 @@ func (c *Params) SetContext(ctx *cli.Context) error {
 					f := ctx.GlobalString(tagValue)
 					fieldValue.SetString(f)
 				}
+			case []string:
+				// Only if explicitly set or current value is empty (use default)
+				if ctx.IsSet(tagValue) {
+					f := ctx.StringSlice(tagValue)
+					fieldValue.Set(reflect.ValueOf(f))
+				} else if ctx.GlobalIsSet(tagValue) || fieldValue.Len() == 0 {
+					f := ctx.GlobalStringSlice(tagValue)
+					fieldValue.Set(reflect.ValueOf(f))
+				}
 			case bool:
 				if ctx.IsSet(tagValue) {
 					f := ctx.Bool(tagValue)
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"flag"
 	"testing"
 
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
 )
 
 func TestNewParams(t *testing.T) {
@@ -40,3 +42,23 @@ func TestParams_SetValuesFromFile(t *testing.T) {
 	assert.Equal(t, "root:photoprism@tcp(localhost:4000)/photoprism?parseTime=true", c.DatabaseDsn)
 	assert.Equal(t, 81, c.HttpServerPort)
 }
+
+func TestParams_SetContext(t *testing.T) {
+	t.Run("repeated flag", func(t *testing.T) {
+		globalSet := flag.NewFlagSet("test", 0)
+		globalSet.Var(&cli.StringSlice{}, "webhook-url", "doc")
+
+		if err := globalSet.Parse([]string{"--webhook-url", "http://a/", "--webhook-url", "http://b/"}); err != nil {
+			t.Fatal(err)
+		}
+
+		c := NewParams(cli.NewContext(cli.NewApp(), globalSet, nil))
+
+		assert.Equal(t, []string{"http://a/", "http://b/"}, c.WebhookURL)
+	})
+	t.Run("not set", func(t *testing.T) {
+		c := NewParams(CliTestContext())
+
+		assert.Empty(t, c.WebhookURL)
+	})
+}
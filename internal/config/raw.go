@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+)
+
+// Supported raw to jpeg converters.
+const (
+	RawSips        = "sips"
+	RawDarktable   = "darktable"
+	RawRawTherapee = "rawtherapee"
+)
+
+// rawConverters is the default order in which raw converters are tried.
+var rawConverters = []string{RawSips, RawDarktable, RawRawTherapee}
+
+// RawConverter returns the preferred raw to jpeg converter, or an empty string if there is no preference.
+func (c *Config) RawConverter() string {
+	name := strings.ToLower(strings.TrimSpace(c.params.RawConverter))
+
+	for _, n := range rawConverters {
+		if name == n {
+			return name
+		}
+	}
+
+	if name != "" {
+		log.Warnf("config: unknown raw converter \"%s\"", name)
+	}
+
+	return ""
+}
+
+// RawConverters returns the raw to jpeg converters in the order they should be tried,
+// starting with the preferred converter.
+func (c *Config) RawConverters() []string {
+	preferred := c.RawConverter()
+
+	if preferred == "" {
+		return append([]string{}, rawConverters...)
+	}
+
+	result := []string{preferred}
+
+	for _, n := range rawConverters {
+		if n != preferred {
+			result = append(result, n)
+		}
+	}
+
+	return result
+}
+
+// DarktableArgs returns additional darktable-cli arguments.
+func (c *Config) DarktableArgs() []string {
+	return strings.Fields(c.params.DarktableArgs)
+}
+
+// RawTherapeeArgs returns additional rawtherapee-cli arguments.
+func (c *Config) RawTherapeeArgs() []string {
+	return strings.Fields(c.params.RawTherapeeArgs)
+}
+
+// RawPresets returns true if user presets should be applied when converting raw files with darktable.
+func (c *Config) RawPresets() bool {
+	return c.params.RawPresets
+}
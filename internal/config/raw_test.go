@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_RawConverters(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.RawConverter())
+	assert.Equal(t, []string{RawSips, RawDarktable, RawRawTherapee}, c.RawConverters())
+
+	c.params.RawConverter = " RawTherapee"
+	assert.Equal(t, RawRawTherapee, c.RawConverter())
+	assert.Equal(t, []string{RawRawTherapee, RawSips, RawDarktable}, c.RawConverters())
+
+	c.params.RawConverter = "lightroom"
+	assert.Equal(t, "", c.RawConverter())
+	assert.Equal(t, []string{RawSips, RawDarktable, RawRawTherapee}, c.RawConverters())
+}
+
+func TestConfig_RawArgs(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Empty(t, c.DarktableArgs())
+	assert.False(t, c.RawPresets())
+
+	c.params.DarktableArgs = "--hq false  --width 4096"
+	c.params.RawTherapeeArgs = "-q"
+
+	assert.Equal(t, []string{"--hq", "false", "--width", "4096"}, c.DarktableArgs())
+	assert.Equal(t, []string{"-q"}, c.RawTherapeeArgs())
+}
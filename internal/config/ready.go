@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Subsystems that are initialized in the background after the web server has started.
+const (
+	SubsystemDatabase = "database"
+	SubsystemModels   = "models"
+)
+
+// Subsystem represents the startup state of a subsystem.
+type Subsystem struct {
+	Name     string        `json:"name"`
+	Ready    bool          `json:"ready"`
+	Error    string        `json:"error,omitempty"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Subsystems is a list of subsystem states in the order they were started.
+type Subsystems []Subsystem
+
+type readiness struct {
+	mutex      sync.RWMutex
+	subsystems Subsystems
+}
+
+// Warming marks a subsystem as being initialized, so that requests depending on it can be rejected.
+func (c *Config) Warming(name string) {
+	c.ready.mutex.Lock()
+	defer c.ready.mutex.Unlock()
+
+	for i, s := range c.ready.subsystems {
+		if s.Name == name {
+			c.ready.subsystems[i] = Subsystem{Name: name, Started: time.Now()}
+			return
+		}
+	}
+
+	c.ready.subsystems = append(c.ready.subsystems, Subsystem{Name: name, Started: time.Now()})
+}
+
+// SetReady marks a subsystem as initialized and logs the time it took. Failed subsystems are
+// reported with their error and don't become ready.
+func (c *Config) SetReady(name string, err error) {
+	c.ready.mutex.Lock()
+	defer c.ready.mutex.Unlock()
+
+	for i, s := range c.ready.subsystems {
+		if s.Name != name {
+			continue
+		}
+
+		s.Duration = time.Since(s.Started)
+
+		if err != nil {
+			s.Error = err.Error()
+			log.Errorf("startup: %s failed after %s (%s)", name, s.Duration, err)
+		} else {
+			s.Ready = true
+			log.Infof("startup: %s ready in %s", name, s.Duration)
+		}
+
+		c.ready.subsystems[i] = s
+
+		return
+	}
+}
+
+// Ready returns true if the subsystems are initialized. Subsystems that were never marked as
+// warming are considered ready, e.g. when running cli commands.
+func (c *Config) Ready(names ...string) bool {
+	return c.NotReady(names...) == ""
+}
+
+// NotReady returns the name of the first subsystem that is not initialized yet, or an empty string.
+// If no names are passed, all subsystems are checked.
+func (c *Config) NotReady(names ...string) string {
+	c.ready.mutex.RLock()
+	defer c.ready.mutex.RUnlock()
+
+	for _, s := range c.ready.subsystems {
+		if s.Ready {
+			continue
+		}
+
+		if len(names) == 0 {
+			return s.Name
+		}
+
+		for _, name := range names {
+			if s.Name == name {
+				return s.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// Subsystems returns the startup state of all subsystems.
+func (c *Config) Subsystems() Subsystems {
+	c.ready.mutex.RLock()
+	defer c.ready.mutex.RUnlock()
+
+	return append(Subsystems{}, c.ready.subsystems...)
+}
+
+// String returns a startup timing breakdown like "database 1.2s, models 3.4s".
+func (s Subsystems) String() string {
+	var result []string
+
+	for _, sub := range s {
+		if sub.Ready {
+			result = append(result, fmt.Sprintf("%s %s", sub.Name, sub.Duration.Round(time.Millisecond)))
+		} else if sub.Error != "" {
+			result = append(result, fmt.Sprintf("%s failed", sub.Name))
+		} else {
+			result = append(result, fmt.Sprintf("%s warming", sub.Name))
+		}
+	}
+
+	return strings.Join(result, ", ")
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Ready(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	t.Run("not started", func(t *testing.T) {
+		assert.True(t, c.Ready())
+		assert.True(t, c.Ready(SubsystemDatabase))
+		assert.Empty(t, c.Subsystems())
+	})
+	t.Run("warming", func(t *testing.T) {
+		c.Warming(SubsystemDatabase)
+		c.Warming(SubsystemModels)
+
+		assert.False(t, c.Ready())
+		assert.False(t, c.Ready(SubsystemModels))
+		assert.Equal(t, SubsystemDatabase, c.NotReady())
+		assert.Equal(t, "database warming, models warming", c.Subsystems().String())
+	})
+	t.Run("partly ready", func(t *testing.T) {
+		c.SetReady(SubsystemDatabase, nil)
+
+		assert.True(t, c.Ready(SubsystemDatabase))
+		assert.False(t, c.Ready(SubsystemDatabase, SubsystemModels))
+		assert.Equal(t, SubsystemModels, c.NotReady())
+	})
+	t.Run("failed", func(t *testing.T) {
+		c.SetReady(SubsystemModels, errors.New("model not found"))
+
+		subsystems := c.Subsystems()
+
+		assert.False(t, c.Ready())
+		assert.Len(t, subsystems, 2)
+		assert.Equal(t, "model not found", subsystems[1].Error)
+		assert.Contains(t, subsystems.String(), "models failed")
+	})
+	t.Run("ready", func(t *testing.T) {
+		c.Warming(SubsystemModels)
+		c.SetReady(SubsystemModels, nil)
+
+		assert.True(t, c.Ready())
+		assert.Len(t, c.Subsystems(), 2)
+	})
+}
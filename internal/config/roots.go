@@ -0,0 +1,178 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gosimple/slug"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// RootNameLength is the maximum length of originals root names, as they are stored with each file.
+const RootNameLength = 16
+
+// Root represents an originals directory, file names in the index are relative to their root.
+type Root struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Roots is a list of originals directories, the first one is the default root.
+type Roots []Root
+
+// ParseRoots parses a comma separated list of originals paths. The first root is always named "default",
+// so that existing indexes keep working. Other roots must have a unique name like "archive=/mnt/hdd/photos",
+// as it identifies the root of indexed files and must not change when the list is reordered. Roots without
+// name and duplicate or reserved names are ignored.
+func ParseRoots(s string) (result Roots) {
+	for _, value := range strings.Split(s, ",") {
+		value = strings.TrimSpace(value)
+
+		if value == "" {
+			continue
+		}
+
+		var name string
+
+		if i := strings.Index(value, "="); i > 0 {
+			name = slug.Make(value[:i])
+			value = strings.TrimSpace(value[i+1:])
+		}
+
+		if value == "" {
+			continue
+		}
+
+		root := Root{Name: name, Path: fs.Abs(value)}
+
+		if len(result) == 0 {
+			root.Name = entity.RootDefault
+			result = append(result, root)
+			continue
+		}
+
+		if len(root.Name) > RootNameLength {
+			root.Name = strings.Trim(root.Name[:RootNameLength], "-")
+		}
+
+		switch {
+		case root.Name == "":
+			log.Warnf("config: ignored originals root %s, a name like \"archive=%s\" is required", root.Path, root.Path)
+		case root.Name == entity.RootDefault || root.Name == entity.RootSidecar:
+			log.Warnf("config: ignored originals root %s, \"%s\" is a reserved name", root.Path, root.Name)
+		case result.Path(root.Name) != "":
+			log.Warnf("config: ignored originals root %s, name \"%s\" is already used", root.Path, root.Name)
+		default:
+			result = append(result, root)
+		}
+	}
+
+	return result
+}
+
+// String returns the roots as comma separated list, the name of the default root is omitted.
+func (r Roots) String() string {
+	values := make([]string, len(r))
+
+	for i, root := range r {
+		if i == 0 {
+			values[i] = root.Path
+		} else {
+			values[i] = root.Name + "=" + root.Path
+		}
+	}
+
+	return strings.Join(values, ",")
+}
+
+// Path returns the path of a root, or an empty string if it doesn't exist.
+func (r Roots) Path(name string) string {
+	if name == "" {
+		name = entity.RootDefault
+	}
+
+	for _, root := range r {
+		if root.Name == name {
+			return root.Path
+		}
+	}
+
+	return ""
+}
+
+// Find returns the root containing a file and the file name relative to it. If roots are
+// nested, the innermost root is returned.
+func (r Roots) Find(fileName string) (result Root, relName string, ok bool) {
+	fileName = filepath.Clean(fileName)
+
+	for _, root := range r {
+		rel, err := filepath.Rel(root.Path, fileName)
+
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		if !ok || len(root.Path) > len(result.Path) {
+			result, relName, ok = root, filepath.ToSlash(rel), true
+		}
+	}
+
+	if relName == "." {
+		relName = ""
+	}
+
+	return result, relName, ok
+}
+
+// OriginalsRoots returns all originals directories, the first one is the default root.
+func (c *Config) OriginalsRoots() Roots {
+	return ParseRoots(c.params.OriginalsPath)
+}
+
+// originalsRootsExtra returns all originals roots except the default root.
+func (c *Config) originalsRootsExtra() Roots {
+	if roots := c.OriginalsRoots(); len(roots) > 1 {
+		return roots[1:]
+	}
+
+	return Roots{}
+}
+
+// OriginalsRootPath returns the path of an originals root, or an empty string if it isn't configured.
+// JPEGs converted in read-only mode belong to the sidecar root.
+func (c *Config) OriginalsRootPath(root string) string {
+	if root == entity.RootSidecar {
+		return c.SidecarPath()
+	}
+
+	return c.OriginalsRoots().Path(root)
+}
+
+// OriginalsFileName returns the absolute name of an indexed file, or an empty string if its
+// root isn't configured anymore.
+func (c *Config) OriginalsFileName(root, fileName string) string {
+	rootPath := c.OriginalsRootPath(root)
+
+	if rootPath == "" {
+		return ""
+	}
+
+	return filepath.Join(rootPath, fileName)
+}
+
+// OriginalsRoot returns the root containing a file and the file name relative to it. Files in the
+// sidecar path belong to the sidecar root.
+func (c *Config) OriginalsRoot(fileName string) (root Root, relName string, ok bool) {
+	return append(c.OriginalsRoots(), Root{Name: entity.RootSidecar, Path: c.SidecarPath()}).Find(fileName)
+}
+
+// RootTrashPath returns the storage path for deleted files of an originals root, so that files
+// are never moved to another device.
+func (c *Config) RootTrashPath(root string) string {
+	if rootPath := c.OriginalsRootPath(root); rootPath != "" {
+		return filepath.Join(rootPath, ".trash")
+	}
+
+	return ""
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoots(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert.Empty(t, ParseRoots(""))
+		assert.Empty(t, ParseRoots(" , "))
+	})
+	t.Run("single", func(t *testing.T) {
+		roots := ParseRoots("/photos/originals")
+
+		assert.Equal(t, Roots{{Name: entity.RootDefault, Path: "/photos/originals"}}, roots)
+		assert.Equal(t, "/photos/originals", roots.String())
+	})
+	t.Run("multiple", func(t *testing.T) {
+		roots := ParseRoots("/mnt/ssd/photos, Photo Archive=/mnt/hdd/Photo Archive, Old Scans=/mnt/hdd/scans")
+
+		assert.Equal(t, Roots{
+			{Name: entity.RootDefault, Path: "/mnt/ssd/photos"},
+			{Name: "photo-archive", Path: "/mnt/hdd/Photo Archive"},
+			{Name: "old-scans", Path: "/mnt/hdd/scans"},
+		}, roots)
+		assert.Equal(t, "/mnt/ssd/photos,photo-archive=/mnt/hdd/Photo Archive,old-scans=/mnt/hdd/scans", roots.String())
+		assert.Equal(t, roots, ParseRoots(roots.String()))
+	})
+	t.Run("default name", func(t *testing.T) {
+		roots := ParseRoots("recent=/mnt/ssd/photos,default=/mnt/nas/photos,sidecar=/mnt/nas/sidecar")
+
+		assert.Equal(t, Roots{{Name: entity.RootDefault, Path: "/mnt/ssd/photos"}}, roots)
+	})
+	t.Run("unnamed", func(t *testing.T) {
+		// Names derived from the position or directory would change when the list is edited.
+		roots := ParseRoots("/a/photos,/b/photos,archive=/c/photos")
+
+		assert.Equal(t, Roots{
+			{Name: entity.RootDefault, Path: "/a/photos"},
+			{Name: "archive", Path: "/c/photos"},
+		}, roots)
+	})
+	t.Run("unique names", func(t *testing.T) {
+		roots := ParseRoots("/a/photos,archive=/b/photos,archive=/c/photos,a-very-long-directory-name=/d")
+
+		assert.Equal(t, Roots{
+			{Name: entity.RootDefault, Path: "/a/photos"},
+			{Name: "archive", Path: "/b/photos"},
+			{Name: "a-very-long-dire", Path: "/d"},
+		}, roots)
+	})
+}
+
+func TestRoots_Path(t *testing.T) {
+	roots := ParseRoots("/photos,archive=/archive")
+
+	assert.Equal(t, "/photos", roots.Path(""))
+	assert.Equal(t, "/photos", roots.Path(entity.RootDefault))
+	assert.Equal(t, "/archive", roots.Path("archive"))
+	assert.Equal(t, "", roots.Path("unknown"))
+}
+
+func TestRoots_Find(t *testing.T) {
+	roots := ParseRoots("/photos,archive=/archive,scans=/photos/scans")
+
+	t.Run("default", func(t *testing.T) {
+		root, fileName, ok := roots.Find("/photos/2020/IMG_1234.jpg")
+
+		assert.True(t, ok)
+		assert.Equal(t, entity.RootDefault, root.Name)
+		assert.Equal(t, "2020/IMG_1234.jpg", fileName)
+	})
+	t.Run("archive", func(t *testing.T) {
+		root, fileName, ok := roots.Find("/archive/2010/../2011/IMG_1234.jpg")
+
+		assert.True(t, ok)
+		assert.Equal(t, "archive", root.Name)
+		assert.Equal(t, "2011/IMG_1234.jpg", fileName)
+	})
+	t.Run("nested", func(t *testing.T) {
+		root, fileName, ok := roots.Find("/photos/scans/1999/scan.jpg")
+
+		assert.True(t, ok)
+		assert.Equal(t, "scans", root.Name)
+		assert.Equal(t, "1999/scan.jpg", fileName)
+	})
+	t.Run("root", func(t *testing.T) {
+		root, fileName, ok := roots.Find("/archive")
+
+		assert.True(t, ok)
+		assert.Equal(t, "archive", root.Name)
+		assert.Equal(t, "", fileName)
+	})
+	t.Run("outside", func(t *testing.T) {
+		_, _, ok := roots.Find("/photos-import/IMG_1234.jpg")
+
+		assert.False(t, ok)
+	})
+}
+
+func TestConfig_OriginalsRoots(t *testing.T) {
+	c := NewConfig(CliTestContext())
+	c.params.OriginalsPath = c.OriginalsPath() + ",archive=/tmp/photoprism/archive"
+
+	roots := c.OriginalsRoots()
+
+	assert.Len(t, roots, 2)
+	assert.Equal(t, roots[0].Path, c.OriginalsPath())
+	assert.Equal(t, "/tmp/photoprism/archive", c.OriginalsRootPath("archive"))
+	assert.Equal(t, "/tmp/photoprism/archive/2020/IMG_1234.jpg", c.OriginalsFileName("archive", "2020/IMG_1234.jpg"))
+	assert.Equal(t, c.OriginalsPath()+"/2020/IMG_1234.jpg", c.OriginalsFileName("", "2020/IMG_1234.jpg"))
+	assert.Equal(t, "", c.OriginalsFileName("unknown", "2020/IMG_1234.jpg"))
+	assert.Equal(t, "/tmp/photoprism/archive/.trash", c.RootTrashPath("archive"))
+	assert.Equal(t, c.TrashPath(), c.RootTrashPath(entity.RootDefault))
+
+	root, fileName, ok := c.OriginalsRoot("/tmp/photoprism/archive/2020/IMG_1234.jpg")
+
+	assert.True(t, ok)
+	assert.Equal(t, "archive", root.Name)
+	assert.Equal(t, "2020/IMG_1234.jpg", fileName)
+
+	// JPEGs converted in read-only mode belong to the sidecar root.
+	root, fileName, ok = c.OriginalsRoot(filepath.Join(c.SidecarPath(), "2020", "IMG_1234.jpg"))
+
+	assert.True(t, ok)
+	assert.Equal(t, entity.RootSidecar, root.Name)
+	assert.Equal(t, "2020/IMG_1234.jpg", fileName)
+	assert.Equal(t, filepath.Join(c.SidecarPath(), "2020", "IMG_1234.jpg"), c.OriginalsFileName(entity.RootSidecar, fileName))
+}
+
+func TestPathList(t *testing.T) {
+	l := &PathList{Value: "~/Pictures/Originals"}
+
+	assert.Equal(t, "~/Pictures/Originals", l.String())
+
+	assert.NoError(t, l.Set("/mnt/ssd/photos"))
+	assert.Equal(t, "/mnt/ssd/photos", l.String())
+
+	assert.NoError(t, l.Set("archive=/mnt/hdd/photos,/mnt/nas/photos"))
+	assert.Equal(t, "/mnt/ssd/photos,archive=/mnt/hdd/photos,/mnt/nas/photos", l.String())
+}
@@ -1,6 +1,11 @@
 package config
 
-import "github.com/photoprism/photoprism/pkg/fs"
+import (
+	"os"
+	"strconv"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
 
 // DatabasePath returns the database storage path for TiDB.
 func (c *Config) DatabasePath() string {
@@ -47,6 +52,53 @@ func (c *Config) HttpServerMode() string {
 	return c.params.HttpServerMode
 }
 
+// HttpServerSocket returns the unix socket file name of the built-in HTTP server (optional).
+func (c *Config) HttpServerSocket() string {
+	if c.params.HttpServerSocket == "" {
+		return ""
+	}
+
+	return fs.Abs(c.params.HttpServerSocket)
+}
+
+// HttpSocketMode returns the file permissions of the HTTP server unix socket, 0660 by default.
+func (c *Config) HttpSocketMode() os.FileMode {
+	if mode, err := strconv.ParseUint(c.params.HttpSocketMode, 8, 32); err == nil && mode > 0 && mode <= 0777 {
+		return os.FileMode(mode)
+	}
+
+	return 0660
+}
+
+// HttpServerTcp returns true if the built-in HTTP server listens on host and port. If a unix socket
+// is configured, this is only the case if the host or port are configured as well.
+func (c *Config) HttpServerTcp() bool {
+	return c.params.HttpServerSocket == "" || c.params.HttpServerHost != "" || c.params.HttpServerPort != 0
+}
+
+// HttpServerCert returns the TLS certificate file name of the built-in HTTP server (optional).
+func (c *Config) HttpServerCert() string {
+	if c.params.HttpServerCert == "" {
+		return ""
+	}
+
+	return fs.Abs(c.params.HttpServerCert)
+}
+
+// HttpServerKey returns the TLS private key file name of the built-in HTTP server (optional).
+func (c *Config) HttpServerKey() string {
+	if c.params.HttpServerKey == "" {
+		return ""
+	}
+
+	return fs.Abs(c.params.HttpServerKey)
+}
+
+// HttpServerTls returns true if the built-in HTTP server uses TLS on host and port.
+func (c *Config) HttpServerTls() bool {
+	return c.HttpServerCert() != "" && c.HttpServerKey() != ""
+}
+
 // HttpServerPassword returns the password for the user interface (optional).
 func (c *Config) HttpServerPassword() string {
 	return c.params.HttpServerPassword
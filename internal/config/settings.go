@@ -1,11 +1,16 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
 	"gopkg.in/yaml.v2"
 )
 
@@ -40,6 +45,74 @@ type FeatureSettings struct {
 	Share    bool `json:"share" yaml:"share"`
 }
 
+// MomentsSettings contains the first day of the week and name templates for generated moments.
+// Empty templates use the default of the current language.
+type MomentsSettings struct {
+	WeekStart string `json:"weekstart" yaml:"weekstart"`
+	Month     string `json:"month" yaml:"month"`
+	Week      string `json:"week" yaml:"week"`
+}
+
+// Validate returns an error if the week start day or a name template is invalid.
+func (s MomentsSettings) Validate() error {
+	if _, err := txt.ParseWeekday(s.WeekStart); err != nil {
+		return err
+	}
+
+	if _, err := txt.ParseDateTemplate(s.Month); err != nil {
+		return err
+	}
+
+	if _, err := txt.ParseDateTemplate(s.Week); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TitleSettings contains the template for generated photo titles, like "{label} / {city} {year}".
+// The default titles are generated if it's empty.
+type TitleSettings struct {
+	Template string `json:"template" yaml:"template"`
+}
+
+// Validate returns an error if the title template is invalid.
+func (s TitleSettings) Validate() error {
+	_, err := txt.ParseTitleTemplate(s.Template)
+
+	return err
+}
+
+// QualitySettings contains the weights used to calculate the quality score of photos. Photos with a
+// score below entity.ReviewQuality need to be reviewed, except favorites and edited photos.
+type QualitySettings struct {
+	Resolution int `json:"resolution" yaml:"resolution"`
+	Location   int `json:"location" yaml:"location"`
+	Date       int `json:"date" yaml:"date"`
+	Favorite   int `json:"favorite" yaml:"favorite"`
+}
+
+// maxQualityWeight is the highest weight that can be configured.
+const maxQualityWeight = 10
+
+// Validate returns an error if a weight is negative or too high.
+func (s QualitySettings) Validate() error {
+	names := []string{"resolution", "location", "date", "favorite"}
+
+	for i, weight := range []int{s.Resolution, s.Location, s.Date, s.Favorite} {
+		if weight < 0 || weight > maxQualityWeight {
+			return fmt.Errorf("%s quality weight must be between 0 and %d", names[i], maxQualityWeight)
+		}
+	}
+
+	return nil
+}
+
+// Weights returns the weights used to calculate quality scores.
+func (s QualitySettings) Weights() entity.QualityWeights {
+	return entity.QualityWeights{Resolution: s.Resolution, Location: s.Location, Date: s.Date, Favorite: s.Favorite}
+}
+
 // Settings contains Web UI settings
 type Settings struct {
 	Theme    string          `json:"theme" yaml:"theme"`
@@ -47,6 +120,9 @@ type Settings struct {
 	Maps     MapsSettings    `json:"maps" yaml:"maps"`
 	Features FeatureSettings `json:"features" yaml:"features"`
 	Library  LibrarySettings `json:"library" yaml:"library"`
+	Moments  MomentsSettings `json:"moments" yaml:"moments"`
+	Titles   TitleSettings   `json:"titles" yaml:"titles"`
+	Quality  QualitySettings `json:"quality" yaml:"quality"`
 }
 
 // NewSettings returns a empty Settings
@@ -77,12 +153,74 @@ func NewSettings() *Settings {
 			RequireReview:  true,
 			HidePrivate:    true,
 		},
+		Moments: MomentsSettings{
+			WeekStart: "monday",
+		},
+		Quality: QualitySettings{
+			Resolution: entity.DefaultQualityWeights.Resolution,
+			Location:   entity.DefaultQualityWeights.Location,
+			Date:       entity.DefaultQualityWeights.Date,
+			Favorite:   entity.DefaultQualityWeights.Favorite,
+		},
+	}
+}
+
+// Validate returns an error if settings contain invalid values.
+func (s *Settings) Validate() error {
+	if err := s.Moments.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Titles.Validate(); err != nil {
+		return err
 	}
+
+	return s.Quality.Validate()
 }
 
-// Propagate updates settings in other packages as needed.
+// WeekStart returns the first day of the week.
+func (s *Settings) WeekStart() time.Weekday {
+	if d, err := txt.ParseWeekday(s.Moments.WeekStart); err == nil {
+		return d
+	}
+
+	return time.Monday
+}
+
+// MonthTemplate returns the name template for monthly moments.
+func (s *Settings) MonthTemplate() txt.DateTemplate {
+	if s.Moments.Month == "" {
+		return txt.DateTemplate(txt.GetLocale(s.Language).MonthTemplate)
+	}
+
+	return txt.DateTemplate(s.Moments.Month)
+}
+
+// WeekTemplate returns the name template for weekly moments.
+func (s *Settings) WeekTemplate() txt.DateTemplate {
+	if s.Moments.Week == "" {
+		return txt.DateTemplate(txt.GetLocale(s.Language).WeekTemplate)
+	}
+
+	return txt.DateTemplate(s.Moments.Week)
+}
+
+// Propagate updates settings in other packages as needed. Only global settings are propagated,
+// user settings like theme and language don't affect other packages.
 func (s *Settings) Propagate() {
+	entity.TitleTemplate = txt.TitleTemplate(s.Titles.Template)
+	entity.TitleLanguage = s.Language
+	entity.TitleWeekStart = s.WeekStart()
+	entity.QualityWeight = s.Quality.Weights()
+}
 
+// SetGlobal replaces global values like enabled features and library options, which can't be
+// changed per user.
+func (s *Settings) SetGlobal(g *Settings) {
+	s.Features = g.Features
+	s.Library = g.Library
+	s.Titles = g.Titles
+	s.Quality = g.Quality
 }
 
 // Load uses a yaml config file to initiate the configuration entity.
@@ -101,6 +239,10 @@ func (s *Settings) Load(fileName string) error {
 		return err
 	}
 
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
 	s.Propagate()
 
 	return nil
@@ -137,7 +279,73 @@ func (c *Config) initSettings() {
 	c.settings.Propagate()
 }
 
-// Settings returns the current user settings.
+// Settings returns the global settings, which are also used in public mode without user session.
 func (c *Config) Settings() *Settings {
 	return c.settings
 }
+
+// UserSettings returns the settings of a user. The global settings are used as default
+// and for values that can't be changed per user.
+func (c *Config) UserSettings(userID uint) *Settings {
+	if userID == 0 {
+		return c.settings
+	}
+
+	result := *c.settings
+
+	m, err := entity.FindUserSettings(c.Db(), userID)
+
+	if err != nil {
+		return &result
+	}
+
+	if err := json.Unmarshal([]byte(m.SettingsData), &result); err != nil {
+		log.Errorf("settings: %s (user %d)", err, userID)
+		result = *c.settings
+	}
+
+	result.SetGlobal(c.settings)
+
+	return &result
+}
+
+// SaveUserSettings stores the settings of a user, global values are ignored.
+func (c *Config) SaveUserSettings(userID uint, s *Settings) error {
+	if userID == 0 {
+		return fmt.Errorf("settings: invalid user id")
+	}
+
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	user := *s
+	user.SetGlobal(c.settings)
+
+	data, err := json.Marshal(user)
+
+	if err != nil {
+		return err
+	}
+
+	m := entity.UserSettings{UserID: userID, SettingsData: string(data)}
+
+	return m.Save(c.Db())
+}
+
+// importSettings stores the settings file as admin settings, if the admin doesn't have settings yet.
+func (c *Config) importSettings() {
+	if !fs.FileExists(c.SettingsFile()) {
+		return
+	}
+
+	if _, err := entity.FindUserSettings(c.Db(), entity.AdminUserID); err == nil {
+		return
+	}
+
+	if err := c.SaveUserSettings(entity.AdminUserID, c.settings); err != nil {
+		log.Errorf("settings: %s", err)
+	} else {
+		log.Infof("settings: imported %s as admin settings", filepath.Base(c.SettingsFile()))
+	}
+}
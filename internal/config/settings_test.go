@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/txt"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +69,133 @@ func TestSettings_Save(t *testing.T) {
 		}
 	})
 }
+
+func TestSettings_Validate(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		s := NewSettings()
+
+		assert.NoError(t, s.Validate())
+	})
+	t.Run("unknown token", func(t *testing.T) {
+		s := NewSettings()
+		s.Moments.Month = "{monthname} {year}"
+
+		assert.EqualError(t, s.Validate(), "unknown token \"{monthname}\" in template \"{monthname} {year}\"")
+	})
+	t.Run("unknown weekday", func(t *testing.T) {
+		s := NewSettings()
+		s.Moments.WeekStart = "someday"
+
+		assert.EqualError(t, s.Validate(), "unknown weekday \"someday\"")
+	})
+	t.Run("unknown title token", func(t *testing.T) {
+		s := NewSettings()
+		s.Titles.Template = "{label} / {town}"
+
+		assert.EqualError(t, s.Validate(), "unknown token \"{town}\" in template \"{label} / {town}\"")
+	})
+	t.Run("negative quality weight", func(t *testing.T) {
+		s := NewSettings()
+		s.Quality.Location = -1
+
+		assert.EqualError(t, s.Validate(), "location quality weight must be between 0 and 10")
+	})
+}
+
+func TestSettings_Propagate(t *testing.T) {
+	template, language, weekStart, weight := entity.TitleTemplate, entity.TitleLanguage, entity.TitleWeekStart, entity.QualityWeight
+
+	defer func() {
+		entity.TitleTemplate, entity.TitleLanguage, entity.TitleWeekStart, entity.QualityWeight = template, language, weekStart, weight
+	}()
+
+	s := NewSettings()
+	s.Language = "de"
+	s.Titles.Template = "{label} / {city} {year}"
+	s.Moments.WeekStart = "sunday"
+	s.Quality.Location = 2
+	s.Propagate()
+
+	assert.Equal(t, txt.TitleTemplate("{label} / {city} {year}"), entity.TitleTemplate)
+	assert.Equal(t, "de", entity.TitleLanguage)
+	assert.Equal(t, time.Sunday, entity.TitleWeekStart)
+	assert.Equal(t, entity.QualityWeights{Resolution: 1, Location: 2, Date: 1, Favorite: 3}, entity.QualityWeight)
+}
+
+func TestSettings_MonthTemplate(t *testing.T) {
+	t.Run("language default", func(t *testing.T) {
+		s := NewSettings()
+		s.Language = "ja"
+
+		assert.Equal(t, "{year}年{m}月", string(s.MonthTemplate()))
+		assert.Equal(t, "{year}年 第{week}週", string(s.WeekTemplate()))
+	})
+	t.Run("custom", func(t *testing.T) {
+		s := NewSettings()
+		s.Moments.Month = "{mm}/{year}"
+		s.Moments.WeekStart = "sunday"
+
+		assert.Equal(t, "{mm}/{year}", string(s.MonthTemplate()))
+		assert.Equal(t, time.Sunday, s.WeekStart())
+	})
+}
+
+func TestSettings_SetGlobal(t *testing.T) {
+	g := NewSettings()
+	g.Features.Upload = false
+	g.Titles.Template = "{city} {year}"
+	g.Quality.Favorite = 5
+
+	s := NewSettings()
+	s.Theme = "lavendel"
+	s.SetGlobal(g)
+
+	assert.Equal(t, "lavendel", s.Theme)
+	assert.False(t, s.Features.Upload)
+	assert.Equal(t, "{city} {year}", s.Titles.Template)
+	assert.Equal(t, 5, s.Quality.Favorite)
+}
+
+func TestConfig_UserSettings(t *testing.T) {
+	c := TestConfig()
+
+	t.Run("public", func(t *testing.T) {
+		assert.Same(t, c.Settings(), c.UserSettings(0))
+	})
+	t.Run("defaults", func(t *testing.T) {
+		s := c.UserSettings(1099)
+
+		assert.NotSame(t, c.Settings(), s)
+		assert.Equal(t, c.Settings().Theme, s.Theme)
+	})
+	t.Run("separate users", func(t *testing.T) {
+		a := c.UserSettings(1001)
+		a.Theme = "lavendel"
+		a.Language = "de"
+
+		b := c.UserSettings(1002)
+		b.Theme = "mint"
+
+		assert.NoError(t, c.SaveUserSettings(1001, a))
+		assert.NoError(t, c.SaveUserSettings(1002, b))
+
+		assert.Equal(t, "lavendel", c.UserSettings(1001).Theme)
+		assert.Equal(t, "de", c.UserSettings(1001).Language)
+		assert.Equal(t, "mint", c.UserSettings(1002).Theme)
+		assert.NotEqual(t, "lavendel", c.Settings().Theme)
+	})
+	t.Run("global values", func(t *testing.T) {
+		s := c.UserSettings(1003)
+		s.Features.Share = !c.Settings().Features.Share
+
+		assert.NoError(t, c.SaveUserSettings(1003, s))
+		assert.Equal(t, c.Settings().Features.Share, c.UserSettings(1003).Features.Share)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		s := c.UserSettings(1004)
+		s.Moments.WeekStart = "someday"
+
+		assert.Error(t, c.SaveUserSettings(1004, s))
+		assert.Error(t, c.SaveUserSettings(0, NewSettings()))
+	})
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"gopkg.in/yaml.v2"
+)
+
+// SetupOptions contains the initial configuration entered in the setup wizard. The admin password is
+// stored as hash of the default admin user, the other options are saved to the options file.
+type SetupOptions struct {
+	AdminPassword string `json:"password" yaml:"-"`
+	Title         string `json:"title" yaml:"title,omitempty"`
+	OriginalsPath string `json:"originalsPath" yaml:"originals-path,omitempty"`
+	ImportPath    string `json:"importPath" yaml:"import-path,omitempty"`
+}
+
+type setupState struct {
+	mutex sync.Mutex
+	done  bool
+}
+
+// SetupRequired returns true if neither an admin password was configured nor any user has a password,
+// so that anyone could log in or browse the library. Once setup is completed, it stays completed.
+func (c *Config) SetupRequired() bool {
+	c.setup.mutex.Lock()
+	defer c.setup.mutex.Unlock()
+
+	return c.setupRequired()
+}
+
+// setupRequired checks if setup is required, the caller must hold the setup lock. It fails closed,
+// so setup is required while the database is not connected or users can't be counted.
+func (c *Config) setupRequired() bool {
+	if c.setup.done || c.params.AdminPassword != "" {
+		return false
+	}
+
+	if c.db == nil {
+		return true
+	}
+
+	if n, err := entity.CountUsersWithPassword(c.db); err != nil {
+		log.Errorf("setup: %s", err)
+		return true
+	} else if n > 0 {
+		c.setup.done = true
+		return false
+	}
+
+	return true
+}
+
+// Setup sets the admin password, site title and storage paths entered in the setup wizard and
+// unlocks all other routes. Paths are validated like on startup and created if they don't exist.
+// WebDAV uses the new paths right away, file watchers are restarted by the workers.
+func (c *Config) Setup(opt SetupOptions) error {
+	c.setup.mutex.Lock()
+	defer c.setup.mutex.Unlock()
+
+	if !c.setupRequired() {
+		return ErrSetupCompleted
+	}
+
+	if c.db == nil {
+		return ErrDbNotConnected
+	}
+
+	params := *c.params
+
+	if title := strings.TrimSpace(opt.Title); title != "" {
+		params.Title = title
+	}
+
+	if opt.OriginalsPath != "" {
+		params.OriginalsPath = ParseRoots(opt.OriginalsPath).String()
+	}
+
+	if opt.ImportPath != "" {
+		params.ImportPath = fs.Abs(opt.ImportPath)
+	}
+
+	result := &Config{params: &params}
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	admin := entity.Admin
+
+	if err := c.db.FirstOrCreate(&admin, "id = ?", admin.ID).Error; err != nil {
+		return err
+	}
+
+	if err := admin.SetPassword(opt.AdminPassword); err != nil {
+		return err
+	}
+
+	dirs := []string{params.ImportPath}
+
+	for _, root := range result.OriginalsRoots() {
+		dirs = append(dirs, root.Path)
+	}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return fmt.Errorf("can't create \"%s\": please check permissions", dir)
+		}
+	}
+
+	saved := SetupOptions{Title: params.Title, OriginalsPath: params.OriginalsPath, ImportPath: params.ImportPath}
+
+	if err := saved.Save(c.OptionsFile()); err != nil {
+		return err
+	}
+
+	if err := admin.Save(c.db); err != nil {
+		return err
+	}
+
+	c.params.Title = params.Title
+	c.params.OriginalsPath = params.OriginalsPath
+	c.params.ImportPath = params.ImportPath
+	c.setup.done = true
+
+	log.Infof("setup: completed, options saved to %s", c.OptionsFile())
+
+	return nil
+}
+
+// Save writes the options to a yaml file, the admin password is never saved.
+func (opt SetupOptions) Save(fileName string) error {
+	data, err := yaml.Marshal(opt)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, data, 0600)
+}
+
+// Validate returns an error if the originals or import path is missing, is a file or if an originals
+// root and the import path are inside each other, so that imported files would be indexed twice.
+func (c *Config) Validate() error {
+	paths := []struct {
+		name string
+		path string
+	}{
+		{"originals", c.OriginalsPath()},
+		{"import", c.ImportPath()},
+	}
+
+	for _, p := range paths {
+		if p.path == "" {
+			return fmt.Errorf("%s path not configured", p.name)
+		}
+
+		if fs.FileExists(p.path) {
+			return fmt.Errorf("\"%s\" is a file, not a directory: please check your configuration", p.path)
+		}
+	}
+
+	imports := filepath.Clean(c.ImportPath()) + string(filepath.Separator)
+
+	for _, root := range c.OriginalsRoots() {
+		if fs.FileExists(root.Path) {
+			return fmt.Errorf("\"%s\" is a file, not a directory: please check your configuration", root.Path)
+		}
+
+		originals := filepath.Clean(root.Path) + string(filepath.Separator)
+
+		if strings.HasPrefix(originals, imports) || strings.HasPrefix(imports, originals) {
+			return fmt.Errorf("originals and import path must not be inside each other")
+		}
+	}
+
+	return nil
+}
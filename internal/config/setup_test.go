@@ -0,0 +1,143 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+
+		assert.NoError(t, c.Validate())
+	})
+	t.Run("empty", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.OriginalsPath = ""
+
+		assert.EqualError(t, c.Validate(), "originals path not configured")
+	})
+	t.Run("nested", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ImportPath = c.OriginalsPath() + "/import"
+
+		assert.Error(t, c.Validate())
+
+		c.params.ImportPath = c.OriginalsPath() + "-import"
+
+		assert.NoError(t, c.Validate())
+	})
+	t.Run("nested root", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.OriginalsPath = c.OriginalsPath() + ",archive=" + c.ImportPath() + "/archive"
+
+		assert.EqualError(t, c.Validate(), "originals and import path must not be inside each other")
+	})
+	t.Run("file", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ImportPath = "testdata/config.yml"
+
+		assert.Error(t, c.Validate())
+	})
+}
+
+func TestConfig_Setup(t *testing.T) {
+	c := TestConfig()
+
+	dir, err := ioutil.TempDir("", "setup")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := *c.params
+
+	defer func() {
+		*c.params = params
+		c.setup.done = false
+		os.RemoveAll(dir)
+		os.Remove(c.OptionsFile())
+		c.Db().Model(&entity.User{}).Where("id = ?", entity.AdminUserID).Update("password_hash", "")
+	}()
+
+	c.params.AdminPassword = ""
+	c.params.ConfigPath = filepath.Join(dir, "config")
+
+	assert.True(t, c.SetupRequired())
+
+	t.Run("invalid password", func(t *testing.T) {
+		err := c.Setup(SetupOptions{AdminPassword: "123"})
+
+		assert.Error(t, err)
+		assert.True(t, c.SetupRequired())
+	})
+	t.Run("invalid path", func(t *testing.T) {
+		err := c.Setup(SetupOptions{AdminPassword: "secret", OriginalsPath: dir, ImportPath: dir + "/import"})
+
+		assert.Error(t, err)
+		assert.True(t, c.SetupRequired())
+	})
+	t.Run("success", func(t *testing.T) {
+		opt := SetupOptions{
+			AdminPassword: "secret",
+			Title:         "Family Photos",
+			OriginalsPath: filepath.Join(dir, "originals"),
+			ImportPath:    filepath.Join(dir, "import"),
+		}
+
+		if err := c.Setup(opt); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, c.SetupRequired())
+		assert.Equal(t, "Family Photos", c.Title())
+		assert.Equal(t, opt.OriginalsPath, c.OriginalsPath())
+		assert.DirExists(t, opt.ImportPath)
+
+		user, err := c.Authenticate("admin", "secret")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, entity.AdminUserID, user.ID)
+
+		saved := &Params{}
+
+		if err := saved.Load(c.OptionsFile()); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Family Photos", saved.Title)
+		assert.Equal(t, opt.ImportPath, saved.ImportPath)
+		assert.Empty(t, saved.AdminPassword)
+	})
+	t.Run("completed", func(t *testing.T) {
+		err := c.Setup(SetupOptions{AdminPassword: "secret"})
+
+		assert.Equal(t, ErrSetupCompleted, err)
+	})
+}
+
+func TestConfig_SetupRequired(t *testing.T) {
+	t.Run("no database", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.AdminPassword = ""
+
+		// Setup is required until users can be counted, so that nobody can log in without password.
+		assert.True(t, c.SetupRequired())
+		assert.Equal(t, ErrDbNotConnected, c.Setup(SetupOptions{AdminPassword: "secret"}))
+		assert.True(t, c.SetupRequired())
+	})
+	t.Run("admin password", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.AdminPassword = "photoprism"
+
+		assert.False(t, c.SetupRequired())
+	})
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"strings"
+)
+
+// Primary file preferences for stacks of related files.
+const (
+	StackJpegFirst = "jpeg-first"
+	StackRawFirst  = "raw-first"
+)
+
+// StackSuffixes returns the file name suffixes of edited versions, e.g. "_edit" for IMG_1234_edit.jpg,
+// which should be stacked with the original if they were taken at the same time.
+func (c *Config) StackSuffixes() (result []string) {
+	for _, s := range strings.Split(c.params.StackSuffixes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// StackPrimary returns the primary file preference for stacks: jpeg-first prefers edited JPEG versions,
+// raw-first prefers the JPEG having the same name as the RAW file.
+func (c *Config) StackPrimary() string {
+	name := strings.ToLower(strings.TrimSpace(c.params.StackPrimary))
+
+	switch name {
+	case StackJpegFirst, StackRawFirst:
+		return name
+	case "":
+		return StackJpegFirst
+	default:
+		log.Warnf("config: unknown stack primary \"%s\", using %s", name, StackJpegFirst)
+		return StackJpegFirst
+	}
+}
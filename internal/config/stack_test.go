@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_StackSuffixes(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.params.StackSuffixes = "_edit, -2,,~1 "
+	assert.Equal(t, []string{"_edit", "-2", "~1"}, c.StackSuffixes())
+
+	c.params.StackSuffixes = ""
+	assert.Empty(t, c.StackSuffixes())
+}
+
+func TestConfig_StackPrimary(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, StackJpegFirst, c.StackPrimary())
+
+	c.params.StackPrimary = "RAW-first"
+	assert.Equal(t, StackRawFirst, c.StackPrimary())
+
+	c.params.StackPrimary = "xxx"
+	assert.Equal(t, StackJpegFirst, c.StackPrimary())
+}
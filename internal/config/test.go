@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 
@@ -40,8 +41,11 @@ func NewTestParams() *Params {
 	c := &Params{
 		Public:         true,
 		ReadOnly:       false,
+		AutoMigrate:    true,
+		IgnorePatterns: "@eaDir/, .*, *.tmp",
 		DetectNSFW:     true,
 		UploadNSFW:     false,
+		UploadLimit:    10000,
 		DarktableBin:   "/usr/bin/darktable-cli",
 		AssetsPath:     assetsPath,
 		CachePath:      testDataPath + "/cache",
@@ -63,6 +67,7 @@ func NewTestParamsError() *Params {
 
 	c := &Params{
 		DarktableBin:   "/usr/bin/darktable-cli",
+		AutoMigrate:    true,
 		AssetsPath:     assetsPath,
 		CachePath:      testDataPath + "/cache",
 		OriginalsPath:  testDataPath + "/originals",
@@ -109,11 +114,37 @@ func NewTestConfig() *Config {
 	thumb.JpegQuality = c.ThumbQuality()
 	thumb.PreRenderSize = c.ThumbSize()
 	thumb.MaxRenderSize = c.ThumbLimit()
+	thumb.SizeLimit = c.OriginalsLimit()
+	thumb.ResolutionLimit = c.ResolutionLimit()
 	thumb.Filter = c.ThumbFilter()
+	thumb.TypeFilters = c.ThumbFilters()
+	thumb.Sharpen = c.ThumbSharpen()
+	thumb.UseEmbedded = c.ThumbUseEmbedded()
+	thumb.ColorManagement = c.ThumbColorManagement()
+	thumb.Progressive = c.ThumbProgressive()
+	thumb.SmartCrop = c.ThumbSmartCrop()
+	thumb.JpegTranBin = c.JpegTranBin()
+	thumb.Copyright = c.ThumbCopyright()
+	thumb.Format = c.ThumbFormat()
+	thumb.CWebPBin = c.CWebPBin()
+
+	if sizes, err := c.ThumbSizes(); err == nil {
+		thumb.Install(sizes)
+	}
 
 	return c
 }
 
+// TestConfigRoots returns a copy of the test config with other originals roots like "archive=/tmp/archive",
+// the first root is the default root. It uses the same database and settings as the test config.
+func TestConfigRoots(roots ...string) *Config {
+	c := TestConfig()
+	params := *c.params
+	params.OriginalsPath = strings.Join(roots, ",")
+
+	return &Config{db: c.db, params: &params, settings: c.settings}
+}
+
 // NewTestErrorConfig inits invalid config used for testing
 func NewTestErrorConfig() *Config {
 	log.SetLevel(logrus.DebugLevel)
@@ -142,6 +173,7 @@ func CliTestContext() *cli.Context {
 	globalSet.String("cache-path", config.OriginalsPath, "doc")
 	globalSet.String("darktable-cli", config.DarktableBin, "doc")
 	globalSet.Bool("detect-nsfw", config.DetectNSFW, "doc")
+	globalSet.Bool("read-only", config.ReadOnly, "doc")
 
 	app := cli.NewApp()
 	app.Version = "1.0.0"
@@ -205,3 +237,50 @@ func (c *Config) InitializeTestData(t *testing.T) {
 
 	c.UnzipTestData(t)
 }
+
+// TestConfigDownloadTokens returns a copy of the test config with download tokens enabled, the instance key
+// is stored in configPath. It uses the same database and settings as the test config.
+func TestConfigDownloadTokens(configPath string, public bool) *Config {
+	c := TestConfig()
+	params := *c.params
+	params.Public = public
+	params.DownloadTokens = true
+	params.ConfigPath = configPath
+
+	return &Config{db: c.db, params: &params, settings: c.settings}
+}
+
+// TestConfigIndexBatchSize returns a copy of the test config with the originals path and the number of files
+// the indexer writes in one transaction. It uses the same database and settings as the test config.
+func TestConfigIndexBatchSize(originalsPath string, size int) *Config {
+	c := TestConfig()
+	params := *c.params
+	params.OriginalsPath = originalsPath
+	params.IndexBatchSize = size
+
+	return &Config{db: c.db, params: &params, settings: c.settings}
+}
+
+// TestConfigReadOnly returns a copy of the test config in read-only mode with the originals, sidecar and
+// heif-convert paths. It uses the same database and settings as the test config.
+func TestConfigReadOnly(originalsPath, sidecarPath, heifConvertBin string) *Config {
+	c := TestConfig()
+	params := *c.params
+	params.ReadOnly = true
+	params.OriginalsPath = originalsPath
+	params.SidecarPath = sidecarPath
+	params.HeifConvertBin = heifConvertBin
+
+	return &Config{db: c.db, params: &params, settings: c.settings}
+}
+
+// TestConfigWorkers returns a copy of the test config with the originals path and the number of workers.
+// It uses the same database and settings as the test config.
+func TestConfigWorkers(originalsPath string, workers int) *Config {
+	c := TestConfig()
+	params := *c.params
+	params.OriginalsPath = originalsPath
+	params.Workers = workers
+
+	return &Config{db: c.db, params: &params, settings: c.settings}
+}
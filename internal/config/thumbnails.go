@@ -1,5 +1,11 @@
 package config
 
+import (
+	"sort"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
 // Thumbnail gives direct access to width and height for a thumbnail setting
 type Thumbnail struct {
 	Name   string
@@ -7,5 +13,26 @@ type Thumbnail struct {
 	Height int
 }
 
-// Thumbnails is a list of default thumbnail size available for the app
-var Thumbnails []Thumbnail
+// Thumbnails returns the public thumbnail sizes available for the app, including user-defined sizes.
+func (c *Config) Thumbnails() (result []Thumbnail) {
+	for name, t := range thumb.Types {
+		if t.Public {
+			result = append(result, Thumbnail{Name: name, Width: t.Width, Height: t.Height})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Width == result[j].Width {
+			return result[i].Name < result[j].Name
+		}
+
+		return result[i].Width < result[j].Width
+	})
+
+	return result
+}
+
+// ThumbSizes returns the user-defined thumbnail sizes, see thumb.ParseSizes.
+func (c *Config) ThumbSizes() ([]thumb.Size, error) {
+	return thumb.ParseSizes(c.params.ThumbSizes, c.ThumbLimit())
+}
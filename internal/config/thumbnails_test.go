@@ -0,0 +1,159 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_ThumbSizes(t *testing.T) {
+	defer thumb.Install(nil)
+
+	t.Run("default", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+
+		sizes, err := c.ThumbSizes()
+
+		assert.Nil(t, err)
+		assert.Empty(t, sizes)
+	})
+	t.Run("override", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ThumbSizes = "fit_1280:1280x800"
+
+		sizes, err := c.ThumbSizes()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		thumb.Install(sizes)
+
+		assert.Equal(t, 800, thumb.Types["fit_1280"].Height)
+		assert.False(t, thumb.Types["fit_1280"].Public)
+		assert.Equal(t, "", thumb.Types["fit_1280"].Source)
+		assert.Contains(t, thumb.DefaultTypes, "fit_1280")
+	})
+	t.Run("addition", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ThumbSizes = "tv_1080:1920x1080:public, square_300:300x300:center"
+
+		sizes, err := c.ThumbSizes()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		thumb.Install(sizes)
+
+		assert.Equal(t, thumb.Type{Width: 1920, Height: 1080, Public: true, Options: []thumb.ResampleOption{thumb.ResampleFit, thumb.ResampleDefault}}, thumb.Types["tv_1080"])
+		assert.Equal(t, []thumb.ResampleOption{thumb.ResampleFillCenter, thumb.ResampleDefault}, thumb.Types["square_300"].Options)
+		assert.Equal(t, "square_300", thumb.DefaultTypes[len(thumb.DefaultTypes)-1])
+		assert.Contains(t, c.Thumbnails(), Thumbnail{Name: "tv_1080", Width: 1920, Height: 1080})
+		assert.NotContains(t, c.Thumbnails(), Thumbnail{Name: "square_300", Width: 300, Height: 300})
+	})
+	t.Run("remove", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ThumbSizes = "-fit_2048"
+
+		sizes, err := c.ThumbSizes()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		thumb.Install(sizes)
+
+		_, ok := thumb.Types["fit_2048"]
+
+		assert.False(t, ok)
+		assert.NotContains(t, thumb.DefaultTypes, "fit_2048")
+		assert.Equal(t, "", thumb.Types["fit_1280"].Source)
+	})
+	t.Run("reset", func(t *testing.T) {
+		thumb.Install(nil)
+
+		assert.Equal(t, "fit_2048", thumb.Types["fit_1280"].Source)
+		assert.Contains(t, thumb.DefaultTypes, "fit_2048")
+		assert.NotContains(t, thumb.DefaultTypes, "tv_1080")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+
+		for _, s := range []string{"tv_1080", "tv_1080:1920", "tv_1080:axb", "tv_1080:0x100", "huge:8000x8000", "tv_1080:1920x1080:blur", "-tile_224", "tile_224:300x300", "-"} {
+			c.params.ThumbSizes = s
+
+			_, err := c.ThumbSizes()
+
+			assert.Error(t, err, s)
+		}
+	})
+}
+
+func TestConfig_Thumbnails(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	result := c.Thumbnails()
+
+	assert.NotEmpty(t, result)
+	assert.Equal(t, Thumbnail{Name: "fit_720", Width: 720, Height: 720}, result[0])
+}
+
+func TestConfig_ThumbFilters(t *testing.T) {
+	defer func() {
+		thumb.Filter = thumb.ResampleLanczos
+		thumb.TypeFilters = map[string]thumb.ResampleFilter{}
+		thumb.Install(nil)
+	}()
+
+	t.Run("bare name", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ThumbFilter = "lanczos"
+
+		assert.Equal(t, thumb.ResampleLanczos, c.ThumbFilter())
+		assert.Empty(t, c.ThumbFilters())
+	})
+	t.Run("per type", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ThumbFilter = "tile_224:lanczos, fit_2048:linear,default:blackman"
+
+		assert.Equal(t, thumb.ResampleBlackman, c.ThumbFilter())
+		assert.Equal(t, map[string]thumb.ResampleFilter{"tile_224": thumb.ResampleLanczos, "fit_2048": thumb.ResampleLinear}, c.ThumbFilters())
+	})
+	t.Run("unknown filter", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ThumbFilter = "tile_224:sharp,foo"
+
+		assert.Equal(t, thumb.ResampleCubic, c.ThumbFilter())
+		assert.Empty(t, c.ThumbFilters())
+	})
+	t.Run("rendering", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.params.ThumbFilter = "tile_224:lanczos,default:cubic"
+		c.Propagate()
+
+		assert.Equal(t, thumb.ResampleLanczos, thumb.Types["tile_224"].Filter())
+		assert.Equal(t, thumb.ResampleCubic, thumb.Types["fit_2048"].Filter())
+		assert.Equal(t, thumb.ResampleFilter(""), thumb.Types["colors"].Filter())
+
+		_, filter, _ := thumb.ResampleOptions(thumb.Types["tile_224"].Options...)
+		assert.Equal(t, imaging.Lanczos.Support, filter.Support)
+
+		_, filter, _ = thumb.ResampleOptions(thumb.Types["tile_500"].Options...)
+		assert.Equal(t, imaging.CatmullRom.Support, filter.Support)
+	})
+}
+
+func TestConfig_ThumbSharpen(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 0.0, c.ThumbSharpen())
+
+	c.params.ThumbSharpen = 0.5
+	assert.Equal(t, 0.5, c.ThumbSharpen())
+
+	c.params.ThumbSharpen = 10
+	assert.Equal(t, 5.0, c.ThumbSharpen())
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"strings"
+)
+
+// WebhookURLs returns the URLs to notify of events with a JSON POST request.
+func (c *Config) WebhookURLs() (result []string) {
+	for _, u := range c.params.WebhookURL {
+		if u = strings.TrimSpace(u); u != "" {
+			result = append(result, u)
+		}
+	}
+
+	return result
+}
+
+// WebhookSecret returns the secret for signing webhook requests, or an empty string if they are not signed.
+func (c *Config) WebhookSecret() string {
+	return c.params.WebhookSecret
+}
+
+// WebhookEvents returns the event topics sent to webhooks, e.g. "import.completed" or "notify.*".
+func (c *Config) WebhookEvents() (result []string) {
+	for _, e := range strings.Split(c.params.WebhookEvents, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_WebhookURLs(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Empty(t, c.WebhookURLs())
+
+	c.params.WebhookURL = []string{" http://localhost:8080/hook", "", "https://example.com/photoprism"}
+	assert.Equal(t, []string{"http://localhost:8080/hook", "https://example.com/photoprism"}, c.WebhookURLs())
+}
+
+func TestConfig_WebhookSecret(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.WebhookSecret())
+
+	c.params.WebhookSecret = "foo"
+	assert.Equal(t, "foo", c.WebhookSecret())
+}
+
+func TestConfig_WebhookEvents(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.params.WebhookEvents = "import.completed, notify.*,,"
+	assert.Equal(t, []string{"import.completed", "notify.*"}, c.WebhookEvents())
+
+	c.params.WebhookEvents = ""
+	assert.Empty(t, c.WebhookEvents())
+}
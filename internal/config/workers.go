@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorkerNice returns how long background workers wait after the last api request before they continue,
+// 2 seconds by default. It's negative if workers should never pause.
+func (c *Config) WorkerNice() time.Duration {
+	if c.params.WorkerNice < 0 {
+		return -1
+	} else if c.params.WorkerNice == 0 {
+		return 2 * time.Second
+	}
+
+	return time.Duration(c.params.WorkerNice) * time.Second
+}
+
+// WorkerNiceLoad returns the load average per cpu above which background workers pause, 0 if disabled.
+func (c *Config) WorkerNiceLoad() float64 {
+	if c.params.WorkerNiceLoad < 0 {
+		return 0
+	}
+
+	return c.params.WorkerNiceLoad
+}
+
+// workerLoad returns true if the load average per cpu exceeds WorkerNiceLoad. The load average is
+// not available on all operating systems, in which case workers only pause for api requests.
+func (c *Config) workerLoad() bool {
+	max := c.WorkerNiceLoad()
+
+	if max <= 0 {
+		return false
+	}
+
+	load, err := loadAverage()
+
+	if err != nil {
+		return false
+	}
+
+	return load/float64(runtime.NumCPU()) > max
+}
+
+// loadAverage returns the system load average of the last minute.
+func loadAverage() (float64, error) {
+	data, err := ioutil.ReadFile("/proc/loadavg")
+
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid load average %q", data)
+	}
+
+	return strconv.ParseFloat(fields[0], 64)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_WorkerNice(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 2*time.Second, c.WorkerNice())
+
+	c.params.WorkerNice = 5
+	assert.Equal(t, 5*time.Second, c.WorkerNice())
+
+	c.params.WorkerNice = -1
+	assert.True(t, c.WorkerNice() < 0)
+}
+
+func TestConfig_WorkerNiceLoad(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, float64(0), c.WorkerNiceLoad())
+	assert.False(t, c.workerLoad())
+
+	c.params.WorkerNiceLoad = 1.5
+	assert.Equal(t, 1.5, c.WorkerNiceLoad())
+
+	c.params.WorkerNiceLoad = -1
+	assert.Equal(t, float64(0), c.WorkerNiceLoad())
+}
@@ -22,33 +22,39 @@ const (
 
 // Account represents a remote service account for uploading, downloading or syncing media files.
 type Account struct {
-	ID            uint   `gorm:"primary_key"`
-	AccName       string `gorm:"type:varchar(255);"`
-	AccOwner      string `gorm:"type:varchar(255);"`
-	AccURL        string `gorm:"type:varbinary(512);"`
-	AccType       string `gorm:"type:varbinary(255);"`
-	AccKey        string `gorm:"type:varbinary(255);"`
-	AccUser       string `gorm:"type:varbinary(255);"`
-	AccPass       string `gorm:"type:varbinary(255);"`
-	AccError      string `gorm:"type:varbinary(512);"`
-	AccErrors     int
-	AccShare      bool
-	AccSync       bool
-	RetryLimit    int
-	SharePath     string `gorm:"type:varbinary(255);"`
-	ShareSize     string `gorm:"type:varbinary(16);"`
-	ShareExpires  int
-	SyncPath      string `gorm:"type:varbinary(255);"`
-	SyncStatus    string `gorm:"type:varbinary(16);"`
-	SyncInterval  int
-	SyncDate      sql.NullTime `deepcopier:"skip"`
-	SyncUpload    bool
-	SyncDownload  bool
-	SyncFilenames bool
-	SyncRaw       bool
-	CreatedAt     time.Time  `deepcopier:"skip"`
-	UpdatedAt     time.Time  `deepcopier:"skip"`
-	DeletedAt     *time.Time `deepcopier:"skip" sql:"index"`
+	ID                uint   `gorm:"primary_key"`
+	AccName           string `gorm:"type:varchar(255);"`
+	AccOwner          string `gorm:"type:varchar(255);"`
+	AccURL            string `gorm:"type:varbinary(512);"`
+	AccType           string `gorm:"type:varbinary(255);"`
+	AccKey            string `gorm:"type:varbinary(255);"`
+	AccUser           string `gorm:"type:varbinary(255);"`
+	AccPass           string `gorm:"type:varbinary(255);"`
+	AccError          string `gorm:"type:varbinary(512);"`
+	AccErrors         int
+	AccShare          bool
+	AccSync           bool
+	RetryLimit        int
+	SharePath         string `gorm:"type:varbinary(255);"`
+	ShareSize         string `gorm:"type:varbinary(16);"`
+	ShareExpires      int
+	SyncPath          string `gorm:"type:varbinary(255);"`
+	SyncStatus        string `gorm:"type:varbinary(16);"`
+	SyncInterval      int
+	SyncDate          sql.NullTime `deepcopier:"skip"`
+	SyncUpload        bool
+	SyncDownload      bool
+	SyncFilenames     bool
+	SyncRaw           bool
+	SyncWindow        string `gorm:"type:varbinary(16);"`
+	SyncUploadLimit   int
+	SyncDownloadLimit int
+	SyncConflicts     string `gorm:"type:varbinary(16);"`
+	SyncDeletes       bool
+	SyncCursor        string     `gorm:"type:varbinary(255);" deepcopier:"skip"`
+	CreatedAt         time.Time  `deepcopier:"skip"`
+	UpdatedAt         time.Time  `deepcopier:"skip"`
+	DeletedAt         *time.Time `deepcopier:"skip" sql:"index"`
 }
 
 // CreateAccount creates a new account entity in the database.
@@ -86,6 +92,10 @@ func (m *Account) Save(form form.Account, db *gorm.DB) error {
 		m.SyncPath = "/"
 	}
 
+	if m.SyncConflicts == "" {
+		m.SyncConflicts = remote.ConflictNewestWins
+	}
+
 	// Refresh after performing changes
 	if m.AccSync && m.SyncStatus == AccountSyncStatusSynced {
 		m.SyncStatus = AccountSyncStatusRefresh
@@ -99,6 +109,11 @@ func (m *Account) Delete(db *gorm.DB) error {
 	return db.Delete(m).Error
 }
 
+// TwoWaySync returns true if files are downloaded and uploaded, so that changes on both sides must be reconciled.
+func (m *Account) TwoWaySync() bool {
+	return m.SyncDownload && m.SyncUpload
+}
+
 // Directories returns a list of directories or albums in an account.
 func (m *Account) Directories() (result fs.FileInfos, err error) {
 	if m.AccType == remote.ServiceWebDAV {
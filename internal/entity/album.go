@@ -1,12 +1,16 @@
 package entity
 
 import (
+	"fmt"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/gosimple/slug"
 	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/pkg/rnd"
 	"github.com/photoprism/photoprism/pkg/txt"
 	"github.com/ulule/deepcopier"
@@ -18,12 +22,18 @@ type Album struct {
 	CoverUUID        string `gorm:"type:varbinary(36);"`
 	AlbumUUID        string `gorm:"type:varbinary(36);unique_index;"`
 	AlbumSlug        string `gorm:"type:varbinary(255);index;"`
+	AlbumType        string `gorm:"type:varbinary(8);default:'album';"`
+	AlbumRoot        string `gorm:"type:varbinary(16);"`
+	AlbumPath        string `gorm:"type:varbinary(768);"`
+	AlbumFilter      string `gorm:"type:varbinary(1024);"`
 	AlbumName        string `gorm:"type:varchar(255);"`
+	AlbumSearch      string `gorm:"type:varbinary(255);" json:"-"`
 	AlbumDescription string `gorm:"type:text;"`
 	AlbumNotes       string `gorm:"type:text;"`
 	AlbumOrder       string `gorm:"type:varbinary(32);"`
 	AlbumTemplate    string `gorm:"type:varbinary(255);"`
 	AlbumFavorite    bool
+	AlbumVisibility  string `gorm:"type:varbinary(16);"`
 	Links            []Link `gorm:"foreignkey:ShareUUID;association_foreignkey:AlbumUUID"`
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
@@ -39,12 +49,18 @@ func (m *Album) BeforeCreate(scope *gorm.Scope) error {
 	return nil
 }
 
+// BeforeSave updates the normalized album name used for search
+func (m *Album) BeforeSave(scope *gorm.Scope) error {
+	return setSearch(scope, "AlbumSearch", "album_name", m.AlbumName)
+}
+
 // NewAlbum creates a new album; default name is current month and year
 func NewAlbum(name string) *Album {
 	now := time.Now().UTC()
 
 	result := &Album{
 		AlbumUUID:  rnd.PPID('a'),
+		AlbumType:  AlbumManual,
 		AlbumOrder: SortOrderOldest,
 		CreatedAt:  now,
 		UpdatedAt:  now,
@@ -72,15 +88,114 @@ func (m *Album) SetName(name string) {
 	}
 }
 
+// IsManual returns true if photos are added to and removed from the album manually.
+func (m *Album) IsManual() bool {
+	return m.AlbumType == AlbumManual || m.AlbumType == ""
+}
+
+// IsFolder returns true if the album contains all photos in a folder.
+func (m *Album) IsFolder() bool {
+	return m.AlbumType == AlbumFolder
+}
+
+// IsSmart returns true if the album contains all photos matching a search filter.
+func (m *Album) IsSmart() bool {
+	return m.AlbumType == AlbumSmart
+}
+
+// SetType changes the album type, the folder path relative to originals is only kept for
+// folder albums and the search filter only for smart albums.
+func (m *Album) SetType(albumType, folder, filter string) error {
+	switch albumType {
+	case "", AlbumManual:
+		m.AlbumType = AlbumManual
+		m.AlbumRoot = ""
+		m.AlbumPath = ""
+		m.AlbumFilter = ""
+	case AlbumFolder:
+		// Cleaning an absolute path removes ".." so that folders outside originals can't be used.
+		m.AlbumType = AlbumFolder
+		m.AlbumPath = strings.Trim(path.Clean("/"+strings.TrimSpace(folder)), "/")
+
+		if m.AlbumRoot == "" {
+			m.AlbumRoot = RootDefault
+		}
+		m.AlbumFilter = ""
+	case AlbumSmart:
+		filter = strings.TrimSpace(filter)
+
+		if filter == "" {
+			return fmt.Errorf("album: smart albums need a search filter")
+		}
+
+		f := form.PhotoSearch{Query: filter}
+
+		if err := f.ParseQueryString(); err != nil {
+			return fmt.Errorf("album: invalid search filter \"%s\" (%s)", filter, err)
+		}
+
+		m.AlbumType = AlbumSmart
+		m.AlbumRoot = ""
+		m.AlbumPath = ""
+		m.AlbumFilter = filter
+	default:
+		return fmt.Errorf("album: unknown type \"%s\"", albumType)
+	}
+
+	return nil
+}
+
 // Save updates the entity using form data and stores it in the database.
+// The album type can't be changed, only the folder path or search filter.
 func (m *Album) Save(f form.Album, db *gorm.DB) error {
+	albumType := m.AlbumType
+
 	if err := deepcopier.Copy(m).From(f); err != nil {
 		return err
 	}
 
+	if err := m.SetType(albumType, f.AlbumPath, f.AlbumFilter); err != nil {
+		return err
+	}
+
 	if f.AlbumName != "" {
 		m.SetName(f.AlbumName)
 	}
 
 	return db.Save(m).Error
 }
+
+// DeleteAlbums flags albums as deleted and removes their photo entries and permissions in a single transaction.
+func DeleteAlbums(db *gorm.DB, albumUUIDs []string) error {
+	if len(albumUUIDs) == 0 {
+		return nil
+	}
+
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	tx := db.Begin()
+
+	if err := tx.Where("album_uuid IN (?)", albumUUIDs).Delete(&PhotoAlbum{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("album_uuid IN (?)", albumUUIDs).Delete(&AlbumPermission{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("album_uuid IN (?)", albumUUIDs).Delete(&Album{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	event.EntitiesDeleted("albums", albumUUIDs)
+
+	return nil
+}
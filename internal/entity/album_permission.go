@@ -0,0 +1,158 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/mutex"
+)
+
+const (
+	// album visibility
+	VisibleEveryone = "everyone"
+	VisibleRoles    = "roles"
+	VisibleUsers    = "users"
+
+	// permission types
+	PermRole = "role"
+	PermUser = "user"
+)
+
+// AlbumPermission grants a role or user access to an album with restricted visibility.
+type AlbumPermission struct {
+	AlbumUUID string `gorm:"type:varbinary(36);primary_key;auto_increment:false"`
+	PermType  string `gorm:"type:varbinary(16);primary_key;auto_increment:false"`
+	PermName  string `gorm:"type:varbinary(255);primary_key;auto_increment:false"`
+}
+
+// TableName returns AlbumPermission table identifier "albums_permissions"
+func (AlbumPermission) TableName() string {
+	return "albums_permissions"
+}
+
+// AlbumAccess represents the visibility of an album and the roles or users it's restricted to.
+type AlbumAccess struct {
+	Visibility string   `json:"visibility"`
+	Roles      []string `json:"roles"`
+	Users      []string `json:"users"`
+}
+
+// Validate returns an error if the visibility is unknown or doesn't match the granted roles or users.
+func (a *AlbumAccess) Validate() error {
+	a.Visibility = strings.ToLower(strings.TrimSpace(a.Visibility))
+
+	switch a.Visibility {
+	case "", VisibleEveryone:
+		a.Visibility = VisibleEveryone
+	case VisibleRoles:
+		if len(a.Roles) == 0 {
+			return fmt.Errorf("no roles specified")
+		}
+	case VisibleUsers:
+		if len(a.Users) == 0 {
+			return fmt.Errorf("no users specified")
+		}
+	default:
+		return fmt.Errorf("unknown visibility \"%s\"", a.Visibility)
+	}
+
+	return nil
+}
+
+// Permissions returns the album permission entities for roles or users, depending on the visibility.
+func (a AlbumAccess) Permissions(albumUUID string) (result []AlbumPermission) {
+	var permType string
+	var names []string
+
+	switch a.Visibility {
+	case VisibleRoles:
+		permType, names = PermRole, a.Roles
+	case VisibleUsers:
+		permType, names = PermUser, a.Users
+	default:
+		return result
+	}
+
+	done := make(map[string]bool)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+
+		if name == "" || done[name] {
+			continue
+		}
+
+		done[name] = true
+
+		result = append(result, AlbumPermission{AlbumUUID: albumUUID, PermType: permType, PermName: name})
+	}
+
+	return result
+}
+
+// Access returns the visibility of the album and the roles or users it's restricted to.
+func (m *Album) Access(db *gorm.DB) (result AlbumAccess) {
+	result.Visibility = m.AlbumVisibility
+	result.Roles = []string{}
+	result.Users = []string{}
+
+	if result.Visibility == "" {
+		result.Visibility = VisibleEveryone
+	}
+
+	var perms []AlbumPermission
+
+	if err := db.Where("album_uuid = ?", m.AlbumUUID).Order("perm_name").Find(&perms).Error; err != nil {
+		log.Errorf("album: %s", err)
+		return result
+	}
+
+	for _, p := range perms {
+		switch p.PermType {
+		case PermRole:
+			result.Roles = append(result.Roles, p.PermName)
+		case PermUser:
+			result.Users = append(result.Users, p.PermName)
+		}
+	}
+
+	return result
+}
+
+// SetAccess replaces the visibility and permissions of the album.
+func (m *Album) SetAccess(db *gorm.DB, access AlbumAccess) error {
+	if err := access.Validate(); err != nil {
+		return err
+	}
+
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	tx := db.Begin()
+
+	if err := tx.Model(m).UpdateColumn("album_visibility", access.Visibility).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("album_uuid = ?", m.AlbumUUID).Delete(&AlbumPermission{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, p := range access.Permissions(m.AlbumUUID) {
+		if err := tx.Create(&p).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	m.AlbumVisibility = access.Visibility
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlbumPermission_TableName(t *testing.T) {
+	assert.Equal(t, "albums_permissions", AlbumPermission{}.TableName())
+}
+
+func TestAlbumAccess_Validate(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		a := AlbumAccess{}
+
+		assert.Nil(t, a.Validate())
+		assert.Equal(t, VisibleEveryone, a.Visibility)
+	})
+	t.Run("roles", func(t *testing.T) {
+		a := AlbumAccess{Visibility: " Roles ", Roles: []string{"family"}}
+
+		assert.Nil(t, a.Validate())
+		assert.Equal(t, VisibleRoles, a.Visibility)
+	})
+	t.Run("no roles", func(t *testing.T) {
+		a := AlbumAccess{Visibility: VisibleRoles, Users: []string{"jane"}}
+
+		assert.EqualError(t, a.Validate(), "no roles specified")
+	})
+	t.Run("no users", func(t *testing.T) {
+		a := AlbumAccess{Visibility: VisibleUsers}
+
+		assert.EqualError(t, a.Validate(), "no users specified")
+	})
+	t.Run("unknown", func(t *testing.T) {
+		a := AlbumAccess{Visibility: "friends"}
+
+		assert.EqualError(t, a.Validate(), "unknown visibility \"friends\"")
+	})
+}
+
+func TestAlbumAccess_Permissions(t *testing.T) {
+	t.Run("everyone", func(t *testing.T) {
+		a := AlbumAccess{Visibility: VisibleEveryone, Roles: []string{"family"}}
+
+		assert.Empty(t, a.Permissions("at9lxuqxpogaaba7"))
+	})
+	t.Run("roles", func(t *testing.T) {
+		a := AlbumAccess{Visibility: VisibleRoles, Roles: []string{"family", " family", "", "kids"}, Users: []string{"jane"}}
+
+		result := a.Permissions("at9lxuqxpogaaba7")
+
+		assert.Equal(t, []AlbumPermission{
+			{AlbumUUID: "at9lxuqxpogaaba7", PermType: PermRole, PermName: "family"},
+			{AlbumUUID: "at9lxuqxpogaaba7", PermType: PermRole, PermName: "kids"},
+		}, result)
+	})
+	t.Run("users", func(t *testing.T) {
+		a := AlbumAccess{Visibility: VisibleUsers, Users: []string{"jane"}}
+
+		result := a.Permissions("at9lxuqxpogaaba7")
+
+		assert.Equal(t, []AlbumPermission{{AlbumUUID: "at9lxuqxpogaaba7", PermType: PermUser, PermName: "jane"}}, result)
+	})
+}
@@ -61,3 +61,65 @@ is an oblate spheroid.`
 		assert.Contains(t, album.AlbumSlug, slug.Make(slugExpected))
 	})
 }
+
+func TestAlbum_SetType(t *testing.T) {
+	t.Run("manual", func(t *testing.T) {
+		album := NewAlbum("Manual")
+
+		assert.True(t, album.IsManual())
+		assert.Nil(t, album.SetType("", "2020", "favorite:true"))
+		assert.Equal(t, AlbumManual, album.AlbumType)
+		assert.Empty(t, album.AlbumPath)
+		assert.Empty(t, album.AlbumFilter)
+	})
+	t.Run("folder", func(t *testing.T) {
+		album := NewAlbum("Folder")
+
+		assert.Nil(t, album.SetType(AlbumFolder, " /2020/holiday/ ", ""))
+		assert.True(t, album.IsFolder())
+		assert.False(t, album.IsManual())
+		assert.Equal(t, "2020/holiday", album.AlbumPath)
+		assert.Equal(t, RootDefault, album.AlbumRoot)
+	})
+	t.Run("folder in other root", func(t *testing.T) {
+		album := NewAlbum("Folder")
+		album.AlbumRoot = "archive"
+
+		assert.Nil(t, album.SetType(AlbumFolder, "2010", ""))
+		assert.Equal(t, "archive", album.AlbumRoot)
+		assert.Equal(t, "2010", album.AlbumPath)
+
+		assert.Nil(t, album.SetType(AlbumManual, "", ""))
+		assert.Empty(t, album.AlbumRoot)
+	})
+	t.Run("folder outside originals", func(t *testing.T) {
+		album := NewAlbum("Folder")
+
+		assert.Nil(t, album.SetType(AlbumFolder, "../../etc", ""))
+		assert.Equal(t, "etc", album.AlbumPath)
+	})
+	t.Run("smart", func(t *testing.T) {
+		album := NewAlbum("Smart")
+
+		assert.Nil(t, album.SetType(AlbumSmart, "", " favorite:true "))
+		assert.True(t, album.IsSmart())
+		assert.Equal(t, "favorite:true", album.AlbumFilter)
+	})
+	t.Run("smart without filter", func(t *testing.T) {
+		album := NewAlbum("Smart")
+
+		assert.EqualError(t, album.SetType(AlbumSmart, "", ""), "album: smart albums need a search filter")
+		assert.True(t, album.IsManual())
+	})
+	t.Run("smart with invalid filter", func(t *testing.T) {
+		album := NewAlbum("Smart")
+
+		assert.Error(t, album.SetType(AlbumSmart, "", "xxx:bla"))
+		assert.True(t, album.IsManual())
+	})
+	t.Run("unknown", func(t *testing.T) {
+		album := NewAlbum("Unknown")
+
+		assert.EqualError(t, album.SetType("moment", "", ""), "album: unknown type \"moment\"")
+	})
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// API token scopes.
+const (
+	ScopeRead   = "read"
+	ScopeUpload = "upload"
+	ScopeFull   = "full"
+)
+
+// ApiTokenPrefixLength is the number of token characters stored as plain text to identify tokens.
+const ApiTokenPrefixLength = 8
+
+// ApiTokenScopes contains all valid API token scopes.
+var ApiTokenScopes = []string{ScopeRead, ScopeUpload, ScopeFull}
+
+// ApiToken represents a long-lived token for non-interactive API clients, only a hash of the token is stored.
+type ApiToken struct {
+	ID          uint       `gorm:"primary_key" json:"ID"`
+	TokenHash   string     `gorm:"type:varbinary(64);unique_index;" json:"-"`
+	TokenPrefix string     `gorm:"type:varbinary(16);" json:"Prefix"`
+	TokenLabel  string     `gorm:"type:varchar(255);" json:"Label"`
+	TokenScope  string     `gorm:"type:varbinary(16);" json:"Scope"`
+	UserID      uint       `json:"UserID"`
+	LastUsed    *time.Time `json:"LastUsed"`
+	CreatedAt   time.Time  `json:"CreatedAt"`
+	UpdatedAt   time.Time  `json:"UpdatedAt"`
+}
+
+// NewApiToken returns a new API token entity and the token, which can't be retrieved afterwards.
+func NewApiToken(label, scope string, userID uint) (*ApiToken, string, error) {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+
+	if scope == "" {
+		scope = ScopeRead
+	}
+
+	m := &ApiToken{
+		TokenLabel: strings.TrimSpace(label),
+		TokenScope: scope,
+		UserID:     userID,
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	b := make([]byte, 24)
+
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+
+	token := hex.EncodeToString(b)
+
+	m.TokenHash = SessionHash(token)
+	m.TokenPrefix = token[:ApiTokenPrefixLength]
+
+	return m, token, nil
+}
+
+// FindApiToken returns the API token entity for a token.
+func FindApiToken(db *gorm.DB, token string) (*ApiToken, error) {
+	var result ApiToken
+
+	if len(token) <= ApiTokenPrefixLength {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if err := db.Where("token_hash = ?", SessionHash(token)).First(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Validate returns an error if the label is empty or the scope is unknown.
+func (m *ApiToken) Validate() error {
+	if m.TokenLabel == "" {
+		return fmt.Errorf("label must not be empty")
+	}
+
+	for _, scope := range ApiTokenScopes {
+		if m.TokenScope == scope {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown scope \"%s\", use %s", m.TokenScope, strings.Join(ApiTokenScopes, ", "))
+}
+
+// Role returns the user role matching the token scope.
+func (m *ApiToken) Role() string {
+	switch m.TokenScope {
+	case ScopeFull:
+		return RoleAdmin
+	case ScopeUpload:
+		return RoleUploader
+	default:
+		return RoleViewer
+	}
+}
+
+// Touch updates the time the token was last used, at most once per minute.
+func (m *ApiToken) Touch(db *gorm.DB) error {
+	now := time.Now()
+
+	if m.LastUsed != nil && now.Sub(*m.LastUsed) < time.Minute {
+		return nil
+	}
+
+	m.LastUsed = &now
+
+	return db.Model(m).UpdateColumn("last_used", now).Error
+}
+
+// Save creates or updates the API token.
+func (m *ApiToken) Save(db *gorm.DB) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	return db.Save(m).Error
+}
+
+// Delete revokes the API token.
+func (m *ApiToken) Delete(db *gorm.DB) error {
+	return db.Delete(m).Error
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewApiToken(t *testing.T) {
+	t.Run("default scope", func(t *testing.T) {
+		m, token, err := NewApiToken("Upload script", "", AdminUserID)
+
+		assert.NoError(t, err)
+		assert.Len(t, token, 48)
+		assert.Equal(t, ScopeRead, m.TokenScope)
+		assert.Equal(t, token[:ApiTokenPrefixLength], m.TokenPrefix)
+		assert.Equal(t, SessionHash(token), m.TokenHash)
+		assert.NotContains(t, m.TokenHash, token)
+	})
+	t.Run("unknown scope", func(t *testing.T) {
+		_, _, err := NewApiToken("Upload script", "write", AdminUserID)
+		assert.EqualError(t, err, "unknown scope \"write\", use read, upload, full")
+	})
+	t.Run("empty label", func(t *testing.T) {
+		_, _, err := NewApiToken(" ", ScopeFull, AdminUserID)
+		assert.EqualError(t, err, "label must not be empty")
+	})
+}
+
+func TestApiToken_Role(t *testing.T) {
+	assert.Equal(t, RoleViewer, (&ApiToken{TokenScope: ScopeRead}).Role())
+	assert.Equal(t, RoleUploader, (&ApiToken{TokenScope: ScopeUpload}).Role())
+	assert.Equal(t, RoleAdmin, (&ApiToken{TokenScope: ScopeFull}).Role())
+	assert.Equal(t, RoleViewer, (&ApiToken{}).Role())
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/gosimple/slug"
 	"github.com/jinzhu/gorm"
-	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -66,8 +65,7 @@ func NewCamera(modelName string, makeName string) *Camera {
 
 // FirstOrCreate checks wether the camera model exist already in the database
 func (m *Camera) FirstOrCreate(db *gorm.DB) *Camera {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "camera_model = ? AND camera_make = ?", m.CameraModel, m.CameraMake).Error; err != nil {
 		log.Errorf("camera: %s", err)
@@ -17,4 +17,22 @@ const (
 	SortOrderOldest    = "oldest"
 	SortOrderImported  = "imported"
 	SortOrderSimilar   = "similar"
+	SortOrderDistance  = "distance"
+
+	// album types
+	AlbumManual = "album"
+	AlbumFolder = "folder"
+	AlbumSmart  = "smart"
+
+	// location sources
+	LocSourceOffline = "offline"
+
+	// originals roots
+	RootDefault = "default"
+	RootSidecar = "sidecar"
+
+	// file error stages
+	StageMetadata  = "metadata"
+	StageThumbnail = "thumbnail"
+	StageConvert   = "convert"
 )
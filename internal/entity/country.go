@@ -4,7 +4,6 @@ import (
 	"github.com/gosimple/slug"
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/maps"
-	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 // altCountryNames defines mapping between different names for the same countriy
@@ -61,8 +60,7 @@ func NewCountry(countryCode string, countryName string) *Country {
 
 // FirstOrCreate checks wether the country exist already in the database (using countryCode)
 func (m *Country) FirstOrCreate(db *gorm.DB) *Country {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "id = ?", m.ID).Error; err != nil {
 		log.Errorf("country: %s", err)
@@ -2,7 +2,6 @@ package entity
 
 import (
 	"github.com/jinzhu/gorm"
-	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 // Description stores additional metadata fields for each photo to improve search performance.
@@ -19,8 +18,7 @@ type Description struct {
 
 // FirstOrCreate returns the matching entity or creates a new one.
 func (m *Description) FirstOrCreate(db *gorm.DB) error {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	return db.FirstOrCreate(m, "photo_id = ?", m.PhotoID).Error
 }
@@ -10,8 +10,11 @@ https://github.com/photoprism/photoprism/wiki/Storage
 package entity
 
 import (
+	"database/sql"
+
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 var log = event.Log
@@ -21,3 +24,48 @@ func logError(result *gorm.DB) {
 		log.Error(result.Error.Error())
 	}
 }
+
+// lockDb locks mutex.Db to prevent duplicate rows and returns the unlock function. Transactions don't
+// wait for the lock, as its holder may wait for rows locked by the transaction. Duplicates cause the
+// transaction to fail instead, e.g. index batches are then indexed again file by file.
+func lockDb(db *gorm.DB) (unlock func()) {
+	if _, ok := db.CommonDB().(*sql.Tx); ok {
+		return func() {}
+	}
+
+	mutex.Db.Lock()
+
+	return mutex.Db.Unlock
+}
+
+// Entities contains all models with a database table, e.g. for migrations.
+var Entities = []interface{}{
+	&Account{},
+	&File{},
+	&FileShare{},
+	&FileSync{},
+	&Photo{},
+	&Description{},
+	&Event{},
+	&Place{},
+	&Location{},
+	&GeoCache{},
+	&Camera{},
+	&Lens{},
+	&Country{},
+	&Album{},
+	&PhotoAlbum{},
+	&AlbumPermission{},
+	&Label{},
+	&Category{},
+	&PhotoLabel{},
+	&Keyword{},
+	&PhotoKeyword{},
+	&Link{},
+	&User{},
+	&UserSettings{},
+	&Session{},
+	&Run{},
+	&ApiToken{},
+	&Migration{},
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,3 +19,39 @@ func TestMain(m *testing.M) {
 	code := m.Run()
 	os.Exit(code)
 }
+
+func TestLockDb(t *testing.T) {
+	db, cleanup := newRunTestDb(t)
+	defer cleanup()
+
+	t.Run("db", func(t *testing.T) {
+		unlock := lockDb(db)
+
+		locked := make(chan bool)
+
+		go func() {
+			mutex.Db.Lock()
+			close(locked)
+			mutex.Db.Unlock()
+		}()
+
+		select {
+		case <-locked:
+			t.Fatal("mutex.Db was not locked")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		unlock()
+		<-locked
+	})
+	t.Run("transaction", func(t *testing.T) {
+		mutex.Db.Lock()
+		defer mutex.Db.Unlock()
+
+		tx := db.Begin()
+		defer tx.Rollback()
+
+		// Transactions must not wait for the lock.
+		lockDb(tx)()
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gosimple/slug"
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
 // File represents an image or sidecar file that belongs to a photo
@@ -17,9 +18,11 @@ type File struct {
 	PhotoID         uint   `gorm:"index;"`
 	PhotoUUID       string `gorm:"type:varbinary(36);index;"`
 	FileUUID        string `gorm:"type:varbinary(36);unique_index;"`
-	FileName        string `gorm:"type:varbinary(768);unique_index"`
+	FileRoot        string `gorm:"type:varbinary(16);default:'default';unique_index:uix_files_root_name"`
+	FileName        string `gorm:"type:varbinary(768);unique_index:uix_files_root_name"`
 	OriginalName    string `gorm:"type:varbinary(768);"`
 	FileHash        string `gorm:"type:varbinary(128);index"`
+	FilePhash       string `gorm:"type:varbinary(16);index"`
 	FileModified    time.Time
 	FileSize        int64
 	FileType        string `gorm:"type:varbinary(32)"`
@@ -33,14 +36,23 @@ type File struct {
 	FileWidth       int
 	FileHeight      int
 	FileOrientation int
+	FileCodec       string `gorm:"type:varbinary(32)"`
+	FileDuration    time.Duration
+	FileHDR         bool
+	FileAudioCodec  string `gorm:"type:varbinary(32)"`
+	FileChannels    int
 	FileAspectRatio float32 `gorm:"type:FLOAT;"`
 	FileMainColor   string  `gorm:"type:varbinary(16);index;"`
 	FileColors      string  `gorm:"type:binary(9);"`
 	FileLuminance   string  `gorm:"type:binary(9);"`
 	FileDiff        uint32
 	FileChroma      uint8
+	FileCrop        string `gorm:"type:varbinary(16);"`
 	FileNotes       string `gorm:"type:text"`
 	FileError       string `gorm:"type:varbinary(512)"`
+	FileErrorStage  string `gorm:"type:varbinary(16);index;"`
+	FileErrorAt     *time.Time
+	FileTimeline    string `gorm:"type:text" json:"-"`
 	Share           []FileShare
 	Sync            []FileSync
 	Links           []Link `gorm:"foreignkey:ShareUUID;association_foreignkey:FileUUID"`
@@ -65,6 +77,45 @@ func (m *File) BeforeCreate(scope *gorm.Scope) error {
 	return scope.SetColumn("FileUUID", rnd.PPID('f'))
 }
 
+// Root returns the name of the originals root the file name is relative to.
+func (m *File) Root() string {
+	if m.FileRoot == "" {
+		return RootDefault
+	}
+
+	return m.FileRoot
+}
+
+// SetError records an error that occurred while processing the file at the given stage,
+// like StageMetadata. Only the most recent error is kept.
+func (m *File) SetError(stage string, err error) {
+	if err == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	m.FileError = txt.Clip(err.Error(), 512)
+	m.FileErrorStage = stage
+	m.FileErrorAt = &now
+}
+
+// ResetError removes the recorded error, e.g. after the file was processed successfully.
+func (m *File) ResetError() {
+	m.FileError = ""
+	m.FileErrorStage = ""
+	m.FileErrorAt = nil
+}
+
+// HasError returns true if an error was recorded at the given stage, or at any stage if it's empty.
+func (m *File) HasError(stage string) bool {
+	if m.FileError == "" {
+		return false
+	}
+
+	return stage == "" || m.FileErrorStage == stage
+}
+
 // ShareFileName returns a meaningful file name useful for sharing.
 func (m *File) ShareFileName() string {
 	if m.Photo == nil {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/remote"
 )
 
 const (
@@ -22,6 +23,9 @@ type FileSync struct {
 	FileID     uint   `gorm:"index;"`
 	RemoteDate time.Time
 	RemoteSize int64
+	RemoteETag string `gorm:"type:varbinary(255);"`
+	LocalDate  time.Time
+	LocalSize  int64
 	Status     string `gorm:"type:varbinary(16);"`
 	Error      string `gorm:"type:varbinary(512);"`
 	Errors     int
@@ -58,3 +62,31 @@ func (m *FileSync) FirstOrCreate(db *gorm.DB) *FileSync {
 
 	return m
 }
+
+// Synced returns true if the file was downloaded or uploaded, so that local and remote changes can be detected.
+func (m *FileSync) Synced() bool {
+	return m.Status == FileSyncDownloaded || m.Status == FileSyncUploaded
+}
+
+// LocalVersion returns the local file version seen at the last sync.
+func (m *FileSync) LocalVersion() remote.Version {
+	return remote.Version{Date: m.LocalDate, Size: m.LocalSize}
+}
+
+// RemoteVersion returns the remote file version seen at the last sync.
+func (m *FileSync) RemoteVersion() remote.Version {
+	return remote.Version{ETag: m.RemoteETag, Date: m.RemoteDate, Size: m.RemoteSize}
+}
+
+// SetLocal remembers the local file version after a sync, the local date is stored in UTC.
+func (m *FileSync) SetLocal(date time.Time, size int64) {
+	m.LocalDate = date.UTC()
+	m.LocalSize = size
+}
+
+// SetRemote remembers the remote file version after a sync.
+func (m *FileSync) SetRemote(etag string, date time.Time, size int64) {
+	m.RemoteETag = etag
+	m.RemoteDate = date
+	m.RemoteSize = size
+}
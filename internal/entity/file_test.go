@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,3 +36,55 @@ func TestFile_DownloadFileName(t *testing.T) {
 		assert.Equal(t, "123Hash.jpg", filename)
 	})
 }
+
+func TestFile_Root(t *testing.T) {
+	assert.Equal(t, RootDefault, (&File{FileName: "2020/IMG_1234.jpg"}).Root())
+	assert.Equal(t, "archive", (&File{FileRoot: "archive", FileName: "2020/IMG_1234.jpg"}).Root())
+}
+
+func TestFile_SetError(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		file := &File{FileName: "2020/IMG_1234.jpg"}
+
+		file.SetError(StageMetadata, errors.New("unexpected EOF"))
+
+		assert.Equal(t, "unexpected EOF", file.FileError)
+		assert.Equal(t, StageMetadata, file.FileErrorStage)
+		assert.NotNil(t, file.FileErrorAt)
+		assert.True(t, file.HasError(""))
+		assert.True(t, file.HasError(StageMetadata))
+		assert.False(t, file.HasError(StageThumbnail))
+
+		file.SetError(StageThumbnail, errors.New("invalid JPEG format"))
+
+		assert.Equal(t, "invalid JPEG format", file.FileError)
+		assert.True(t, file.HasError(StageThumbnail))
+	})
+	t.Run("nil", func(t *testing.T) {
+		file := &File{FileName: "2020/IMG_1234.jpg"}
+
+		file.SetError(StageMetadata, nil)
+
+		assert.False(t, file.HasError(""))
+		assert.Nil(t, file.FileErrorAt)
+	})
+	t.Run("long message", func(t *testing.T) {
+		file := &File{FileName: "2020/IMG_1234.jpg"}
+
+		file.SetError(StageConvert, errors.New(strings.Repeat("x", 600)))
+
+		assert.True(t, len(file.FileError) <= 512)
+	})
+}
+
+func TestFile_ResetError(t *testing.T) {
+	file := &File{FileName: "2020/IMG_1234.jpg"}
+
+	file.SetError(StageConvert, errors.New("exit status 1"))
+	file.ResetError()
+
+	assert.False(t, file.HasError(""))
+	assert.Equal(t, "", file.FileError)
+	assert.Equal(t, "", file.FileErrorStage)
+	assert.Nil(t, file.FileErrorAt)
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/maps"
+)
+
+// GeoCacheTTL is the max age of cached reverse geocoding results, zero means no limit.
+var GeoCacheTTL = 30 * 24 * time.Hour
+
+// GeoCache stores reverse geocoding results by S2 cell id, so that they survive restarts.
+type GeoCache struct {
+	ID          string `gorm:"type:varbinary(16);primary_key;auto_increment:false;"`
+	GeoApi      string `gorm:"type:varbinary(16);primary_key;auto_increment:false;"`
+	GeoFound    bool
+	LocName     string `gorm:"type:varchar(255);"`
+	LocCategory string `gorm:"type:varchar(64);"`
+	LocLabel    string `gorm:"type:varbinary(512);"`
+	LocCity     string `gorm:"type:varchar(128);"`
+	LocState    string `gorm:"type:varchar(128);"`
+	LocCountry  string `gorm:"type:varbinary(2);"`
+	LocSource   string `gorm:"type:varbinary(16);"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewGeoCache returns a maps.Cache that stores results in the database.
+func NewGeoCache(db *gorm.DB) maps.Cache {
+	return &geoCache{db: db}
+}
+
+// geoCache implements maps.Cache.
+type geoCache struct {
+	db *gorm.DB
+}
+
+// Load returns a cached result if it exists and didn't expire.
+func (c *geoCache) Load(id, api string) (*maps.Location, bool) {
+	var m GeoCache
+
+	if err := c.db.First(&m, "id = ? AND geo_api = ?", id, api).Error; err != nil {
+		return nil, false
+	}
+
+	if GeoCacheTTL > 0 && time.Since(m.UpdatedAt) > GeoCacheTTL {
+		return nil, false
+	}
+
+	log.Debugf("geo: cache hit for %s (%s)", id, api)
+
+	if !m.GeoFound {
+		return &maps.Location{}, true
+	}
+
+	return maps.NewLocation(m.ID, m.LocName, m.LocCategory, m.LocLabel, m.LocCity, m.LocState, m.LocCountry, m.LocSource), true
+}
+
+// Save adds a result to the cache, or replaces an existing result.
+func (c *geoCache) Save(id, api string, l *maps.Location) {
+	m := GeoCache{
+		ID:          id,
+		GeoApi:      api,
+		GeoFound:    !l.Unknown(),
+		LocName:     l.LocName,
+		LocCategory: l.LocCategory,
+		LocLabel:    l.LocLabel,
+		LocCity:     l.LocCity,
+		LocState:    l.LocState,
+		LocCountry:  l.LocCountry,
+		LocSource:   l.LocSource,
+	}
+
+	logError(c.db.Save(&m))
+}
@@ -4,7 +4,6 @@ import (
 	"strings"
 
 	"github.com/jinzhu/gorm"
-	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -28,8 +27,7 @@ func NewKeyword(keyword string) *Keyword {
 
 // FirstOrCreate checks wether the keyword already exist in the database
 func (m *Keyword) FirstOrCreate(db *gorm.DB) *Keyword {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "keyword = ?", m.Keyword).Error; err != nil {
 		log.Errorf("keyword: %s", err)
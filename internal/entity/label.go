@@ -6,6 +6,7 @@ import (
 	"github.com/gosimple/slug"
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/pkg/rnd"
 	"github.com/photoprism/photoprism/pkg/txt"
@@ -18,7 +19,9 @@ type Label struct {
 	LabelSlug        string `gorm:"type:varbinary(255);unique_index;"`
 	CustomSlug       string `gorm:"type:varbinary(255);index;"`
 	LabelName        string `gorm:"type:varchar(255);"`
+	LabelSearch      string `gorm:"type:varbinary(255);" json:"-"`
 	LabelPriority    int
+	LabelCount       int // Number of photos with this label, updated after indexing.
 	LabelFavorite    bool
 	LabelDescription string   `gorm:"type:text;"`
 	LabelNotes       string   `gorm:"type:text;"`
@@ -40,6 +43,11 @@ func (m *Label) BeforeCreate(scope *gorm.Scope) error {
 	return nil
 }
 
+// BeforeSave updates the normalized label name used for search
+func (m *Label) BeforeSave(scope *gorm.Scope) error {
+	return setSearch(scope, "LabelSearch", "label_name", m.LabelName)
+}
+
 // NewLabel creates a label in database with a given name and priority
 func NewLabel(name string, priority int) *Label {
 	labelName := txt.Clip(name, txt.ClipDefault)
@@ -63,8 +71,7 @@ func NewLabel(name string, priority int) *Label {
 
 // FirstOrCreate checks if the label already exists in the database
 func (m *Label) FirstOrCreate(db *gorm.DB) *Label {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "label_slug = ? OR custom_slug = ?", m.LabelSlug, m.CustomSlug).Error; err != nil {
 		log.Errorf("label: %s", err)
@@ -128,3 +135,56 @@ func (m *Label) Update(label classify.Label, db *gorm.DB) error {
 
 	return nil
 }
+
+// DeleteLabels flags labels as deleted and removes their photo and category associations in a single
+// transaction, so that label counts stay correct without reindexing.
+func DeleteLabels(db *gorm.DB, labelUUIDs []string) error {
+	if len(labelUUIDs) == 0 {
+		return nil
+	}
+
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	tx := db.Begin()
+
+	var labelIDs []uint
+
+	if err := tx.Model(&Label{}).Where("label_uuid IN (?)", labelUUIDs).Pluck("id", &labelIDs).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(labelIDs) == 0 {
+		tx.Rollback()
+		return nil
+	}
+
+	if err := tx.Where("label_id IN (?)", labelIDs).Delete(&PhotoLabel{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("label_id IN (?) OR category_id IN (?)", labelIDs, labelIDs).Delete(&Category{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(&Label{}).Where("label_uuid IN (?)", labelUUIDs).UpdateColumn("label_count", 0).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("label_uuid IN (?)", labelUUIDs).Delete(&Label{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	event.EntitiesDeleted("labels", labelUUIDs)
+
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/gosimple/slug"
 	"github.com/jinzhu/gorm"
-	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 // Lens represents camera lens (as extracted from UpdateExif metadata)
@@ -61,8 +60,7 @@ func NewLens(modelName string, makeName string) *Lens {
 
 // FirstOrCreate checks if the lens already exists in the database
 func (m *Lens) FirstOrCreate(db *gorm.DB) *Lens {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "lens_slug = ?", m.LensSlug).Error; err != nil {
 		log.Errorf("lens: %s", err)
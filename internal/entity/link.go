@@ -1,18 +1,25 @@
 package entity
 
 import (
+	"crypto/subtle"
+	"regexp"
 	"time"
 
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/pkg/rnd"
+	"golang.org/x/crypto/bcrypt"
 )
 
+var bcryptHash = regexp.MustCompile(`^\$2[ayb]\$.{56}$`)
+
 // Link represents a sharing link.
 type Link struct {
 	LinkToken    string     `gorm:"type:varbinary(255);primary_key;"`
-	LinkPassword string     `gorm:"type:varbinary(255);"`
+	LinkPassword string     `gorm:"type:varbinary(255);" json:"-"`
 	LinkExpires  *time.Time `gorm:"type:datetime;"`
-	ShareUUID    string     `gorm:"type:varbinary(36);index;"`
+	LinkViews    uint
+	MaxViews     uint
+	ShareUUID    string `gorm:"type:varbinary(36);index;"`
 	CanComment   bool
 	CanEdit      bool
 	CreatedAt    time.Time  `deepcopier:"skip"`
@@ -30,13 +37,100 @@ func (m *Link) BeforeCreate(scope *gorm.Scope) error {
 }
 
 // NewLink creates a sharing link.
-func NewLink(password string, canComment, canEdit bool) Link {
+func NewLink(canComment, canEdit bool) Link {
 	result := Link{
-		LinkToken:    rnd.Token(10),
-		LinkPassword: password,
-		CanComment:   canComment,
-		CanEdit:      canEdit,
+		LinkToken:  rnd.Token(10),
+		CanComment: canComment,
+		CanEdit:    canEdit,
 	}
 
 	return result
 }
+
+// FindLink returns the link for a token, expired links are returned as well.
+func FindLink(db *gorm.DB, token string) (*Link, error) {
+	var result Link
+
+	if err := db.Where("link_token = ?", token).First(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// LinkAccessTTL is the time visitors keep access to a link once opened.
+var LinkAccessTTL = 24 * time.Hour
+
+// DeleteExpiredLinks deletes expired links and links that reached their max number of views,
+// after visitors who opened them lost access.
+func DeleteExpiredLinks(db *gorm.DB) (int64, error) {
+	now := time.Now()
+	result := db.Where("link_expires <= ? OR (max_views > 0 AND link_views >= max_views AND updated_at <= ?)", now, now.Add(-LinkAccessTTL)).Delete(&Link{})
+
+	return result.RowsAffected, result.Error
+}
+
+// SetPassword stores a hash of the password, an empty password removes password protection.
+func (m *Link) SetPassword(password string) error {
+	if password == "" {
+		m.LinkPassword = ""
+		return nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	if err != nil {
+		return err
+	}
+
+	m.LinkPassword = string(hash)
+
+	return nil
+}
+
+// HasPassword returns true if the link is password protected.
+func (m *Link) HasPassword() bool {
+	return m.LinkPassword != ""
+}
+
+// CheckPassword returns true if the password is correct, links without password accept any password.
+func (m *Link) CheckPassword(password string) bool {
+	if !m.HasPassword() {
+		return true
+	}
+
+	// Links created by older versions contain the password as plain text.
+	if !bcryptHash.MatchString(m.LinkPassword) {
+		return subtle.ConstantTimeCompare([]byte(m.LinkPassword), []byte(password)) == 1
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(m.LinkPassword), []byte(password)) == nil
+}
+
+// Expired returns true if the link expired.
+func (m *Link) Expired() bool {
+	return m.LinkExpires != nil && !time.Now().Before(*m.LinkExpires)
+}
+
+// Exhausted returns true if the link reached the max number of views and can't be opened again.
+func (m *Link) Exhausted() bool {
+	return m.MaxViews > 0 && m.LinkViews >= m.MaxViews
+}
+
+// AddView increments the number of link views and returns false if the link reached the max number of
+// views in the meantime. The view count is checked by the update, so that it can't be exceeded by
+// concurrent requests.
+func (m *Link) AddView(db *gorm.DB) (bool, error) {
+	result := db.Model(&Link{}).Where("link_token = ? AND (max_views = 0 OR link_views < max_views)", m.LinkToken).
+		UpdateColumn("link_views", gorm.Expr("link_views + 1"))
+
+	if result.Error != nil {
+		return false, result.Error
+	} else if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	m.LinkViews++
+
+	return true, nil
+}
@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLink_Expired(t *testing.T) {
+	t.Run("no expiry", func(t *testing.T) {
+		link := NewLink(false, false)
+		assert.False(t, link.Expired())
+	})
+	t.Run("future", func(t *testing.T) {
+		link := NewLink(false, false)
+		expires := time.Now().Add(time.Second)
+		link.LinkExpires = &expires
+		assert.False(t, link.Expired())
+	})
+	t.Run("past", func(t *testing.T) {
+		link := NewLink(false, false)
+		expires := time.Now().Add(-time.Second)
+		link.LinkExpires = &expires
+		assert.True(t, link.Expired())
+	})
+	t.Run("now", func(t *testing.T) {
+		link := NewLink(false, false)
+		expires := time.Now()
+		link.LinkExpires = &expires
+		assert.True(t, link.Expired())
+	})
+}
+
+func TestLink_Exhausted(t *testing.T) {
+	link := NewLink(false, false)
+	assert.False(t, link.Exhausted())
+
+	link.LinkViews = 100
+	assert.False(t, link.Exhausted())
+
+	link.MaxViews = 101
+	assert.False(t, link.Exhausted())
+
+	link.LinkViews = 101
+	assert.True(t, link.Exhausted())
+}
+
+func TestLink_CheckPassword(t *testing.T) {
+	t.Run("no password", func(t *testing.T) {
+		link := NewLink(false, false)
+		assert.False(t, link.HasPassword())
+		assert.True(t, link.CheckPassword(""))
+		assert.True(t, link.CheckPassword("foo"))
+	})
+	t.Run("hash", func(t *testing.T) {
+		link := NewLink(false, false)
+
+		if err := link.SetPassword("foobar"); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, link.HasPassword())
+		assert.NotEqual(t, "foobar", link.LinkPassword)
+		assert.True(t, link.CheckPassword("foobar"))
+		assert.False(t, link.CheckPassword("Foobar"))
+		assert.False(t, link.CheckPassword(""))
+	})
+	t.Run("plain text", func(t *testing.T) {
+		link := Link{LinkPassword: "somepassword"}
+		assert.True(t, link.CheckPassword("somepassword"))
+		assert.False(t, link.CheckPassword("some"))
+	})
+	t.Run("remove", func(t *testing.T) {
+		link := Link{LinkPassword: "somepassword"}
+
+		assert.Nil(t, link.SetPassword(""))
+		assert.False(t, link.HasPassword())
+	})
+}
+
+func TestLink_AddView(t *testing.T) {
+	db, cleanup := newRunTestDb(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&Link{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	link := NewLink(false, false)
+	link.MaxViews = 2
+
+	if err := db.Create(&link).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		ok, err := link.AddView(db)
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	// Views are counted in the database, so that other copies of the link can't exceed the max views.
+	other := link
+	other.LinkViews = 0
+
+	ok, err := other.AddView(db)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, uint(2), link.LinkViews)
+}
@@ -41,7 +41,7 @@ func (m *Location) Find(db *gorm.DB, api string) error {
 		ID: m.ID,
 	}
 
-	if err := l.QueryApi(api); err != nil {
+	if err := l.QueryCached(api, NewGeoCache(db)); err != nil {
 		return err
 	}
 
@@ -70,6 +70,56 @@ func (m *Location) Find(db *gorm.DB, api string) error {
 	return nil
 }
 
+// Refresh resolves a location found by the offline geocoder again using the api, e.g. once a self-hosted
+// places service became available. Returns true if the location was updated.
+func (m *Location) Refresh(db *gorm.DB, api string) (bool, error) {
+	if !m.Offline() || api == "" || api == LocSourceOffline {
+		return false, nil
+	}
+
+	l := &maps.Location{
+		ID: m.ID,
+	}
+
+	if err := l.QueryCached(api, NewGeoCache(db)); err != nil {
+		return false, err
+	}
+
+	// Still unreachable, the offline geocoder was used again.
+	if l.LocSource != api {
+		return false, nil
+	}
+
+	// The place created for the offline result has the same id and is updated.
+	place := &Place{}
+
+	if err := db.First(place, "loc_label = ?", l.LocLabel).Error; err != nil {
+		place = &Place{}
+		values := Place{LocLabel: l.LocLabel, LocCity: l.LocCity, LocState: l.LocState, LocCountry: l.LocCountry}
+
+		if err := db.Where(Place{ID: l.ID}).Assign(values).FirstOrCreate(place).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if err := db.Model(m).UpdateColumns(map[string]interface{}{
+		"place_id":     place.ID,
+		"loc_name":     l.LocName,
+		"loc_category": l.LocCategory,
+		"loc_source":   l.LocSource,
+	}).Error; err != nil {
+		return false, err
+	}
+
+	m.Place = place
+	m.PlaceID = place.ID
+	m.LocName = l.LocName
+	m.LocCategory = l.LocCategory
+	m.LocSource = l.LocSource
+
+	return true, nil
+}
+
 // Keywords computes keyword based on a Location
 func (m *Location) Keywords() (result []string) {
 	result = append(result, txt.Keywords(txt.ReplaceSpaces(m.City(), "-"))...)
@@ -162,3 +212,8 @@ func (m *Location) Notes() string {
 func (m *Location) Source() string {
 	return m.LocSource
 }
+
+// Offline checks if the location was resolved by the offline geocoder
+func (m *Location) Offline() bool {
+	return m.LocSource == LocSourceOffline
+}
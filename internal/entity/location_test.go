@@ -13,3 +13,33 @@ func TestLocation_Label(t *testing.T) {
 
 	assert.Equal(t, "restaurant", result)
 }
+
+func TestLocation_Offline(t *testing.T) {
+	l := NewLocation(1, 1)
+	assert.False(t, l.Offline())
+
+	l.LocSource = LocSourceOffline
+	assert.True(t, l.Offline())
+}
+
+func TestLocation_Refresh(t *testing.T) {
+	t.Run("not offline", func(t *testing.T) {
+		l := NewLocation(1, 1)
+		l.LocSource = "places"
+
+		updated, err := l.Refresh(nil, "places")
+
+		assert.NoError(t, err)
+		assert.False(t, updated)
+	})
+	t.Run("offline api", func(t *testing.T) {
+		l := NewLocation(1, 1)
+		l.LocSource = LocSourceOffline
+
+		updated, err := l.Refresh(nil, LocSourceOffline)
+
+		assert.NoError(t, err)
+		assert.False(t, updated)
+		assert.Equal(t, LocSourceOffline, l.Source())
+	})
+}
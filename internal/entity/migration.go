@@ -0,0 +1,531 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// Migration represents a schema migration step that was applied to the database.
+type Migration struct {
+	ID        int    `gorm:"primary_key;auto_increment:false"`
+	Name      string `gorm:"type:varchar(255);"`
+	AppliedAt time.Time
+}
+
+// TableName returns Migration table identifier "migrations"
+func (Migration) TableName() string {
+	return "migrations"
+}
+
+// MigrationSQL contains raw SQL statements by dialect name, statements for all other
+// dialects can be added with an empty name.
+type MigrationSQL map[string][]string
+
+// Statements returns the statements for a dialect.
+func (m MigrationSQL) Statements(dialect string) []string {
+	if s, ok := m[dialect]; ok {
+		return s
+	}
+
+	return m[""]
+}
+
+// MigrationStep is a numbered schema change, it either runs functions or raw SQL statements.
+type MigrationStep struct {
+	ID      int
+	Name    string
+	Up      func(db *gorm.DB) error
+	UpSQL   MigrationSQL
+	Down    func(db *gorm.DB) error
+	DownSQL MigrationSQL
+}
+
+// Reversible returns true if the step can be rolled back.
+func (m MigrationStep) Reversible() bool {
+	return m.Down != nil || m.DownSQL != nil
+}
+
+// MigrationColumn is a column added by a migration step. The type must be valid in all
+// supported dialects, so that the same definition can be used for MySQL and SQLite.
+type MigrationColumn struct {
+	Table string
+	Name  string
+	Type  string
+}
+
+// MigrationIndex is an index added by a migration step.
+type MigrationIndex struct {
+	Table   string
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// MigrationSteps contains all schema migration steps in the order they are applied.
+// Steps must never be changed or removed once released, add new steps instead. They only
+// contain explicit DDL, as tables of new databases are created with the latest schema, so
+// that columns and indexes are skipped if they already exist.
+var MigrationSteps = []MigrationStep{
+	{
+		ID:   1,
+		Name: "create tables",
+		Up: func(db *gorm.DB) error {
+			// Databases created before migrations were added already contain most tables.
+			for _, e := range Entities {
+				if db.HasTable(e) {
+					continue
+				}
+
+				if err := db.CreateTable(e).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := addColumns(db,
+				MigrationColumn{"albums", "album_visibility", "VARBINARY(16)"},
+				MigrationColumn{"files", "file_phash", "VARBINARY(16)"},
+				MigrationColumn{"files", "file_codec", "VARBINARY(32)"},
+				MigrationColumn{"files", "file_duration", "BIGINT"},
+				MigrationColumn{"files", "file_hdr", "BOOLEAN"},
+				MigrationColumn{"files", "file_audio_codec", "VARBINARY(32)"},
+				MigrationColumn{"files", "file_channels", "INTEGER"},
+				MigrationColumn{"files", "file_timeline", "TEXT"},
+				MigrationColumn{"links", "link_views", "INTEGER"},
+				MigrationColumn{"links", "max_views", "INTEGER"},
+			); err != nil {
+				return err
+			}
+
+			return addIndexes(db, MigrationIndex{Table: "files", Name: "idx_files_file_phash", Columns: []string{"file_phash"}})
+		},
+	},
+	{
+		ID:    2,
+		Name:  "index missing files",
+		UpSQL: MigrationSQL{"": {"CREATE INDEX idx_files_file_missing ON files (file_missing)"}},
+		DownSQL: MigrationSQL{
+			"mysql":   {"DROP INDEX idx_files_file_missing ON files"},
+			"sqlite3": {"DROP INDEX idx_files_file_missing"},
+		},
+	},
+	{
+		ID:   3,
+		Name: "add account sync limits",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db,
+				MigrationColumn{"accounts", "sync_window", "VARBINARY(16)"},
+				MigrationColumn{"accounts", "sync_upload_limit", "INTEGER"},
+				MigrationColumn{"accounts", "sync_download_limit", "INTEGER"},
+			)
+		},
+	},
+	{
+		ID:   4,
+		Name: "add two-way sync state",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db,
+				MigrationColumn{"accounts", "sync_conflicts", "VARBINARY(16)"},
+				MigrationColumn{"accounts", "sync_deletes", "BOOLEAN"},
+				MigrationColumn{"accounts", "sync_cursor", "VARBINARY(255)"},
+				MigrationColumn{"files_sync", "remote_e_tag", "VARBINARY(255)"},
+				MigrationColumn{"files_sync", "local_date", "DATETIME NULL"},
+				MigrationColumn{"files_sync", "local_size", "BIGINT"},
+			)
+		},
+	},
+	{
+		ID:   5,
+		Name: "add photo trash state",
+		Up: func(db *gorm.DB) error {
+			if err := addColumns(db, MigrationColumn{"photos", "trashed_at", "DATETIME NULL"}); err != nil {
+				return err
+			}
+
+			return addIndexes(db, MigrationIndex{Table: "photos", Name: "idx_photos_trashed_at", Columns: []string{"trashed_at"}})
+		},
+	},
+	{
+		ID:   6,
+		Name: "add file crop",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db, MigrationColumn{"files", "file_crop", "VARBINARY(16)"})
+		},
+	},
+	{
+		ID:   7,
+		Name: "add label counts",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db, MigrationColumn{"labels", "label_count", "INTEGER"})
+		},
+	},
+	{
+		ID:   8,
+		Name: "add focal length 35mm equivalent",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db, MigrationColumn{"photos", "photo_focal_length35", "INTEGER"})
+		},
+	},
+	{
+		ID:   9,
+		Name: "add photo colors",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db,
+				MigrationColumn{"photos", "photo_colors", "VARBINARY(64)"},
+				MigrationColumn{"photos", "photo_luminance", "SMALLINT"},
+				MigrationColumn{"photos", "photo_mono", "BOOLEAN"},
+			)
+		},
+	},
+	{
+		ID:   10,
+		Name: "add album types",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db,
+				MigrationColumn{"albums", "album_type", "VARBINARY(8) DEFAULT 'album'"},
+				MigrationColumn{"albums", "album_path", "VARBINARY(768)"},
+				MigrationColumn{"albums", "album_filter", "VARBINARY(1024)"},
+			)
+		},
+	},
+	{
+		ID:   11,
+		Name: "add originals roots",
+		Up: func(db *gorm.DB) error {
+			if err := addColumns(db,
+				MigrationColumn{"files", "file_root", "VARBINARY(16) DEFAULT 'default'"},
+				MigrationColumn{"photos", "photo_root", "VARBINARY(16) DEFAULT 'default'"},
+				MigrationColumn{"albums", "album_root", "VARBINARY(16)"},
+			); err != nil {
+				return err
+			}
+
+			// Existing files and photos belong to the default root, file names are unique per root now.
+			if err := db.Exec("UPDATE files SET file_root = ? WHERE file_root IS NULL OR file_root = ''", RootDefault).Error; err != nil {
+				return err
+			}
+
+			if err := db.Exec("UPDATE photos SET photo_root = ? WHERE photo_root IS NULL OR photo_root = ''", RootDefault).Error; err != nil {
+				return err
+			}
+
+			if err := db.Exec("UPDATE albums SET album_root = ? WHERE album_type = ?", RootDefault, AlbumFolder).Error; err != nil {
+				return err
+			}
+
+			if err := addIndexes(db, MigrationIndex{Table: "files", Name: "uix_files_root_name", Columns: []string{"file_root", "file_name"}, Unique: true}); err != nil {
+				return err
+			}
+
+			if db.Dialect().HasIndex("files", "uix_files_file_name") {
+				return db.Table("files").RemoveIndex("uix_files_file_name").Error
+			}
+
+			return nil
+		},
+	},
+	{
+		ID:   12,
+		Name: "add file error stages",
+		Up: func(db *gorm.DB) error {
+			if err := addColumns(db,
+				MigrationColumn{"files", "file_error_stage", "VARBINARY(16)"},
+				MigrationColumn{"files", "file_error_at", "DATETIME NULL"},
+			); err != nil {
+				return err
+			}
+
+			if err := addIndexes(db, MigrationIndex{Table: "files", Name: "idx_files_file_error_stage", Columns: []string{"file_error_stage"}}); err != nil {
+				return err
+			}
+
+			// Errors were only recorded when thumbnails couldn't be rendered so far.
+			return db.Exec("UPDATE files SET file_error_stage = ? WHERE file_error <> '' AND (file_error_stage IS NULL OR file_error_stage = '')", StageThumbnail).Error
+		},
+	},
+	{
+		ID:   13,
+		Name: "add normalized search columns",
+		Up: func(db *gorm.DB) error {
+			if err := addColumns(db,
+				MigrationColumn{"labels", "label_search", "VARBINARY(255)"},
+				MigrationColumn{"albums", "album_search", "VARBINARY(255)"},
+				MigrationColumn{"places", "loc_search", "VARBINARY(512)"},
+				MigrationColumn{"photos", "title_search", "VARBINARY(255)"},
+			); err != nil {
+				return err
+			}
+
+			columns := []struct{ table, name, search string }{
+				{"labels", "label_name", "label_search"},
+				{"albums", "album_name", "album_search"},
+				{"places", "loc_label", "loc_search"},
+				{"photos", "photo_title", "title_search"},
+			}
+
+			for _, c := range columns {
+				if err := normalizeColumn(db, c.table, c.name, c.search); err != nil {
+					return err
+				}
+			}
+
+			return normalizeKeywords(db)
+		},
+	},
+	{
+		ID:   14,
+		Name: "add import and index runs",
+		Up: func(db *gorm.DB) error {
+			if db.HasTable("runs") {
+				return nil
+			}
+
+			id := "id INTEGER PRIMARY KEY AUTOINCREMENT"
+
+			if db.Dialect().GetName() == "mysql" {
+				id = "id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY"
+			}
+
+			if err := db.Exec("CREATE TABLE runs (" + id + ", run_uuid VARBINARY(36), run_type VARBINARY(16), " +
+				"run_path VARBINARY(512), run_dir VARBINARY(1024), run_cursor VARBINARY(1024), run_files INTEGER, " +
+				"run_resumed INTEGER, finished_at DATETIME NULL, created_at DATETIME NULL, updated_at DATETIME NULL)").Error; err != nil {
+				return err
+			}
+
+			return addIndexes(db,
+				MigrationIndex{Table: "runs", Name: "uix_runs_run_uuid", Columns: []string{"run_uuid"}, Unique: true},
+				MigrationIndex{Table: "runs", Name: "idx_runs_type_path", Columns: []string{"run_type", "run_path"}},
+				MigrationIndex{Table: "runs", Name: "idx_runs_created_at", Columns: []string{"created_at"}},
+				MigrationIndex{Table: "runs", Name: "idx_runs_updated_at", Columns: []string{"updated_at"}},
+			)
+		},
+		DownSQL: MigrationSQL{"": {"DROP TABLE runs"}},
+	},
+}
+
+// addColumns adds columns to existing tables, unless they already exist.
+func addColumns(db *gorm.DB, columns ...MigrationColumn) error {
+	d := db.Dialect()
+
+	for _, c := range columns {
+		if d.HasColumn(c.Table, c.Name) {
+			continue
+		}
+
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", d.Quote(c.Table), d.Quote(c.Name), c.Type)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addIndexes creates indexes on existing tables, unless they already exist.
+func addIndexes(db *gorm.DB, indexes ...MigrationIndex) error {
+	d := db.Dialect()
+
+	for _, i := range indexes {
+		if d.HasIndex(i.Table, i.Name) {
+			continue
+		}
+
+		create := "CREATE INDEX"
+
+		if i.Unique {
+			create = "CREATE UNIQUE INDEX"
+		}
+
+		columns := make([]string, len(i.Columns))
+
+		for n, c := range i.Columns {
+			columns[n] = d.Quote(c)
+		}
+
+		if err := db.Exec(fmt.Sprintf("%s %s ON %s (%s)", create, i.Name, d.Quote(i.Table), strings.Join(columns, ", "))).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeColumn sets the search column of all rows to the normalized value of the name column.
+func normalizeColumn(db *gorm.DB, table, name, search string) error {
+	var rows []struct {
+		ID   string
+		Name string
+	}
+
+	if err := db.Table(table).Select("id, " + name + " AS name").Where(name + " <> ''").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		if err := db.Table(table).Where("id = ?", r.ID).UpdateColumn(search, txt.Normalize(r.Name)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeKeywords adds the normalized version of keywords with non-ASCII characters to their photos.
+func normalizeKeywords(db *gorm.DB) error {
+	var keywords []Keyword
+
+	if err := db.Find(&keywords).Error; err != nil {
+		return err
+	}
+
+	for _, k := range keywords {
+		normalized := txt.Normalize(k.Keyword)
+
+		if normalized == "" || normalized == k.Keyword {
+			continue
+		}
+
+		kw := NewKeyword(normalized).FirstOrCreate(db)
+
+		if kw.ID == 0 {
+			continue
+		}
+
+		var photoKeywords []PhotoKeyword
+
+		if err := db.Where("keyword_id = ?", k.ID).Find(&photoKeywords).Error; err != nil {
+			return err
+		}
+
+		for _, pk := range photoKeywords {
+			NewPhotoKeyword(pk.PhotoID, kw.ID).FirstOrCreate(db)
+		}
+	}
+
+	return nil
+}
+
+// AppliedMigrations returns all migration steps applied to the database so far.
+func AppliedMigrations(db *gorm.DB) (result []Migration, err error) {
+	if !db.HasTable(&Migration{}) {
+		return result, nil
+	}
+
+	err = db.Order("id").Find(&result).Error
+
+	return result, err
+}
+
+// PendingMigrations returns all migration steps that were not applied to the database yet.
+func PendingMigrations(db *gorm.DB) (result []MigrationStep, err error) {
+	applied, err := AppliedMigrations(db)
+
+	if err != nil {
+		return result, err
+	}
+
+	done := make(map[int]bool, len(applied))
+
+	for _, m := range applied {
+		done[m.ID] = true
+	}
+
+	for _, step := range MigrationSteps {
+		if !done[step.ID] {
+			result = append(result, step)
+		}
+	}
+
+	return result, nil
+}
+
+// Migrate applies all pending migration steps in order and returns them. With dryRun,
+// the pending steps are only returned.
+func Migrate(db *gorm.DB, dryRun bool) ([]MigrationStep, error) {
+	pending, err := PendingMigrations(db)
+
+	if err != nil || dryRun {
+		return pending, err
+	}
+
+	if !db.HasTable(&Migration{}) {
+		if err := db.CreateTable(&Migration{}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for i, step := range pending {
+		if err := runMigration(db, step.Up, step.UpSQL); err != nil {
+			return pending[:i], fmt.Errorf("migration %d (%s) failed: %s", step.ID, step.Name, err)
+		}
+
+		if err := db.Create(&Migration{ID: step.ID, Name: step.Name, AppliedAt: time.Now().UTC()}).Error; err != nil {
+			return pending[:i], err
+		}
+
+		log.Infof("migrate: applied %d (%s)", step.ID, step.Name)
+	}
+
+	return pending, nil
+}
+
+// Rollback reverts the last applied migration step and returns it. With dryRun,
+// the step is only returned.
+func Rollback(db *gorm.DB, dryRun bool) (*MigrationStep, error) {
+	applied, err := AppliedMigrations(db)
+
+	if err != nil {
+		return nil, err
+	} else if len(applied) == 0 {
+		return nil, fmt.Errorf("no migrations applied")
+	}
+
+	last := applied[len(applied)-1]
+
+	var step *MigrationStep
+
+	for i := range MigrationSteps {
+		if MigrationSteps[i].ID == last.ID {
+			step = &MigrationSteps[i]
+		}
+	}
+
+	if step == nil {
+		return nil, fmt.Errorf("migration %d (%s) is unknown", last.ID, last.Name)
+	} else if !step.Reversible() {
+		return step, fmt.Errorf("migration %d (%s) can't be rolled back", step.ID, step.Name)
+	} else if dryRun {
+		return step, nil
+	}
+
+	if err := runMigration(db, step.Down, step.DownSQL); err != nil {
+		return step, fmt.Errorf("rollback of %d (%s) failed: %s", step.ID, step.Name, err)
+	}
+
+	if err := db.Delete(&last).Error; err != nil {
+		return step, err
+	}
+
+	log.Infof("migrate: rolled back %d (%s)", step.ID, step.Name)
+
+	return step, nil
+}
+
+// runMigration runs a migration function and raw SQL statements for the current dialect.
+func runMigration(db *gorm.DB, f func(db *gorm.DB) error, statements MigrationSQL) error {
+	if f != nil {
+		if err := f(db); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range statements.Statements(db.Dialect().GetName()) {
+		if err := db.Exec(s).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package entity
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationSQL_Statements(t *testing.T) {
+	m := MigrationSQL{"": {"a"}, "mysql": {"b", "c"}}
+
+	assert.Equal(t, []string{"b", "c"}, m.Statements("mysql"))
+	assert.Equal(t, []string{"a"}, m.Statements("sqlite3"))
+	assert.Empty(t, MigrationSQL{}.Statements("mysql"))
+}
+
+func TestMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	db, err := gorm.Open("sqlite3", filepath.Join(dir, "index.db"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	// Create a database at schema version 1, later versions are tested with the first two steps.
+	steps := MigrationSteps
+	defer func() { MigrationSteps = steps }()
+
+	MigrationSteps = steps[:1]
+
+	applied, err := Migrate(db, false)
+
+	MigrationSteps = steps[:2]
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, applied, 1)
+
+	photo := &Photo{PhotoTitle: "Lake"}
+
+	if err := db.Create(photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Create(&File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "2020/lake.jpg", FileMissing: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		pending, err := Migrate(db, true)
+
+		assert.NoError(t, err)
+		assert.Len(t, pending, len(MigrationSteps)-1)
+		assert.False(t, db.Dialect().HasIndex("files", "idx_files_file_missing"))
+	})
+	t.Run("forward", func(t *testing.T) {
+		applied, err := Migrate(db, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, applied, len(MigrationSteps)-1)
+		assert.True(t, db.Dialect().HasIndex("files", "idx_files_file_missing"))
+
+		pending, err := PendingMigrations(db)
+
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+
+		var result Photo
+
+		assert.NoError(t, db.Where("photo_uuid = ?", photo.PhotoUUID).First(&result).Error)
+		assert.Equal(t, "Lake", result.PhotoTitle)
+
+		var count int
+
+		db.Model(&File{}).Where("photo_id = ? AND file_missing = ?", photo.ID, true).Count(&count)
+
+		assert.Equal(t, 1, count)
+	})
+	t.Run("rollback", func(t *testing.T) {
+		step, err := Rollback(db, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, step.ID)
+		assert.False(t, db.Dialect().HasIndex("files", "idx_files_file_missing"))
+
+		pending, err := PendingMigrations(db)
+
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+	})
+	t.Run("irreversible", func(t *testing.T) {
+		if _, err := Migrate(db, false); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := len(MigrationSteps); i > 1; i-- {
+			if _, err := Rollback(db, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err := Rollback(db, false)
+
+		assert.EqualError(t, err, "migration 1 (create tables) can't be rolled back")
+	})
+}
+
+func TestMigrate_Baseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	db, err := gorm.Open("sqlite3", filepath.Join(dir, "index.db"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	schema, err := ioutil.ReadFile("testdata/schema_sqlite3.sql")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create the schema of an existing database, in which tables were created by AutoMigrate.
+	statements := []string{
+		string(schema),
+		"INSERT INTO photos (id, photo_uuid, photo_title, photo_path, photo_name) VALUES (1, 'pt9jtdre2lvl0y11', 'Zürich', '2020', 'lake')",
+		"INSERT INTO files (id, photo_id, photo_uuid, file_uuid, file_name, file_primary, file_error) VALUES (1, 1, 'pt9jtdre2lvl0y11', 'ft9jtdre2lvl0y11', '2020/lake.jpg', 1, 'broken')",
+		"INSERT INTO albums (id, album_uuid, album_slug, album_name) VALUES (1, 'at9jtdre2lvl0y11', 'geneve', 'Genève')",
+		"INSERT INTO labels (id, label_uuid, label_slug, label_name) VALUES (1, 'lt9jtdre2lvl0y11', 'cafe', 'Café')",
+	}
+
+	for _, s := range statements {
+		if err := db.Exec(s).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	applied, err := Migrate(db, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, applied, len(MigrationSteps))
+
+	t.Run("schema", func(t *testing.T) {
+		for _, e := range Entities {
+			scope := db.NewScope(e)
+
+			if !assert.True(t, scope.Dialect().HasTable(scope.TableName()), scope.TableName()) {
+				continue
+			}
+
+			for _, field := range scope.GetModelStruct().StructFields {
+				if field.IsNormal && !field.IsIgnored {
+					assert.True(t, scope.Dialect().HasColumn(scope.TableName(), field.DBName), scope.TableName()+"."+field.DBName)
+				}
+			}
+		}
+
+		assert.True(t, db.Dialect().HasIndex("files", "uix_files_root_name"))
+		assert.False(t, db.Dialect().HasIndex("files", "uix_files_file_name"))
+		assert.True(t, db.Dialect().HasIndex("runs", "idx_runs_type_path"))
+	})
+	t.Run("data", func(t *testing.T) {
+		var photo Photo
+
+		if err := db.First(&photo, 1).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Zürich", photo.PhotoTitle)
+		assert.Equal(t, "zurich", photo.TitleSearch)
+		assert.Equal(t, RootDefault, photo.PhotoRoot)
+
+		var file File
+
+		if err := db.First(&file, 1).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "2020/lake.jpg", file.FileName)
+		assert.Equal(t, RootDefault, file.FileRoot)
+		assert.Equal(t, StageThumbnail, file.FileErrorStage)
+
+		var album Album
+
+		if err := db.First(&album, 1).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, AlbumManual, album.AlbumType)
+		assert.Equal(t, "geneve", album.AlbumSearch)
+
+		var label Label
+
+		if err := db.First(&label, 1).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "cafe", label.LabelSearch)
+	})
+	t.Run("new rows", func(t *testing.T) {
+		photo := &Photo{PhotoTitle: "Lake"}
+
+		if err := db.Create(photo).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NoError(t, db.Create(&File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileRoot: "usb", FileName: "2020/lake.jpg"}).Error)
+		assert.NoError(t, db.Create(&Run{RunType: RunIndex, RunPath: "/"}).Error)
+	})
+}
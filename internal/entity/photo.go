@@ -17,53 +17,60 @@ import (
 
 // Photo represents a photo, all its properties, and link to all its images and sidecar files.
 type Photo struct {
-	ID               uint        `gorm:"primary_key"`
-	PhotoUUID        string      `gorm:"type:varbinary(36);unique_index;index:idx_photos_taken_uuid;"`
-	TakenAt          time.Time   `gorm:"type:datetime;index:idx_photos_taken_uuid;" json:"TakenAt"`
-	TakenAtLocal     time.Time   `gorm:"type:datetime;"`
-	TakenSrc         string      `gorm:"type:varbinary(8);" json:"TakenSrc"`
-	PhotoTitle       string      `gorm:"type:varchar(255);" json:"PhotoTitle"`
-	TitleSrc         string      `gorm:"type:varbinary(8);" json:"TitleSrc"`
-	PhotoPath        string      `gorm:"type:varbinary(768);index;"`
-	PhotoName        string      `gorm:"type:varbinary(255);"`
-	PhotoQuality     int         `gorm:"type:SMALLINT" json:"PhotoQuality"`
-	PhotoResolution  int         `gorm:"type:SMALLINT" json:"PhotoResolution"`
-	PhotoFavorite    bool        `json:"PhotoFavorite"`
-	PhotoPrivate     bool        `json:"PhotoPrivate"`
-	PhotoStory       bool        `json:"PhotoStory"`
-	PhotoLat         float32     `gorm:"type:FLOAT;index;" json:"PhotoLat"`
-	PhotoLng         float32     `gorm:"type:FLOAT;index;" json:"PhotoLng"`
-	PhotoAltitude    int         `json:"PhotoAltitude"`
-	PhotoIso         int         `json:"PhotoIso"`
-	PhotoFocalLength int         `json:"PhotoFocalLength"`
-	PhotoFNumber     float32     `gorm:"type:FLOAT;" json:"PhotoFNumber"`
-	PhotoExposure    string      `gorm:"type:varbinary(64);" json:"PhotoExposure"`
-	CameraID         uint        `gorm:"index:idx_photos_camera_lens;" json:"CameraID"`
-	CameraSerial     string      `gorm:"type:varbinary(255);" json:"CameraSerial"`
-	CameraSrc        string      `gorm:"type:varbinary(8);" json:"CameraSrc"`
-	LensID           uint        `gorm:"index:idx_photos_camera_lens;" json:"LensID"`
-	PlaceID          string      `gorm:"type:varbinary(16);index;default:'zz'" json:"PlaceID"`
-	LocationID       string      `gorm:"type:varbinary(16);index;" json:"LocationID"`
-	LocationSrc      string      `gorm:"type:varbinary(8);" json:"LocationSrc"`
-	TimeZone         string      `gorm:"type:varbinary(64);" json:"TimeZone"`
-	PhotoCountry     string      `gorm:"type:varbinary(2);index:idx_photos_country_year_month;default:'zz'" json:"PhotoCountry"`
-	PhotoYear        int         `gorm:"index:idx_photos_country_year_month;"`
-	PhotoMonth       int         `gorm:"index:idx_photos_country_year_month;"`
-	Description      Description `json:"Description"`
-	DescriptionSrc   string      `gorm:"type:varbinary(8);" json:"DescriptionSrc"`
-	Camera           *Camera     `json:"Camera"`
-	Lens             *Lens       `json:"Lens"`
-	Location         *Location   `json:"Location"`
-	Place            *Place      `json:"-"`
-	Links            []Link      `gorm:"foreignkey:ShareUUID;association_foreignkey:PhotoUUID"`
-	Keywords         []Keyword   `json:"-"`
-	Albums           []Album     `json:"-"`
-	Files            []File
-	Labels           []PhotoLabel
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
-	EditedAt         *time.Time
-	DeletedAt        *time.Time `sql:"index"`
+	ID                 uint        `gorm:"primary_key"`
+	PhotoUUID          string      `gorm:"type:varbinary(36);unique_index;index:idx_photos_taken_uuid;"`
+	TakenAt            time.Time   `gorm:"type:datetime;index:idx_photos_taken_uuid;" json:"TakenAt"`
+	TakenAtLocal       time.Time   `gorm:"type:datetime;"`
+	TakenSrc           string      `gorm:"type:varbinary(8);" json:"TakenSrc"`
+	PhotoTitle         string      `gorm:"type:varchar(255);" json:"PhotoTitle"`
+	TitleSrc           string      `gorm:"type:varbinary(8);" json:"TitleSrc"`
+	TitleSearch        string      `gorm:"type:varbinary(255);" json:"-"`
+	PhotoRoot          string      `gorm:"type:varbinary(16);default:'default';"`
+	PhotoPath          string      `gorm:"type:varbinary(768);index;"`
+	PhotoName          string      `gorm:"type:varbinary(255);"`
+	PhotoQuality       int         `gorm:"type:SMALLINT" json:"PhotoQuality"`
+	PhotoResolution    int         `gorm:"type:SMALLINT" json:"PhotoResolution"`
+	PhotoFavorite      bool        `json:"PhotoFavorite"`
+	PhotoPrivate       bool        `json:"PhotoPrivate"`
+	PhotoStory         bool        `json:"PhotoStory"`
+	PhotoLat           float32     `gorm:"type:FLOAT;index;" json:"PhotoLat"`
+	PhotoLng           float32     `gorm:"type:FLOAT;index;" json:"PhotoLng"`
+	PhotoAltitude      int         `json:"PhotoAltitude"`
+	PhotoIso           int         `json:"PhotoIso"`
+	PhotoFocalLength   int         `json:"PhotoFocalLength"`
+	PhotoFocalLength35 int         `json:"PhotoFocalLength35"`
+	PhotoFNumber       float32     `gorm:"type:FLOAT;" json:"PhotoFNumber"`
+	PhotoExposure      string      `gorm:"type:varbinary(64);" json:"PhotoExposure"`
+	PhotoColors        string      `gorm:"type:varbinary(64);" json:"PhotoColors"`
+	PhotoLuminance     uint8       `json:"PhotoLuminance"`
+	PhotoMono          bool        `json:"PhotoMono"`
+	CameraID           uint        `gorm:"index:idx_photos_camera_lens;" json:"CameraID"`
+	CameraSerial       string      `gorm:"type:varbinary(255);" json:"CameraSerial"`
+	CameraSrc          string      `gorm:"type:varbinary(8);" json:"CameraSrc"`
+	LensID             uint        `gorm:"index:idx_photos_camera_lens;" json:"LensID"`
+	PlaceID            string      `gorm:"type:varbinary(16);index;default:'zz'" json:"PlaceID"`
+	LocationID         string      `gorm:"type:varbinary(16);index;" json:"LocationID"`
+	LocationSrc        string      `gorm:"type:varbinary(8);" json:"LocationSrc"`
+	TimeZone           string      `gorm:"type:varbinary(64);" json:"TimeZone"`
+	PhotoCountry       string      `gorm:"type:varbinary(2);index:idx_photos_country_year_month;default:'zz'" json:"PhotoCountry"`
+	PhotoYear          int         `gorm:"index:idx_photos_country_year_month;"`
+	PhotoMonth         int         `gorm:"index:idx_photos_country_year_month;"`
+	Description        Description `json:"Description"`
+	DescriptionSrc     string      `gorm:"type:varbinary(8);" json:"DescriptionSrc"`
+	Camera             *Camera     `json:"Camera"`
+	Lens               *Lens       `json:"Lens"`
+	Location           *Location   `json:"Location"`
+	Place              *Place      `json:"-"`
+	Links              []Link      `gorm:"foreignkey:ShareUUID;association_foreignkey:PhotoUUID"`
+	Keywords           []Keyword   `json:"-"`
+	Albums             []Album     `json:"-"`
+	Files              []File
+	Labels             []PhotoLabel
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	EditedAt           *time.Time
+	TrashedAt          *time.Time `sql:"index"`
+	DeletedAt          *time.Time `sql:"index"`
 }
 
 // SavePhotoForm updates a model using form data and persists it in the database.
@@ -163,7 +170,8 @@ func (m *Photo) BeforeCreate(scope *gorm.Scope) error {
 	return nil
 }
 
-// BeforeSave ensures the existence of TakenAt properties before indexing or updating a photo
+// BeforeSave ensures the existence of TakenAt properties before indexing or updating a photo, and
+// updates the normalized title used for search
 func (m *Photo) BeforeSave(scope *gorm.Scope) error {
 	if m.TakenAt.IsZero() || m.TakenAtLocal.IsZero() {
 		now := time.Now()
@@ -177,7 +185,7 @@ func (m *Photo) BeforeSave(scope *gorm.Scope) error {
 		}
 	}
 
-	return nil
+	return setSearch(scope, "TitleSearch", "photo_title", m.PhotoTitle)
 }
 
 // IndexKeywords adds given keywords to the photo entry
@@ -196,6 +204,9 @@ func (m *Photo) IndexKeywords(db *gorm.DB) error {
 	keywords = append(keywords, txt.Keywords(m.Description.PhotoSubject)...)
 	keywords = append(keywords, txt.Keywords(m.Description.PhotoArtist)...)
 
+	// Add normalized keywords for search, e.g. "zurich" for "zürich"
+	keywords = append(keywords, txt.NormalizedWords(keywords)...)
+
 	keywords = txt.UniqueWords(keywords)
 
 	for _, w := range keywords {
@@ -322,6 +333,14 @@ func (m *Photo) UpdateTitle(labels classify.Labels) error {
 		return errors.New("photo: won't update title, was modified")
 	}
 
+	if TitleTemplate != "" {
+		m.templateTitle(labels)
+
+		log.Infof("photo: new title is \"%s\"", m.PhotoTitle)
+
+		return nil
+	}
+
 	hasLocation := m.Location != nil && m.Location.Place != nil
 
 	if hasLocation {
@@ -355,9 +374,9 @@ func (m *Photo) UpdateTitle(labels classify.Labels) error {
 		if len(labels) > 0 && labels[0].Priority >= -1 && labels[0].Uncertainty <= 85 && labels[0].Name != "" {
 			m.SetTitle(fmt.Sprintf("%s / %s", txt.Title(labels[0].Name), m.TakenAt.Format("2006")), SrcAuto)
 		} else if !m.TakenAtLocal.IsZero() {
-			m.SetTitle(fmt.Sprintf("Unknown / %s", m.TakenAtLocal.Format("2006")), SrcAuto)
+			m.SetTitle(fmt.Sprintf("%s / %s", txt.GetLocale(TitleLanguage).Unknown(), m.TakenAtLocal.Format("2006")), SrcAuto)
 		} else {
-			m.SetTitle("Unknown", SrcAuto)
+			m.SetTitle(txt.GetLocale(TitleLanguage).Unknown(), SrcAuto)
 		}
 
 		log.Infof("photo: changed photo title to \"%s\"", m.PhotoTitle)
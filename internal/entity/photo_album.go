@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/jinzhu/gorm"
-	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 // PhotoAlbum represents the many_to_many relation between Photo and Album
@@ -35,8 +34,7 @@ func NewPhotoAlbum(photoUUID, albumUUID string) *PhotoAlbum {
 
 // FirstOrCreate checks wether the PhotoAlbum relation already exist in the database before the creation
 func (m *PhotoAlbum) FirstOrCreate(db *gorm.DB) *PhotoAlbum {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "photo_uuid = ? AND album_uuid = ?", m.PhotoUUID, m.AlbumUUID).Error; err != nil {
 		log.Errorf("photo album: %s", err)
@@ -0,0 +1,13 @@
+package entity
+
+import "github.com/photoprism/photoprism/pkg/colors"
+
+// photoColors is the maximum number of dominant colors stored for a photo.
+const photoColors = 3
+
+// SetColors updates the dominant colors, average luminance and monochrome flag of the photo.
+func (m *Photo) SetColors(p colors.ColorPerception) {
+	m.PhotoColors = p.Colors.Dominant(photoColors).Names()
+	m.PhotoLuminance = p.Luminance.Brightness()
+	m.PhotoMono = p.Mono()
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/pkg/colors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhoto_SetColors(t *testing.T) {
+	t.Run("red", func(t *testing.T) {
+		m := Photo{}
+		m.SetColors(colors.ColorPerception{
+			Colors:    colors.Colors{colors.Red, colors.Red, colors.Red, colors.Black, colors.Black, colors.Grey, colors.Red, colors.Brown, colors.Orange},
+			MainColor: colors.Red,
+			Luminance: colors.LightMap{6, 8, 7, 1, 0, 4, 6, 5, 1},
+			Chroma:    42,
+		})
+
+		assert.Equal(t, "red,black,orange", m.PhotoColors)
+		assert.Equal(t, uint8(28), m.PhotoLuminance)
+		assert.False(t, m.PhotoMono)
+	})
+	t.Run("mono", func(t *testing.T) {
+		m := Photo{}
+		m.SetColors(colors.ColorPerception{
+			Colors:    colors.Colors{colors.Grey, colors.Grey, colors.Black, colors.White},
+			MainColor: colors.Grey,
+			Luminance: colors.LightMap{10, 9, 0, 6},
+			Chroma:    0,
+		})
+
+		assert.Equal(t, "grey,black,white", m.PhotoColors)
+		assert.True(t, m.PhotoMono)
+	})
+}
@@ -2,7 +2,6 @@ package entity
 
 import (
 	"github.com/jinzhu/gorm"
-	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 // PhotoKeyword represents the many-to-many relation between Photo and Keyword
@@ -28,8 +27,7 @@ func NewPhotoKeyword(photoID, keywordID uint) *PhotoKeyword {
 
 // FirstOrCreate checks wether the PhotoKeywords relation already exist in the database before the creation
 func (m *PhotoKeyword) FirstOrCreate(db *gorm.DB) *PhotoKeyword {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "photo_id = ? AND keyword_id = ?", m.PhotoID, m.KeywordID).Error; err != nil {
 		log.Errorf("photo keyword: %s", err)
@@ -3,7 +3,6 @@ package entity
 import (
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/classify"
-	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 // PhotoLabel represents the many-to-many relation between Photo and label.
@@ -36,8 +35,7 @@ func NewPhotoLabel(photoID, labelID uint, uncertainty int, source string) *Photo
 
 // FirstOrCreate checks if the PhotoLabel relation already exist in the database before the creation
 func (m *PhotoLabel) FirstOrCreate(db *gorm.DB) *PhotoLabel {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "photo_id = ? AND label_id = ?", m.PhotoID, m.LabelID).Error; err != nil {
 		log.Errorf("photo label: %s", err)
@@ -44,17 +44,42 @@ func (m *Photo) GetTakenAt() time.Time {
 
 // UpdateLocation updates location and labels based on latitude and longitude.
 func (m *Photo) UpdateLocation(db *gorm.DB, geoApi string) (keywords []string, labels classify.Labels) {
-	var location = NewLocation(m.PhotoLat, m.PhotoLng)
+	location, err := FindLocation(db, m.PhotoLat, m.PhotoLng, geoApi)
 
-	err := location.Find(db, geoApi)
+	return m.SetLocation(location, err)
+}
 
-	if err == nil {
-		if location.Place.New {
-			event.Publish("count.places", event.Data{
-				"count": 1,
-			})
-		}
+// FindLocation returns the location of latitude and longitude, which is created including place and
+// country if it doesn't exist yet. The geocoding api may be queried, so it must not be called within
+// a transaction.
+func FindLocation(db *gorm.DB, lat, lng float32, geoApi string) (*Location, error) {
+	location := NewLocation(lat, lng)
+
+	if err := location.Find(db, geoApi); err != nil {
+		return location, err
+	}
+
+	if location.Place.New {
+		event.Publish("count.places", event.Data{
+			"count": 1,
+		})
+	}
+
+	country := NewCountry(location.CountryCode(), location.CountryName()).FirstOrCreate(db)
 
+	if country.New {
+		event.Publish("count.countries", event.Data{
+			"count": 1,
+		})
+	}
+
+	return location, nil
+}
+
+// SetLocation updates location and labels with the result of FindLocation, the place is unknown if
+// the location could not be found.
+func (m *Photo) SetLocation(location *Location, err error) (keywords []string, labels classify.Labels) {
+	if err == nil {
 		m.Location = location
 		m.LocationID = location.ID
 		m.Place = location.Place
@@ -66,14 +91,6 @@ func (m *Photo) UpdateLocation(db *gorm.DB, geoApi string) (keywords []string, l
 			m.TakenAt = m.GetTakenAt()
 		}
 
-		country := NewCountry(location.CountryCode(), location.CountryName()).FirstOrCreate(db)
-
-		if country.New {
-			event.Publish("count.countries", event.Data{
-				"count": 1,
-			})
-		}
-
 		locCategory := location.Category()
 		keywords = append(keywords, location.Keywords()...)
 
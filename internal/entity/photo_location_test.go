@@ -1,8 +1,11 @@
 package entity
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPhoto_GetTimeZone(t *testing.T) {
@@ -41,3 +44,30 @@ func TestPhoto_GetTakenAt(t *testing.T) {
 		t.Fatalf("utc time should be 2020-02-04T10:54:34: %s", utcTime)
 	}
 }
+
+func TestPhoto_SetLocation(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		location := NewLocation(52.5208, 13.4093)
+		location.LocCategory = "park"
+		location.Place = &Place{ID: "de:berlin", LocLabel: "Berlin, Germany", LocCountry: "de"}
+		location.PlaceID = location.Place.ID
+
+		photo := Photo{PhotoLat: 52.5208, PhotoLng: 13.4093, TakenSrc: SrcManual}
+
+		_, labels := photo.SetLocation(location, nil)
+
+		assert.Equal(t, location.ID, photo.LocationID)
+		assert.Equal(t, "de:berlin", photo.PlaceID)
+		assert.Equal(t, "de", photo.PhotoCountry)
+		assert.Len(t, labels, 1)
+	})
+	t.Run("not found", func(t *testing.T) {
+		photo := Photo{PhotoLat: 52.5208, PhotoLng: 13.4093}
+
+		keywords, labels := photo.SetLocation(NewLocation(52.5208, 13.4093), errors.New("not found"))
+
+		assert.Equal(t, UnknownPlace.ID, photo.PlaceID)
+		assert.Empty(t, keywords)
+		assert.Empty(t, labels)
+	})
+}
@@ -13,31 +13,51 @@ var QualityBlacklist = map[string]bool{
 	"info":        true,
 }
 
+// ReviewQuality is the minimum quality of photos that don't need to be reviewed.
+const ReviewQuality = 3
+
+// QualityWeights contains the points a photo gets for its resolution, location, date and for being a favorite.
+type QualityWeights struct {
+	Resolution int
+	Location   int
+	Date       int
+	Favorite   int
+}
+
+// DefaultQualityWeights are used unless other weights are configured in the settings.
+var DefaultQualityWeights = QualityWeights{Resolution: 1, Location: 1, Date: 1, Favorite: 3}
+
+// QualityWeight contains the weights used to calculate quality scores.
+var QualityWeight = DefaultQualityWeights
+
 var (
 	year2008 = time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC)
 	year2012 = time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
 )
 
-// QualityScore returns a score based on photo properties like size and metadata.
+// QualityScore returns a score based on photo properties like size and metadata. Favorites and edited
+// photos never need to be reviewed.
 func (m *Photo) QualityScore() (score int) {
+	weight := QualityWeight
+
 	if m.PhotoFavorite {
-		score += 3
+		score += weight.Favorite
 	}
 
 	if m.TakenSrc != SrcAuto {
-		score++
+		score += weight.Date
 	}
 
 	if m.HasLatLng() {
-		score++
+		score += weight.Location
 	}
 
 	if m.TakenAt.Before(year2008) {
-		score++
+		score += weight.Resolution
 	} else if m.TakenAt.Before(year2012) && m.PhotoResolution >= 1 {
-		score++
+		score += weight.Resolution
 	} else if m.PhotoResolution >= 2 {
-		score++
+		score += weight.Resolution
 	}
 
 	blacklisted := false
@@ -59,8 +79,8 @@ func (m *Photo) QualityScore() (score int) {
 		score++
 	}
 
-	if score < 3 && m.EditedAt != nil {
-		score = 3
+	if score < ReviewQuality && (m.EditedAt != nil || m.PhotoFavorite) {
+		score = ReviewQuality
 	}
 
 	return score
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhoto_QualityScore(t *testing.T) {
+	taken := time.Date(2015, 11, 11, 9, 7, 18, 0, time.UTC)
+
+	t.Run("location added", func(t *testing.T) {
+		photo := Photo{TakenAt: taken, TakenSrc: SrcAuto, PhotoResolution: 1}
+
+		assert.Equal(t, 1, photo.QualityScore())
+
+		photo.PhotoLat, photo.PhotoLng = -21.342636, 55.466944
+
+		assert.Equal(t, 2, photo.QualityScore())
+	})
+	t.Run("weights", func(t *testing.T) {
+		defer func() { QualityWeight = DefaultQualityWeights }()
+
+		photo := Photo{TakenAt: taken, TakenSrc: SrcExif, PhotoResolution: 2, PhotoLat: -21.342636, PhotoLng: 55.466944}
+
+		assert.Equal(t, 4, photo.QualityScore())
+
+		QualityWeight = QualityWeights{Resolution: 2, Location: 0, Date: 3, Favorite: 3}
+
+		assert.Equal(t, 6, photo.QualityScore())
+
+		QualityWeight = QualityWeights{}
+
+		assert.Equal(t, 1, photo.QualityScore())
+	})
+	t.Run("favorite", func(t *testing.T) {
+		defer func() { QualityWeight = DefaultQualityWeights }()
+
+		QualityWeight.Favorite = 0
+
+		photo := Photo{TakenAt: taken, TakenSrc: SrcAuto, PhotoFavorite: true}
+
+		assert.Equal(t, ReviewQuality, photo.QualityScore())
+	})
+	t.Run("edited", func(t *testing.T) {
+		edited := time.Now()
+		photo := Photo{TakenAt: taken, TakenSrc: SrcAuto, EditedAt: &edited}
+
+		assert.Equal(t, ReviewQuality, photo.QualityScore())
+	})
+	t.Run("blacklisted", func(t *testing.T) {
+		photo := Photo{TakenAt: taken, TakenSrc: SrcAuto, Description: Description{PhotoKeywords: "screenshot, info"}}
+
+		assert.Equal(t, 0, photo.QualityScore())
+	})
+}
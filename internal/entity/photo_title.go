@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// TitleTemplate is used to generate photo titles if not empty, see txt.TitleTemplate.
+var TitleTemplate txt.TitleTemplate
+
+// TitleLanguage is the language of generated titles, e.g. for month names.
+var TitleLanguage = txt.DefaultLocale
+
+// TitleWeekStart is the first day of the week for week numbers in generated titles.
+var TitleWeekStart = time.Monday
+
+// templateTitle sets a title generated from TitleTemplate, based on the best label,
+// the location and the local time the photo was taken.
+func (m *Photo) templateTitle(labels classify.Labels) {
+	values := map[string]string{
+		"label": txt.Title(labels.Title("")),
+	}
+
+	if loc := m.Location; loc != nil && loc.Place != nil && loc.Place.ID != UnknownPlace.ID {
+		values["name"] = loc.Name()
+		values["city"] = loc.City()
+		values["state"] = loc.State()
+		values["country"] = loc.CountryName()
+	}
+
+	if title := TitleTemplate.Format(values, m.TakenAtLocal, TitleWeekStart, TitleLanguage); title != "" {
+		m.SetTitle(title, SrcAuto)
+	} else {
+		m.SetTitle(txt.GetLocale(TitleLanguage).Unknown(), SrcAuto)
+	}
+}
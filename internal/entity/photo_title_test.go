@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/pkg/txt"
+	"github.com/stretchr/testify/assert"
+)
+
+func titleTestPhoto() Photo {
+	return Photo{
+		TakenAt:      time.Date(2020, 8, 6, 10, 0, 0, 0, time.UTC),
+		TakenAtLocal: time.Date(2020, 8, 6, 12, 0, 0, 0, time.UTC),
+		Location: &Location{
+			LocName: "Brandenburger Tor",
+			Place:   &Place{ID: "de:berlin", LocCity: "Berlin", LocState: "Berlin", LocCountry: "de"},
+		},
+	}
+}
+
+func setTitleTemplate(template, language string) func() {
+	prevTemplate, prevLanguage := TitleTemplate, TitleLanguage
+	TitleTemplate, TitleLanguage = txt.TitleTemplate(template), language
+
+	return func() { TitleTemplate, TitleLanguage = prevTemplate, prevLanguage }
+}
+
+func TestPhoto_UpdateTitle(t *testing.T) {
+	beach := classify.Labels{{Name: "beach", Uncertainty: 20, Priority: 0}}
+
+	t.Run("template", func(t *testing.T) {
+		defer setTitleTemplate("{label} / {city}, {country} {year}", "en")()
+
+		m := titleTestPhoto()
+
+		assert.NoError(t, m.UpdateTitle(beach))
+		assert.Equal(t, "Beach / Berlin, Germany 2020", m.PhotoTitle)
+		assert.Equal(t, SrcAuto, m.TitleSrc)
+	})
+	t.Run("empty label", func(t *testing.T) {
+		defer setTitleTemplate("{label} / {city}, {country} {year}", "en")()
+
+		m := titleTestPhoto()
+
+		assert.NoError(t, m.UpdateTitle(classify.Labels{}))
+		assert.Equal(t, "Berlin, Germany 2020", m.PhotoTitle)
+	})
+	t.Run("fallback chain", func(t *testing.T) {
+		defer setTitleTemplate("{label|name|city} / {month} {year}", "de")()
+
+		m := titleTestPhoto()
+
+		assert.NoError(t, m.UpdateTitle(classify.Labels{}))
+		assert.Equal(t, "Brandenburger Tor / August 2020", m.PhotoTitle)
+
+		m.Location = nil
+		m.PhotoTitle = ""
+
+		assert.NoError(t, m.UpdateTitle(classify.Labels{}))
+		assert.Equal(t, "August 2020", m.PhotoTitle)
+	})
+	t.Run("all tokens empty", func(t *testing.T) {
+		defer setTitleTemplate("{label} / {city}", "de")()
+
+		m := Photo{}
+
+		assert.NoError(t, m.UpdateTitle(classify.Labels{}))
+		assert.Equal(t, "Unbekannt", m.PhotoTitle)
+	})
+	t.Run("manual title", func(t *testing.T) {
+		defer setTitleTemplate("{label} / {city}, {country} {year}", "en")()
+
+		m := titleTestPhoto()
+		m.SetTitle("Our Trip", SrcManual)
+
+		assert.Error(t, m.UpdateTitle(beach))
+		assert.Equal(t, "Our Trip", m.PhotoTitle)
+		assert.Equal(t, SrcManual, m.TitleSrc)
+	})
+	t.Run("default", func(t *testing.T) {
+		defer setTitleTemplate("", "en")()
+
+		m := titleTestPhoto()
+
+		assert.NoError(t, m.UpdateTitle(beach))
+		assert.Equal(t, "Beach / Berlin / 2020", m.PhotoTitle)
+	})
+	t.Run("default unknown", func(t *testing.T) {
+		defer setTitleTemplate("", "de")()
+
+		m := Photo{TakenAtLocal: time.Date(2020, 8, 6, 12, 0, 0, 0, time.UTC)}
+
+		assert.NoError(t, m.UpdateTitle(classify.Labels{}))
+		assert.Equal(t, "Unbekannt / 2020", m.PhotoTitle)
+	})
+}
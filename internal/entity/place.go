@@ -5,13 +5,13 @@ import (
 
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/maps"
-	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 // Place used to associate photos to places
 type Place struct {
 	ID          string `gorm:"type:varbinary(16);primary_key;auto_increment:false;"`
 	LocLabel    string `gorm:"type:varbinary(512);unique_index;"`
+	LocSearch   string `gorm:"type:varbinary(512);" json:"-"`
 	LocCity     string `gorm:"type:varchar(128);"`
 	LocState    string `gorm:"type:varchar(128);"`
 	LocCountry  string `gorm:"type:varbinary(2);"`
@@ -41,6 +41,11 @@ func (m *Place) AfterCreate(scope *gorm.Scope) error {
 	return scope.SetColumn("New", true)
 }
 
+// BeforeSave updates the normalized label used for search
+func (m *Place) BeforeSave(scope *gorm.Scope) error {
+	return setSearch(scope, "LocSearch", "loc_label", m.LocLabel)
+}
+
 // FindPlaceByLabel returns a place from an id or a label
 func FindPlaceByLabel(id string, label string, db *gorm.DB) *Place {
 	place := &Place{}
@@ -64,8 +69,7 @@ func (m *Place) Find(db *gorm.DB) error {
 
 // FirstOrCreate checks wether the place already exists in the database
 func (m *Place) FirstOrCreate(db *gorm.DB) *Place {
-	mutex.Db.Lock()
-	defer mutex.Db.Unlock()
+	defer lockDb(db)()
 
 	if err := db.FirstOrCreate(m, "id = ? OR loc_label = ?", m.ID, m.LocLabel).Error; err != nil {
 		log.Debugf("place: %s for token %s or label \"%s\"", err.Error(), m.ID, m.LocLabel)
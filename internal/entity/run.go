@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// Run types.
+const (
+	RunImport = "import"
+	RunIndex  = "index"
+)
+
+// RunMaxAge is the time after which the progress of import and index runs is deleted.
+var RunMaxAge = 7 * 24 * time.Hour
+
+// Run stores the progress of an import or index run, so that an interrupted run can be resumed
+// without processing completed folders again.
+type Run struct {
+	ID         uint   `gorm:"primary_key"`
+	RunUUID    string `gorm:"type:varbinary(36);unique_index;"`
+	RunType    string `gorm:"type:varbinary(16);index:idx_runs_type_path;"`
+	RunPath    string `gorm:"type:varbinary(512);index:idx_runs_type_path;"`
+	RunDir     string `gorm:"type:varbinary(1024);"`
+	RunCursor  string `gorm:"type:varbinary(1024);"`
+	RunFiles   int
+	RunResumed int
+	FinishedAt *time.Time
+	CreatedAt  time.Time `gorm:"index"`
+	UpdatedAt  time.Time `gorm:"index"`
+}
+
+// BeforeCreate creates a random UUID if needed before inserting a new row to the database.
+func (m *Run) BeforeCreate(scope *gorm.Scope) error {
+	if m.RunUUID != "" {
+		return nil
+	}
+
+	return scope.SetColumn("RunUUID", rnd.PPID('r'))
+}
+
+// NewRun returns a new import or index run for a path.
+func NewRun(runType, runPath string) *Run {
+	result := &Run{
+		RunUUID: rnd.PPID('r'),
+		RunType: runType,
+		RunPath: runPath,
+	}
+
+	return result
+}
+
+// FindInterruptedRun returns the most recent run of the same type and path, if it didn't finish.
+func FindInterruptedRun(db *gorm.DB, runType, runPath string) (*Run, error) {
+	var result Run
+
+	if err := db.Where("run_type = ? AND run_path = ?", runType, runPath).Order("id DESC").First(&result).Error; err != nil {
+		return nil, err
+	}
+
+	if result.Finished() {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &result, nil
+}
+
+// DeleteExpiredRuns deletes the progress of runs that weren't updated since maxAge.
+func DeleteExpiredRuns(db *gorm.DB, maxAge time.Duration) (int64, error) {
+	result := db.Where("updated_at < ?", time.Now().Add(-maxAge)).Delete(&Run{})
+
+	return result.RowsAffected, result.Error
+}
+
+// Finished returns true if the run was completed without being interrupted.
+func (m *Run) Finished() bool {
+	return m.FinishedAt != nil
+}
+
+// Resumed returns true if the run continues an interrupted run.
+func (m *Run) Resumed() bool {
+	return m.RunResumed > 0
+}
+
+// Save stores the progress of the run.
+func (m *Run) Save(db *gorm.DB) error {
+	return db.Save(m).Error
+}
+
+// Finish marks the run as completed and stores its progress.
+func (m *Run) Finish(db *gorm.DB) error {
+	now := time.Now()
+	m.FinishedAt = &now
+
+	return m.Save(db)
+}
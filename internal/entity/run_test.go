@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRunTestDb(t *testing.T) (db *gorm.DB, cleanup func()) {
+	dir, err := ioutil.TempDir("", "run")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = gorm.Open("sqlite3", filepath.Join(dir, "index.db"))
+
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	if err := db.AutoMigrate(&Run{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestNewRun(t *testing.T) {
+	run := NewRun(RunImport, "/import")
+
+	assert.Len(t, run.RunUUID, 16)
+	assert.Equal(t, uint8('r'), run.RunUUID[0])
+	assert.Equal(t, RunImport, run.RunType)
+	assert.False(t, run.Finished())
+	assert.False(t, run.Resumed())
+}
+
+func TestFindInterruptedRun(t *testing.T) {
+	db, cleanup := newRunTestDb(t)
+	defer cleanup()
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := FindInterruptedRun(db, RunIndex, "default:")
+
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+	t.Run("interrupted", func(t *testing.T) {
+		run := NewRun(RunIndex, "default:2020")
+		run.RunDir = "2020/01"
+
+		if err := run.Save(db); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := FindInterruptedRun(db, RunIndex, "default:2020")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, run.RunUUID, result.RunUUID)
+		assert.Equal(t, "2020/01", result.RunDir)
+
+		_, err = FindInterruptedRun(db, RunImport, "default:2020")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+	t.Run("finished", func(t *testing.T) {
+		run := NewRun(RunIndex, "default:2021")
+
+		if err := run.Finish(db); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := FindInterruptedRun(db, RunIndex, "default:2021")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+}
+
+func TestDeleteExpiredRuns(t *testing.T) {
+	db, cleanup := newRunTestDb(t)
+	defer cleanup()
+
+	current := NewRun(RunImport, "/import")
+	expired := NewRun(RunImport, "/import")
+
+	if err := current.Save(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := expired.Save(db); err != nil {
+		t.Fatal(err)
+	}
+
+	db.Model(expired).UpdateColumn("updated_at", time.Now().Add(-8*24*time.Hour))
+
+	n, err := DeleteExpiredRuns(db, RunMaxAge)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(1), n)
+
+	var count int
+	db.Model(&Run{}).Count(&count)
+	assert.Equal(t, 1, count)
+}
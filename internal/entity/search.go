@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// setSearch sets a search column to the normalized value of the name column it belongs to. Updates
+// of other columns don't change it, as the name field of the model may not be loaded.
+func setSearch(scope *gorm.Scope, field, nameColumn, name string) error {
+	if attrs, ok := scope.InstanceGet("gorm:update_attrs"); ok {
+		if updates, ok := attrs.(map[string]interface{}); ok {
+			if _, ok := updates[nameColumn]; !ok {
+				return nil
+			}
+		}
+	}
+
+	return scope.SetColumn(field, txt.Normalize(name))
+}
@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSearchTestDb returns a new SQLite database with labels, albums, places, photos and keywords.
+func newSearchTestDb(t *testing.T) (db *gorm.DB, cleanup func()) {
+	dir, err := ioutil.TempDir("", "search")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = gorm.Open("sqlite3", filepath.Join(dir, "index.db"))
+
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	if err := db.AutoMigrate(&Label{}, &Album{}, &Place{}, &Photo{}, &Keyword{}, &PhotoKeyword{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSetSearch(t *testing.T) {
+	db, cleanup := newSearchTestDb(t)
+	defer cleanup()
+
+	t.Run("label", func(t *testing.T) {
+		label := NewLabel("Zürich", 0)
+
+		if err := db.Create(label).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Zürich", label.LabelName)
+		assert.Equal(t, "zurich", label.LabelSearch)
+
+		if err := db.Model(label).Updates(map[string]interface{}{"label_name": "Genève"}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		var result Label
+		db.First(&result, label.ID)
+		assert.Equal(t, "geneve", result.LabelSearch)
+	})
+	t.Run("album", func(t *testing.T) {
+		album := NewAlbum("Île-de-France")
+
+		if err := db.Create(album).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "ile-de-france", album.AlbumSearch)
+	})
+	t.Run("place", func(t *testing.T) {
+		place := &Place{ID: "br:sao-paulo", LocLabel: "São Paulo, Brazil", LocCountry: "br"}
+
+		if err := db.Create(place).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "sao paulo, brazil", place.LocSearch)
+	})
+	t.Run("photo", func(t *testing.T) {
+		photo := &Photo{PhotoTitle: "Neckarbrücke"}
+
+		if err := db.Create(photo).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "neckarbrucke", photo.TitleSearch)
+
+		// Updates of other columns must keep the search column.
+		if err := db.Model(&Photo{ID: photo.ID}).Updates(map[string]interface{}{"photo_quality": 3}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		var result Photo
+		db.First(&result, photo.ID)
+		assert.Equal(t, "Neckarbrücke", result.PhotoTitle)
+		assert.Equal(t, "neckarbrucke", result.TitleSearch)
+	})
+}
+
+func TestNormalizeColumn(t *testing.T) {
+	db, cleanup := newSearchTestDb(t)
+	defer cleanup()
+
+	// Rows created before the migration have empty search columns.
+	db.Exec("INSERT INTO labels (id, label_slug, custom_slug, label_name) VALUES (1, 'koln', 'koln', 'KÖLN')")
+	db.Exec("INSERT INTO keywords (id, keyword) VALUES (1, 'köln')")
+	db.Exec("INSERT INTO photos_keywords (photo_id, keyword_id) VALUES (5, 1)")
+
+	if err := normalizeColumn(db, "labels", "label_name", "label_search"); err != nil {
+		t.Fatal(err)
+	}
+
+	var label Label
+	db.First(&label, 1)
+	assert.Equal(t, "koln", label.LabelSearch)
+
+	if err := normalizeKeywords(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var keyword Keyword
+
+	if err := db.First(&keyword, "keyword = ?", "koln").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var count int
+	db.Model(&PhotoKeyword{}).Where("photo_id = 5 AND keyword_id = ?", keyword.ID).Count(&count)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Session represents a user session, only a hash of the session token is stored.
+type Session struct {
+	SessionHash string    `gorm:"type:varbinary(64);primary_key;auto_increment:false"`
+	SessionData string    `gorm:"type:text;"`
+	LastActive  time.Time `gorm:"index"`
+	CreatedAt   time.Time `gorm:"index"`
+	UpdatedAt   time.Time
+}
+
+// SessionHash returns the hash of a session token as stored in the database.
+func SessionHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// FindSession returns the session for a token, expired sessions are returned as well.
+func FindSession(db *gorm.DB, token string) (*Session, error) {
+	return FindSessionHash(db, SessionHash(token))
+}
+
+// FindSessionHash returns the session for a token hash, expired sessions are returned as well.
+func FindSessionHash(db *gorm.DB, hash string) (*Session, error) {
+	var result Session
+
+	if err := db.Where("session_hash = ?", hash).First(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteSession deletes the session for a token.
+func DeleteSession(db *gorm.DB, token string) error {
+	return db.Where("session_hash = ?", SessionHash(token)).Delete(&Session{}).Error
+}
+
+// DeleteExpiredSessions deletes sessions that were inactive for longer than timeout,
+// or that were created before maxAge.
+func DeleteExpiredSessions(db *gorm.DB, timeout, maxAge time.Duration) (int64, error) {
+	now := time.Now()
+	result := db.Where("last_active < ? OR created_at < ?", now.Add(-timeout), now.Add(-maxAge)).Delete(&Session{})
+
+	return result.RowsAffected, result.Error
+}
+
+// Expired returns true if the session was inactive for longer than timeout or was created before maxAge.
+func (m *Session) Expired(now time.Time, timeout, maxAge time.Duration) bool {
+	return now.Sub(m.LastActive) > timeout || now.Sub(m.CreatedAt) > maxAge
+}
@@ -0,0 +1,62 @@
+-- SQLite schema of the index database before schema migrations were added, created by gorm AutoMigrate.
+CREATE TABLE "accounts" ("id" integer primary key autoincrement,"acc_name" varchar(255),"acc_owner" varchar(255),"acc_url" varbinary(512),"acc_type" varbinary(255),"acc_key" varbinary(255),"acc_user" varbinary(255),"acc_pass" varbinary(255),"acc_error" varbinary(512),"acc_errors" integer,"acc_share" bool,"acc_sync" bool,"retry_limit" integer,"share_path" varbinary(255),"share_size" varbinary(16),"share_expires" integer,"sync_path" varbinary(255),"sync_status" varbinary(16),"sync_interval" integer,"sync_date" datetime,"sync_upload" bool,"sync_download" bool,"sync_filenames" bool,"sync_raw" bool,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime );
+CREATE TABLE "albums" ("id" integer primary key autoincrement,"cover_uuid" varbinary(36),"album_uuid" varbinary(36),"album_slug" varbinary(255),"album_name" varchar(255),"album_description" text,"album_notes" text,"album_order" varbinary(32),"album_template" varbinary(255),"album_favorite" bool,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime );
+CREATE TABLE "cameras" ("id" integer primary key autoincrement,"camera_slug" varbinary(255),"camera_model" varchar(255),"camera_make" varchar(255),"camera_type" varchar(255),"camera_description" text,"camera_notes" text,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime );
+CREATE TABLE "categories" ("label_id" integer,"category_id" integer, PRIMARY KEY ("label_id","category_id"));
+CREATE TABLE "countries" ("id" varbinary(2),"country_slug" varbinary(255),"country_name" varchar(255),"country_description" text,"country_notes" text,"country_photo_id" integer , PRIMARY KEY ("id"));
+CREATE TABLE "descriptions" ("photo_id" integer,"photo_description" text,"photo_keywords" text,"photo_notes" text,"photo_subject" varchar(255),"photo_artist" varchar(255),"photo_copyright" varchar(255),"photo_license" varchar(255) , PRIMARY KEY ("photo_id"));
+CREATE TABLE "events" ("event_uuid" varbinary(36),"event_slug" varbinary(255),"event_name" varchar(255),"event_type" varchar(255),"event_description" text,"event_notes" text,"event_begin" datetime,"event_end" datetime,"event_lat" FLOAT,"event_lng" FLOAT,"event_dist" FLOAT,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime );
+CREATE TABLE "files" ("id" integer primary key autoincrement,"photo_id" integer,"photo_uuid" varbinary(36),"file_uuid" varbinary(36),"file_name" varbinary(768),"original_name" varbinary(768),"file_hash" varbinary(128),"file_modified" datetime,"file_size" bigint,"file_type" varbinary(32),"file_mime" varbinary(64),"file_primary" bool,"file_sidecar" bool,"file_video" bool,"file_missing" bool,"file_duplicate" bool,"file_portrait" bool,"file_width" integer,"file_height" integer,"file_orientation" integer,"file_aspect_ratio" FLOAT,"file_main_color" varbinary(16),"file_colors" binary(9),"file_luminance" binary(9),"file_diff" integer,"file_chroma" integer,"file_notes" text,"file_error" varbinary(512),"created_at" datetime,"created_in" bigint,"updated_at" datetime,"updated_in" bigint,"deleted_at" datetime );
+CREATE TABLE "files_share" ("file_id" integer,"account_id" integer,"remote_name" varbinary(255),"status" varbinary(16),"error" varbinary(512),"errors" integer,"created_at" datetime,"updated_at" datetime , PRIMARY KEY ("file_id","account_id","remote_name"));
+CREATE TABLE "files_sync" ("remote_name" varbinary(255),"account_id" integer,"file_id" integer,"remote_date" datetime,"remote_size" bigint,"status" varbinary(16),"error" varbinary(512),"errors" integer,"created_at" datetime,"updated_at" datetime , PRIMARY KEY ("remote_name","account_id"));
+CREATE TABLE "keywords" ("id" integer primary key autoincrement,"keyword" varchar(64),"skip" bool );
+CREATE TABLE "labels" ("id" integer primary key autoincrement,"label_uuid" varbinary(36),"label_slug" varbinary(255),"custom_slug" varbinary(255),"label_name" varchar(255),"label_priority" integer,"label_favorite" bool,"label_description" text,"label_notes" text,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime );
+CREATE TABLE "lenses" ("id" integer primary key autoincrement,"lens_slug" varbinary(255),"lens_model" varchar(255),"lens_make" varchar(255),"lens_type" varchar(255),"lens_owner" varchar(255),"lens_description" text,"lens_notes" text,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime );
+CREATE TABLE "links" ("link_token" varbinary(255),"link_password" varbinary(255),"link_expires" datetime,"share_uuid" varbinary(36),"can_comment" bool,"can_edit" bool,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime , PRIMARY KEY ("link_token"));
+CREATE TABLE "locations" ("id" varbinary(16),"place_id" varbinary(16),"loc_name" varchar(255),"loc_category" varchar(64),"loc_source" varbinary(16),"created_at" datetime,"updated_at" datetime , PRIMARY KEY ("id"));
+CREATE TABLE "photos" ("id" integer primary key autoincrement,"photo_uuid" varbinary(36),"taken_at" datetime,"taken_at_local" datetime,"taken_src" varbinary(8),"photo_title" varchar(255),"title_src" varbinary(8),"photo_path" varbinary(768),"photo_name" varbinary(255),"photo_quality" SMALLINT,"photo_resolution" SMALLINT,"photo_favorite" bool,"photo_private" bool,"photo_story" bool,"photo_lat" FLOAT,"photo_lng" FLOAT,"photo_altitude" integer,"photo_iso" integer,"photo_focal_length" integer,"photo_f_number" FLOAT,"photo_exposure" varbinary(64),"camera_id" integer,"camera_serial" varbinary(255),"camera_src" varbinary(8),"lens_id" integer,"place_id" varbinary(16) DEFAULT 'zz',"location_id" varbinary(16),"location_src" varbinary(8),"time_zone" varbinary(64),"photo_country" varbinary(2) DEFAULT 'zz',"photo_year" integer,"photo_month" integer,"description_src" varbinary(8),"created_at" datetime,"updated_at" datetime,"edited_at" datetime,"deleted_at" datetime );
+CREATE TABLE "photos_albums" ("photo_uuid" varbinary(36),"album_uuid" varbinary(36),"order" integer,"created_at" datetime,"updated_at" datetime , PRIMARY KEY ("photo_uuid","album_uuid"));
+CREATE TABLE "photos_keywords" ("photo_id" integer,"keyword_id" integer , PRIMARY KEY ("photo_id","keyword_id"));
+CREATE TABLE "photos_labels" ("photo_id" integer,"label_id" integer,"label_src" varbinary(8),"uncertainty" SMALLINT , PRIMARY KEY ("photo_id","label_id"));
+CREATE TABLE "places" ("id" varbinary(16),"loc_label" varbinary(512),"loc_city" varchar(128),"loc_state" varchar(128),"loc_country" varbinary(2),"loc_notes" text,"loc_favorite" bool,"created_at" datetime,"updated_at" datetime , PRIMARY KEY ("id"));
+CREATE INDEX idx_accounts_deleted_at ON "accounts"(deleted_at) ;
+CREATE INDEX idx_albums_album_slug ON "albums"(album_slug) ;
+CREATE INDEX idx_albums_deleted_at ON "albums"(deleted_at) ;
+CREATE INDEX idx_cameras_deleted_at ON "cameras"(deleted_at) ;
+CREATE INDEX idx_events_deleted_at ON "events"(deleted_at) ;
+CREATE INDEX idx_files_deleted_at ON "files"(deleted_at) ;
+CREATE INDEX idx_files_file_hash ON "files"(file_hash) ;
+CREATE INDEX idx_files_file_main_color ON "files"(file_main_color) ;
+CREATE INDEX idx_files_photo_id ON "files"(photo_id) ;
+CREATE INDEX idx_files_photo_uuid ON "files"(photo_uuid) ;
+CREATE INDEX idx_files_sync_file_id ON "files_sync"(file_id) ;
+CREATE INDEX idx_keywords_keyword ON "keywords"("keyword") ;
+CREATE INDEX idx_labels_custom_slug ON "labels"(custom_slug) ;
+CREATE INDEX idx_labels_deleted_at ON "labels"(deleted_at) ;
+CREATE INDEX idx_lenses_deleted_at ON "lenses"(deleted_at) ;
+CREATE INDEX idx_links_deleted_at ON "links"(deleted_at) ;
+CREATE INDEX idx_links_share_uuid ON "links"(share_uuid) ;
+CREATE INDEX idx_photos_albums_album_uuid ON "photos_albums"(album_uuid) ;
+CREATE INDEX idx_photos_camera_lens ON "photos"(camera_id, lens_id) ;
+CREATE INDEX idx_photos_country_year_month ON "photos"(photo_country, photo_year, photo_month) ;
+CREATE INDEX idx_photos_deleted_at ON "photos"(deleted_at) ;
+CREATE INDEX idx_photos_keywords_keyword_id ON "photos_keywords"(keyword_id) ;
+CREATE INDEX idx_photos_labels_label_id ON "photos_labels"(label_id) ;
+CREATE INDEX idx_photos_location_id ON "photos"(location_id) ;
+CREATE INDEX idx_photos_photo_lat ON "photos"(photo_lat) ;
+CREATE INDEX idx_photos_photo_lng ON "photos"(photo_lng) ;
+CREATE INDEX idx_photos_photo_path ON "photos"(photo_path) ;
+CREATE INDEX idx_photos_place_id ON "photos"(place_id) ;
+CREATE INDEX idx_photos_taken_uuid ON "photos"(photo_uuid, taken_at) ;
+CREATE UNIQUE INDEX uix_albums_album_uuid ON "albums"(album_uuid) ;
+CREATE UNIQUE INDEX uix_cameras_camera_slug ON "cameras"(camera_slug) ;
+CREATE UNIQUE INDEX uix_countries_country_slug ON "countries"(country_slug) ;
+CREATE UNIQUE INDEX uix_events_event_slug ON "events"(event_slug) ;
+CREATE UNIQUE INDEX uix_events_event_uuid ON "events"(event_uuid) ;
+CREATE UNIQUE INDEX uix_files_file_name ON "files"(file_name) ;
+CREATE UNIQUE INDEX uix_files_file_uuid ON "files"(file_uuid) ;
+CREATE UNIQUE INDEX uix_labels_label_slug ON "labels"(label_slug) ;
+CREATE UNIQUE INDEX uix_labels_label_uuid ON "labels"(label_uuid) ;
+CREATE UNIQUE INDEX uix_lenses_lens_slug ON "lenses"(lens_slug) ;
+CREATE UNIQUE INDEX uix_photos_photo_uuid ON "photos"(photo_uuid) ;
+CREATE UNIQUE INDEX uix_places_loc_label ON "places"(loc_label) ;
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MaxTimelineEntries limits the number of processing steps stored per file.
+const MaxTimelineEntries = 64
+
+// TimelineEntry represents a single processing step like reading metadata or creating thumbnails.
+type TimelineEntry struct {
+	Stage   string    `json:"stage"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// Timeline contains the processing steps of the last indexing run.
+type Timeline []TimelineEntry
+
+// Add appends a new entry to the timeline, existing entries are dropped if the limit is reached.
+func (t *Timeline) Add(stage, format string, args ...interface{}) {
+	*t = append(*t, TimelineEntry{
+		Stage:   stage,
+		Time:    time.Now().UTC(),
+		Message: fmt.Sprintf(format, args...),
+	})
+
+	if n := len(*t); n > MaxTimelineEntries {
+		*t = (*t)[n-MaxTimelineEntries:]
+	}
+}
+
+// Timeline returns the processing steps recorded during the last indexing run.
+func (m *File) Timeline() (result Timeline) {
+	if m.FileTimeline == "" {
+		return result
+	}
+
+	if err := json.Unmarshal([]byte(m.FileTimeline), &result); err != nil {
+		log.Errorf("file: can't decode timeline (%s)", err)
+	}
+
+	return result
+}
+
+// SetTimeline replaces the processing steps stored for this file.
+func (m *File) SetTimeline(t Timeline) {
+	if len(t) > MaxTimelineEntries {
+		t = t[len(t)-MaxTimelineEntries:]
+	}
+
+	if data, err := json.Marshal(t); err != nil {
+		log.Errorf("file: can't encode timeline (%s)", err)
+	} else {
+		m.FileTimeline = string(data)
+	}
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeline_Add(t *testing.T) {
+	t.Run("message", func(t *testing.T) {
+		var timeline Timeline
+
+		timeline.Add("meta", "TakenAt from %s", "filename")
+
+		assert.Len(t, timeline, 1)
+		assert.Equal(t, "meta", timeline[0].Stage)
+		assert.Equal(t, "TakenAt from filename", timeline[0].Message)
+		assert.False(t, timeline[0].Time.IsZero())
+	})
+	t.Run("limit", func(t *testing.T) {
+		var timeline Timeline
+
+		for i := 0; i <= MaxTimelineEntries; i++ {
+			timeline.Add("index", "step %d", i)
+		}
+
+		assert.Len(t, timeline, MaxTimelineEntries)
+		assert.Equal(t, "step 1", timeline[0].Message)
+	})
+}
+
+func TestFile_Timeline(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		file := &File{}
+
+		assert.Empty(t, file.Timeline())
+	})
+	t.Run("encoded", func(t *testing.T) {
+		var timeline Timeline
+		timeline.Add("thumbs", "created 3 thumbnails")
+
+		file := &File{}
+		file.SetTimeline(timeline)
+
+		assert.Contains(t, file.FileTimeline, "created 3 thumbnails")
+
+		result := file.Timeline()
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, "thumbs", result[0].Stage)
+	})
+}
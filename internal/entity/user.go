@@ -0,0 +1,184 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User roles.
+const (
+	RoleAdmin    = "admin"
+	RoleViewer   = "viewer"
+	RoleUploader = "uploader"
+)
+
+// UserRoles contains all valid user roles.
+var UserRoles = []string{RoleAdmin, RoleViewer, RoleUploader}
+
+// User represents a user account for the web UI and WebDAV.
+type User struct {
+	ID           uint   `gorm:"primary_key"`
+	UserName     string `gorm:"type:varchar(64);unique_index;"`
+	UserRole     string `gorm:"type:varchar(32);"`
+	PasswordHash string `gorm:"type:varbinary(255);" json:"-"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Admin is the default admin user, it uses the admin password from the config until a password is set.
+var Admin = User{
+	ID:       AdminUserID,
+	UserName: "admin",
+	UserRole: RoleAdmin,
+}
+
+// CreateDefaultUsers initializes the database with the default admin user if not exists.
+func CreateDefaultUsers(db *gorm.DB) {
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	m := Admin
+
+	if err := db.FirstOrCreate(&m, "id = ?", m.ID).Error; err != nil {
+		log.Errorf("user: %s", err)
+	}
+}
+
+// NewUser returns a new user with the given name, password and role.
+func NewUser(userName, password, role string) (*User, error) {
+	m := &User{
+		UserName: strings.ToLower(strings.TrimSpace(userName)),
+		UserRole: strings.ToLower(strings.TrimSpace(role)),
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := m.SetPassword(password); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindUser returns the user with the given id.
+func FindUser(db *gorm.DB, id uint) (*User, error) {
+	var result User
+
+	if err := db.Where("id = ?", id).First(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindUserByName returns the user with the given name.
+func FindUserByName(db *gorm.DB, userName string) (*User, error) {
+	var result User
+
+	if err := db.Where("user_name = ?", strings.ToLower(strings.TrimSpace(userName))).First(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CountUsersWithPassword returns the number of users who can log in with their own password.
+func CountUsersWithPassword(db *gorm.DB) (count int, err error) {
+	err = db.Model(&User{}).Where("password_hash IS NOT NULL AND password_hash <> ''").Count(&count).Error
+
+	return count, err
+}
+
+// Validate returns an error if the user name is empty or the role is unknown.
+func (m *User) Validate() error {
+	if m.UserName == "" {
+		return fmt.Errorf("user name must not be empty")
+	}
+
+	if strings.ContainsAny(m.UserName, ": \t") {
+		return fmt.Errorf("user name must not contain spaces or colons")
+	}
+
+	for _, role := range UserRoles {
+		if m.UserRole == role {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown role \"%s\", use %s", m.UserRole, strings.Join(UserRoles, ", "))
+}
+
+// SetPassword stores a hash of the password.
+func (m *User) SetPassword(password string) error {
+	if len(password) < 4 {
+		return fmt.Errorf("password must have at least 4 characters")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	if err != nil {
+		return err
+	}
+
+	m.PasswordHash = string(hash)
+
+	return nil
+}
+
+// HasPassword returns true if a password was set.
+func (m *User) HasPassword() bool {
+	return m.PasswordHash != ""
+}
+
+// CheckPassword returns true if the password is correct, users without password can't log in.
+func (m *User) CheckPassword(password string) bool {
+	if !m.HasPassword() {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(m.PasswordHash), []byte(password)) == nil
+}
+
+// Admin returns true if the user has the admin role.
+func (m *User) Admin() bool {
+	return m.UserRole == RoleAdmin
+}
+
+// CanModify returns true if the user may change and delete photos, albums and labels.
+func (m *User) CanModify() bool {
+	return m.UserRole == RoleAdmin
+}
+
+// CanUpload returns true if the user may upload and import files.
+func (m *User) CanUpload() bool {
+	return m.UserRole == RoleAdmin || m.UserRole == RoleUploader
+}
+
+// Save creates or updates the user.
+func (m *User) Save(db *gorm.DB) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	return db.Save(m).Error
+}
+
+// Delete removes the user, the default admin user can't be removed.
+func (m *User) Delete(db *gorm.DB) error {
+	if m.ID == AdminUserID {
+		return fmt.Errorf("default admin user can't be removed")
+	}
+
+	if err := db.Delete(m).Error; err != nil {
+		return err
+	}
+
+	return db.Where("user_id = ?", m.ID).Delete(&UserSettings{}).Error
+}
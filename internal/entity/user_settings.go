@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// AdminUserID is the user id of the admin account.
+const AdminUserID uint = 1
+
+// UserSettings contains the Web UI settings of a user as JSON, see config.Settings.
+type UserSettings struct {
+	UserID       uint   `gorm:"primary_key;auto_increment:false"`
+	SettingsData string `gorm:"type:text;"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// FindUserSettings returns the stored settings of a user.
+func FindUserSettings(db *gorm.DB, userID uint) (*UserSettings, error) {
+	var result UserSettings
+
+	if err := db.Where("user_id = ?", userID).First(&result).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Save creates or updates the user settings.
+func (m *UserSettings) Save(db *gorm.DB) error {
+	return db.Save(m).Error
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUser(t *testing.T) {
+	t.Run("viewer", func(t *testing.T) {
+		m, err := NewUser(" Jens ", "secret", "Viewer")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jens", m.UserName)
+		assert.Equal(t, RoleViewer, m.UserRole)
+		assert.True(t, m.HasPassword())
+		assert.NotEqual(t, "secret", m.PasswordHash)
+		assert.False(t, m.CanModify())
+		assert.False(t, m.CanUpload())
+	})
+	t.Run("uploader", func(t *testing.T) {
+		m, err := NewUser("max", "secret", RoleUploader)
+
+		assert.NoError(t, err)
+		assert.False(t, m.CanModify())
+		assert.True(t, m.CanUpload())
+	})
+	t.Run("unknown role", func(t *testing.T) {
+		_, err := NewUser("max", "secret", "editor")
+		assert.EqualError(t, err, "unknown role \"editor\", use admin, viewer, uploader")
+	})
+	t.Run("empty name", func(t *testing.T) {
+		_, err := NewUser(" ", "secret", RoleViewer)
+		assert.EqualError(t, err, "user name must not be empty")
+	})
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := NewUser("max:1", "secret", RoleViewer)
+		assert.Error(t, err)
+	})
+	t.Run("short password", func(t *testing.T) {
+		_, err := NewUser("max", "abc", RoleViewer)
+		assert.EqualError(t, err, "password must have at least 4 characters")
+	})
+}
+
+func TestUser_CheckPassword(t *testing.T) {
+	m, err := NewUser("anna", "secret", RoleAdmin)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, m.CheckPassword("secret"))
+	assert.False(t, m.CheckPassword("Secret"))
+	assert.False(t, m.CheckPassword(""))
+
+	admin := Admin
+	assert.False(t, admin.HasPassword())
+	assert.False(t, admin.CheckPassword(""))
+	assert.True(t, admin.Admin())
+}
@@ -22,8 +22,9 @@ func (h *Hook) Fire(entry *logrus.Entry) error {
 		Name: "log." + entry.Level.String(),
 		Fields: Data{
 			"time":  entry.Time,
-			"level": entry.Level.String(),
-			"msg":   entry.Message,
+			"level":     entry.Level.String(),
+			"component": logComponent(entry.Message),
+			"msg":       entry.Message,
 		},
 	})
 
@@ -37,6 +38,7 @@ func (h *Hook) Levels() []logrus.Level {
 func init() {
 	hooks := logrus.LevelHooks{}
 	hooks.Add(NewHook(SharedHub()))
+	hooks.Add(Logs)
 
 	Log = &logrus.Logger{
 		Out:          os.Stderr,
@@ -0,0 +1,127 @@
+package event
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogEntry represents a log message kept in a LogBuffer.
+type LogEntry struct {
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Component string    `json:"component"`
+	Message   string    `json:"msg"`
+
+	level logrus.Level
+}
+
+// LogBuffer is a logrus hook that keeps the most recent log entries in memory.
+// Debug entries are only kept in debug mode.
+type LogBuffer struct {
+	entries []LogEntry
+	next    int
+	size    int
+	debug   bool
+	mutex   sync.RWMutex
+}
+
+// Logs contains the most recent log entries of Log.
+var Logs = NewLogBuffer(1000)
+
+// NewLogBuffer returns a new log buffer that keeps up to size entries.
+func NewLogBuffer(size int) *LogBuffer {
+	b := &LogBuffer{}
+	b.Configure(size, false)
+
+	return b
+}
+
+// Configure changes the max number of entries and whether debug entries are kept.
+// Existing entries are removed if the size changes.
+func (b *LogBuffer) Configure(size int, debug bool) {
+	if size < 0 {
+		size = 0
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if size != b.size {
+		b.entries = make([]LogEntry, 0, size)
+		b.next = 0
+		b.size = size
+	}
+
+	b.debug = debug
+}
+
+// Levels returns the log levels entries are kept for.
+func (b *LogBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds a log entry, replacing the oldest entry if the buffer is full.
+func (b *LogBuffer) Fire(entry *logrus.Entry) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.size == 0 || entry.Level > logrus.InfoLevel && !b.debug {
+		return nil
+	}
+
+	e := LogEntry{
+		Time:      entry.Time,
+		Level:     entry.Level.String(),
+		Component: logComponent(entry.Message),
+		Message:   entry.Message,
+		level:     entry.Level,
+	}
+
+	if len(b.entries) < b.size {
+		b.entries = append(b.entries, e)
+		return nil
+	}
+
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % b.size
+
+	return nil
+}
+
+// Entries returns up to count of the most recent entries with the given level or above,
+// oldest first. All matching entries are returned if count is 0 or less.
+func (b *LogBuffer) Entries(level logrus.Level, count int) []LogEntry {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	result := make([]LogEntry, 0, len(b.entries))
+
+	// The most recent entry comes first.
+	for i := 0; i < len(b.entries) && (count <= 0 || len(result) < count); i++ {
+		e := b.entries[(b.next-1-i+2*len(b.entries))%len(b.entries)]
+
+		if e.level <= level {
+			result = append(result, e)
+		}
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result
+}
+
+// logComponent returns the component of a log message like "index: ...", if any.
+func logComponent(msg string) string {
+	i := strings.Index(msg, ": ")
+
+	if i < 1 || i > 16 || strings.ContainsAny(msg[:i], " \t") {
+		return ""
+	}
+
+	return msg[:i]
+}
@@ -0,0 +1,92 @@
+package event
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func logEntry(level logrus.Level, msg string) *logrus.Entry {
+	return &logrus.Entry{Time: time.Now(), Level: level, Message: msg}
+}
+
+func TestLogBuffer(t *testing.T) {
+	t.Run("evicts oldest", func(t *testing.T) {
+		b := NewLogBuffer(3)
+
+		for i := 1; i <= 5; i++ {
+			assert.NoError(t, b.Fire(logEntry(logrus.InfoLevel, fmt.Sprintf("index: file %d", i))))
+		}
+
+		entries := b.Entries(logrus.InfoLevel, 0)
+
+		if assert.Len(t, entries, 3) {
+			assert.Equal(t, "index: file 3", entries[0].Message)
+			assert.Equal(t, "index: file 5", entries[2].Message)
+			assert.Equal(t, "index", entries[0].Component)
+			assert.Equal(t, "info", entries[0].Level)
+		}
+
+		entries = b.Entries(logrus.InfoLevel, 2)
+
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "index: file 4", entries[0].Message)
+			assert.Equal(t, "index: file 5", entries[1].Message)
+		}
+	})
+	t.Run("level", func(t *testing.T) {
+		b := NewLogBuffer(10)
+
+		b.Fire(logEntry(logrus.InfoLevel, "info"))
+		b.Fire(logEntry(logrus.WarnLevel, "warning"))
+		b.Fire(logEntry(logrus.ErrorLevel, "error"))
+		b.Fire(logEntry(logrus.InfoLevel, "info 2"))
+
+		entries := b.Entries(logrus.WarnLevel, 0)
+
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "warning", entries[0].Message)
+			assert.Equal(t, "error", entries[1].Message)
+			assert.Equal(t, "", entries[0].Component)
+		}
+
+		assert.Len(t, b.Entries(logrus.ErrorLevel, 0), 1)
+		assert.Len(t, b.Entries(logrus.DebugLevel, 0), 4)
+	})
+	t.Run("debug", func(t *testing.T) {
+		b := NewLogBuffer(10)
+
+		b.Fire(logEntry(logrus.DebugLevel, "skipped"))
+		assert.Empty(t, b.Entries(logrus.TraceLevel, 0))
+
+		b.Configure(10, true)
+		b.Fire(logEntry(logrus.DebugLevel, "kept"))
+		assert.Len(t, b.Entries(logrus.TraceLevel, 0), 1)
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		b := NewLogBuffer(100)
+
+		var wg sync.WaitGroup
+
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+
+			go func(w int) {
+				defer wg.Done()
+
+				for i := 0; i < 50; i++ {
+					b.Fire(logEntry(logrus.WarnLevel, fmt.Sprintf("worker %d", w)))
+					b.Entries(logrus.WarnLevel, 10)
+				}
+			}(w)
+		}
+
+		wg.Wait()
+
+		assert.Len(t, b.Entries(logrus.WarnLevel, 0), 100)
+	})
+}
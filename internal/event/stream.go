@@ -0,0 +1,245 @@
+package event
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/leandro-lugaresi/hub"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// Event represents a published message with a sequence number. Sequence numbers start again after
+// a restart, so they are only comparable within the same epoch.
+type Event struct {
+	Epoch string `json:"epoch"`
+	Seq   uint64 `json:"seq"`
+	Name  string `json:"event"`
+	Data  Data   `json:"data"`
+}
+
+// Stream numbers messages published to a hub, keeps the most recent events of each topic for
+// replay, and delivers events to clients. Publishers are never blocked by slow clients, events
+// are dropped instead if a client buffer is full.
+type Stream struct {
+	sub     hub.Subscription
+	size    int
+	epoch   string
+	seq     uint64
+	recent  map[string]*ring
+	clients map[*Client]bool
+	mutex   sync.Mutex
+	stop    chan bool
+}
+
+// Client receives events matching its topic patterns from a stream.
+type Client struct {
+	C        <-chan Event
+	ch       chan Event
+	patterns []string
+	dropped  uint64
+}
+
+// ring contains the most recent events of a topic.
+type ring struct {
+	events []Event
+	next   int
+}
+
+// NewStream returns a stream of messages published to the hub with the given topic patterns,
+// keeping up to size recent events per topic.
+func NewStream(h *Hub, size int, topics ...string) *Stream {
+	if size < 0 {
+		size = 0
+	}
+
+	s := &Stream{
+		sub:     h.Subscribe(1000, topics...),
+		size:    size,
+		epoch:   rnd.Token(8),
+		recent:  make(map[string]*ring),
+		clients: make(map[*Client]bool),
+		stop:    make(chan bool),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// run numbers and delivers messages until the stream is closed.
+func (s *Stream) run() {
+	for {
+		select {
+		case <-s.stop:
+			return
+		case msg, ok := <-s.sub.Receiver:
+			if !ok {
+				return
+			}
+
+			s.publish(msg)
+		}
+	}
+}
+
+// publish numbers a message, adds it to the recent events and delivers it to matching clients.
+func (s *Stream) publish(msg Message) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.seq++
+
+	ev := Event{Epoch: s.epoch, Seq: s.seq, Name: msg.Name, Data: msg.Fields}
+
+	if s.size > 0 {
+		r, ok := s.recent[ev.Name]
+
+		if !ok {
+			r = &ring{}
+			s.recent[ev.Name] = r
+		}
+
+		r.add(ev, s.size)
+	}
+
+	for c := range s.clients {
+		if !c.Match(ev.Name) {
+			continue
+		}
+
+		select {
+		case c.ch <- ev:
+		default:
+			atomic.AddUint64(&c.dropped, 1)
+		}
+	}
+}
+
+// Close stops receiving messages from the hub.
+func (s *Stream) Close(h *Hub) {
+	h.Unsubscribe(s.sub)
+	close(s.stop)
+}
+
+// Epoch returns a random id of the stream, which changes after a restart.
+func (s *Stream) Epoch() string {
+	return s.epoch
+}
+
+// Seq returns the sequence number of the last event.
+func (s *Stream) Seq() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.seq
+}
+
+// Subscribe adds a client that receives events matching the topic patterns. Up to capacity events
+// are buffered, further events are dropped until the client catches up.
+func (s *Stream) Subscribe(capacity int, patterns ...string) *Client {
+	ch := make(chan Event, capacity)
+	c := &Client{C: ch, ch: ch, patterns: patterns}
+
+	s.mutex.Lock()
+	s.clients[c] = true
+	s.mutex.Unlock()
+
+	return c
+}
+
+// Unsubscribe removes a client, it doesn't receive events afterwards.
+func (s *Stream) Unsubscribe(c *Client) {
+	s.mutex.Lock()
+	delete(s.clients, c)
+	s.mutex.Unlock()
+}
+
+// Resubscribe changes the topic patterns of a client and returns recent events matching the
+// new patterns with a sequence number greater than since, sorted by sequence number. All recent
+// events are returned if since belongs to another epoch, e.g. before the server was restarted.
+func (s *Stream) Resubscribe(c *Client, epoch string, since uint64, patterns ...string) (replay []Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c.patterns = patterns
+
+	if epoch != s.epoch {
+		since = 0
+	}
+
+	for name, r := range s.recent {
+		if !c.Match(name) {
+			continue
+		}
+
+		for _, ev := range r.events {
+			if ev.Seq > since {
+				replay = append(replay, ev)
+			}
+		}
+	}
+
+	sort.Slice(replay, func(i, j int) bool { return replay[i].Seq < replay[j].Seq })
+
+	return replay
+}
+
+// Match returns true if the client is subscribed to the topic.
+func (c *Client) Match(topic string) bool {
+	for _, pattern := range c.patterns {
+		if MatchTopic(pattern, topic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Dropped returns the number of events dropped because the client buffer was full.
+func (c *Client) Dropped() uint64 {
+	return atomic.LoadUint64(&c.dropped)
+}
+
+// MatchTopic returns true if the topic matches the pattern. Topics consist of words separated by dots,
+// a "*" matches any word, and a trailing "*" also matches any number of following words.
+func MatchTopic(pattern, topic string) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	p := strings.Split(pattern, ".")
+	t := strings.Split(topic, ".")
+
+	for i, word := range p {
+		if i >= len(t) {
+			return false
+		}
+
+		if word == "*" {
+			if i == len(p)-1 {
+				return true
+			}
+
+			continue
+		}
+
+		if word != t[i] {
+			return false
+		}
+	}
+
+	return len(p) == len(t)
+}
+
+// add adds an event, replacing the oldest event if the ring is full.
+func (r *ring) add(ev Event, size int) {
+	if len(r.events) < size {
+		r.events = append(r.events, ev)
+		return
+	}
+
+	r.events[r.next] = ev
+	r.next = (r.next + 1) % size
+}
@@ -0,0 +1,117 @@
+package event
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitSeq waits until the stream numbered at least seq events.
+func waitSeq(t *testing.T, s *Stream, seq uint64) {
+	for i := 0; i < 100; i++ {
+		if s.Seq() >= seq {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("stream didn't receive %d events", seq)
+}
+
+func TestMatchTopic(t *testing.T) {
+	assert.True(t, MatchTopic("index.*", "index.indexing"))
+	assert.True(t, MatchTopic("index.*", "index.file.done"))
+	assert.False(t, MatchTopic("index.*", "index"))
+	assert.False(t, MatchTopic("index.*", "import.indexing"))
+	assert.True(t, MatchTopic("photos.updated", "photos.updated"))
+	assert.False(t, MatchTopic("photos.updated", "photos.created"))
+	assert.False(t, MatchTopic("photos.updated", "photos.updated.now"))
+	assert.True(t, MatchTopic("*.updated", "albums.updated"))
+	assert.False(t, MatchTopic("*.updated", "albums.created"))
+	assert.True(t, MatchTopic("*", "config.updated"))
+}
+
+func TestStream(t *testing.T) {
+	h := NewHub()
+	s := NewStream(h, 3, "index.*", "photos.*", "config.*")
+	defer s.Close(h)
+
+	t.Run("subscribe", func(t *testing.T) {
+		c := s.Subscribe(10, "photos.updated", "config.*")
+		defer s.Unsubscribe(c)
+
+		h.Publish(Message{Name: "index.indexing", Fields: Data{"file": "a.jpg"}})
+		h.Publish(Message{Name: "photos.updated", Fields: Data{"id": 1}})
+		h.Publish(Message{Name: "config.updated", Fields: Data{}})
+
+		ev := <-c.C
+		assert.Equal(t, "photos.updated", ev.Name)
+		assert.Equal(t, Data{"id": 1}, ev.Data)
+
+		next := <-c.C
+		assert.Equal(t, "config.updated", next.Name)
+		assert.Equal(t, ev.Seq+1, next.Seq)
+	})
+	t.Run("replay", func(t *testing.T) {
+		since := s.Seq()
+
+		for i := 0; i < 5; i++ {
+			h.Publish(Message{Name: "index.indexing", Fields: Data{"i": i}})
+		}
+
+		h.Publish(Message{Name: "photos.created", Fields: Data{}})
+
+		waitSeq(t, s, since+6)
+
+		// A client reconnects and requests events since the last sequence number it received.
+		c := s.Subscribe(10)
+		defer s.Unsubscribe(c)
+
+		replay := s.Resubscribe(c, s.Epoch(), since+1, "index.*")
+
+		// Only the 3 most recent events of each topic are kept.
+		if assert.Len(t, replay, 3) {
+			assert.Equal(t, since+3, replay[0].Seq)
+			assert.Equal(t, Data{"i": 2}, replay[0].Data)
+			assert.Equal(t, since+5, replay[2].Seq)
+		}
+
+		assert.Empty(t, s.Resubscribe(c, s.Epoch(), s.Seq(), "index.*"))
+	})
+	t.Run("other epoch", func(t *testing.T) {
+		c := s.Subscribe(10)
+		defer s.Unsubscribe(c)
+
+		// Sequence numbers of a previous server instance can't be compared.
+		replay := s.Resubscribe(c, "previous", s.Seq(), "photos.*")
+
+		if assert.Len(t, replay, 2) {
+			assert.Equal(t, "photos.updated", replay[0].Name)
+			assert.Equal(t, "photos.created", replay[1].Name)
+			assert.Equal(t, s.Epoch(), replay[1].Epoch)
+		}
+	})
+	t.Run("slow client", func(t *testing.T) {
+		slow := s.Subscribe(2, "index.*")
+		defer s.Unsubscribe(slow)
+
+		fast := s.Subscribe(100, "index.*")
+		defer s.Unsubscribe(fast)
+
+		since := s.Seq()
+
+		for i := 0; i < 10; i++ {
+			h.Publish(Message{Name: fmt.Sprintf("index.file%d", i), Fields: Data{}})
+		}
+
+		waitSeq(t, s, since+10)
+
+		assert.Equal(t, uint64(8), slow.Dropped())
+		assert.Equal(t, uint64(0), fast.Dropped())
+		assert.Len(t, fast.C, 10)
+		assert.Equal(t, since+1, (<-slow.C).Seq)
+	})
+}
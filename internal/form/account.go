@@ -1,32 +1,40 @@
 package form
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/photoprism/photoprism/internal/remote"
 	"github.com/ulule/deepcopier"
 )
 
 // Account represents a remote service account form for uploading, downloading or syncing media files.
 type Account struct {
-	AccName       string `json:"AccName"`
-	AccOwner      string `json:"AccOwner"`
-	AccURL        string `json:"AccURL"`
-	AccType       string `json:"AccType"`
-	AccKey        string `json:"AccKey"`
-	AccUser       string `json:"AccUser"`
-	AccPass       string `json:"AccPass"`
-	AccError      string `json:"AccError"`
-	AccShare      bool   `json:"AccShare"`
-	AccSync       bool   `json:"AccSync"`
-	RetryLimit    int    `json:"RetryLimit"`
-	SharePath     string `json:"SharePath"`
-	ShareSize     string `json:"ShareSize"`
-	ShareExpires  int    `json:"ShareExpires"`
-	SyncPath      string `json:"SyncPath"`
-	SyncInterval  int    `json:"SyncInterval"`
-	SyncUpload    bool   `json:"SyncUpload"`
-	SyncDownload  bool   `json:"SyncDownload"`
-	SyncFilenames bool   `json:"SyncFilenames"`
-	SyncRaw       bool   `json:"SyncRaw"`
+	AccName           string `json:"AccName"`
+	AccOwner          string `json:"AccOwner"`
+	AccURL            string `json:"AccURL"`
+	AccType           string `json:"AccType"`
+	AccKey            string `json:"AccKey"`
+	AccUser           string `json:"AccUser"`
+	AccPass           string `json:"AccPass"`
+	AccError          string `json:"AccError"`
+	AccShare          bool   `json:"AccShare"`
+	AccSync           bool   `json:"AccSync"`
+	RetryLimit        int    `json:"RetryLimit"`
+	SharePath         string `json:"SharePath"`
+	ShareSize         string `json:"ShareSize"`
+	ShareExpires      int    `json:"ShareExpires"`
+	SyncPath          string `json:"SyncPath"`
+	SyncInterval      int    `json:"SyncInterval"`
+	SyncUpload        bool   `json:"SyncUpload"`
+	SyncDownload      bool   `json:"SyncDownload"`
+	SyncFilenames     bool   `json:"SyncFilenames"`
+	SyncRaw           bool   `json:"SyncRaw"`
+	SyncWindow        string `json:"SyncWindow"`
+	SyncUploadLimit   int    `json:"SyncUploadLimit"`
+	SyncDownloadLimit int    `json:"SyncDownloadLimit"`
+	SyncConflicts     string `json:"SyncConflicts"`
+	SyncDeletes       bool   `json:"SyncDeletes"`
 }
 
 func NewAccount(m interface{}) (f Account, err error) {
@@ -35,6 +43,23 @@ func NewAccount(m interface{}) (f Account, err error) {
 	return f, err
 }
 
+// Validate returns an error if the sync window, transfer limits or conflict policy are invalid.
+func (f *Account) Validate() error {
+	if _, err := remote.ParseWindow(f.SyncWindow); err != nil {
+		return err
+	}
+
+	if f.SyncUploadLimit < 0 || f.SyncDownloadLimit < 0 {
+		return errors.New("transfer limits must not be negative")
+	}
+
+	if !remote.ValidConflictPolicy(f.SyncConflicts) {
+		return fmt.Errorf("unknown conflict policy %q, use %s, %s or %s", f.SyncConflicts, remote.ConflictNewestWins, remote.ConflictKeepBoth, remote.ConflictLocalWins)
+	}
+
+	return nil
+}
+
 func (f *Account) ServiceDiscovery() error {
 	acc, err := remote.Discover(f.AccURL, f.AccUser, f.AccPass)
 
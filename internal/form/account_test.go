@@ -0,0 +1,30 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		f := Account{SyncWindow: "23:00-07:00", SyncUploadLimit: 512, SyncConflicts: "keep-both"}
+
+		assert.NoError(t, f.Validate())
+	})
+	t.Run("invalid window", func(t *testing.T) {
+		f := Account{SyncWindow: "night"}
+
+		assert.EqualError(t, f.Validate(), "invalid time window \"night\", use a format like 23:00-07:00")
+	})
+	t.Run("negative limit", func(t *testing.T) {
+		f := Account{SyncDownloadLimit: -1}
+
+		assert.EqualError(t, f.Validate(), "transfer limits must not be negative")
+	})
+	t.Run("unknown conflict policy", func(t *testing.T) {
+		f := Account{SyncConflicts: "remote-wins"}
+
+		assert.EqualError(t, f.Validate(), "unknown conflict policy \"remote-wins\", use newest-wins, keep-both or local-wins")
+	})
+}
@@ -5,6 +5,10 @@ import "github.com/ulule/deepcopier"
 // Album represents an album edit form.
 type Album struct {
 	AlbumName        string `json:"AlbumName"`
+	AlbumType        string `json:"AlbumType"`
+	AlbumRoot        string `json:"AlbumRoot"`
+	AlbumPath        string `json:"AlbumPath"`
+	AlbumFilter      string `json:"AlbumFilter"`
 	AlbumDescription string `json:"AlbumDescription"`
 	AlbumNotes       string `json:"AlbumNotes"`
 	AlbumOrder       string `json:"AlbumOrder"`
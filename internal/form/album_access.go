@@ -0,0 +1,14 @@
+package form
+
+// AlbumAccess represents the visibility of an album and the roles or users it's restricted to.
+type AlbumAccess struct {
+	Visibility string   `json:"visibility"`
+	Roles      []string `json:"roles"`
+	Users      []string `json:"users"`
+}
+
+// AlbumAccessBatch applies the same visibility template to many albums.
+type AlbumAccessBatch struct {
+	Albums   []string    `json:"albums"`
+	Template AlbumAccess `json:"template"`
+}
@@ -6,6 +6,7 @@ type AlbumSearch struct {
 	ID        string `form:"id"`
 	Slug      string `form:"slug"`
 	Name      string `form:"name"`
+	Type      string `form:"type"`
 	Favorites bool   `form:"favorites"`
 	Count     int    `form:"count" binding:"required"`
 	Offset    int    `form:"offset"`
@@ -0,0 +1,7 @@
+package form
+
+// ApiToken represents a form for creating API tokens.
+type ApiToken struct {
+	Label string `json:"label"`
+	Scope string `json:"scope"`
+}
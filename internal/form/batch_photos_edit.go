@@ -0,0 +1,59 @@
+package form
+
+import (
+	"errors"
+	"strings"
+)
+
+// BatchPhotosEdit represents a partial update of many photos for "/api/v1/batch/photos/edit",
+// values that are nil or empty are not changed.
+type BatchPhotosEdit struct {
+	Photos []string         `json:"photos"`
+	Values BatchPhotoValues `json:"values"`
+}
+
+// BatchPhotoValues contains the changes applied to all selected photos.
+type BatchPhotoValues struct {
+	PhotoPrivate  *bool    `json:"PhotoPrivate"`
+	PhotoFavorite *bool    `json:"PhotoFavorite"`
+	PhotoCountry  *string  `json:"PhotoCountry"`
+	AddLabels     []string `json:"AddLabels"`
+	RemoveLabels  []string `json:"RemoveLabels"`
+	AddToAlbum    string   `json:"AddToAlbum"`
+}
+
+// Empty returns true if no changes were requested.
+func (f BatchPhotoValues) Empty() bool {
+	return f.PhotoPrivate == nil && f.PhotoFavorite == nil && f.PhotoCountry == nil &&
+		len(f.AddLabels) == 0 && len(f.RemoveLabels) == 0 && f.AddToAlbum == ""
+}
+
+// Validate returns an error if no photos were selected or the changes are invalid, the
+// country code is normalized to lower case.
+func (f *BatchPhotosEdit) Validate() error {
+	if len(f.Photos) == 0 {
+		return errors.New("no photos selected")
+	}
+
+	if f.Values.Empty() {
+		return errors.New("no changes")
+	}
+
+	if f.Values.PhotoCountry != nil {
+		code := strings.ToLower(strings.TrimSpace(*f.Values.PhotoCountry))
+
+		if len(code) != 2 {
+			return errors.New("country must be a two-letter code")
+		}
+
+		f.Values.PhotoCountry = &code
+	}
+
+	for _, name := range append(f.Values.AddLabels, f.Values.RemoveLabels...) {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("label names must not be empty")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchPhotosEdit_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		country := " DE"
+		f := BatchPhotosEdit{Photos: []string{"654"}, Values: BatchPhotoValues{PhotoCountry: &country}}
+
+		assert.NoError(t, f.Validate())
+		assert.Equal(t, "de", *f.Values.PhotoCountry)
+	})
+	t.Run("no photos", func(t *testing.T) {
+		f := BatchPhotosEdit{Values: BatchPhotoValues{AddLabels: []string{"Cat"}}}
+
+		assert.EqualError(t, f.Validate(), "no photos selected")
+	})
+	t.Run("no changes", func(t *testing.T) {
+		f := BatchPhotosEdit{Photos: []string{"654"}}
+
+		assert.EqualError(t, f.Validate(), "no changes")
+	})
+	t.Run("invalid country", func(t *testing.T) {
+		country := "Germany"
+		f := BatchPhotosEdit{Photos: []string{"654"}, Values: BatchPhotoValues{PhotoCountry: &country}}
+
+		assert.EqualError(t, f.Validate(), "country must be a two-letter code")
+	})
+	t.Run("empty label", func(t *testing.T) {
+		f := BatchPhotosEdit{Photos: []string{"654"}, Values: BatchPhotoValues{RemoveLabels: []string{" "}}}
+
+		assert.EqualError(t, f.Validate(), "label names must not be empty")
+	})
+}
@@ -0,0 +1,10 @@
+package form
+
+// FileErrorSearch represents search form fields for "/api/v1/errors".
+type FileErrorSearch struct {
+	Stage  string `form:"stage"`
+	Root   string `form:"root"`
+	Folder string `form:"folder"`
+	Count  int    `form:"count"`
+	Offset int    `form:"offset"`
+}
@@ -1,5 +1,7 @@
 package form
 
 type ImportOptions struct {
-	Move bool `json:"move"`
+	Move    bool `json:"move"`
+	Force   bool `json:"force"`
+	Takeout bool `json:"takeout"`
 }
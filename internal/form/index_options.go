@@ -1,7 +1,10 @@
 package form
 
 type IndexOptions struct {
-	CompleteRescan bool `json:"rescan"`
-	CreateThumbs   bool `json:"thumbs"`
-	ConvertRaw     bool `json:"raw"`
+	CompleteRescan bool   `json:"rescan"`
+	CreateThumbs   bool   `json:"thumbs"`
+	ConvertRaw     bool   `json:"raw"`
+	Cleanup        bool   `json:"cleanup"`
+	Root           string `json:"root"`
+	Path           string `json:"path"`
 }
@@ -0,0 +1,7 @@
+package form
+
+// LogSearch represents search form fields for "/api/v1/logs".
+type LogSearch struct {
+	Level string `form:"level"`
+	Count int    `form:"count"`
+}
@@ -1,6 +1,7 @@
 package form
 
 type Login struct {
+	UserName string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
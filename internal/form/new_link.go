@@ -4,6 +4,12 @@ package form
 type NewLink struct {
 	Password   string `json:"password"`
 	Expires    int    `json:"expires"`
+	MaxViews   uint   `json:"max_views"`
 	CanComment bool   `json:"comment"`
 	CanEdit    bool   `json:"edit"`
 }
+
+// SharePassword represents the password form of a protected sharing link.
+type SharePassword struct {
+	Password string `json:"password"`
+}
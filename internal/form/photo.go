@@ -24,25 +24,26 @@ type Photo struct {
 		PhotoCopyright   string `json:"PhotoCopyright"`
 		PhotoLicense     string `json:"PhotoLicense"`
 	} `json:"Description"`
-	DescriptionSrc   string  `json:"DescriptionSrc"`
-	PhotoFavorite    bool    `json:"PhotoFavorite"`
-	PhotoPrivate     bool    `json:"PhotoPrivate"`
-	PhotoStory       bool    `json:"PhotoStory"`
-	PhotoReview      bool    `json:"PhotoReview"`
-	PhotoLat         float32 `json:"PhotoLat"`
-	PhotoLng         float32 `json:"PhotoLng"`
-	PhotoAltitude    int     `json:"PhotoAltitude"`
-	PhotoIso         int     `json:"PhotoIso"`
-	PhotoFocalLength int     `json:"PhotoFocalLength"`
-	PhotoFNumber     float32 `json:"PhotoFNumber"`
-	PhotoExposure    string  `json:"PhotoExposure"`
-	CameraID         uint    `json:"CameraID"`
-	CameraSrc        string  `json:"CameraSrc"`
-	LensID           uint    `json:"LensID"`
-	LocationID       string  `json:"LocationID"`
-	LocationSrc      string  `json:"LocationSrc"`
-	PlaceID          string  `json:"PlaceID"`
-	PhotoCountry     string  `json:"PhotoCountry"`
+	DescriptionSrc     string  `json:"DescriptionSrc"`
+	PhotoFavorite      bool    `json:"PhotoFavorite"`
+	PhotoPrivate       bool    `json:"PhotoPrivate"`
+	PhotoStory         bool    `json:"PhotoStory"`
+	PhotoReview        bool    `json:"PhotoReview"`
+	PhotoLat           float32 `json:"PhotoLat"`
+	PhotoLng           float32 `json:"PhotoLng"`
+	PhotoAltitude      int     `json:"PhotoAltitude"`
+	PhotoIso           int     `json:"PhotoIso"`
+	PhotoFocalLength   int     `json:"PhotoFocalLength"`
+	PhotoFocalLength35 int     `json:"PhotoFocalLength35"`
+	PhotoFNumber       float32 `json:"PhotoFNumber"`
+	PhotoExposure      string  `json:"PhotoExposure"`
+	CameraID           uint    `json:"CameraID"`
+	CameraSrc          string  `json:"CameraSrc"`
+	LensID             uint    `json:"LensID"`
+	LocationID         string  `json:"LocationID"`
+	LocationSrc        string  `json:"LocationSrc"`
+	PlaceID            string  `json:"PlaceID"`
+	PhotoCountry       string  `json:"PhotoCountry"`
 }
 
 func NewPhoto(m interface{}) (f Photo, err error) {
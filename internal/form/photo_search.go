@@ -1,6 +1,9 @@
 package form
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,19 +15,27 @@ type PhotoSearch struct {
 	Hash      string    `form:"hash"`
 	Duplicate bool      `form:"duplicate"`
 	Archived  bool      `form:"archived"`
+	Trashed   bool      `form:"trashed"`
 	Error     bool      `form:"error"`
 	Lat       float32   `form:"lat"`
 	Lng       float32   `form:"lng"`
 	Dist      uint      `form:"dist"`
+	S2        string    `form:"s2"`
+	Bbox      string    `form:"bbox"`
+	Center    string    `form:"center"`
+	Radius    float64   `form:"radius"`
 	Fmin      float32   `form:"fmin"`
 	Fmax      float32   `form:"fmax"`
 	Chroma    uint8     `form:"chroma"`
 	Diff      uint32    `form:"diff"`
 	Mono      bool      `form:"mono"`
+	Dark      bool      `form:"dark"`
 	Portrait  bool      `form:"portrait"`
 	Location  bool      `form:"location"`
 	Album     string    `form:"album"`
+	Root      string    `form:"root"`
 	Label     string    `form:"label"`
+	Person    string    `form:"person"`
 	Country   string    `form:"country"`
 	Year      uint      `form:"year"`
 	Month     uint      `form:"month"`
@@ -32,10 +43,14 @@ type PhotoSearch struct {
 	Quality   int       `form:"quality"`
 	Review    bool      `form:"review"`
 	Camera    int       `form:"camera"`
-	Lens      int       `form:"lens"`
+	Lens      string    `form:"lens"`
+	Serial    string    `form:"serial"`
+	Taken     string    `form:"taken"`
+	Mm        string    `form:"mm"`
 	Before    time.Time `form:"before" time_format:"2006-01-02"`
 	After     time.Time `form:"after" time_format:"2006-01-02"`
 	Favorites bool      `form:"favorites"`
+	Favorite  bool      `form:"favorite"`
 	Public    bool      `form:"public"`
 	Private   bool      `form:"private"`
 	Story     bool      `form:"story"`
@@ -59,6 +74,161 @@ func (f *PhotoSearch) ParseQueryString() error {
 	return ParseQueryString(f)
 }
 
+// MergeFilter parses a saved search filter into the form, for example the filter of a smart album.
+// Filter values take precedence, the search query is only kept if the filter doesn't contain one.
+func (f *PhotoSearch) MergeFilter(filter string) error {
+	q := f.Query
+	f.Query = filter
+
+	if err := f.ParseQueryString(); err != nil {
+		return err
+	}
+
+	if f.Query == "" {
+		f.Query = q
+	}
+
+	return nil
+}
+
+// TakenRange returns the inclusive date range of the taken filter, for example
+// "2019-05-01..2019-06-15". Either bound may be omitted, a single date matches the whole day.
+// Zero values are returned for missing bounds.
+func (f *PhotoSearch) TakenRange() (from, to time.Time, err error) {
+	if f.Taken == "" {
+		return from, to, nil
+	}
+
+	bounds := strings.SplitN(f.Taken, "..", 2)
+
+	if len(bounds) == 1 {
+		bounds = append(bounds, bounds[0])
+	}
+
+	if bounds[0] == "" && bounds[1] == "" {
+		return from, to, fmt.Errorf("invalid date range \"%s\", use YYYY-MM-DD..YYYY-MM-DD", f.Taken)
+	}
+
+	if bounds[0] != "" {
+		if from, err = time.Parse("2006-01-02", bounds[0]); err != nil {
+			return from, to, fmt.Errorf("invalid date \"%s\", use YYYY-MM-DD", bounds[0])
+		}
+	}
+
+	if bounds[1] != "" {
+		if to, err = time.Parse("2006-01-02", bounds[1]); err != nil {
+			return from, to, fmt.Errorf("invalid date \"%s\", use YYYY-MM-DD", bounds[1])
+		}
+	}
+
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return from, to, fmt.Errorf("invalid date range \"%s\", start is after end", f.Taken)
+	}
+
+	return from, to, nil
+}
+
+// FocalRange returns the inclusive range of the mm filter in 35mm equivalent focal length, for example
+// "24-35". Either bound may be omitted, a single value matches exactly. Zero is returned for missing bounds.
+func (f *PhotoSearch) FocalRange() (min, max int, err error) {
+	if f.Mm == "" {
+		return min, max, nil
+	}
+
+	bounds := strings.SplitN(f.Mm, "-", 2)
+
+	if len(bounds) == 1 {
+		bounds = append(bounds, bounds[0])
+	}
+
+	if bounds[0] == "" && bounds[1] == "" {
+		return min, max, fmt.Errorf("invalid focal length range \"%s\", use MIN-MAX", f.Mm)
+	}
+
+	if bounds[0] != "" {
+		if min, err = strconv.Atoi(bounds[0]); err != nil || min < 1 {
+			return 0, 0, fmt.Errorf("invalid focal length \"%s\"", bounds[0])
+		}
+	}
+
+	if bounds[1] != "" {
+		if max, err = strconv.Atoi(bounds[1]); err != nil || max < 1 {
+			return 0, 0, fmt.Errorf("invalid focal length \"%s\"", bounds[1])
+		}
+	}
+
+	if min > 0 && max > 0 && min > max {
+		return min, max, fmt.Errorf("invalid focal length range \"%s\", min is greater than max", f.Mm)
+	}
+
+	return min, max, nil
+}
+
+// Bounds returns the coordinates of the bounding box filter in degrees, for example
+// "13.0,52.3,13.8,52.7" (west, south, east, north). West is greater than east if the
+// box crosses the antimeridian.
+func (f *PhotoSearch) Bounds() (west, south, east, north float64, err error) {
+	values, err := parseFloats(f.Bbox, 4)
+
+	if err != nil {
+		return west, south, east, north, fmt.Errorf("invalid bounding box \"%s\", use west,south,east,north", f.Bbox)
+	}
+
+	west, south, east, north = values[0], values[1], values[2], values[3]
+
+	if !validLng(west) || !validLng(east) || !validLat(south) || !validLat(north) || south > north {
+		return west, south, east, north, fmt.Errorf("invalid bounding box \"%s\", coordinates out of range", f.Bbox)
+	}
+
+	return west, south, east, north, nil
+}
+
+// CenterLatLng returns the coordinates of the center filter in degrees, for example "52.5163,13.3777".
+func (f *PhotoSearch) CenterLatLng() (lat, lng float64, err error) {
+	values, err := parseFloats(f.Center, 2)
+
+	if err != nil {
+		return lat, lng, fmt.Errorf("invalid center \"%s\", use lat,lng", f.Center)
+	}
+
+	lat, lng = values[0], values[1]
+
+	if !validLat(lat) || !validLng(lng) {
+		return lat, lng, fmt.Errorf("invalid center \"%s\", coordinates out of range", f.Center)
+	}
+
+	return lat, lng, nil
+}
+
+// parseFloats parses a comma separated list with the expected number of float values.
+func parseFloats(s string, n int) (result []float64, err error) {
+	values := strings.Split(s, ",")
+
+	if len(values) != n {
+		return result, fmt.Errorf("expected %d values", n)
+	}
+
+	for _, v := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+func validLat(lat float64) bool {
+	return lat >= -90 && lat <= 90
+}
+
+func validLng(lng float64) bool {
+	return lng >= -180 && lng <= 180
+}
+
 func NewPhotoSearch(query string) PhotoSearch {
 	return PhotoSearch{Query: query}
 }
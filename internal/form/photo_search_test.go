@@ -53,6 +53,17 @@ func TestParseQueryString(t *testing.T) {
 		assert.Equal(t, false, form.Duplicate)
 		assert.Equal(t, float32(33.45343), form.Lng)
 	})
+	t.Run("color filters", func(t *testing.T) {
+		form := &PhotoSearch{Query: "color:red mono:true dark:true"}
+
+		if err := form.ParseQueryString(); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "red", form.Color)
+		assert.True(t, form.Mono)
+		assert.True(t, form.Dark)
+	})
 	t.Run("valid query with umlauts", func(t *testing.T) {
 		form := &PhotoSearch{Query: "title:\"tübingen\""}
 
@@ -143,3 +154,218 @@ func TestParseQueryString(t *testing.T) {
 		assert.Equal(t, "Could not find format for \"cat\"", err.Error())
 	})
 }
+
+func TestPhotoSearch_TakenRange(t *testing.T) {
+	t.Run("range", func(t *testing.T) {
+		form := &PhotoSearch{Query: "taken:2019-05-01..2019-06-15 serial:\"C39PQ*\" lens:\"EF 35mm\""}
+
+		if err := form.ParseQueryString(); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "c39pq*", form.Serial)
+		assert.Equal(t, "ef 35mm", form.Lens)
+
+		from, to, err := form.TakenRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC), to)
+	})
+	t.Run("single day", func(t *testing.T) {
+		from, to, err := (&PhotoSearch{Taken: "2019-05-01"}).TakenRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, from, to)
+		assert.Equal(t, time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), from)
+	})
+	t.Run("open end", func(t *testing.T) {
+		from, to, err := (&PhotoSearch{Taken: "2019-05-01.."}).TakenRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.True(t, to.IsZero())
+	})
+	t.Run("open start", func(t *testing.T) {
+		from, to, err := (&PhotoSearch{Taken: "..2019-06-15"}).TakenRange()
+
+		assert.Nil(t, err)
+		assert.True(t, from.IsZero())
+		assert.Equal(t, time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC), to)
+	})
+	t.Run("empty", func(t *testing.T) {
+		from, to, err := (&PhotoSearch{}).TakenRange()
+
+		assert.Nil(t, err)
+		assert.True(t, from.IsZero())
+		assert.True(t, to.IsZero())
+	})
+	t.Run("invalid date", func(t *testing.T) {
+		_, _, err := (&PhotoSearch{Taken: "2019-13-01.."}).TakenRange()
+
+		assert.EqualError(t, err, "invalid date \"2019-13-01\", use YYYY-MM-DD")
+	})
+	t.Run("no bounds", func(t *testing.T) {
+		_, _, err := (&PhotoSearch{Taken: ".."}).TakenRange()
+
+		assert.EqualError(t, err, "invalid date range \"..\", use YYYY-MM-DD..YYYY-MM-DD")
+	})
+	t.Run("start after end", func(t *testing.T) {
+		_, _, err := (&PhotoSearch{Taken: "2019-06-15..2019-05-01"}).TakenRange()
+
+		assert.EqualError(t, err, "invalid date range \"2019-06-15..2019-05-01\", start is after end")
+	})
+}
+
+func TestPhotoSearch_Person(t *testing.T) {
+	form := &PhotoSearch{Query: "person:\"Jane Doe\""}
+
+	if err := form.ParseQueryString(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "jane doe", form.Person)
+	assert.Equal(t, "", form.Query)
+}
+
+func TestPhotoSearch_MergeFilter(t *testing.T) {
+	t.Run("filter values", func(t *testing.T) {
+		form := &PhotoSearch{Query: "lens:canon", Count: 10}
+
+		if err := form.ParseQueryString(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := form.MergeFilter("favorite:true country:de"); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, form.Favorite)
+		assert.Equal(t, "de", form.Country)
+		assert.Equal(t, "canon", form.Lens)
+		assert.Equal(t, 10, form.Count)
+	})
+	t.Run("query", func(t *testing.T) {
+		form := &PhotoSearch{Query: "cat"}
+
+		if err := form.MergeFilter("favorite:true"); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "cat", form.Query)
+
+		if err := form.MergeFilter("dog"); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "dog", form.Query)
+	})
+	t.Run("invalid filter", func(t *testing.T) {
+		form := &PhotoSearch{}
+
+		assert.Error(t, form.MergeFilter("xxx:bla"))
+	})
+}
+
+func TestPhotoSearch_FocalRange(t *testing.T) {
+	t.Run("range", func(t *testing.T) {
+		form := &PhotoSearch{Query: "mm:24-35"}
+
+		if err := form.ParseQueryString(); err != nil {
+			t.Fatal(err)
+		}
+
+		min, max, err := form.FocalRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 24, min)
+		assert.Equal(t, 35, max)
+	})
+	t.Run("single value", func(t *testing.T) {
+		min, max, err := (&PhotoSearch{Mm: "50"}).FocalRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 50, min)
+		assert.Equal(t, 50, max)
+	})
+	t.Run("open end", func(t *testing.T) {
+		min, max, err := (&PhotoSearch{Mm: "200-"}).FocalRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 200, min)
+		assert.Equal(t, 0, max)
+	})
+	t.Run("open start", func(t *testing.T) {
+		min, max, err := (&PhotoSearch{Mm: "-28"}).FocalRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, min)
+		assert.Equal(t, 28, max)
+	})
+	t.Run("empty", func(t *testing.T) {
+		min, max, err := (&PhotoSearch{}).FocalRange()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, min)
+		assert.Equal(t, 0, max)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := (&PhotoSearch{Mm: "wide"}).FocalRange()
+
+		assert.EqualError(t, err, "invalid focal length \"wide\"")
+	})
+	t.Run("min greater than max", func(t *testing.T) {
+		_, _, err := (&PhotoSearch{Mm: "85-35"}).FocalRange()
+
+		assert.EqualError(t, err, "invalid focal length range \"85-35\", min is greater than max")
+	})
+}
+
+func TestPhotoSearch_Bounds(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		form := &PhotoSearch{Query: "bbox:13.0,52.3,13.8,52.7"}
+
+		if err := form.ParseQueryString(); err != nil {
+			t.Fatal(err)
+		}
+
+		west, south, east, north, err := form.Bounds()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 13.0, west)
+		assert.Equal(t, 52.3, south)
+		assert.Equal(t, 13.8, east)
+		assert.Equal(t, 52.7, north)
+	})
+	t.Run("antimeridian", func(t *testing.T) {
+		west, _, east, _, err := (&PhotoSearch{Bbox: "170, -20, -170, -10"}).Bounds()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 170.0, west)
+		assert.Equal(t, -170.0, east)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, _, _, err := (&PhotoSearch{Bbox: "13.0,52.3,13.8"}).Bounds()
+		assert.EqualError(t, err, "invalid bounding box \"13.0,52.3,13.8\", use west,south,east,north")
+
+		_, _, _, _, err = (&PhotoSearch{Bbox: "13.0,52.3,190,52.7"}).Bounds()
+		assert.EqualError(t, err, "invalid bounding box \"13.0,52.3,190,52.7\", coordinates out of range")
+	})
+}
+
+func TestPhotoSearch_CenterLatLng(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		lat, lng, err := (&PhotoSearch{Center: "52.5163,13.3777"}).CenterLatLng()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 52.5163, lat)
+		assert.Equal(t, 13.3777, lng)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := (&PhotoSearch{Center: "north"}).CenterLatLng()
+		assert.EqualError(t, err, "invalid center \"north\", use lat,lng")
+
+		_, _, err = (&PhotoSearch{Center: "91,13.3777"}).CenterLatLng()
+		assert.EqualError(t, err, "invalid center \"91,13.3777\", coordinates out of range")
+	})
+}
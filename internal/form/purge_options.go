@@ -0,0 +1,10 @@
+package form
+
+// PurgeOptions represents purge form fields for "/api/v1/purge".
+type PurgeOptions struct {
+	Root   string `json:"root"`
+	Path   string `json:"path"`
+	Hard   bool   `json:"hard"`
+	DryRun bool   `json:"dryRun"`
+	Force  bool   `json:"force"`
+}
@@ -0,0 +1,8 @@
+package form
+
+// UploadOptions describes a file that is uploaded in chunks.
+type UploadOptions struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
@@ -0,0 +1,9 @@
+package maps
+
+// Cache persistently stores reverse geocoding results by S2 cell id and api, so that the
+// same location isn't requested again after a restart. Locations that could not be found
+// are stored as unknown location without id.
+type Cache interface {
+	Load(id, api string) (result *Location, ok bool)
+	Save(id, api string, result *Location)
+}
@@ -0,0 +1,108 @@
+/*
+This package provides the HTTP client shared by the geocoding APIs, so that proxy, timeout
+and user agent settings are applied consistently.
+
+Additional information can be found in our Developer Guide:
+
+https://github.com/photoprism/photoprism/wiki
+*/
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the default timeout of outbound requests.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultUserAgent is used if no other user agent was configured.
+const DefaultUserAgent = "PhotoPrism"
+
+var (
+	client    = newClient(nil, DefaultTimeout)
+	userAgent = DefaultUserAgent
+	mutex     sync.RWMutex
+)
+
+// newClient returns a new client using the proxy, or the proxy environment variables if proxy is nil.
+func newClient(proxy *url.URL, timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// Configure replaces the shared client. An empty proxy means the HTTP_PROXY, HTTPS_PROXY
+// and NO_PROXY environment variables are used.
+func Configure(proxy string, timeout time.Duration, agent string) error {
+	var proxyUrl *url.URL
+
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("httpclient: invalid proxy url %s", proxy)
+		}
+
+		proxyUrl = u
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	if agent == "" {
+		agent = DefaultUserAgent
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	client = newClient(proxyUrl, timeout)
+	userAgent = agent
+
+	return nil
+}
+
+// Client returns the shared client.
+func Client() *http.Client {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return client
+}
+
+// UserAgent returns the user agent sent with requests.
+func UserAgent() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return userAgent
+}
+
+// Get sends a GET request using the shared client.
+func Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Do(req)
+}
+
+// Do sends a request using the shared client and sets the user agent.
+func Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", UserAgent())
+
+	return Client().Do(req)
+}
@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	defer Configure("", DefaultTimeout, DefaultUserAgent)
+
+	t.Run("proxy", func(t *testing.T) {
+		if err := Configure("http://proxy.example.com:3128", 5*time.Second, "PhotoPrism/test"); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 5*time.Second, Client().Timeout)
+		assert.Equal(t, "PhotoPrism/test", UserAgent())
+
+		req, _ := http.NewRequest(http.MethodGet, "https://nominatim.openstreetmap.org/reverse", nil)
+		proxy, err := Client().Transport.(*http.Transport).Proxy(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "http://proxy.example.com:3128", proxy.String())
+	})
+	t.Run("environment", func(t *testing.T) {
+		if err := Configure("", 0, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, DefaultTimeout, Client().Timeout)
+		assert.Equal(t, DefaultUserAgent, UserAgent())
+
+		transport := Client().Transport.(*http.Transport)
+
+		// Proxy environment variables are used if no proxy was configured.
+		assert.NotNil(t, transport.Proxy)
+		assert.NotSame(t, http.DefaultTransport, transport)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert.EqualError(t, Configure("proxy:3128", 0, ""), "httpclient: invalid proxy url proxy:3128")
+	})
+}
+
+func TestGet(t *testing.T) {
+	defer Configure("", DefaultTimeout, DefaultUserAgent)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.UserAgent())
+	}))
+
+	defer srv.Close()
+
+	if err := Configure("", time.Second, "PhotoPrism/200501-Test"); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Get(srv.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "PhotoPrism/200501-Test", string(body))
+}
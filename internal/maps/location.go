@@ -2,8 +2,11 @@ package maps
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
 
+	"github.com/photoprism/photoprism/internal/maps/offline"
 	"github.com/photoprism/photoprism/internal/maps/osm"
 	"github.com/photoprism/photoprism/internal/maps/places"
 )
@@ -59,15 +62,75 @@ func NewLocation(id string, name string, category string, label string, city str
 	return result
 }
 
-func (l *Location) QueryApi(api string) error {
+// ErrUnknownLocation is returned if the location could not be resolved.
+var ErrUnknownLocation = errors.New("maps: unknown location")
+
+// QueryApi resolves the location using the given api (osm, places or offline). The offline
+// geocoder is used as fallback if the api can't be reached.
+func (l *Location) QueryApi(api string) (err error) {
 	switch api {
 	case "osm":
-		return l.QueryOSM()
+		err = l.QueryOSM()
 	case "places":
-		return l.QueryPlaces()
+		err = l.QueryPlaces()
+	case "offline":
+		return l.QueryOffline()
+	default:
+		return errors.New("maps: reverse lookup disabled")
+	}
+
+	if err != nil && Unreachable(err) {
+		log.Warnf("maps: %s, using offline geocoder", err)
+
+		if offlineErr := l.QueryOffline(); offlineErr == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// QueryCached works like QueryApi, but first checks if there is a cached result. Results of the
+// api are added to the cache, including locations that could not be found.
+func (l *Location) QueryCached(api string, cache Cache) error {
+	id := l.ID
+
+	if cached, ok := cache.Load(id, api); ok {
+		if cached.Unknown() {
+			return fmt.Errorf("%w for %s (cached)", ErrUnknownLocation, id)
+		}
+
+		*l = *cached
+
+		return nil
+	}
+
+	err := l.QueryApi(api)
+
+	switch {
+	case err == nil && l.LocSource == api:
+		cache.Save(id, api, l)
+	case err != nil && NotFound(err):
+		cache.Save(id, api, &Location{})
 	}
 
-	return errors.New("maps: reverse lookup disabled")
+	return err
+}
+
+// Unreachable returns true if the error indicates that the api could not be reached.
+func Unreachable(err error) bool {
+	var urlErr *url.Error
+
+	return errors.As(err, &urlErr) || errors.Is(err, places.ErrRequestFailed)
+}
+
+// NotFound returns true if the error indicates that a location doesn't exist, as opposed to
+// connection problems.
+func NotFound(err error) bool {
+	return errors.Is(err, ErrUnknownLocation) ||
+		errors.Is(err, osm.ErrNoResult) ||
+		errors.Is(err, places.ErrNoResult) ||
+		errors.Is(err, offline.ErrNoResult)
 }
 
 func (l *Location) QueryPlaces() error {
@@ -88,6 +151,16 @@ func (l *Location) QueryPlaces() error {
 	return nil
 }
 
+func (l *Location) QueryOffline() error {
+	s, err := offline.FindLocation(l.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return l.Assign(s)
+}
+
 func (l *Location) QueryOSM() error {
 	s, err := osm.FindLocation(l.ID)
 
@@ -105,7 +178,7 @@ func (l *Location) Assign(s LocationSource) error {
 
 	if l.Unknown() {
 		l.LocCategory = "unknown"
-		return errors.New("maps: unknown location")
+		return ErrUnknownLocation
 	}
 
 	l.LocName = s.Name()
@@ -1,7 +1,13 @@
 package maps
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/photoprism/photoprism/internal/maps/places"
@@ -291,3 +297,116 @@ func TestLocation_QueryApi(t *testing.T) {
 	})
 
 }
+
+// testCache implements Cache for testing.
+type testCache map[string]Location
+
+func (c testCache) Load(id, api string) (*Location, bool) {
+	l, ok := c[api+":"+id]
+	return &l, ok
+}
+
+func (c testCache) Save(id, api string, l *Location) {
+	c[api+":"+id] = *l
+}
+
+func TestLocation_QueryCached(t *testing.T) {
+	var requests int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch id := path.Base(r.URL.Path); id {
+		case "47a85a61":
+			_, _ = fmt.Fprint(w, `{}`)
+		case "47a85a63":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = fmt.Fprintf(w, `{"id": %q, "name": "Stub", "category": "test", "place": {"id": "de:stub", "label": "Berlin, Germany", "city": "Berlin", "state": "Berlin", "country": "de"}}`, id)
+		}
+	}))
+
+	defer srv.Close()
+
+	defaultURL := places.ReverseLookupURL
+	places.ReverseLookupURL = srv.URL + "/v1/location/%s"
+	defer func() { places.ReverseLookupURL = defaultURL }()
+
+	cache := testCache{}
+
+	t.Run("cache hit", func(t *testing.T) {
+		cache["places:47a85a65"] = *NewLocation("47a85a65", "Cached", "", "Munich, Germany", "Munich", "Bavaria", "de", "places")
+
+		l := NewLocation("47a85a65", "", "", "", "", "", "", "")
+
+		if err := l.QueryCached("places", cache); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Cached", l.LocName)
+		assert.Equal(t, "Munich", l.LocCity)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	})
+	t.Run("cache miss", func(t *testing.T) {
+		l := NewLocation("47a85a67", "", "", "", "", "", "", "")
+
+		if err := l.QueryCached("places", cache); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Berlin", l.LocCity)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+		assert.Equal(t, "Berlin", cache["places:47a85a67"].LocCity)
+
+		// Second lookup is served from the cache.
+		l = NewLocation("47a85a67", "", "", "", "", "", "", "")
+
+		if err := l.QueryCached("places", cache); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+	t.Run("not found", func(t *testing.T) {
+		l := NewLocation("47a85a61", "", "", "", "", "", "", "")
+
+		err := l.QueryCached("places", cache)
+
+		assert.True(t, NotFound(err))
+		cached := cache["places:47a85a61"]
+		assert.True(t, cached.Unknown())
+
+		n := atomic.LoadInt32(&requests)
+
+		err = l.QueryCached("places", cache)
+
+		assert.True(t, errors.Is(err, ErrUnknownLocation))
+		assert.Equal(t, n, atomic.LoadInt32(&requests))
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		l := NewLocation("47a85a63", "", "", "", "", "", "", "")
+
+		if err := l.QueryCached("places", cache); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "offline", l.LocSource)
+		assert.Equal(t, "de", l.LocCountry)
+
+		// Offline results are not cached for other apis.
+		_, ok := cache["places:47a85a63"]
+		assert.False(t, ok)
+	})
+}
+
+func TestLocation_QueryOffline(t *testing.T) {
+	l := NewLocation(s2.Token(48.1374, 11.5755), "", "", "", "", "", "", "")
+
+	if err := l.QueryApi("offline"); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Munich", l.LocCity)
+	assert.Equal(t, "Munich, Bavaria, Germany", l.LocLabel)
+	assert.Equal(t, "offline", l.LocSource)
+}
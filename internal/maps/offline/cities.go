@@ -0,0 +1,499 @@
+// Code generated by go generate; DO NOT EDIT.
+package offline
+
+var Cities = []City{
+	{"Berlin", "Berlin", "de", 52.52, 13.405},
+	{"Hamburg", "Hamburg", "de", 53.5511, 9.9937},
+	{"Munich", "Bavaria", "de", 48.1351, 11.582},
+	{"Cologne", "North Rhine-Westphalia", "de", 50.9375, 6.9603},
+	{"Frankfurt am Main", "Hesse", "de", 50.1109, 8.6821},
+	{"Stuttgart", "Baden-Württemberg", "de", 48.7758, 9.1829},
+	{"Düsseldorf", "North Rhine-Westphalia", "de", 51.2277, 6.7735},
+	{"Leipzig", "Saxony", "de", 51.3397, 12.3731},
+	{"Dresden", "Saxony", "de", 51.0504, 13.7373},
+	{"Hanover", "Lower Saxony", "de", 52.3759, 9.732},
+	{"Nuremberg", "Bavaria", "de", 49.4521, 11.0767},
+	{"Bremen", "Bremen", "de", 53.0793, 8.8017},
+	{"Freiburg im Breisgau", "Baden-Württemberg", "de", 47.999, 7.8421},
+	{"Rostock", "Mecklenburg-Vorpommern", "de", 54.0924, 12.0991},
+	{"Kiel", "Schleswig-Holstein", "de", 54.3233, 10.1228},
+	{"Vienna", "Vienna", "at", 48.2082, 16.3738},
+	{"Graz", "Styria", "at", 47.0707, 15.4395},
+	{"Salzburg", "Salzburg", "at", 47.8095, 13.055},
+	{"Innsbruck", "Tyrol", "at", 47.2692, 11.4041},
+	{"Zurich", "Zurich", "ch", 47.3769, 8.5417},
+	{"Geneva", "Geneva", "ch", 46.2044, 6.1432},
+	{"Bern", "Bern", "ch", 46.948, 7.4474},
+	{"Basel", "Basel-City", "ch", 47.5596, 7.5886},
+	{"Lugano", "Ticino", "ch", 46.0037, 8.9511},
+	{"Paris", "Île-de-France", "fr", 48.8566, 2.3522},
+	{"Marseille", "Provence-Alpes-Côte d'Azur", "fr", 43.2965, 5.3698},
+	{"Lyon", "Auvergne-Rhône-Alpes", "fr", 45.764, 4.8357},
+	{"Toulouse", "Occitanie", "fr", 43.6047, 1.4442},
+	{"Nice", "Provence-Alpes-Côte d'Azur", "fr", 43.7102, 7.262},
+	{"Nantes", "Pays de la Loire", "fr", 47.2184, -1.5536},
+	{"Strasbourg", "Grand Est", "fr", 48.5734, 7.7521},
+	{"Bordeaux", "Nouvelle-Aquitaine", "fr", 44.8378, -0.5792},
+	{"Lille", "Hauts-de-France", "fr", 50.6292, 3.0573},
+	{"Rennes", "Brittany", "fr", 48.1173, -1.6778},
+	{"Brest", "Brittany", "fr", 48.3904, -4.4861},
+	{"Ajaccio", "Corsica", "fr", 41.9192, 8.7386},
+	{"Brussels", "Brussels", "be", 50.8503, 4.3517},
+	{"Antwerp", "Flanders", "be", 51.2194, 4.4025},
+	{"Liège", "Wallonia", "be", 50.6326, 5.5797},
+	{"Amsterdam", "North Holland", "nl", 52.3676, 4.9041},
+	{"Rotterdam", "South Holland", "nl", 51.9244, 4.4777},
+	{"Groningen", "Groningen", "nl", 53.2194, 6.5665},
+	{"Eindhoven", "North Brabant", "nl", 51.4416, 5.4697},
+	{"Luxembourg", "Luxembourg", "lu", 49.6116, 6.1319},
+	{"London", "England", "gb", 51.5074, -0.1278},
+	{"Birmingham", "England", "gb", 52.4862, -1.8904},
+	{"Manchester", "England", "gb", 53.4808, -2.2426},
+	{"Newcastle upon Tyne", "England", "gb", 54.9783, -1.6178},
+	{"Plymouth", "England", "gb", 50.3755, -4.1427},
+	{"Edinburgh", "Scotland", "gb", 55.9533, -3.1883},
+	{"Glasgow", "Scotland", "gb", 55.8642, -4.2518},
+	{"Aberdeen", "Scotland", "gb", 57.1497, -2.0943},
+	{"Inverness", "Scotland", "gb", 57.4778, -4.2247},
+	{"Cardiff", "Wales", "gb", 51.4816, -3.1791},
+	{"Belfast", "Northern Ireland", "gb", 54.5973, -5.9301},
+	{"Dublin", "Leinster", "ie", 53.3498, -6.2603},
+	{"Cork", "Munster", "ie", 51.8985, -8.4756},
+	{"Galway", "Connacht", "ie", 53.2707, -9.0568},
+	{"Reykjavík", "Capital Region", "is", 64.1466, -21.9426},
+	{"Akureyri", "Northeastern Region", "is", 65.6885, -18.1262},
+	{"Oslo", "Oslo", "no", 59.9139, 10.7522},
+	{"Bergen", "Vestland", "no", 60.3913, 5.3221},
+	{"Trondheim", "Trøndelag", "no", 63.4305, 10.3951},
+	{"Tromsø", "Troms", "no", 69.6492, 18.9553},
+	{"Stockholm", "Stockholm", "se", 59.3293, 18.0686},
+	{"Gothenburg", "Västra Götaland", "se", 57.7089, 11.9746},
+	{"Malmö", "Skåne", "se", 55.605, 13.0038},
+	{"Umeå", "Västerbotten", "se", 63.8258, 20.263},
+	{"Kiruna", "Norrbotten", "se", 67.8558, 20.2253},
+	{"Copenhagen", "Capital Region", "dk", 55.6761, 12.5683},
+	{"Aarhus", "Central Denmark", "dk", 56.1629, 10.2039},
+	{"Helsinki", "Uusimaa", "fi", 60.1699, 24.9384},
+	{"Tampere", "Pirkanmaa", "fi", 61.4978, 23.761},
+	{"Oulu", "North Ostrobothnia", "fi", 65.0121, 25.4651},
+	{"Rovaniemi", "Lapland", "fi", 66.5039, 25.7294},
+	{"Tallinn", "Harju", "ee", 59.437, 24.7536},
+	{"Riga", "Riga", "lv", 56.9496, 24.1052},
+	{"Vilnius", "Vilnius", "lt", 54.6872, 25.2797},
+	{"Warsaw", "Masovia", "pl", 52.2297, 21.0122},
+	{"Kraków", "Lesser Poland", "pl", 50.0647, 19.945},
+	{"Gdańsk", "Pomerania", "pl", 54.352, 18.6466},
+	{"Wrocław", "Lower Silesia", "pl", 51.1079, 17.0385},
+	{"Poznań", "Greater Poland", "pl", 52.4064, 16.9252},
+	{"Prague", "Prague", "cz", 50.0755, 14.4378},
+	{"Brno", "South Moravia", "cz", 49.1951, 16.6068},
+	{"Bratislava", "Bratislava", "sk", 48.1486, 17.1077},
+	{"Košice", "Košice", "sk", 48.7164, 21.2611},
+	{"Budapest", "Budapest", "hu", 47.4979, 19.0402},
+	{"Debrecen", "Hajdú-Bihar", "hu", 47.5316, 21.6273},
+	{"Ljubljana", "Ljubljana", "si", 46.0569, 14.5058},
+	{"Zagreb", "Zagreb", "hr", 45.815, 15.9819},
+	{"Split", "Split-Dalmatia", "hr", 43.5081, 16.4402},
+	{"Dubrovnik", "Dubrovnik-Neretva", "hr", 42.6507, 18.0944},
+	{"Sarajevo", "Sarajevo", "ba", 43.8563, 18.4131},
+	{"Belgrade", "Belgrade", "rs", 44.7866, 20.4489},
+	{"Podgorica", "Podgorica", "me", 42.4304, 19.2594},
+	{"Tirana", "Tirana", "al", 41.3275, 19.8187},
+	{"Skopje", "Skopje", "mk", 41.9981, 21.4254},
+	{"Sofia", "Sofia", "bg", 42.6977, 23.3219},
+	{"Varna", "Varna", "bg", 43.2141, 27.9147},
+	{"Bucharest", "Bucharest", "ro", 44.4268, 26.1025},
+	{"Cluj-Napoca", "Cluj", "ro", 46.7712, 23.6236},
+	{"Chișinău", "Chișinău", "md", 47.0105, 28.8638},
+	{"Kyiv", "Kyiv", "ua", 50.4501, 30.5234},
+	{"Lviv", "Lviv", "ua", 49.8397, 24.0297},
+	{"Odesa", "Odesa", "ua", 46.4825, 30.7233},
+	{"Kharkiv", "Kharkiv", "ua", 49.9935, 36.2304},
+	{"Minsk", "Minsk", "by", 53.9006, 27.559},
+	{"Moscow", "Moscow", "ru", 55.7558, 37.6173},
+	{"Saint Petersburg", "Saint Petersburg", "ru", 59.9311, 30.3609},
+	{"Kazan", "Tatarstan", "ru", 55.7963, 49.1088},
+	{"Yekaterinburg", "Sverdlovsk", "ru", 56.8389, 60.6057},
+	{"Novosibirsk", "Novosibirsk", "ru", 55.0084, 82.9357},
+	{"Krasnoyarsk", "Krasnoyarsk", "ru", 56.0153, 92.8932},
+	{"Irkutsk", "Irkutsk", "ru", 52.287, 104.305},
+	{"Yakutsk", "Sakha", "ru", 62.0355, 129.6755},
+	{"Vladivostok", "Primorsky", "ru", 43.1198, 131.8869},
+	{"Murmansk", "Murmansk", "ru", 68.9585, 33.0827},
+	{"Rome", "Lazio", "it", 41.9028, 12.4964},
+	{"Milan", "Lombardy", "it", 45.4642, 9.19},
+	{"Naples", "Campania", "it", 40.8518, 14.2681},
+	{"Turin", "Piedmont", "it", 45.0703, 7.6869},
+	{"Venice", "Veneto", "it", 45.4408, 12.3155},
+	{"Florence", "Tuscany", "it", 43.7696, 11.2558},
+	{"Bologna", "Emilia-Romagna", "it", 44.4949, 11.3426},
+	{"Bari", "Apulia", "it", 41.1171, 16.8719},
+	{"Palermo", "Sicily", "it", 38.1157, 13.3615},
+	{"Catania", "Sicily", "it", 37.5079, 15.083},
+	{"Cagliari", "Sardinia", "it", 39.2238, 9.1217},
+	{"Bolzano", "Trentino-South Tyrol", "it", 46.4983, 11.3548},
+	{"Valletta", "Malta", "mt", 35.8989, 14.5146},
+	{"Madrid", "Community of Madrid", "es", 40.4168, -3.7038},
+	{"Barcelona", "Catalonia", "es", 41.3851, 2.1734},
+	{"Valencia", "Valencian Community", "es", 39.4699, -0.3763},
+	{"Seville", "Andalusia", "es", 37.3891, -5.9845},
+	{"Málaga", "Andalusia", "es", 36.7213, -4.4214},
+	{"Bilbao", "Basque Country", "es", 43.263, -2.935},
+	{"Zaragoza", "Aragon", "es", 41.6488, -0.8891},
+	{"A Coruña", "Galicia", "es", 43.3623, -8.4115},
+	{"Palma", "Balearic Islands", "es", 39.5696, 2.6502},
+	{"Las Palmas de Gran Canaria", "Canary Islands", "es", 28.1235, -15.4363},
+	{"Santa Cruz de Tenerife", "Canary Islands", "es", 28.4636, -16.2518},
+	{"Lisbon", "Lisbon", "pt", 38.7223, -9.1393},
+	{"Porto", "Porto", "pt", 41.1579, -8.6291},
+	{"Faro", "Faro", "pt", 37.0194, -7.9322},
+	{"Funchal", "Madeira", "pt", 32.6669, -16.9241},
+	{"Ponta Delgada", "Azores", "pt", 37.7412, -25.6756},
+	{"Athens", "Attica", "gr", 37.9838, 23.7275},
+	{"Thessaloniki", "Central Macedonia", "gr", 40.6401, 22.9444},
+	{"Heraklion", "Crete", "gr", 35.3387, 25.1442},
+	{"Rhodes", "South Aegean", "gr", 36.4341, 28.2176},
+	{"Nicosia", "Nicosia", "cy", 35.1856, 33.3823},
+	{"Istanbul", "Istanbul", "tr", 41.0082, 28.9784},
+	{"Ankara", "Ankara", "tr", 39.9334, 32.8597},
+	{"Izmir", "Izmir", "tr", 38.4237, 27.1428},
+	{"Antalya", "Antalya", "tr", 36.8969, 30.7133},
+	{"Erzurum", "Erzurum", "tr", 39.9043, 41.2679},
+	{"Tbilisi", "Tbilisi", "ge", 41.7151, 44.8271},
+	{"Yerevan", "Yerevan", "am", 40.1792, 44.4991},
+	{"Baku", "Baku", "az", 40.4093, 49.8671},
+	{"Tel Aviv", "Tel Aviv", "il", 32.0853, 34.7818},
+	{"Jerusalem", "Jerusalem", "il", 31.7683, 35.2137},
+	{"Beirut", "Beirut", "lb", 33.8938, 35.5018},
+	{"Amman", "Amman", "jo", 31.9454, 35.9284},
+	{"Damascus", "Damascus", "sy", 33.5138, 36.2765},
+	{"Baghdad", "Baghdad", "iq", 33.3152, 44.3661},
+	{"Riyadh", "Riyadh", "sa", 24.7136, 46.6753},
+	{"Jeddah", "Makkah", "sa", 21.4858, 39.1925},
+	{"Dubai", "Dubai", "ae", 25.2048, 55.2708},
+	{"Abu Dhabi", "Abu Dhabi", "ae", 24.4539, 54.3773},
+	{"Doha", "Doha", "qa", 25.2854, 51.531},
+	{"Muscat", "Muscat", "om", 23.588, 58.3829},
+	{"Sana'a", "Sana'a", "ye", 15.3694, 44.191},
+	{"Tehran", "Tehran", "ir", 35.6892, 51.389},
+	{"Isfahan", "Isfahan", "ir", 32.6546, 51.668},
+	{"Kabul", "Kabul", "af", 34.5553, 69.2075},
+	{"Karachi", "Sindh", "pk", 24.8607, 67.0011},
+	{"Lahore", "Punjab", "pk", 31.5204, 74.3587},
+	{"Islamabad", "Islamabad", "pk", 33.6844, 73.0479},
+	{"Tashkent", "Tashkent", "uz", 41.2995, 69.2401},
+	{"Almaty", "Almaty", "kz", 43.222, 76.8512},
+	{"Astana", "Astana", "kz", 51.1694, 71.4491},
+	{"Bishkek", "Chuy", "kg", 42.8746, 74.5698},
+	{"Ulaanbaatar", "Ulaanbaatar", "mn", 47.8864, 106.9057},
+	{"New Delhi", "Delhi", "in", 28.6139, 77.209},
+	{"Mumbai", "Maharashtra", "in", 19.076, 72.8777},
+	{"Bengaluru", "Karnataka", "in", 12.9716, 77.5946},
+	{"Chennai", "Tamil Nadu", "in", 13.0827, 80.2707},
+	{"Kolkata", "West Bengal", "in", 22.5726, 88.3639},
+	{"Hyderabad", "Telangana", "in", 17.385, 78.4867},
+	{"Jaipur", "Rajasthan", "in", 26.9124, 75.7873},
+	{"Goa", "Goa", "in", 15.4909, 73.8278},
+	{"Kathmandu", "Bagmati", "np", 27.7172, 85.324},
+	{"Thimphu", "Thimphu", "bt", 27.4728, 89.639},
+	{"Dhaka", "Dhaka", "bd", 23.8103, 90.4125},
+	{"Colombo", "Western", "lk", 6.9271, 79.8612},
+	{"Malé", "Malé", "mv", 4.1755, 73.5093},
+	{"Yangon", "Yangon", "mm", 16.8409, 96.1735},
+	{"Bangkok", "Bangkok", "th", 13.7563, 100.5018},
+	{"Chiang Mai", "Chiang Mai", "th", 18.7883, 98.9853},
+	{"Phuket", "Phuket", "th", 7.8804, 98.3923},
+	{"Vientiane", "Vientiane", "la", 17.9757, 102.6331},
+	{"Phnom Penh", "Phnom Penh", "kh", 11.5564, 104.9282},
+	{"Hanoi", "Hanoi", "vn", 21.0278, 105.8342},
+	{"Ho Chi Minh City", "Ho Chi Minh City", "vn", 10.8231, 106.6297},
+	{"Da Nang", "Da Nang", "vn", 16.0544, 108.2022},
+	{"Kuala Lumpur", "Kuala Lumpur", "my", 3.139, 101.6869},
+	{"Kota Kinabalu", "Sabah", "my", 5.9804, 116.0735},
+	{"Singapore", "Singapore", "sg", 1.3521, 103.8198},
+	{"Jakarta", "Jakarta", "id", -6.2088, 106.8456},
+	{"Surabaya", "East Java", "id", -7.2575, 112.7521},
+	{"Denpasar", "Bali", "id", -8.6705, 115.2126},
+	{"Medan", "North Sumatra", "id", 3.5952, 98.6722},
+	{"Makassar", "South Sulawesi", "id", -5.1477, 119.4327},
+	{"Jayapura", "Papua", "id", -2.5337, 140.7181},
+	{"Manila", "Metro Manila", "ph", 14.5995, 120.9842},
+	{"Cebu City", "Central Visayas", "ph", 10.3157, 123.8854},
+	{"Davao City", "Davao", "ph", 7.1907, 125.4553},
+	{"Beijing", "Beijing", "cn", 39.9042, 116.4074},
+	{"Shanghai", "Shanghai", "cn", 31.2304, 121.4737},
+	{"Guangzhou", "Guangdong", "cn", 23.1291, 113.2644},
+	{"Shenzhen", "Guangdong", "cn", 22.5431, 114.0579},
+	{"Chengdu", "Sichuan", "cn", 30.5728, 104.0668},
+	{"Xi'an", "Shaanxi", "cn", 34.3416, 108.9398},
+	{"Wuhan", "Hubei", "cn", 30.5928, 114.3055},
+	{"Kunming", "Yunnan", "cn", 25.0389, 102.7183},
+	{"Harbin", "Heilongjiang", "cn", 45.8038, 126.5349},
+	{"Ürümqi", "Xinjiang", "cn", 43.8256, 87.6168},
+	{"Lhasa", "Tibet", "cn", 29.65, 91.1},
+	{"Hong Kong", "Hong Kong", "hk", 22.3193, 114.1694},
+	{"Macau", "Macau", "mo", 22.1987, 113.5439},
+	{"Taipei", "Taipei", "tw", 25.033, 121.5654},
+	{"Kaohsiung", "Kaohsiung", "tw", 22.6273, 120.3014},
+	{"Seoul", "Seoul", "kr", 37.5665, 126.978},
+	{"Busan", "Busan", "kr", 35.1796, 129.0756},
+	{"Jeju", "Jeju", "kr", 33.4996, 126.5312},
+	{"Pyongyang", "Pyongyang", "kp", 39.0392, 125.7625},
+	{"Tokyo", "Tokyo", "jp", 35.6762, 139.6503},
+	{"Osaka", "Osaka", "jp", 34.6937, 135.5023},
+	{"Kyoto", "Kyoto", "jp", 35.0116, 135.7681},
+	{"Nagoya", "Aichi", "jp", 35.1815, 136.9066},
+	{"Hiroshima", "Hiroshima", "jp", 34.3853, 132.4553},
+	{"Fukuoka", "Fukuoka", "jp", 33.5904, 130.4017},
+	{"Sapporo", "Hokkaido", "jp", 43.0618, 141.3545},
+	{"Sendai", "Miyagi", "jp", 38.2682, 140.8694},
+	{"Naha", "Okinawa", "jp", 26.2124, 127.6809},
+	{"Sydney", "New South Wales", "au", -33.8688, 151.2093},
+	{"Melbourne", "Victoria", "au", -37.8136, 144.9631},
+	{"Brisbane", "Queensland", "au", -27.4698, 153.0251},
+	{"Perth", "Western Australia", "au", -31.9505, 115.8605},
+	{"Adelaide", "South Australia", "au", -34.9285, 138.6007},
+	{"Canberra", "Australian Capital Territory", "au", -35.2809, 149.13},
+	{"Hobart", "Tasmania", "au", -42.8821, 147.3272},
+	{"Darwin", "Northern Territory", "au", -12.4634, 130.8456},
+	{"Cairns", "Queensland", "au", -16.9186, 145.7781},
+	{"Alice Springs", "Northern Territory", "au", -23.698, 133.8807},
+	{"Broome", "Western Australia", "au", -17.9614, 122.2359},
+	{"Townsville", "Queensland", "au", -19.259, 146.8169},
+	{"Auckland", "Auckland", "nz", -36.8485, 174.7633},
+	{"Wellington", "Wellington", "nz", -41.2865, 174.7762},
+	{"Christchurch", "Canterbury", "nz", -43.5321, 172.6362},
+	{"Queenstown", "Otago", "nz", -45.0312, 168.6626},
+	{"Suva", "Central", "fj", -18.1248, 178.4501},
+	{"Nouméa", "South Province", "nc", -22.2758, 166.458},
+	{"Port Moresby", "National Capital", "pg", -9.4438, 147.1803},
+	{"Papeete", "Windward Islands", "pf", -17.5516, -149.5585},
+	{"Honolulu", "Hawaii", "us", 21.3069, -157.8583},
+	{"Hilo", "Hawaii", "us", 19.7074, -155.0885},
+	{"Anchorage", "Alaska", "us", 61.2181, -149.9003},
+	{"Fairbanks", "Alaska", "us", 64.8378, -147.7164},
+	{"Juneau", "Alaska", "us", 58.3019, -134.4197},
+	{"Seattle", "Washington", "us", 47.6062, -122.3321},
+	{"Portland", "Oregon", "us", 45.5152, -122.6784},
+	{"San Francisco", "California", "us", 37.7749, -122.4194},
+	{"Los Angeles", "California", "us", 34.0522, -118.2437},
+	{"San Diego", "California", "us", 32.7157, -117.1611},
+	{"Sacramento", "California", "us", 38.5816, -121.4944},
+	{"Fresno", "California", "us", 36.7378, -119.7871},
+	{"Las Vegas", "Nevada", "us", 36.1699, -115.1398},
+	{"Reno", "Nevada", "us", 39.5296, -119.8138},
+	{"Salt Lake City", "Utah", "us", 40.7608, -111.891},
+	{"Boise", "Idaho", "us", 43.615, -116.2023},
+	{"Phoenix", "Arizona", "us", 33.4484, -112.074},
+	{"Flagstaff", "Arizona", "us", 35.1983, -111.6513},
+	{"Albuquerque", "New Mexico", "us", 35.0844, -106.6504},
+	{"Denver", "Colorado", "us", 39.7392, -104.9903},
+	{"Billings", "Montana", "us", 45.7833, -108.5007},
+	{"Missoula", "Montana", "us", 46.8721, -113.994},
+	{"Cheyenne", "Wyoming", "us", 41.14, -104.8202},
+	{"Jackson", "Wyoming", "us", 43.4799, -110.7624},
+	{"Rapid City", "South Dakota", "us", 44.0805, -103.231},
+	{"Bismarck", "North Dakota", "us", 46.8083, -100.7837},
+	{"Omaha", "Nebraska", "us", 41.2565, -95.9345},
+	{"Kansas City", "Missouri", "us", 39.0997, -94.5786},
+	{"Wichita", "Kansas", "us", 37.6872, -97.3301},
+	{"Oklahoma City", "Oklahoma", "us", 35.4676, -97.5164},
+	{"Dallas", "Texas", "us", 32.7767, -96.797},
+	{"Houston", "Texas", "us", 29.7604, -95.3698},
+	{"Austin", "Texas", "us", 30.2672, -97.7431},
+	{"San Antonio", "Texas", "us", 29.4241, -98.4936},
+	{"El Paso", "Texas", "us", 31.7619, -106.485},
+	{"Amarillo", "Texas", "us", 35.222, -101.8313},
+	{"Minneapolis", "Minnesota", "us", 44.9778, -93.265},
+	{"Duluth", "Minnesota", "us", 46.7867, -92.1005},
+	{"Des Moines", "Iowa", "us", 41.5868, -93.625},
+	{"St. Louis", "Missouri", "us", 38.627, -90.1994},
+	{"Chicago", "Illinois", "us", 41.8781, -87.6298},
+	{"Milwaukee", "Wisconsin", "us", 43.0389, -87.9065},
+	{"Detroit", "Michigan", "us", 42.3314, -83.0458},
+	{"Indianapolis", "Indiana", "us", 39.7684, -86.1581},
+	{"Columbus", "Ohio", "us", 39.9612, -82.9988},
+	{"Cleveland", "Ohio", "us", 41.4993, -81.6944},
+	{"Louisville", "Kentucky", "us", 38.2527, -85.7585},
+	{"Nashville", "Tennessee", "us", 36.1627, -86.7816},
+	{"Memphis", "Tennessee", "us", 35.1495, -90.049},
+	{"New Orleans", "Louisiana", "us", 29.9511, -90.0715},
+	{"Jackson", "Mississippi", "us", 32.2988, -90.1848},
+	{"Birmingham", "Alabama", "us", 33.5186, -86.8104},
+	{"Atlanta", "Georgia", "us", 33.749, -84.388},
+	{"Miami", "Florida", "us", 25.7617, -80.1918},
+	{"Orlando", "Florida", "us", 28.5383, -81.3792},
+	{"Tampa", "Florida", "us", 27.9506, -82.4572},
+	{"Jacksonville", "Florida", "us", 30.3322, -81.6557},
+	{"Key West", "Florida", "us", 24.5551, -81.78},
+	{"Charlotte", "North Carolina", "us", 35.2271, -80.8431},
+	{"Raleigh", "North Carolina", "us", 35.7796, -78.6382},
+	{"Charleston", "South Carolina", "us", 32.7765, -79.9311},
+	{"Richmond", "Virginia", "us", 37.5407, -77.436},
+	{"Washington", "District of Columbia", "us", 38.9072, -77.0369},
+	{"Baltimore", "Maryland", "us", 39.2904, -76.6122},
+	{"Philadelphia", "Pennsylvania", "us", 39.9526, -75.1652},
+	{"Pittsburgh", "Pennsylvania", "us", 40.4406, -79.9959},
+	{"New York", "New York", "us", 40.7128, -74.006},
+	{"Buffalo", "New York", "us", 42.8864, -78.8784},
+	{"Albany", "New York", "us", 42.6526, -73.7562},
+	{"Boston", "Massachusetts", "us", 42.3601, -71.0589},
+	{"Portland", "Maine", "us", 43.6591, -70.2568},
+	{"Bangor", "Maine", "us", 44.8012, -68.7778},
+	{"Burlington", "Vermont", "us", 44.4759, -73.2121},
+	{"Toronto", "Ontario", "ca", 43.6532, -79.3832},
+	{"Ottawa", "Ontario", "ca", 45.4215, -75.6972},
+	{"Montreal", "Quebec", "ca", 45.5017, -73.5673},
+	{"Quebec City", "Quebec", "ca", 46.8139, -71.208},
+	{"Halifax", "Nova Scotia", "ca", 44.6488, -63.5752},
+	{"St. John's", "Newfoundland and Labrador", "ca", 47.5615, -52.7126},
+	{"Winnipeg", "Manitoba", "ca", 49.8951, -97.1384},
+	{"Regina", "Saskatchewan", "ca", 50.4452, -104.6189},
+	{"Calgary", "Alberta", "ca", 51.0447, -114.0719},
+	{"Edmonton", "Alberta", "ca", 53.5461, -113.4938},
+	{"Vancouver", "British Columbia", "ca", 49.2827, -123.1207},
+	{"Victoria", "British Columbia", "ca", 48.4284, -123.3656},
+	{"Prince George", "British Columbia", "ca", 53.9171, -122.7497},
+	{"Whitehorse", "Yukon", "ca", 60.7212, -135.0568},
+	{"Yellowknife", "Northwest Territories", "ca", 62.454, -114.3718},
+	{"Iqaluit", "Nunavut", "ca", 63.7467, -68.517},
+	{"Thunder Bay", "Ontario", "ca", 48.3809, -89.2477},
+	{"Nuuk", "Sermersooq", "gl", 64.1814, -51.6941},
+	{"Mexico City", "Mexico City", "mx", 19.4326, -99.1332},
+	{"Guadalajara", "Jalisco", "mx", 20.6597, -103.3496},
+	{"Monterrey", "Nuevo León", "mx", 25.6866, -100.3161},
+	{"Tijuana", "Baja California", "mx", 32.5149, -117.0382},
+	{"La Paz", "Baja California Sur", "mx", 24.1426, -110.3128},
+	{"Chihuahua", "Chihuahua", "mx", 28.632, -106.0691},
+	{"Oaxaca", "Oaxaca", "mx", 17.0732, -96.7266},
+	{"Cancún", "Quintana Roo", "mx", 21.1619, -86.8515},
+	{"Mérida", "Yucatán", "mx", 20.9674, -89.5926},
+	{"Guatemala City", "Guatemala", "gt", 14.6349, -90.5069},
+	{"Belize City", "Belize", "bz", 17.5046, -88.1962},
+	{"San Salvador", "San Salvador", "sv", 13.6929, -89.2182},
+	{"Tegucigalpa", "Francisco Morazán", "hn", 14.0723, -87.1921},
+	{"Managua", "Managua", "ni", 12.1364, -86.2514},
+	{"San José", "San José", "cr", 9.9281, -84.0907},
+	{"Panama City", "Panamá", "pa", 8.9824, -79.5199},
+	{"Havana", "Havana", "cu", 23.1136, -82.3666},
+	{"Santiago de Cuba", "Santiago de Cuba", "cu", 20.0247, -75.8219},
+	{"Kingston", "Kingston", "jm", 17.9712, -76.7936},
+	{"Port-au-Prince", "Ouest", "ht", 18.5944, -72.3074},
+	{"Santo Domingo", "Santo Domingo", "do", 18.4861, -69.9312},
+	{"San Juan", "San Juan", "pr", 18.4655, -66.1057},
+	{"Nassau", "New Providence", "bs", 25.0443, -77.3504},
+	{"Port of Spain", "Port of Spain", "tt", 10.6596, -61.5086},
+	{"Bridgetown", "Saint Michael", "bb", 13.0975, -59.6167},
+	{"Bogotá", "Bogotá", "co", 4.711, -74.0721},
+	{"Medellín", "Antioquia", "co", 6.2442, -75.5812},
+	{"Cartagena", "Bolívar", "co", 10.391, -75.4794},
+	{"Caracas", "Capital District", "ve", 10.4806, -66.9036},
+	{"Maracaibo", "Zulia", "ve", 10.6427, -71.6125},
+	{"Georgetown", "Demerara-Mahaica", "gy", 6.8013, -58.1551},
+	{"Paramaribo", "Paramaribo", "sr", 5.852, -55.2038},
+	{"Cayenne", "French Guiana", "gf", 4.9224, -52.3135},
+	{"Quito", "Pichincha", "ec", -0.1807, -78.4678},
+	{"Guayaquil", "Guayas", "ec", -2.171, -79.9224},
+	{"Puerto Ayora", "Galápagos", "ec", -0.743, -90.315},
+	{"Lima", "Lima", "pe", -12.0464, -77.0428},
+	{"Cusco", "Cusco", "pe", -13.5319, -71.9675},
+	{"Arequipa", "Arequipa", "pe", -16.409, -71.5375},
+	{"Iquitos", "Loreto", "pe", -3.7491, -73.2538},
+	{"La Paz", "La Paz", "bo", -16.4897, -68.1193},
+	{"Santa Cruz de la Sierra", "Santa Cruz", "bo", -17.8146, -63.1561},
+	{"Manaus", "Amazonas", "br", -3.119, -60.0217},
+	{"Belém", "Pará", "br", -1.4558, -48.4902},
+	{"Fortaleza", "Ceará", "br", -3.7319, -38.5267},
+	{"Recife", "Pernambuco", "br", -8.0476, -34.877},
+	{"Salvador", "Bahia", "br", -12.9777, -38.5016},
+	{"Brasília", "Federal District", "br", -15.7975, -47.8919},
+	{"Cuiabá", "Mato Grosso", "br", -15.6014, -56.0979},
+	{"Rio de Janeiro", "Rio de Janeiro", "br", -22.9068, -43.1729},
+	{"São Paulo", "São Paulo", "br", -23.5505, -46.6333},
+	{"Curitiba", "Paraná", "br", -25.4284, -49.2733},
+	{"Porto Alegre", "Rio Grande do Sul", "br", -30.0346, -51.2177},
+	{"Porto Velho", "Rondônia", "br", -8.7612, -63.9004},
+	{"Asunción", "Asunción", "py", -25.2637, -57.5759},
+	{"Montevideo", "Montevideo", "uy", -34.9011, -56.1645},
+	{"Buenos Aires", "Buenos Aires", "ar", -34.6037, -58.3816},
+	{"Córdoba", "Córdoba", "ar", -31.4201, -64.1888},
+	{"Mendoza", "Mendoza", "ar", -32.8895, -68.8458},
+	{"Salta", "Salta", "ar", -24.7821, -65.4232},
+	{"Bariloche", "Río Negro", "ar", -41.1335, -71.3103},
+	{"Ushuaia", "Tierra del Fuego", "ar", -54.8019, -68.303},
+	{"Santiago", "Santiago Metropolitan", "cl", -33.4489, -70.6693},
+	{"Antofagasta", "Antofagasta", "cl", -23.6509, -70.3975},
+	{"Puerto Montt", "Los Lagos", "cl", -41.4689, -72.9411},
+	{"Punta Arenas", "Magallanes", "cl", -53.1638, -70.9171},
+	{"Hanga Roa", "Valparaíso", "cl", -27.15, -109.4333},
+	{"Cairo", "Cairo", "eg", 30.0444, 31.2357},
+	{"Alexandria", "Alexandria", "eg", 31.2001, 29.9187},
+	{"Luxor", "Luxor", "eg", 25.6872, 32.6396},
+	{"Tripoli", "Tripoli", "ly", 32.8872, 13.1913},
+	{"Benghazi", "Benghazi", "ly", 32.1167, 20.0667},
+	{"Tunis", "Tunis", "tn", 36.8065, 10.1815},
+	{"Algiers", "Algiers", "dz", 36.7538, 3.0588},
+	{"Tamanrasset", "Tamanrasset", "dz", 22.785, 5.5228},
+	{"Rabat", "Rabat-Salé-Kénitra", "ma", 34.0209, -6.8416},
+	{"Casablanca", "Casablanca-Settat", "ma", 33.5731, -7.5898},
+	{"Marrakesh", "Marrakesh-Safi", "ma", 31.6295, -7.9811},
+	{"Nouakchott", "Nouakchott", "mr", 18.0735, -15.9582},
+	{"Dakar", "Dakar", "sn", 14.7167, -17.4677},
+	{"Bamako", "Bamako", "ml", 12.6392, -8.0029},
+	{"Timbuktu", "Tombouctou", "ml", 16.7666, -3.0026},
+	{"Niamey", "Niamey", "ne", 13.5116, 2.1254},
+	{"Agadez", "Agadez", "ne", 16.9742, 7.9865},
+	{"Ouagadougou", "Centre", "bf", 12.3714, -1.5197},
+	{"Conakry", "Conakry", "gn", 9.6412, -13.5784},
+	{"Freetown", "Western Area", "sl", 8.4657, -13.2317},
+	{"Monrovia", "Montserrado", "lr", 6.3156, -10.8074},
+	{"Abidjan", "Abidjan", "ci", 5.36, -4.0083},
+	{"Accra", "Greater Accra", "gh", 5.6037, -0.187},
+	{"Lomé", "Maritime", "tg", 6.1256, 1.2254},
+	{"Cotonou", "Littoral", "bj", 6.3703, 2.3912},
+	{"Lagos", "Lagos", "ng", 6.5244, 3.3792},
+	{"Abuja", "Federal Capital Territory", "ng", 9.0765, 7.3986},
+	{"Kano", "Kano", "ng", 12.0022, 8.592},
+	{"N'Djamena", "N'Djamena", "td", 12.1348, 15.0557},
+	{"Khartoum", "Khartoum", "sd", 15.5007, 32.5599},
+	{"Juba", "Central Equatoria", "ss", 4.8594, 31.5713},
+	{"Addis Ababa", "Addis Ababa", "et", 9.03, 38.74},
+	{"Asmara", "Maekel", "er", 15.3229, 38.9251},
+	{"Djibouti", "Djibouti", "dj", 11.5721, 43.1456},
+	{"Mogadishu", "Banaadir", "so", 2.0469, 45.3182},
+	{"Nairobi", "Nairobi", "ke", -1.2921, 36.8219},
+	{"Mombasa", "Mombasa", "ke", -4.0435, 39.6682},
+	{"Kampala", "Central", "ug", 0.3476, 32.5825},
+	{"Kigali", "Kigali", "rw", -1.9441, 30.0619},
+	{"Dar es Salaam", "Dar es Salaam", "tz", -6.7924, 39.2083},
+	{"Arusha", "Arusha", "tz", -3.3869, 36.683},
+	{"Zanzibar City", "Zanzibar", "tz", -6.1659, 39.2026},
+	{"Yaoundé", "Centre", "cm", 3.848, 11.5021},
+	{"Douala", "Littoral", "cm", 4.0511, 9.7679},
+	{"Libreville", "Estuaire", "ga", 0.4162, 9.4673},
+	{"Kinshasa", "Kinshasa", "cd", -4.4419, 15.2663},
+	{"Lubumbashi", "Haut-Katanga", "cd", -11.6876, 27.5026},
+	{"Kisangani", "Tshopo", "cd", 0.5153, 25.191},
+	{"Brazzaville", "Brazzaville", "cg", -4.2634, 15.2429},
+	{"Bangui", "Bangui", "cf", 4.3947, 18.5582},
+	{"Luanda", "Luanda", "ao", -8.839, 13.2894},
+	{"Lusaka", "Lusaka", "zm", -15.3875, 28.3228},
+	{"Harare", "Harare", "zw", -17.8252, 31.0335},
+	{"Victoria Falls", "Matabeleland North", "zw", -17.9243, 25.8572},
+	{"Lilongwe", "Central", "mw", -13.9626, 33.7741},
+	{"Maputo", "Maputo", "mz", -25.9692, 32.5732},
+	{"Beira", "Sofala", "mz", -19.8436, 34.8389},
+	{"Windhoek", "Khomas", "na", -22.5609, 17.0658},
+	{"Gaborone", "South-East", "bw", -24.6282, 25.9231},
+	{"Maun", "North-West", "bw", -19.9833, 23.4167},
+	{"Johannesburg", "Gauteng", "za", -26.2041, 28.0473},
+	{"Pretoria", "Gauteng", "za", -25.7479, 28.2293},
+	{"Cape Town", "Western Cape", "za", -33.9249, 18.4241},
+	{"Durban", "KwaZulu-Natal", "za", -29.8587, 31.0218},
+	{"Port Elizabeth", "Eastern Cape", "za", -33.9608, 25.6022},
+	{"Antananarivo", "Analamanga", "mg", -18.8792, 47.5079},
+	{"Toliara", "Atsimo-Andrefana", "mg", -23.35, 43.6667},
+	{"Port Louis", "Port Louis", "mu", -20.1609, 57.5012},
+	{"Saint-Denis", "Réunion", "re", -20.8821, 55.4507},
+	{"Victoria", "Mahé", "sc", -4.6191, 55.4513},
+}
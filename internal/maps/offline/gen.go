@@ -0,0 +1,64 @@
+// +build ignore
+
+// This generates cities.go by running "go generate"
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+)
+
+type City struct {
+	Name    string
+	State   string
+	Country string
+	Lat     float64
+	Lng     float64
+}
+
+var cities []City
+
+func main() {
+	rawData, err := ioutil.ReadFile("./cities.json")
+
+	if err != nil {
+		panic(err)
+	}
+
+	err = json.Unmarshal(rawData, &cities)
+
+	if err != nil {
+		panic(err)
+	}
+
+	f, err := os.Create("cities.go")
+
+	if err != nil {
+		panic(err)
+	}
+
+	defer f.Close()
+
+	for i, v := range cities {
+		cities[i].Country = strings.ToLower(v.Country)
+	}
+
+	packageTemplate.Execute(f, struct {
+		Cities []City
+	}{
+		Cities: cities,
+	})
+}
+
+var packageTemplate = template.Must(template.New("").Parse(`// Code generated by go generate; DO NOT EDIT.
+package offline
+
+var Cities = []City{
+{{- range .Cities }}
+	{ {{- printf "%q" .Name }}, {{ printf "%q" .State }}, {{ printf "%q" .Country }}, {{ .Lat }}, {{ .Lng -}} },
+{{- end }}
+}
+`))
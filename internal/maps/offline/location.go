@@ -0,0 +1,104 @@
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/photoprism/photoprism/pkg/s2"
+)
+
+// MaxDistance is the max distance in km to the nearest city.
+var MaxDistance = 250.0
+
+// ErrNoResult is returned if there is no city within MaxDistance.
+var ErrNoResult = errors.New("offline: no result")
+
+// City represents a city in the embedded dataset.
+type City struct {
+	Name    string
+	State   string
+	Country string
+	Lat     float64
+	Lng     float64
+}
+
+// Location represents the city nearest to a location.
+type Location struct {
+	ID       string
+	Nearest  City
+	Distance float64
+}
+
+// FindLocation returns the nearest city for a S2 cell id.
+func FindLocation(id string) (result Location, err error) {
+	if len(id) > 16 || len(id) == 0 {
+		return result, fmt.Errorf("offline: invalid location id %s", id)
+	}
+
+	lat, lng := s2.LatLng(id)
+
+	if lat == 0.0 || lng == 0.0 {
+		return result, fmt.Errorf("offline: skipping lat %f, lng %f", lat, lng)
+	}
+
+	result.Distance = math.MaxFloat64
+
+	for _, c := range Cities {
+		if d := distance(lat, lng, c.Lat, c.Lng); d < result.Distance {
+			result.Nearest = c
+			result.Distance = d
+		}
+	}
+
+	if result.Distance > MaxDistance {
+		return Location{}, fmt.Errorf("%w for %s", ErrNoResult, id)
+	}
+
+	result.ID = id
+
+	log.Debugf("offline: %s is %.1f km from %s", id, result.Distance, result.Nearest.Name)
+
+	return result, nil
+}
+
+// distance returns the great-circle distance between two points in km.
+func distance(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadius = 6371.0
+
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func (l Location) CellID() (result string) {
+	return l.ID
+}
+
+func (l Location) Name() (result string) {
+	return ""
+}
+
+func (l Location) Category() (result string) {
+	return ""
+}
+
+func (l Location) City() (result string) {
+	return l.Nearest.Name
+}
+
+func (l Location) State() (result string) {
+	return l.Nearest.State
+}
+
+func (l Location) CountryCode() (result string) {
+	return l.Nearest.Country
+}
+
+func (l Location) Source() string {
+	return "offline"
+}
@@ -0,0 +1,75 @@
+package offline
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/photoprism/photoprism/pkg/s2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindLocation(t *testing.T) {
+	t.Run("Alexanderplatz", func(t *testing.T) {
+		id := s2.Token(52.5219, 13.4132)
+
+		l, err := FindLocation(id)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, id, l.CellID())
+		assert.Equal(t, "Berlin", l.City())
+		assert.Equal(t, "Berlin", l.State())
+		assert.Equal(t, "de", l.CountryCode())
+		assert.Equal(t, "offline", l.Source())
+		assert.Less(t, l.Distance, 2.0)
+	})
+	t.Run("SantaMonica", func(t *testing.T) {
+		l, err := FindLocation(s2.Token(34.00909444444444, -118.49700833333334))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Los Angeles", l.City())
+		assert.Equal(t, "California", l.State())
+		assert.Equal(t, "us", l.CountryCode())
+	})
+	t.Run("AirportZurich", func(t *testing.T) {
+		l, err := FindLocation(s2.Token(47.45401666666667, 8.557494444444446))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Zurich", l.City())
+		assert.Equal(t, "ch", l.CountryCode())
+	})
+	t.Run("Sydney", func(t *testing.T) {
+		l, err := FindLocation(s2.Token(-33.8568, 151.2153))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Sydney", l.City())
+		assert.Equal(t, "au", l.CountryCode())
+	})
+	t.Run("Pacific", func(t *testing.T) {
+		_, err := FindLocation(s2.Token(-30.0, -130.0))
+
+		assert.True(t, errors.Is(err, ErrNoResult))
+	})
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := FindLocation("")
+
+		assert.EqualError(t, err, "offline: invalid location id ")
+	})
+}
+
+func TestDistance(t *testing.T) {
+	// Berlin to Munich.
+	assert.InDelta(t, 504, distance(52.5200, 13.4050, 48.1351, 11.5820), 5)
+	assert.Equal(t, 0.0, distance(10, 10, 10, 10))
+}
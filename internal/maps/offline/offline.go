@@ -0,0 +1,16 @@
+/*
+This package provides an offline reverse geocoder based on an embedded list of cities.
+
+Additional information can be found in our Developer Guide:
+
+https://github.com/photoprism/photoprism/wiki
+*/
+package offline
+
+import (
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+//go:generate go run gen.go
+
+var log = event.Log
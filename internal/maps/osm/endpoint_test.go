@@ -0,0 +1,70 @@
+package osm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/maps/httpclient"
+	"github.com/photoprism/photoprism/pkg/s2"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestSetEndpoint(t *testing.T) {
+	defer SetEndpoint("")
+
+	t.Run("default", func(t *testing.T) {
+		SetEndpoint("")
+		assert.Equal(t, "https://nominatim.openstreetmap.org/reverse?lat=%f&lon=%f&format=jsonv2&accept-language=en&zoom=18", ReverseLookupURL)
+		assert.Equal(t, publicLimit, limiter.Limit())
+	})
+	t.Run("self-hosted", func(t *testing.T) {
+		SetEndpoint("http://nominatim.local:8080/")
+		assert.Equal(t, "http://nominatim.local:8080/reverse?lat=%f&lon=%f&format=jsonv2&accept-language=en&zoom=18", ReverseLookupURL)
+		assert.Equal(t, rate.Inf, limiter.Limit())
+	})
+	t.Run("public with trailing slash", func(t *testing.T) {
+		SetEndpoint(DefaultEndpoint + "/")
+		assert.Equal(t, publicLimit, limiter.Limit())
+	})
+	t.Run("request", func(t *testing.T) {
+		var userAgent, lat string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent = r.UserAgent()
+			lat = r.URL.Query().Get("lat")
+			_, _ = fmt.Fprint(w, `{"place_id": 1, "name": "Stub", "category": "tourism", "address": {"city": "Berlin", "country_code": "de"}}`)
+		}))
+
+		defer srv.Close()
+
+		if err := httpclient.Configure("", 0, "PhotoPrism/test"); err != nil {
+			t.Fatal(err)
+		}
+
+		defer httpclient.Configure("", 0, "")
+
+		SetEndpoint(srv.URL)
+
+		start := time.Now()
+
+		l, err := FindLocation(s2.Token(52.5163, 13.3777))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Berlin", l.City())
+		assert.Equal(t, "PhotoPrism/test", userAgent)
+		assert.Equal(t, "52.516", lat[:6])
+
+		if _, err := FindLocation(s2.Token(48.1351, 11.5820)); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	})
+}
@@ -1,16 +1,18 @@
 package osm
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/melihmucuk/geocache"
+	"github.com/photoprism/photoprism/internal/maps/httpclient"
 	"github.com/photoprism/photoprism/pkg/s2"
 	"github.com/photoprism/photoprism/pkg/txt"
+	"golang.org/x/time/rate"
 )
 
 type Location struct {
@@ -24,7 +26,38 @@ type Location struct {
 	Cached         bool
 }
 
-var ReverseLookupURL = "https://nominatim.openstreetmap.org/reverse?lat=%f&lon=%f&format=jsonv2&accept-language=en&zoom=18"
+// DefaultEndpoint is the base URL of the public Nominatim API.
+const DefaultEndpoint = "https://nominatim.openstreetmap.org"
+
+const reverseLookupPath = "/reverse?lat=%f&lon=%f&format=jsonv2&accept-language=en&zoom=18"
+
+var ReverseLookupURL = DefaultEndpoint + reverseLookupPath
+
+// SetEndpoint sets the base URL of the API, for example of a self-hosted Nominatim instance.
+// Requests are only rate limited if it's the public API.
+func SetEndpoint(baseUrl string) {
+	baseUrl = strings.TrimRight(baseUrl, "/")
+
+	if baseUrl == "" {
+		baseUrl = DefaultEndpoint
+	}
+
+	if baseUrl == DefaultEndpoint {
+		limiter.SetLimit(publicLimit)
+	} else {
+		limiter.SetLimit(rate.Inf)
+	}
+
+	ReverseLookupURL = strings.ReplaceAll(baseUrl, "%", "%%") + reverseLookupPath
+}
+
+// ErrNoResult is returned if the API didn't find a location.
+var ErrNoResult = errors.New("osm: no result")
+
+// The Nominatim usage policy allows an absolute maximum of 1 request per second.
+var publicLimit = rate.Every(time.Second)
+
+var limiter = rate.NewLimiter(publicLimit, 1)
 
 // API docs see https://wiki.openstreetmap.org/wiki/Nominatim#Reverse_Geocoding
 func FindLocation(id string) (result Location, err error) {
@@ -51,7 +84,11 @@ func FindLocation(id string) (result Location, err error) {
 
 	log.Debugf("osm: query %s", url)
 
-	r, err := http.Get(url)
+	if err := limiter.Wait(context.Background()); err != nil {
+		return result, err
+	}
+
+	r, err := httpclient.Get(url)
 
 	if err != nil {
 		log.Errorf("osm: %s", err.Error())
@@ -68,7 +105,7 @@ func FindLocation(id string) (result Location, err error) {
 	if result.PlaceID == 0 {
 		result.ID = ""
 
-		return result, fmt.Errorf("osm: no result for %s", id)
+		return result, fmt.Errorf("%w for %s", ErrNoResult, id)
 	}
 
 	result.ID = id
@@ -4,6 +4,20 @@ import (
 	"time"
 
 	gc "github.com/patrickmn/go-cache"
+	"github.com/photoprism/photoprism/internal/cache"
 )
 
-var cache = gc.New(15*time.Minute, 5*time.Minute)
+// cacheTTL is the time results of the API are cached.
+const cacheTTL = 15 * time.Minute
+
+var locationCache cache.Cache = gc.New(cacheTTL, 5*time.Minute)
+
+// SetCache replaces the default in-memory cache, for example with a cache shared by multiple instances.
+func SetCache(c cache.Cache) {
+	locationCache = c
+}
+
+// cacheKey returns the cache key for a location id.
+func cacheKey(id string) string {
+	return "places:" + id
+}
@@ -2,14 +2,17 @@ package places
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
-	gc "github.com/patrickmn/go-cache"
+	"github.com/photoprism/photoprism/internal/cache"
+	"github.com/photoprism/photoprism/internal/maps/httpclient"
 	"github.com/photoprism/photoprism/pkg/s2"
 	"github.com/photoprism/photoprism/pkg/txt"
+	"golang.org/x/time/rate"
 )
 
 // Location
@@ -23,8 +26,30 @@ type Location struct {
 	Cached      bool    `json:"-"`
 }
 
-var ReverseLookupURL = "https://places.photoprism.org/v1/location/%s"
-var client = &http.Client{Timeout: 30 * time.Second} // TODO: Change timeout if needed
+// DefaultEndpoint is the base URL of the PhotoPrism Places API.
+const DefaultEndpoint = "https://places.photoprism.org"
+
+const reverseLookupPath = "/v1/location/%s"
+
+var ReverseLookupURL = DefaultEndpoint + reverseLookupPath
+
+// SetEndpoint sets the base URL of the API.
+func SetEndpoint(baseUrl string) {
+	if baseUrl == "" {
+		baseUrl = DefaultEndpoint
+	}
+
+	ReverseLookupURL = strings.ReplaceAll(strings.TrimRight(baseUrl, "/"), "%", "%%") + reverseLookupPath
+}
+
+// ErrNoResult is returned if the API didn't find a location.
+var ErrNoResult = errors.New("places: no result")
+
+// ErrRequestFailed is returned if the API responded with an error status code.
+var ErrRequestFailed = errors.New("places: request failed")
+
+// Outbound requests are limited to 1 per second.
+var limiter = rate.NewLimiter(rate.Every(time.Second), 1)
 
 func NewLocation(id string, lat float64, lng float64, name string, category string, place Place, cached bool) *Location {
 	result := &Location{
@@ -50,9 +75,8 @@ func FindLocation(id string) (result Location, err error) {
 		return result, fmt.Errorf("places: skipping lat %f, lng %f", lat, lng)
 	}
 
-	if hit, ok := cache.Get(id); ok {
+	if cache.Load(locationCache, cacheKey(id), &result) {
 		log.Debugf("places: cache hit for lat %f, lng %f", lat, lng)
-		result = hit.(Location)
 		result.Cached = true
 		return result, nil
 	}
@@ -68,13 +92,17 @@ func FindLocation(id string) (result Location, err error) {
 		return result, err
 	}
 
-	r, err := client.Do(req)
+	if err := limiter.Wait(req.Context()); err != nil {
+		return result, err
+	}
+
+	r, err := httpclient.Do(req)
 
 	if err != nil {
 		log.Errorf("places: %s", err.Error())
 		return result, err
 	} else if r.StatusCode >= 400 {
-		err = fmt.Errorf("places: request failed with status code %d", r.StatusCode)
+		err = fmt.Errorf("%w with status code %d", ErrRequestFailed, r.StatusCode)
 		log.Error(err)
 		return result, err
 	}
@@ -88,10 +116,10 @@ func FindLocation(id string) (result Location, err error) {
 
 	if result.ID == "" {
 		log.Debugf("result: %+v", result)
-		return result, fmt.Errorf("places: no result for %s", id)
+		return result, fmt.Errorf("%w for %s", ErrNoResult, id)
 	}
 
-	cache.Set(id, result, gc.DefaultExpiration)
+	locationCache.Set(cacheKey(id), result, cacheTTL)
 
 	result.Cached = false
 
@@ -1,7 +1,14 @@
 package places
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/photoprism/photoprism/pkg/s2"
 	"github.com/stretchr/testify/assert"
@@ -69,3 +76,71 @@ func TestLocationGetters(t *testing.T) {
 	})
 
 }
+
+// stubServer returns a test server that counts requests and responds with a location,
+// except for the unknown id, so that tests don't depend on the places api.
+func stubServer(requests *int32, unknown string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+		if id == unknown {
+			_, _ = fmt.Fprint(w, `{}`)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"id": %q, "name": "Stub", "category": "test", "place": {"id": "de:stub", "label": "Berlin, Germany", "city": "Berlin", "state": "Berlin", "country": "de"}}`, id)
+	}))
+}
+
+func TestFindLocation_Stub(t *testing.T) {
+	var requests int32
+
+	unknown := s2.Token(54.0, 3.0)
+	srv := stubServer(&requests, unknown)
+	defer srv.Close()
+
+	SetEndpoint(srv.URL)
+	defer SetEndpoint("")
+
+	assert.Equal(t, srv.URL+"/v1/location/%s", ReverseLookupURL)
+
+	t.Run("cache hit", func(t *testing.T) {
+		id := s2.Token(52.5163, 13.3777)
+
+		l, err := FindLocation(id)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, l.Cached)
+		assert.Equal(t, "Berlin", l.City())
+
+		l, err = FindLocation(id)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, l.Cached)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+	t.Run("no result", func(t *testing.T) {
+		_, err := FindLocation(unknown)
+
+		assert.True(t, errors.Is(err, ErrNoResult))
+	})
+	t.Run("rate limit", func(t *testing.T) {
+		start := time.Now()
+
+		for _, id := range []string{s2.Token(52.5200, 13.4050), s2.Token(48.1351, 11.5820)} {
+			if _, err := FindLocation(id); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(900*time.Millisecond))
+	})
+}
@@ -0,0 +1,286 @@
+package meta
+
+import (
+	"math"
+	"strings"
+)
+
+// cameraMakes maps camera makes as found in Exif data to the names used as prefix in cropFactors.
+var cameraMakes = map[string]string{
+	"nikon corporation":           "nikon",
+	"olympus imaging corp.":       "olympus",
+	"olympus corporation":         "olympus",
+	"olympus optical co.,ltd":     "olympus",
+	"om digital solutions":        "olympus",
+	"pentax corporation":          "pentax",
+	"ricoh imaging company, ltd.": "pentax",
+	"fujifilm corporation":        "fujifilm",
+	"sony corporation":            "sony",
+	"panasonic corporation":       "panasonic",
+}
+
+// cropFactors contains the sensor crop factor of common cameras that don't write the FocalLengthIn35mmFilm
+// tag, keyed by lower case make and model without the make prefix. Cameras must only be added if their
+// sensor size is known, so that unknown cameras are never guessed.
+var cropFactors = map[string]float64{
+	// Canon full frame
+	"canon eos 5d":           1.0,
+	"canon eos 5d mark ii":   1.0,
+	"canon eos 5d mark iii":  1.0,
+	"canon eos 5d mark iv":   1.0,
+	"canon eos 5ds":          1.0,
+	"canon eos 5ds r":        1.0,
+	"canon eos 6d":           1.0,
+	"canon eos 6d mark ii":   1.0,
+	"canon eos-1d x":         1.0,
+	"canon eos-1d x mark ii": 1.0,
+	"canon eos r":            1.0,
+	"canon eos rp":           1.0,
+	"canon eos r5":           1.0,
+	"canon eos r6":           1.0,
+
+	// Canon APS-H
+	"canon eos-1d mark ii":  1.3,
+	"canon eos-1d mark iii": 1.3,
+	"canon eos-1d mark iv":  1.3,
+
+	// Canon APS-C
+	"canon eos 7d":            1.6,
+	"canon eos 7d mark ii":    1.6,
+	"canon eos 20d":           1.6,
+	"canon eos 30d":           1.6,
+	"canon eos 40d":           1.6,
+	"canon eos 50d":           1.6,
+	"canon eos 60d":           1.6,
+	"canon eos 70d":           1.6,
+	"canon eos 77d":           1.6,
+	"canon eos 80d":           1.6,
+	"canon eos 90d":           1.6,
+	"canon eos 350d digital":  1.6,
+	"canon eos 400d digital":  1.6,
+	"canon eos 450d":          1.6,
+	"canon eos 500d":          1.6,
+	"canon eos 550d":          1.6,
+	"canon eos 600d":          1.6,
+	"canon eos 650d":          1.6,
+	"canon eos 700d":          1.6,
+	"canon eos 750d":          1.6,
+	"canon eos 760d":          1.6,
+	"canon eos 800d":          1.6,
+	"canon eos 1000d":         1.6,
+	"canon eos 1100d":         1.6,
+	"canon eos 1200d":         1.6,
+	"canon eos 1300d":         1.6,
+	"canon eos 2000d":         1.6,
+	"canon eos 4000d":         1.6,
+	"canon eos 100d":          1.6,
+	"canon eos 200d":          1.6,
+	"canon eos m":             1.6,
+	"canon eos m3":            1.6,
+	"canon eos m5":            1.6,
+	"canon eos m6":            1.6,
+	"canon eos m50":           1.6,
+	"canon eos digital rebel": 1.6,
+	"canon eos rebel t3i":     1.6,
+	"canon eos rebel t5i":     1.6,
+
+	// Nikon FX
+	"nikon d3":    1.0,
+	"nikon d3s":   1.0,
+	"nikon d3x":   1.0,
+	"nikon d4":    1.0,
+	"nikon d4s":   1.0,
+	"nikon d5":    1.0,
+	"nikon d6":    1.0,
+	"nikon d600":  1.0,
+	"nikon d610":  1.0,
+	"nikon d700":  1.0,
+	"nikon d750":  1.0,
+	"nikon d780":  1.0,
+	"nikon d800":  1.0,
+	"nikon d800e": 1.0,
+	"nikon d810":  1.0,
+	"nikon d850":  1.0,
+	"nikon df":    1.0,
+	"nikon z 5":   1.0,
+	"nikon z 6":   1.0,
+	"nikon z 7":   1.0,
+
+	// Nikon DX
+	"nikon d40":   1.5,
+	"nikon d40x":  1.5,
+	"nikon d50":   1.5,
+	"nikon d60":   1.5,
+	"nikon d70":   1.5,
+	"nikon d70s":  1.5,
+	"nikon d80":   1.5,
+	"nikon d90":   1.5,
+	"nikon d200":  1.5,
+	"nikon d300":  1.5,
+	"nikon d300s": 1.5,
+	"nikon d500":  1.5,
+	"nikon d3000": 1.5,
+	"nikon d3100": 1.5,
+	"nikon d3200": 1.5,
+	"nikon d3300": 1.5,
+	"nikon d3400": 1.5,
+	"nikon d3500": 1.5,
+	"nikon d5000": 1.5,
+	"nikon d5100": 1.5,
+	"nikon d5200": 1.5,
+	"nikon d5300": 1.5,
+	"nikon d5500": 1.5,
+	"nikon d5600": 1.5,
+	"nikon d7000": 1.5,
+	"nikon d7100": 1.5,
+	"nikon d7200": 1.5,
+	"nikon d7500": 1.5,
+	"nikon z 50":  1.5,
+
+	// Sony full frame
+	"sony ilce-7":    1.0,
+	"sony ilce-7m2":  1.0,
+	"sony ilce-7m3":  1.0,
+	"sony ilce-7r":   1.0,
+	"sony ilce-7rm2": 1.0,
+	"sony ilce-7rm3": 1.0,
+	"sony ilce-7rm4": 1.0,
+	"sony ilce-7s":   1.0,
+	"sony ilce-7sm2": 1.0,
+	"sony ilce-9":    1.0,
+	"sony dsc-rx1":   1.0,
+	"sony dsc-rx1r":  1.0,
+
+	// Sony APS-C
+	"sony ilce-5000": 1.5,
+	"sony ilce-5100": 1.5,
+	"sony ilce-6000": 1.5,
+	"sony ilce-6100": 1.5,
+	"sony ilce-6300": 1.5,
+	"sony ilce-6400": 1.5,
+	"sony ilce-6500": 1.5,
+	"sony ilce-6600": 1.5,
+	"sony nex-3":     1.5,
+	"sony nex-5":     1.5,
+	"sony nex-5n":    1.5,
+	"sony nex-5r":    1.5,
+	"sony nex-5t":    1.5,
+	"sony nex-6":     1.5,
+	"sony nex-7":     1.5,
+
+	// Sony 1"
+	"sony dsc-rx100":   2.7,
+	"sony dsc-rx100m2": 2.7,
+	"sony dsc-rx100m3": 2.7,
+	"sony dsc-rx100m4": 2.7,
+	"sony dsc-rx100m5": 2.7,
+	"sony dsc-rx100m6": 2.7,
+	"sony dsc-rx100m7": 2.7,
+	"sony dsc-rx10":    2.7,
+	"sony dsc-rx10m2":  2.7,
+	"sony dsc-rx10m3":  2.7,
+	"sony dsc-rx10m4":  2.7,
+
+	// Fujifilm APS-C
+	"fujifilm x-e1":   1.5,
+	"fujifilm x-e2":   1.5,
+	"fujifilm x-e2s":  1.5,
+	"fujifilm x-e3":   1.5,
+	"fujifilm x-h1":   1.5,
+	"fujifilm x-pro1": 1.5,
+	"fujifilm x-pro2": 1.5,
+	"fujifilm x-pro3": 1.5,
+	"fujifilm x-t1":   1.5,
+	"fujifilm x-t2":   1.5,
+	"fujifilm x-t3":   1.5,
+	"fujifilm x-t4":   1.5,
+	"fujifilm x-t10":  1.5,
+	"fujifilm x-t20":  1.5,
+	"fujifilm x-t30":  1.5,
+	"fujifilm x100":   1.5,
+	"fujifilm x100s":  1.5,
+	"fujifilm x100t":  1.5,
+	"fujifilm x100f":  1.5,
+	"fujifilm x100v":  1.5,
+
+	// Fujifilm medium format
+	"fujifilm gfx 50s": 0.79,
+	"fujifilm gfx 50r": 0.79,
+	"fujifilm gfx 100": 0.79,
+
+	// Micro Four Thirds
+	"olympus e-m1":         2.0,
+	"olympus e-m1markii":   2.0,
+	"olympus e-m1markiii":  2.0,
+	"olympus e-m5":         2.0,
+	"olympus e-m5markii":   2.0,
+	"olympus e-m5markiii":  2.0,
+	"olympus e-m10":        2.0,
+	"olympus e-m10markii":  2.0,
+	"olympus e-m10markiii": 2.0,
+	"olympus e-pl7":        2.0,
+	"olympus e-pl8":        2.0,
+	"olympus e-pl9":        2.0,
+	"olympus e-p5":         2.0,
+	"panasonic dmc-gh3":    2.0,
+	"panasonic dmc-gh4":    2.0,
+	"panasonic dc-gh5":     2.0,
+	"panasonic dc-gh5s":    2.0,
+	"panasonic dmc-g7":     2.0,
+	"panasonic dmc-g80":    2.0,
+	"panasonic dmc-g81":    2.0,
+	"panasonic dmc-g85":    2.0,
+	"panasonic dc-g9":      2.0,
+	"panasonic dmc-gx7":    2.0,
+	"panasonic dmc-gx8":    2.0,
+	"panasonic dmc-gx80":   2.0,
+	"panasonic dmc-gx85":   2.0,
+	"panasonic dc-gx9":     2.0,
+
+	// Pentax
+	"pentax k-1":         1.0,
+	"pentax k-1 mark ii": 1.0,
+	"pentax k-3":         1.5,
+	"pentax k-3 ii":      1.5,
+	"pentax k-5":         1.5,
+	"pentax k-5 ii":      1.5,
+	"pentax k-5 ii s":    1.5,
+	"pentax k-7":         1.5,
+	"pentax k-50":        1.5,
+	"pentax k-70":        1.5,
+	"pentax kp":          1.5,
+}
+
+// CropFactor returns the sensor crop factor of a camera, or 0 if it's unknown.
+func CropFactor(cameraMake, cameraModel string) float64 {
+	cameraMake = strings.ToLower(strings.TrimSpace(cameraMake))
+	cameraModel = strings.ToLower(strings.TrimSpace(cameraModel))
+
+	if name, ok := cameraMakes[cameraMake]; ok {
+		cameraMake = name
+	}
+
+	if cameraMake == "" || cameraModel == "" {
+		return 0
+	}
+
+	// Most makes repeat their name in the model, e.g. "Canon EOS 50D" or "NIKON D7000".
+	cameraModel = strings.TrimSpace(strings.TrimPrefix(cameraModel, cameraMake))
+
+	return cropFactors[cameraMake+" "+cameraModel]
+}
+
+// FocalLength35 returns the 35mm equivalent of a focal length in mm, or 0 if the crop factor of the camera is unknown.
+func FocalLength35(focalLength float64, cameraMake, cameraModel string) int {
+	if focalLength <= 0 {
+		return 0
+	}
+
+	crop := CropFactor(cameraMake, cameraModel)
+
+	if crop <= 0 {
+		return 0
+	}
+
+	return int(math.Round(focalLength * crop))
+}
@@ -0,0 +1,41 @@
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCropFactor(t *testing.T) {
+	t.Run("canon", func(t *testing.T) {
+		assert.Equal(t, 1.6, CropFactor("Canon", "Canon EOS 50D"))
+		assert.Equal(t, 1.0, CropFactor("Canon", "Canon EOS 5D Mark III"))
+	})
+	t.Run("nikon", func(t *testing.T) {
+		assert.Equal(t, 1.5, CropFactor("NIKON CORPORATION", "NIKON D7000"))
+	})
+	t.Run("olympus", func(t *testing.T) {
+		assert.Equal(t, 2.0, CropFactor("OLYMPUS IMAGING CORP.  ", "E-M5"))
+	})
+	t.Run("unknown", func(t *testing.T) {
+		assert.Equal(t, 0.0, CropFactor("GoPro", "HD2"))
+		assert.Equal(t, 0.0, CropFactor("", "EOS 50D"))
+		assert.Equal(t, 0.0, CropFactor("Canon", ""))
+	})
+}
+
+func TestFocalLength35(t *testing.T) {
+	t.Run("crop sensor", func(t *testing.T) {
+		assert.Equal(t, 160, FocalLength35(100, "Canon", "Canon EOS 50D"))
+		assert.Equal(t, 27, FocalLength35(18, "NIKON CORPORATION", "NIKON D90"))
+	})
+	t.Run("full frame", func(t *testing.T) {
+		assert.Equal(t, 35, FocalLength35(35, "SONY", "ILCE-7M3"))
+	})
+	t.Run("unknown camera", func(t *testing.T) {
+		assert.Equal(t, 0, FocalLength35(5.58, "HUAWEI", "ELE-L29"))
+	})
+	t.Run("no focal length", func(t *testing.T) {
+		assert.Equal(t, 0, FocalLength35(0, "Canon", "Canon EOS 50D"))
+	})
+}
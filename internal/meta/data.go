@@ -4,36 +4,83 @@ import (
 	"time"
 )
 
-// Data represents image meta data.
+// Data represents image and video meta data.
 type Data struct {
-	UniqueID     string
-	TakenAt      time.Time
-	TakenAtLocal time.Time
-	TimeZone     string
-	Title        string
-	Subject      string
-	Keywords     string
-	Comment      string
-	Artist       string
-	Description  string
-	Copyright    string
-	CameraMake   string
-	CameraModel  string
-	CameraOwner  string
-	CameraSerial string
-	LensMake     string
-	LensModel    string
-	Flash        bool
-	FocalLength  int
-	Exposure     string
-	Aperture     float32
-	FNumber      float32
-	Iso          int
-	Lat          float32
-	Lng          float32
-	Altitude     int
-	Width        int
-	Height       int
-	Orientation  int
-	All          map[string]string
+	UniqueID      string
+	TakenAt       time.Time
+	TakenAtLocal  time.Time
+	TimeZone      string
+	Title         string
+	Subject       string
+	Keywords      string
+	Comment       string
+	Artist        string
+	Description   string
+	Copyright     string
+	CameraMake    string
+	CameraModel   string
+	CameraOwner   string
+	CameraSerial  string
+	LensMake      string
+	LensModel     string
+	Flash         bool
+	FocalLength   int
+	FocalLength35 int
+	Exposure      string
+	Aperture      float32
+	FNumber       float32
+	Iso           int
+	Lat           float32
+	Lng           float32
+	Altitude      int
+	Width         int
+	Height        int
+	Orientation   int
+	Rotation      int
+	Duration      time.Duration
+	Codec         string
+	AudioCodec    string
+	AudioChannels int
+	ColorTransfer int
+	HDR           bool
+	FaceRegions   FaceRegions
+	All           map[string]string
+	Warnings      []string
+}
+
+// Merge adds values that are missing in data, e.g. from a sidecar file. Keywords are appended.
+func (data *Data) Merge(other Data) {
+	if data.TakenAt.IsZero() && !other.TakenAt.IsZero() {
+		data.TakenAt = other.TakenAt
+		data.TakenAtLocal = other.TakenAtLocal
+		data.TimeZone = other.TimeZone
+	}
+
+	if data.Lat == 0 && data.Lng == 0 {
+		data.Lat = other.Lat
+		data.Lng = other.Lng
+		data.Altitude = other.Altitude
+
+		if data.TimeZone == "" {
+			data.TimeZone = other.TimeZone
+		}
+	}
+
+	if data.Title == "" {
+		data.Title = other.Title
+	}
+
+	if data.Description == "" {
+		data.Description = other.Description
+	}
+
+	if len(data.FaceRegions) == 0 {
+		data.FaceRegions = other.FaceRegions
+	}
+
+	if data.Keywords == "" {
+		data.Keywords = other.Keywords
+	} else if other.Keywords != "" {
+		data.Keywords = data.Keywords + ", " + other.Keywords
+	}
 }
@@ -1,6 +1,9 @@
 package meta
 
 import (
+	"bytes"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"math"
 	"path"
@@ -11,10 +14,23 @@ import (
 	"github.com/dsoprea/go-exif/v2"
 	"github.com/dsoprea/go-exif/v2/common"
 	"github.com/dsoprea/go-jpeg-image-structure"
+	dlog "github.com/dsoprea/go-logging"
 	"github.com/dsoprea/go-png-image-structure"
 	"gopkg.in/ugjka/go-tz.v2/tz"
 )
 
+// ErrNoExif is returned if a file contains no Exif data, which is common and not an error of the file itself.
+var ErrNoExif = errors.New("meta: no exif data")
+
+// exifError returns ErrNoExif if err reports missing Exif data, and the original error otherwise.
+func exifError(err error) error {
+	if dlog.Is(err, exif.ErrNoExif) || dlog.Is(err, pngstructure.ErrNoExif) {
+		return ErrNoExif
+	}
+
+	return err
+}
+
 // Exif parses an image file for Exif meta data and returns as Data struct.
 func Exif(filename string) (data Data, err error) {
 	defer func() {
@@ -26,7 +42,7 @@ func Exif(filename string) (data Data, err error) {
 
 	// Extract raw EXIF block.
 
-	var rawExif []byte
+	var rawExif, rawXmp []byte
 
 	fileExtension := path.Ext(filename)
 	fileExtension = strings.ToLower(fileExtension)
@@ -40,10 +56,16 @@ func Exif(filename string) (data Data, err error) {
 			return data, err
 		}
 
+		if segments, ok := sl.(*jpegstructure.SegmentList); ok {
+			rawXmp = jpegXmp(segments.Segments())
+		}
+
 		_, rawExif, err = sl.Exif()
 
+		// The embedded XMP packet may contain face regions even if there is no Exif data.
 		if err != nil {
-			return data, err
+			data.embeddedXmp(rawXmp)
+			return data, exifError(err)
 		}
 	} else if fileExtension == ".png" {
 		pmp := pngstructure.NewPngMediaParser()
@@ -57,7 +79,7 @@ func Exif(filename string) (data Data, err error) {
 		_, rawExif, err = cs.Exif()
 
 		if err != nil {
-			return data, err
+			return data, exifError(err)
 		}
 	} else {
 		// Fallback to an optimistic, brute-force search.
@@ -67,7 +89,7 @@ func Exif(filename string) (data Data, err error) {
 		rawExif, err = exif.SearchFileAndExtractExif(filename)
 
 		if err != nil {
-			return data, err
+			return data, exifError(err)
 		}
 	}
 
@@ -191,21 +213,33 @@ func Exif(filename string) (data Data, err error) {
 		}
 	}
 
-	if value, ok := tags["FocalLengthIn35mmFilm"]; ok {
-		if i, err := strconv.Atoi(value); err == nil {
-			data.FocalLength = i
-		}
-	} else if value, ok := tags["FocalLength"]; ok {
+	var focalLength float64
+
+	if value, ok := tags["FocalLength"]; ok {
 		values := strings.Split(value, "/")
 
 		if len(values) == 2 && values[1] != "0" && values[1] != "" {
 			number, _ := strconv.ParseFloat(values[0], 64)
 			denom, _ := strconv.ParseFloat(values[1], 64)
 
-			data.FocalLength = int(math.Round((number/denom)*1000) / 1000)
+			focalLength = number / denom
 		}
 	}
 
+	if value, ok := tags["FocalLengthIn35mmFilm"]; ok {
+		if i, err := strconv.Atoi(value); err == nil {
+			data.FocalLength = i
+			data.FocalLength35 = i
+		}
+	} else if focalLength > 0 {
+		data.FocalLength = int(math.Round(focalLength*1000) / 1000)
+	}
+
+	// Compute the 35mm equivalent from the crop factor if the camera doesn't provide it.
+	if data.FocalLength35 <= 0 {
+		data.FocalLength35 = FocalLength35(focalLength, data.CameraMake, data.CameraModel)
+	}
+
 	if value, ok := tags["ISOSpeedRatings"]; ok {
 		if i, err := strconv.Atoi(value); err == nil {
 			data.Iso = i
@@ -244,6 +278,11 @@ func Exif(filename string) (data Data, err error) {
 		data.Orientation = 1
 	}
 
+	// Width and height are returned as displayed, so they are swapped if the orientation transposes the image.
+	if data.Orientation > 4 {
+		data.Width, data.Height = data.Height, data.Width
+	}
+
 	_, index, err := exif.Collect(im, ti, rawExif)
 
 	if err != nil {
@@ -264,21 +303,27 @@ func Exif(filename string) (data Data, err error) {
 			Lon: float64(data.Lng),
 		})
 
-		if err != nil {
+		if err != nil || len(zones) == 0 {
 			data.TimeZone = "UTC"
+			data.Warnings = append(data.Warnings, "no time zone found for GPS position, using UTC")
+		} else {
+			data.TimeZone = zones[0]
 		}
-
-		data.TimeZone = zones[0]
 	}
 
 	if value, ok := tags["DateTimeOriginal"]; ok {
-		data.TakenAtLocal, _ = time.Parse("2006:01:02 15:04:05", value)
+		if tl, err := time.Parse("2006:01:02 15:04:05", value); err != nil {
+			data.Warnings = append(data.Warnings, fmt.Sprintf("invalid Exif date \"%s\"", value))
+		} else {
+			data.TakenAtLocal = tl
+		}
 
 		loc, err := time.LoadLocation(data.TimeZone)
 
 		if err != nil {
 			data.TakenAt = data.TakenAtLocal
 			log.Warnf("no location for timezone: %s", err.Error())
+			data.Warnings = append(data.Warnings, fmt.Sprintf("unknown time zone \"%s\"", data.TimeZone))
 		} else if tl, err := time.ParseInLocation("2006:01:02 15:04:05", value, loc); err == nil {
 			data.TakenAt = tl.UTC()
 		} else {
@@ -296,7 +341,38 @@ func Exif(filename string) (data Data, err error) {
 		data.Description = strings.Replace(value, "\"", "", -1)
 	}
 
+	data.embeddedXmp(rawXmp)
+
 	data.All = tags
 
 	return data, nil
 }
+
+// embeddedXmp reads face regions from the XMP packet embedded by Picasa and digiKam, if any.
+func (data *Data) embeddedXmp(rawXmp []byte) {
+	if len(rawXmp) == 0 {
+		return
+	}
+
+	doc := XmpDocument{}
+
+	if err := xml.Unmarshal(rawXmp, &doc); err != nil {
+		log.Warnf("meta: %s in embedded xmp", err)
+	} else {
+		data.setFaceRegions(&doc)
+	}
+}
+
+// xmpHeader is the prefix of JPEG APP1 segments that contain an XMP packet.
+var xmpHeader = []byte("http://ns.adobe.com/xap/1.0/\x00")
+
+// jpegXmp returns the XMP packet embedded in JPEG segments, if any.
+func jpegXmp(segments []*jpegstructure.Segment) []byte {
+	for _, s := range segments {
+		if s.MarkerId == jpegstructure.MARKER_APP1 && bytes.HasPrefix(s.Data, xmpHeader) {
+			return s.Data[len(xmpHeader):]
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,12 @@
 package meta
 
 import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,6 +36,7 @@ func TestExif(t *testing.T) {
 		assert.Equal(t, "", data.CameraOwner)
 		assert.Equal(t, "", data.CameraSerial)
 		assert.Equal(t, 27, data.FocalLength)
+		assert.Equal(t, 27, data.FocalLength35)
 		assert.Equal(t, 1, int(data.Orientation))
 
 		// TODO: Values are empty - why?
@@ -63,6 +70,7 @@ func TestExif(t *testing.T) {
 		assert.Equal(t, "Thomas Meyer-Boudnik", data.CameraOwner)
 		assert.Equal(t, "2260716910", data.CameraSerial)
 		assert.Equal(t, 100, data.FocalLength)
+		assert.Equal(t, 160, data.FocalLength35)
 		assert.Equal(t, 1, int(data.Orientation))
 	})
 
@@ -92,6 +100,7 @@ func TestExif(t *testing.T) {
 		assert.Equal(t, "", data.CameraOwner)
 		assert.Equal(t, "", data.CameraSerial)
 		assert.Equal(t, 16, data.FocalLength)
+		assert.Equal(t, 16, data.FocalLength35)
 		assert.Equal(t, 1, int(data.Orientation))
 	})
 
@@ -123,3 +132,76 @@ func TestExif(t *testing.T) {
 
 	})
 }
+
+func TestExif_FaceRegions(t *testing.T) {
+	img, err := ioutil.ReadFile("testdata/photoshop.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packet, err := ioutil.ReadFile("testdata/picasa.xmp")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "meta")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	t.Run("exif", func(t *testing.T) {
+		fileName := filepath.Join(dir, "picasa.jpg")
+
+		if err := ioutil.WriteFile(fileName, embedXmp(img, packet), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := Exif(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Michael Mayer", data.Artist)
+		assert.Equal(t, []string{"Jane Doe", "John Doe"}, data.FaceRegions.Names())
+	})
+	t.Run("no exif", func(t *testing.T) {
+		var plain bytes.Buffer
+
+		if err := jpeg.Encode(&plain, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil); err != nil {
+			t.Fatal(err)
+		}
+
+		fileName := filepath.Join(dir, "picasa_no_exif.jpg")
+
+		if err := ioutil.WriteFile(fileName, embedXmp(plain.Bytes(), packet), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := Exif(fileName)
+
+		assert.Equal(t, ErrNoExif, err)
+		assert.Equal(t, []string{"Jane Doe", "John Doe"}, data.FaceRegions.Names())
+	})
+}
+
+// embedXmp returns JPEG data with an XMP packet in an APP1 segment after the start of image marker,
+// like Picasa embeds it.
+func embedXmp(img, packet []byte) []byte {
+	segment := append(append([]byte{}, xmpHeader...), packet...)
+	length := []byte{byte((len(segment) + 2) >> 8), byte(len(segment) + 2)}
+
+	var buf bytes.Buffer
+	buf.Write(img[:2])
+	buf.Write([]byte{0xFF, 0xE1})
+	buf.Write(length)
+	buf.Write(segment)
+	buf.Write(img[2:])
+
+	return buf.Bytes()
+}
@@ -0,0 +1,93 @@
+package meta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FaceRegion represents a named face region, with the position of its top left corner and its size
+// relative to the width and height of the image.
+type FaceRegion struct {
+	Name string
+	X    float64
+	Y    float64
+	W    float64
+	H    float64
+}
+
+// FaceRegions represents a list of face regions.
+type FaceRegions []FaceRegion
+
+// NewFaceRegion returns a new face region and an error if the name is empty or the rectangle
+// is outside the image.
+func NewFaceRegion(name string, x, y, w, h float64) (FaceRegion, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return FaceRegion{}, fmt.Errorf("face region without name")
+	}
+
+	const e = 0.0001
+
+	if w <= 0 || h <= 0 || x < -e || y < -e || x+w > 1+e || y+h > 1+e {
+		return FaceRegion{}, fmt.Errorf("face region \"%s\" outside image", name)
+	}
+
+	return FaceRegion{Name: name, X: x, Y: y, W: w, H: h}, nil
+}
+
+// Rotate transforms a face region of the stored image, so that it matches the image once the
+// Exif orientation is applied.
+func (r FaceRegion) Rotate(orientation int) FaceRegion {
+	x, y, w, h := r.X, r.Y, r.W, r.H
+
+	switch orientation {
+	case 2:
+		x = 1 - r.X - r.W
+	case 3:
+		x, y = 1-r.X-r.W, 1-r.Y-r.H
+	case 4:
+		y = 1 - r.Y - r.H
+	case 5:
+		x, y, w, h = r.Y, r.X, r.H, r.W
+	case 6:
+		x, y, w, h = 1-r.Y-r.H, r.X, r.H, r.W
+	case 7:
+		x, y, w, h = 1-r.Y-r.H, 1-r.X-r.W, r.H, r.W
+	case 8:
+		x, y, w, h = r.Y, 1-r.X-r.W, r.H, r.W
+	}
+
+	return FaceRegion{Name: r.Name, X: x, Y: y, W: w, H: h}
+}
+
+// Rotate transforms all face regions like FaceRegion.Rotate.
+func (regions FaceRegions) Rotate(orientation int) FaceRegions {
+	if orientation <= 1 || orientation > 8 {
+		return regions
+	}
+
+	result := make(FaceRegions, len(regions))
+
+	for i, r := range regions {
+		result[i] = r.Rotate(orientation)
+	}
+
+	return result
+}
+
+// Names returns the unique names of the face regions.
+func (regions FaceRegions) Names() (result []string) {
+	found := make(map[string]bool)
+
+	for _, r := range regions {
+		if found[r.Name] {
+			continue
+		}
+
+		found[r.Name] = true
+		result = append(result, r.Name)
+	}
+
+	return result
+}
@@ -0,0 +1,60 @@
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFaceRegion(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		r, err := NewFaceRegion(" Jane Doe ", 0.1, 0.2, 0.3, 0.4)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, FaceRegion{Name: "Jane Doe", X: 0.1, Y: 0.2, W: 0.3, H: 0.4}, r)
+	})
+	t.Run("no name", func(t *testing.T) {
+		_, err := NewFaceRegion("", 0.1, 0.2, 0.3, 0.4)
+		assert.EqualError(t, err, "face region without name")
+	})
+	t.Run("outside", func(t *testing.T) {
+		_, err := NewFaceRegion("Jane Doe", 0.8, 0.2, 0.3, 0.4)
+		assert.EqualError(t, err, "face region \"Jane Doe\" outside image")
+	})
+	t.Run("empty", func(t *testing.T) {
+		_, err := NewFaceRegion("Jane Doe", 0.1, 0.2, 0, 0.4)
+		assert.Error(t, err)
+	})
+}
+
+func TestFaceRegion_Rotate(t *testing.T) {
+	r := FaceRegion{Name: "Jane Doe", X: 0.1, Y: 0.2, W: 0.3, H: 0.4}
+
+	expected := map[int][4]float64{
+		1: {0.1, 0.2, 0.3, 0.4},
+		2: {0.6, 0.2, 0.3, 0.4},
+		3: {0.6, 0.4, 0.3, 0.4},
+		4: {0.1, 0.4, 0.3, 0.4},
+		5: {0.2, 0.1, 0.4, 0.3},
+		6: {0.4, 0.1, 0.4, 0.3},
+		7: {0.4, 0.6, 0.4, 0.3},
+		8: {0.2, 0.6, 0.4, 0.3},
+	}
+
+	for orientation, e := range expected {
+		result := r.Rotate(orientation)
+
+		assert.Equal(t, "Jane Doe", result.Name)
+		assert.InDeltaSlice(t, e[:], []float64{result.X, result.Y, result.W, result.H}, 0.0001, "orientation %d", orientation)
+	}
+}
+
+func TestFaceRegions_Names(t *testing.T) {
+	regions := FaceRegions{{Name: "Jane Doe"}, {Name: "John Doe"}, {Name: "Jane Doe"}}
+
+	assert.Equal(t, []string{"Jane Doe", "John Doe"}, regions.Names())
+	assert.Empty(t, FaceRegions{}.Names())
+}
@@ -0,0 +1,353 @@
+package meta
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// Transfer characteristics as defined in ITU-T H.273.
+const (
+	TransferBT709 = 1
+	TransferPQ    = 16 // SMPTE ST 2084, used by HDR10 and Dolby Vision.
+	TransferHLG   = 18 // ARIB STD-B67 Hybrid Log-Gamma, used by iPhone HDR videos.
+)
+
+// MaxMovieBoxSize limits the amount of memory used to parse the movie header.
+const MaxMovieBoxSize = 64 * 1024 * 1024
+
+// mp4Epoch is the start of the MP4 and QuickTime time scale.
+var mp4Epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var videoCodecs = map[string]string{
+	"avc1": "avc",
+	"avc3": "avc",
+	"hvc1": "hevc",
+	"hev1": "hevc",
+	"dvh1": "hevc",
+	"av01": "av1",
+	"vp09": "vp9",
+	"mp4v": "mp4v",
+	"apch": "prores",
+	"apcn": "prores",
+	"apcs": "prores",
+	"apco": "prores",
+	"ap4h": "prores",
+	"jpeg": "mjpeg",
+}
+
+var audioCodecs = map[string]string{
+	"mp4a": "aac",
+	"ac-3": "ac3",
+	"ec-3": "eac3",
+	"Opus": "opus",
+	"alac": "alac",
+	"lpcm": "pcm",
+	"sowt": "pcm",
+	"twos": "pcm",
+	"ipcm": "pcm",
+}
+
+// rotationOrientation maps a clockwise display rotation to the equivalent Exif orientation.
+var rotationOrientation = map[int]int{0: 1, 90: 6, 180: 3, 270: 8}
+
+type mp4Box struct {
+	Type string
+	Data []byte
+}
+
+// MP4 returns meta data of MP4 and QuickTime video files, including track rotation,
+// HDR transfer function and audio track details.
+func MP4(filename string) (data Data, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			data = Data{}
+			err = fmt.Errorf("meta: %s", e)
+		}
+	}()
+
+	f, err := os.Open(filename)
+
+	if err != nil {
+		return data, err
+	}
+
+	defer f.Close()
+
+	moov, err := findMovieBox(f)
+
+	if err != nil {
+		return data, err
+	}
+
+	data.All = make(map[string]string)
+	data.Orientation = 1
+
+	for _, box := range mp4Boxes(moov) {
+		switch box.Type {
+		case "mvhd":
+			parseMovieHeader(box.Data, &data)
+		case "trak":
+			parseTrack(box.Data, &data)
+		}
+	}
+
+	if data.Width == 0 || data.Height == 0 {
+		return data, errors.New("meta: no video track found")
+	}
+
+	return data, nil
+}
+
+// findMovieBox returns the content of the top-level moov box without reading the media data.
+func findMovieBox(f *os.File) ([]byte, error) {
+	var offset int64
+	header := make([]byte, 16)
+
+	for {
+		if _, err := f.ReadAt(header[:8], offset); err == io.EOF {
+			return nil, errors.New("meta: no movie header found")
+		} else if err != nil {
+			return nil, err
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+
+		if size == 1 {
+			if _, err := f.ReadAt(header[8:16], offset+8); err != nil {
+				return nil, err
+			}
+
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		} else if size == 0 {
+			info, err := f.Stat()
+
+			if err != nil {
+				return nil, err
+			}
+
+			size = info.Size() - offset
+		}
+
+		if size < headerSize {
+			return nil, fmt.Errorf("meta: invalid box size %d", size)
+		}
+
+		if boxType == "moov" {
+			if size > MaxMovieBoxSize {
+				return nil, fmt.Errorf("meta: movie header exceeds %d bytes", MaxMovieBoxSize)
+			}
+
+			result := make([]byte, size-headerSize)
+
+			if _, err := f.ReadAt(result, offset+headerSize); err != nil {
+				return nil, err
+			}
+
+			return result, nil
+		}
+
+		offset += size
+	}
+}
+
+// mp4Boxes returns the boxes contained in b, truncated boxes are ignored.
+func mp4Boxes(b []byte) (result []mp4Box) {
+	for len(b) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(b[0:4]))
+		headerSize := uint64(8)
+
+		if size == 1 && len(b) >= 16 {
+			size = binary.BigEndian.Uint64(b[8:16])
+			headerSize = 16
+		} else if size == 0 {
+			size = uint64(len(b))
+		}
+
+		if size < headerSize || size > uint64(len(b)) {
+			break
+		}
+
+		result = append(result, mp4Box{Type: string(b[4:8]), Data: b[headerSize:size]})
+		b = b[size:]
+	}
+
+	return result
+}
+
+// findBox returns the content of the first box matching the path, e.g. "mdia", "hdlr".
+func findBox(b []byte, path ...string) []byte {
+	for _, name := range path {
+		found := false
+
+		for _, box := range mp4Boxes(b) {
+			if box.Type == name {
+				b = box.Data
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil
+		}
+	}
+
+	return b
+}
+
+func parseMovieHeader(b []byte, data *Data) {
+	var created, timescale, duration uint64
+
+	if len(b) < 20 {
+		return
+	}
+
+	if b[0] == 1 && len(b) >= 32 {
+		created = binary.BigEndian.Uint64(b[4:12])
+		timescale = uint64(binary.BigEndian.Uint32(b[20:24]))
+		duration = binary.BigEndian.Uint64(b[24:32])
+	} else {
+		created = uint64(binary.BigEndian.Uint32(b[4:8]))
+		timescale = uint64(binary.BigEndian.Uint32(b[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(b[16:20]))
+	}
+
+	if created > 0 {
+		data.TakenAt = mp4Epoch.Add(time.Duration(created) * time.Second)
+	}
+
+	if timescale > 0 {
+		data.Duration = time.Duration(duration) * time.Second / time.Duration(timescale)
+	}
+}
+
+func parseTrack(b []byte, data *Data) {
+	hdlr := findBox(b, "mdia", "hdlr")
+
+	if len(hdlr) < 12 {
+		return
+	}
+
+	stsd := findBox(b, "mdia", "minf", "stbl", "stsd")
+
+	if len(stsd) < 8 {
+		return
+	}
+
+	entries := mp4Boxes(stsd[8:])
+
+	if len(entries) == 0 {
+		return
+	}
+
+	entry := entries[0]
+
+	switch string(hdlr[8:12]) {
+	case "vide":
+		if data.Width > 0 {
+			// Only the first video track is used.
+			return
+		}
+
+		parseTrackHeader(findBox(b, "tkhd"), data)
+		parseVideoEntry(entry, data)
+	case "soun":
+		if data.AudioCodec != "" {
+			return
+		}
+
+		parseAudioEntry(entry, data)
+	}
+}
+
+func parseTrackHeader(b []byte, data *Data) {
+	// The display matrix follows the durations and 16 bytes of layer, volume and reserved fields.
+	offset := 40
+
+	if len(b) > 0 && b[0] == 1 {
+		offset = 52
+	}
+
+	if len(b) < offset+44 {
+		return
+	}
+
+	// Values are 16.16 fixed point numbers.
+	matrix := make([]float64, 2)
+
+	for i := range matrix {
+		matrix[i] = float64(int32(binary.BigEndian.Uint32(b[offset+i*4:offset+i*4+4]))) / 65536
+	}
+
+	data.Rotation = MatrixRotation(matrix[0], matrix[1])
+	data.Orientation = rotationOrientation[data.Rotation]
+	data.Width = int(binary.BigEndian.Uint32(b[offset+36:offset+40]) >> 16)
+	data.Height = int(binary.BigEndian.Uint32(b[offset+40:offset+44]) >> 16)
+}
+
+// MatrixRotation returns the clockwise rotation in degrees (0, 90, 180 or 270) of a track display
+// matrix, where a and b are the first two values of the matrix, i.e. cos and sin of the angle.
+func MatrixRotation(a, b float64) int {
+	if a == 0 && b == 0 {
+		return 0
+	}
+
+	deg := int(math.Round(math.Atan2(b, a)*180/math.Pi/90)) * 90
+
+	return (deg + 360) % 360
+}
+
+func parseVideoEntry(entry mp4Box, data *Data) {
+	if codec, ok := videoCodecs[entry.Type]; ok {
+		data.Codec = codec
+	} else {
+		data.Codec = strings.TrimSpace(entry.Type)
+	}
+
+	// Visual sample entries have a fixed size of 78 bytes before their child boxes.
+	if len(entry.Data) < 78 {
+		return
+	}
+
+	if colr := findBox(entry.Data[78:], "colr"); len(colr) >= 10 {
+		colorType := string(colr[0:4])
+
+		if colorType == "nclx" || colorType == "nclc" {
+			data.ColorTransfer = int(binary.BigEndian.Uint16(colr[6:8]))
+		}
+	}
+
+	data.HDR = data.ColorTransfer == TransferPQ || data.ColorTransfer == TransferHLG
+
+	if entry.Type == "dvh1" || findBox(entry.Data[78:], "dvcC") != nil || findBox(entry.Data[78:], "dvvC") != nil {
+		data.HDR = true
+	}
+}
+
+func parseAudioEntry(entry mp4Box, data *Data) {
+	if codec, ok := audioCodecs[entry.Type]; ok {
+		data.AudioCodec = codec
+	} else {
+		data.AudioCodec = strings.TrimSpace(entry.Type)
+	}
+
+	if len(entry.Data) < 28 {
+		return
+	}
+
+	// QuickTime sound description version 2 stores the channel count as 32 bit value.
+	if binary.BigEndian.Uint16(entry.Data[8:10]) == 2 && len(entry.Data) >= 44 {
+		data.AudioChannels = int(binary.BigEndian.Uint32(entry.Data[40:44]))
+	} else {
+		data.AudioChannels = int(binary.BigEndian.Uint16(entry.Data[16:18]))
+	}
+}
@@ -0,0 +1,205 @@
+package meta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testVideo describes a synthetic MP4 file with the same box layout as files created by phones and cameras.
+type testVideo struct {
+	Version  int
+	Matrix   [4]int32
+	Width    int
+	Height   int
+	Codec    string
+	Transfer int
+	Audio    string
+	Channels int
+}
+
+func box(boxType string, content ...[]byte) []byte {
+	data := bytes.Join(content, nil)
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(data)+8))
+	copy(b[4:8], boxType)
+	return append(b, data...)
+}
+
+func u32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func u16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return b
+}
+
+func fixed(v int32) []byte {
+	return u32(uint32(v << 16))
+}
+
+func (v testVideo) trackHeader() []byte {
+	var b []byte
+
+	if v.Version == 1 {
+		b = append([]byte{1, 0, 0, 0}, make([]byte, 48)...)
+	} else {
+		b = make([]byte, 40)
+	}
+
+	b = append(b, fixed(v.Matrix[0])...)
+	b = append(b, fixed(v.Matrix[1])...)
+	b = append(b, u32(0)...)
+	b = append(b, fixed(v.Matrix[2])...)
+	b = append(b, fixed(v.Matrix[3])...)
+	b = append(b, u32(0)...)
+	b = append(b, u32(0)...)
+	b = append(b, u32(0)...)
+	b = append(b, u32(0x40000000)...)
+	b = append(b, fixed(int32(v.Width))...)
+	b = append(b, fixed(int32(v.Height))...)
+
+	return box("tkhd", b)
+}
+
+func track(handler string, tkhd []byte, entry []byte) []byte {
+	hdlr := box("hdlr", make([]byte, 8), []byte(handler), make([]byte, 12))
+	stsd := box("stsd", u32(0), u32(1), entry)
+	return box("trak", tkhd, box("mdia", hdlr, box("minf", box("stbl", stsd))))
+}
+
+func (v testVideo) Bytes() []byte {
+	// Created 2020-05-01 12:00:00 UTC, duration 12.5 seconds.
+	created := uint32(time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC).Sub(mp4Epoch) / time.Second)
+	mvhd := box("mvhd", u32(0), u32(created), u32(created), u32(1000), u32(12500), make([]byte, 80))
+
+	var colr []byte
+
+	if v.Transfer > 0 {
+		colr = box("colr", []byte("nclx"), u16(9), u16(uint16(v.Transfer)), u16(9), []byte{0})
+	}
+
+	visual := box(v.Codec, make([]byte, 24), u16(uint16(v.Width)), u16(uint16(v.Height)), make([]byte, 50), box("hvcC", make([]byte, 23)), colr)
+	tracks := track("vide", v.trackHeader(), visual)
+
+	if v.Audio != "" {
+		sound := box(v.Audio, make([]byte, 16), u16(uint16(v.Channels)), u16(16), u32(0), fixed(48000), box("esds", make([]byte, 20)))
+		tracks = append(tracks, track("soun", box("tkhd", make([]byte, 84)), sound)...)
+	}
+
+	ftyp := box("ftyp", []byte("qt  "), u32(0))
+	mdat := box("mdat", make([]byte, 1024))
+
+	return bytes.Join([][]byte{ftyp, mdat, box("moov", mvhd, tracks)}, nil)
+}
+
+func writeTestVideo(t *testing.T, v testVideo) string {
+	dir, err := ioutil.TempDir("", "mp4")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(dir, "video.mp4")
+
+	if err := ioutil.WriteFile(fileName, v.Bytes(), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func TestMP4(t *testing.T) {
+	t.Run("iphone portrait", func(t *testing.T) {
+		fileName := writeTestVideo(t, testVideo{Matrix: [4]int32{0, 1, -1, 0}, Width: 1920, Height: 1080, Codec: "hvc1", Audio: "mp4a", Channels: 1})
+		defer os.RemoveAll(filepath.Dir(fileName))
+
+		data, err := MP4(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 90, data.Rotation)
+		assert.Equal(t, 6, data.Orientation)
+		assert.Equal(t, 1920, data.Width)
+		assert.Equal(t, 1080, data.Height)
+		assert.Equal(t, "hevc", data.Codec)
+		assert.False(t, data.HDR)
+		assert.Equal(t, "aac", data.AudioCodec)
+		assert.Equal(t, 1, data.AudioChannels)
+		assert.Equal(t, 12500*time.Millisecond, data.Duration)
+		assert.Equal(t, "2020-05-01 12:00:00 +0000 UTC", data.TakenAt.String())
+	})
+	t.Run("gopro", func(t *testing.T) {
+		fileName := writeTestVideo(t, testVideo{Version: 1, Matrix: [4]int32{1, 0, 0, 1}, Width: 3840, Height: 2160, Codec: "avc1", Transfer: TransferBT709, Audio: "mp4a", Channels: 2})
+		defer os.RemoveAll(filepath.Dir(fileName))
+
+		data, err := MP4(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 0, data.Rotation)
+		assert.Equal(t, 1, data.Orientation)
+		assert.Equal(t, 3840, data.Width)
+		assert.Equal(t, "avc", data.Codec)
+		assert.Equal(t, TransferBT709, data.ColorTransfer)
+		assert.False(t, data.HDR)
+		assert.Equal(t, 2, data.AudioChannels)
+	})
+	t.Run("hlg", func(t *testing.T) {
+		fileName := writeTestVideo(t, testVideo{Matrix: [4]int32{-1, 0, 0, -1}, Width: 1920, Height: 1080, Codec: "hvc1", Transfer: TransferHLG})
+		defer os.RemoveAll(filepath.Dir(fileName))
+
+		data, err := MP4(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 180, data.Rotation)
+		assert.Equal(t, 3, data.Orientation)
+		assert.Equal(t, TransferHLG, data.ColorTransfer)
+		assert.True(t, data.HDR)
+		assert.Equal(t, "", data.AudioCodec)
+	})
+	t.Run("encoder output", func(t *testing.T) {
+		data, err := MP4("../../assets/resources/examples/christmas.mp4")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 0, data.Rotation)
+		assert.Equal(t, 640, data.Width)
+		assert.Equal(t, 416, data.Height)
+		assert.Equal(t, "avc", data.Codec)
+		assert.False(t, data.HDR)
+		assert.Equal(t, 810*time.Millisecond, data.Duration.Truncate(time.Millisecond))
+	})
+	t.Run("not a video", func(t *testing.T) {
+		_, err := MP4("testdata/ladybug.jpg")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMatrixRotation(t *testing.T) {
+	assert.Equal(t, 0, MatrixRotation(1, 0))
+	assert.Equal(t, 90, MatrixRotation(0, 1))
+	assert.Equal(t, 180, MatrixRotation(-1, 0))
+	assert.Equal(t, 270, MatrixRotation(0, -1))
+	assert.Equal(t, 0, MatrixRotation(0, 0))
+}
@@ -0,0 +1,116 @@
+package meta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+)
+
+// exifOrientation is the Exif tag of the image orientation.
+const exifOrientation = 0x0112
+
+// ResetOrientation sets the Exif orientation of a JPEG file to 1 (normal), so that images whose
+// pixels have already been rotated, e.g. by a converter that copied the original Exif data, are
+// not rotated twice. Files without orientation are not changed.
+func ResetOrientation(fileName string) error {
+	data, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	changed, err := setOrientation(data, 1)
+
+	if err != nil || !changed {
+		return err
+	}
+
+	info, err := os.Stat(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, data, info.Mode())
+}
+
+// setOrientation changes the orientation tag in the Exif segment of JPEG data in place.
+// Returns true if the data was changed.
+func setOrientation(data []byte, orientation int) (bool, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return false, errors.New("meta: not a jpeg image")
+	}
+
+	i := 2
+
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+
+		// Metadata segments are in front of the image data.
+		if marker == 0xDA {
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+
+		if length < 2 || i+2+length > len(data) {
+			return false, errors.New("meta: invalid jpeg segment length")
+		}
+
+		if content := data[i+4 : i+2+length]; marker == 0xE1 && bytes.HasPrefix(content, []byte("Exif\x00\x00")) {
+			return setTiffOrientation(content[6:], orientation), nil
+		}
+
+		i += 2 + length
+	}
+
+	return false, nil
+}
+
+// setTiffOrientation changes the orientation tag in the first IFD of TIFF data in place.
+func setTiffOrientation(tiff []byte, orientation int) bool {
+	var order binary.ByteOrder
+
+	if len(tiff) < 8 {
+		return false
+	} else if tiff[0] == 'I' && tiff[1] == 'I' {
+		order = binary.LittleEndian
+	} else if tiff[0] == 'M' && tiff[1] == 'M' {
+		order = binary.BigEndian
+	} else {
+		return false
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+
+	if ifd+2 > len(tiff) {
+		return false
+	}
+
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+
+	for n := 0; n < count; n++ {
+		entry := ifd + 2 + n*12
+
+		if entry+12 > len(tiff) {
+			return false
+		}
+
+		// The orientation is a single short value, which is stored in the entry itself.
+		if order.Uint16(tiff[entry:entry+2]) != exifOrientation || order.Uint16(tiff[entry+2:entry+4]) != 3 {
+			continue
+		}
+
+		if int(order.Uint16(tiff[entry+8:entry+10])) == orientation {
+			return false
+		}
+
+		order.PutUint16(tiff[entry+8:entry+10], uint16(orientation))
+
+		return true
+	}
+
+	return false
+}
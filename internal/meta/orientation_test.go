@@ -0,0 +1,108 @@
+package meta
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExif_Orientation(t *testing.T) {
+	// All fixtures show the same 64x32 image.
+	for orientation := 1; orientation <= 8; orientation++ {
+		for _, ext := range []string{".jpg", ".png"} {
+			fileName := fmt.Sprintf("testdata/orientation/orientation_%d%s", orientation, ext)
+
+			t.Run(filepath.Base(fileName), func(t *testing.T) {
+				data, err := Exif(fileName)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, orientation, data.Orientation)
+				assert.Equal(t, 64, data.Width)
+				assert.Equal(t, 32, data.Height)
+			})
+		}
+	}
+}
+
+func TestResetOrientation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orientation")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	t.Run("orientation_6.jpg", func(t *testing.T) {
+		fileName := filepath.Join(dir, "orientation_6.jpg")
+		b, err := ioutil.ReadFile("testdata/orientation/orientation_6.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(fileName, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ResetOrientation(fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := Exif(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, data.Orientation)
+		assert.Equal(t, 32, data.Width)
+		assert.Equal(t, 64, data.Height)
+
+		result, err := ioutil.ReadFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, len(b), len(result))
+	})
+
+	t.Run("orientation_1.jpg", func(t *testing.T) {
+		fileName := filepath.Join(dir, "orientation_1.jpg")
+		b, err := ioutil.ReadFile("testdata/orientation/orientation_1.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(fileName, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ResetOrientation(fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := ioutil.ReadFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, b, result)
+	})
+
+	t.Run("not a jpeg", func(t *testing.T) {
+		err := ResetOrientation("testdata/orientation/orientation_6.png")
+
+		assert.EqualError(t, err, "meta: not a jpeg image")
+	})
+}
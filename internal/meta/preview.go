@@ -0,0 +1,250 @@
+package meta
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"math"
+	"os"
+)
+
+// Preview represents a JPEG image embedded in another file, e.g. an Exif thumbnail or RAW preview.
+type Preview struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+var jpegStart = []byte{0xFF, 0xD8, 0xFF}
+
+// previewBufferSize is the size of the buffer used to search files for embedded JPEG images.
+const previewBufferSize = 1 << 20
+
+// PreviewAspectTolerance is the maximum relative difference between the aspect ratio of an embedded
+// preview and the original, so that rounded sizes of small thumbnails are still accepted.
+const PreviewAspectTolerance = 0.02
+
+// EmbeddedPreview returns the largest JPEG image embedded in a file. The image data of JPEG
+// files themselves is skipped, so only Exif thumbnails and similar previews are returned.
+// Files are searched in chunks and only the preview is read, as RAW files may be large.
+func EmbeddedPreview(filename string) (result Preview, err error) {
+	return EmbeddedPreviewAspect(filename, 0, 0)
+}
+
+// EmbeddedPreviewAspect returns the largest JPEG image embedded in a file that has the aspect
+// ratio of the original with the given width and height, in either orientation. Letterboxed or
+// cropped previews are skipped. The aspect ratio is not checked if the size is unknown.
+func EmbeddedPreviewAspect(filename string, width, height int) (result Preview, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			result = Preview{}
+			err = fmt.Errorf("meta: %s", e)
+		}
+	}()
+
+	f, err := os.Open(filename)
+
+	if err != nil {
+		return result, err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return result, err
+	}
+
+	size := info.Size()
+	best := int64(-1)
+	buf := make([]byte, previewBufferSize)
+
+	// Chunks overlap, so that markers crossing their boundaries are found.
+	overlap := int64(len(jpegStart) - 1)
+
+	for pos := int64(0); pos < size; pos += int64(len(buf)) - overlap {
+		n, err := f.ReadAt(buf, pos)
+
+		if err != nil && err != io.EOF {
+			return result, err
+		}
+
+		for i := 0; i < n; {
+			j := bytes.Index(buf[i:n], jpegStart)
+
+			if j < 0 {
+				break
+			}
+
+			offset := pos + int64(i+j)
+			i += j + 1
+
+			if offset == 0 || offset <= best {
+				continue
+			}
+
+			cfg, err := jpeg.DecodeConfig(io.NewSectionReader(f, offset, size-offset))
+
+			if err != nil || cfg.Width*cfg.Height <= result.Width*result.Height || !sameAspect(cfg.Width, cfg.Height, width, height) {
+				continue
+			}
+
+			best = offset
+			result = Preview{Width: cfg.Width, Height: cfg.Height}
+		}
+
+		if int64(n) < int64(len(buf)) {
+			break
+		}
+	}
+
+	if result.Width == 0 || result.Height == 0 {
+		return result, errors.New("meta: no embedded preview found")
+	}
+
+	length, err := jpegLength(io.NewSectionReader(f, best, size-best))
+
+	// The rest of the file is returned if the end of the preview can't be found.
+	if err != nil {
+		length = size - best
+	}
+
+	result.Data = make([]byte, length)
+
+	if _, err := f.ReadAt(result.Data, best); err != nil && err != io.EOF {
+		return Preview{}, err
+	}
+
+	return result, nil
+}
+
+// sameAspect tests if two sizes have the same aspect ratio within PreviewAspectTolerance, regardless
+// of their orientation. It returns true if the second size is unknown.
+func sameAspect(width, height, origWidth, origHeight int) bool {
+	if origWidth <= 0 || origHeight <= 0 {
+		return true
+	}
+
+	if width <= 0 || height <= 0 {
+		return false
+	}
+
+	ratio := func(w, h int) float64 {
+		if w < h {
+			w, h = h, w
+		}
+
+		return float64(w) / float64(h)
+	}
+
+	orig := ratio(origWidth, origHeight)
+
+	return math.Abs(ratio(width, height)-orig)/orig <= PreviewAspectTolerance
+}
+
+// jpegLength returns the length of the JPEG image at the start of r, up to and including its
+// end of image marker. Segments are skipped by length, entropy coded data is searched for markers.
+func jpegLength(r io.Reader) (length int64, err error) {
+	br := bufio.NewReader(r)
+
+	next := func() (byte, error) {
+		b, err := br.ReadByte()
+
+		if err == nil {
+			length++
+		}
+
+		return b, err
+	}
+
+	// Skips the start of image marker.
+	for i := 0; i < 2; i++ {
+		if _, err := next(); err != nil {
+			return length, err
+		}
+	}
+
+	var marker byte
+
+	for {
+		if marker == 0 {
+			if b, err := next(); err != nil {
+				return length, err
+			} else if b != 0xFF {
+				return length, errors.New("meta: invalid jpeg marker")
+			}
+
+			// Markers may be preceded by any number of fill bytes.
+			for marker == 0 || marker == 0xFF {
+				if marker, err = next(); err != nil {
+					return length, err
+				}
+			}
+		}
+
+		m := marker
+		marker = 0
+
+		switch {
+		case m == 0xD9:
+			return length, nil
+		case m == 0x01 || m >= 0xD0 && m <= 0xD7:
+			continue
+		}
+
+		hi, err := next()
+
+		if err != nil {
+			return length, err
+		}
+
+		lo, err := next()
+
+		if err != nil {
+			return length, err
+		}
+
+		segment := int(hi)<<8 | int(lo)
+
+		if segment < 2 {
+			return length, errors.New("meta: invalid jpeg segment length")
+		}
+
+		discarded, err := br.Discard(segment - 2)
+		length += int64(discarded)
+
+		if err != nil {
+			return length, err
+		}
+
+		if m != 0xDA {
+			continue
+		}
+
+		// Entropy coded data follows the start of scan segment, it ends with the next marker
+		// that isn't a byte stuffing or restart marker.
+		for marker == 0 {
+			b, err := next()
+
+			if err != nil {
+				return length, err
+			} else if b != 0xFF {
+				continue
+			}
+
+			for b == 0xFF {
+				if b, err = next(); err != nil {
+					return length, err
+				}
+			}
+
+			if b != 0x00 && (b < 0xD0 || b > 0xD7) {
+				marker = b
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package meta
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeRawFile creates a file with a JPEG preview of the given size between random data, like a RAW
+// file, and returns the encoded preview.
+func writeRawFile(t testing.TB, fileName string, width, height, padding int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 255})
+		}
+	}
+
+	var preview bytes.Buffer
+
+	if err := jpeg.Encode(&preview, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+
+	noise := make([]byte, padding)
+	rand.New(rand.NewSource(1)).Read(noise)
+
+	var data bytes.Buffer
+
+	data.Write([]byte("II*\x00"))
+	data.Write(noise)
+	data.Write(preview.Bytes())
+	data.Write(noise)
+
+	if err := ioutil.WriteFile(fileName, data.Bytes(), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	return preview.Bytes()
+}
+
+func TestEmbeddedPreview(t *testing.T) {
+	t.Run("ladybug.jpg", func(t *testing.T) {
+		preview, err := EmbeddedPreview("testdata/ladybug.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 160, preview.Width)
+		assert.Equal(t, 120, preview.Height)
+	})
+	t.Run("photoshop.jpg", func(t *testing.T) {
+		preview, err := EmbeddedPreview("testdata/photoshop.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 160, preview.Width)
+		assert.Equal(t, 120, preview.Height)
+	})
+	t.Run("gopro_hd2.jpg", func(t *testing.T) {
+		preview, err := EmbeddedPreview("testdata/gopro_hd2.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 160, preview.Width)
+		assert.Equal(t, 120, preview.Height)
+	})
+	t.Run("tweethog.png", func(t *testing.T) {
+		_, err := EmbeddedPreview("testdata/tweethog.png")
+
+		assert.EqualError(t, err, "meta: no embedded preview found")
+	})
+	t.Run("raw", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "preview")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(dir)
+
+		fileName := filepath.Join(dir, "preview.cr2")
+
+		// The preview crosses the boundary of the search buffer.
+		expected := writeRawFile(t, fileName, 640, 480, previewBufferSize-1000)
+
+		preview, err := EmbeddedPreview(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 640, preview.Width)
+		assert.Equal(t, 480, preview.Height)
+		assert.Equal(t, expected, preview.Data)
+	})
+	t.Run("ladybug.jpg data", func(t *testing.T) {
+		preview, err := EmbeddedPreview("testdata/ladybug.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := jpeg.Decode(bytes.NewReader(preview.Data))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 160, img.Bounds().Dx())
+	})
+}
+
+func TestEmbeddedPreviewAspect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "preview")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "preview.cr2")
+	writeRawFile(t, fileName, 640, 480, 1000)
+
+	t.Run("same aspect", func(t *testing.T) {
+		preview, err := EmbeddedPreviewAspect(fileName, 4000, 3000)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 640, preview.Width)
+	})
+	t.Run("rotated", func(t *testing.T) {
+		preview, err := EmbeddedPreviewAspect(fileName, 3000, 4000)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 480, preview.Height)
+	})
+	t.Run("letterboxed", func(t *testing.T) {
+		_, err := EmbeddedPreviewAspect(fileName, 1920, 1080)
+
+		assert.EqualError(t, err, "meta: no embedded preview found")
+	})
+	t.Run("unknown size", func(t *testing.T) {
+		preview, err := EmbeddedPreviewAspect(fileName, 0, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 640, preview.Width)
+	})
+}
+
+func TestSameAspect(t *testing.T) {
+	assert.True(t, sameAspect(160, 107, 6000, 4000))
+	assert.True(t, sameAspect(107, 160, 6000, 4000))
+	assert.False(t, sameAspect(160, 120, 6000, 4000))
+	assert.False(t, sameAspect(0, 120, 6000, 4000))
+	assert.True(t, sameAspect(160, 120, 0, 0))
+}
+
+func BenchmarkEmbeddedPreview(b *testing.B) {
+	dir, err := ioutil.TempDir("", "preview")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "preview.cr2")
+
+	writeRawFile(b, fileName, 1620, 1080, 12<<20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := EmbeddedPreview(fileName); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+	"gopkg.in/ugjka/go-tz.v2/tz"
+)
+
+// takeoutDateLayouts contains the formats of the human readable dates in Google Takeout sidecar files,
+// which depend on the language of the account they were exported from.
+var takeoutDateLayouts = []string{
+	"Jan 2, 2006, 3:04:05 PM MST",
+	"2 Jan 2006, 15:04:05 MST",
+	"02.01.2006, 15:04:05 MST",
+	"2006-01-02 15:04:05 MST",
+}
+
+// takeoutTime represents a date in a Google Takeout sidecar file. Timestamps are Unix seconds, encoded
+// as string in most exports.
+type takeoutTime struct {
+	Timestamp interface{} `json:"timestamp"`
+	Formatted string      `json:"formatted"`
+}
+
+// Time returns the date as UTC time, or a zero time if it's empty or invalid.
+func (t *takeoutTime) Time() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	var sec int64
+
+	switch v := t.Timestamp.(type) {
+	case string:
+		sec, _ = strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	case float64:
+		sec = int64(v)
+	}
+
+	if sec > 0 {
+		return time.Unix(sec, 0).UTC()
+	}
+
+	// Newer exports may contain non-breaking spaces instead of regular spaces.
+	formatted := strings.NewReplacer("\u202f", " ", "\u00a0", " ").Replace(strings.TrimSpace(t.Formatted))
+
+	for _, layout := range takeoutDateLayouts {
+		if result, err := time.Parse(layout, formatted); err == nil {
+			return result.UTC()
+		}
+	}
+
+	return time.Time{}
+}
+
+// takeoutGeo represents a position in a Google Takeout sidecar file.
+type takeoutGeo struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+}
+
+// Empty returns true if the position is missing, which Google exports as 0, 0.
+func (g *takeoutGeo) Empty() bool {
+	return g == nil || g.Latitude == 0 && g.Longitude == 0
+}
+
+// takeoutSidecar represents the metadata of a photo in a Google Takeout sidecar file.
+type takeoutSidecar struct {
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	PhotoTakenTime *takeoutTime `json:"photoTakenTime"`
+	CreationTime   *takeoutTime `json:"creationTime"`
+	GeoData        *takeoutGeo  `json:"geoData"`
+	GeoDataExif    *takeoutGeo  `json:"geoDataExif"`
+	People         []struct {
+		Name string `json:"name"`
+	} `json:"people"`
+}
+
+// Takeout parses a Google Takeout JSON sidecar file and returns a Data struct. People tagged in
+// the photo are returned as keywords.
+func Takeout(filename string) (data Data, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			data = Data{}
+			err = fmt.Errorf("meta: %s", e)
+		}
+	}()
+
+	b, err := ioutil.ReadFile(filename)
+
+	if err != nil {
+		return data, err
+	}
+
+	var doc takeoutSidecar
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return data, fmt.Errorf("meta: %s is not a valid takeout sidecar (%s)", filepath.Base(filename), err)
+	}
+
+	// Google uses the original file name as title, unless it was changed.
+	if title := strings.TrimSpace(doc.Title); title != "" && fs.GetFileType(title) == fs.TypeOther {
+		data.Title = title
+	}
+
+	data.Description = strings.TrimSpace(doc.Description)
+
+	if data.TakenAt = doc.PhotoTakenTime.Time(); data.TakenAt.IsZero() {
+		data.TakenAt = doc.CreationTime.Time()
+	}
+
+	geo := doc.GeoData
+
+	if geo.Empty() {
+		geo = doc.GeoDataExif
+	}
+
+	if !geo.Empty() {
+		data.Lat = float32(geo.Latitude)
+		data.Lng = float32(geo.Longitude)
+		data.Altitude = int(geo.Altitude)
+
+		if zones, err := tz.GetZone(tz.Point{Lat: geo.Latitude, Lon: geo.Longitude}); err == nil && len(zones) > 0 {
+			data.TimeZone = zones[0]
+		}
+	}
+
+	if !data.TakenAt.IsZero() && data.TimeZone != "" {
+		if loc, err := time.LoadLocation(data.TimeZone); err == nil {
+			// Local times are stored as UTC with the wall clock of the time zone, like Exif dates.
+			local := data.TakenAt.In(loc)
+			data.TakenAtLocal = time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+		}
+	}
+
+	var people []string
+
+	for _, p := range doc.People {
+		if name := strings.TrimSpace(p.Name); name != "" {
+			people = append(people, name)
+		}
+	}
+
+	data.Keywords = strings.Join(people, ", ")
+
+	return data, nil
+}
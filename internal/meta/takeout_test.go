@@ -0,0 +1,114 @@
+package meta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeout(t *testing.T) {
+	t.Run("IMG_1234.jpg.json", func(t *testing.T) {
+		data, err := Takeout("testdata/takeout/IMG_1234.jpg.json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "", data.Title)
+		assert.Equal(t, "Brandenburg Gate at night", data.Description)
+		assert.Equal(t, time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC), data.TakenAt)
+		assert.Equal(t, float32(52.5163), data.Lat)
+		assert.Equal(t, float32(13.3777), data.Lng)
+		assert.Equal(t, 34, data.Altitude)
+		assert.Equal(t, "Jens Mander, Anna Mander", data.Keywords)
+	})
+
+	t.Run("formatted.jpg.json", func(t *testing.T) {
+		data, err := Takeout("testdata/takeout/formatted.jpg.json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Sunset at the beach", data.Title)
+		assert.Equal(t, "", data.Description)
+		assert.Equal(t, time.Date(2019, 7, 4, 20, 30, 15, 0, time.UTC), data.TakenAt)
+		assert.True(t, data.TakenAtLocal.IsZero())
+		assert.Equal(t, "", data.TimeZone)
+		assert.Equal(t, float32(0), data.Lat)
+		assert.Equal(t, float32(0), data.Lng)
+		assert.Equal(t, "", data.Keywords)
+	})
+
+	t.Run("numeric.jpg.json", func(t *testing.T) {
+		data, err := Takeout("testdata/takeout/numeric.jpg.json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, time.Unix(1561000000, 0).UTC(), data.TakenAt)
+		assert.Equal(t, float32(48.8584), data.Lat)
+		assert.Equal(t, float32(2.2945), data.Lng)
+		assert.Equal(t, 35, data.Altitude)
+	})
+
+	t.Run("broken.jpg.json", func(t *testing.T) {
+		_, err := Takeout("testdata/takeout/broken.jpg.json")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("missing.jpg.json", func(t *testing.T) {
+		_, err := Takeout("testdata/takeout/missing.jpg.json")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestData_Merge(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		data := Data{CameraModel: "iPhone 7"}
+
+		data.Merge(Data{
+			TakenAt:     time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC),
+			TimeZone:    "Europe/Berlin",
+			Description: "Brandenburg Gate at night",
+			Keywords:    "Jens Mander",
+			Lat:         52.5163,
+			Lng:         13.3777,
+		})
+
+		assert.Equal(t, "iPhone 7", data.CameraModel)
+		assert.Equal(t, time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC), data.TakenAt)
+		assert.Equal(t, "Europe/Berlin", data.TimeZone)
+		assert.Equal(t, "Brandenburg Gate at night", data.Description)
+		assert.Equal(t, "Jens Mander", data.Keywords)
+		assert.Equal(t, float32(52.5163), data.Lat)
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		data := Data{
+			TakenAt:     time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC),
+			Description: "Exif",
+			Keywords:    "berlin",
+			Lat:         1,
+			Lng:         2,
+		}
+
+		data.Merge(Data{
+			TakenAt:     time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC),
+			Description: "Takeout",
+			Keywords:    "Jens Mander",
+			Lat:         52.5163,
+			Lng:         13.3777,
+		})
+
+		assert.Equal(t, time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC), data.TakenAt)
+		assert.Equal(t, "Exif", data.Description)
+		assert.Equal(t, "berlin, Jens Mander", data.Keywords)
+		assert.Equal(t, float32(1), data.Lat)
+		assert.Equal(t, float32(2), data.Lng)
+	})
+}
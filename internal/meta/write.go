@@ -0,0 +1,226 @@
+package meta
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ExifToolBin is the exiftool executable used to write metadata to image files.
+var ExifToolBin = ""
+
+// Write saves title, description, keywords, copyright and GPS coordinates to a file.
+// XMP sidecar files are created natively, all other formats require exiftool.
+func Write(filename string, data Data) error {
+	if strings.ToLower(filepath.Ext(filename)) == ".xmp" {
+		return WriteXMP(filename, data)
+	}
+
+	if ExifToolBin == "" {
+		return errors.New("meta: exiftool not found, can't write to original")
+	}
+
+	args := []string{
+		"-overwrite_original",
+		"-sep", ", ",
+		"-XMP-dc:Title=" + data.Title,
+		"-XMP-dc:Description=" + data.Description,
+		"-EXIF:ImageDescription=" + data.Description,
+		"-XMP-dc:Subject=" + data.Keywords,
+		"-IPTC:Keywords=" + data.Keywords,
+		"-EXIF:Copyright=" + data.Copyright,
+		"-XMP-dc:Rights=" + data.Copyright,
+	}
+
+	if data.Lat != 0 || data.Lng != 0 {
+		args = append(args,
+			fmt.Sprintf("-GPSLatitude=%f", math.Abs(float64(data.Lat))),
+			"-GPSLatitudeRef="+latRef(data.Lat),
+			fmt.Sprintf("-GPSLongitude=%f", math.Abs(float64(data.Lng))),
+			"-GPSLongitudeRef="+lngRef(data.Lng),
+		)
+	}
+
+	args = append(args, filename)
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(ExifToolBin, args...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("meta: %s (%s)", strings.TrimSpace(stderr.String()), err.Error())
+	}
+
+	return nil
+}
+
+// Strip returns the data of a file without meta data except for the orientation and copyright,
+// which is replaced if not empty. Requires exiftool, the file itself isn't changed.
+func Strip(filename string, copyright string) ([]byte, error) {
+	if ExifToolBin == "" {
+		return nil, errors.New("meta: exiftool not found, can't remove meta data")
+	}
+
+	args := []string{"-all=", "-tagsFromFile", "@", "-Orientation"}
+
+	if copyright != "" {
+		args = append(args, "-EXIF:Copyright="+copyright)
+	} else {
+		args = append(args, "-Copyright")
+	}
+
+	args = append(args, "-o", "-", filename)
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(ExifToolBin, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("meta: %s (%s)", strings.TrimSpace(stderr.String()), err.Error())
+	}
+
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("meta: can't remove meta data from %s", filepath.Base(filename))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+var xmpTemplate = template.Must(template.New("xmp").Funcs(template.FuncMap{"escape": xmpEscape}).Parse(
+	`<?xpacket begin="` + "\ufeff" + `" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/" x:xmptk="PhotoPrism">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:exif="http://ns.adobe.com/exif/1.0/">
+{{- if .Title}}
+   <dc:title>
+    <rdf:Alt>
+     <rdf:li xml:lang="x-default">{{escape .Title}}</rdf:li>
+    </rdf:Alt>
+   </dc:title>
+{{- end}}
+{{- if .Description}}
+   <dc:description>
+    <rdf:Alt>
+     <rdf:li xml:lang="x-default">{{escape .Description}}</rdf:li>
+    </rdf:Alt>
+   </dc:description>
+{{- end}}
+{{- if .Keywords}}
+   <dc:subject>
+    <rdf:Bag>
+{{- range .Keywords}}
+     <rdf:li>{{escape .}}</rdf:li>
+{{- end}}
+    </rdf:Bag>
+   </dc:subject>
+{{- end}}
+{{- if .Copyright}}
+   <dc:rights>
+    <rdf:Alt>
+     <rdf:li xml:lang="x-default">{{escape .Copyright}}</rdf:li>
+    </rdf:Alt>
+   </dc:rights>
+{{- end}}
+{{- if .Lat}}
+   <exif:GPSLatitude>{{.Lat}}</exif:GPSLatitude>
+   <exif:GPSLongitude>{{.Lng}}</exif:GPSLongitude>
+{{- end}}
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>
+`))
+
+// WriteXMP saves title, description, keywords, copyright and GPS coordinates as XMP sidecar file.
+func WriteXMP(filename string, data Data) error {
+	values := struct {
+		Title       string
+		Description string
+		Keywords    []string
+		Copyright   string
+		Lat         string
+		Lng         string
+	}{
+		Title:       data.Title,
+		Description: data.Description,
+		Keywords:    keywordList(data.Keywords),
+		Copyright:   data.Copyright,
+	}
+
+	if data.Lat != 0 || data.Lng != 0 {
+		values.Lat = xmpGPS(data.Lat, latRef(data.Lat))
+		values.Lng = xmpGPS(data.Lng, lngRef(data.Lng))
+	}
+
+	var buf bytes.Buffer
+
+	if err := xmpTemplate.Execute(&buf, values); err != nil {
+		return fmt.Errorf("meta: %s", err.Error())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// keywordList splits a comma separated keyword string.
+func keywordList(s string) (results []string) {
+	for _, w := range strings.Split(s, ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			results = append(results, w)
+		}
+	}
+
+	return results
+}
+
+// xmpEscape returns s with special XML characters escaped.
+func xmpEscape(s string) string {
+	var buf bytes.Buffer
+
+	if err := xml.EscapeText(&buf, []byte(s)); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// xmpGPS formats a coordinate as XMP GPS value with degrees and decimal minutes, e.g. "52,27.5814N".
+func xmpGPS(coord float32, ref string) string {
+	abs := math.Abs(float64(coord))
+	deg := math.Floor(abs)
+	min := (abs - deg) * 60
+
+	return fmt.Sprintf("%d,%.6f%s", int(deg), min, ref)
+}
+
+func latRef(lat float32) string {
+	if lat < 0 {
+		return "S"
+	}
+
+	return "N"
+}
+
+func lngRef(lng float32) string {
+	if lng < 0 {
+		return "W"
+	}
+
+	return "E"
+}
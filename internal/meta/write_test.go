@@ -0,0 +1,204 @@
+package meta
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteXMP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "meta")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	t.Run("round trip", func(t *testing.T) {
+		filename := filepath.Join(dir, "2020/01/photo.xmp")
+
+		data := Data{
+			Title:       "Night Shift & Friends",
+			Description: "Example <file> for development",
+			Keywords:    "berlin, night, shift",
+			Copyright:   "This is a legal notice",
+			Lat:         52.459690,
+			Lng:         -13.321831,
+		}
+
+		if err := WriteXMP(filename, data); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := XMP(filename)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, data.Title, result.Title)
+		assert.Equal(t, data.Description, result.Description)
+		assert.Equal(t, data.Keywords, result.Keywords)
+		assert.Equal(t, data.Copyright, result.Copyright)
+		assert.InDelta(t, data.Lat, result.Lat, 0.00001)
+		assert.InDelta(t, data.Lng, result.Lng, 0.00001)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		filename := filepath.Join(dir, "empty.xmp")
+
+		if err := Write(filename, Data{}); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := XMP(filename)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "", result.Title)
+		assert.Equal(t, float32(0), result.Lat)
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("exiftool missing", func(t *testing.T) {
+		bin := ExifToolBin
+		ExifToolBin = ""
+		defer func() { ExifToolBin = bin }()
+
+		err := Write("testdata/ladybug.jpg", Data{Title: "Ladybug"})
+
+		assert.EqualError(t, err, "meta: exiftool not found, can't write to original")
+	})
+
+	t.Run("exiftool", func(t *testing.T) {
+		bin, err := exec.LookPath("exiftool")
+
+		if err != nil {
+			t.Skip("exiftool not installed")
+		}
+
+		ExifToolBin = bin
+		defer func() { ExifToolBin = "" }()
+
+		dir, err := ioutil.TempDir("", "meta")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(dir)
+
+		filename := filepath.Join(dir, "ladybug.jpg")
+
+		if b, err := ioutil.ReadFile("testdata/ladybug.jpg"); err != nil {
+			t.Fatal(err)
+		} else if err := ioutil.WriteFile(filename, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		data := Data{Description: "Ladybug on a leaf", Copyright: "Gopher", Lat: 48.519234, Lng: 9.057997}
+
+		if err := Write(filename, data); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := Exif(filename)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, data.Description, result.Description)
+		assert.Equal(t, data.Copyright, result.Copyright)
+		assert.InDelta(t, data.Lat, result.Lat, 0.0001)
+		assert.InDelta(t, data.Lng, result.Lng, 0.0001)
+	})
+}
+
+func TestStrip(t *testing.T) {
+	t.Run("exiftool missing", func(t *testing.T) {
+		bin := ExifToolBin
+		ExifToolBin = ""
+		defer func() { ExifToolBin = bin }()
+
+		_, err := Strip("testdata/ladybug.jpg", "")
+
+		assert.EqualError(t, err, "meta: exiftool not found, can't remove meta data")
+	})
+
+	t.Run("exiftool", func(t *testing.T) {
+		bin, err := exec.LookPath("exiftool")
+
+		if err != nil {
+			t.Skip("exiftool not installed")
+		}
+
+		ExifToolBin = bin
+		defer func() { ExifToolBin = "" }()
+
+		dir, err := ioutil.TempDir("", "meta")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(dir)
+
+		filename := filepath.Join(dir, "ladybug.jpg")
+
+		if b, err := ioutil.ReadFile("testdata/ladybug.jpg"); err != nil {
+			t.Fatal(err)
+		} else if err := ioutil.WriteFile(filename, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Write(filename, Data{Description: "Ladybug on a leaf", Lat: 48.519234, Lng: 9.057997}); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := Strip(filename, "Gopher")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stripped := filepath.Join(dir, "stripped.jpg")
+
+		if err := ioutil.WriteFile(stripped, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := Exif(stripped)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "", result.Description)
+		assert.Equal(t, "Gopher", result.Copyright)
+		assert.Equal(t, float32(0), result.Lat)
+
+		// The original file is unchanged.
+		if original, err := Exif(filename); err != nil {
+			t.Fatal(err)
+		} else {
+			assert.Equal(t, "Ladybug on a leaf", original.Description)
+		}
+	})
+}
+
+func TestXmpCoordinate(t *testing.T) {
+	assert.InDelta(t, 52.45969, xmpCoordinate("52,27.5814N"), 0.00001)
+	assert.InDelta(t, -13.321831, xmpCoordinate("13,19.3099W"), 0.00001)
+	assert.InDelta(t, 52.459722, xmpCoordinate("52,27,35N"), 0.00001)
+	assert.Equal(t, float32(0), xmpCoordinate(""))
+	assert.Equal(t, float32(0), xmpCoordinate("abc"))
+}
@@ -23,9 +23,27 @@ func XMP(filename string) (data Data, err error) {
 	data.Artist = doc.Artist()
 	data.Description = doc.Description()
 	data.Copyright = doc.Copyright()
+	data.Keywords = doc.Keywords()
+	data.Lat = doc.Lat()
+	data.Lng = doc.Lng()
 	data.CameraMake = doc.CameraMake()
 	data.CameraModel = doc.CameraModel()
 	data.LensModel = doc.LensModel()
+	data.Orientation = doc.Orientation()
+	data.setFaceRegions(&doc)
 
 	return data, nil
 }
+
+// setFaceRegions sets the face regions of an XMP document as displayed, like width and height.
+// Malformed regions are logged and skipped.
+func (data *Data) setFaceRegions(doc *XmpDocument) {
+	regions, warnings := doc.FaceRegions()
+
+	for _, w := range warnings {
+		log.Warnf("meta: %s", w)
+		data.Warnings = append(data.Warnings, w)
+	}
+
+	data.FaceRegions = regions.Rotate(data.Orientation)
+}
@@ -3,6 +3,8 @@ package meta
 import (
 	"encoding/xml"
 	"io/ioutil"
+	"strconv"
+	"strings"
 )
 
 // XmpDocument represents an XMP sidecar file.
@@ -97,6 +99,7 @@ type XmpDocument struct {
 			} `xml:"BitsPerSample" json:"bitspersample,omitempty"`
 			PhotometricInterpretation string `xml:"PhotometricInterpretation"` // 2
 			Orientation               string `xml:"Orientation"`               // 0
+			OrientationAttr           string `xml:"Orientation,attr"`          // 6
 			SamplesPerPixel           string `xml:"SamplesPerPixel"`           // 3
 			YCbCrPositioning          string `xml:"YCbCrPositioning"`          // 1
 			XResolution               string `xml:"XResolution"`               // 72/1
@@ -188,6 +191,8 @@ type XmpDocument struct {
 					Li   string `xml:"li"` // Gopher
 				} `xml:"Bag" json:"bag,omitempty"`
 			} `xml:"PersonInImage" json:"personinimage,omitempty"`
+			Regions    xmpRegions    `xml:"Regions" json:"regions,omitempty"`
+			RegionInfo xmpRegionInfo `xml:"RegionInfo" json:"regioninfo,omitempty"`
 		} `xml:"Description" json:"description,omitempty"`
 	} `xml:"RDF" json:"rdf,omitempty"`
 }
@@ -202,6 +207,17 @@ func (doc *XmpDocument) Load(filename string) error {
 	return xml.Unmarshal(data, doc)
 }
 
+// Orientation returns the Exif orientation of the image, or 0 if unknown.
+func (doc *XmpDocument) Orientation() int {
+	for _, s := range []string{doc.RDF.Description.Orientation, doc.RDF.Description.OrientationAttr} {
+		if i, err := strconv.Atoi(s); err == nil && i >= 1 && i <= 8 {
+			return i
+		}
+	}
+
+	return 0
+}
+
 func (doc *XmpDocument) Title() string {
 	return doc.RDF.Description.Title.Alt.Li.Text
 }
@@ -229,3 +245,49 @@ func (doc *XmpDocument) CameraModel() string {
 func (doc *XmpDocument) LensModel() string {
 	return doc.RDF.Description.LensModel
 }
+
+func (doc *XmpDocument) Keywords() string {
+	return strings.Join(doc.RDF.Description.Subject.Bag.Li, ", ")
+}
+
+func (doc *XmpDocument) Lat() float32 {
+	return xmpCoordinate(doc.RDF.Description.GPSLatitude)
+}
+
+func (doc *XmpDocument) Lng() float32 {
+	return xmpCoordinate(doc.RDF.Description.GPSLongitude)
+}
+
+// xmpCoordinate converts an XMP GPS coordinate like "52,27.5814N" or "52,27,34.9E" to decimal degrees.
+func xmpCoordinate(s string) float32 {
+	s = strings.TrimSpace(s)
+
+	if len(s) < 2 {
+		return 0
+	}
+
+	ref := strings.ToUpper(s[len(s)-1:])
+	parts := strings.Split(s[:len(s)-1], ",")
+
+	var result float64
+
+	for i, div := range []float64{1, 60, 3600} {
+		if i >= len(parts) {
+			break
+		}
+
+		v, err := strconv.ParseFloat(parts[i], 64)
+
+		if err != nil {
+			return 0
+		}
+
+		result += v / div
+	}
+
+	if ref == "S" || ref == "W" {
+		result = -result
+	}
+
+	return float32(result)
+}
@@ -0,0 +1,172 @@
+package meta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// xmpRegions represents face regions of the Metadata Working Group scheme (mwg-rs:Regions), as
+// written by Picasa and digiKam. Values are either attributes or elements, depending on the writer.
+type xmpRegions struct {
+	RegionList struct {
+		Bag struct {
+			Li []struct {
+				xmpRegion
+				Description xmpRegion `xml:"Description"`
+			} `xml:"li"`
+		} `xml:"Bag"`
+	} `xml:"RegionList"`
+}
+
+type xmpRegion struct {
+	NameAttr string `xml:"Name,attr"`
+	Name     string `xml:"Name"`
+	TypeAttr string `xml:"Type,attr"`
+	Type     string `xml:"Type"`
+	Area     struct {
+		X    string `xml:"x,attr"`
+		Y    string `xml:"y,attr"`
+		W    string `xml:"w,attr"`
+		H    string `xml:"h,attr"`
+		Unit string `xml:"unit,attr"`
+	} `xml:"Area"`
+}
+
+// xmpRegionInfo represents face regions of the Microsoft Photo scheme (MP:RegionInfo), as written
+// by Windows Live Photo Gallery and digiKam.
+type xmpRegionInfo struct {
+	Regions struct {
+		Bag struct {
+			Li []struct {
+				xmpPersonRegion
+				Description xmpPersonRegion `xml:"Description"`
+			} `xml:"li"`
+		} `xml:"Bag"`
+	} `xml:"Regions"`
+}
+
+type xmpPersonRegion struct {
+	NameAttr      string `xml:"PersonDisplayName,attr"`
+	Name          string `xml:"PersonDisplayName"`
+	RectangleAttr string `xml:"Rectangle,attr"`
+	Rectangle     string `xml:"Rectangle"`
+}
+
+// FaceRegions returns the named face regions of the image as stored, without applying the orientation.
+// Regions of the MWG scheme are preferred, as writers like digiKam add the same regions in both schemes.
+// Malformed regions are skipped and returned as warnings.
+func (doc *XmpDocument) FaceRegions() (result FaceRegions, warnings []string) {
+	for _, li := range doc.RDF.Description.Regions.RegionList.Bag.Li {
+		r := li.xmpRegion
+
+		if li.Description != (xmpRegion{}) {
+			r = li.Description
+		}
+
+		if t := r.regionType(); t != "" && !strings.EqualFold(t, "Face") {
+			continue
+		}
+
+		if region, err := r.faceRegion(); err != nil {
+			warnings = append(warnings, err.Error())
+		} else {
+			result = append(result, region)
+		}
+	}
+
+	if len(result) > 0 {
+		return result, warnings
+	}
+
+	for _, li := range doc.RDF.Description.RegionInfo.Regions.Bag.Li {
+		r := li.xmpPersonRegion
+
+		if li.Description != (xmpPersonRegion{}) {
+			r = li.Description
+		}
+
+		if region, err := r.faceRegion(); err != nil {
+			warnings = append(warnings, err.Error())
+		} else {
+			result = append(result, region)
+		}
+	}
+
+	return result, warnings
+}
+
+func (r xmpRegion) name() string {
+	if r.NameAttr != "" {
+		return r.NameAttr
+	}
+
+	return r.Name
+}
+
+func (r xmpRegion) regionType() string {
+	if r.TypeAttr != "" {
+		return r.TypeAttr
+	}
+
+	return r.Type
+}
+
+// faceRegion converts the area, whose position is the center of the region, to a face region.
+func (r xmpRegion) faceRegion() (FaceRegion, error) {
+	if unit := r.Area.Unit; unit != "" && unit != "normalized" {
+		return FaceRegion{}, fmt.Errorf("face region \"%s\" has unsupported unit %s", r.name(), unit)
+	}
+
+	v, err := xmpFloats(r.Area.X, r.Area.Y, r.Area.W, r.Area.H)
+
+	if err != nil {
+		return FaceRegion{}, fmt.Errorf("face region \"%s\" has invalid area (%s)", r.name(), err)
+	}
+
+	return NewFaceRegion(r.name(), v[0]-v[2]/2, v[1]-v[3]/2, v[2], v[3])
+}
+
+func (r xmpPersonRegion) name() string {
+	if r.NameAttr != "" {
+		return r.NameAttr
+	}
+
+	return r.Name
+}
+
+// faceRegion converts the rectangle, whose position is the top left corner of the region, to a face region.
+func (r xmpPersonRegion) faceRegion() (FaceRegion, error) {
+	rect := r.RectangleAttr
+
+	if rect == "" {
+		rect = r.Rectangle
+	}
+
+	values := strings.Split(rect, ",")
+
+	if len(values) != 4 {
+		return FaceRegion{}, fmt.Errorf("face region \"%s\" has invalid rectangle \"%s\"", r.name(), rect)
+	}
+
+	v, err := xmpFloats(values...)
+
+	if err != nil {
+		return FaceRegion{}, fmt.Errorf("face region \"%s\" has invalid rectangle (%s)", r.name(), err)
+	}
+
+	return NewFaceRegion(r.name(), v[0], v[1], v[2], v[3])
+}
+
+// xmpFloats parses XMP real values.
+func xmpFloats(values ...string) (result []float64, err error) {
+	result = make([]float64, len(values))
+
+	for i, s := range values {
+		if result[i], err = strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -55,4 +55,63 @@ func TestXMP(t *testing.T) {
 		assert.Equal(t, "iPhone 7 back camera 3.99mm f/1.8", data.LensModel)
 	})
 
+	t.Run("picasa", func(t *testing.T) {
+		data, err := XMP("testdata/picasa.xmp")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Regions of other types than Face are ignored.
+		assert.Len(t, data.FaceRegions, 2)
+		assert.Empty(t, data.Warnings)
+		assertFaceRegion(t, FaceRegion{Name: "Jane Doe", X: 0.2, Y: 0.2, W: 0.1, H: 0.2}, data.FaceRegions[0])
+		assertFaceRegion(t, FaceRegion{Name: "John Doe", X: 0.6, Y: 0.3, W: 0.2, H: 0.3}, data.FaceRegions[1])
+	})
+
+	t.Run("digikam", func(t *testing.T) {
+		data, err := XMP("testdata/digikam.xmp")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The same region is written in both schemes and rotated by 90 degrees as displayed.
+		assert.Equal(t, 6, data.Orientation)
+		assert.Len(t, data.FaceRegions, 1)
+		assertFaceRegion(t, FaceRegion{Name: "Erika Mustermann", X: 0.7, Y: 0.1, W: 0.1, H: 0.2}, data.FaceRegions[0])
+	})
+
+	t.Run("windows_photo", func(t *testing.T) {
+		data, err := XMP("testdata/windows_photo.xmp")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, data.FaceRegions, 1)
+		assertFaceRegion(t, FaceRegion{Name: "Max Mustermann", X: 0.5, Y: 0.1, W: 0.25, H: 0.3}, data.FaceRegions[0])
+	})
+
+	t.Run("malformed_regions", func(t *testing.T) {
+		data, err := XMP("testdata/malformed_regions.xmp")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, data.FaceRegions, 1)
+		assertFaceRegion(t, FaceRegion{Name: "Max Mustermann", X: 0.4, Y: 0.4, W: 0.2, H: 0.2}, data.FaceRegions[0])
+
+		assert.Equal(t, []string{
+			"face region \"Jane Doe\" has invalid area (strconv.ParseFloat: parsing \"abc\": invalid syntax)",
+			"face region \"John Doe\" outside image",
+			"face region without name",
+		}, data.Warnings)
+	})
+}
+
+func assertFaceRegion(t *testing.T, expected, actual FaceRegion) {
+	assert.Equal(t, expected.Name, actual.Name)
+	assert.InDeltaSlice(t, []float64{expected.X, expected.Y, expected.W, expected.H}, []float64{actual.X, actual.Y, actual.W, actual.H}, 0.0001)
 }
@@ -0,0 +1,110 @@
+package mutex
+
+import (
+	"sync"
+	"time"
+)
+
+// Interactive tracks API requests in progress, so that background workers can pause while the server
+// is busy serving users.
+var Interactive = NewActivity()
+
+// ActivityPoll is the interval in which paused workers check again if they may continue.
+var ActivityPoll = 100 * time.Millisecond
+
+// ActivityMaxPause is the maximum time workers pause at once. Afterwards, they may continue for the same
+// duration without pausing, so that constant browsing can't keep them waiting forever.
+var ActivityMaxPause = 30 * time.Second
+
+// Activity represents a load signal that is busy while requests are in progress, until an idle delay
+// has passed after the last one, or when an optional load function like a CPU threshold reports so.
+type Activity struct {
+	mutex    sync.Mutex
+	disabled bool
+	active   int
+	last     time.Time
+	delay    time.Duration
+	load     func() bool
+	resume   time.Time
+}
+
+// NewActivity returns a load signal with an idle delay of 2 seconds.
+func NewActivity() *Activity {
+	return &Activity{delay: 2 * time.Second}
+}
+
+// Configure sets the idle delay after the last request and an optional load function. Background workers
+// never pause if the delay is negative.
+func (a *Activity) Configure(delay time.Duration, load func() bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.disabled = delay < 0
+	a.delay = delay
+	a.load = load
+}
+
+// Start marks the beginning of a request.
+func (a *Activity) Start() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.active++
+}
+
+// Done marks the end of a request.
+func (a *Activity) Done() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.active > 0 {
+		a.active--
+	}
+
+	a.last = time.Now()
+}
+
+// Busy returns true if background workers should pause.
+func (a *Activity) Busy() bool {
+	a.mutex.Lock()
+	disabled, active, last, delay, load := a.disabled, a.active, a.last, a.delay, a.load
+	a.mutex.Unlock()
+
+	if disabled {
+		return false
+	}
+
+	if active > 0 || !last.IsZero() && time.Since(last) < delay {
+		return true
+	}
+
+	return load != nil && load()
+}
+
+// Pause blocks while the server is busy and returns how long it waited. It returns as soon as
+// canceled reports true, e.g. after a worker was canceled or the server is shutting down, and
+// after ActivityMaxPause, in which case workers won't pause again for the same duration.
+func (a *Activity) Pause(canceled func() bool) time.Duration {
+	start := time.Now()
+
+	a.mutex.Lock()
+	resume := a.resume
+	a.mutex.Unlock()
+
+	if start.Before(resume) {
+		return 0
+	}
+
+	for a.Busy() && !canceled() {
+		if time.Since(start) >= ActivityMaxPause {
+			a.mutex.Lock()
+			a.resume = time.Now().Add(ActivityMaxPause)
+			a.mutex.Unlock()
+			break
+		}
+
+		time.Sleep(ActivityPoll)
+	}
+
+	return time.Since(start)
+}
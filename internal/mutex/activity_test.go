@@ -0,0 +1,118 @@
+package mutex
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivity_Busy(t *testing.T) {
+	t.Run("requests", func(t *testing.T) {
+		a := NewActivity()
+		a.Configure(50*time.Millisecond, nil)
+
+		assert.False(t, a.Busy())
+
+		a.Start()
+		assert.True(t, a.Busy())
+
+		a.Done()
+		assert.True(t, a.Busy())
+
+		time.Sleep(60 * time.Millisecond)
+		assert.False(t, a.Busy())
+	})
+	t.Run("load", func(t *testing.T) {
+		a := NewActivity()
+		a.Configure(0, func() bool { return true })
+
+		assert.True(t, a.Busy())
+	})
+	t.Run("disabled", func(t *testing.T) {
+		a := NewActivity()
+		a.Configure(-1, func() bool { return true })
+		a.Start()
+
+		assert.False(t, a.Busy())
+	})
+}
+
+func TestActivity_Pause(t *testing.T) {
+	poll := ActivityPoll
+	ActivityPoll = 5 * time.Millisecond
+	defer func() { ActivityPoll = poll }()
+
+	var busy int32
+
+	a := NewActivity()
+	a.Configure(0, func() bool { return atomic.LoadInt32(&busy) == 1 })
+
+	// work processes files like a background worker for the given duration and returns their number.
+	work := func(d time.Duration) (files int) {
+		for start := time.Now(); time.Since(start) < d; files++ {
+			a.Pause(func() bool { return false })
+			time.Sleep(time.Millisecond)
+		}
+
+		return files
+	}
+
+	t.Run("throttled", func(t *testing.T) {
+		idle := work(30 * time.Millisecond)
+
+		atomic.StoreInt32(&busy, 1)
+
+		go func() {
+			time.Sleep(60 * time.Millisecond)
+			atomic.StoreInt32(&busy, 0)
+		}()
+
+		throttled := work(30 * time.Millisecond)
+		resumed := work(30 * time.Millisecond)
+
+		assert.Equal(t, 1, throttled)
+		assert.Greater(t, idle, throttled)
+		assert.Greater(t, resumed, throttled)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		atomic.StoreInt32(&busy, 1)
+		defer atomic.StoreInt32(&busy, 0)
+
+		var canceled int32
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			atomic.StoreInt32(&canceled, 1)
+		}()
+
+		paused := a.Pause(func() bool { return atomic.LoadInt32(&canceled) == 1 })
+
+		assert.True(t, paused >= 20*time.Millisecond)
+		assert.True(t, paused < time.Second)
+	})
+	t.Run("max pause", func(t *testing.T) {
+		maxPause := ActivityMaxPause
+		ActivityMaxPause = 20 * time.Millisecond
+		defer func() { ActivityMaxPause = maxPause }()
+
+		atomic.StoreInt32(&busy, 1)
+		defer atomic.StoreInt32(&busy, 0)
+
+		b := NewActivity()
+		b.Configure(0, func() bool { return atomic.LoadInt32(&busy) == 1 })
+
+		paused := b.Pause(func() bool { return false })
+
+		assert.True(t, paused >= 20*time.Millisecond)
+		assert.True(t, paused < time.Second)
+
+		// Workers continue without pausing for the same duration.
+		assert.Equal(t, time.Duration(0), b.Pause(func() bool { return false }))
+
+		time.Sleep(25 * time.Millisecond)
+
+		assert.True(t, b.Pause(func() bool { return false }) >= 20*time.Millisecond)
+	})
+}
@@ -1,6 +1,7 @@
 package mutex
 
 import (
+	"context"
 	"errors"
 	"sync"
 )
@@ -8,6 +9,7 @@ import (
 type Busy struct {
 	busy     bool
 	canceled bool
+	done     chan struct{}
 	mutex    sync.Mutex
 }
 
@@ -32,6 +34,7 @@ func (b *Busy) Start() error {
 
 	b.busy = true
 	b.canceled = false
+	b.done = make(chan struct{})
 
 	return nil
 }
@@ -42,14 +45,16 @@ func (b *Busy) Stop() {
 
 	b.busy = false
 	b.canceled = false
+	b.done = nil
 }
 
 func (b *Busy) Cancel() {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	if b.busy {
+	if b.busy && !b.canceled {
 		b.canceled = true
+		close(b.done)
 	}
 }
 
@@ -59,3 +64,27 @@ func (b *Busy) Canceled() bool {
 
 	return b.canceled
 }
+
+// Context returns a context that is canceled with the running operation, e.g. to stop external
+// commands. The cancel function must be called to release its resources once the operation is done.
+func (b *Busy) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	b.mutex.Lock()
+	done := b.done
+	b.mutex.Unlock()
+
+	ctx, cancel := context.WithCancel(parent)
+
+	if done == nil {
+		return ctx, cancel
+	}
+
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
@@ -1,7 +1,9 @@
 package mutex
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -21,3 +23,38 @@ func TestBusy_Busy(t *testing.T) {
 	assert.False(t, b.Canceled())
 	assert.False(t, b.Busy())
 }
+
+func TestBusy_Context(t *testing.T) {
+	t.Run("canceled", func(t *testing.T) {
+		b := Busy{}
+
+		assert.Nil(t, b.Start())
+		defer b.Stop()
+
+		ctx, cancel := b.Context(context.Background())
+		defer cancel()
+
+		assert.Nil(t, ctx.Err())
+
+		b.Cancel()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("context not canceled")
+		}
+
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+	t.Run("not running", func(t *testing.T) {
+		b := Busy{}
+
+		ctx, cancel := b.Context(context.Background())
+
+		b.Cancel()
+		assert.Nil(t, ctx.Err())
+
+		cancel()
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+}
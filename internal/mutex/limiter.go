@@ -0,0 +1,88 @@
+package mutex
+
+import (
+	"context"
+	"sync"
+)
+
+// Limiter limits the number of concurrent calls, e.g. to render thumbnails on demand.
+// Concurrent calls with the same key are merged, so that a single call serves all callers.
+type Limiter struct {
+	slots chan struct{}
+	mutex sync.Mutex
+	calls map[string]*limiterCall
+}
+
+type limiterCall struct {
+	done     chan struct{}
+	result   string
+	err      error
+	canceled bool // fn didn't run, as the context of the caller was done.
+}
+
+// NewLimiter returns a limiter that runs at most n calls at the same time.
+func NewLimiter(n int) *Limiter {
+	if n < 1 {
+		n = 1
+	}
+
+	return &Limiter{
+		slots: make(chan struct{}, n),
+		calls: make(map[string]*limiterCall),
+	}
+}
+
+// Do runs fn once a slot is available and returns its result. If a call with the same key is
+// already waiting or running, Do waits for its result instead and shared is true.
+// The context error is returned if ctx is done before a result is available. Once fn
+// is running, it runs to completion so that the result can still be used by others.
+// If a shared call was given up because the context of its caller was done, waiting
+// callers try again with their own context.
+func (l *Limiter) Do(ctx context.Context, key string, fn func() (string, error)) (result string, shared bool, err error) {
+	for {
+		l.mutex.Lock()
+
+		c, ok := l.calls[key]
+
+		if !ok {
+			break
+		}
+
+		l.mutex.Unlock()
+
+		select {
+		case <-c.done:
+			if !c.canceled || ctx.Err() != nil {
+				return c.result, true, c.err
+			}
+		case <-ctx.Done():
+			return "", true, ctx.Err()
+		}
+	}
+
+	c := &limiterCall{done: make(chan struct{})}
+	l.calls[key] = c
+	l.mutex.Unlock()
+
+	select {
+	case l.slots <- struct{}{}:
+		c.result, c.err = fn()
+		<-l.slots
+	case <-ctx.Done():
+		c.err = ctx.Err()
+		c.canceled = true
+	}
+
+	l.mutex.Lock()
+	delete(l.calls, key)
+	l.mutex.Unlock()
+
+	close(c.done)
+
+	return c.result, false, c.err
+}
+
+// Size returns the max number of concurrent calls.
+func (l *Limiter) Size() int {
+	return cap(l.slots)
+}
@@ -0,0 +1,182 @@
+package mutex
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Do(t *testing.T) {
+	t.Run("same key", func(t *testing.T) {
+		l := NewLimiter(2)
+
+		var calls int32
+		var wg sync.WaitGroup
+
+		release := make(chan struct{})
+		results := make([]string, 20)
+
+		for i := range results {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				results[i], _, _ = l.Do(context.Background(), "abc/tile_500", func() (string, error) {
+					atomic.AddInt32(&calls, 1)
+					<-release
+					return "abc_500x500_center.jpg", nil
+				})
+			}(i)
+		}
+
+		// Give all goroutines a chance to wait for the first call.
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls)
+
+		for _, r := range results {
+			assert.Equal(t, "abc_500x500_center.jpg", r)
+		}
+	})
+	t.Run("concurrency", func(t *testing.T) {
+		l := NewLimiter(3)
+
+		var running, max int32
+		var wg sync.WaitGroup
+
+		for i := 0; i < 12; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				_, shared, err := l.Do(context.Background(), fmt.Sprintf("file%d", i), func() (string, error) {
+					n := atomic.AddInt32(&running, 1)
+
+					for {
+						m := atomic.LoadInt32(&max)
+
+						if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+
+					return "", nil
+				})
+
+				assert.False(t, shared)
+				assert.Nil(t, err)
+			}(i)
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 3, l.Size())
+		assert.LessOrEqual(t, max, int32(3))
+	})
+	t.Run("timeout", func(t *testing.T) {
+		l := NewLimiter(1)
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		go l.Do(context.Background(), "slow", func() (string, error) {
+			close(started)
+			<-release
+			return "", nil
+		})
+
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, shared, err := l.Do(ctx, "other", func() (string, error) {
+			t.Error("must not run")
+			return "", nil
+		})
+
+		assert.False(t, shared)
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		_, shared, err = l.Do(ctx, "slow", func() (string, error) {
+			t.Error("must not run")
+			return "", nil
+		})
+
+		assert.True(t, shared)
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		close(release)
+	})
+	t.Run("first caller canceled", func(t *testing.T) {
+		l := NewLimiter(1)
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		// Block the only slot, so that the first call for "photo" is waiting.
+		go l.Do(context.Background(), "busy", func() (string, error) {
+			close(started)
+			<-release
+			return "", nil
+		})
+
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := make(chan error)
+
+		go func() {
+			_, _, err := l.Do(ctx, "photo", func() (string, error) {
+				t.Error("must not run")
+				return "", nil
+			})
+
+			first <- err
+		}()
+
+		waiting := make(chan string)
+
+		go func() {
+			// Wait until the first call was registered.
+			time.Sleep(20 * time.Millisecond)
+
+			result, _, err := l.Do(context.Background(), "photo", func() (string, error) {
+				return "rendered", nil
+			})
+
+			assert.NoError(t, err)
+			waiting <- result
+		}()
+
+		time.Sleep(40 * time.Millisecond)
+		cancel()
+
+		assert.Equal(t, context.Canceled, <-first)
+
+		close(release)
+
+		// The other caller doesn't get the context error of the first, but runs the call itself.
+		assert.Equal(t, "rendered", <-waiting)
+	})
+	t.Run("error", func(t *testing.T) {
+		l := NewLimiter(0)
+
+		_, _, err := l.Do(context.Background(), "broken", func() (string, error) {
+			return "", fmt.Errorf("can't decode")
+		})
+
+		assert.EqualError(t, err, "can't decode")
+		assert.Equal(t, 1, l.Size())
+	})
+}
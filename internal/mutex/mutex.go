@@ -6,6 +6,7 @@ import (
 
 var (
 	Db     = sync.Mutex{}
+	Batch  = sync.Mutex{}
 	Worker = Busy{}
 	Sync   = Busy{}
 	Share  = Busy{}
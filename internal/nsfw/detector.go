@@ -28,6 +28,11 @@ func New(modelPath string) *Detector {
 	return &Detector{modelPath: modelPath, modelTags: []string{"serve"}}
 }
 
+// Init loads the model, so that it doesn't need to be loaded when the first image is checked.
+func (t *Detector) Init() error {
+	return t.loadModel()
+}
+
 // File returns matching labels for a jpeg media file.
 func (t *Detector) File(filename string) (result Labels, err error) {
 	if fs.MimeType(filename) != "image/jpeg" {
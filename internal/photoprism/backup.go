@@ -0,0 +1,476 @@
+package photoprism
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// BackupSchema is the version of the backup archive format, it must be increased if tables
+// or columns change in a way that prevents older backups from being restored.
+const BackupSchema = 1
+
+// BackupManifestName is the name of the manifest file in a backup archive.
+const BackupManifestName = "backup.json"
+
+// ErrBackupNotEmpty is returned when restoring a backup into a database that already contains an index.
+var ErrBackupNotEmpty = errors.New("restore: database is not empty, use force to overwrite it")
+
+// BackupManifest describes the contents of a backup archive.
+type BackupManifest struct {
+	Schema  int            `json:"schema"`
+	Version string         `json:"version"`
+	Created time.Time      `json:"created"`
+	Tables  map[string]int `json:"tables"`
+}
+
+// backupTable describes how a table is backed up and restored.
+type backupTable struct {
+	name   string            // table name
+	autoID bool              // auto-increment ids get remapped on restore
+	refs   map[string]string // columns with ids of other tables that get remapped
+}
+
+// backupRefs contains the columns with ids of other tables by table name, they get remapped on
+// restore. Tables are restored after the tables they reference.
+var backupRefs = map[string]map[string]string{
+	"photos":          {"camera_id": "cameras", "lens_id": "lenses"},
+	"descriptions":    {"photo_id": "photos"},
+	"countries":       {"country_photo_id": "photos"},
+	"files":           {"photo_id": "photos"},
+	"files_share":     {"file_id": "files", "account_id": "accounts"},
+	"files_sync":      {"file_id": "files", "account_id": "accounts"},
+	"photos_keywords": {"photo_id": "photos", "keyword_id": "keywords"},
+	"categories":      {"label_id": "labels", "category_id": "labels"},
+	"photos_labels":   {"photo_id": "photos", "label_id": "labels"},
+}
+
+// backupSkip contains tables that are not backed up. Sessions expire anyway, migrations belong to
+// the schema of the target database and runs only store the progress of interrupted imports.
+var backupSkip = map[string]bool{
+	"sessions":   true,
+	"migrations": true,
+	"runs":       true,
+}
+
+// backupKeepIDs contains tables with auto-increment ids that are preserved, because the admin
+// account has a fixed user id.
+var backupKeepIDs = map[string]bool{
+	"users": true,
+}
+
+// backupTables returns all backed up entity tables in restore order, so that referenced ids
+// were remapped before they are used.
+func backupTables(db *gorm.DB) (result []backupTable) {
+	var pending []backupTable
+
+	for _, model := range entity.Entities {
+		scope := db.NewScope(model)
+		name := scope.TableName()
+
+		if backupSkip[name] {
+			continue
+		}
+
+		t := backupTable{name: name, refs: backupRefs[name]}
+
+		if fields := scope.PrimaryFields(); len(fields) == 1 && fields[0].DBName == "id" && !backupKeepIDs[name] {
+			switch fields[0].Struct.Type.Kind() {
+			case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+				t.autoID = true
+			}
+		}
+
+		pending = append(pending, t)
+	}
+
+	done := make(map[string]bool, len(pending))
+
+	for len(pending) > 0 {
+		var next []backupTable
+
+		for _, t := range pending {
+			ready := true
+
+			for _, ref := range t.refs {
+				if !done[ref] {
+					ready = false
+				}
+			}
+
+			if ready {
+				result = append(result, t)
+				done[t.name] = true
+			} else {
+				next = append(next, t)
+			}
+		}
+
+		// Tables referencing each other or unknown tables are restored last.
+		if len(next) == len(pending) {
+			return append(result, next...)
+		}
+
+		pending = next
+	}
+
+	return result
+}
+
+// backupIndexTables are checked to find out if a database already contains an index.
+var backupIndexTables = []interface{}{&entity.Photo{}, &entity.File{}, &entity.Album{}, &entity.Label{}}
+
+// backupTimeFormat is understood by both, MySQL and SQLite.
+const backupTimeFormat = "2006-01-02 15:04:05"
+
+// Backup writes all tables as newline-delimited JSON into a zip archive. All tables are read in a
+// single transaction, so the backup is consistent even if the index is changed at the same time.
+func Backup(db *gorm.DB, w io.Writer, version string) (*BackupManifest, error) {
+	opt := &sql.TxOptions{}
+
+	if db.Dialect().GetName() == "mysql" {
+		opt.Isolation = sql.LevelRepeatableRead
+	}
+
+	tx, err := db.DB().BeginTx(context.Background(), opt)
+
+	if err != nil {
+		return nil, fmt.Errorf("backup: %s", err)
+	}
+
+	defer tx.Rollback()
+
+	manifest := &BackupManifest{
+		Schema:  BackupSchema,
+		Version: version,
+		Created: time.Now().UTC(),
+		Tables:  make(map[string]int),
+	}
+
+	archive := zip.NewWriter(w)
+
+	for _, t := range backupTables(db) {
+		name := t.name
+
+		f, err := archive.Create(name + ".jsonl")
+
+		if err != nil {
+			return nil, fmt.Errorf("backup: %s", err)
+		}
+
+		count, err := backupRows(db, tx, t, name, f)
+
+		if err != nil {
+			return nil, fmt.Errorf("backup: %s (%s)", err, name)
+		}
+
+		manifest.Tables[name] = count
+
+		log.Debugf("backup: %d rows in %s", count, name)
+	}
+
+	f, err := archive.Create(BackupManifestName)
+
+	if err != nil {
+		return nil, fmt.Errorf("backup: %s", err)
+	}
+
+	if err := json.NewEncoder(f).Encode(manifest); err != nil {
+		return nil, fmt.Errorf("backup: %s", err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("backup: %s", err)
+	}
+
+	return manifest, nil
+}
+
+// backupRows writes all rows of a table as JSON objects, one per line.
+func backupRows(db *gorm.DB, tx *sql.Tx, t backupTable, name string, w io.Writer) (count int, err error) {
+	query := fmt.Sprintf("SELECT * FROM %s", db.Dialect().Quote(name))
+
+	if t.autoID {
+		// Restored rows get new ids in the same order.
+		query += " ORDER BY id"
+	}
+
+	rows, err := tx.Query(query)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+
+	if err != nil {
+		return 0, err
+	}
+
+	enc := json.NewEncoder(w)
+
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return count, err
+		}
+
+		row := make(map[string]interface{}, len(cols))
+
+		for i, col := range cols {
+			switch v := values[i].(type) {
+			case []byte:
+				row[col] = string(v)
+			case time.Time:
+				row[col] = v.UTC().Format(backupTimeFormat)
+			default:
+				row[col] = v
+			}
+		}
+
+		if err := enc.Encode(row); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, rows.Err()
+}
+
+// Restore imports a backup archive into a database, it refuses to overwrite an existing index
+// unless force is true. Auto-increment ids are remapped while UUIDs are preserved.
+func Restore(db *gorm.DB, fileName string, force bool) (*BackupManifest, error) {
+	archive, err := zip.OpenReader(fileName)
+
+	if err != nil {
+		return nil, fmt.Errorf("restore: %s", err)
+	}
+
+	defer archive.Close()
+
+	files := make(map[string]*zip.File, len(archive.File))
+
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	manifest := &BackupManifest{}
+
+	if f, ok := files[BackupManifestName]; !ok {
+		return nil, fmt.Errorf("restore: %s not found in %s", BackupManifestName, fileName)
+	} else if err := readBackupFile(f, func(dec *json.Decoder) error { return dec.Decode(manifest) }); err != nil {
+		return nil, fmt.Errorf("restore: %s", err)
+	}
+
+	if manifest.Schema < 1 || manifest.Schema > BackupSchema {
+		return nil, fmt.Errorf("restore: unsupported backup schema %d", manifest.Schema)
+	}
+
+	if !force {
+		for _, model := range backupIndexTables {
+			count := 0
+
+			if err := db.Unscoped().Model(model).Count(&count).Error; err != nil {
+				return nil, fmt.Errorf("restore: %s", err)
+			} else if count > 0 {
+				return nil, ErrBackupNotEmpty
+			}
+		}
+	}
+
+	tx, err := db.DB().Begin()
+
+	if err != nil {
+		return nil, fmt.Errorf("restore: %s", err)
+	}
+
+	defer tx.Rollback()
+
+	tables := backupTables(db)
+
+	// Default rows like the unknown camera are part of the backup as well.
+	for _, t := range tables {
+		name := t.name
+
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", db.Dialect().Quote(name))); err != nil {
+			return nil, fmt.Errorf("restore: %s (%s)", err, name)
+		}
+	}
+
+	ids := make(map[string]map[int64]int64)
+
+	for _, t := range tables {
+		name := t.name
+
+		f, ok := files[name+".jsonl"]
+
+		if !ok {
+			log.Warnf("restore: %s not found in backup", name)
+			continue
+		}
+
+		count, err := restoreRows(db, tx, t, name, f, ids)
+
+		if err != nil {
+			return nil, fmt.Errorf("restore: %s (%s)", err, name)
+		}
+
+		log.Debugf("restore: %d rows in %s", count, name)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("restore: %s", err)
+	}
+
+	return manifest, nil
+}
+
+// restoreRows inserts all rows of a table and remembers the new auto-increment ids.
+func restoreRows(db *gorm.DB, tx *sql.Tx, t backupTable, name string, f *zip.File, ids map[string]map[int64]int64) (count int, err error) {
+	columns, err := restoreColumns(db, tx, name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if t.autoID {
+		ids[name] = make(map[int64]int64)
+	}
+
+	err = readBackupFile(f, func(dec *json.Decoder) error {
+		for dec.More() {
+			row := make(map[string]interface{})
+
+			if err := dec.Decode(&row); err != nil {
+				return err
+			}
+
+			var cols, params []string
+			var args []interface{}
+
+			for _, col := range columns {
+				value, ok := row[col]
+
+				if !ok || t.autoID && col == "id" {
+					continue
+				}
+
+				if n, ok := value.(json.Number); ok {
+					value = backupNumber(n)
+				}
+
+				if ref, ok := t.refs[col]; ok {
+					value = remapID(ids[ref], value)
+				}
+
+				cols = append(cols, db.Dialect().Quote(col))
+				params = append(params, "?")
+				args = append(args, value)
+			}
+
+			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", db.Dialect().Quote(name), strings.Join(cols, ", "), strings.Join(params, ", "))
+
+			result, err := tx.Exec(query, args...)
+
+			if err != nil {
+				return err
+			}
+
+			if t.autoID {
+				n, _ := row["id"].(json.Number)
+				oldID, ok := backupNumber(n).(int64)
+
+				if !ok {
+					return fmt.Errorf("invalid id %s", row["id"])
+				}
+
+				newID, err := result.LastInsertId()
+
+				if err != nil {
+					return err
+				}
+
+				ids[name][oldID] = newID
+			}
+
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// restoreColumns returns the column names of a table in the target database, so that
+// backups of older versions can be restored after columns were added or removed.
+func restoreColumns(db *gorm.DB, tx *sql.Tx, name string) ([]string, error) {
+	rows, err := tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", db.Dialect().Quote(name)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return rows.Columns()
+}
+
+// readBackupFile opens a file in a backup archive and passes a JSON decoder to f.
+func readBackupFile(file *zip.File, f func(dec *json.Decoder) error) error {
+	r, err := file.Open()
+
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	return f(dec)
+}
+
+// backupNumber converts a JSON number to int64 if possible and to float64 otherwise.
+func backupNumber(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+
+	return n.String()
+}
+
+// remapID returns the new id for an old id, values without a new id are returned unchanged.
+func remapID(ids map[int64]int64, value interface{}) interface{} {
+	if id, ok := value.(int64); ok {
+		if newID, ok := ids[id]; ok {
+			return newID
+		}
+	}
+
+	return value
+}
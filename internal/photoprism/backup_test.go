@@ -0,0 +1,183 @@
+package photoprism
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// backupMembers returns the album members and files per photo uuid, which must be equal after a restore.
+func backupMembers(t *testing.T, db *gorm.DB) (albums, files []string) {
+	rows, err := db.Raw("SELECT album_uuid, photo_uuid FROM photos_albums ORDER BY album_uuid, photo_uuid").Rows()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for rows.Next() {
+		var albumUUID, photoUUID string
+
+		if err := rows.Scan(&albumUUID, &photoUUID); err != nil {
+			t.Fatal(err)
+		}
+
+		albums = append(albums, albumUUID+"/"+photoUUID)
+	}
+
+	rows.Close()
+
+	rows, err = db.Raw("SELECT photos.photo_uuid, files.file_uuid FROM files JOIN photos ON photos.id = files.photo_id ORDER BY files.file_uuid").Rows()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for rows.Next() {
+		var photoUUID, fileUUID string
+
+		if err := rows.Scan(&photoUUID, &fileUUID); err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, photoUUID+"/"+fileUUID)
+	}
+
+	rows.Close()
+
+	return albums, files
+}
+
+func TestBackup(t *testing.T) {
+	conf := config.TestConfig()
+	src := conf.Db()
+
+	dir, err := ioutil.TempDir("", "backup")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "backup.zip")
+
+	f, err := os.Create(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := Backup(src, f, conf.Version())
+	f.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, BackupSchema, manifest.Schema)
+	assert.NotEmpty(t, manifest.Tables["photos"])
+
+	dst, err := gorm.Open("sqlite3", filepath.Join(dir, "index.db"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer dst.Close()
+
+	if err := dst.AutoMigrate(entity.Entities...).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		if _, err := Restore(dst, fileName, false); err != nil {
+			t.Fatal(err)
+		}
+
+		var srcPhotos, dstPhotos int
+
+		src.Unscoped().Model(&entity.Photo{}).Count(&srcPhotos)
+		dst.Unscoped().Model(&entity.Photo{}).Count(&dstPhotos)
+
+		assert.Equal(t, srcPhotos, dstPhotos)
+		assert.Equal(t, manifest.Tables["photos"], dstPhotos)
+
+		srcAlbums, srcFiles := backupMembers(t, src)
+		dstAlbums, dstFiles := backupMembers(t, dst)
+
+		assert.NotEmpty(t, dstAlbums)
+		assert.Equal(t, srcAlbums, dstAlbums)
+		assert.Equal(t, srcFiles, dstFiles)
+	})
+	t.Run("not empty", func(t *testing.T) {
+		_, err := Restore(dst, fileName, false)
+
+		assert.Equal(t, ErrBackupNotEmpty, err)
+	})
+	t.Run("force", func(t *testing.T) {
+		if _, err := Restore(dst, fileName, true); err != nil {
+			t.Fatal(err)
+		}
+
+		var dstPhotos int
+
+		dst.Unscoped().Model(&entity.Photo{}).Count(&dstPhotos)
+
+		assert.Equal(t, manifest.Tables["photos"], dstPhotos)
+	})
+	t.Run("file not found", func(t *testing.T) {
+		_, err := Restore(dst, filepath.Join(dir, "missing.zip"), true)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestBackupTables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	db, err := gorm.Open("sqlite3", filepath.Join(dir, "index.db"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tables := backupTables(db)
+	restored := make(map[string]backupTable, len(tables))
+
+	for _, table := range tables {
+		for col, ref := range table.refs {
+			_, ok := restored[ref]
+			assert.True(t, ok, "%s.%s references %s, which must be restored first", table.name, col, ref)
+		}
+
+		restored[table.name] = table
+	}
+
+	// All entity tables are backed up unless they are skipped explicitly.
+	for _, model := range entity.Entities {
+		name := db.NewScope(model).TableName()
+		_, ok := restored[name]
+
+		assert.Equal(t, !backupSkip[name], ok, name)
+	}
+
+	assert.True(t, restored["photos"].autoID)
+	assert.True(t, restored["api_tokens"].autoID)
+	assert.False(t, restored["users"].autoID)
+	assert.False(t, restored["places"].autoID)
+	assert.False(t, restored["user_settings"].autoID)
+}
@@ -168,6 +168,49 @@ func TestMediaFile_Colors(t *testing.T) {
 		}
 	})
 
+	t.Run("dog_toshi_red.jpg", func(t *testing.T) {
+		if mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/dog_toshi_red.jpg"); err == nil {
+			p, err := mediaFile.Colors(conf.ThumbnailsPath())
+
+			assert.Nil(t, err)
+			assert.Equal(t, "red", p.MainColor.Name())
+			assert.Equal(t, "red,black,brown", p.Colors.Dominant(3).Names())
+			assert.Equal(t, uint8(28), p.Luminance.Brightness())
+			assert.False(t, p.Mono())
+			assert.False(t, p.Dark())
+		} else {
+			t.Error(err)
+		}
+	})
+
+	t.Run("elephant_mono.jpg", func(t *testing.T) {
+		if mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/elephant_mono.jpg"); err == nil {
+			p, err := mediaFile.Colors(conf.ThumbnailsPath())
+
+			assert.Nil(t, err)
+			assert.Equal(t, 0, p.Chroma.Int())
+			assert.Equal(t, "black,grey", p.Colors.Dominant(3).Names())
+			assert.True(t, p.Mono())
+			assert.False(t, p.Dark())
+		} else {
+			t.Error(err)
+		}
+	})
+
+	t.Run("jellyfish_blue.jpg", func(t *testing.T) {
+		if mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/jellyfish_blue.jpg"); err == nil {
+			p, err := mediaFile.Colors(conf.ThumbnailsPath())
+
+			assert.Nil(t, err)
+			assert.Equal(t, "blue,black", p.Colors.Dominant(3).Names())
+			assert.Equal(t, uint8(8), p.Luminance.Brightness())
+			assert.False(t, p.Mono())
+			assert.True(t, p.Dark())
+		} else {
+			t.Error(err)
+		}
+	})
+
 	t.Run("Random.docx", func(t *testing.T) {
 		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/Random.docx")
 		p, err := mediaFile.Colors(conf.ThumbnailsPath())
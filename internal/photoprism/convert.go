@@ -2,33 +2,39 @@ package photoprism
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/meta"
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
-// Convert represents a converter that can convert RAW/HEIF images to JPEG.
+// Convert represents a converter that can convert RAW/HEIF images to JPEG and videos to H.264.
 type Convert struct {
-	conf     *config.Config
-	cmdMutex sync.Mutex
+	conf       *config.Config
+	cmdMutex   sync.Mutex
+	avcLimiter *mutex.Limiter
 }
 
 // NewConvert returns a new converter and expects the config as argument.
 func NewConvert(conf *config.Config) *Convert {
-	return &Convert{conf: conf}
+	return &Convert{conf: conf, avcLimiter: mutex.NewLimiter(conf.Workers())}
 }
 
-// Start converts all files in a directory to JPEG if possible.
+// Start converts all files in a directory to JPEG if possible. Videos that can't be played
+// in browsers are transcoded to H.264 if ffmpeg is installed.
 func (c *Convert) Start(path string) error {
 	if err := mutex.Worker.Start(); err != nil {
 		return err
@@ -36,7 +42,13 @@ func (c *Convert) Start(path string) error {
 
 	defer mutex.Worker.Stop()
 
+	// Running transcodes are stopped when the worker is canceled.
+	ctx, cancel := mutex.Worker.Context(context.Background())
+	defer cancel()
+
 	jobs := make(chan ConvertJob)
+	transcode := c.conf.FFmpegBin() != ""
+	ignore := fs.NewIgnoreList(path, c.conf.IgnorePatterns())
 
 	// Start a fixed number of goroutines to convert files.
 	var wg sync.WaitGroup
@@ -64,17 +76,26 @@ func (c *Convert) Start(path string) error {
 			return nil
 		}
 
+		if ignore.Ignore(fileName, fileInfo.IsDir()) {
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
 		if fileInfo.IsDir() {
 			return nil
 		}
 
 		mf, err := NewMediaFile(fileName)
 
-		if err != nil || !(mf.IsRaw() || mf.IsHEIF() || mf.IsImageOther()) {
+		if err != nil || !(mf.IsRaw() || mf.IsHEIF() || mf.IsImageOther() || transcode && mf.IsVideo()) {
 			return nil
 		}
 
 		jobs <- ConvertJob{
+			ctx:     ctx,
 			image:   mf,
 			convert: c,
 		}
@@ -88,30 +109,93 @@ func (c *Convert) Start(path string) error {
 	return err
 }
 
-// ConvertCommand returns the command for converting files to JPEG, depending on the format.
-func (c *Convert) ConvertCommand(image *MediaFile, jpegName string, xmpName string) (result *exec.Cmd, useMutex bool, err error) {
+// ConvertCommand represents a command for converting a file to JPEG.
+type ConvertCommand struct {
+	Name     string
+	Cmd      *exec.Cmd
+	UseMutex bool
+	Oriented bool // The output is already rotated, but may contain the original Exif orientation.
+}
+
+// ConvertCommands returns the commands for converting a file to JPEG, depending on the format.
+// Raw converters are returned in the configured order, so that others can be used as fallback.
+func (c *Convert) ConvertCommands(image *MediaFile, jpegName string, xmpName string) (result []ConvertCommand, err error) {
 	if image.IsRaw() {
-		if c.conf.SipsBin() != "" {
-			result = exec.Command(c.conf.SipsBin(), "-s", "format", "jpeg", "--out", jpegName, image.fileName)
-		} else if c.conf.DarktableBin() != "" {
-			// Only one instance of darktable-cli allowed due to locking
-			useMutex = true
-
-			if xmpName != "" {
-				result = exec.Command(c.conf.DarktableBin(), image.fileName, xmpName, jpegName)
-			} else {
-				result = exec.Command(c.conf.DarktableBin(), image.fileName, jpegName)
+		for _, name := range c.conf.RawConverters() {
+			switch name {
+			case config.RawSips:
+				if bin := c.conf.SipsBin(); bin != "" {
+					result = append(result, ConvertCommand{Name: name, Cmd: exec.Command(bin, "-s", "format", "jpeg", "--out", jpegName, image.fileName)})
+				}
+			case config.RawDarktable:
+				if bin := c.conf.DarktableBin(); bin != "" {
+					args := []string{image.fileName}
+
+					if xmpName != "" {
+						args = append(args, xmpName)
+					}
+
+					args = append(args, jpegName)
+
+					if !c.conf.RawPresets() {
+						args = append(args, "--apply-custom-presets", "false")
+					}
+
+					// Extra arguments may end with --core options, so they are added last.
+					args = append(args, c.conf.DarktableArgs()...)
+
+					// Only one instance of darktable-cli allowed due to locking
+					result = append(result, ConvertCommand{Name: name, Cmd: exec.Command(bin, args...), UseMutex: true})
+				}
+			case config.RawRawTherapee:
+				if bin := c.conf.RawTherapeeBin(); bin != "" {
+					args := []string{"-o", jpegName, "-j95", "-Y"}
+					args = append(args, c.conf.RawTherapeeArgs()...)
+
+					// Input files must be the last arguments.
+					args = append(args, "-c", image.fileName)
+
+					result = append(result, ConvertCommand{Name: name, Cmd: exec.Command(bin, args...)})
+				}
 			}
-		} else {
-			return nil, useMutex, fmt.Errorf("convert: no raw to jpeg converter installed (%s)", image.Base(c.conf.Settings().Library.GroupRelated))
+		}
+
+		if len(result) == 0 {
+			return nil, fmt.Errorf("convert: no raw to jpeg converter installed (%s)", image.Base(c.conf.Settings().Library.GroupRelated))
 		}
 	} else if image.IsHEIF() {
-		result = exec.Command(c.conf.HeifConvertBin(), image.fileName, jpegName)
+		bin := c.conf.HeifConvertBin()
+
+		if bin == "" {
+			return nil, fmt.Errorf("convert: heif-convert not found, install libheif-examples to convert %s", filepath.Base(image.fileName))
+		}
+
+		result = append(result, ConvertCommand{Name: "heif-convert", Cmd: exec.Command(bin, image.fileName, jpegName), Oriented: true})
+	} else if image.IsVideo() {
+		cmd, err := c.PosterCommand(image, jpegName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, cmd)
 	} else {
-		return nil, useMutex, fmt.Errorf("convert: image type not supported for conversion (%s)", image.FileType())
+		return nil, fmt.Errorf("convert: image type not supported for conversion (%s)", image.FileType())
 	}
 
-	return result, useMutex, nil
+	return result, nil
+}
+
+// JpegName returns the file name of the JPEG version of an image. Converted images are stored
+// next to the original, or in the sidecar path in read-only mode.
+func (c *Convert) JpegName(image *MediaFile) string {
+	groupRelated := c.conf.Settings().Library.GroupRelated
+
+	if c.conf.ReadOnly() {
+		return filepath.Join(c.conf.SidecarPath(), rootBase(c.conf, image, groupRelated)+".jpg")
+	}
+
+	return image.AbsBase(groupRelated) + ".jpg"
 }
 
 // ToJpeg converts a single image file to JPEG if possible.
@@ -132,13 +216,17 @@ func (c *Convert) ToJpeg(image *MediaFile) (*MediaFile, error) {
 		return mediaFile, nil
 	}
 
-	jpegName = image.AbsBase(c.conf.Settings().Library.GroupRelated) + ".jpg"
+	jpegName = c.JpegName(image)
 
-	if c.conf.ReadOnly() {
-		return nil, fmt.Errorf("convert: disabled in read only mode (%s)", image.FileName())
+	if mediaFile, err = NewMediaFile(jpegName); err == nil {
+		return mediaFile, nil
 	}
 
-	fileName := image.RelativeName(c.conf.OriginalsPath())
+	if err := os.MkdirAll(filepath.Dir(jpegName), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	_, fileName := rootName(c.conf, image.FileName())
 
 	log.Infof("convert: %s -> %s", fileName, jpegName)
 
@@ -161,17 +249,248 @@ func (c *Convert) ToJpeg(image *MediaFile) (*MediaFile, error) {
 		return NewMediaFile(jpegName)
 	}
 
-	cmd, useMutex, err := c.ConvertCommand(image, jpegName, xmpName)
+	cmds, err := c.ConvertCommands(image, jpegName, xmpName)
 
 	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	if err := c.run(context.Background(), cmds, fileName, jpegName); err != nil {
 		return nil, err
 	}
 
+	return NewMediaFile(jpegName)
+}
+
+// SaveError records a failed conversion on the indexed file, so that it's listed with other file errors,
+// or clears the error of a previous conversion if err is nil. Files that aren't indexed yet are ignored.
+func (c *Convert) SaveError(image *MediaFile, err error) {
+	db := c.conf.Db()
+	root, fileName := rootName(c.conf, image.FileName())
+
+	var file entity.File
+
+	if db.Where("file_root = ? AND file_name = ?", root, fileName).First(&file).Error != nil {
+		return
+	}
+
+	if err != nil {
+		file.SetError(entity.StageConvert, errors.New(strings.TrimSpace(err.Error())))
+	} else if file.HasError(entity.StageConvert) {
+		file.ResetError()
+	} else {
+		return
+	}
+
+	if err := db.Model(&file).UpdateColumns(map[string]interface{}{
+		"file_error":       file.FileError,
+		"file_error_stage": file.FileErrorStage,
+		"file_error_at":    file.FileErrorAt,
+	}).Error; err != nil {
+		log.Errorf("convert: %s", err)
+	}
+}
+
+// AvcName returns the file name of the H.264 transcode of a video. Transcodes are stored
+// next to the original, or in the cache path in read-only mode.
+func (c *Convert) AvcName(video *MediaFile) string {
+	if c.conf.ReadOnly() {
+		return filepath.Join(c.conf.CachePath(), "videos", rootBase(c.conf, video, false)+".avc")
+	}
+
+	return video.AbsBase(false) + ".avc"
+}
+
+// AvcCommand returns the command for transcoding a video to H.264 in an MP4 container.
+func (c *Convert) AvcCommand(video *MediaFile, avcName string) (ConvertCommand, error) {
+	bin := c.conf.FFmpegBin()
+
+	if bin == "" {
+		return ConvertCommand{}, fmt.Errorf("convert: ffmpeg not found, install ffmpeg to transcode %s", filepath.Base(video.fileName))
+	}
+
+	args := []string{
+		"-noautorotate",
+		"-i", video.fileName,
+		"-vf", videoFilter(video, "yuv420p"),
+		"-metadata:s:v:0", "rotate=0",
+		"-c:v", "libx264",
+		"-preset", "fast",
+		"-b:v", fmt.Sprintf("%dM", c.conf.FFmpegBitrate()),
+		"-c:a", "aac",
+		"-movflags", "+faststart",
+		"-f", "mp4",
+		"-y", avcName,
+	}
+
+	return ConvertCommand{Name: "ffmpeg", Cmd: exec.Command(bin, args...)}, nil
+}
+
+// PosterCommand returns the command for extracting the first frame of a video as JPEG poster image.
+func (c *Convert) PosterCommand(video *MediaFile, jpegName string) (ConvertCommand, error) {
+	bin := c.conf.FFmpegBin()
+
+	if bin == "" {
+		return ConvertCommand{}, fmt.Errorf("convert: ffmpeg not found, install ffmpeg to create a poster for %s", filepath.Base(video.fileName))
+	}
+
+	args := []string{
+		"-noautorotate",
+		"-i", video.fileName,
+		"-ss", "00:00:00.001",
+		"-vf", videoFilter(video, "yuvj420p"),
+		"-frames:v", "1",
+		"-y", jpegName,
+	}
+
+	return ConvertCommand{Name: "ffmpeg", Cmd: exec.Command(bin, args...)}, nil
+}
+
+// hdrToneMap is the ffmpeg filter chain that tone maps HDR videos like iPhone HLG or HDR10 recordings
+// to SDR with BT.709 colors, as they look washed out otherwise.
+var hdrToneMap = []string{
+	"zscale=t=linear:npl=100",
+	"format=gbrpf32le",
+	"zscale=p=bt709",
+	"tonemap=tonemap=hable:desat=0",
+	"zscale=t=bt709:m=bt709:r=tv",
+}
+
+// videoFilter returns the ffmpeg video filter for transcodes and posters. Videos are rotated according
+// to the track matrix, as automatic rotation is disabled so that it doesn't depend on the ffmpeg version,
+// and HDR videos are tone mapped. The pixel format is applied last.
+func videoFilter(video *MediaFile, pixFmt string) string {
+	var filters []string
+
+	info, err := video.MetaData()
+
+	if err != nil {
+		log.Debugf("convert: %s (%s)", err, filepath.Base(video.fileName))
+	}
+
+	switch info.Rotation {
+	case 90:
+		filters = append(filters, "transpose=clock")
+	case 180:
+		filters = append(filters, "hflip", "vflip")
+	case 270:
+		filters = append(filters, "transpose=cclock")
+	}
+
+	if info.HDR {
+		filters = append(filters, hdrToneMap...)
+	}
+
+	filters = append(filters, "format="+pixFmt)
+
+	return strings.Join(filters, ",")
+}
+
+// ToAvc returns a video that can be played in browsers. The original is returned if it is
+// already H.264 encoded, otherwise it is transcoded unless a transcode exists. Concurrent
+// calls for the same video wait for a single transcode.
+func (c *Convert) ToAvc(video *MediaFile) (*MediaFile, error) {
+	return c.ToAvcContext(context.Background(), video)
+}
+
+// ToAvcContext returns a playable video like ToAvc, but stops waiting when the context is done.
+// Transcodes started by the call are stopped along with it, or once the ffmpeg timeout is reached.
+func (c *Convert) ToAvcContext(ctx context.Context, video *MediaFile) (*MediaFile, error) {
+	if !video.Exists() {
+		return nil, fmt.Errorf("convert: can not transcode video, file does not exist (%s)", video.FileName())
+	}
+
+	if video.IsPlayable() {
+		return video, nil
+	}
+
+	avcName := c.AvcName(video)
+
+	if fs.FileExists(avcName) {
+		return NewMediaFile(avcName)
+	}
+
+	_, fileName := rootName(c.conf, video.FileName())
+
+	ctx, cancel := context.WithTimeout(ctx, c.conf.FFmpegTimeout())
+	defer cancel()
+
+	result, _, err := c.avcLimiter.Do(ctx, avcName, func() (string, error) {
+		// Another call may have finished the transcode in the meantime.
+		if fs.FileExists(avcName) {
+			return avcName, nil
+		}
+
+		// Keep the extension, so that incomplete files are not indexed as related files.
+		tmpName := strings.TrimSuffix(avcName, ".avc") + ".tmp.avc"
+
+		cmd, err := c.AvcCommand(video, tmpName)
+
+		if err != nil {
+			log.Error(err)
+			return "", err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(avcName), os.ModePerm); err != nil {
+			return "", err
+		}
+
+		log.Infof("convert: %s -> %s", fileName, avcName)
+
+		event.Publish("index.converting", event.Data{
+			"fileType": video.FileType(),
+			"fileName": fileName,
+			"baseName": filepath.Base(fileName),
+		})
+
+		if err := c.run(ctx, []ConvertCommand{cmd}, fileName, tmpName); err != nil {
+			return "", err
+		}
+
+		return avcName, os.Rename(tmpName, avcName)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMediaFile(result)
+}
+
+// run runs the convert commands until one succeeds and returns the last error otherwise.
+// Failures are logged with the command output. Commands are stopped when the context is done.
+func (c *Convert) run(ctx context.Context, cmds []ConvertCommand, fileName, jpegName string) (err error) {
 	// Unclear if this is really necessary here, but safe is safe.
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 
-	if useMutex {
+	for _, cmd := range cmds {
+		if err = c.runCommand(ctx, cmd); err == nil {
+			// Reset the orientation, so that the output isn't rotated twice.
+			if cmd.Oriented {
+				if err := meta.ResetOrientation(jpegName); err != nil {
+					log.Warnf("convert: could not reset orientation of %s (%s)", filepath.Base(jpegName), err)
+				}
+			}
+
+			return nil
+		}
+
+		log.Errorf("convert: %s failed for %s (%s)", cmd.Name, fileName, err)
+
+		// Remove incomplete output before trying the next converter.
+		if fs.FileExists(jpegName) {
+			os.Remove(jpegName)
+		}
+	}
+
+	return err
+}
+
+// runCommand runs a single convert command and returns its error output if it fails.
+func (c *Convert) runCommand(ctx context.Context, cmd ConvertCommand) error {
+	if cmd.UseMutex {
 		// Make sure only one command is executed at a time.
 		// See https://photo.stackexchange.com/questions/105969/darktable-cli-fails-because-of-locked-database-file
 		c.cmdMutex.Lock()
@@ -181,17 +500,38 @@ func (c *Convert) ToJpeg(image *MediaFile) (*MediaFile, error) {
 	// Fetch command output.
 	var out bytes.Buffer
 	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
+	cmd.Cmd.Stdout = &out
+	cmd.Cmd.Stderr = &stderr
 
 	// Run convert command.
-	if err := cmd.Run(); err != nil {
-		if stderr.String() != "" {
-			return nil, errors.New(stderr.String())
-		} else {
-			return nil, err
-		}
+	if err := cmd.Cmd.Start(); err != nil {
+		return err
 	}
 
-	return NewMediaFile(jpegName)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- cmd.Cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			return nil
+		}
+
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+
+		return err
+	case <-ctx.Done():
+		if err := cmd.Cmd.Process.Kill(); err != nil {
+			log.Warnf("convert: could not stop %s (%s)", cmd.Name, err)
+		}
+
+		<-done
+
+		return fmt.Errorf("convert: %s stopped (%s)", cmd.Name, ctx.Err())
+	}
 }
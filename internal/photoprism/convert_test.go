@@ -1,12 +1,22 @@
 package photoprism
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/meta"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
 )
 
 func TestNewConvert(t *testing.T) {
@@ -126,3 +136,592 @@ func TestConvert_Start(t *testing.T) {
 
 	assert.NotEqual(t, oldHash, newHash, "Fingerprint of old and new JPEG file must not be the same")
 }
+
+// fakeConverter creates an executable script that logs its name and exits with the given status.
+func fakeConverter(t *testing.T, dir, name string, status int) string {
+	fileName := filepath.Join(dir, name)
+	script := fmt.Sprintf("#!/bin/sh\necho %s >> %s/calls.log\necho \"%s error\" >&2\nexit %d\n", name, dir, name, status)
+
+	if err := ioutil.WriteFile(fileName, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+// fakeConvertConfig returns a config with the given string flags and the raw-presets option.
+func fakeConvertConfig(flags map[string]string, presets bool) *config.Config {
+	set := flag.NewFlagSet("test", 0)
+	set.Bool("raw-presets", presets, "doc")
+
+	for name, value := range flags {
+		set.String(name, value, "doc")
+	}
+
+	ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+	for name, value := range flags {
+		ctx.Set(name, value)
+	}
+
+	ctx.Set("raw-presets", fmt.Sprintf("%t", presets))
+
+	return config.NewConfig(ctx)
+}
+
+func TestConvert_ConvertCommands(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	image, err := NewMediaFile(config.TestConfig().ExamplesPath() + "/canon_eos_6d.dng")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	darktable := fakeConverter(t, dir, "darktable-cli", 0)
+	rawtherapee := fakeConverter(t, dir, "rawtherapee-cli", 0)
+
+	t.Run("preferred converter", func(t *testing.T) {
+		conf := fakeConvertConfig(map[string]string{
+			"sips-bin":         filepath.Join(dir, "missing"),
+			"darktable-bin":    darktable,
+			"darktable-args":   "--hq false",
+			"rawtherapee-bin":  rawtherapee,
+			"rawtherapee-args": "-q",
+			"raw-converter":    "RawTherapee",
+		}, false)
+
+		cmds, err := NewConvert(conf).ConvertCommands(image, "/tmp/out.jpg", "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, cmds, 2)
+		assert.Equal(t, "rawtherapee", cmds[0].Name)
+		assert.Equal(t, []string{rawtherapee, "-o", "/tmp/out.jpg", "-j95", "-Y", "-q", "-c", image.FileName()}, cmds[0].Cmd.Args)
+		assert.False(t, cmds[0].UseMutex)
+		assert.Equal(t, "darktable", cmds[1].Name)
+		assert.Equal(t, []string{darktable, image.FileName(), "/tmp/out.jpg", "--apply-custom-presets", "false", "--hq", "false"}, cmds[1].Cmd.Args)
+		assert.True(t, cmds[1].UseMutex)
+	})
+	t.Run("default order with presets", func(t *testing.T) {
+		conf := fakeConvertConfig(map[string]string{
+			"sips-bin":        filepath.Join(dir, "missing"),
+			"darktable-bin":   darktable,
+			"rawtherapee-bin": rawtherapee,
+		}, true)
+
+		cmds, err := NewConvert(conf).ConvertCommands(image, "/tmp/out.jpg", "/tmp/canon_eos_6d.xmp")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, cmds, 2)
+		assert.Equal(t, "darktable", cmds[0].Name)
+		assert.Equal(t, []string{darktable, image.FileName(), "/tmp/canon_eos_6d.xmp", "/tmp/out.jpg"}, cmds[0].Cmd.Args)
+		assert.Equal(t, "rawtherapee", cmds[1].Name)
+	})
+}
+
+func TestConvert_run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	image, err := NewMediaFile(config.TestConfig().ExamplesPath() + "/canon_eos_6d.dng")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := func() []string {
+		data, _ := ioutil.ReadFile(filepath.Join(dir, "calls.log"))
+		os.Remove(filepath.Join(dir, "calls.log"))
+		return strings.Fields(string(data))
+	}
+
+	t.Run("fallback", func(t *testing.T) {
+		conf := fakeConvertConfig(map[string]string{
+			"sips-bin":        filepath.Join(dir, "missing"),
+			"darktable-bin":   fakeConverter(t, dir, "darktable-cli", 0),
+			"rawtherapee-bin": fakeConverter(t, dir, "rawtherapee-cli", 1),
+			"raw-converter":   "rawtherapee",
+		}, false)
+
+		convert := NewConvert(conf)
+		cmds, err := convert.ConvertCommands(image, filepath.Join(dir, "out.jpg"), "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, convert.run(context.Background(), cmds, "canon_eos_6d.dng", filepath.Join(dir, "out.jpg")))
+		assert.Equal(t, []string{"rawtherapee-cli", "darktable-cli"}, calls())
+	})
+	t.Run("all failed", func(t *testing.T) {
+		conf := fakeConvertConfig(map[string]string{
+			"sips-bin":        filepath.Join(dir, "missing"),
+			"darktable-bin":   fakeConverter(t, dir, "darktable-cli", 1),
+			"rawtherapee-bin": fakeConverter(t, dir, "rawtherapee-cli", 1),
+		}, false)
+
+		convert := NewConvert(conf)
+		cmds, err := convert.ConvertCommands(image, filepath.Join(dir, "out.jpg"), "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = convert.run(context.Background(), cmds, "canon_eos_6d.dng", filepath.Join(dir, "out.jpg"))
+
+		assert.EqualError(t, err, "rawtherapee-cli error")
+		assert.Equal(t, []string{"darktable-cli", "rawtherapee-cli"}, calls())
+	})
+}
+
+func TestConvert_ConvertCommands_Heif(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	image, err := NewMediaFile(config.TestConfig().ExamplesPath() + "/iphone_7.heic")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("heif-convert", func(t *testing.T) {
+		bin := fakeConverter(t, dir, "heif-convert", 0)
+		conf := fakeConvertConfig(map[string]string{"heifconvert-bin": bin}, false)
+
+		cmds, err := NewConvert(conf).ConvertCommands(image, "/tmp/out.jpg", "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, cmds, 1)
+		assert.Equal(t, "heif-convert", cmds[0].Name)
+		assert.Equal(t, []string{bin, image.FileName(), "/tmp/out.jpg"}, cmds[0].Cmd.Args)
+	})
+	t.Run("missing binary", func(t *testing.T) {
+		conf := fakeConvertConfig(map[string]string{"heifconvert-bin": filepath.Join(dir, "missing")}, false)
+
+		cmds, err := NewConvert(conf).ConvertCommands(image, "/tmp/out.jpg", "")
+
+		assert.Nil(t, cmds)
+		assert.EqualError(t, err, "convert: heif-convert not found, install libheif-examples to convert iphone_7.heic")
+	})
+}
+
+// fakeFFmpeg creates an executable script that logs its name and writes the output file.
+func fakeFFmpeg(t *testing.T, dir string) string {
+	fileName := filepath.Join(dir, "ffmpeg")
+	script := fmt.Sprintf("#!/bin/sh\necho ffmpeg >> %s/calls.log\nsleep 0.1\nfor last; do true; done\necho avc > \"$last\"\n", dir)
+
+	if err := ioutil.WriteFile(fileName, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func TestConvert_AvcCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	video, err := NewMediaFile("testdata/hevc.mp4")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("bitrate", func(t *testing.T) {
+		bin := fakeFFmpeg(t, dir)
+		conf := fakeConvertConfig(map[string]string{"ffmpeg-bin": bin, "ffmpeg-bitrate": "8"}, false)
+
+		cmd, err := NewConvert(conf).AvcCommand(video, "/tmp/hevc.avc")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "ffmpeg", cmd.Name)
+		assert.Equal(t, []string{bin, "-noautorotate", "-i", "testdata/hevc.mp4", "-vf", "format=yuv420p", "-metadata:s:v:0", "rotate=0", "-c:v", "libx264", "-preset", "fast", "-b:v", "8M", "-c:a", "aac", "-movflags", "+faststart", "-f", "mp4", "-y", "/tmp/hevc.avc"}, cmd.Cmd.Args)
+	})
+	t.Run("poster", func(t *testing.T) {
+		bin := fakeFFmpeg(t, dir)
+		conf := fakeConvertConfig(map[string]string{"ffmpeg-bin": bin}, false)
+
+		cmds, err := NewConvert(conf).ConvertCommands(video, "/tmp/hevc.jpg", "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, cmds, 1) {
+			assert.Equal(t, []string{bin, "-noautorotate", "-i", "testdata/hevc.mp4", "-ss", "00:00:00.001", "-vf", "format=yuvj420p", "-frames:v", "1", "-y", "/tmp/hevc.jpg"}, cmds[0].Cmd.Args)
+		}
+	})
+	t.Run("missing binary", func(t *testing.T) {
+		conf := fakeConvertConfig(map[string]string{"ffmpeg-bin": filepath.Join(dir, "missing")}, false)
+
+		_, err := NewConvert(conf).AvcCommand(video, "/tmp/hevc.avc")
+
+		assert.EqualError(t, err, "convert: ffmpeg not found, install ffmpeg to transcode hevc.mp4")
+	})
+}
+
+func TestVideoFilter(t *testing.T) {
+	video := func(info meta.Data) *MediaFile {
+		m, err := NewMediaFile("testdata/hevc.mp4")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m.once.Do(func() { m.metaData = info })
+
+		return m
+	}
+
+	t.Run("iphone portrait", func(t *testing.T) {
+		assert.Equal(t, "transpose=clock,format=yuv420p", videoFilter(video(meta.Data{Rotation: 90}), "yuv420p"))
+	})
+	t.Run("upside down", func(t *testing.T) {
+		assert.Equal(t, "hflip,vflip,format=yuv420p", videoFilter(video(meta.Data{Rotation: 180}), "yuv420p"))
+	})
+	t.Run("hlg", func(t *testing.T) {
+		filter := videoFilter(video(meta.Data{Rotation: 270, HDR: true, ColorTransfer: meta.TransferHLG}), "yuvj420p")
+
+		assert.Equal(t, "transpose=cclock,"+strings.Join(hdrToneMap, ",")+",format=yuvj420p", filter)
+	})
+	t.Run("gopro", func(t *testing.T) {
+		assert.Equal(t, "format=yuv420p", videoFilter(video(meta.Data{ColorTransfer: meta.TransferBT709}), "yuv420p"))
+	})
+}
+
+func TestConvert_ToAvc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	originals := filepath.Join(dir, "originals")
+	cache := filepath.Join(dir, "cache")
+
+	if err := os.MkdirAll(filepath.Join(originals, "2020"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"hevc.mp4", "avc.mp4"} {
+		f, err := NewMediaFile(filepath.Join("testdata", name))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Copy(filepath.Join(originals, "2020", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := func() []string {
+		data, _ := ioutil.ReadFile(filepath.Join(dir, "calls.log"))
+		os.Remove(filepath.Join(dir, "calls.log"))
+		return strings.Fields(string(data))
+	}
+
+	flags := map[string]string{
+		"ffmpeg-bin":     fakeFFmpeg(t, dir),
+		"originals-path": originals,
+		"cache-path":     cache,
+	}
+
+	t.Run("playable", func(t *testing.T) {
+		video, err := NewMediaFile(filepath.Join(originals, "2020", "avc.mp4"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := NewConvert(fakeConvertConfig(flags, false)).ToAvc(video)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, video.FileName(), result.FileName())
+		assert.Empty(t, calls())
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		convert := NewConvert(fakeConvertConfig(flags, false))
+
+		var wg sync.WaitGroup
+
+		results := make([]string, 5)
+
+		for i := range results {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				video, err := NewMediaFile(filepath.Join(originals, "2020", "hevc.mp4"))
+
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if result, err := convert.ToAvc(video); err != nil {
+					t.Error(err)
+				} else {
+					results[i] = result.FileName()
+				}
+			}(i)
+		}
+
+		wg.Wait()
+
+		for _, r := range results {
+			assert.Equal(t, filepath.Join(originals, "2020", "hevc.avc"), r)
+		}
+
+		assert.Equal(t, []string{"ffmpeg"}, calls())
+		assert.False(t, fs.FileExists(filepath.Join(originals, "2020", "hevc.tmp.avc")))
+	})
+	t.Run("existing", func(t *testing.T) {
+		video, err := NewMediaFile(filepath.Join(originals, "2020", "hevc.mp4"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := NewConvert(fakeConvertConfig(flags, false)).ToAvc(video)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, filepath.Join(originals, "2020", "hevc.avc"), result.FileName())
+		assert.Empty(t, calls())
+	})
+	t.Run("read only", func(t *testing.T) {
+		os.Remove(filepath.Join(originals, "2020", "hevc.avc"))
+
+		readOnly := map[string]string{"read-only": "true"}
+
+		for k, v := range flags {
+			readOnly[k] = v
+		}
+
+		video, err := NewMediaFile(filepath.Join(originals, "2020", "hevc.mp4"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := NewConvert(fakeConvertConfig(readOnly, false)).ToAvc(video)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, filepath.Join(cache, "videos", "2020", "hevc.avc"), result.FileName())
+		assert.False(t, fs.FileExists(filepath.Join(originals, "2020", "hevc.avc")))
+		assert.Equal(t, []string{"ffmpeg"}, calls())
+	})
+}
+
+func TestConvert_ToAvcContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	originals := filepath.Join(dir, "originals")
+
+	if err := os.MkdirAll(originals, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewMediaFile("testdata/hevc.mp4")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Copy(filepath.Join(originals, "hevc.mp4")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fake ffmpeg never finishes, exec replaces the shell so that it can be stopped.
+	bin := filepath.Join(dir, "ffmpeg")
+
+	if err := ioutil.WriteFile(bin, []byte("#!/bin/sh\nexec sleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := map[string]string{
+		"ffmpeg-bin":     bin,
+		"originals-path": originals,
+		"cache-path":     filepath.Join(dir, "cache"),
+	}
+
+	video, err := NewMediaFile(filepath.Join(originals, "hevc.mp4"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := NewConvert(fakeConvertConfig(flags, false)).ToAvcContext(ctx, video)
+
+		assert.Error(t, err)
+		assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+		assert.False(t, fs.FileExists(filepath.Join(originals, "hevc.avc")))
+	})
+	t.Run("timeout", func(t *testing.T) {
+		timeout := map[string]string{"ffmpeg-timeout": "1"}
+
+		for k, v := range flags {
+			timeout[k] = v
+		}
+
+		start := time.Now()
+		_, err := NewConvert(fakeConvertConfig(timeout, false)).ToAvc(video)
+
+		assert.Error(t, err)
+		assert.Less(t, int64(time.Since(start)), int64(10*time.Second))
+		assert.False(t, fs.FileExists(filepath.Join(originals, "hevc.avc")))
+	})
+}
+
+func TestConvert_Start_ReadOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	originals := filepath.Join(dir, "originals")
+	sidecar := filepath.Join(dir, "sidecar")
+
+	if err := os.MkdirAll(filepath.Join(originals, "2020"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	image, err := NewMediaFile(config.TestConfig().ExamplesPath() + "/iphone_7.heic")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := image.Copy(filepath.Join(originals, "2020", "iphone_7.heic")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fake heif-convert that writes the output file given as last argument.
+	bin := filepath.Join(dir, "heif-convert")
+	script := "#!/bin/sh\nfor last; do true; done\necho jpeg > \"$last\"\n"
+
+	if err := ioutil.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	conf := fakeConvertConfig(map[string]string{
+		"read-only":       "true",
+		"heifconvert-bin": bin,
+		"originals-path":  originals,
+		"sidecar-path":    sidecar,
+		"cache-path":      filepath.Join(dir, "cache"),
+	}, false)
+
+	snapshot := func() map[string]int64 {
+		result := make(map[string]int64)
+
+		_ = filepath.Walk(originals, func(fileName string, info os.FileInfo, err error) error {
+			if err == nil {
+				result[fileName] = info.ModTime().UnixNano()
+			}
+
+			return nil
+		})
+
+		return result
+	}
+
+	before := snapshot()
+
+	for _, d := range []string{filepath.Join(originals, "2020"), originals} {
+		if err := os.Chmod(d, 0555); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Chmod(d, 0755)
+	}
+
+	if err := NewConvert(conf).Start(originals); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, fs.FileExists(filepath.Join(sidecar, "2020", "iphone_7.jpg")))
+	assert.Equal(t, before, snapshot())
+
+	// The converted JPEG is related to the original, so that it's indexed as primary file.
+	mf, err := NewMediaFile(filepath.Join(originals, "2020", "iphone_7.heic"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	related, err := mf.RelatedSidecarFiles(false, sidecarBase(conf, mf, false))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+
+	for _, f := range related.Files {
+		names = append(names, f.FileName())
+	}
+
+	assert.True(t, related.Main.IsHEIF())
+	assert.ElementsMatch(t, []string{mf.FileName(), filepath.Join(sidecar, "2020", "iphone_7.jpg")}, names)
+}
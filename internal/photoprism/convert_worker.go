@@ -1,17 +1,35 @@
 package photoprism
 
-import "strings"
+import (
+	"context"
+	"strings"
+)
 
 type ConvertJob struct {
+	ctx     context.Context
 	image   *MediaFile
 	convert *Convert
 }
 
 func ConvertWorker(jobs <-chan ConvertJob) {
 	for job := range jobs {
-		if _, err := job.convert.ToJpeg(job.image); err != nil {
-			fileName := job.image.RelativeName(job.convert.conf.OriginalsPath())
+		if job.image.IsVideo() {
+			if _, err := job.convert.ToAvcContext(job.ctx, job.image); err != nil {
+				_, fileName := rootName(job.convert.conf, job.image.FileName())
+				log.Errorf("convert: could not transcode %s (%s)", fileName, strings.TrimSpace(err.Error()))
+			}
+
+			// Videos get a poster image for thumbnails, just like other files that aren't JPEGs.
+			if _, err := job.convert.ToJpeg(job.image); err != nil {
+				_, fileName := rootName(job.convert.conf, job.image.FileName())
+				log.Errorf("convert: could not create poster for %s (%s)", fileName, strings.TrimSpace(err.Error()))
+			}
+		} else if _, err := job.convert.ToJpeg(job.image); err != nil {
+			_, fileName := rootName(job.convert.conf, job.image.FileName())
 			log.Errorf("convert: could not create jpeg for %s (%s)", fileName, strings.TrimSpace(err.Error()))
+			job.convert.SaveError(job.image, err)
+		} else {
+			job.convert.SaveError(job.image, nil)
 		}
 	}
 }
@@ -0,0 +1,87 @@
+package photoprism
+
+import (
+	"image"
+	"math"
+
+	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// SetCrop sets the crop window of centered fill thumbnails, e.g. as stored in the index.
+func (m *MediaFile) SetCrop(crop thumb.Crop) {
+	m.crop = crop
+}
+
+// Crop returns the crop window of centered fill thumbnails if smart cropping is enabled. It is
+// chosen once using the fit_720 thumbnail, so that analysis doesn't require decoding the original.
+// Face regions stored in the file or its XMP sidecar are preferred over the image content.
+func (m *MediaFile) Crop(thumbPath string) thumb.Crop {
+	if !thumb.SmartCrop {
+		return thumb.Crop{}
+	}
+
+	if !m.IsJpeg() || !m.crop.IsZero() {
+		return m.crop
+	}
+
+	img, err := m.Resample(thumbPath, "fit_720")
+
+	if err != nil {
+		log.Warnf("mediafile: using center crop for %s (%s)", m.Base(false), err)
+		return m.crop
+	}
+
+	m.crop = thumb.FindCrop(img, faceRects(m.FaceRegions(), img.Bounds()))
+
+	if !m.crop.IsZero() {
+		m.timeline.Add("thumbs", "crop center %s", m.crop)
+	}
+
+	return m.crop
+}
+
+// FaceRegions returns the face regions stored in the file, or in its XMP sidecar files otherwise.
+func (m *MediaFile) FaceRegions() meta.FaceRegions {
+	if data, err := m.MetaData(); (err == nil || err == meta.ErrNoExif) && len(data.FaceRegions) > 0 {
+		return data.FaceRegions
+	}
+
+	related, err := m.RelatedFiles(false)
+
+	if err != nil {
+		return nil
+	}
+
+	for _, f := range related.Files {
+		if !f.IsXMP() {
+			continue
+		}
+
+		if data, err := meta.XMP(f.FileName()); err == nil && len(data.FaceRegions) > 0 {
+			return data.FaceRegions
+		}
+	}
+
+	return nil
+}
+
+// faceRects returns the pixel rectangles of face regions in an image with the given bounds.
+func faceRects(regions meta.FaceRegions, b image.Rectangle) (result []image.Rectangle) {
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	for _, r := range regions {
+		rect := image.Rect(
+			b.Min.X+int(math.Round(r.X*w)),
+			b.Min.Y+int(math.Round(r.Y*h)),
+			b.Min.X+int(math.Round((r.X+r.W)*w)),
+			b.Min.Y+int(math.Round((r.Y+r.H)*h)),
+		).Intersect(b)
+
+		if !rect.Empty() {
+			result = append(result, rect)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,183 @@
+package photoprism
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeOffCenterJpeg creates a landscape image with a plain background and a detailed subject on the right.
+func writeOffCenterJpeg(t *testing.T, fileName string) {
+	img := image.NewNRGBA(image.Rect(0, 0, 1200, 800))
+
+	for y := 0; y < 800; y++ {
+		for x := 0; x < 1200; x++ {
+			c := color.NRGBA{R: 190, G: 210, B: 230, A: 255}
+
+			if x > 900 && x < 1160 && y > 250 && y < 550 && (x/10+y/10)%2 == 0 {
+				c = color.NRGBA{R: 40, G: 30, B: 20, A: 255}
+			}
+
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	if err := imaging.Save(img, fileName); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMediaFile_Crop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crop")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "subject_right.jpg")
+	thumbsPath := filepath.Join(dir, "thumbs")
+
+	writeOffCenterJpeg(t, fileName)
+
+	defer func(enabled bool) { thumb.SmartCrop = enabled }(thumb.SmartCrop)
+
+	t.Run("disabled", func(t *testing.T) {
+		thumb.SmartCrop = false
+
+		m, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, m.Crop(thumbsPath).IsZero())
+	})
+	t.Run("off-center subject", func(t *testing.T) {
+		thumb.SmartCrop = true
+
+		m, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		crop := m.Crop(thumbsPath)
+
+		assert.Greater(t, crop.X, 0.6)
+		assert.Equal(t, 0.5, crop.Y)
+
+		// Results must be the same when analyzing the file again.
+		again, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, crop.String(), again.Crop(thumbsPath).String())
+
+		stored, ok := thumb.ParseCrop(crop.String())
+
+		assert.True(t, ok)
+		assert.Equal(t, crop.String(), stored.String())
+	})
+	t.Run("face region in sidecar", func(t *testing.T) {
+		thumb.SmartCrop = true
+
+		faceName := filepath.Join(dir, "face_left.jpg")
+
+		writeOffCenterJpeg(t, faceName)
+
+		// The face on the left is preferred over the detailed subject on the right.
+		xmp := `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` +
+			`<rdf:Description rdf:about="" xmlns:MP="http://ns.microsoft.com/photo/1.2/" ` +
+			`xmlns:MPRI="http://ns.microsoft.com/photo/1.2/t/RegionInfo#" xmlns:MPReg="http://ns.microsoft.com/photo/1.2/t/Region#">` +
+			`<MP:RegionInfo rdf:parseType="Resource"><MPRI:Regions><rdf:Bag>` +
+			`<rdf:li MPReg:PersonDisplayName="Jane Doe" MPReg:Rectangle="0.1, 0.4, 0.1, 0.2"/>` +
+			`</rdf:Bag></MPRI:Regions></MP:RegionInfo></rdf:Description></rdf:RDF></x:xmpmeta>`
+
+		if err := ioutil.WriteFile(filepath.Join(dir, "face_left.xmp"), []byte(xmp), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := NewMediaFile(faceName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, m.FaceRegions(), 1)
+
+		crop := m.Crop(thumbsPath)
+
+		assert.InDelta(t, 0.15, crop.X, 0.01)
+		assert.InDelta(t, 0.5, crop.Y, 0.01)
+	})
+	t.Run("stored crop", func(t *testing.T) {
+		thumb.SmartCrop = true
+
+		m, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m.SetCrop(thumb.Crop{X: 0.25, Y: 0.5})
+
+		assert.Equal(t, "0.250,0.500", m.Crop(thumbsPath).String())
+	})
+	t.Run("fit thumbnails are not cropped", func(t *testing.T) {
+		thumb.SmartCrop = true
+
+		m, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := m.Resample(thumbsPath, "fit_720")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 720, img.Bounds().Dx())
+		assert.Equal(t, 480, img.Bounds().Dy())
+	})
+	t.Run("offset tile", func(t *testing.T) {
+		thumb.SmartCrop = true
+
+		m, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := m.Resample(thumbsPath, "tile_224")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The background is plain, so a center crop would miss most of the subject on the right.
+		var dark int
+
+		for y := 0; y < 224; y++ {
+			for x := 0; x < 224; x++ {
+				if r, _, _, _ := img.At(x, y).RGBA(); r < 0x8000 {
+					dark++
+				}
+			}
+		}
+
+		assert.Greater(t, dark, 224*224/25)
+	})
+}
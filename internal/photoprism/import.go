@@ -1,6 +1,7 @@
 package photoprism
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -40,28 +41,45 @@ func (imp *Import) originalsPath() string {
 	return imp.conf.OriginalsPath()
 }
 
+// ImportResult contains the number of files that were added to the index and skipped as duplicates.
+type ImportResult struct {
+	Added      int
+	Duplicates int
+}
+
 // Start imports media files from a directory and converts/indexes them as needed.
-func (imp *Import) Start(opt ImportOptions) {
+func (imp *Import) Start(opt ImportOptions) ImportResult {
+	return imp.StartContext(context.Background(), opt)
+}
+
+// StartContext imports media files like Start, but stops sending files to the workers when
+// the context is canceled. Interrupted runs can be resumed with opt.Resume.
+func (imp *Import) StartContext(ctx context.Context, opt ImportOptions) (result ImportResult) {
 	var directories []string
 	done := make(map[string]bool)
 	ind := imp.index
 	importPath := opt.Path
 
+	if imp.conf.ReadOnly() {
+		event.Error(fmt.Sprintf("import: %s", config.ErrReadOnly.Error()))
+		return result
+	}
+
 	if !fs.PathExists(importPath) {
 		event.Error(fmt.Sprintf("import: %s does not exist", importPath))
-		return
+		return result
 	}
 
 	if err := mutex.Worker.Start(); err != nil {
 		event.Error(fmt.Sprintf("import: %s", err.Error()))
-		return
+		return result
 	}
 
 	defer mutex.Worker.Stop()
 
 	if err := ind.tensorFlow.Init(); err != nil {
 		log.Errorf("import: %s", err.Error())
-		return
+		return result
 	}
 
 	jobs := make(chan ImportJob)
@@ -77,7 +95,19 @@ func (imp *Import) Start(opt ImportOptions) {
 		}()
 	}
 
+	ind.progress = newRunProgress(ind.db, entity.RunImport, importPath, opt.Resume)
+	defer func() { ind.progress = nil }()
+
 	indexOpt := IndexOptionsAll()
+	var added, duplicates int32
+	var takeoutFiles []string
+	sidecars := newTakeoutSidecars()
+
+	// Google Takeout sidecars are read when importing their media files, but aren't imported themselves.
+	takeout := func(m *MediaFile) bool {
+		return m.IsTakeout() || opt.Takeout && m.HasFileType(fs.TypeJson)
+	}
+	ignore := fs.NewIgnoreList(importPath, imp.conf.IgnorePatterns())
 
 	err := filepath.Walk(importPath, func(fileName string, fileInfo os.FileInfo, err error) error {
 		defer func() {
@@ -86,7 +116,7 @@ func (imp *Import) Start(opt ImportOptions) {
 			}
 		}()
 
-		if mutex.Worker.Canceled() {
+		if mutex.Worker.Canceled() || ctx.Err() != nil {
 			return errors.New("import canceled")
 		}
 
@@ -95,10 +125,25 @@ func (imp *Import) Start(opt ImportOptions) {
 		}
 
 		if fileInfo.IsDir() {
+			// Ignored directories are skipped without reading their contents.
+			if ignore.Ignore(fileName, true) {
+				return filepath.SkipDir
+			}
+
 			if fileName != importPath {
 				directories = append(directories, fileName)
 			}
 
+			// Folders completed by an interrupted run are skipped when resuming.
+			if ind.progress.CompletedDir(fileName) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		// Files completed by an interrupted run are skipped when resuming.
+		if ind.progress.Completed(fileName) {
 			return nil
 		}
 
@@ -116,9 +161,23 @@ func (imp *Import) Start(opt ImportOptions) {
 			return nil
 		}
 
+		if ignore.Ignore(fileName, false) {
+			return nil
+		}
+
 		mf, err := NewMediaFile(fileName)
 
-		if err != nil || !mf.IsPhoto() {
+		if err != nil {
+			return nil
+		}
+
+		if takeout(mf) {
+			done[fileName] = true
+			takeoutFiles = append(takeoutFiles, fileName)
+			return nil
+		}
+
+		if !mf.IsPhoto() {
 			return nil
 		}
 
@@ -133,24 +192,40 @@ func (imp *Import) Start(opt ImportOptions) {
 		var files MediaFiles
 
 		for _, f := range related.Files {
-			if done[f.FileName()] {
+			if done[f.FileName()] || ignore.Ignore(f.FileName(), false) {
 				continue
 			}
 
-			files = append(files, f)
 			done[f.FileName()] = true
+
+			if takeout(f) {
+				takeoutFiles = append(takeoutFiles, f.FileName())
+				continue
+			}
+
+			files = append(files, f)
 		}
 
 		done[mf.FileName()] = true
 
 		related.Files = files
 
+		if related.Main != nil {
+			if takeoutName := related.Main.TakeoutName(); takeoutName != "" {
+				related.Main.SetTakeout(takeoutName)
+			}
+		}
+
 		jobs <- ImportJob{
-			FileName:  fileName,
-			Related:   related,
-			IndexOpt:  indexOpt,
-			ImportOpt: opt,
-			Imp:       imp,
+			FileName:   fileName,
+			Related:    related,
+			IndexOpt:   indexOpt,
+			ImportOpt:  opt,
+			Imp:        imp,
+			Added:      &added,
+			Duplicates: &duplicates,
+			step:       ind.progress.Add(fileName),
+			sidecars:   sidecars,
 		}
 
 		return nil
@@ -159,6 +234,25 @@ func (imp *Import) Start(opt ImportOptions) {
 	close(jobs)
 	wg.Wait()
 
+	ind.progress.Finish(err != nil)
+	ind.updateLabelCounts()
+
+	result.Added = int(added)
+	result.Duplicates = int(duplicates)
+
+	if duplicates > 0 {
+		event.Info(fmt.Sprintf("skipped %d duplicate files", duplicates))
+	}
+
+	// Takeout sidecars may be shared by edited copies, so they are removed after all files were imported.
+	if opt.Move {
+		for _, fileName := range sidecars.Removable(takeoutFiles) {
+			if err := os.Remove(fileName); err != nil {
+				log.Errorf("import: could not remove takeout sidecar \"%s\" (%s)", fileName, err.Error())
+			}
+		}
+	}
+
 	sort.Slice(directories, func(i, j int) bool {
 		return len(directories[i]) > len(directories[j])
 	})
@@ -179,6 +273,8 @@ func (imp *Import) Start(opt ImportOptions) {
 	if err != nil {
 		log.Error(err.Error())
 	}
+
+	return result
 }
 
 // Cancel stops the current import operation.
@@ -186,34 +282,52 @@ func (imp *Import) Cancel() {
 	mutex.Worker.Cancel()
 }
 
-// DestinationFilename returns the destination filename of a MediaFile to be imported.
+// Duplicate returns the indexed file with the same content as a media file, so that it
+// doesn't need to be imported again. Sidecar files are never considered duplicates.
+func (imp *Import) Duplicate(mediaFile *MediaFile) (entity.File, bool) {
+	if mediaFile.IsSidecar() {
+		return entity.File{}, false
+	}
+
+	f, err := entity.FirstFileByHash(imp.conf.Db(), mediaFile.Hash())
+
+	return f, err == nil
+}
+
+// Trashed returns true if the photo of an existing file was deleted and is in the trash.
+func (imp *Import) Trashed(f entity.File) bool {
+	var count int
+
+	imp.conf.Db().Unscoped().Model(&entity.Photo{}).Where("id = ? AND trashed_at IS NOT NULL", f.PhotoID).Count(&count)
+
+	return count > 0
+}
+
+// DestinationFilename returns the destination filename of a MediaFile to be imported, based on
+// the import path pattern. A numeric suffix is added if a different file with the same name exists.
+// Related files get the same suffix as the main file, so that they are still found as related.
 func (imp *Import) DestinationFilename(mainFile *MediaFile, mediaFile *MediaFile) (string, error) {
-	fileName := mainFile.CanonicalName()
+	pathName := path.Join(imp.originalsPath(), ImportPathName(imp.conf.ImportPathPattern(), mainFile))
+	mainExtension := mainFile.Extension()
 	fileExtension := mediaFile.Extension()
-	dateCreated := mainFile.DateCreated()
 
-	if !mediaFile.IsSidecar() {
-		if f, err := entity.FirstFileByHash(imp.conf.Db(), mediaFile.Hash()); err == nil {
-			existingFilename := imp.conf.OriginalsPath() + string(os.PathSeparator) + f.FileName
-			return existingFilename, fmt.Errorf("\"%s\" is identical to \"%s\" (%s)", mediaFile.FileName(), f.FileName, mediaFile.Hash())
-		}
+	// taken returns true if a different file already exists.
+	taken := func(fileName string, m *MediaFile) bool {
+		return fs.FileExists(fileName) && m.Hash() != fs.Hash(fileName)
 	}
 
-	//	Mon Jan 2 15:04:05 -0700 MST 2006
-	pathName := path.Join(imp.originalsPath(), dateCreated.Format("2006/01"))
-
 	iteration := 0
+	prefix := pathName
 
-	result := path.Join(pathName, fileName+fileExtension)
-
-	for fs.FileExists(result) {
-		if mediaFile.Hash() == fs.Hash(result) {
-			return result, fmt.Errorf("file already exists: %s", result)
-		}
-
+	for taken(prefix+mainExtension, mainFile) || taken(prefix+fileExtension, mediaFile) {
 		iteration++
+		prefix = pathName + "." + fmt.Sprintf("%04d", iteration)
+	}
+
+	result := prefix + fileExtension
 
-		result = path.Join(pathName, fileName+"."+fmt.Sprintf("%04d", iteration)+fileExtension)
+	if fs.FileExists(result) {
+		return result, fmt.Errorf("file already exists: %s", result)
 	}
 
 	return result, nil
@@ -6,6 +6,9 @@ type ImportOptions struct {
 	RemoveDotFiles         bool
 	RemoveExistingFiles    bool
 	RemoveEmptyDirectories bool
+	Force                  bool // Import files even if they have been indexed before.
+	Takeout                bool // Treat all JSON files as Google Takeout metadata instead of importing them.
+	Resume                 bool // Skip folders completed by the most recent run if it was interrupted.
 }
 
 // ImportOptionsCopy returns import options for copying files to originals (read-only).
@@ -0,0 +1,41 @@
+package photoprism
+
+import (
+	"path"
+	"strings"
+)
+
+// pathNameReplacer replaces characters that are not allowed in file and directory names.
+var pathNameReplacer = strings.NewReplacer("/", "-", "\\", "-", ":", "-", "*", "", "?", "", "\"", "", "<", "", ">", "", "|", "")
+
+// ImportPathName returns the destination of an imported main file relative to the originals path,
+// without file extension. Tokens in the pattern are replaced by values of the main file:
+//
+//   {year}, {month}, {day}: date created, e.g. "2019", "07" and "05"
+//   {camera}: camera model or make, "Unknown" if not available
+//   {filename}: original file name without extension
+//   {canonical}: canonical name based on date created and checksum, see CanonicalName
+func ImportPathName(pattern string, mainFile *MediaFile) string {
+	date := mainFile.DateCreated()
+
+	camera := strings.TrimSpace(mainFile.CameraModel())
+
+	if camera == "" {
+		camera = strings.TrimSpace(mainFile.CameraMake())
+	}
+
+	if camera == "" {
+		camera = "Unknown"
+	}
+
+	r := strings.NewReplacer(
+		"{year}", date.Format("2006"),
+		"{month}", date.Format("01"),
+		"{day}", date.Format("02"),
+		"{camera}", pathNameReplacer.Replace(camera),
+		"{filename}", pathNameReplacer.Replace(mainFile.Base(false)),
+		"{canonical}", mainFile.CanonicalName(),
+	)
+
+	return path.Clean(r.Replace(pattern))
+}
@@ -0,0 +1,70 @@
+package photoprism
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// takeoutSidecars tracks if the media files of Google Takeout sidecars were imported, so that sidecars
+// are only removed in move mode once all media files reading metadata from them are gone.
+type takeoutSidecars struct {
+	mutex    sync.Mutex
+	imported map[string]bool
+}
+
+// newTakeoutSidecars returns a new sidecar tracker.
+func newTakeoutSidecars() *takeoutSidecars {
+	return &takeoutSidecars{imported: make(map[string]bool)}
+}
+
+// Done records if a media file reading metadata from the sidecar was imported. A sidecar
+// is kept if at least one of its media files wasn't imported.
+func (t *takeoutSidecars) Done(fileName string, imported bool) {
+	if t == nil || fileName == "" {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if done, ok := t.imported[fileName]; ok && !done {
+		return
+	}
+
+	t.imported[fileName] = imported
+}
+
+// Removable returns the files of fileNames that may be removed. Sidecars of media files are removable
+// if all their media files were imported, and kept if none was found. Other JSON files, like album
+// metadata, are kept if a sidecar in the same folder is kept, or if no media file of the folder was imported.
+func (t *takeoutSidecars) Removable(fileNames []string) (result []string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	imported := make(map[string]bool)
+	kept := make(map[string]bool)
+
+	for fileName, done := range t.imported {
+		dir := filepath.Dir(fileName)
+
+		if done {
+			imported[dir] = true
+		} else {
+			kept[dir] = true
+		}
+	}
+
+	for _, fileName := range fileNames {
+		if done, ok := t.imported[fileName]; ok {
+			if done {
+				result = append(result, fileName)
+			}
+		} else if m, err := NewMediaFile(fileName); err != nil || m.IsTakeout() {
+			continue
+		} else if dir := filepath.Dir(fileName); imported[dir] && !kept[dir] {
+			result = append(result, fileName)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package photoprism
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeoutSidecars_Removable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "takeout")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	album := filepath.Join(dir, "Berlin 2020")
+	other := filepath.Join(dir, "Paris 2019")
+
+	for _, name := range []string{
+		filepath.Join(album, "IMG_1234.jpg.json"),
+		filepath.Join(album, "IMG_1235.jpg.json"),
+		filepath.Join(album, "IMG_1236.jpg.json"),
+		filepath.Join(album, "metadata.json"),
+		filepath.Join(other, "IMG_2000.jpg.json"),
+		filepath.Join(other, "metadata.json"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(name, []byte("{}"), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := []string{
+		filepath.Join(album, "IMG_1234.jpg.json"),
+		filepath.Join(album, "IMG_1235.jpg.json"),
+		filepath.Join(album, "IMG_1236.jpg.json"),
+		filepath.Join(album, "metadata.json"),
+		filepath.Join(other, "IMG_2000.jpg.json"),
+		filepath.Join(other, "metadata.json"),
+	}
+
+	t.Run("all imported", func(t *testing.T) {
+		s := newTakeoutSidecars()
+
+		s.Done(files[0], true)
+		s.Done(files[1], true)
+		s.Done(files[4], true)
+
+		// IMG_1236.jpg wasn't found, so its sidecar is kept.
+		assert.Equal(t, []string{files[0], files[1], files[3], files[4], files[5]}, s.Removable(files))
+	})
+	t.Run("edited copy failed", func(t *testing.T) {
+		s := newTakeoutSidecars()
+
+		s.Done(files[0], true)
+		s.Done(files[0], false)
+		s.Done(files[0], true)
+		s.Done(files[1], true)
+
+		// Album metadata is kept as long as sidecars in the same folder are kept.
+		assert.Equal(t, []string{files[1]}, s.Removable(files))
+	})
+	t.Run("nothing imported", func(t *testing.T) {
+		assert.Empty(t, newTakeoutSidecars().Removable(files))
+	})
+	t.Run("nil", func(t *testing.T) {
+		var s *takeoutSidecars
+
+		s.Done(files[0], true)
+	})
+}
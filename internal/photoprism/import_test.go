@@ -1,11 +1,17 @@
 package photoprism
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/classify"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/nsfw"
+	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,3 +77,316 @@ func TestImport_Start(t *testing.T) {
 
 	imp.Start(opt)
 }
+
+func TestImport_DestinationFilename_Collision(t *testing.T) {
+	conf := config.TestConfig()
+
+	conf.InitializeTestData(t)
+
+	tf := classify.New(conf.ResourcesPath(), conf.DisableTensorFlow())
+	nd := nsfw.New(conf.NSFWModelPath())
+
+	imp := NewImport(conf, NewIndex(conf, tf, nd), NewConvert(conf))
+
+	rawFile, err := NewMediaFile(conf.ImportPath() + "/raw/IMG_2567.CR2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xmpName := conf.ImportPath() + "/raw/IMG_2567.xmp"
+
+	if err := ioutil.WriteFile(xmpName, []byte("<x:xmpmeta/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(xmpName)
+
+	xmpFile, err := NewMediaFile(xmpName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A different file with the same name already exists.
+	existing := conf.OriginalsPath() + "/2019/07/20190705_153230_C167C6FD.cr2"
+
+	if err := os.MkdirAll(filepath.Dir(existing), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(existing, []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(existing)
+
+	fileName, err := imp.DestinationFilename(rawFile, rawFile)
+
+	assert.Nil(t, err)
+	assert.Equal(t, conf.OriginalsPath()+"/2019/07/20190705_153230_C167C6FD.0001.cr2", fileName)
+
+	// Related files get the same suffix as the main file.
+	fileName, err = imp.DestinationFilename(rawFile, xmpFile)
+
+	assert.Nil(t, err)
+	assert.Equal(t, conf.OriginalsPath()+"/2019/07/20190705_153230_C167C6FD.0001.xmp", fileName)
+
+	// Identical files are not imported twice.
+	if err := xmpFile.Copy(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(fileName)
+
+	_, err = imp.DestinationFilename(rawFile, xmpFile)
+
+	assert.EqualError(t, err, "file already exists: "+fileName)
+}
+
+func TestImportPathName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "import")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	f, err := NewMediaFile("../meta/testdata/photoshop.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(dir, "Event: Party.jpg")
+
+	if err := f.Copy(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewMediaFile(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := m.DateCreated()
+
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, date.Format("2006/01/")+m.CanonicalName(), ImportPathName(config.DefaultImportPathPattern, m))
+	})
+	t.Run("date and file name", func(t *testing.T) {
+		assert.Equal(t, date.Format("2006/2006-01-02/")+"Event- Party", ImportPathName("{year}/{year}-{month}-{day}/{filename}", m))
+	})
+	t.Run("camera", func(t *testing.T) {
+		camera := m.CameraModel()
+
+		if camera == "" {
+			camera = m.CameraMake()
+		}
+
+		if camera == "" {
+			camera = "Unknown"
+		}
+
+		assert.Equal(t, camera+"/Event- Party", ImportPathName("{camera}/{filename}", m))
+	})
+}
+
+func TestImportWorker_Duplicates(t *testing.T) {
+	conf := config.TestConfig()
+
+	tf := classify.New(conf.ResourcesPath(), conf.DisableTensorFlow())
+	nd := nsfw.New(conf.NSFWModelPath())
+
+	imp := NewImport(conf, NewIndex(conf, tf, nd), NewConvert(conf))
+
+	run := func(t *testing.T, opt func(string) ImportOptions) (dir string, duplicates int32) {
+		dir, err := ioutil.TempDir("", "import")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for src, dest := range map[string]string{"photoshop.jpg": "IMG_0001.jpg", "photoshop.xmp": "IMG_0001.xmp"} {
+			f, err := NewMediaFile(filepath.Join("../meta/testdata", src))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := f.Copy(filepath.Join(dir, dest)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		mf, err := NewMediaFile(filepath.Join(dir, "IMG_0001.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := mf.RelatedFiles(false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, related.Files, 2)
+
+		jobs := make(chan ImportJob, 1)
+
+		jobs <- ImportJob{
+			FileName:   mf.FileName(),
+			Related:    related,
+			IndexOpt:   IndexOptionsAll(),
+			ImportOpt:  opt(dir),
+			Imp:        imp,
+			Duplicates: &duplicates,
+		}
+
+		close(jobs)
+
+		ImportWorker(jobs)
+
+		dest, err := imp.DestinationFilename(related.Main, related.Main)
+
+		assert.Nil(t, err)
+		assert.False(t, fs.FileExists(dest))
+
+		return dir, duplicates
+	}
+
+	// The same content was indexed before under a different name.
+	existing := entity.File{FileName: "2020/01/20200101_120000_ABCDEF12.jpg", FileHash: fs.Hash("../meta/testdata/photoshop.jpg")}
+
+	if err := conf.Db().Create(&existing).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer conf.Db().Unscoped().Delete(&existing)
+
+	t.Run("copy", func(t *testing.T) {
+		dir, duplicates := run(t, ImportOptionsCopy)
+		defer os.RemoveAll(dir)
+
+		assert.Equal(t, int32(2), duplicates)
+		assert.True(t, fs.FileExists(filepath.Join(dir, "IMG_0001.jpg")))
+		assert.True(t, fs.FileExists(filepath.Join(dir, "IMG_0001.xmp")))
+	})
+	t.Run("move", func(t *testing.T) {
+		dir, duplicates := run(t, ImportOptionsMove)
+		defer os.RemoveAll(dir)
+
+		assert.Equal(t, int32(2), duplicates)
+		assert.False(t, fs.FileExists(filepath.Join(dir, "IMG_0001.jpg")))
+		assert.False(t, fs.FileExists(filepath.Join(dir, "IMG_0001.xmp")))
+	})
+}
+
+func TestImport_Takeout(t *testing.T) {
+	importPath, err := ioutil.TempDir("", "photoprism-takeout")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(importPath)
+
+	originalsPath, err := ioutil.TempDir("", "photoprism-originals")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(originalsPath)
+
+	conf := config.TestConfigRoots(originalsPath)
+
+	albumPath := filepath.Join(importPath, "Google Photos", "Berlin 2020")
+
+	if err := os.MkdirAll(albumPath, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures, err := filepath.Glob("testdata/takeout/*")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fileName := range fixtures {
+		f, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Copy(filepath.Join(albumPath, filepath.Base(fileName))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tf := classify.New(conf.ResourcesPath(), true)
+	nd := nsfw.New(conf.NSFWModelPath())
+
+	imp := NewImport(conf, NewIndex(conf, tf, nd), NewConvert(conf))
+
+	opt := ImportOptionsMove(importPath)
+	opt.Takeout = true
+
+	res := imp.Start(opt)
+
+	assert.Equal(t, 3, res.Added)
+	assert.Equal(t, 0, res.Duplicates)
+
+	// All sidecars were read and removed, instead of being imported.
+	assert.True(t, fs.IsEmpty(importPath))
+
+	sidecars, err := filepath.Glob(filepath.Join(originalsPath, "*", "*", "*.json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, sidecars)
+
+	expected := map[string]time.Time{
+		"IMG_1234.jpg":        time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC),
+		"IMG_1234-edited.jpg": time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC),
+		"IMG_1235(1).jpg":     time.Date(2019, 7, 4, 20, 30, 15, 0, time.UTC),
+	}
+
+	for name, takenAt := range expected {
+		file, err := entity.FirstFileByHash(conf.Db(), fs.Hash(filepath.Join("testdata/takeout", name)))
+
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		var photo entity.Photo
+
+		if err := conf.Db().Preload("Description").First(&photo, file.PhotoID).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		defer conf.Db().Unscoped().Delete(&photo)
+		defer conf.Db().Unscoped().Delete(&file)
+
+		assert.Equal(t, takenAt, photo.TakenAt.UTC(), name)
+		assert.Equal(t, takenAt.Format("2006/01"), photo.PhotoPath, name)
+
+		if name == "IMG_1235(1).jpg" {
+			// Google exports missing positions as 0, 0.
+			assert.Equal(t, float32(0), photo.PhotoLat, name)
+			assert.Equal(t, float32(0), photo.PhotoLng, name)
+			continue
+		}
+
+		assert.Equal(t, float32(52.5163), photo.PhotoLat, name)
+		assert.Equal(t, float32(13.3777), photo.PhotoLng, name)
+		assert.Equal(t, "Brandenburg Gate at night", photo.Description.PhotoDescription, name)
+		assert.Contains(t, photo.Description.PhotoKeywords, "Jens Mander", name)
+	}
+}
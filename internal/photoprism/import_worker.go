@@ -4,128 +4,226 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sync/atomic"
 
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/mutex"
 )
 
 type ImportJob struct {
-	FileName  string
-	Related   RelatedFiles
-	IndexOpt  IndexOptions
-	ImportOpt ImportOptions
-	Imp       *Import
+	FileName   string
+	Related    RelatedFiles
+	IndexOpt   IndexOptions
+	ImportOpt  ImportOptions
+	Imp        *Import
+	Added      *int32 // Optional counter for files added to the index.
+	Duplicates *int32 // Optional counter for skipped duplicates.
+	step       *runStep
+	sidecars   *takeoutSidecars
 }
 
 func ImportWorker(jobs <-chan ImportJob) {
 	for job := range jobs {
-		var destinationMainFilename string
-		related := job.Related
-		imp := job.Imp
-		opt := job.ImportOpt
-		indexOpt := job.IndexOpt
-		importPath := job.ImportOpt.Path
-
-		if related.Main == nil {
-			log.Warnf("import: no main file found for %s", job.FileName)
-			continue
+		nice()
+		importJob(job)
+
+		// Jobs are only completed if they weren't stopped by Cancel().
+		if !mutex.Worker.Canceled() {
+			job.step.Done()
 		}
+	}
+}
 
-		originalName := related.Main.RelativeName(importPath)
+// countAdded increments the optional counter of added files if a file was added to the index.
+func (job ImportJob) countAdded(res IndexResult) {
+	if job.Added != nil && res.Status == IndexAdded {
+		atomic.AddInt32(job.Added, 1)
+	}
+}
 
-		event.Publish("import.file", event.Data{
-			"fileName": originalName,
-			"baseName": filepath.Base(related.Main.FileName()),
-		})
+// importJob moves or copies the files of a job to the originals folder and indexes them.
+func importJob(job ImportJob) {
+	var destinationMainFilename string
+	related := job.Related
+	imp := job.Imp
+	opt := job.ImportOpt
+	indexOpt := job.IndexOpt
+	importPath := job.ImportOpt.Path
+
+	if related.Main == nil {
+		log.Warnf("import: no main file found for %s", job.FileName)
+		return
+	}
 
-		for _, f := range related.Files {
-			relativeFilename := f.RelativeName(importPath)
+	originalName := related.Main.RelativeName(importPath)
 
-			if destinationFilename, err := imp.DestinationFilename(related.Main, f); err == nil {
-				if err := os.MkdirAll(path.Dir(destinationFilename), os.ModePerm); err != nil {
-					log.Errorf("import: could not create directories (%s)", err.Error())
-				}
+	// Takeout sidecars may only be removed if the main file was imported, see takeoutSidecars.
+	var imported bool
 
-				if related.Main.HasSameName(f) {
-					destinationMainFilename = destinationFilename
-					log.Infof("import: moving main %s file \"%s\" to \"%s\"", f.FileType(), relativeFilename, destinationFilename)
-				} else {
-					log.Infof("import: moving related %s file \"%s\" to \"%s\"", f.FileType(), relativeFilename, destinationFilename)
-				}
+	defer func() { job.sidecars.Done(related.Main.Takeout(), imported) }()
 
-				if opt.Move {
-					if err := f.Move(destinationFilename); err != nil {
-						log.Errorf("import: could not move file to %s (%s)", destinationMainFilename, err.Error())
-					}
-				} else {
-					if err := f.Copy(destinationFilename); err != nil {
-						log.Errorf("import: could not copy file to %s (%s)", destinationMainFilename, err.Error())
-					}
-				}
-			} else if opt.RemoveExistingFiles {
+	event.Publish("import.file", event.Data{
+		"fileName": originalName,
+		"baseName": filepath.Base(related.Main.FileName()),
+		"runId":    imp.index.progress.RunID(),
+		"resumed":  imp.index.progress.Resumed(),
+	})
+
+	// Files that were indexed before are skipped without copying them again,
+	// including sidecar files of a duplicate main file.
+	var mainDuplicate bool
+
+	if !opt.Force {
+		if existing, ok := imp.Duplicate(related.Main); ok {
+			mainDuplicate = true
+
+			if imp.Trashed(existing) {
+				// Deleted photos should be restored instead of importing them again.
+				log.Infof("import: skipped %s (duplicate of deleted photo %s)", originalName, existing.PhotoUUID)
+
+				event.Publish("import.trashed", event.Data{
+					"fileName":  originalName,
+					"photoUUID": existing.PhotoUUID,
+				})
+			} else {
+				log.Infof("import: skipped %s (duplicate of \"%s\")", originalName, existing.FileName)
+			}
+		}
+	}
+
+	for _, f := range related.Files {
+		relativeFilename := f.RelativeName(importPath)
+
+		duplicate := mainDuplicate && (f.IsSidecar() || related.Main.HasSameName(f))
+
+		if !duplicate && !opt.Force {
+			if existing, ok := imp.Duplicate(f); ok {
+				duplicate = true
+				log.Infof("import: skipped %s (duplicate of \"%s\")", relativeFilename, existing.FileName)
+			}
+		}
+
+		if duplicate {
+			if job.Duplicates != nil {
+				atomic.AddInt32(job.Duplicates, 1)
+			}
+
+			if opt.RemoveExistingFiles {
 				if err := f.Remove(); err != nil {
 					log.Errorf("import: could not delete %s (%s)", f.FileName(), err.Error())
 				} else {
-					log.Infof("import: deleted %s (already exists)", relativeFilename)
+					log.Infof("import: deleted %s (duplicate)", relativeFilename)
+					imported = imported || related.Main.HasSameName(f)
 				}
 			}
-		}
 
-		if destinationMainFilename != "" {
-			importedMainFile, err := NewMediaFile(destinationMainFilename)
+			continue
+		}
 
-			if err != nil {
-				log.Errorf("import: could not index \"%s\" (%s)", destinationMainFilename, err.Error())
+		destinationFilename, err := imp.DestinationFilename(related.Main, f)
 
-				continue
+		if err == nil {
+			if err := os.MkdirAll(path.Dir(destinationFilename), os.ModePerm); err != nil {
+				log.Errorf("import: could not create directories (%s)", err.Error())
 			}
 
-			if importedMainFile.IsRaw() || importedMainFile.IsHEIF() || importedMainFile.IsImageOther() {
-				if _, err := imp.convert.ToJpeg(importedMainFile); err != nil {
-					log.Errorf("import: creating jpeg failed (%s)", err.Error())
-				}
+			if related.Main.HasSameName(f) {
+				destinationMainFilename = destinationFilename
+				log.Infof("import: moving main %s file \"%s\" to \"%s\"", f.FileType(), relativeFilename, destinationFilename)
+			} else {
+				log.Infof("import: moving related %s file \"%s\" to \"%s\"", f.FileType(), relativeFilename, destinationFilename)
 			}
 
-			if jpg, err := importedMainFile.Jpeg(); err != nil {
-				log.Error(err)
+			if opt.Move {
+				if err := f.Move(destinationFilename); err != nil {
+					log.Errorf("import: could not move file to %s (%s)", destinationMainFilename, err.Error())
+				}
 			} else {
-				if err := jpg.ResampleDefault(imp.conf.ThumbnailsPath(), false); err != nil {
-					log.Errorf("import: could not create default thumbnails (%s)", err.Error())
+				if err := f.Copy(destinationFilename); err != nil {
+					log.Errorf("import: could not copy file to %s (%s)", destinationMainFilename, err.Error())
 				}
 			}
+		} else if opt.RemoveExistingFiles {
+			if err := f.Remove(); err != nil {
+				log.Errorf("import: could not delete %s (%s)", f.FileName(), err.Error())
+			} else {
+				log.Infof("import: deleted %s (already exists)", relativeFilename)
+			}
+		} else {
+			log.Infof("import: skipped %s (%s)", relativeFilename, err.Error())
+		}
+	}
 
-			related, err := importedMainFile.RelatedFiles(imp.conf.Settings().Library.GroupRelated)
+	if destinationMainFilename != "" {
+		importedMainFile, err := NewMediaFile(destinationMainFilename)
 
-			if err != nil {
-				log.Errorf("import: could not index \"%s\" (%s)", destinationMainFilename, err.Error())
+		if err != nil {
+			log.Errorf("import: could not index \"%s\" (%s)", destinationMainFilename, err.Error())
 
-				continue
-			}
+			return
+		}
 
-			done := make(map[string]bool)
-			ind := imp.index
+		var convertErr error
 
-			if related.Main != nil {
-				res := ind.MediaFile(related.Main, indexOpt, originalName)
-				log.Infof("import: %s main %s file \"%s\"", res, related.Main.FileType(), related.Main.RelativeName(ind.originalsPath()))
-				done[related.Main.FileName()] = true
-			} else {
-				log.Warnf("import: no main file for %s (conversion to jpeg failed?)", destinationMainFilename)
+		if importedMainFile.IsRaw() || importedMainFile.IsHEIF() || importedMainFile.IsImageOther() || importedMainFile.IsVideo() && imp.conf.FFmpegBin() != "" {
+			if _, convertErr = imp.convert.ToJpeg(importedMainFile); convertErr != nil {
+				log.Errorf("import: creating jpeg failed (%s)", convertErr.Error())
 			}
+		}
 
-			for _, f := range related.Files {
-				if f == nil {
-					continue
-				}
+		if jpg, err := importedMainFile.Jpeg(); err != nil {
+			log.Error(err)
+		} else {
+			if err := jpg.ResampleDefault(imp.conf.ThumbnailsPath(), false); err != nil {
+				log.Errorf("import: could not create default thumbnails (%s)", err.Error())
+			}
+		}
 
-				if done[f.FileName()] {
-					continue
-				}
+		related, err := importedMainFile.RelatedFiles(imp.conf.Settings().Library.GroupRelated)
 
-				res := ind.MediaFile(f, indexOpt, "")
-				done[f.FileName()] = true
+		if err != nil {
+			log.Errorf("import: could not index \"%s\" (%s)", destinationMainFilename, err.Error())
 
-				log.Infof("import: %s related %s file \"%s\"", res, f.FileType(), f.RelativeName(ind.originalsPath()))
+			return
+		}
+
+		done := make(map[string]bool)
+		ind := imp.index
+
+		if related.Main != nil {
+			if takeoutName := job.Related.Main.Takeout(); takeoutName != "" {
+				log.Infof("import: reading metadata from takeout sidecar \"%s\"", filepath.Base(takeoutName))
+				related.Main.SetTakeout(takeoutName)
+			}
+
+			res := ind.MediaFile(related.Main, indexOpt, originalName)
+			job.countAdded(res)
+			imported = res.Success()
+			log.Infof("import: %s main %s file \"%s\"", res, related.Main.FileType(), related.Main.RelativeName(ind.originalsPath()))
+			done[related.Main.FileName()] = true
+		} else {
+			log.Warnf("import: no main file for %s (conversion to jpeg failed?)", destinationMainFilename)
+		}
+
+		for _, f := range related.Files {
+			if f == nil {
+				continue
+			}
+
+			if done[f.FileName()] {
+				continue
 			}
+
+			res := ind.MediaFile(f, indexOpt, "")
+			job.countAdded(res)
+			done[f.FileName()] = true
+
+			log.Infof("import: %s related %s file \"%s\"", res, f.FileType(), f.RelativeName(ind.originalsPath()))
+		}
+
+		// The failed conversion can only be recorded once the file is indexed.
+		if convertErr != nil {
+			imp.convert.SaveError(importedMainFile, convertErr)
 		}
 	}
 }
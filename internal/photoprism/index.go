@@ -1,16 +1,17 @@
 package photoprism
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/classify"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/nsfw"
@@ -25,6 +26,11 @@ type Index struct {
 	nsfwDetector *nsfw.Detector
 	db           *gorm.DB
 	q            *query.Query
+	cache        *indexCache
+	pending      *[]func()
+	progress     *runProgress
+	labelsMutex  sync.Mutex
+	labelIDs     map[uint]bool
 }
 
 // NewIndex returns a new indexer and expects its dependencies as arguments.
@@ -44,6 +50,12 @@ func (ind *Index) originalsPath() string {
 	return ind.conf.OriginalsPath()
 }
 
+// relativeName returns the name of a file relative to its originals root for log messages.
+func (ind *Index) relativeName(fileName string) string {
+	_, relName := rootName(ind.conf, fileName)
+	return relName
+}
+
 func (ind *Index) thumbnailsPath() string {
 	return ind.conf.ThumbnailsPath()
 }
@@ -53,16 +65,41 @@ func (ind *Index) Cancel() {
 	mutex.Worker.Cancel()
 }
 
-// Start indexes media files in the originals directory.
+// Start indexes media files in all originals roots, or only in the root given as options.Root. If a
+// sub-folder is given as options.Path, only this folder of the root is indexed.
 func (ind *Index) Start(options IndexOptions) map[string]bool {
+	return ind.StartContext(context.Background(), options)
+}
+
+// StartContext indexes media files like Start, but stops sending files to the workers when
+// the context is canceled. Interrupted runs can be resumed with options.Resume.
+func (ind *Index) StartContext(ctx context.Context, options IndexOptions) map[string]bool {
 	done := make(map[string]bool)
-	originalsPath := ind.originalsPath()
+	roots, err := selectRoots(ind.conf, options.Root, options.Path)
 
-	if !fs.PathExists(originalsPath) {
-		event.Error(fmt.Sprintf("index: %s does not exist", originalsPath))
+	if err != nil {
+		event.Error(fmt.Sprintf("index: %s", err.Error()))
 		return done
 	}
 
+	indexPaths := make([]string, len(roots))
+
+	for i, root := range roots {
+		indexPath, err := fs.SubPath(root.Path, options.Path)
+
+		if err != nil {
+			event.Error(fmt.Sprintf("index: %s", err.Error()))
+			return done
+		}
+
+		if !fs.PathExists(indexPath) {
+			event.Error(fmt.Sprintf("index: %s does not exist", indexPath))
+			return done
+		}
+
+		indexPaths[i] = indexPath
+	}
+
 	if err := mutex.Worker.Start(); err != nil {
 		event.Error(fmt.Sprintf("index: %s", err.Error()))
 		return done
@@ -76,27 +113,132 @@ func (ind *Index) Start(options IndexOptions) map[string]bool {
 		return done
 	}
 
-	jobs := make(chan IndexJob)
+	batches := make(chan IndexBatch)
 
-	// Start a fixed number of goroutines to index files.
+	// Start a fixed number of goroutines to index batches of files.
 	var wg sync.WaitGroup
 	var numWorkers = ind.conf.Workers()
 	wg.Add(numWorkers)
 	for i := 0; i < numWorkers; i++ {
 		go func() {
-			IndexWorker(jobs) // HLc
+			IndexBatchWorker(batches) // HLc
 			wg.Done()
 		}()
 	}
 
-	err := filepath.Walk(originalsPath, func(fileName string, fileInfo os.FileInfo, err error) error {
+	ind.progress = newRunProgress(ind.db, entity.RunIndex, options.Root+":"+options.Path, options.Resume)
+	defer func() { ind.progress = nil }()
+
+	// Roots before the one that contains the cursor were completed by the interrupted run.
+	from := ind.progress.resumeFrom(indexPaths)
+
+	for i, root := range roots {
+		if i < from {
+			continue
+		}
+
+		if err = ind.walk(ctx, root, indexPaths[i], options, done, batches); err != nil {
+			break
+		}
+
+		ind.progress.Passed()
+	}
+
+	close(batches)
+	wg.Wait()
+
+	ind.progress.Finish(err != nil)
+
+	if err != nil {
+		log.Error(err.Error())
+	}
+
+	ind.updateLabelCounts()
+
+	return done
+}
+
+// labelsChanged remembers the labels of an indexed photo, so that only their photo counts need to be updated.
+func (ind *Index) labelsChanged(labels []entity.PhotoLabel) {
+	if len(labels) == 0 {
+		return
+	}
+
+	ind.labelsMutex.Lock()
+	defer ind.labelsMutex.Unlock()
+
+	if ind.labelIDs == nil {
+		ind.labelIDs = make(map[uint]bool)
+	}
+
+	for _, l := range labels {
+		ind.labelIDs[l.LabelID] = true
+	}
+}
+
+// updateLabelCounts updates the photo counts of labels changed since it was last called.
+func (ind *Index) updateLabelCounts() {
+	ind.labelsMutex.Lock()
+	ids := make([]uint, 0, len(ind.labelIDs))
+
+	for id := range ind.labelIDs {
+		ids = append(ids, id)
+	}
+
+	ind.labelIDs = nil
+	ind.labelsMutex.Unlock()
+
+	if len(ids) == 0 {
+		return
+	}
+
+	if _, err := query.New(ind.db).UpdateLabelCounts(ids...); err != nil {
+		log.Errorf("index: %s", err)
+	}
+}
+
+// walk sends index jobs for all media files in indexPath, which must be inside the originals root. Jobs
+// are sent in batches of files in the same folder.
+func (ind *Index) walk(ctx context.Context, root config.Root, indexPath string, options IndexOptions, done map[string]bool, batches chan IndexBatch) error {
+	ignore := fs.NewIgnoreList(root.Path, ind.conf.IgnorePatterns())
+	batchSize := ind.conf.IndexBatchSize()
+
+	var batch []IndexJob
+	var batchDir string
+
+	flush := func() {
+		if len(batch) > 0 {
+			batches <- IndexBatch{Jobs: batch, Ind: ind}
+			batch = nil
+		}
+	}
+	trashPath := ind.conf.RootTrashPath(root.Name)
+
+	// Only files with recorded errors are processed again when retrying.
+	var failed map[string]bool
+
+	if options.RetryErrors {
+		fileNames, err := ind.q.FileErrorNames(root.Name)
+
+		if err != nil {
+			return err
+		}
+
+		failed = make(map[string]bool, len(fileNames))
+
+		for _, fileName := range fileNames {
+			failed[filepath.Join(root.Path, fileName)] = true
+		}
+	}
+
+	err := filepath.Walk(indexPath, func(fileName string, fileInfo os.FileInfo, err error) error {
 		defer func() {
 			if err := recover(); err != nil {
 				log.Errorf("index: %s [panic]", err)
 			}
 		}()
 
-		if mutex.Worker.Canceled() {
+		if mutex.Worker.Canceled() || ctx.Err() != nil {
 			return errors.New("indexing canceled")
 		}
 
@@ -104,13 +246,28 @@ func (ind *Index) Start(options IndexOptions) map[string]bool {
 			return nil
 		}
 
-		hidden := strings.HasPrefix(filepath.Base(fileName), ".")
+		// Folders and files completed by an interrupted run are skipped when resuming.
+		if fileInfo.IsDir() && ind.progress.CompletedDir(fileName) {
+			return filepath.SkipDir
+		} else if !fileInfo.IsDir() && ind.progress.Completed(fileName) {
+			return nil
+		}
 
-		if fileInfo.IsDir() && hidden {
+		// Files of deleted photos are never indexed, even if hidden folders are not ignored.
+		if fileInfo.IsDir() && fileName == trashPath {
 			return filepath.SkipDir
 		}
 
-		if fileInfo.IsDir() || hidden {
+		// Ignored directories are skipped without reading their contents.
+		if ignore.Ignore(fileName, fileInfo.IsDir()) {
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if fileInfo.IsDir() {
 			return nil
 		}
 
@@ -120,7 +277,8 @@ func (ind *Index) Start(options IndexOptions) map[string]bool {
 			return nil
 		}
 
-		related, err := mf.RelatedFiles(ind.conf.Settings().Library.GroupRelated)
+		groupRelated := ind.conf.Settings().Library.GroupRelated
+		related, err := mf.StackedFiles(groupRelated, sidecarBase(ind.conf, mf, groupRelated), ind.conf.StackSuffixes(), ind.conf.StackPrimary())
 
 		if err != nil {
 			log.Warnf("index: %s", err.Error())
@@ -128,10 +286,22 @@ func (ind *Index) Start(options IndexOptions) map[string]bool {
 			return nil
 		}
 
+		if options.RetryErrors {
+			retry := false
+
+			for _, f := range related.Files {
+				retry = retry || failed[f.FileName()]
+			}
+
+			if !retry {
+				return nil
+			}
+		}
+
 		var files MediaFiles
 
 		for _, f := range related.Files {
-			if done[f.FileName()] {
+			if done[f.FileName()] || ignore.Ignore(f.FileName(), false) {
 				continue
 			}
 
@@ -143,22 +313,111 @@ func (ind *Index) Start(options IndexOptions) map[string]bool {
 
 		related.Files = files
 
-		jobs <- IndexJob{
+		if dir := filepath.Dir(mf.FileName()); dir != batchDir || len(batch) >= batchSize {
+			flush()
+			batchDir = dir
+		}
+
+		batch = append(batch, IndexJob{
 			FileName: mf.FileName(),
 			Related:  related,
 			IndexOpt: options,
 			Ind:      ind,
-		}
+			step:     ind.progress.Add(mf.FileName()),
+		})
 
 		return nil
 	})
 
-	close(jobs)
-	wg.Wait()
+	if err == nil {
+		flush()
+	}
 
-	if err != nil {
-		log.Error(err.Error())
+	return err
+}
+
+// Cleanup removes files matching an ignore pattern from the index, for example after a
+// .ppignore file was added. Photos without remaining files are deleted as well.
+func (ind *Index) Cleanup() (deleted int, err error) {
+	ignore := make(map[string]*fs.IgnoreList)
+
+	for _, root := range ind.conf.OriginalsRoots() {
+		ignore[root.Name] = fs.NewIgnoreList(root.Path, ind.conf.IgnorePatterns())
 	}
 
-	return done
+	var files []entity.File
+
+	if err := ind.db.Select("id, photo_id, file_root, file_name").Find(&files).Error; err != nil {
+		return 0, err
+	}
+
+	for _, f := range files {
+		// Files of roots that aren't configured anymore are kept.
+		if ignore[f.Root()] == nil || !ignore[f.Root()].Ignore(ind.conf.OriginalsFileName(f.FileRoot, f.FileName), false) {
+			continue
+		}
+
+		if err := ind.db.Delete(&entity.File{ID: f.ID}).Error; err != nil {
+			return deleted, err
+		}
+
+		deleted++
+
+		log.Infof("index: removed ignored file \"%s\"", f.FileName)
+
+		var count int
+
+		if err := ind.db.Model(&entity.File{}).Where("photo_id = ?", f.PhotoID).Count(&count).Error; err != nil {
+			return deleted, err
+		}
+
+		if count == 0 {
+			if err := ind.db.Delete(&entity.Photo{ID: f.PhotoID}).Error; err != nil {
+				return deleted, err
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// MarkMissing flags indexed files of an originals root as missing if they don't exist anymore, either
+// the file itself or all files in a folder given relative to the root.
+func (ind *Index) MarkMissing(root, fileName string) (count int, err error) {
+	var files []entity.File
+
+	if root == "" {
+		root = entity.RootDefault
+	}
+
+	if ind.conf.OriginalsRootPath(root) == "" {
+		return 0, fmt.Errorf("originals root \"%s\" not found", root)
+	}
+
+	q := ind.db.Select("id, file_root, file_name").Where("file_missing = ? AND file_root = ?", false, root)
+
+	if fileName != "" {
+		fileName = filepath.ToSlash(filepath.Clean(fileName))
+		q = q.Where("file_name = ? OR file_name LIKE ?", fileName, fileName+"/%")
+	}
+
+	if err := q.Find(&files).Error; err != nil {
+		return 0, err
+	}
+
+	for _, f := range files {
+		if fs.FileExists(ind.conf.OriginalsFileName(f.FileRoot, f.FileName)) {
+			continue
+		}
+
+		if err := ind.db.Model(&entity.File{}).Where("id = ?", f.ID).Update("file_missing", true).Error; err != nil {
+			return count, err
+		}
+
+		count++
+
+		log.Infof("index: flagged \"%s\" as missing", f.FileName)
+	}
+
+	return count, nil
 }
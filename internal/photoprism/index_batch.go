@@ -0,0 +1,409 @@
+package photoprism
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/colors"
+	"github.com/photoprism/photoprism/pkg/phash"
+)
+
+// IndexBatch contains index jobs of the same folder, whose database writes are committed in one transaction.
+type IndexBatch struct {
+	Jobs []IndexJob
+	Ind  *Index
+}
+
+// IndexBatchWorker indexes batches of jobs until the channel is closed.
+func IndexBatchWorker(batches <-chan IndexBatch) {
+	for b := range batches {
+		b.Ind.indexBatch(b.Jobs)
+
+		// Jobs are only completed if they weren't stopped by Cancel().
+		if mutex.Worker.Canceled() {
+			continue
+		}
+
+		for _, job := range b.Jobs {
+			job.step.Done()
+		}
+	}
+}
+
+// indexBatch indexes the jobs of a batch. Existing files and photos are looked up with IN queries, and
+// classification and other image processing is done before the transaction is started, so that only
+// one batch is written at a time. If writing fails, the transaction is rolled back and the files are
+// indexed one by one, so that an error only affects the file that caused it.
+func (ind *Index) indexBatch(jobs []IndexJob) {
+	if len(jobs) == 0 {
+		return
+	}
+
+	if ind.conf.IndexBatchSize() <= 1 {
+		for _, job := range jobs {
+			nice()
+
+			if mutex.Worker.Canceled() {
+				return
+			}
+
+			for _, r := range ind.indexJob(job) {
+				r.Log(ind)
+			}
+		}
+
+		return
+	}
+
+	opt := jobs[0].IndexOpt
+	c := newIndexCache()
+	files := batchFiles(jobs)
+
+	if err := c.Prefetch(ind, files); err != nil {
+		log.Warnf("index: %s", err)
+		c.Reset()
+	}
+
+	ind.prepare(c, files, opt)
+
+	if mutex.Worker.Canceled() {
+		return
+	}
+
+	results, err := ind.writeBatch(c, jobs, files)
+
+	if err != nil {
+		log.Warnf("index: %s, indexing %d files one by one", err, len(files))
+
+		// Prepared results can be reused, but rows may have been written by the failed transaction.
+		c.Reset()
+		single := ind.withCache(ind.db, c)
+
+		for _, job := range jobs {
+			for _, r := range single.indexJob(job) {
+				r.Log(single)
+			}
+		}
+
+		return
+	}
+
+	for _, r := range results {
+		r.Log(ind)
+	}
+}
+
+// writeBatch indexes the jobs in a transaction and publishes their events after it was committed. Rows are
+// queried again within the transaction, as other batches may have been written since they were prefetched.
+func (ind *Index) writeBatch(c *indexCache, jobs []IndexJob, files MediaFiles) (results []indexJobResult, err error) {
+	mutex.Batch.Lock()
+	defer mutex.Batch.Unlock()
+
+	tx := ind.db.Begin()
+
+	if err := tx.Error; err != nil {
+		return results, err
+	}
+
+	var pending []func()
+
+	batch := ind.withCache(tx, c)
+	batch.pending = &pending
+
+	c.Reset()
+
+	if err := c.Prefetch(batch, files); err != nil {
+		tx.Rollback()
+		return results, err
+	}
+
+	for _, job := range jobs {
+		for _, r := range batch.indexJob(job) {
+			if r.Failed() {
+				tx.Rollback()
+				return results, r.Result.Error
+			}
+
+			results = append(results, r)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return results, err
+	}
+
+	for _, publish := range pending {
+		publish()
+	}
+
+	return results, nil
+}
+
+// withCache returns a copy of the indexer that writes to the database or transaction and uses
+// the cache of a batch.
+func (ind *Index) withCache(db *gorm.DB, c *indexCache) *Index {
+	return &Index{
+		conf:         ind.conf,
+		tensorFlow:   ind.tensorFlow,
+		nsfwDetector: ind.nsfwDetector,
+		db:           db,
+		q:            query.New(db),
+		cache:        c,
+		progress:     ind.progress,
+	}
+}
+
+// publish publishes an event, or queues it until the current batch was committed.
+func (ind *Index) publish(f func()) {
+	if ind.pending != nil {
+		*ind.pending = append(*ind.pending, f)
+	} else {
+		f()
+	}
+}
+
+// fileKey returns the originals root and the name of the media file as stored in the database.
+func (ind *Index) fileKey(m *MediaFile) (root, name string) {
+	root, _ = rootName(ind.conf, m.FileName())
+
+	return root, m.RelativeName(ind.conf.OriginalsRootPath(root))
+}
+
+// prepare computes hashes, metadata, labels, colors and perceptual hashes of new and changed files of a batch,
+// and creates their cameras, lenses and locations. The indexer uses the results instead of computing them while
+// the batch is written, so that the geocoding api isn't queried and no global lock is needed in the transaction.
+func (ind *Index) prepare(c *indexCache, files MediaFiles, o IndexOptions) {
+	for _, m := range files {
+		if mutex.Worker.Canceled() {
+			return
+		}
+
+		root, name := ind.fileKey(m)
+		file, fileExists := c.File(root, name)
+		fileSize, fileModified := m.Stat()
+		fileChanged := !fileExists || file.Changed(fileSize, fileModified)
+
+		if !fileChanged && o.SkipUnchanged() {
+			continue
+		}
+
+		nice()
+
+		if mutex.Worker.Canceled() {
+			return
+		}
+
+		m.Hash()
+		data, err := m.MetaData()
+
+		if fileChanged || o.UpdateCamera {
+			ind.camera(m)
+			ind.lens(m)
+		}
+
+		if err == nil && (data.Lat != 0 || data.Lng != 0) {
+			_, _ = ind.location(data.Lat, data.Lng)
+		}
+
+		if photo, found, _ := c.photo(file.PhotoID); found && photo.HasLatLng() {
+			_, _ = ind.location(photo.PhotoLat, photo.PhotoLng)
+		}
+
+		if !m.IsJpeg() || m.CheckLimits() != nil {
+			continue
+		}
+
+		if !ind.conf.DisableTensorFlow() && (fileChanged || o.UpdateKeywords || o.UpdateLabels || o.UpdateTitle) {
+			ind.labels(m)
+		}
+
+		if !fileExists && ind.conf.DetectNSFW() {
+			ind.nsfw(m)
+		}
+
+		if fileChanged || o.UpdateColors {
+			_, _ = ind.colors(m)
+		}
+
+		if fileChanged || file.FilePhash == "" {
+			_, _ = ind.perceptualHash(m)
+		}
+	}
+}
+
+// findFile returns the indexed file with the root and name, including deleted files.
+func (ind *Index) findFile(root, name string) (file entity.File, found bool) {
+	if ind.cache != nil {
+		if file, found, cached := ind.cache.file(root, name); cached {
+			return file, found
+		}
+	}
+
+	found = ind.db.Unscoped().First(&file, "file_root = ? AND file_name = ?", root, name).Error == nil
+
+	return file, found
+}
+
+// findFileByHash returns the first indexed file with the hash, including deleted files.
+func (ind *Index) findFileByHash(hash string) (file entity.File, found bool) {
+	if ind.cache != nil {
+		if file, found, cached := ind.cache.fileByHash(hash); cached {
+			return file, found
+		}
+	}
+
+	found = ind.db.Unscoped().First(&file, "file_hash = ?", hash).Error == nil
+
+	return file, found
+}
+
+// findPhoto returns the photo with the id, including deleted photos.
+func (ind *Index) findPhoto(id uint) (photo entity.Photo, found bool) {
+	if ind.cache != nil {
+		if photo, found, cached := ind.cache.photo(id); cached {
+			return photo, found
+		}
+	}
+
+	found = ind.db.Unscoped().First(&photo, "id = ?", id).Error == nil
+
+	return photo, found
+}
+
+// labels returns the image classification labels of a media file.
+func (ind *Index) labels(m *MediaFile) classify.Labels {
+	if ind.cache == nil {
+		return ind.classifyImage(m)
+	}
+
+	if labels, ok := ind.cache.labels[m.FileName()]; ok {
+		return labels
+	}
+
+	labels := ind.classifyImage(m)
+	ind.cache.labels[m.FileName()] = labels
+
+	return labels
+}
+
+// nsfw returns true if the media file might be offensive.
+func (ind *Index) nsfw(m *MediaFile) bool {
+	if ind.cache == nil {
+		return ind.NSFW(m)
+	}
+
+	if result, ok := ind.cache.nsfw[m.FileName()]; ok {
+		return result
+	}
+
+	result := ind.NSFW(m)
+	ind.cache.nsfw[m.FileName()] = result
+
+	return result
+}
+
+// colors returns the color perception of a media file.
+func (ind *Index) colors(m *MediaFile) (colors.ColorPerception, error) {
+	if ind.cache == nil {
+		return m.Colors(ind.thumbnailsPath())
+	}
+
+	if result, ok := ind.cache.colors[m.FileName()]; ok {
+		return result.perception, result.err
+	}
+
+	p, err := m.Colors(ind.thumbnailsPath())
+	ind.cache.colors[m.FileName()] = colorsResult{perception: p, err: err}
+
+	return p, err
+}
+
+// perceptualHash returns the perceptual hash of a media file.
+func (ind *Index) perceptualHash(m *MediaFile) (phash.Hash, error) {
+	if ind.cache == nil {
+		return m.PerceptualHash(ind.thumbnailsPath())
+	}
+
+	if result, ok := ind.cache.phash[m.FileName()]; ok {
+		return result.hash, result.err
+	}
+
+	h, err := m.PerceptualHash(ind.thumbnailsPath())
+	ind.cache.phash[m.FileName()] = phashResult{hash: h, err: err}
+
+	return h, err
+}
+
+// camera returns the camera of a media file, which is created if it doesn't exist yet.
+func (ind *Index) camera(m *MediaFile) *entity.Camera {
+	if ind.cache == nil {
+		return entity.NewCamera(m.CameraModel(), m.CameraMake()).FirstOrCreate(ind.db)
+	}
+
+	if camera, ok := ind.cache.cameras[m.FileName()]; ok {
+		return camera
+	}
+
+	camera := entity.NewCamera(m.CameraModel(), m.CameraMake()).FirstOrCreate(ind.conf.Db())
+	ind.cache.cameras[m.FileName()] = camera
+
+	return camera
+}
+
+// lens returns the lens of a media file, which is created if it doesn't exist yet.
+func (ind *Index) lens(m *MediaFile) *entity.Lens {
+	if ind.cache == nil {
+		return entity.NewLens(m.LensModel(), m.LensMake()).FirstOrCreate(ind.db)
+	}
+
+	if lens, ok := ind.cache.lenses[m.FileName()]; ok {
+		return lens
+	}
+
+	lens := entity.NewLens(m.LensModel(), m.LensMake()).FirstOrCreate(ind.conf.Db())
+	ind.cache.lenses[m.FileName()] = lens
+
+	return lens
+}
+
+// location returns the location of latitude and longitude, see entity.FindLocation. Batches find locations
+// before the transaction is started, as the geocoding api may be queried.
+func (ind *Index) location(lat, lng float32) (*entity.Location, error) {
+	if ind.cache == nil {
+		return entity.FindLocation(ind.db, lat, lng, ind.conf.GeoCodingApi())
+	}
+
+	id := entity.NewLocation(lat, lng).ID
+
+	if result, ok := ind.cache.locations[id]; ok {
+		return result.location, result.err
+	}
+
+	location, err := entity.FindLocation(ind.conf.Db(), lat, lng, ind.conf.GeoCodingApi())
+	ind.cache.locations[id] = locationResult{location: location, err: err}
+
+	return location, err
+}
+
+// batchFiles returns the main and related files of the jobs.
+func batchFiles(jobs []IndexJob) (files MediaFiles) {
+	done := make(map[string]bool)
+
+	for _, job := range jobs {
+		if m := job.Related.Main; m != nil && !done[m.FileName()] {
+			files = append(files, m)
+			done[m.FileName()] = true
+		}
+
+		for _, m := range job.Related.Files {
+			if !done[m.FileName()] {
+				files = append(files, m)
+				done[m.FileName()] = true
+			}
+		}
+	}
+
+	return files
+}
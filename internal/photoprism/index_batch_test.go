@@ -0,0 +1,119 @@
+package photoprism
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/nsfw"
+	"github.com/stretchr/testify/assert"
+)
+
+// indexBatchExamples contains the example files copied to folders of a temporary originals path, including
+// related files and a duplicate.
+var indexBatchExamples = map[string]string{
+	"index-batch/a/elephants.jpg":  "elephants.jpg",
+	"index-batch/a/cat_brown.jpg":  "cat_brown.jpg",
+	"index-batch/a/beach_wood.jpg": "beach_wood.jpg",
+	"index-batch/a/iphone_7.jpg":   "iphone_7.jpg",
+	"index-batch/a/iphone_7.json":  "iphone_7.json",
+	"index-batch/a/iphone_7.xmp":   "iphone_7.xmp",
+	"index-batch/b/fern_green.jpg": "fern_green.jpg",
+	"index-batch/b/tree_white.jpg": "tree_white.jpg",
+	"index-batch/b/elephants.jpg":  "elephants.jpg",
+}
+
+// indexBatchOriginals copies the examples to a new temporary originals path.
+func indexBatchOriginals(tb testing.TB) string {
+	dir, err := ioutil.TempDir("", "photoprism-batch")
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	examplesPath := config.TestConfig().ExamplesPath()
+
+	for dest, src := range indexBatchExamples {
+		mediaFile, err := NewMediaFile(filepath.Join(examplesPath, src))
+
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		if err := mediaFile.Copy(filepath.Join(dir, dest)); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// indexBatchRows indexes the originals path with the batch size and returns the indexed files and photos
+// without ids, timestamps and other values that differ between runs.
+func indexBatchRows(tb testing.TB, originalsPath string, batchSize int) (rows []string) {
+	conf := config.TestConfigIndexBatchSize(originalsPath, batchSize)
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+	ind.Start(IndexOptionsAll())
+
+	var files []entity.File
+
+	if err := conf.Db().Unscoped().Preload("Photo").Where("file_root = ? AND file_name LIKE 'index-batch/%'", entity.RootDefault).Find(&files).Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	for _, f := range files {
+		p := f.Photo
+
+		if p == nil {
+			p = &entity.Photo{}
+		}
+
+		rows = append(rows, fmt.Sprintf("%s %s %s %s primary=%t sidecar=%t %dx%d %s %s %s | %s/%s %q %s %.4f,%.4f q=%d r=%d private=%t",
+			f.FileName, f.FileHash, f.FilePhash, f.FileType, f.FilePrimary, f.FileSidecar, f.FileWidth, f.FileHeight,
+			f.FileMainColor, f.FileColors, f.FileLuminance,
+			p.PhotoPath, p.PhotoName, p.PhotoTitle, p.TakenAt.UTC(), p.PhotoLat, p.PhotoLng, p.PhotoQuality, p.PhotoResolution, p.PhotoPrivate))
+
+		conf.Db().Unscoped().Delete(&entity.Photo{ID: f.PhotoID})
+		conf.Db().Unscoped().Delete(&entity.File{ID: f.ID})
+	}
+
+	sort.Strings(rows)
+
+	return rows
+}
+
+func TestIndex_Batch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	originalsPath := indexBatchOriginals(t)
+	defer os.RemoveAll(originalsPath)
+
+	single := indexBatchRows(t, originalsPath, 1)
+	batch := indexBatchRows(t, originalsPath, 100)
+	small := indexBatchRows(t, originalsPath, 2)
+
+	assert.NotEmpty(t, single)
+	assert.Equal(t, single, batch)
+	assert.Equal(t, single, small)
+}
+
+func BenchmarkIndex(b *testing.B) {
+	originalsPath := indexBatchOriginals(b)
+	defer os.RemoveAll(originalsPath)
+
+	for _, size := range []int{1, 100} {
+		b.Run(fmt.Sprintf("batch-%d", size), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				indexBatchRows(b, originalsPath, size)
+			}
+		})
+	}
+}
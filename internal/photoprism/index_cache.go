@@ -0,0 +1,200 @@
+package photoprism
+
+import (
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/colors"
+	"github.com/photoprism/photoprism/pkg/phash"
+)
+
+type colorsResult struct {
+	perception colors.ColorPerception
+	err        error
+}
+
+type phashResult struct {
+	hash phash.Hash
+	err  error
+}
+
+type locationResult struct {
+	location *entity.Location
+	err      error
+}
+
+// indexCache contains the existing files and photos of an index batch, which are queried with IN queries
+// instead of one by one, and the results of image processing done before the batch is written. A nil
+// row means it was queried, but doesn't exist. Rows are removed after they were written, so that the
+// indexer queries them again within the transaction, like without batching. Cameras, lenses and
+// locations are created before the transaction, as geocoding may take a while.
+type indexCache struct {
+	files     map[string]*entity.File
+	hashes    map[string]*entity.File
+	photos    map[uint]*entity.Photo
+	labels    map[string]classify.Labels
+	nsfw      map[string]bool
+	colors    map[string]colorsResult
+	phash     map[string]phashResult
+	cameras   map[string]*entity.Camera
+	lenses    map[string]*entity.Lens
+	locations map[string]locationResult
+}
+
+// newIndexCache returns a new, empty cache.
+func newIndexCache() *indexCache {
+	c := &indexCache{
+		labels:    make(map[string]classify.Labels),
+		nsfw:      make(map[string]bool),
+		colors:    make(map[string]colorsResult),
+		phash:     make(map[string]phashResult),
+		cameras:   make(map[string]*entity.Camera),
+		lenses:    make(map[string]*entity.Lens),
+		locations: make(map[string]locationResult),
+	}
+
+	c.Reset()
+
+	return c
+}
+
+// cacheKey returns the cache key of a file name in an originals root.
+func cacheKey(root, name string) string {
+	return root + ":" + name
+}
+
+// Reset removes all rows, but keeps image processing results.
+func (c *indexCache) Reset() {
+	c.files = make(map[string]*entity.File)
+	c.hashes = make(map[string]*entity.File)
+	c.photos = make(map[uint]*entity.Photo)
+}
+
+// Prefetch queries the indexed files of a batch by name, the files with the same hash as new files
+// for duplicate detection, and the photos of indexed files.
+func (c *indexCache) Prefetch(ind *Index, files MediaFiles) error {
+	names := make(map[string][]string)
+
+	for _, m := range files {
+		root, name := ind.fileKey(m)
+		names[root] = append(names[root], name)
+		c.files[cacheKey(root, name)] = nil
+	}
+
+	var photoIDs []uint
+
+	for root, rootNames := range names {
+		var result []entity.File
+
+		if err := ind.db.Unscoped().Where("file_root = ? AND file_name IN (?)", root, rootNames).Order("id").Find(&result).Error; err != nil {
+			return err
+		}
+
+		for i := range result {
+			f := &result[i]
+
+			if key := cacheKey(f.FileRoot, f.FileName); c.files[key] == nil {
+				c.files[key] = f
+				photoIDs = append(photoIDs, f.PhotoID)
+			}
+		}
+	}
+
+	var hashes []string
+
+	for _, m := range files {
+		if m.IsSidecar() {
+			continue
+		}
+
+		if _, found := c.File(ind.fileKey(m)); found {
+			continue
+		}
+
+		hashes = append(hashes, m.Hash())
+		c.hashes[m.Hash()] = nil
+	}
+
+	if len(hashes) > 0 {
+		var result []entity.File
+
+		if err := ind.db.Unscoped().Where("file_hash IN (?)", hashes).Order("id").Find(&result).Error; err != nil {
+			return err
+		}
+
+		for i := range result {
+			if f := &result[i]; c.hashes[f.FileHash] == nil {
+				c.hashes[f.FileHash] = f
+			}
+		}
+	}
+
+	if len(photoIDs) > 0 {
+		var result []entity.Photo
+
+		if err := ind.db.Unscoped().Where("id IN (?)", photoIDs).Find(&result).Error; err != nil {
+			return err
+		}
+
+		for _, id := range photoIDs {
+			c.photos[id] = nil
+		}
+
+		for i := range result {
+			c.photos[result[i].ID] = &result[i]
+		}
+	}
+
+	return nil
+}
+
+// File returns the indexed file with the root and name, if it was found.
+func (c *indexCache) File(root, name string) (file entity.File, found bool) {
+	file, found, _ = c.file(root, name)
+
+	return file, found
+}
+
+// file returns the indexed file with the root and name, cached is false if it wasn't queried.
+func (c *indexCache) file(root, name string) (file entity.File, found, cached bool) {
+	f, cached := c.files[cacheKey(root, name)]
+
+	if f == nil {
+		return file, false, cached
+	}
+
+	return *f, true, true
+}
+
+// fileByHash returns the first indexed file with the hash, cached is false if it wasn't queried.
+func (c *indexCache) fileByHash(hash string) (file entity.File, found, cached bool) {
+	f, cached := c.hashes[hash]
+
+	if f == nil {
+		return file, false, cached
+	}
+
+	return *f, true, true
+}
+
+// photo returns the photo with the id, cached is false if it wasn't queried.
+func (c *indexCache) photo(id uint) (photo entity.Photo, found, cached bool) {
+	p, cached := c.photos[id]
+
+	if p == nil {
+		return photo, false, cached
+	}
+
+	return *p, true, true
+}
+
+// Forget removes the file and its photo, so that they are queried again after they were written.
+func (c *indexCache) Forget(file entity.File) {
+	delete(c.files, cacheKey(file.FileRoot, file.FileName))
+	delete(c.hashes, file.FileHash)
+	delete(c.photos, file.PhotoID)
+}
+
+// ForgetPhoto removes the photo, so that it's queried again after it was written.
+func (c *indexCache) ForgetPhoto(id uint) {
+	delete(c.photos, id)
+}
@@ -0,0 +1,136 @@
+package photoprism
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheKey(t *testing.T) {
+	assert.Equal(t, "default:2020/IMG_0001.jpg", cacheKey(entity.RootDefault, "2020/IMG_0001.jpg"))
+}
+
+func TestIndexCache_File(t *testing.T) {
+	c := newIndexCache()
+	c.files[cacheKey(entity.RootDefault, "exists.jpg")] = &entity.File{ID: 1, FileName: "exists.jpg", FileHash: "abc", PhotoID: 2}
+	c.files[cacheKey(entity.RootDefault, "missing.jpg")] = nil
+
+	t.Run("found", func(t *testing.T) {
+		file, found, cached := c.file(entity.RootDefault, "exists.jpg")
+
+		assert.True(t, found)
+		assert.True(t, cached)
+		assert.Equal(t, uint(1), file.ID)
+	})
+	t.Run("not found", func(t *testing.T) {
+		file, found, cached := c.file(entity.RootDefault, "missing.jpg")
+
+		assert.False(t, found)
+		assert.True(t, cached)
+		assert.Equal(t, uint(0), file.ID)
+	})
+	t.Run("not queried", func(t *testing.T) {
+		_, found, cached := c.file("archive", "exists.jpg")
+
+		assert.False(t, found)
+		assert.False(t, cached)
+	})
+	t.Run("copy", func(t *testing.T) {
+		file, _ := c.File(entity.RootDefault, "exists.jpg")
+		file.FileName = "changed.jpg"
+
+		file, _ = c.File(entity.RootDefault, "exists.jpg")
+		assert.Equal(t, "exists.jpg", file.FileName)
+	})
+}
+
+func TestIndexCache_FileByHash(t *testing.T) {
+	c := newIndexCache()
+	c.hashes["abc"] = &entity.File{ID: 1, FileHash: "abc"}
+	c.hashes["def"] = nil
+
+	file, found, cached := c.fileByHash("abc")
+	assert.True(t, found)
+	assert.True(t, cached)
+	assert.Equal(t, uint(1), file.ID)
+
+	_, found, cached = c.fileByHash("def")
+	assert.False(t, found)
+	assert.True(t, cached)
+
+	_, found, cached = c.fileByHash("xyz")
+	assert.False(t, found)
+	assert.False(t, cached)
+}
+
+func TestIndexCache_Photo(t *testing.T) {
+	c := newIndexCache()
+	c.photos[2] = &entity.Photo{ID: 2, PhotoName: "IMG_0001"}
+	c.photos[3] = nil
+
+	photo, found, cached := c.photo(2)
+	assert.True(t, found)
+	assert.True(t, cached)
+	assert.Equal(t, "IMG_0001", photo.PhotoName)
+
+	_, found, cached = c.photo(3)
+	assert.False(t, found)
+	assert.True(t, cached)
+
+	_, found, cached = c.photo(4)
+	assert.False(t, found)
+	assert.False(t, cached)
+}
+
+func TestIndexCache_Forget(t *testing.T) {
+	c := newIndexCache()
+	file := entity.File{ID: 1, FileRoot: entity.RootDefault, FileName: "exists.jpg", FileHash: "abc", PhotoID: 2}
+	c.files[cacheKey(file.FileRoot, file.FileName)] = &file
+	c.hashes[file.FileHash] = &file
+	c.photos[file.PhotoID] = &entity.Photo{ID: file.PhotoID}
+	c.photos[3] = &entity.Photo{ID: 3}
+
+	c.Forget(file)
+
+	_, _, cached := c.file(file.FileRoot, file.FileName)
+	assert.False(t, cached)
+	_, _, cached = c.fileByHash(file.FileHash)
+	assert.False(t, cached)
+	_, _, cached = c.photo(file.PhotoID)
+	assert.False(t, cached)
+
+	c.ForgetPhoto(3)
+
+	_, _, cached = c.photo(3)
+	assert.False(t, cached)
+}
+
+func TestIndexCache_Reset(t *testing.T) {
+	c := newIndexCache()
+	c.files[cacheKey(entity.RootDefault, "exists.jpg")] = &entity.File{ID: 1}
+	c.hashes["abc"] = nil
+	c.photos[2] = nil
+	c.nsfw["/originals/exists.jpg"] = true
+
+	c.Reset()
+
+	assert.Empty(t, c.files)
+	assert.Empty(t, c.hashes)
+	assert.Empty(t, c.photos)
+	assert.True(t, c.nsfw["/originals/exists.jpg"])
+}
+
+func TestBatchFiles(t *testing.T) {
+	main := &MediaFile{fileName: "/originals/IMG_0001.jpg"}
+	raw := &MediaFile{fileName: "/originals/IMG_0001.cr2"}
+	other := &MediaFile{fileName: "/originals/IMG_0002.jpg"}
+
+	jobs := []IndexJob{
+		{FileName: raw.FileName(), Related: RelatedFiles{Main: main, Files: MediaFiles{raw, main}}},
+		{FileName: main.FileName(), Related: RelatedFiles{Main: main, Files: MediaFiles{main, raw}}},
+		{FileName: other.FileName(), Related: RelatedFiles{Main: other, Files: MediaFiles{other}}},
+	}
+
+	assert.Equal(t, MediaFiles{main, raw, other}, batchFiles(jobs))
+}
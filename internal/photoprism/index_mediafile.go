@@ -8,11 +8,11 @@ import (
 	"strings"
 	"time"
 
-	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/classify"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
@@ -59,49 +59,57 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 	var description entity.Description
 	var file, primaryFile entity.File
 	var metaData meta.Data
-	var photoQuery, fileQuery *gorm.DB
 	var locKeywords []string
 
 	labels := classify.Labels{}
 	fileBase := m.Base(ind.conf.Settings().Library.GroupRelated)
-	filePath := m.RelativePath(ind.originalsPath())
-	fileName := m.RelativeName(ind.originalsPath())
+	fileRoot, _ := rootName(ind.conf, m.FileName())
+	rootPath := ind.conf.OriginalsRootPath(fileRoot)
+	filePath := m.RelativePath(rootPath)
+	fileName := m.RelativeName(rootPath)
 	fileHash := ""
 	fileSize, fileModified := m.Stat()
 	fileChanged := true
 	fileExists := false
 	photoExists := false
 
+	// Stacked files belong to the photo named like the original, without the suffix of edited versions.
+	if stack := m.Stack(); stack != "" {
+		fileBase = stack
+	}
+
 	event.Publish("index.indexing", event.Data{
 		"fileHash": fileHash,
 		"fileSize": fileSize,
+		"fileRoot": fileRoot,
 		"fileName": fileName,
 		"baseName": filepath.Base(fileName),
+		"subPath":  o.Path,
+		"runId":    ind.progress.RunID(),
+		"resumed":  ind.progress.Resumed(),
 	})
 
-	fileQuery = ind.db.Unscoped().First(&file, "file_name = ?", fileName)
-	fileExists = fileQuery.Error == nil
+	file, fileExists = ind.findFile(fileRoot, fileName)
 
 	if !fileExists && !m.IsSidecar() {
 		fileHash = m.Hash()
-		fileQuery = ind.db.Unscoped().First(&file, "file_hash = ?", fileHash)
-		fileExists = fileQuery.Error == nil
+		file, fileExists = ind.findFileByHash(fileHash)
 
-		if fileExists && fs.FileExists(filepath.Join(ind.conf.OriginalsPath(), file.FileName)) {
+		if fileExists && fs.FileExists(ind.conf.OriginalsFileName(file.Root(), file.FileName)) {
 			result.Status = IndexDuplicate
 			return result
 		}
 	}
 
 	if !fileExists {
-		photoQuery = ind.db.Unscoped().First(&photo, "photo_path = ? AND photo_name = ?", filePath, fileBase)
+		photoExists = ind.db.Unscoped().First(&photo, "photo_root = ? AND photo_path = ? AND photo_name = ?", fileRoot, filePath, fileBase).Error == nil
 
-		if photoQuery.Error != nil && m.HasTimeAndPlace() {
+		if !photoExists && m.HasTimeAndPlace() {
 			metaData, _ = m.MetaData()
-			photoQuery = ind.db.Unscoped().First(&photo, "photo_lat = ? AND photo_lng = ? AND taken_at = ?", metaData.Lat, metaData.Lng, metaData.TakenAt)
+			photoExists = ind.db.Unscoped().First(&photo, "photo_lat = ? AND photo_lng = ? AND taken_at = ?", metaData.Lat, metaData.Lng, metaData.TakenAt).Error == nil
 		}
 	} else {
-		photoQuery = ind.db.Unscoped().First(&photo, "id = ?", file.PhotoID)
+		photo, photoExists = ind.findPhoto(file.PhotoID)
 
 		fileChanged = file.Changed(fileSize, fileModified)
 
@@ -110,7 +118,16 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 		}
 	}
 
-	photoExists = photoQuery.Error == nil
+	// Rows of a batch must be queried again after they were written.
+	if ind.cache != nil {
+		found := file
+
+		defer func() {
+			ind.cache.Forget(found)
+			ind.cache.Forget(file)
+			ind.cache.ForgetPhoto(photo.ID)
+		}()
+	}
 
 	if !fileChanged && photoExists && o.SkipUnchanged() {
 		result.Status = IndexSkipped
@@ -125,9 +142,55 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 		fileHash = m.Hash()
 	}
 
+	photo.PhotoRoot = fileRoot
 	photo.PhotoPath = filePath
 	photo.PhotoName = fileBase
 
+	// Only keep the processing steps of the last run.
+	var timeline entity.Timeline
+
+	timeline.Add("index", "PhotoPrism %s, TensorFlow %s", ind.conf.Version(), ind.conf.TensorFlowVersion())
+
+	if fileChanged {
+		timeline.Add("index", "file is new or was modified")
+	}
+
+	// Only the first error of a run is recorded, later errors are usually caused by it.
+	errorRecorded := false
+
+	setError := func(stage string, err error) {
+		if !errorRecorded {
+			file.SetError(stage, err)
+			errorRecorded = true
+		}
+	}
+
+	// Errors of previous runs are only cleared when their stage runs again. Failed conversions
+	// are cleared by the converter.
+	resetError := func(stage string) {
+		if !errorRecorded && file.HasError(stage) {
+			file.ResetError()
+		}
+	}
+
+	if fileChanged || o.UpdateColors {
+		resetError(entity.StageThumbnail)
+	}
+
+	// Files that exceed the size or resolution limit are indexed without decoding them.
+	tooLarge := m.CheckLimits()
+
+	if tooLarge != nil {
+		log.Warnf("index: %s is %s, skipped decoding", fileName, tooLarge)
+		timeline.Add("index", "%s, skipped decoding", tooLarge)
+		setError(entity.StageThumbnail, tooLarge)
+	}
+
+	// Keep the crop window of unchanged files, so that thumbnails are rendered the same way again.
+	if crop, ok := thumb.ParseCrop(file.FileCrop); ok && !fileChanged {
+		m.SetCrop(crop)
+	}
+
 	if !file.FilePrimary {
 		if photoExists {
 			if q := ind.db.Where("file_type = 'jpg' AND file_primary = 1 AND photo_id = ?", photo.ID).First(&primaryFile); q.Error != nil {
@@ -141,18 +204,44 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 	if file.FilePrimary {
 		primaryFile = file
 
-		if !ind.conf.DisableTensorFlow() && (fileChanged || o.UpdateKeywords || o.UpdateLabels || o.UpdateTitle) {
+		if !ind.conf.DisableTensorFlow() && tooLarge == nil && (fileChanged || o.UpdateKeywords || o.UpdateLabels || o.UpdateTitle) {
 			// Image classification via TensorFlow
-			labels = ind.classifyImage(m)
+			labels = ind.labels(m)
+
+			timeline.Add("classify", "%d labels found", len(labels))
 
 			if !photoExists && ind.conf.DetectNSFW() {
-				photo.PhotoPrivate = ind.NSFW(m)
+				photo.PhotoPrivate = ind.nsfw(m)
 			}
 		}
 
 		if fileChanged || o.UpdateExif {
+			resetError(entity.StageMetadata)
+
 			// Read UpdateExif data
-			if metaData, err := m.MetaData(); err == nil {
+			if metaData, err := m.MetaData(); err == meta.ErrNoExif {
+				timeline.Add("meta", "no Exif data")
+
+				if len(metaData.FaceRegions) > 0 {
+					timeline.Add("meta", "%d face regions found", len(metaData.FaceRegions))
+					labels = append(labels, personLabels(metaData.FaceRegions)...)
+				}
+			} else if err != nil {
+				timeline.Add("meta", "no Exif data (%s)", err)
+				setError(entity.StageMetadata, err)
+			} else {
+				for _, w := range metaData.Warnings {
+					timeline.Add("meta", w)
+				}
+
+				if metaData.TakenAt.IsZero() {
+					timeline.Add("meta", "no TakenAt in Exif data")
+				} else if metaData.TimeZone != "" {
+					timeline.Add("meta", "TakenAt from Exif, time zone %s", metaData.TimeZone)
+				} else {
+					timeline.Add("meta", "TakenAt from Exif, time zone unknown")
+				}
+
 				photo.SetTitle(metaData.Title, entity.SrcExif)
 				photo.SetDescription(metaData.Description, entity.SrcExif)
 				photo.SetTakenAt(metaData.TakenAt, metaData.TakenAtLocal, metaData.TimeZone, entity.SrcExif)
@@ -182,6 +271,11 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 					photo.CameraSerial = metaData.CameraSerial
 				}
 
+				if len(metaData.FaceRegions) > 0 {
+					timeline.Add("meta", "%d face regions found", len(metaData.FaceRegions))
+					labels = append(labels, personLabels(metaData.FaceRegions)...)
+				}
+
 				if len(metaData.UniqueID) > 15 {
 					log.Debugf("index: file uuid \"%s\"", metaData.UniqueID)
 
@@ -192,9 +286,10 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 
 		if photo.CameraSrc == entity.SrcAuto && (fileChanged || o.UpdateCamera) {
 			// Set UpdateCamera, Lens, Focal Length and F Number
-			photo.Camera = entity.NewCamera(m.CameraModel(), m.CameraMake()).FirstOrCreate(ind.db)
-			photo.Lens = entity.NewLens(m.LensModel(), m.LensMake()).FirstOrCreate(ind.db)
+			photo.Camera = ind.camera(m)
+			photo.Lens = ind.lens(m)
 			photo.PhotoFocalLength = m.FocalLength()
+			photo.PhotoFocalLength35 = m.FocalLength35()
 			photo.PhotoFNumber = m.FNumber()
 			photo.PhotoIso = m.Iso()
 			photo.PhotoExposure = m.Exposure()
@@ -207,10 +302,11 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 		if fileChanged || o.UpdateKeywords || o.UpdateLocation || o.UpdateTitle || photo.NoTitle() {
 			if photo.HasLatLng() {
 				var locLabels classify.Labels
-				locKeywords, locLabels = photo.UpdateLocation(ind.db, ind.conf.GeoCodingApi())
+				locKeywords, locLabels = photo.SetLocation(ind.location(photo.PhotoLat, photo.PhotoLng))
 				labels = append(labels, locLabels...)
 			} else {
 				log.Info("index: no latitude and longitude in metadata")
+				timeline.Add("index", "no latitude and longitude in metadata")
 
 				photo.Place = &entity.UnknownPlace
 				photo.PlaceID = entity.UnknownPlace.ID
@@ -219,6 +315,8 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 	} else if m.IsXMP() {
 		// TODO: Proof-of-concept for indexing XMP sidecar files
 		if data, err := meta.XMP(m.FileName()); err == nil {
+			timeline.Add("meta", "title and description from XMP sidecar")
+
 			photo.SetTitle(data.Title, entity.SrcXmp)
 			photo.SetDescription(data.Description, entity.SrcXmp)
 
@@ -233,6 +331,11 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 			if photo.Description.NoCopyright() && data.Copyright != "" {
 				photo.Description.PhotoCopyright = data.Copyright
 			}
+
+			if len(data.FaceRegions) > 0 {
+				timeline.Add("meta", "%d face regions found in XMP sidecar", len(data.FaceRegions))
+				labels = append(labels, personLabels(data.FaceRegions)...)
+			}
 		}
 	}
 
@@ -254,6 +357,7 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 	file.FileSidecar = m.IsSidecar()
 	file.FileVideo = m.IsVideo()
 	file.FileMissing = false
+	file.FileRoot = fileRoot
 	file.FileName = fileName
 	file.FileHash = fileHash
 	file.FileSize = fileSize
@@ -262,16 +366,70 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 	file.FileMime = m.MimeType()
 	file.FileOrientation = m.Orientation()
 
-	if m.IsJpeg() && (fileChanged || o.UpdateColors) {
+	if m.IsJpeg() && tooLarge == nil && (fileChanged || o.UpdateColors) {
 		// Color information
-		if p, err := m.Colors(ind.thumbnailsPath()); err != nil {
+		if p, err := ind.colors(m); err != nil {
 			log.Errorf("index: %s", err.Error())
+			timeline.Add("thumbs", "no color information (%s)", err)
+			setError(entity.StageThumbnail, err)
 		} else {
 			file.FileMainColor = p.MainColor.Name()
 			file.FileColors = p.Colors.Hex()
 			file.FileLuminance = p.Luminance.Hex()
 			file.FileDiff = p.Luminance.Diff()
 			file.FileChroma = p.Chroma.Value()
+
+			if file.FilePrimary {
+				photo.SetColors(p)
+			}
+		}
+	}
+
+	if thumb.SmartCrop && m.IsJpeg() && tooLarge == nil {
+		file.FileCrop = m.Crop(ind.thumbnailsPath()).String()
+	}
+
+	if m.IsJpeg() && tooLarge == nil && (fileChanged || file.FilePhash == "") {
+		if h, err := ind.perceptualHash(m); err != nil {
+			log.Errorf("index: %s", err.Error())
+			setError(entity.StageThumbnail, err)
+		} else {
+			file.FilePhash = h.String()
+			timeline.Add("thumbs", "perceptual hash %s", file.FilePhash)
+		}
+	}
+
+	if m.IsVideo() && (fileChanged || o.UpdateExif) {
+		resetError(entity.StageMetadata)
+
+		if info, err := m.MetaData(); err != nil {
+			timeline.Add("meta", "no video metadata (%s)", err)
+			setError(entity.StageMetadata, err)
+		} else {
+			file.FileWidth = info.Width
+			file.FileHeight = info.Height
+			file.FileCodec = info.Codec
+			file.FileDuration = info.Duration
+			file.FileHDR = info.HDR
+			file.FileAudioCodec = info.AudioCodec
+			file.FileChannels = info.AudioChannels
+
+			if info.Height > 0 {
+				file.FileAspectRatio = float32(info.Width) / float32(info.Height)
+			}
+
+			// Video width and height are stored before rotation, so the rotation must be considered.
+			file.FilePortrait = info.Width < info.Height != (info.Rotation == 90 || info.Rotation == 270)
+
+			timeline.Add("meta", "%s video, %dx%d, rotated %d°, duration %s", info.Codec, info.Width, info.Height, info.Rotation, info.Duration)
+
+			if info.HDR {
+				timeline.Add("meta", "HDR video, transfer characteristics %d", info.ColorTransfer)
+			}
+
+			if info.AudioCodec != "" {
+				timeline.Add("meta", "%s audio, %d channels", info.AudioCodec, info.AudioChannels)
+			}
 		}
 	}
 
@@ -312,15 +470,24 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 			return result
 		}
 
-		event.Publish("count.photos", event.Data{
-			"count": 1,
-		})
+		created := photo
 
-		event.EntitiesCreated("photos", []entity.Photo{photo})
+		ind.publish(func() {
+			event.Publish("count.photos", event.Data{
+				"count": 1,
+			})
+
+			event.EntitiesCreated("photos", []entity.Photo{created})
+		})
 	}
 
 	photo.AddLabels(labels, ind.db)
 
+	// Label counts only change for labels added to a photo, and for all labels of new photos.
+	if len(labels) > 0 || !photoExists {
+		ind.labelsChanged(photo.Labels)
+	}
+
 	file.PhotoID = photo.ID
 	result.PhotoID = photo.ID
 
@@ -379,7 +546,11 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 
 	result.Status = IndexUpdated
 
-	if fileQuery.Error == nil {
+	timeline = append(timeline, m.Timeline()...)
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].Time.Before(timeline[j].Time) })
+	file.SetTimeline(timeline)
+
+	if fileExists {
 		file.UpdatedIn = int64(time.Since(start))
 
 		if err := ind.db.Unscoped().Save(&file).Error; err != nil {
@@ -419,7 +590,7 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 
 // NSFW returns true if media file might be offensive and detection is enabled.
 func (ind *Index) NSFW(jpeg *MediaFile) bool {
-	filename, err := jpeg.Thumbnail(ind.thumbnailsPath(), "fit_720")
+	filename, err := jpeg.JpegThumbnail(ind.thumbnailsPath(), "fit_720")
 
 	if err != nil {
 		log.Error(err)
@@ -439,6 +610,15 @@ func (ind *Index) NSFW(jpeg *MediaFile) bool {
 	return false
 }
 
+// personLabels returns a label for each person named in the face regions.
+func personLabels(regions meta.FaceRegions) (results classify.Labels) {
+	for _, name := range regions.Names() {
+		results = append(results, classify.PersonLabel(name))
+	}
+
+	return results
+}
+
 // classifyImage returns all matching labels for a media file.
 func (ind *Index) classifyImage(jpeg *MediaFile) (results classify.Labels) {
 	start := time.Now()
@@ -454,7 +634,7 @@ func (ind *Index) classifyImage(jpeg *MediaFile) (results classify.Labels) {
 	var labels classify.Labels
 
 	for _, thumb := range thumbs {
-		filename, err := jpeg.Thumbnail(ind.thumbnailsPath(), thumb)
+		filename, err := jpeg.JpegThumbnail(ind.thumbnailsPath(), thumb)
 
 		if err != nil {
 			log.Error(err)
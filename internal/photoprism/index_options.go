@@ -15,13 +15,22 @@ type IndexOptions struct {
 	UpdateKeywords bool
 	UpdateXMP      bool
 	UpdateExif     bool
+	RetryErrors    bool
+	Resume         bool // Skip folders completed by the most recent run if it was interrupted.
+	Path           string
+	Root           string
 }
 
 func (o *IndexOptions) UpdateAny() bool {
 	v := reflect.ValueOf(o).Elem()
 
 	for i := 0; i < v.NumField(); i++ {
-		if v.Field(i).Bool() {
+		// Resuming doesn't change how files are indexed.
+		if v.Type().Field(i).Name == "Resume" {
+			continue
+		}
+
+		if f := v.Field(i); f.Kind() == reflect.Bool && f.Bool() {
 			return true
 		}
 	}
@@ -51,6 +60,14 @@ func IndexOptionsAll() IndexOptions {
 	return result
 }
 
+// IndexOptionsRetry returns new index options to reprocess only files with recorded errors.
+func IndexOptionsRetry() IndexOptions {
+	result := IndexOptionsAll()
+	result.RetryErrors = true
+
+	return result
+}
+
 // IndexOptionsNone returns new index options with all options set to false.
 func IndexOptionsNone() IndexOptions {
 	result := IndexOptions{}
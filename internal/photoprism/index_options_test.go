@@ -22,6 +22,18 @@ func TestIndexOptions_UpdateAny(t *testing.T) {
 		result := IndexOptionsNone()
 		assert.False(t, result.UpdateAny())
 	})
+
+	t.Run("path", func(t *testing.T) {
+		result := IndexOptionsNone()
+		result.Path = "2020"
+		assert.False(t, result.UpdateAny())
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		result := IndexOptionsNone()
+		result.Resume = true
+		assert.False(t, result.UpdateAny())
+	})
 }
 
 func TestIndexOptions_SkipUnchanged(t *testing.T) {
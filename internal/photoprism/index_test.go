@@ -1,11 +1,23 @@
 package photoprism
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/classify"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/nsfw"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestIndex_Start(t *testing.T) {
@@ -33,3 +45,500 @@ func TestIndex_Start(t *testing.T) {
 
 	ind.Start(indexOpt)
 }
+
+func TestIndex_Start_Busy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	conf := config.TestConfig()
+
+	conf.InitializeTestData(t)
+
+	poll, maxPause := mutex.ActivityPoll, mutex.ActivityMaxPause
+	mutex.ActivityPoll, mutex.ActivityMaxPause = 5*time.Millisecond, 100*time.Millisecond
+
+	defer func() {
+		mutex.ActivityPoll, mutex.ActivityMaxPause = poll, maxPause
+		mutex.Interactive.Configure(2*time.Second, nil)
+	}()
+
+	// A request stays active while the index is running.
+	mutex.Interactive.Configure(time.Hour, nil)
+	mutex.Interactive.Start()
+	defer mutex.Interactive.Done()
+
+	tf := classify.New(conf.ResourcesPath(), conf.DisableTensorFlow())
+	nd := nsfw.New(conf.NSFWModelPath())
+
+	ind := NewIndex(conf, tf, nd)
+	imp := NewImport(conf, ind, NewConvert(conf))
+
+	done := make(chan bool)
+
+	go func() {
+		imp.Start(ImportOptionsMove(conf.ImportPath()))
+		ind.Start(IndexOptionsAll())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Minute):
+		mutex.Worker.Cancel()
+		t.Fatal("workers did not resume while the server was busy")
+	}
+}
+
+func TestIndex_Start_ReadOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	dir, err := ioutil.TempDir("", "index-readonly")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	originals := filepath.Join(dir, "originals")
+	sidecar := filepath.Join(dir, "sidecar")
+
+	if err := os.MkdirAll(filepath.Join(originals, "readonly"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	examples := config.TestConfig().ExamplesPath()
+	image, err := NewMediaFile(filepath.Join(examples, "iphone_7.heic"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := image.Copy(filepath.Join(originals, "readonly", "iphone_7.heic")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fake heif-convert that copies an example JPEG to the output file given as last argument.
+	bin := filepath.Join(dir, "heif-convert")
+	script := fmt.Sprintf("#!/bin/sh\nfor last; do true; done\ncp %s \"$last\"\n", filepath.Join(examples, "elephants.jpg"))
+
+	if err := ioutil.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config.TestConfigReadOnly(originals, sidecar, bin)
+
+	if err := NewConvert(conf).Start(originals); err != nil {
+		t.Fatal(err)
+	}
+
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+	ind.Start(IndexOptionsAll())
+
+	q := query.New(conf.Db())
+
+	jpeg, err := q.FileByRootName(entity.RootSidecar, "readonly/iphone_7.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	heif, err := q.FileByRootName(entity.RootDefault, "readonly/iphone_7.heic")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		conf.Db().Unscoped().Delete(&entity.Photo{ID: heif.PhotoID})
+		conf.Db().Unscoped().Delete(&entity.File{}, "id IN (?)", []uint{jpeg.ID, heif.ID})
+	}()
+
+	assert.True(t, jpeg.FilePrimary)
+	assert.False(t, heif.FilePrimary)
+	assert.Equal(t, heif.PhotoID, jpeg.PhotoID)
+	assert.Equal(t, filepath.Join(sidecar, "readonly", "iphone_7.jpg"), conf.OriginalsFileName(jpeg.FileRoot, jpeg.FileName))
+}
+
+func TestIndex_Start_Path(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	conf := config.TestConfig()
+
+	conf.InitializeTestData(t)
+
+	tf := classify.New(conf.ResourcesPath(), conf.DisableTensorFlow())
+	nd := nsfw.New(conf.NSFWModelPath())
+
+	ind := NewIndex(conf, tf, nd)
+	imp := NewImport(conf, ind, NewConvert(conf))
+
+	imp.Start(ImportOptionsMove(conf.ImportPath()))
+
+	t.Run("traversal", func(t *testing.T) {
+		for _, p := range []string{"../import", "2020/../../import", "/etc"} {
+			opt := IndexOptionsAll()
+			opt.Path = p
+
+			assert.Empty(t, ind.Start(opt))
+		}
+	})
+	t.Run("sub-folder", func(t *testing.T) {
+		dirs, err := ioutil.ReadDir(conf.OriginalsPath())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var subPath string
+
+		for _, d := range dirs {
+			if d.IsDir() {
+				subPath = d.Name()
+				break
+			}
+		}
+
+		if subPath == "" {
+			t.Skip("no sub-folder in originals")
+		}
+
+		opt := IndexOptionsAll()
+		opt.Path = subPath
+
+		done := ind.Start(opt)
+
+		assert.NotEmpty(t, done)
+
+		for fileName := range done {
+			assert.True(t, strings.HasPrefix(fileName, filepath.Join(conf.OriginalsPath(), subPath)+string(os.PathSeparator)), fileName)
+		}
+	})
+}
+
+func TestIndex_MediaFile_TooLarge(t *testing.T) {
+	conf := config.TestConfig()
+
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+
+	dir := filepath.Join(conf.OriginalsPath(), "too-large")
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "panorama.jpg")
+
+	writeJpegHeader(t, fileName, 60000, 10000)
+
+	resolutionLimit := thumb.ResolutionLimit
+	thumb.ResolutionLimit = 100
+
+	defer func() { thumb.ResolutionLimit = resolutionLimit }()
+
+	mediaFile, err := NewMediaFile(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := ind.MediaFile(mediaFile, IndexOptionsAll(), "")
+
+	assert.Equal(t, IndexAdded, result.Status)
+
+	var file entity.File
+
+	if err := conf.Db().Where("file_uuid = ?", result.FileUUID).First(&file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata is indexed, the image is not decoded.
+	assert.Equal(t, "too large, 600 megapixels exceed the limit of 100", file.FileError)
+	assert.Equal(t, 60000, file.FileWidth)
+	assert.Empty(t, file.FilePhash)
+	assert.Empty(t, file.FileMainColor)
+
+	// The error is cleared once the file is within the limit.
+	copyTestFile(t, "testdata/takeout/IMG_1234.jpg", fileName)
+
+	if mediaFile, err = NewMediaFile(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	ind.MediaFile(mediaFile, IndexOptionsAll(), "")
+
+	if err := conf.Db().Where("id = ?", file.ID).First(&file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, file.FileError)
+}
+
+func TestIndex_MediaFile_Errors(t *testing.T) {
+	conf := config.TestConfig()
+
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+
+	dir := filepath.Join(conf.OriginalsPath(), "index-errors")
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "truncated.jpg")
+
+	copyTestFile(t, "testdata/truncated.jpg", fileName)
+
+	done := ind.Start(IndexOptions{Path: "index-errors"})
+
+	assert.True(t, done[fileName])
+
+	var file entity.File
+
+	if err := conf.Db().Where("file_root = ? AND file_name = ?", entity.RootDefault, "index-errors/truncated.jpg").First(&file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer conf.Db().Unscoped().Delete(&entity.Photo{ID: file.PhotoID})
+	defer conf.Db().Unscoped().Delete(&file)
+
+	// The first error is recorded, the image data can't be decoded either.
+	assert.True(t, file.HasError(entity.StageMetadata))
+	assert.Contains(t, file.FileError, "partial segment data")
+	assert.NotNil(t, file.FileErrorAt)
+	assert.Empty(t, file.FileMainColor)
+
+	results, err := query.New(conf.Db()).FileErrors(form.FileErrorSearch{Stage: entity.StageMetadata, Folder: "index-errors"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, file.FileUUID, results[0].FileUUID)
+	}
+
+	// Errors are kept if their stage doesn't run again.
+	done = ind.Start(IndexOptions{Path: "index-errors", RetryErrors: true})
+
+	assert.True(t, done[fileName])
+
+	if err := conf.Db().Where("id = ?", file.ID).First(&file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, file.HasError(entity.StageMetadata))
+
+	// Retrying reprocesses the replaced file, which clears the error.
+	copyTestFile(t, "testdata/takeout/IMG_1234.jpg", fileName)
+
+	done = ind.Start(IndexOptions{Path: "index-errors", RetryErrors: true, UpdateColors: true, UpdateExif: true})
+
+	assert.True(t, done[fileName])
+
+	if err := conf.Db().Where("id = ?", file.ID).First(&file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, file.HasError(""))
+	assert.Nil(t, file.FileErrorAt)
+	assert.NotEmpty(t, file.FileMainColor)
+
+	// Files without errors are skipped when retrying.
+	retryOpt := IndexOptionsRetry()
+	retryOpt.Path = "index-errors"
+
+	assert.Empty(t, ind.Start(retryOpt))
+}
+
+func copyTestFile(t *testing.T, src, dst string) {
+	data, err := ioutil.ReadFile(src)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(dst, data, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndex_FolderAlbum(t *testing.T) {
+	conf := config.TestConfig()
+
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+
+	dir := filepath.Join(conf.OriginalsPath(), "folder-album")
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	album := entity.NewAlbum("Folder Album")
+
+	if err := album.SetType(entity.AlbumFolder, "folder-album/", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Db().Create(album).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer entity.DeleteAlbums(conf.Db(), []string{album.AlbumUUID})
+
+	q := query.New(conf.Db())
+	opt := IndexOptionsAll()
+	opt.Path = "folder-album"
+
+	for i, name := range []string{"elephants.jpg", "fern_green.jpg"} {
+		mediaFile, err := NewMediaFile(filepath.Join(conf.ExamplesPath(), name))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := mediaFile.Copy(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+
+		ind.Start(opt)
+
+		count, err := q.PhotosTotal(form.PhotoSearch{Album: album.AlbumUUID})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, i+1, count)
+	}
+}
+
+func TestIndex_Roots(t *testing.T) {
+	recent, err := ioutil.TempDir("", "photoprism-recent")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(recent)
+
+	archive, err := ioutil.TempDir("", "photoprism-archive")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(archive)
+
+	conf := config.TestConfigRoots(recent, "archive="+archive)
+
+	// Both roots contain a different file with the same relative name.
+	examples := map[string]string{recent: "elephants.jpg", archive: "fern_green.jpg"}
+
+	for root, name := range examples {
+		mediaFile, err := NewMediaFile(filepath.Join(conf.ExamplesPath(), name))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := mediaFile.Copy(filepath.Join(root, "multiple-roots", "IMG_0001.jpg")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+	files := ind.Start(IndexOptionsAll())
+
+	assert.Len(t, files, 2)
+
+	q := query.New(conf.Db())
+
+	for _, root := range conf.OriginalsRoots() {
+		file, err := q.FileByRootName(root.Name, "multiple-roots/IMG_0001.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer conf.Db().Unscoped().Delete(&entity.Photo{ID: file.PhotoID})
+		defer conf.Db().Unscoped().Delete(&file)
+
+		fileName := conf.OriginalsFileName(file.FileRoot, file.FileName)
+
+		assert.Equal(t, filepath.Join(root.Path, "multiple-roots", "IMG_0001.jpg"), fileName)
+		assert.Equal(t, root.Name, file.Photo.PhotoRoot)
+		assert.Equal(t, "multiple-roots", file.Photo.PhotoPath)
+
+		count, err := q.PhotosTotal(form.PhotoSearch{Root: root.Name})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, count)
+
+		thumbnail, err := thumb.FromFile(fileName, file.FileHash, conf.ThumbnailsPath(), 224, 224, thumb.ResampleFit)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.FileExists(t, thumbnail)
+	}
+
+	// Indexing a single root and flagging missing files don't affect the other root.
+	if err := os.Remove(filepath.Join(archive, "multiple-roots", "IMG_0001.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := ind.MarkMissing(entity.RootDefault, "multiple-roots")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = ind.MarkMissing("archive", "multiple-roots")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestIndex_LabelsChanged(t *testing.T) {
+	conf := config.TestConfig()
+
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+
+	ind.labelsChanged(nil)
+	assert.Empty(t, ind.labelIDs)
+
+	label := entity.NewLabel("Label Count Test", 0).FirstOrCreate(conf.Db())
+	defer conf.Db().Unscoped().Delete(label)
+
+	if err := conf.Db().Model(label).UpdateColumn("label_count", 5).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ind.labelsChanged([]entity.PhotoLabel{{LabelID: label.ID}, {LabelID: label.ID}})
+	assert.Len(t, ind.labelIDs, 1)
+
+	// Only the counts of changed labels are updated, the label has no photos.
+	ind.updateLabelCounts()
+	assert.Empty(t, ind.labelIDs)
+
+	if err := conf.Db().First(label, label.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 0, label.LabelCount)
+}
@@ -5,33 +5,72 @@ type IndexJob struct {
 	Related  RelatedFiles
 	IndexOpt IndexOptions
 	Ind      *Index
+	step     *runStep
+}
+
+// indexJobResult contains the result of indexing one file of a job, so that it can be logged after a batch was committed.
+type indexJobResult struct {
+	Result IndexResult
+	File   *MediaFile
+	Main   bool
+}
+
+// Failed returns true if the file could not be written to the database.
+func (r indexJobResult) Failed() bool {
+	return r.Result.Status == IndexFailed
+}
+
+// Log logs the result like "index: added main jpg file".
+func (r indexJobResult) Log(ind *Index) {
+	kind := "related"
+
+	if r.Main {
+		kind = "main"
+	}
+
+	log.Infof("index: %s %s %s file \"%s\"", r.Result, kind, r.File.FileType(), ind.relativeName(r.File.FileName()))
 }
 
 func IndexWorker(jobs <-chan IndexJob) {
 	for job := range jobs {
-		done := make(map[string]bool)
-		related := job.Related
-		opt := job.IndexOpt
-		ind := job.Ind
-
-		if related.Main != nil {
-			res := ind.MediaFile(related.Main, opt, "")
-			done[related.Main.FileName()] = true
-
-			log.Infof("index: %s main %s file \"%s\"", res, related.Main.FileType(), related.Main.RelativeName(ind.originalsPath()))
-		} else {
-			log.Warnf("index: no main file for %s (conversion to jpeg failed?)", job.FileName)
+		for _, r := range job.Ind.indexJob(job) {
+			r.Log(job.Ind)
 		}
+	}
+}
 
-		for _, f := range related.Files {
-			if done[f.FileName()] {
-				continue
-			}
+// indexJob indexes the main file and the related files of a job. It stops after the first file that could
+// not be written to the database if the indexer is part of a batch, as the batch is rolled back anyway.
+func (ind *Index) indexJob(job IndexJob) (results []indexJobResult) {
+	done := make(map[string]bool)
+	related := job.Related
+	opt := job.IndexOpt
 
-			res := ind.MediaFile(f, opt, "")
-			done[f.FileName()] = true
+	if related.Main != nil {
+		r := indexJobResult{Result: ind.MediaFile(related.Main, opt, ""), File: related.Main, Main: true}
+		results = append(results, r)
+		done[related.Main.FileName()] = true
 
-			log.Infof("index: %s related %s file \"%s\"", res, f.FileType(), f.RelativeName(ind.originalsPath()))
+		if r.Failed() && ind.pending != nil {
+			return results
 		}
+	} else {
+		log.Warnf("index: no main file for %s (conversion to jpeg failed?)", job.FileName)
 	}
+
+	for _, f := range related.Files {
+		if done[f.FileName()] {
+			continue
+		}
+
+		r := indexJobResult{Result: ind.MediaFile(f, opt, ""), File: f}
+		results = append(results, r)
+		done[f.FileName()] = true
+
+		if r.Failed() && ind.pending != nil {
+			return results
+		}
+	}
+
+	return results
 }
@@ -33,7 +33,12 @@ type MediaFile struct {
 	height      int
 	once        sync.Once
 	metaData    meta.Data
+	metaDataErr error
 	location    *entity.Location
+	timeline    entity.Timeline
+	crop        thumb.Crop
+	stack       string
+	takeout     string
 }
 
 // NewMediaFile returns a new media file.
@@ -90,8 +95,10 @@ func (m *MediaFile) DateCreated() time.Time {
 
 	if t.HasBirthTime() {
 		m.dateCreated = t.BirthTime().UTC()
+		m.timeline.Add("meta", "TakenAt from file creation time since Exif date is missing or invalid")
 	} else {
 		m.dateCreated = t.ModTime().UTC()
+		m.timeline.Add("meta", "TakenAt from file modification time since Exif date is missing or invalid")
 	}
 
 	log.Infof("mediafile: taken at %s", m.dateCreated.String())
@@ -99,6 +106,11 @@ func (m *MediaFile) DateCreated() time.Time {
 	return m.dateCreated
 }
 
+// Timeline returns the processing steps recorded for this media file.
+func (m *MediaFile) Timeline() entity.Timeline {
+	return m.timeline
+}
+
 func (m *MediaFile) HasTimeAndPlace() bool {
 	exifData, err := m.MetaData()
 
@@ -176,6 +188,19 @@ func (m *MediaFile) FocalLength() int {
 	return result
 }
 
+// FocalLength35 returns the 35mm equivalent focal length, or 0 if it is unknown.
+func (m *MediaFile) FocalLength35() int {
+	info, err := m.MetaData()
+
+	var result int
+
+	if err == nil {
+		result = info.FocalLength35
+	}
+
+	return result
+}
+
 // FNumber returns the F number with which the media file was created.
 func (m *MediaFile) FNumber() float32 {
 	info, err := m.MetaData()
@@ -270,6 +295,12 @@ func (m *MediaFile) EditedName() string {
 
 // RelatedFiles returns files which are related to this file.
 func (m *MediaFile) RelatedFiles(stripSequence bool) (result RelatedFiles, err error) {
+	return m.RelatedSidecarFiles(stripSequence, "")
+}
+
+// RelatedSidecarFiles returns files which are related to this file, including files named like sidecarBase,
+// e.g. JPEGs converted to the sidecar path in read-only mode.
+func (m *MediaFile) RelatedSidecarFiles(stripSequence bool, sidecarBase string) (result RelatedFiles, err error) {
 	baseFilename := m.AbsBase(stripSequence)
 	// escape any meta characters in the file name
 	baseFilename = regexp.QuoteMeta(baseFilename)
@@ -279,14 +310,32 @@ func (m *MediaFile) RelatedFiles(stripSequence bool) (result RelatedFiles, err e
 		return result, err
 	}
 
-	if filename := m.EditedName(); filename != "" {
-		matches = append(matches, filename)
+	if sidecarBase != "" {
+		sidecarMatches, err := filepath.Glob(regexp.QuoteMeta(sidecarBase) + "*")
+
+		if err != nil {
+			return result, err
+		}
+
+		matches = append(matches, sidecarMatches...)
+	}
+
+	editedName := m.EditedName()
+
+	if editedName != "" {
+		matches = append(matches, editedName)
 	}
 
 	for _, filename := range matches {
+		// Files like IMG_12345.jpg also match the pattern for IMG_1234, but are not related.
+		if filename != editedName && fs.Base(filename, stripSequence) != m.Base(stripSequence) {
+			continue
+		}
+
 		resultFile, err := NewMediaFile(filename)
 
-		if err != nil {
+		// Skip video transcodes created for playback in browsers.
+		if err != nil || resultFile.HasFileType(fs.TypeAvc) {
 			continue
 		}
 
@@ -420,9 +469,12 @@ func (m MediaFile) HasSameName(f *MediaFile) bool {
 	return m.FileName() == f.FileName()
 }
 
+// rename renames a file, it may be replaced in tests to simulate moving files across devices.
+var rename = os.Rename
+
 // Move file to a new destination with the filename provided in parameter.
 func (m *MediaFile) Move(newFilename string) error {
-	if err := os.Rename(m.fileName, newFilename); err != nil {
+	if err := rename(m.fileName, newFilename); err != nil {
 		log.Debugf("could not rename file, falling back to copy and delete: %s", err.Error())
 	} else {
 		m.fileName = newFilename
@@ -558,6 +610,18 @@ func (m MediaFile) IsVideo() bool {
 	return m.MediaType() == fs.MediaVideo
 }
 
+// IsPlayable returns true if this media file is a H.264 video in a container
+// format that can be played in browsers without transcoding.
+func (m *MediaFile) IsPlayable() bool {
+	if !m.HasFileType(fs.TypeMP4) && !m.HasFileType(fs.TypeMov) {
+		return false
+	}
+
+	info, err := m.MetaData()
+
+	return err == nil && info.Codec == "avc"
+}
+
 // IsPhoto checks if this media file is a photo / image.
 func (m MediaFile) IsPhoto() bool {
 	return m.IsJpeg() || m.IsRaw() || m.IsHEIF() || m.IsImageOther()
@@ -587,36 +651,36 @@ func (m *MediaFile) decodeDimensions() error {
 		return fmt.Errorf("not a photo: %s", m.FileName())
 	}
 
-	var width, height int
-
-	exif, err := m.MetaData()
+	// Exif dimensions are already swapped for orientations that transpose the image.
+	if exif, err := m.MetaData(); err == nil {
+		m.width = exif.Width
+		m.height = exif.Height
+	}
 
-	if err == nil {
-		width = exif.Width
-		height = exif.Height
+	if !m.IsJpeg() {
+		return nil
 	}
 
-	if m.IsJpeg() {
-		file, err := os.Open(m.FileName())
+	file, err := os.Open(m.FileName())
 
-		defer file.Close()
+	if err != nil {
+		return err
+	}
 
-		size, _, err := image.DecodeConfig(file)
+	defer file.Close()
 
-		if err != nil {
-			return err
-		}
+	size, _, err := image.DecodeConfig(file)
 
-		width = size.Width
-		height = size.Height
+	if err != nil {
+		return err
 	}
 
 	if m.Orientation() > 4 {
-		m.width = height
-		m.height = width
+		m.width = size.Height
+		m.height = size.Width
 	} else {
-		m.width = width
-		m.height = height
+		m.width = size.Width
+		m.height = size.Height
 	}
 
 	return nil
@@ -652,6 +716,16 @@ func (m *MediaFile) Height() int {
 	return m.height
 }
 
+// CheckLimits returns an error wrapping thumb.ErrTooLarge if the file exceeds the size or
+// resolution limit and must not be decoded. Videos and sidecar files are not checked.
+func (m *MediaFile) CheckLimits() error {
+	if m.IsVideo() || m.IsSidecar() {
+		return nil
+	}
+
+	return thumb.CheckLimits(m.FileName())
+}
+
 // AspectRatio returns the aspect ratio of a MediaFile.
 func (m *MediaFile) AspectRatio() float32 {
 	width := float64(m.Width())
@@ -677,6 +751,16 @@ func (m *MediaFile) Orientation() int {
 
 // Thumbnail returns a thumbnail filename.
 func (m *MediaFile) Thumbnail(path string, typeName string) (filename string, err error) {
+	return m.thumbnail(path, typeName, false)
+}
+
+// JpegThumbnail returns the filename of a JPEG thumbnail, independent of the configured thumbnail format.
+// This is required by TensorFlow, which only decodes JPEG images.
+func (m *MediaFile) JpegThumbnail(path string, typeName string) (filename string, err error) {
+	return m.thumbnail(path, typeName, true)
+}
+
+func (m *MediaFile) thumbnail(path string, typeName string, jpeg bool) (filename string, err error) {
 	thumbType, ok := thumb.Types[typeName]
 
 	if !ok {
@@ -684,13 +768,32 @@ func (m *MediaFile) Thumbnail(path string, typeName string) (filename string, er
 		return "", fmt.Errorf("mediafile: invalid type %s", typeName)
 	}
 
-	thumbnail, err := thumb.FromFile(m.FileName(), m.Hash(), path, thumbType.Width, thumbType.Height, thumbType.Options...)
+	opts := append([]thumb.ResampleOption{}, thumbType.Options...)
+
+	if jpeg {
+		opts = append(opts, thumb.ResampleJpeg)
+	}
+
+	_, exists := thumb.Existing(m.Hash(), path, thumbType.Width, thumbType.Height, opts...)
+
+	var crop thumb.Crop
+
+	if thumbType.Centered() && !exists {
+		crop = m.Crop(path)
+	}
+
+	thumbnail, err := thumb.FromFileCrop(m.FileName(), m.Hash(), path, thumbType.Width, thumbType.Height, crop, opts...)
 
 	if err != nil {
 		log.Errorf("mediafile: could not create thumbnail (%s)", err)
+		m.timeline.Add("thumbs", "could not create %s (%s)", typeName, err)
 		return "", fmt.Errorf("mediafile: could not create thumbnail (%s)", err)
 	}
 
+	if !exists {
+		m.timeline.Add("thumbs", "created %s", typeName)
+	}
+
 	return thumbnail, nil
 }
 
@@ -706,10 +809,31 @@ func (m *MediaFile) Resample(path string, typeName string) (img image.Image, err
 }
 
 func (m *MediaFile) ResampleDefault(thumbPath string, force bool) (err error) {
+	hash := m.Hash()
+
+	// Skip files whose thumbnails are complete for the current settings, and recreate them if settings changed.
+	if marker := thumb.Marker(hash, thumbPath); marker != "" && !force {
+		if marker == thumb.SettingsHash() {
+			log.Debugf("mediafile: thumbnails of %s are complete", m.Base(false))
+			return nil
+		}
+
+		force = true
+	}
+
+	if err := m.CheckLimits(); err != nil {
+		return err
+	}
+
 	count := 0
+	embedded := 0
 	start := time.Now()
 
 	defer func() {
+		if count > 0 {
+			m.timeline.Add("thumbs", "created %d default thumbnails, %d from embedded preview", count, embedded)
+		}
+
 		switch count {
 		case 0:
 			log.Info(capture.Time(start, fmt.Sprintf("mediafile: no new thumbnails created for %s", m.Base(false))))
@@ -720,12 +844,19 @@ func (m *MediaFile) ResampleDefault(thumbPath string, force bool) (err error) {
 		}
 	}()
 
-	hash := m.Hash()
-
 	var originalImg *image.Image
+	var embeddedImg *image.Image
 	var sourceImg *image.Image
 	var sourceImgType string
 
+	if thumb.UseEmbedded {
+		if img, err := openEmbedded(m.FileName()); err == nil {
+			embeddedImg = &img
+		} else {
+			log.Debugf("mediafile: %s (%s)", err, m.Base(false))
+		}
+	}
+
 	for _, name := range thumb.DefaultTypes {
 		thumbType := thumb.Types[name]
 
@@ -739,30 +870,45 @@ func (m *MediaFile) ResampleDefault(thumbPath string, force bool) (err error) {
 
 			return err
 		} else {
-			if !force && fs.FileExists(fileName) {
+			if _, exists := thumb.Existing(hash, thumbPath, thumbType.Width, thumbType.Height, thumbType.Options...); !force && exists {
 				continue
 			}
 
-			if originalImg == nil {
-				img, err := imaging.Open(m.FileName(), imaging.AutoOrientation(true))
+			if thumbType.Source != "" && thumbType.Source == sourceImgType && sourceImg != nil {
+				_, err = thumb.Create(sourceImg, fileName, thumbType.Width, thumbType.Height, thumbType.Options...)
+			} else {
+				img := embeddedImg
+
+				if embeddedImg != nil && thumb.SourceFits(*embeddedImg, thumbType.Width, thumbType.Height, thumbType.Options...) {
+					embedded++
+				} else {
+					if originalImg == nil {
+						img, err := openImage(m.FileName())
 
-				if err != nil {
-					log.Errorf("mediafile: can't open \"%s\" (%s)", m.FileName(), err.Error())
-					return err
+						if err != nil {
+							log.Errorf("mediafile: can't open \"%s\" (%s)", m.FileName(), err.Error())
+							return err
+						}
+
+						originalImg = &img
+					}
+
+					img = originalImg
 				}
 
-				originalImg = &img
-			}
+				var crop thumb.Crop
 
-			if thumbType.Source != "" {
-				if thumbType.Source == sourceImgType && sourceImg != nil {
-					_, err = thumb.Create(sourceImg, fileName, thumbType.Width, thumbType.Height, thumbType.Options...)
+				// Sources are already cropped, so the crop window only applies when rendering from the original.
+				if thumbType.Centered() {
+					crop = m.Crop(thumbPath)
+				}
+
+				if thumbType.Source != "" {
+					_, err = thumb.CreateCrop(img, fileName, thumbType.Width, thumbType.Height, crop, thumbType.Options...)
 				} else {
-					_, err = thumb.Create(originalImg, fileName, thumbType.Width, thumbType.Height, thumbType.Options...)
+					sourceImg, err = thumb.CreateCrop(img, fileName, thumbType.Width, thumbType.Height, crop, thumbType.Options...)
+					sourceImgType = name
 				}
-			} else {
-				sourceImg, err = thumb.Create(originalImg, fileName, thumbType.Width, thumbType.Height, thumbType.Options...)
-				sourceImgType = name
 			}
 
 			if err != nil {
@@ -774,5 +920,9 @@ func (m *MediaFile) ResampleDefault(thumbPath string, force bool) (err error) {
 		}
 	}
 
+	if err := thumb.SetMarker(hash, thumbPath); err != nil {
+		log.Warnf("mediafile: %s", err)
+	}
+
 	return nil
 }
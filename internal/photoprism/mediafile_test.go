@@ -1,9 +1,15 @@
 package photoprism
 
 import (
+	"errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/internal/meta"
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
 
@@ -145,6 +151,30 @@ func TestMediaFile_FocalLength(t *testing.T) {
 	})
 }
 
+func TestMediaFile_FocalLength35(t *testing.T) {
+	t.Run("/elephants.jpg", func(t *testing.T) {
+		conf := config.TestConfig()
+
+		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/elephants.jpg")
+		assert.Nil(t, err)
+		assert.Equal(t, 111, mediaFile.FocalLength35())
+	})
+	t.Run("/fern_green.jpg", func(t *testing.T) {
+		conf := config.TestConfig()
+
+		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/fern_green.jpg")
+		assert.Nil(t, err)
+		assert.Equal(t, 160, mediaFile.FocalLength35())
+	})
+	t.Run("/beach_sand.jpg", func(t *testing.T) {
+		conf := config.TestConfig()
+
+		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/beach_sand.jpg")
+		assert.Nil(t, err)
+		assert.Equal(t, 0, mediaFile.FocalLength35())
+	})
+}
+
 func TestMediaFile_FNumber(t *testing.T) {
 	t.Run("/cat_brown.jpg", func(t *testing.T) {
 		conf := config.TestConfig()
@@ -303,6 +333,65 @@ func TestMediaFile_RelatedFiles(t *testing.T) {
 	})
 }
 
+func TestMediaFile_RelatedFiles_LivePhoto(t *testing.T) {
+	dir, err := ioutil.TempDir("", "livephoto")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	copyFile := func(src, dest string) {
+		f, err := NewMediaFile(src)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Copy(dest); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	heic := filepath.Join(dir, "IMG_1234.HEIC")
+
+	copyFile("../meta/testdata/iphone_7.heic", heic)
+	copyFile("../meta/testdata/photoshop.jpg", filepath.Join(dir, "IMG_1234.jpg"))
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "IMG_1234.MOV"), []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mediaFile, err := NewMediaFile(filepath.Join(dir, "IMG_1234.MOV"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	related, err := mediaFile.RelatedFiles(true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, related.Files, 3)
+	assert.Equal(t, heic, related.Main.FileName())
+	assert.True(t, related.Main.IsHEIF())
+	assert.Equal(t, fs.MimeTypeHEIC, related.Main.MimeType())
+
+	videos := 0
+
+	for _, f := range related.Files {
+		if f.IsVideo() {
+			videos++
+			assert.Equal(t, "IMG_1234.MOV", filepath.Base(f.FileName()))
+		}
+	}
+
+	assert.Equal(t, 1, videos)
+}
+
 func TestMediaFile_RelatedFiles_Ordering(t *testing.T) {
 	conf := config.TestConfig()
 
@@ -528,6 +617,48 @@ func TestMediaFile_Move(t *testing.T) {
 	assert.Equal(t, destName, m.FileName())
 }
 
+func TestMediaFile_Move_CrossDevice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "move")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	rename = func(oldName, newName string) error {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: syscall.EXDEV}
+	}
+
+	defer func() { rename = os.Rename }()
+
+	f, err := NewMediaFile("../meta/testdata/photoshop.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origName := filepath.Join(dir, "original.jpg")
+	destName := filepath.Join(dir, "destination.jpg")
+
+	if err := f.Copy(origName); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewMediaFile(origName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := m.Hash()
+
+	assert.Nil(t, m.Move(destName))
+	assert.False(t, fs.FileExists(origName))
+	assert.Equal(t, destName, m.FileName())
+	assert.Equal(t, hash, fs.Hash(destName))
+}
+
 func TestMediaFile_Copy(t *testing.T) {
 	conf := config.TestConfig()
 
@@ -970,6 +1101,92 @@ func TestMediaFile_Height(t *testing.T) {
 	})
 }
 
+// writeJpegHeader creates a JPEG file that only contains a frame header with the given dimensions,
+// so that resolution limits can be tested without huge fixtures.
+func writeJpegHeader(t *testing.T, fileName string, width, height int) {
+	data := []byte{
+		0xff, 0xd8, // SOI
+		0xff, 0xc0, 0x00, 0x0b, 0x08, byte(height >> 8), byte(height), byte(width >> 8), byte(width), 0x01, 0x01, 0x11, 0x00, // SOF0
+		0xff, 0xda, 0x00, 0x08, 0x01, 0x01, 0x00, 0x00, 0x3f, 0x00, // SOS
+		0xff, 0xd9, // EOI
+	}
+
+	if err := ioutil.WriteFile(fileName, data, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMediaFile_CheckLimits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "limits")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "panorama.jpg")
+
+	// 60000 x 10000 pixels = 600 megapixels.
+	writeJpegHeader(t, fileName, 60000, 10000)
+
+	mediaFile, err := NewMediaFile(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sizeLimit, resolutionLimit := thumb.SizeLimit, thumb.ResolutionLimit
+
+	defer func() {
+		thumb.SizeLimit, thumb.ResolutionLimit = sizeLimit, resolutionLimit
+	}()
+
+	t.Run("unlimited", func(t *testing.T) {
+		thumb.SizeLimit, thumb.ResolutionLimit = 0, 0
+
+		assert.NoError(t, mediaFile.CheckLimits())
+	})
+	t.Run("resolution", func(t *testing.T) {
+		thumb.SizeLimit, thumb.ResolutionLimit = 1000, 100
+
+		err := mediaFile.CheckLimits()
+
+		assert.True(t, errors.Is(err, thumb.ErrTooLarge))
+		assert.EqualError(t, err, "too large, 600 megapixels exceed the limit of 100")
+		assert.Equal(t, 60000, mediaFile.Width())
+	})
+	t.Run("resolution ok", func(t *testing.T) {
+		thumb.SizeLimit, thumb.ResolutionLimit = 0, 600
+
+		assert.NoError(t, mediaFile.CheckLimits())
+	})
+	t.Run("open", func(t *testing.T) {
+		thumb.SizeLimit, thumb.ResolutionLimit = 0, 100
+
+		_, err := thumb.Open(fileName)
+
+		assert.True(t, errors.Is(err, thumb.ErrTooLarge))
+	})
+	t.Run("file size", func(t *testing.T) {
+		thumb.SizeLimit, thumb.ResolutionLimit = 1, 0
+
+		largeName := filepath.Join(dir, "scan.tiff")
+
+		if err := ioutil.WriteFile(largeName, make([]byte, 2*1048576), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		large, err := NewMediaFile(largeName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.EqualError(t, large.CheckLimits(), "too large, 2 MB exceed the limit of 1 MB")
+	})
+}
+
 func TestMediaFile_AspectRatio(t *testing.T) {
 	t.Run("/iphone_7.heic", func(t *testing.T) {
 		conf := config.TestConfig()
@@ -1057,6 +1274,175 @@ func TestMediaFile_Thumbnail(t *testing.T) {
 	})
 }
 
+func TestMediaFile_Thumbnail_WebP(t *testing.T) {
+	conf := config.TestConfig()
+
+	if err := conf.CreateDirectories(); err != nil {
+		t.Error(err)
+	}
+
+	thumbsPath := conf.CachePath() + "/_tmp_webp"
+
+	defer os.RemoveAll(thumbsPath)
+
+	defer func() { thumb.Format = conf.ThumbFormat() }()
+
+	t.Run("existing jpeg fallback", func(t *testing.T) {
+		image, err := NewMediaFile(conf.ExamplesPath() + "/elephants.jpg")
+		assert.Nil(t, err)
+
+		thumb.Format = fs.TypeJpeg
+
+		jpegThumb, err := image.Thumbnail(thumbsPath, "tile_224")
+		assert.Nil(t, err)
+
+		thumb.Format = fs.TypeWebP
+
+		thumbnail, err := image.Thumbnail(thumbsPath, "tile_224")
+		assert.Nil(t, err)
+		assert.Equal(t, jpegThumb, thumbnail)
+	})
+	t.Run("webp", func(t *testing.T) {
+		if thumb.CWebPBin == "" {
+			t.Skip("cwebp not installed")
+		}
+
+		image, err := NewMediaFile(conf.ExamplesPath() + "/elephants.jpg")
+		assert.Nil(t, err)
+
+		thumb.Format = fs.TypeWebP
+
+		thumbnail, err := image.Thumbnail(thumbsPath, "fit_720")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, ".webp", filepath.Ext(thumbnail))
+		assert.Equal(t, "image/webp", thumb.ContentType(thumbnail))
+
+		img, err := imaging.Open(thumbnail)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.LessOrEqual(t, img.Bounds().Max.X, 720)
+		assert.LessOrEqual(t, img.Bounds().Max.Y, 720)
+		assert.True(t, img.Bounds().Max.X == 720 || img.Bounds().Max.Y == 720)
+
+		jpegThumb, err := image.JpegThumbnail(thumbsPath, "fit_720")
+		assert.Nil(t, err)
+		assert.Equal(t, ".jpg", filepath.Ext(jpegThumb))
+	})
+}
+
+// jpegMarkers returns the markers of all segments before the image data.
+func jpegMarkers(t *testing.T, fileName string) (result []byte) {
+	data, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF && data[i+1] != 0xDA; i += 2 + int(data[i+2])<<8 + int(data[i+3]) {
+		result = append(result, data[i+1])
+	}
+
+	return result
+}
+
+func TestMediaFile_Thumbnail_Metadata(t *testing.T) {
+	conf := config.TestConfig()
+
+	if err := conf.CreateDirectories(); err != nil {
+		t.Error(err)
+	}
+
+	thumbsPath := conf.CachePath() + "/_tmp_metadata"
+
+	defer os.RemoveAll(thumbsPath)
+
+	defer func() {
+		thumb.Copyright = conf.ThumbCopyright()
+		thumb.Progressive = conf.ThumbProgressive()
+	}()
+
+	t.Run("no exif", func(t *testing.T) {
+		defer os.RemoveAll(thumbsPath)
+
+		image, err := NewMediaFile(conf.ExamplesPath() + "/elephants.jpg")
+		assert.Nil(t, err)
+
+		thumb.Copyright = ""
+
+		thumbnail, err := image.JpegThumbnail(thumbsPath, "fit_720")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotContains(t, jpegMarkers(t, thumbnail), byte(0xE1))
+		assert.NotContains(t, jpegMarkers(t, thumbnail), byte(0xED))
+	})
+	t.Run("copyright", func(t *testing.T) {
+		defer os.RemoveAll(thumbsPath)
+
+		image, err := NewMediaFile(conf.ExamplesPath() + "/elephants.jpg")
+		assert.Nil(t, err)
+
+		thumb.Copyright = "© Jane Doe"
+
+		thumbnail, err := image.JpegThumbnail(thumbsPath, "fit_720")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Contains(t, jpegMarkers(t, thumbnail), byte(0xE1))
+
+		data, err := meta.Exif(thumbnail)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "© Jane Doe", data.Copyright)
+		assert.Equal(t, float32(0), data.Lat)
+		assert.Equal(t, float32(0), data.Lng)
+		assert.Equal(t, "", data.CameraModel)
+	})
+	t.Run("progressive", func(t *testing.T) {
+		if thumb.JpegTranBin == "" {
+			t.Skip("jpegtran not installed")
+		}
+
+		defer os.RemoveAll(thumbsPath)
+
+		image, err := NewMediaFile(conf.ExamplesPath() + "/elephants.jpg")
+		assert.Nil(t, err)
+
+		thumb.Copyright = ""
+		thumb.Progressive = true
+
+		large, err := image.JpegThumbnail(thumbsPath, "fit_720")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		small, err := image.JpegThumbnail(thumbsPath, "tile_224")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Contains(t, jpegMarkers(t, large), byte(0xC2))
+		assert.NotContains(t, jpegMarkers(t, small), byte(0xC2))
+		assert.NotContains(t, jpegMarkers(t, large), byte(0xE1))
+	})
+}
+
 func TestMediaFile_Resample(t *testing.T) {
 	conf := config.TestConfig()
 
@@ -1,11 +1,73 @@
 package photoprism
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
-// MetaData returns exif meta data of a media file.
+// MetaData returns exif meta data of a media file. For MP4 and QuickTime videos, the
+// movie header is parsed instead. Values missing in the file are added from its Google
+// Takeout sidecar, if one was set.
 func (m *MediaFile) MetaData() (result meta.Data, err error) {
-	m.once.Do(func() { m.metaData, err = meta.Exif(m.FileName()) })
-	return m.metaData, err
+	m.once.Do(func() {
+		if m.HasFileType(fs.TypeMP4) || m.HasFileType(fs.TypeMov) {
+			m.metaData, m.metaDataErr = meta.MP4(m.FileName())
+		} else {
+			m.metaData, m.metaDataErr = meta.Exif(m.FileName())
+		}
+
+		if m.takeout == "" {
+			return
+		}
+
+		if data, takeoutErr := meta.Takeout(m.takeout); takeoutErr != nil {
+			m.metaData.Warnings = append(m.metaData.Warnings, takeoutErr.Error())
+		} else {
+			// Takeout exports often strip the Exif data, so the sidecar alone is sufficient.
+			m.metaData.Merge(data)
+			m.metaDataErr = nil
+		}
+	})
+
+	return m.metaData, m.metaDataErr
+}
+
+// SaveMetadata writes title, description, keywords, copyright and GPS coordinates of a photo back
+// to its original file using exiftool. In read-only mode, or if exiftool is not installed,
+// an XMP sidecar file is created in the sidecar path instead. Returns the name of the file written.
+func SaveMetadata(conf *config.Config, photo entity.Photo, file entity.File) (fileName string, err error) {
+	data := meta.Data{
+		Title:       photo.PhotoTitle,
+		Description: photo.Description.PhotoDescription,
+		Keywords:    photo.Description.PhotoKeywords,
+		Copyright:   photo.Description.PhotoCopyright,
+		Lat:         photo.PhotoLat,
+		Lng:         photo.PhotoLng,
+	}
+
+	if !conf.ReadOnly() && conf.ExifToolBin() != "" {
+		fileName = conf.OriginalsFileName(file.Root(), file.FileName)
+	} else if file.Root() == entity.RootDefault {
+		fileName = filepath.Join(conf.SidecarPath(), strings.TrimSuffix(file.FileName, filepath.Ext(file.FileName))+".xmp")
+	} else {
+		fileName = filepath.Join(conf.SidecarPath(), rootsDir, file.Root(), strings.TrimSuffix(file.FileName, filepath.Ext(file.FileName))+".xmp")
+	}
+
+	if fileName == "" {
+		return fileName, fmt.Errorf("metadata: originals root \"%s\" not found", file.Root())
+	}
+
+	if err := meta.Write(fileName, data); err != nil {
+		return fileName, err
+	}
+
+	log.Infof("metadata: saved \"%s\"", fileName)
+
+	return fileName, nil
 }
@@ -1,11 +1,14 @@
 package photoprism
 
 import (
+	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -153,7 +156,8 @@ func TestMediaFile_Exif_HEIF(t *testing.T) {
 	assert.Equal(t, "", jpegInfo.UniqueID)
 	assert.Equal(t, "2018-09-10 03:16:13 +0000 UTC", jpegInfo.TakenAt.String())
 	assert.Equal(t, "2018-09-10 12:16:13 +0000 UTC", jpegInfo.TakenAtLocal.String())
-	assert.Equal(t, 6, jpegInfo.Orientation)
+	// The converted image is already rotated.
+	assert.Equal(t, 1, jpegInfo.Orientation)
 	assert.Equal(t, "iPhone 7", jpegInfo.CameraModel)
 	assert.Equal(t, "Apple", jpegInfo.CameraMake)
 	assert.Equal(t, "iPhone 7 back camera 3.99mm f/1.8", jpegInfo.LensModel)
@@ -176,3 +180,73 @@ func TestMediaFile_Exif_HEIF(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestSaveMetadata(t *testing.T) {
+	t.Run("read-only", func(t *testing.T) {
+		cachePath, err := ioutil.TempDir("", "photoprism")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(cachePath)
+
+		ctx := config.CliTestContext()
+
+		if err := ctx.Set("read-only", "true"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ctx.Set("cache-path", cachePath); err != nil {
+			t.Fatal(err)
+		}
+
+		conf := config.NewConfig(ctx)
+
+		if err := ctx.Set("originals-path", conf.ExamplesPath()); err != nil {
+			t.Fatal(err)
+		}
+
+		conf = config.NewConfig(ctx)
+
+		originalName := conf.ExamplesPath() + "/elephants.jpg"
+		originalHash := fs.Hash(originalName)
+
+		photo := entity.Photo{PhotoTitle: "Elephants / Kruger", PhotoLat: -24.01, PhotoLng: 31.48}
+		photo.Description.PhotoDescription = "Elephants at the waterhole"
+		photo.Description.PhotoKeywords = "elephant, kruger, waterhole"
+		photo.Description.PhotoCopyright = "PhotoPrism"
+
+		fileName, err := SaveMetadata(conf, photo, entity.File{FileName: "elephants.jpg"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, conf.SidecarPath()+"/elephants.xmp", fileName)
+		assert.Equal(t, originalHash, fs.Hash(originalName))
+
+		data, err := meta.XMP(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Elephants / Kruger", data.Title)
+		assert.Equal(t, "Elephants at the waterhole", data.Description)
+		assert.Equal(t, "elephant, kruger, waterhole", data.Keywords)
+		assert.Equal(t, "PhotoPrism", data.Copyright)
+		assert.InDelta(t, -24.01, data.Lat, 0.0001)
+		assert.InDelta(t, 31.48, data.Lng, 0.0001)
+
+		// Sidecar files of other roots are stored in a separate directory, see rootBase.
+		fileName, err = SaveMetadata(conf, photo, entity.File{FileRoot: "archive", FileName: "2020/elephants.jpg"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, conf.SidecarPath()+"/.roots/archive/2020/elephants.xmp", fileName)
+		assert.FileExists(t, fileName)
+	})
+}
@@ -0,0 +1,15 @@
+package photoprism
+
+import (
+	"time"
+
+	"github.com/photoprism/photoprism/internal/mutex"
+)
+
+// nice pauses background work like indexing and importing while the server is busy serving api
+// requests. It returns early if the worker is canceled.
+func nice() {
+	if paused := mutex.Interactive.Pause(mutex.Worker.Canceled); paused >= time.Second {
+		log.Debugf("workers: paused for %s while the server was busy", paused.Round(time.Second))
+	}
+}
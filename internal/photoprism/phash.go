@@ -0,0 +1,23 @@
+package photoprism
+
+import (
+	"errors"
+
+	"github.com/photoprism/photoprism/pkg/phash"
+)
+
+// PerceptualHash returns the difference hash of a JPEG image. It's computed from the tile_224
+// thumbnail that is also used for image classification, so the original doesn't need to be decoded again.
+func (m *MediaFile) PerceptualHash(thumbPath string) (phash.Hash, error) {
+	if !m.IsJpeg() {
+		return 0, errors.New("no perceptual hash: not a JPEG file")
+	}
+
+	img, err := m.Resample(thumbPath, "tile_224")
+
+	if err != nil {
+		return 0, err
+	}
+
+	return phash.DHash(img), nil
+}
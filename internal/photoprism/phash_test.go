@@ -0,0 +1,53 @@
+package photoprism
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/phash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaFile_PerceptualHash(t *testing.T) {
+	conf := config.TestConfig()
+
+	hash := func(fileName string) phash.Hash {
+		mediaFile, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		h, err := mediaFile.PerceptualHash(conf.ThumbnailsPath())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return h
+	}
+
+	t.Run("resized copy", func(t *testing.T) {
+		original := hash("../../pkg/phash/testdata/ladybug.jpg")
+		copied := hash("../../pkg/phash/testdata/ladybug_small.jpg")
+
+		assert.LessOrEqual(t, original.Distance(copied), conf.DuplicateDistance())
+	})
+	t.Run("different photo", func(t *testing.T) {
+		original := hash("../../pkg/phash/testdata/ladybug.jpg")
+		other := hash("../../pkg/phash/testdata/gopro_hd2.jpg")
+
+		assert.Greater(t, original.Distance(other), conf.DuplicateDistance())
+	})
+	t.Run("not a jpeg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/tweethog.png")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = mediaFile.PerceptualHash(conf.ThumbnailsPath())
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,238 @@
+package photoprism
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// runSaveInterval is the number of completed files after which the progress of a run is saved.
+const runSaveInterval = 100
+
+// runProgress tracks the folders completed by an import or index run, so that the run can be resumed
+// if it is interrupted. Jobs are grouped in steps of consecutive files in the same folder, in the
+// order they are sent to the workers. The cursor moves past a step once all its jobs and the jobs of
+// all previous steps are done, as workers may complete them in a different order.
+type runProgress struct {
+	mu      sync.Mutex
+	db      *gorm.DB
+	run     *entity.Run
+	resume  string
+	steps   []*runStep
+	unsaved int
+}
+
+// runStep contains consecutive jobs of a run with files in the same folder.
+type runStep struct {
+	p       *runProgress
+	dir     string
+	last    string
+	pending int
+	closed  bool
+}
+
+// newRunProgress returns a new progress tracker. If resume is true, the most recent run of the same type
+// and path is continued if it was interrupted, so that files it completed are skipped.
+func newRunProgress(db *gorm.DB, runType, runPath string, resume bool) *runProgress {
+	p := &runProgress{db: db}
+
+	if run, err := entity.FindInterruptedRun(db, runType, runPath); err == nil && resume {
+		run.RunResumed++
+		p.run = run
+		p.resume = run.RunCursor
+
+		log.Infof("%s: resuming run %s after \"%s\"", runType, run.RunUUID, run.RunDir)
+	} else if err == nil {
+		log.Infof("%s: run %s was interrupted, use --resume to skip completed folders", runType, run.RunUUID)
+	}
+
+	if p.run == nil {
+		p.run = entity.NewRun(runType, runPath)
+	}
+
+	p.save()
+
+	return p
+}
+
+// RunID returns the UUID of the run.
+func (p *runProgress) RunID() string {
+	if p == nil {
+		return ""
+	}
+
+	return p.run.RunUUID
+}
+
+// Resumed returns true if the run continues an interrupted run.
+func (p *runProgress) Resumed() bool {
+	if p == nil {
+		return false
+	}
+
+	return p.run.Resumed()
+}
+
+// Completed returns true if the interrupted run already completed the file.
+func (p *runProgress) Completed(fileName string) bool {
+	if p == nil || p.resume == "" {
+		return false
+	}
+
+	return fileName == p.resume || walkBefore(fileName, p.resume)
+}
+
+// CompletedDir returns true if the interrupted run already completed all files in the directory.
+func (p *runProgress) CompletedDir(dir string) bool {
+	if p == nil || p.resume == "" || within(p.resume, dir) {
+		return false
+	}
+
+	return walkBefore(dir, p.resume)
+}
+
+// resumeFrom returns the index of the path that contains the cursor of the interrupted run, so that
+// previous paths can be skipped. The cursor is reset if no path contains it.
+func (p *runProgress) resumeFrom(paths []string) int {
+	if p == nil || p.resume == "" {
+		return 0
+	}
+
+	for i, dir := range paths {
+		if within(p.resume, dir) {
+			return i
+		}
+	}
+
+	p.resume = ""
+
+	return 0
+}
+
+// Passed resets the cursor of the interrupted run, once the path that contains it was walked.
+func (p *runProgress) Passed() {
+	if p == nil {
+		return
+	}
+
+	p.resume = ""
+}
+
+// Add adds a job for a file to the current step, or to a new step if the file is in a different folder.
+func (p *runProgress) Add(fileName string) *runStep {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dir := filepath.Dir(fileName)
+
+	if n := len(p.steps); n > 0 && !p.steps[n-1].closed {
+		if s := p.steps[n-1]; s.dir == dir {
+			s.last = fileName
+			s.pending++
+			return s
+		}
+
+		p.steps[n-1].closed = true
+		p.advance()
+	}
+
+	s := &runStep{p: p, dir: dir, last: fileName, pending: 1}
+	p.steps = append(p.steps, s)
+
+	return s
+}
+
+// Done marks a job of the step as done.
+func (s *runStep) Done() {
+	if s == nil {
+		return
+	}
+
+	p := s.p
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s.pending--
+	p.run.RunFiles++
+	p.unsaved++
+	p.advance()
+
+	if p.unsaved >= runSaveInterval {
+		p.save()
+	}
+}
+
+// advance moves the cursor past leading steps whose jobs are all done.
+func (p *runProgress) advance() {
+	for len(p.steps) > 0 && p.steps[0].closed && p.steps[0].pending <= 0 {
+		p.run.RunDir = p.steps[0].dir
+		p.run.RunCursor = p.steps[0].last
+		p.steps = p.steps[1:]
+	}
+}
+
+// Finish saves the progress once all jobs were done. The run is only marked as finished if it
+// wasn't interrupted, so that it can be resumed otherwise.
+func (p *runProgress) Finish(interrupted bool) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !interrupted {
+		if n := len(p.steps); n > 0 {
+			p.steps[n-1].closed = true
+		}
+
+		p.advance()
+
+		if err := p.run.Finish(p.db); err != nil {
+			log.Errorf("%s: %s", p.run.RunType, err)
+		}
+
+		return
+	}
+
+	p.advance()
+	p.save()
+}
+
+// save stores the progress, errors are logged as the run can continue without.
+func (p *runProgress) save() {
+	p.unsaved = 0
+
+	if err := p.run.Save(p.db); err != nil {
+		log.Errorf("%s: %s", p.run.RunType, err)
+	}
+}
+
+// walkBefore returns true if filepath.Walk visits name a before b, as directory entries are
+// walked in lexical order.
+func walkBefore(a, b string) bool {
+	sep := string(filepath.Separator)
+	ea := strings.Split(filepath.Clean(a), sep)
+	eb := strings.Split(filepath.Clean(b), sep)
+
+	for i := 0; i < len(ea) && i < len(eb); i++ {
+		if ea[i] != eb[i] {
+			return ea[i] < eb[i]
+		}
+	}
+
+	return len(ea) < len(eb)
+}
+
+// within returns true if the file is inside dir.
+func within(fileName, dir string) bool {
+	return strings.HasPrefix(filepath.Clean(fileName), filepath.Clean(dir)+string(filepath.Separator))
+}
@@ -0,0 +1,233 @@
+package photoprism
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/nsfw"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkBefore(t *testing.T) {
+	assert.True(t, walkBefore("/import/a/b.jpg", "/import/a/c.jpg"))
+	assert.False(t, walkBefore("/import/a/c.jpg", "/import/a/b.jpg"))
+	assert.False(t, walkBefore("/import/a/c.jpg", "/import/a/c.jpg"))
+	assert.True(t, walkBefore("/import/a", "/import/a/c.jpg"))
+	assert.True(t, walkBefore("/import/a/z.jpg", "/import/b/a.jpg"))
+
+	// Folders are walked before files in the parent folder with a name that starts like the folder.
+	assert.True(t, walkBefore("/import/a/x.jpg", "/import/a.jpg"))
+	assert.True(t, walkBefore("/import/2020/12/a.jpg", "/import/2020/12a/a.jpg"))
+}
+
+func TestWithin(t *testing.T) {
+	assert.True(t, within("/import/a/b.jpg", "/import"))
+	assert.True(t, within("/import/a/b.jpg", "/import/a/"))
+	assert.False(t, within("/import", "/import"))
+	assert.False(t, within("/import2/a.jpg", "/import"))
+}
+
+func TestRunProgress_Completed(t *testing.T) {
+	p := &runProgress{run: entity.NewRun(entity.RunImport, "/import"), resume: "/import/b/2.jpg"}
+
+	assert.True(t, p.Completed("/import/a/9.jpg"))
+	assert.True(t, p.Completed("/import/b/1.jpg"))
+	assert.True(t, p.Completed("/import/b/2.jpg"))
+	assert.False(t, p.Completed("/import/b/3.jpg"))
+	assert.False(t, p.Completed("/import/c/1.jpg"))
+
+	assert.True(t, p.CompletedDir("/import/a"))
+	assert.False(t, p.CompletedDir("/import"))
+	assert.False(t, p.CompletedDir("/import/b"))
+	assert.False(t, p.CompletedDir("/import/c"))
+
+	p.Passed()
+
+	assert.False(t, p.Completed("/import/a/9.jpg"))
+	assert.False(t, p.CompletedDir("/import/a"))
+
+	var none *runProgress
+
+	assert.False(t, none.Completed("/import/a/9.jpg"))
+	assert.Equal(t, "", none.RunID())
+	assert.Nil(t, none.Add("/import/a/9.jpg"))
+}
+
+func TestRunProgress_ResumeFrom(t *testing.T) {
+	paths := []string{"/originals", "/archive/2020", "/photos"}
+
+	t.Run("found", func(t *testing.T) {
+		p := &runProgress{resume: "/archive/2020/a/1.jpg"}
+		assert.Equal(t, 1, p.resumeFrom(paths))
+		assert.Equal(t, "/archive/2020/a/1.jpg", p.resume)
+	})
+	t.Run("not found", func(t *testing.T) {
+		p := &runProgress{resume: "/archive/2019/a/1.jpg"}
+		assert.Equal(t, 0, p.resumeFrom(paths))
+		assert.Equal(t, "", p.resume)
+	})
+}
+
+func TestRunProgress_Add(t *testing.T) {
+	p := &runProgress{run: entity.NewRun(entity.RunImport, "/import")}
+
+	a1 := p.Add("/import/a/1.jpg")
+	a2 := p.Add("/import/a/2.jpg")
+	b1 := p.Add("/import/b/1.jpg")
+	c1 := p.Add("/import/c/1.jpg")
+
+	assert.Equal(t, a1, a2)
+	assert.Len(t, p.steps, 3)
+
+	// Folders completed out of order don't move the cursor.
+	b1.Done()
+	assert.Equal(t, "", p.run.RunCursor)
+
+	a2.Done()
+	assert.Equal(t, "", p.run.RunCursor)
+
+	a1.Done()
+	assert.Equal(t, "/import/b", p.run.RunDir)
+	assert.Equal(t, "/import/b/1.jpg", p.run.RunCursor)
+
+	// The last folder may still get more files until the next folder is walked.
+	c1.Done()
+	assert.Equal(t, "/import/b/1.jpg", p.run.RunCursor)
+	assert.Equal(t, 4, p.run.RunFiles)
+
+	var none *runStep
+	none.Done()
+}
+
+// indexResumeExamples contains the example files copied to folders of a temporary originals path.
+var indexResumeExamples = map[string]string{
+	"index-resume/a/elephants.jpg":  "elephants.jpg",
+	"index-resume/a/cat_brown.jpg":  "cat_brown.jpg",
+	"index-resume/b/fern_green.jpg": "fern_green.jpg",
+	"index-resume/c/tree_white.jpg": "tree_white.jpg",
+	"index-resume/d/beach_wood.jpg": "beach_wood.jpg",
+	"index-resume/e/iphone_7.jpg":   "iphone_7.jpg",
+}
+
+// indexResumeEvents subscribes to indexing events and returns the names of indexed files and the run
+// ids once stop is called. The optional callback is called for every event.
+func indexResumeEvents(callback func(fileName string)) (stop func() (files, runs []string, resumed bool)) {
+	s := event.Subscribe("index.indexing")
+
+	var wg sync.WaitGroup
+	var files, runs []string
+	var resumed bool
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for msg := range s.Receiver {
+			fileName, _ := msg.Fields["fileName"].(string)
+			runId, _ := msg.Fields["runId"].(string)
+			files = append(files, fileName)
+			runs = append(runs, runId)
+			resumed, _ = msg.Fields["resumed"].(bool)
+
+			if callback != nil {
+				callback(fileName)
+			}
+		}
+	}()
+
+	return func() ([]string, []string, bool) {
+		event.Unsubscribe(s)
+		wg.Wait()
+		return files, runs, resumed
+	}
+}
+
+func TestIndex_StartContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "photoprism-resume")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	examplesPath := config.TestConfig().ExamplesPath()
+
+	for dest, src := range indexResumeExamples {
+		mediaFile, err := NewMediaFile(filepath.Join(examplesPath, src))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := mediaFile.Copy(filepath.Join(dir, dest)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// With one worker, the walker waits for each folder to be indexed before it sends the next one.
+	conf := config.TestConfigWorkers(dir, 1)
+	ind := NewIndex(conf, classify.New(conf.ResourcesPath(), true), nsfw.New(conf.NSFWModelPath()))
+	opt := IndexOptionsAll()
+	opt.Path = "index-resume"
+
+	// The first run is interrupted once files in folder b are indexed.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stop := indexResumeEvents(func(fileName string) {
+		if strings.HasPrefix(fileName, "index-resume/b/") {
+			cancel()
+		}
+	})
+
+	ind.StartContext(ctx, opt)
+	cancel()
+
+	files, runs, _ := stop()
+
+	assert.Contains(t, files, "index-resume/a/elephants.jpg")
+	assert.NotContains(t, files, "index-resume/e/iphone_7.jpg")
+
+	run, err := entity.FindInterruptedRun(conf.Db(), entity.RunIndex, ":index-resume")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, run.RunUUID, runs[0])
+	assert.True(t, within(run.RunCursor, filepath.Join(dir, "index-resume")))
+
+	// The resumed run skips folders the first run completed and sends the run id of the first run.
+	opt.Resume = true
+	stop = indexResumeEvents(nil)
+
+	ind.StartContext(context.Background(), opt)
+
+	files, runs, resumed := stop()
+
+	assert.True(t, resumed)
+	assert.Contains(t, files, "index-resume/e/iphone_7.jpg")
+	assert.NotContains(t, files, "index-resume/a/elephants.jpg")
+	assert.NotContains(t, files, "index-resume/a/cat_brown.jpg")
+
+	for _, fileName := range files {
+		assert.False(t, walkBefore(filepath.Join(dir, fileName), run.RunCursor), fileName)
+	}
+
+	for _, runId := range runs {
+		assert.Equal(t, run.RunUUID, runId)
+	}
+
+	_, err = entity.FindInterruptedRun(conf.Db(), entity.RunIndex, ":index-resume")
+	assert.Error(t, err)
+}
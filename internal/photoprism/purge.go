@@ -0,0 +1,297 @@
+package photoprism
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// PurgeMaxMissing is the maximum share of checked files that may be missing, unless forced. More missing files
+// usually mean that the storage isn't mounted, so photos must not be deleted.
+const PurgeMaxMissing = 0.5
+
+// purgeMinFiles is the number of checked files from which PurgeMaxMissing applies.
+const purgeMinFiles = 10
+
+// Purge removes missing files and orphaned records from the index.
+type Purge struct {
+	conf *config.Config
+	db   *gorm.DB
+}
+
+// PurgeOptions represents purge options.
+type PurgeOptions struct {
+	Root   string // Only check files in this originals root, all roots are checked by default.
+	Path   string // Only check files in this sub-folder of the root.
+	Hard   bool   // Permanently delete photos instead of flagging them as deleted.
+	DryRun bool   // Only report affected records.
+	Force  bool   // Don't abort if the root is empty or most files are missing.
+}
+
+// PurgeResult contains the records affected by a purge.
+type PurgeResult struct {
+	Files       []string `json:"files"`
+	Photos      []string `json:"photos"`
+	PhotoLabels int      `json:"photoLabels"`
+	PhotoAlbums int      `json:"photoAlbums"`
+}
+
+// NewPurge returns a new purge worker and expects the config as argument.
+func NewPurge(conf *config.Config) *Purge {
+	return &Purge{
+		conf: conf,
+		db:   conf.Db(),
+	}
+}
+
+// purgeFile contains the file columns needed to find photos without existing files.
+type purgeFile struct {
+	ID          uint
+	PhotoID     uint
+	FileName    string
+	FileMissing bool
+}
+
+// Start flags files as missing if they don't exist anymore, deletes photos without existing files
+// and removes label and album associations of photos, labels and albums that don't exist anymore.
+func (p *Purge) Start(opt PurgeOptions) (result PurgeResult, err error) {
+	if err := mutex.Worker.Start(); err != nil {
+		return result, err
+	}
+
+	defer mutex.Worker.Stop()
+
+	var missing map[uint]bool
+
+	if result.Files, missing, err = p.missingFiles(opt); err != nil {
+		return result, err
+	}
+
+	if result.Photos, err = p.deletePhotos(opt, missing); err != nil {
+		return result, err
+	}
+
+	if result.PhotoLabels, err = p.orphans(opt, "photos_labels", "photo_id NOT IN (SELECT id FROM photos) OR label_id NOT IN (SELECT id FROM labels)"); err != nil {
+		return result, err
+	}
+
+	if result.PhotoAlbums, err = p.orphans(opt, "photos_albums", "photo_uuid NOT IN (SELECT photo_uuid FROM photos) OR album_uuid NOT IN (SELECT album_uuid FROM albums)"); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// missingFiles flags indexed files as missing if they don't exist anymore and returns their names
+// and ids. Files of roots that aren't configured anymore are never flagged, as they may be offline.
+// Unless forced, nothing is flagged if a root doesn't exist, is empty, or most checked files are missing,
+// as the storage probably isn't mounted.
+func (p *Purge) missingFiles(opt PurgeOptions) (result []string, missing map[uint]bool, err error) {
+	missing = make(map[uint]bool)
+
+	roots, err := selectRoots(p.conf, opt.Root, opt.Path)
+
+	if err != nil {
+		return result, missing, err
+	}
+
+	var found []purgeFile
+	checked := 0
+
+	for _, root := range roots {
+		var files []purgeFile
+
+		q, err := p.rootFiles(root, opt.Path)
+
+		if err != nil {
+			return result, missing, err
+		}
+
+		// Files of photos in the trash were moved on purpose.
+		q = q.Select("id, file_name").Where("file_missing = ?", false).
+			Where("photo_id NOT IN (SELECT id FROM photos WHERE trashed_at IS NOT NULL)")
+
+		if err := q.Scan(&files).Error; err != nil {
+			return result, missing, err
+		}
+
+		if len(files) == 0 {
+			continue
+		}
+
+		if !opt.Force && (!fs.PathExists(root.Path) || fs.IsEmpty(root.Path)) {
+			return result, missing, fmt.Errorf("originals root \"%s\" is empty or doesn't exist, not mounted?", root.Name)
+		}
+
+		checked += len(files)
+
+		for _, f := range files {
+			if !fs.FileExists(filepath.Join(root.Path, f.FileName)) {
+				found = append(found, f)
+			}
+		}
+	}
+
+	if !opt.Force && len(found) > 0 && checked >= purgeMinFiles && float64(len(found)) > float64(checked)*PurgeMaxMissing {
+		return result, missing, fmt.Errorf("%d of %d files are missing, not mounted? use force to purge anyway", len(found), checked)
+	}
+
+	for _, f := range found {
+		result = append(result, f.FileName)
+		missing[f.ID] = true
+
+		if opt.DryRun {
+			continue
+		}
+
+		if err := p.db.Model(&entity.File{}).Where("id = ?", f.ID).Update("file_missing", true).Error; err != nil {
+			return result, missing, err
+		}
+
+		log.Infof("purge: flagged \"%s\" as missing", f.FileName)
+	}
+
+	return result, missing, nil
+}
+
+// rootFiles returns a query for the indexed files in a sub-folder of an originals root.
+func (p *Purge) rootFiles(root config.Root, path string) (*gorm.DB, error) {
+	q := p.db.Model(&entity.File{}).Where("file_root = ?", root.Name)
+
+	purgePath, err := fs.SubPath(root.Path, path)
+
+	if err != nil {
+		return q, err
+	}
+
+	if subPath, err := filepath.Rel(root.Path, purgePath); err == nil && subPath != "." {
+		q = q.Where("file_name LIKE ?", filepath.ToSlash(subPath)+"/%")
+	}
+
+	return q, nil
+}
+
+// deletePhotos deletes photos whose files are all missing and returns their uuids. Only photos with
+// files in the selected roots and path are checked. The ids of files flagged as missing are given
+// as argument, as they were not updated in the database in a dry run.
+func (p *Purge) deletePhotos(opt PurgeOptions, missing map[uint]bool) (result []string, err error) {
+	roots, err := selectRoots(p.conf, opt.Root, opt.Path)
+
+	if err != nil {
+		return result, err
+	}
+
+	var files []purgeFile
+
+	for _, root := range roots {
+		q, err := p.rootFiles(root, opt.Path)
+
+		if err != nil {
+			return result, err
+		}
+
+		var rootFiles []purgeFile
+
+		// Other files of the same photos may be stored in a different folder or root.
+		if err := p.db.Model(&entity.File{}).Select("id, photo_id, file_name, file_missing").
+			Where("photo_id IN (?)", q.Select("photo_id").QueryExpr()).Scan(&rootFiles).Error; err != nil {
+			return result, err
+		}
+
+		files = append(files, rootFiles...)
+	}
+
+	exists := make(map[uint]bool)
+
+	for _, f := range files {
+		exists[f.PhotoID] = exists[f.PhotoID] || !f.FileMissing && !missing[f.ID]
+	}
+
+	var photoIDs []int
+
+	for photoID, ok := range exists {
+		if !ok {
+			photoIDs = append(photoIDs, int(photoID))
+		}
+	}
+
+	sort.Ints(photoIDs)
+
+	for _, photoID := range photoIDs {
+		var photo entity.Photo
+
+		if err := p.db.Select("id, photo_uuid").Where("id = ?", photoID).First(&photo).Error; gorm.IsRecordNotFoundError(err) {
+			continue
+		} else if err != nil {
+			return result, err
+		}
+
+		result = append(result, photo.PhotoUUID)
+
+		if opt.DryRun {
+			continue
+		}
+
+		if err := p.deletePhoto(photo, opt.Hard); err != nil {
+			return result, err
+		}
+
+		log.Infof("purge: deleted photo %s", photo.PhotoUUID)
+	}
+
+	return result, nil
+}
+
+// deletePhoto flags a photo as deleted, or permanently deletes it including files and associations.
+func (p *Purge) deletePhoto(photo entity.Photo, hard bool) error {
+	if !hard {
+		return p.db.Delete(&entity.Photo{ID: photo.ID}).Error
+	}
+
+	return deletePhotoPermanently(p.db, photo)
+}
+
+// deletePhotoPermanently deletes a photo from the index, including its files and associations.
+func deletePhotoPermanently(db *gorm.DB, photo entity.Photo) error {
+	tx := db.Begin()
+
+	for _, model := range []interface{}{&entity.File{}, &entity.PhotoLabel{}, &entity.PhotoKeyword{}, &entity.Description{}} {
+		if err := tx.Unscoped().Where("photo_id = ?", photo.ID).Delete(model).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Where("photo_uuid = ?", photo.PhotoUUID).Delete(&entity.PhotoAlbum{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Unscoped().Delete(&entity.Photo{ID: photo.ID}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// orphans removes rows matching a condition from an association table and returns their number.
+func (p *Purge) orphans(opt PurgeOptions, table, cond string) (count int, err error) {
+	if err := p.db.Table(table).Where(cond).Count(&count).Error; err != nil || count == 0 || opt.DryRun {
+		return count, err
+	}
+
+	if err := p.db.Exec("DELETE FROM " + table + " WHERE " + cond).Error; err != nil {
+		return 0, err
+	}
+
+	log.Infof("purge: removed %d orphaned rows from %s", count, table)
+
+	return count, nil
+}
@@ -0,0 +1,253 @@
+package photoprism
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurge_Start(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	root := filepath.Join(conf.OriginalsPath(), "purge")
+
+	if err := os.MkdirAll(root, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	// createPhoto adds a photo with files in the purge folder to the index.
+	createPhoto := func(title string, fileNames ...string) *entity.Photo {
+		photo := &entity.Photo{PhotoTitle: title}
+
+		if err := db.Create(photo).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		for _, fileName := range fileNames {
+			if err := ioutil.WriteFile(filepath.Join(root, fileName), []byte("jpeg"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			file := &entity.File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "purge/" + fileName}
+
+			if err := db.Create(file).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return photo
+	}
+
+	kept := createPhoto("Kept", "kept_1.jpg", "kept_2.jpg")
+	soft := createPhoto("Soft", "soft.jpg")
+	hard := createPhoto("Hard", "hard.jpg")
+
+	for _, fileName := range []string{"kept_2.jpg", "soft.jpg", "hard.jpg"} {
+		if err := os.Remove(filepath.Join(root, fileName)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	label := entity.NewLabel("Purge", 0)
+
+	if err := db.Create(label).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	orphaned := entity.NewPhotoLabel(kept.ID, label.ID+1000, 10, "manual")
+
+	if err := db.Create(orphaned).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Create(entity.NewPhotoLabel(kept.ID, label.ID, 10, "manual")).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Create(&entity.PhotoAlbum{PhotoUUID: hard.PhotoUUID, AlbumUUID: "4"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	purge := NewPurge(conf)
+
+	t.Run("dry run", func(t *testing.T) {
+		result, err := purge.Start(PurgeOptions{Path: "purge", DryRun: true})
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"purge/kept_2.jpg", "purge/soft.jpg", "purge/hard.jpg"}, result.Files)
+		assert.Subset(t, result.Photos, []string{soft.PhotoUUID, hard.PhotoUUID})
+		assert.NotContains(t, result.Photos, kept.PhotoUUID)
+		assert.GreaterOrEqual(t, result.PhotoLabels, 1)
+
+		var count int
+
+		db.Model(&entity.File{}).Where("file_name LIKE 'purge/%' AND file_missing = ?", true).Count(&count)
+
+		assert.Equal(t, 0, count)
+	})
+	t.Run("soft", func(t *testing.T) {
+		result, err := purge.Start(PurgeOptions{Path: "purge"})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Files, 3)
+		assert.Subset(t, result.Photos, []string{soft.PhotoUUID, hard.PhotoUUID})
+		assert.GreaterOrEqual(t, result.PhotoLabels, 1)
+
+		var count int
+
+		db.Model(&entity.File{}).Where("file_name LIKE 'purge/%' AND file_missing = ?", true).Count(&count)
+		assert.Equal(t, 3, count)
+
+		db.Model(&entity.Photo{}).Where("id IN (?)", []uint{kept.ID, soft.ID, hard.ID}).Count(&count)
+		assert.Equal(t, 1, count)
+
+		db.Unscoped().Model(&entity.Photo{}).Where("id IN (?)", []uint{kept.ID, soft.ID, hard.ID}).Count(&count)
+		assert.Equal(t, 3, count)
+
+		db.Model(&entity.PhotoLabel{}).Where("photo_id = ?", kept.ID).Count(&count)
+		assert.Equal(t, 1, count)
+
+		// Soft deleted photos keep their album membership.
+		db.Model(&entity.PhotoAlbum{}).Where("photo_uuid = ?", hard.PhotoUUID).Count(&count)
+		assert.Equal(t, 1, count)
+	})
+	t.Run("hard", func(t *testing.T) {
+		db.Unscoped().Model(&entity.Photo{}).Where("id = ?", hard.ID).Update("deleted_at", nil)
+
+		result, err := purge.Start(PurgeOptions{Path: "purge", Hard: true})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Files)
+		assert.Contains(t, result.Photos, hard.PhotoUUID)
+		assert.NotContains(t, result.Photos, soft.PhotoUUID)
+
+		var count int
+
+		db.Unscoped().Model(&entity.Photo{}).Where("id = ?", hard.ID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		db.Unscoped().Model(&entity.File{}).Where("photo_id = ?", hard.ID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		db.Model(&entity.PhotoAlbum{}).Where("photo_uuid = ?", hard.PhotoUUID).Count(&count)
+		assert.Equal(t, 0, count)
+	})
+	t.Run("other path", func(t *testing.T) {
+		other := &entity.Photo{PhotoTitle: "Other"}
+
+		if err := db.Create(other).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		defer db.Unscoped().Delete(other)
+
+		file := &entity.File{PhotoID: other.ID, PhotoUUID: other.PhotoUUID, FileName: "purge-other/missing.jpg", FileMissing: true}
+
+		if err := db.Create(file).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		defer db.Unscoped().Delete(file)
+
+		result, err := purge.Start(PurgeOptions{Path: "purge"})
+
+		assert.NoError(t, err)
+		assert.NotContains(t, result.Photos, other.PhotoUUID)
+
+		var count int
+
+		db.Model(&entity.Photo{}).Where("id = ?", other.ID).Count(&count)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestPurge_Start_NotMounted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "purge")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "offline")
+	conf := config.TestConfigRoots(config.TestConfig().OriginalsPath(), "offline="+root)
+	db := conf.Db()
+
+	var fileNames []string
+
+	for i := 0; i < 12; i++ {
+		fileName := fmt.Sprintf("2020/IMG_%04d.jpg", i)
+		fileNames = append(fileNames, fileName)
+
+		if err := db.Create(&entity.File{FileRoot: "offline", FileName: fileName}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer db.Unscoped().Where("file_root = ?", "offline").Delete(&entity.File{})
+
+	purge := NewPurge(conf)
+
+	t.Run("root missing", func(t *testing.T) {
+		_, err := purge.Start(PurgeOptions{Root: "offline", DryRun: true})
+
+		assert.Error(t, err)
+	})
+	t.Run("root empty", func(t *testing.T) {
+		if err := os.MkdirAll(root, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := purge.Start(PurgeOptions{Root: "offline", DryRun: true})
+
+		assert.Error(t, err)
+	})
+	t.Run("most files missing", func(t *testing.T) {
+		if err := os.MkdirAll(filepath.Join(root, "2020"), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, fileName := range fileNames[:4] {
+			if err := ioutil.WriteFile(filepath.Join(root, fileName), []byte("jpeg"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err := purge.Start(PurgeOptions{Root: "offline"})
+
+		assert.Error(t, err)
+
+		var count int
+
+		db.Model(&entity.File{}).Where("file_root = ? AND file_missing = ?", "offline", true).Count(&count)
+		assert.Equal(t, 0, count)
+	})
+	t.Run("force", func(t *testing.T) {
+		result, err := purge.Start(PurgeOptions{Root: "offline", DryRun: true, Force: true})
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, fileNames[4:], result.Files)
+	})
+	t.Run("some files missing", func(t *testing.T) {
+		for _, fileName := range fileNames[4:8] {
+			if err := ioutil.WriteFile(filepath.Join(root, fileName), []byte("jpeg"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result, err := purge.Start(PurgeOptions{Root: "offline", DryRun: true})
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, fileNames[8:], result.Files)
+	})
+}
@@ -10,6 +10,14 @@ import (
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Functions decoding images when creating thumbnails, tests may replace them to count decodes.
+var (
+	openImage    = thumb.Open
+	openEmbedded = thumb.Embedded
 )
 
 // Resample represents a thumbnail generator.
@@ -22,16 +30,71 @@ func NewResample(conf *config.Config) *Resample {
 	return &Resample{conf: conf}
 }
 
-// Start creates default thumbnails for all files in originalsPath.
+// Start creates default thumbnails for all files in all originals roots.
 func (rs *Resample) Start(force bool) error {
+	return rs.StartPath("", force)
+}
+
+// StartPath creates default thumbnails for all files in a sub directory of the default originals root, or in all
+// roots if subPath is empty. Files are skipped if their thumbnails are complete for the current settings, unless
+// force is true. Progress is published as "thumbs.progress" event after each file.
+func (rs *Resample) StartPath(subPath string, force bool) error {
 	if err := mutex.Worker.Start(); err != nil {
 		return err
 	}
 
 	defer mutex.Worker.Stop()
 
-	originalsPath := rs.conf.OriginalsPath()
 	thumbnailsPath := rs.conf.ThumbnailsPath()
+	roots, err := selectRoots(rs.conf, "", subPath)
+
+	if err != nil {
+		return err
+	}
+
+	// Find all files first, so that the progress can be reported.
+	var files MediaFiles
+
+	progress := newResampleProgress()
+
+	for _, root := range roots {
+		resamplePath, err := fs.SubPath(root.Path, subPath)
+
+		if err != nil {
+			return err
+		}
+
+		err = filepath.Walk(resamplePath, func(filename string, fileInfo os.FileInfo, err error) error {
+			defer func() {
+				if err := recover(); err != nil {
+					log.Errorf("resample: %s [panic]", err)
+				}
+			}()
+
+			if mutex.Worker.Canceled() {
+				return errors.New("resample: canceled")
+			}
+
+			if err != nil || fileInfo.IsDir() || strings.HasPrefix(filepath.Base(filename), ".") {
+				return nil
+			}
+
+			mf, err := NewMediaFile(filename)
+
+			if err != nil || !mf.IsJpeg() {
+				return nil
+			}
+
+			files = append(files, mf)
+			progress.add(rs.folder(mf))
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
 
 	jobs := make(chan ResampleJob)
 
@@ -46,28 +109,13 @@ func (rs *Resample) Start(force bool) error {
 		}()
 	}
 
-	err := filepath.Walk(originalsPath, func(filename string, fileInfo os.FileInfo, err error) error {
-		defer func() {
-			if err := recover(); err != nil {
-				log.Errorf("resample: %s [panic]", err)
-			}
-		}()
-
+	for _, mf := range files {
 		if mutex.Worker.Canceled() {
-			return errors.New("resample: canceled")
-		}
-
-		if err != nil || fileInfo.IsDir() || strings.HasPrefix(filepath.Base(filename), ".") {
-			return nil
-		}
-
-		mf, err := NewMediaFile(filename)
-
-		if err != nil || !mf.IsJpeg() {
-			return nil
+			err = errors.New("resample: canceled")
+			break
 		}
 
-		fileName := mf.RelativeName(originalsPath)
+		_, fileName := rootName(rs.conf, mf.FileName())
 
 		event.Publish("index.thumbnails", event.Data{
 			"fileName": fileName,
@@ -79,13 +127,80 @@ func (rs *Resample) Start(force bool) error {
 			mediaFile: mf,
 			path:      thumbnailsPath,
 			force:     force,
+			folder:    rs.folder(mf),
+			progress:  progress,
 		}
-
-		return nil
-	})
+	}
 
 	close(jobs)
 	wg.Wait()
 
 	return err
 }
+
+// resampleFolder contains the number of done and total files in a folder.
+type resampleFolder struct {
+	done  int
+	total int
+}
+
+// resampleProgress counts the done and total files per folder.
+type resampleProgress struct {
+	mutex   sync.Mutex
+	done    int
+	total   int
+	folders map[string]*resampleFolder
+}
+
+func newResampleProgress() *resampleProgress {
+	return &resampleProgress{folders: make(map[string]*resampleFolder)}
+}
+
+// add adds a file in the folder to the total.
+func (p *resampleProgress) add(folder string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.folders[folder] == nil {
+		p.folders[folder] = &resampleFolder{}
+	}
+
+	p.folders[folder].total++
+	p.total++
+}
+
+// finish counts a file in the folder as done and publishes the progress.
+func (p *resampleProgress) finish(folder string) {
+	p.mutex.Lock()
+
+	f, ok := p.folders[folder]
+
+	if !ok {
+		p.mutex.Unlock()
+		return
+	}
+
+	f.done++
+	p.done++
+
+	data := event.Data{
+		"folder":     folder,
+		"done":       f.done,
+		"total":      f.total,
+		"filesDone":  p.done,
+		"filesTotal": p.total,
+	}
+
+	p.mutex.Unlock()
+
+	event.Publish("thumbs.progress", data)
+}
+
+// folder returns the folder of a file for progress events, relative to its originals root.
+func (rs *Resample) folder(mf *MediaFile) string {
+	if dir := strings.TrimPrefix(filepath.Dir(rootBase(rs.conf, mf, false)), rootsDir+string(filepath.Separator)); dir != "." {
+		return dir
+	}
+
+	return ""
+}
@@ -1,12 +1,17 @@
 package photoprism
 
 import (
+	"fmt"
+	"image"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/disintegration/imaging"
 	"github.com/photoprism/photoprism/internal/classify"
 	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/meta"
 	"github.com/photoprism/photoprism/internal/nsfw"
 	"github.com/photoprism/photoprism/internal/thumb"
 
@@ -48,6 +53,80 @@ func TestResample_Start(t *testing.T) {
 	}
 }
 
+func TestResample_StartPath(t *testing.T) {
+	conf := config.TestConfig()
+
+	subPath := "resample-test"
+	dir := filepath.Join(conf.OriginalsPath(), subPath)
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	mf, err := NewMediaFile(conf.ExamplesPath() + "/elephants.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mf.Copy(filepath.Join(dir, "elephants.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Count decoded images instead of inspecting thumbnail files.
+	decoded := 0
+
+	defer func(open, embedded func(string) (image.Image, error)) {
+		openImage = open
+		openEmbedded = embedded
+	}(openImage, openEmbedded)
+
+	openImage = func(fileName string) (image.Image, error) {
+		decoded++
+		return thumb.Open(fileName)
+	}
+
+	openEmbedded = func(fileName string) (image.Image, error) {
+		decoded++
+		return thumb.Embedded(fileName)
+	}
+
+	rs := NewResample(conf)
+
+	t.Run("complete thumbnails are skipped", func(t *testing.T) {
+		if err := rs.StartPath(subPath, false); err != nil {
+			t.Fatal(err)
+		}
+
+		decoded = 0
+
+		if err := rs.StartPath(subPath, false); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 0, decoded)
+	})
+
+	t.Run("changed settings", func(t *testing.T) {
+		defer func(quality int) { thumb.JpegQuality = quality }(thumb.JpegQuality)
+
+		thumb.JpegQuality = thumb.JpegQuality - 1
+		decoded = 0
+
+		if err := rs.StartPath(subPath, false); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Greater(t, decoded, 0)
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		assert.Error(t, rs.StartPath("../", false))
+	})
+}
+
 func TestThumb_Filename(t *testing.T) {
 	conf := config.TestConfig()
 
@@ -240,3 +319,112 @@ func TestThumb_Create(t *testing.T) {
 		assert.NotEqual(t, 150, bounds.Dx())
 	})
 }
+
+func TestThumb_Embedded(t *testing.T) {
+	conf := config.TestConfig()
+
+	thumbsPath := conf.CachePath() + "/_tmp"
+
+	defer os.RemoveAll(thumbsPath)
+
+	fileName := "../meta/testdata/ladybug.jpg"
+
+	t.Run("preview", func(t *testing.T) {
+		img, err := thumb.Embedded(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 160, img.Bounds().Dx())
+		assert.Equal(t, 120, img.Bounds().Dy())
+		assert.True(t, thumb.SourceFits(img, 100, 100, thumb.ResampleFillCenter, thumb.ResampleDefault))
+		assert.True(t, thumb.SourceFits(img, 3, 3, thumb.ResampleResize, thumb.ResampleNearestNeighbor, thumb.ResamplePng))
+		assert.False(t, thumb.SourceFits(img, 224, 224, thumb.ResampleFillCenter, thumb.ResampleDefault))
+		assert.False(t, thumb.SourceFits(img, 720, 720, thumb.ResampleFit, thumb.ResampleDefault))
+	})
+
+	t.Run("tile_100", func(t *testing.T) {
+		thumb.UseEmbedded = true
+		defer func() { thumb.UseEmbedded = false }()
+
+		thumbName, err := thumb.FromFile(fileName, "9a8b7c6d5e", thumbsPath, 100, 100, thumb.ResampleFillCenter, thumb.ResampleDefault)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := imaging.Open(thumbName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 100, img.Bounds().Dx())
+		assert.Equal(t, 100, img.Bounds().Dy())
+	})
+}
+
+func TestThumb_Orientation(t *testing.T) {
+	thumbsPath, err := ioutil.TempDir("", "orientation")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(thumbsPath)
+
+	red := func(img image.Image, x, y int) bool {
+		r, g, b, _ := img.At(x, y).RGBA()
+		return r > 0xC000 && g < 0x4000 && b < 0x4000
+	}
+
+	green := func(img image.Image, x, y int) bool {
+		r, g, b, _ := img.At(x, y).RGBA()
+		return r < 0x4000 && g > 0xC000 && b < 0x4000
+	}
+
+	// All fixtures show a 64x32 image with a red square in the top left and a green square
+	// in the top right corner, the mirrored orientations are 2, 4, 5 and 7.
+	for orientation := 1; orientation <= 8; orientation++ {
+		for _, ext := range []string{".jpg", ".png"} {
+			fileName := fmt.Sprintf("../meta/testdata/orientation/orientation_%d%s", orientation, ext)
+
+			t.Run(filepath.Base(fileName), func(t *testing.T) {
+				mediaFile, err := NewMediaFile(fileName)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, orientation, mediaFile.Orientation())
+				assert.Equal(t, 64, mediaFile.Width())
+				assert.Equal(t, 32, mediaFile.Height())
+
+				thumbnail, err := thumb.FromFile(fileName, mediaFile.Hash(), thumbsPath, 64, 64, thumb.ResampleFit, thumb.ResampleNearestNeighbor)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				img, err := imaging.Open(thumbnail)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, 64, img.Bounds().Dx())
+				assert.Equal(t, 32, img.Bounds().Dy())
+				assert.True(t, red(img, 4, 4), "red square must be in the top left corner")
+				assert.True(t, green(img, 59, 4), "green square must be in the top right corner")
+				assert.False(t, red(img, 4, 27) || green(img, 4, 27), "bottom left corner must be white")
+				assert.False(t, red(img, 59, 27) || green(img, 59, 27), "bottom right corner must be white")
+
+				// Thumbnails are already rotated and must not contain an orientation.
+				if data, err := meta.Exif(thumbnail); err == nil {
+					assert.LessOrEqual(t, data.Orientation, 1)
+				}
+			})
+		}
+	}
+}
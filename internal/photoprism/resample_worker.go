@@ -4,6 +4,8 @@ type ResampleJob struct {
 	mediaFile *MediaFile
 	path      string
 	force     bool
+	folder    string
+	progress  *resampleProgress
 }
 
 func ResampleWorker(jobs <-chan ResampleJob) {
@@ -15,8 +17,14 @@ func ResampleWorker(jobs <-chan ResampleJob) {
 			continue
 		}
 
+		nice()
+
 		if err := mf.ResampleDefault(job.path, job.force); err != nil {
 			log.Errorf("resample: %s", err)
 		}
+
+		if job.progress != nil {
+			job.progress.finish(job.folder)
+		}
 	}
 }
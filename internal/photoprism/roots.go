@@ -0,0 +1,69 @@
+package photoprism
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// rootName returns the originals root of a file and its name relative to the root. Files outside
+// all roots keep their name and belong to the default root.
+func rootName(conf *config.Config, fileName string) (root, relName string) {
+	if r, rel, ok := conf.OriginalsRoot(fileName); ok {
+		return r.Name, rel
+	}
+
+	return entity.RootDefault, fileName
+}
+
+// rootsDir is the reserved directory in the sidecar and cache path for files of other roots than the default root.
+const rootsDir = ".roots"
+
+// rootBase returns the base name of a file relative to its originals root for files stored in the
+// sidecar or cache path. Files of other roots than the default root are stored in rootsDir below the
+// root name, so that they can't collide with files of the default root in a folder with the same name.
+func rootBase(conf *config.Config, m *MediaFile, stripSequence bool) string {
+	root, relName := rootName(conf, m.FileName())
+	relBase := filepath.Join(filepath.Dir(relName), m.Base(stripSequence))
+
+	if strings.HasPrefix(relBase, "..") || filepath.IsAbs(relBase) {
+		relBase = m.Base(stripSequence)
+	}
+
+	if root == entity.RootDefault {
+		return relBase
+	}
+
+	return filepath.Join(rootsDir, root, relBase)
+}
+
+// sidecarBase returns the absolute base name of files converted to the sidecar path in read-only mode,
+// see Convert.JpegName.
+func sidecarBase(conf *config.Config, m *MediaFile, stripSequence bool) string {
+	return filepath.Join(conf.SidecarPath(), rootBase(conf, m, stripSequence))
+}
+
+// selectRoots returns the originals roots a worker should process. All roots are returned if neither
+// a root nor a sub-folder is given, sub-folders are relative to the default root unless a root is given.
+func selectRoots(conf *config.Config, name, subPath string) (config.Roots, error) {
+	roots := conf.OriginalsRoots()
+
+	if name == "" && subPath == "" {
+		return roots, nil
+	}
+
+	if name == "" {
+		name = entity.RootDefault
+	}
+
+	for _, root := range roots {
+		if root.Name == name {
+			return config.Roots{root}, nil
+		}
+	}
+
+	return config.Roots{}, fmt.Errorf("originals root \"%s\" not found", name)
+}
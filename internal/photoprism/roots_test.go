@@ -0,0 +1,83 @@
+package photoprism
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootName(t *testing.T) {
+	conf := config.TestConfigRoots("/photos", "archive=/archive")
+
+	t.Run("default", func(t *testing.T) {
+		root, fileName := rootName(conf, "/photos/2020/IMG_1234.jpg")
+
+		assert.Equal(t, entity.RootDefault, root)
+		assert.Equal(t, "2020/IMG_1234.jpg", fileName)
+	})
+	t.Run("archive", func(t *testing.T) {
+		root, fileName := rootName(conf, "/archive/2010/IMG_1234.jpg")
+
+		assert.Equal(t, "archive", root)
+		assert.Equal(t, "2010/IMG_1234.jpg", fileName)
+	})
+	t.Run("outside", func(t *testing.T) {
+		root, fileName := rootName(conf, "/import/IMG_1234.jpg")
+
+		assert.Equal(t, entity.RootDefault, root)
+		assert.Equal(t, "/import/IMG_1234.jpg", fileName)
+	})
+}
+
+func TestRootBase(t *testing.T) {
+	examples := config.TestConfig().ExamplesPath()
+	conf := config.TestConfigRoots("/photos", "examples="+examples)
+
+	mf, err := NewMediaFile(filepath.Join(examples, "elephants.jpg"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ".roots/examples/elephants", rootBase(conf, mf, false))
+
+	conf = config.TestConfigRoots(examples)
+
+	assert.Equal(t, "elephants", rootBase(conf, mf, false))
+
+	// Files in a default root folder named like another root don't collide.
+	conf = config.TestConfigRoots(filepath.Dir(examples), "examples=/photos")
+
+	assert.Equal(t, "examples/elephants", rootBase(conf, mf, false))
+}
+
+func TestSelectRoots(t *testing.T) {
+	conf := config.TestConfigRoots("/photos", "archive=/archive")
+
+	t.Run("all", func(t *testing.T) {
+		roots, err := selectRoots(conf, "", "")
+
+		assert.NoError(t, err)
+		assert.Len(t, roots, 2)
+	})
+	t.Run("path", func(t *testing.T) {
+		roots, err := selectRoots(conf, "", "2020")
+
+		assert.NoError(t, err)
+		assert.Equal(t, config.Roots{{Name: entity.RootDefault, Path: "/photos"}}, roots)
+	})
+	t.Run("root", func(t *testing.T) {
+		roots, err := selectRoots(conf, "archive", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, config.Roots{{Name: "archive", Path: "/archive"}}, roots)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		_, err := selectRoots(conf, "scans", "")
+
+		assert.EqualError(t, err, "originals root \"scans\" not found")
+	})
+}
@@ -0,0 +1,159 @@
+package photoprism
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// StackMaxInterval is the max difference between the taken at times of stacked files, if both are known.
+const StackMaxInterval = time.Minute
+
+// Stack returns the base name of the stack the file belongs to, or an empty string if not stacked.
+func (m *MediaFile) Stack() string {
+	return m.stack
+}
+
+// SetStack sets the base name of the stack the file belongs to.
+func (m *MediaFile) SetStack(base string) {
+	m.stack = base
+}
+
+// takenAt returns the time the file was taken according to its metadata, or zero if unknown.
+func (m *MediaFile) takenAt() time.Time {
+	if data, err := m.MetaData(); err == nil && data.TakenAt.Year() > 1000 {
+		return data.TakenAt
+	}
+
+	return time.Time{}
+}
+
+// stackBase returns the file base without the first matching suffix of edited versions, compared case-insensitively.
+func stackBase(base string, suffixes []string) string {
+	lower := strings.ToLower(base)
+
+	for _, s := range suffixes {
+		if s = strings.ToLower(s); len(lower) > len(s) && strings.HasSuffix(lower, s) {
+			return base[:len(base)-len(s)]
+		}
+	}
+
+	return base
+}
+
+// StackedFiles returns the related files in the same directory and sidecarBase plus edited versions, which are
+// named like the original with one of the suffixes, e.g. IMG_1234_edit.jpg for IMG_1234.CR2. Edited versions with
+// a different taken at time are not stacked. The preferred primary JPEG is returned as first file,
+// see config.StackPrimary for supported preferences.
+func (m *MediaFile) StackedFiles(stripSequence bool, sidecarBase string, suffixes []string, primary string) (result RelatedFiles, err error) {
+	result, err = m.RelatedSidecarFiles(stripSequence, sidecarBase)
+
+	if err != nil || len(suffixes) == 0 {
+		return result, err
+	}
+
+	base := stackBase(m.Base(stripSequence), suffixes)
+	matches, err := filepath.Glob(regexp.QuoteMeta(filepath.Join(m.Directory(), base)) + "*")
+
+	if err != nil {
+		return result, err
+	}
+
+	known := make(map[string]bool)
+
+	for _, f := range result.Files {
+		known[f.FileName()] = true
+	}
+
+	takenAt := m.takenAt()
+	stacked := false
+
+	for _, fileName := range matches {
+		if known[fileName] || stackBase(fs.Base(fileName, stripSequence), suffixes) != base {
+			continue
+		}
+
+		f, err := NewMediaFile(fileName)
+
+		// Skip video transcodes created for playback in browsers.
+		if err != nil || f.HasFileType(fs.TypeAvc) {
+			continue
+		}
+
+		if t := f.takenAt(); !takenAt.IsZero() && !t.IsZero() && (t.Sub(takenAt) > StackMaxInterval || takenAt.Sub(t) > StackMaxInterval) {
+			log.Debugf("index: %s was not taken at the same time as %s, not stacked", f.Base(false), m.Base(false))
+			continue
+		}
+
+		known[fileName] = true
+		stacked = true
+		result.Files = append(result.Files, f)
+
+		if result.Main == nil || f.IsRaw() || f.IsHEIF() && !result.Main.IsRaw() {
+			result.Main = f
+		}
+	}
+
+	if !stacked && base == m.Base(stripSequence) {
+		return result, nil
+	}
+
+	sort.Sort(result.Files)
+
+	m.SetStack(base)
+
+	for _, f := range result.Files {
+		f.SetStack(base)
+	}
+
+	rawBases := make(map[string]bool)
+
+	for _, f := range result.Files {
+		if f.IsRaw() {
+			rawBases[f.Base(stripSequence)] = true
+		}
+	}
+
+	// Files are sorted by name length, so the first preferred JPEG has the shortest name.
+	best := -1
+
+	for i, f := range result.Files {
+		if !f.IsJpeg() {
+			continue
+		}
+
+		preferred := false
+
+		if primary == config.StackRawFirst {
+			preferred = rawBases[f.Base(stripSequence)]
+		} else {
+			preferred = f.Base(stripSequence) != base
+		}
+
+		if best == -1 || preferred {
+			best = i
+		}
+
+		if preferred {
+			break
+		}
+	}
+
+	if best > 0 {
+		f := result.Files[best]
+		copy(result.Files[1:best+1], result.Files[:best])
+		result.Files[0] = f
+	}
+
+	// The main file is indexed first, so it must not be another JPEG than the preferred primary.
+	if best >= 0 && (result.Main == nil || result.Main.IsJpeg()) {
+		result.Main = result.Files[0]
+	}
+
+	return result, nil
+}
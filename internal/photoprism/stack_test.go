@@ -0,0 +1,245 @@
+package photoprism
+
+import (
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackBase(t *testing.T) {
+	suffixes := []string{"_edit", "-2", "~1"}
+
+	assert.Equal(t, "IMG_1234", stackBase("IMG_1234_edit", suffixes))
+	assert.Equal(t, "IMG_1234", stackBase("IMG_1234_EDIT", suffixes))
+	assert.Equal(t, "IMG_1234", stackBase("IMG_1234-2", suffixes))
+	assert.Equal(t, "IMG_1234", stackBase("IMG_1234~1", suffixes))
+	assert.Equal(t, "IMG_1234", stackBase("IMG_1234", suffixes))
+	assert.Equal(t, "IMG_12345", stackBase("IMG_12345", suffixes))
+	assert.Equal(t, "_edit", stackBase("_edit", suffixes))
+}
+
+func TestMediaFile_StackedFiles(t *testing.T) {
+	conf := config.TestConfig()
+
+	suffixes := []string{"_edit", "-2", "~1"}
+
+	// stackDir creates files in a temporary directory. Generated JPEG and fake RAW files don't have a taken at time.
+	stackDir := func(t *testing.T, names ...string) string {
+		dir, err := ioutil.TempDir("", "stack")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range names {
+			fileName := filepath.Join(dir, name)
+
+			if filepath.Ext(name) == ".jpg" {
+				err = imaging.Save(image.NewNRGBA(image.Rect(0, 0, 8, 8)), fileName)
+			} else {
+				err = ioutil.WriteFile(fileName, []byte(name), 0644)
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return dir
+	}
+
+	// copyExample copies an example file with exif data to the directory.
+	copyExample := func(t *testing.T, src, dir, name string) {
+		data, err := ioutil.ReadFile(filepath.Join(conf.ExamplesPath(), src))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(files MediaFiles) (result []string) {
+		for _, f := range files {
+			result = append(result, filepath.Base(f.FileName()))
+		}
+
+		return result
+	}
+
+	t.Run("raw and jpeg", func(t *testing.T) {
+		dir := stackDir(t, "IMG_1234.CR2", "IMG_1234.jpg", "IMG_1234_edit.jpg")
+		defer os.RemoveAll(dir)
+
+		m, err := NewMediaFile(filepath.Join(dir, "IMG_1234.CR2"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := m.StackedFiles(true, "", suffixes, config.StackJpegFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234_edit.jpg", "IMG_1234.CR2", "IMG_1234.jpg"}, names(related.Files))
+		assert.Equal(t, "IMG_1234.CR2", filepath.Base(related.Main.FileName()))
+
+		for _, f := range related.Files {
+			assert.Equal(t, "IMG_1234", f.Stack())
+		}
+
+		related, err = m.StackedFiles(true, "", suffixes, config.StackRawFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234.jpg", "IMG_1234.CR2", "IMG_1234_edit.jpg"}, names(related.Files))
+		assert.Equal(t, "IMG_1234.CR2", filepath.Base(related.Main.FileName()))
+	})
+	t.Run("edited version first", func(t *testing.T) {
+		dir := stackDir(t, "IMG_1234.CR2", "IMG_1234-2.jpg")
+		defer os.RemoveAll(dir)
+
+		m, err := NewMediaFile(filepath.Join(dir, "IMG_1234-2.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := m.StackedFiles(true, "", suffixes, config.StackJpegFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234-2.jpg", "IMG_1234.CR2"}, names(related.Files))
+		assert.Equal(t, "IMG_1234.CR2", filepath.Base(related.Main.FileName()))
+		assert.Equal(t, "IMG_1234", m.Stack())
+	})
+	t.Run("jpeg only", func(t *testing.T) {
+		dir := stackDir(t, "IMG_1234.jpg", "IMG_1234~1.jpg")
+		defer os.RemoveAll(dir)
+
+		m, err := NewMediaFile(filepath.Join(dir, "IMG_1234.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := m.StackedFiles(true, "", suffixes, config.StackJpegFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234~1.jpg", "IMG_1234.jpg"}, names(related.Files))
+		assert.Equal(t, "IMG_1234~1.jpg", filepath.Base(related.Main.FileName()))
+
+		related, err = m.StackedFiles(true, "", suffixes, config.StackRawFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234.jpg", "IMG_1234~1.jpg"}, names(related.Files))
+		assert.Equal(t, "IMG_1234.jpg", filepath.Base(related.Main.FileName()))
+	})
+	t.Run("burst sequence", func(t *testing.T) {
+		dir := stackDir(t, "BURST_0001.jpg", "BURST_0002.jpg", "BURST_0003.jpg")
+		defer os.RemoveAll(dir)
+
+		m, err := NewMediaFile(filepath.Join(dir, "BURST_0001.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := m.StackedFiles(true, "", suffixes, config.StackJpegFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"BURST_0001.jpg"}, names(related.Files))
+		assert.Equal(t, "", m.Stack())
+	})
+	t.Run("false positive base names", func(t *testing.T) {
+		dir := stackDir(t, "IMG_1234.jpg", "IMG_12345.jpg", "IMG_12345_edit.jpg", "IMG_1234_edited.jpg")
+		defer os.RemoveAll(dir)
+
+		m, err := NewMediaFile(filepath.Join(dir, "IMG_1234.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := m.StackedFiles(true, "", suffixes, config.StackJpegFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234.jpg"}, names(related.Files))
+		assert.Equal(t, "", m.Stack())
+
+		related, err = m.RelatedFiles(true)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234.jpg"}, names(related.Files))
+	})
+	t.Run("taken at different times", func(t *testing.T) {
+		dir := stackDir(t)
+		defer os.RemoveAll(dir)
+
+		copyExample(t, "ferriswheel_colorful.jpg", dir, "IMG_1234.jpg")
+		copyExample(t, "elephants.jpg", dir, "IMG_1234_edit.jpg")
+		copyExample(t, "ferriswheel_colorful.jpg", dir, "IMG_1234-2.jpg")
+
+		m, err := NewMediaFile(filepath.Join(dir, "IMG_1234.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := m.StackedFiles(true, "", suffixes, config.StackJpegFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234-2.jpg", "IMG_1234.jpg"}, names(related.Files))
+	})
+	t.Run("no suffixes", func(t *testing.T) {
+		dir := stackDir(t, "IMG_1234.CR2", "IMG_1234.jpg", "IMG_1234_edit.jpg")
+		defer os.RemoveAll(dir)
+
+		m, err := NewMediaFile(filepath.Join(dir, "IMG_1234.CR2"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		related, err := m.StackedFiles(true, "", nil, config.StackJpegFirst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"IMG_1234.CR2", "IMG_1234.jpg"}, names(related.Files))
+		assert.Equal(t, "", m.Stack())
+	})
+}
@@ -0,0 +1,75 @@
+package photoprism
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// takeoutEditedSuffixes contains the suffixes Google adds to the names of edited copies, which
+// share the sidecar file of the original.
+var takeoutEditedSuffixes = []string{"-edited", "-bearbeitet", "-modifié"}
+
+// takeoutCopyRegexp matches the counter of duplicate names, e.g. IMG_1234(1).jpg, which Google puts
+// after the file extension in sidecar names, e.g. IMG_1234.jpg(1).json.
+var takeoutCopyRegexp = regexp.MustCompile(`^(.+)(\(\d+\))$`)
+
+// IsTakeout returns true if this is a Google Takeout JSON sidecar file, like IMG_1234.jpg.json.
+func (m *MediaFile) IsTakeout() bool {
+	if !m.HasFileType(fs.TypeJson) {
+		return false
+	}
+
+	name := strings.TrimSuffix(filepath.Base(m.FileName()), filepath.Ext(m.FileName()))
+
+	if match := takeoutCopyRegexp.FindStringSubmatch(name); match != nil {
+		name = match[1]
+	}
+
+	return fs.GetFileType(name) != fs.TypeOther
+}
+
+// TakeoutName returns the name of the Google Takeout JSON sidecar file of this media file,
+// or an empty string if there is none.
+func (m *MediaFile) TakeoutName() string {
+	if m.IsSidecar() {
+		return ""
+	}
+
+	dir := filepath.Dir(m.FileName())
+	ext := filepath.Ext(m.FileName())
+	base := strings.TrimSuffix(filepath.Base(m.FileName()), ext)
+
+	names := []string{base + ext + ".json"}
+
+	if match := takeoutCopyRegexp.FindStringSubmatch(base); match != nil {
+		names = append(names, match[1]+ext+match[2]+".json")
+	}
+
+	for _, suffix := range takeoutEditedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			names = append(names, strings.TrimSuffix(base, suffix)+ext+".json")
+		}
+	}
+
+	for _, name := range names {
+		if fileName := filepath.Join(dir, name); fs.FileExists(fileName) {
+			return fileName
+		}
+	}
+
+	return ""
+}
+
+// SetTakeout sets the Google Takeout JSON sidecar file to read missing metadata from.
+// It must be set before the metadata is read for the first time.
+func (m *MediaFile) SetTakeout(fileName string) {
+	m.takeout = fileName
+}
+
+// Takeout returns the name of the Google Takeout JSON sidecar file set for this media file.
+func (m *MediaFile) Takeout() string {
+	return m.takeout
+}
@@ -0,0 +1,155 @@
+package photoprism
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaFile_IsTakeout(t *testing.T) {
+	t.Run("IMG_1234.jpg.json", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1234.jpg.json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, mediaFile.IsTakeout())
+	})
+
+	t.Run("IMG_1235.jpg(1).json", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1235.jpg(1).json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, mediaFile.IsTakeout())
+	})
+
+	t.Run("metadata.json", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/metadata.json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, mediaFile.IsTakeout())
+	})
+
+	t.Run("IMG_1234.jpg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1234.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, mediaFile.IsTakeout())
+	})
+}
+
+func TestMediaFile_TakeoutName(t *testing.T) {
+	t.Run("IMG_1234.jpg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1234.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "testdata/takeout/IMG_1234.jpg.json", mediaFile.TakeoutName())
+	})
+
+	t.Run("IMG_1234-edited.jpg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1234-edited.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "testdata/takeout/IMG_1234.jpg.json", mediaFile.TakeoutName())
+	})
+
+	t.Run("IMG_1235(1).jpg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1235(1).jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "testdata/takeout/IMG_1235.jpg(1).json", mediaFile.TakeoutName())
+	})
+
+	t.Run("sidecar", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1234.jpg.json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "", mediaFile.TakeoutName())
+	})
+
+	t.Run("none", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/avc.mp4")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "", mediaFile.TakeoutName())
+	})
+}
+
+func TestMediaFile_SetTakeout(t *testing.T) {
+	t.Run("IMG_1234-edited.jpg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1234-edited.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mediaFile.SetTakeout(mediaFile.TakeoutName())
+
+		data, err := mediaFile.MetaData()
+
+		// The fixture has no Exif data, so all values are read from the sidecar.
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC), data.TakenAt)
+		assert.Equal(t, "Brandenburg Gate at night", data.Description)
+		assert.Equal(t, "Jens Mander, Anna Mander", data.Keywords)
+		assert.Equal(t, float32(52.5163), data.Lat)
+		assert.Equal(t, float32(13.3777), data.Lng)
+		assert.Equal(t, time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC), mediaFile.DateCreated())
+	})
+
+	t.Run("IMG_1235(1).jpg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1235(1).jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mediaFile.SetTakeout(mediaFile.TakeoutName())
+
+		data, err := mediaFile.MetaData()
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2019, 7, 4, 20, 30, 15, 0, time.UTC), data.TakenAt)
+		assert.Equal(t, float32(0), data.Lat)
+		assert.Equal(t, float32(0), data.Lng)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		mediaFile, err := NewMediaFile("testdata/takeout/IMG_1234.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := mediaFile.MetaData()
+
+		assert.Error(t, err)
+		assert.True(t, data.TakenAt.IsZero())
+	})
+}
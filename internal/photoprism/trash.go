@@ -0,0 +1,211 @@
+package photoprism
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Trash moves deleted photos and their files to the trash, restores them, and permanently removes
+// them after the retention period. In read-only mode, files are not moved and only the index changes.
+type Trash struct {
+	conf *config.Config
+	db   *gorm.DB
+}
+
+// NewTrash returns a new trash worker and expects the config as argument.
+func NewTrash(conf *config.Config) *Trash {
+	return &Trash{
+		conf: conf,
+		db:   conf.Db(),
+	}
+}
+
+// Delete moves photos to the trash and returns their uuids. Photos are archived as well, so that
+// they don't show up in search results anymore.
+func (t *Trash) Delete(photoUUIDs []string) (result []string, err error) {
+	var photos []entity.Photo
+
+	if err := t.db.Unscoped().Where("photo_uuid IN (?) AND trashed_at IS NULL", photoUUIDs).Find(&photos).Error; err != nil {
+		return result, err
+	}
+
+	for _, photo := range photos {
+		if !t.conf.ReadOnly() {
+			if err := t.moveFiles(photo, false); err != nil {
+				return result, fmt.Errorf("trash: can't move files of %s (%s)", photo.PhotoUUID, err)
+			}
+		}
+
+		now := time.Now().UTC()
+
+		if err := t.db.Unscoped().Model(&entity.Photo{}).Where("id = ?", photo.ID).UpdateColumns(map[string]interface{}{
+			"trashed_at": now,
+			"deleted_at": gorm.Expr("COALESCE(deleted_at, ?)", now),
+		}).Error; err != nil {
+			return result, err
+		}
+
+		log.Infof("trash: deleted photo %s", photo.PhotoUUID)
+
+		result = append(result, photo.PhotoUUID)
+	}
+
+	return result, nil
+}
+
+// Restore moves photos and their files back from the trash and returns their uuids.
+func (t *Trash) Restore(photoUUIDs []string) (result []string, err error) {
+	var photos []entity.Photo
+
+	if err := t.db.Unscoped().Where("photo_uuid IN (?) AND trashed_at IS NOT NULL", photoUUIDs).Find(&photos).Error; err != nil {
+		return result, err
+	}
+
+	for _, photo := range photos {
+		if !t.conf.ReadOnly() {
+			if err := t.moveFiles(photo, true); err != nil {
+				return result, fmt.Errorf("trash: can't restore files of %s (%s)", photo.PhotoUUID, err)
+			}
+		}
+
+		if err := t.db.Unscoped().Model(&entity.Photo{}).Where("id = ?", photo.ID).UpdateColumns(map[string]interface{}{
+			"trashed_at": gorm.Expr("NULL"),
+			"deleted_at": gorm.Expr("NULL"),
+		}).Error; err != nil {
+			return result, err
+		}
+
+		log.Infof("trash: restored photo %s", photo.PhotoUUID)
+
+		result = append(result, photo.PhotoUUID)
+	}
+
+	return result, nil
+}
+
+// Cleanup permanently removes photos that are in the trash for longer than the retention period
+// and returns their uuids. In read-only mode, the original files can't be removed, so photos are
+// kept in the trash to prevent them from being indexed again.
+func (t *Trash) Cleanup() (result []string, err error) {
+	days := t.conf.TrashRetentionDays()
+
+	if days == 0 || t.conf.ReadOnly() {
+		return result, nil
+	}
+
+	if err := mutex.Worker.Start(); err != nil {
+		return result, err
+	}
+
+	defer mutex.Worker.Stop()
+
+	var photos []entity.Photo
+
+	expired := time.Now().UTC().AddDate(0, 0, -days)
+
+	if err := t.db.Unscoped().Where("trashed_at < ?", expired).Find(&photos).Error; err != nil {
+		return result, err
+	}
+
+	for _, photo := range photos {
+		var files []entity.File
+
+		if err := t.db.Unscoped().Where("photo_id = ?", photo.ID).Find(&files).Error; err != nil {
+			return result, err
+		}
+
+		for _, f := range files {
+			trashPath := t.conf.RootTrashPath(f.Root())
+
+			// Files of roots that aren't configured anymore can't be removed.
+			if trashPath == "" {
+				continue
+			}
+
+			fileName := filepath.Join(trashPath, f.FileName)
+
+			if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+				return result, err
+			}
+		}
+
+		if err := deletePhotoPermanently(t.db, photo); err != nil {
+			return result, err
+		}
+
+		log.Infof("trash: permanently removed photo %s", photo.PhotoUUID)
+
+		result = append(result, photo.PhotoUUID)
+	}
+
+	return result, nil
+}
+
+// moveFiles moves the files of a photo to the trash of their originals root, or back if restore is
+// true, keeping their relative paths. Files that were moved already are moved back if an error occurs.
+func (t *Trash) moveFiles(photo entity.Photo, restore bool) (err error) {
+	var files []entity.File
+
+	if err := t.db.Unscoped().Where("photo_id = ?", photo.ID).Find(&files).Error; err != nil {
+		return err
+	}
+
+	type move struct{ src, dest string }
+
+	var moved []move
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for _, m := range moved {
+			if err := os.Rename(m.dest, m.src); err != nil {
+				log.Errorf("trash: can't move %s back (%s)", m.dest, err)
+			}
+		}
+	}()
+
+	for _, f := range files {
+		rootPath := t.conf.OriginalsRootPath(f.Root())
+
+		if rootPath == "" {
+			return fmt.Errorf("originals root \"%s\" not found", f.Root())
+		}
+
+		src := filepath.Join(rootPath, f.FileName)
+		dest := filepath.Join(t.conf.RootTrashPath(f.Root()), f.FileName)
+
+		if restore {
+			src, dest = dest, src
+		}
+
+		if !fs.FileExists(src) {
+			continue
+		}
+
+		if fs.FileExists(dest) {
+			return fmt.Errorf("%s already exists", dest)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := os.Rename(src, dest); err != nil {
+			return err
+		}
+
+		moved = append(moved, move{src, dest})
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package photoprism
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrash(t *testing.T) {
+	db := config.TestConfig().Db()
+
+	dir, err := ioutil.TempDir("", "trash")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	originals := filepath.Join(dir, "originals")
+
+	// newTrash returns a trash worker for the temporary originals folder.
+	newTrash := func(flags map[string]string) *Trash {
+		flags["originals-path"] = originals
+		return &Trash{conf: fakeConvertConfig(flags, false), db: db}
+	}
+
+	// createPhoto adds a photo with a file in originals to the index.
+	createPhoto := func(fileName string) *entity.Photo {
+		photo := &entity.Photo{PhotoTitle: "Trash"}
+
+		if err := db.Create(photo).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.MkdirAll(filepath.Join(originals, filepath.Dir(fileName)), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(originals, fileName), []byte("jpeg"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		file := &entity.File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: fileName}
+
+		if err := db.Create(file).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		return photo
+	}
+
+	// find returns the photo including deleted ones.
+	find := func(photo *entity.Photo) (result entity.Photo, err error) {
+		err = db.Unscoped().Where("id = ?", photo.ID).First(&result).Error
+		return result, err
+	}
+
+	t.Run("restore after file move", func(t *testing.T) {
+		trash := newTrash(map[string]string{})
+		photo := createPhoto("2020/05/IMG_1234.jpg")
+
+		deleted, err := trash.Delete([]string{photo.PhotoUUID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{photo.PhotoUUID}, deleted)
+		assert.False(t, fs.FileExists(filepath.Join(originals, "2020/05/IMG_1234.jpg")))
+		assert.True(t, fs.FileExists(filepath.Join(originals, ".trash", "2020/05/IMG_1234.jpg")))
+
+		if m, err := find(photo); assert.NoError(t, err) {
+			assert.NotNil(t, m.TrashedAt)
+			assert.NotNil(t, m.DeletedAt)
+		}
+
+		// Photos in the trash can't be deleted twice.
+		deleted, err = trash.Delete([]string{photo.PhotoUUID})
+
+		assert.NoError(t, err)
+		assert.Empty(t, deleted)
+
+		restored, err := trash.Restore([]string{photo.PhotoUUID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{photo.PhotoUUID}, restored)
+		assert.True(t, fs.FileExists(filepath.Join(originals, "2020/05/IMG_1234.jpg")))
+		assert.False(t, fs.FileExists(filepath.Join(originals, ".trash", "2020/05/IMG_1234.jpg")))
+
+		if m, err := find(photo); assert.NoError(t, err) {
+			assert.Nil(t, m.TrashedAt)
+			assert.Nil(t, m.DeletedAt)
+		}
+	})
+	t.Run("restore conflict", func(t *testing.T) {
+		trash := newTrash(map[string]string{})
+		photo := createPhoto("2020/05/IMG_5678.jpg")
+
+		if _, err := trash.Delete([]string{photo.PhotoUUID}); err != nil {
+			t.Fatal(err)
+		}
+
+		// A different file with the same name was added in the meantime.
+		if err := ioutil.WriteFile(filepath.Join(originals, "2020/05/IMG_5678.jpg"), []byte("new"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := trash.Restore([]string{photo.PhotoUUID})
+
+		assert.Error(t, err)
+		assert.True(t, fs.FileExists(filepath.Join(originals, ".trash", "2020/05/IMG_5678.jpg")))
+
+		if m, err := find(photo); assert.NoError(t, err) {
+			assert.NotNil(t, m.TrashedAt)
+		}
+	})
+	t.Run("retention expiry", func(t *testing.T) {
+		trash := newTrash(map[string]string{"trash-retention-days": "30"})
+		expired := createPhoto("2019/IMG_0001.jpg")
+		recent := createPhoto("2019/IMG_0002.jpg")
+
+		if _, err := trash.Delete([]string{expired.PhotoUUID, recent.PhotoUUID}); err != nil {
+			t.Fatal(err)
+		}
+
+		db.Unscoped().Model(&entity.Photo{}).Where("id = ?", expired.ID).UpdateColumn("trashed_at", time.Now().UTC().AddDate(0, 0, -31))
+
+		removed, err := trash.Cleanup()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{expired.PhotoUUID}, removed)
+		assert.False(t, fs.FileExists(filepath.Join(originals, ".trash", "2019/IMG_0001.jpg")))
+		assert.True(t, fs.FileExists(filepath.Join(originals, ".trash", "2019/IMG_0002.jpg")))
+
+		_, err = find(expired)
+		assert.Error(t, err)
+
+		var count int
+
+		db.Unscoped().Model(&entity.File{}).Where("photo_id = ?", expired.ID).Count(&count)
+		assert.Equal(t, 0, count)
+
+		_, err = find(recent)
+		assert.NoError(t, err)
+	})
+	t.Run("read-only", func(t *testing.T) {
+		trash := newTrash(map[string]string{"read-only": "true", "trash-retention-days": "30"})
+		photo := createPhoto("2018/IMG_0003.jpg")
+
+		deleted, err := trash.Delete([]string{photo.PhotoUUID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{photo.PhotoUUID}, deleted)
+
+		// Only the index changes, files stay where they are.
+		assert.True(t, fs.FileExists(filepath.Join(originals, "2018/IMG_0003.jpg")))
+		assert.False(t, fs.FileExists(filepath.Join(originals, ".trash", "2018/IMG_0003.jpg")))
+
+		if m, err := find(photo); assert.NoError(t, err) {
+			assert.NotNil(t, m.TrashedAt)
+		}
+
+		// Expired photos are kept, as the original files can't be removed.
+		db.Unscoped().Model(&entity.Photo{}).Where("id = ?", photo.ID).UpdateColumn("trashed_at", time.Now().UTC().AddDate(0, 0, -31))
+
+		removed, err := trash.Cleanup()
+
+		assert.NoError(t, err)
+		assert.Empty(t, removed)
+
+		restored, err := trash.Restore([]string{photo.PhotoUUID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{photo.PhotoUUID}, restored)
+		assert.True(t, fs.FileExists(filepath.Join(originals, "2018/IMG_0003.jpg")))
+
+		if m, err := find(photo); assert.NoError(t, err) {
+			assert.Nil(t, m.TrashedAt)
+			assert.Nil(t, m.DeletedAt)
+		}
+	})
+}
+
+func TestImport_Trashed(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	photo := &entity.Photo{PhotoTitle: "Trashed"}
+
+	if err := db.Create(photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	file := entity.File{PhotoID: photo.ID}
+	imp := &Import{conf: conf}
+
+	assert.False(t, imp.Trashed(file))
+
+	db.Unscoped().Model(&entity.Photo{}).Where("id = ?", photo.ID).UpdateColumn("trashed_at", time.Now().UTC())
+
+	assert.True(t, imp.Trashed(file))
+}
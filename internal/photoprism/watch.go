@@ -0,0 +1,366 @@
+package photoprism
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Watcher watches the originals directory for changes and indexes changed directories
+// once no further events were received for the configured settle time.
+type Watcher struct {
+	root     string
+	settle   time.Duration
+	patterns []string
+	ignore   *fs.IgnoreList
+	index    func(subPath string) error
+	missing  func(subPath string) error
+	fsw      *fsnotify.Watcher
+	changed  map[string]time.Time
+	removed  map[string]time.Time
+	running  bool
+	failed   bool
+	stop     chan bool
+	mutex    sync.Mutex
+}
+
+// Watchers watch multiple originals roots for changes.
+type Watchers []*Watcher
+
+// NewWatchers returns a watcher for each originals root that uses the indexer to index changed directories.
+func NewWatchers(conf *config.Config, ind *Index) (result Watchers) {
+	for _, root := range conf.OriginalsRoots() {
+		result = append(result, NewWatcher(conf, ind, root))
+	}
+
+	return result
+}
+
+// Start starts all watchers and returns the last error, watchers that started successfully keep running.
+func (ws Watchers) Start() (err error) {
+	for _, w := range ws {
+		if startErr := w.Start(); startErr != nil {
+			err = startErr
+		}
+	}
+
+	return err
+}
+
+// Stop stops all watchers.
+func (ws Watchers) Stop() {
+	for _, w := range ws {
+		w.Stop()
+	}
+}
+
+// Failed returns true if changes of any root can't be detected, so that originals must be scanned periodically.
+func (ws Watchers) Failed() bool {
+	for _, w := range ws {
+		if w.Failed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewWatcher returns a new watcher for an originals root that uses the indexer to index changed directories.
+func NewWatcher(conf *config.Config, ind *Index, root config.Root) *Watcher {
+	index := func(subPath string) error {
+		// Try again later if another worker like the importer or a full index is running.
+		if mutex.Worker.Busy() {
+			return errors.New("indexer busy")
+		}
+
+		opt := IndexOptionsNone()
+		opt.Root = root.Name
+		opt.Path = subPath
+
+		ind.Start(opt)
+
+		return nil
+	}
+
+	missing := func(subPath string) error {
+		_, err := ind.MarkMissing(root.Name, subPath)
+		return err
+	}
+
+	return newWatcher(root.Path, conf.AutoIndexSettle(), conf.IgnorePatterns(), index, missing)
+}
+
+// newWatcher returns a new watcher with custom functions for indexing changed directories
+// and flagging removed files as missing, both expect a path relative to root.
+func newWatcher(root string, settle time.Duration, ignore []string, index, missing func(subPath string) error) *Watcher {
+	return &Watcher{
+		root:     filepath.Clean(root),
+		settle:   settle,
+		patterns: ignore,
+		ignore:   fs.NewIgnoreList(root, ignore),
+		index:    index,
+		missing:  missing,
+		changed:  make(map[string]time.Time),
+		removed:  make(map[string]time.Time),
+		stop:     make(chan bool, 1),
+	}
+}
+
+// Start adds watches for all directories below root and starts processing events. An error is
+// returned if file system events are not supported, for example on network file systems, or if
+// the max number of watches was reached. Callers should then fall back to periodic scanning.
+func (w *Watcher) Start() (err error) {
+	if networkFileSystem(w.root) {
+		return w.fail(fmt.Errorf("watch: %s is on a network file system, changes can't be detected", w.root))
+	}
+
+	if w.fsw, err = fsnotify.NewWatcher(); err != nil {
+		return w.fail(fmt.Errorf("watch: %s", err))
+	}
+
+	if err := w.addDir(w.root); err != nil {
+		w.fsw.Close()
+		return w.fail(err)
+	}
+
+	log.Infof("watch: watching %s for changes", w.root)
+
+	go w.run()
+
+	return nil
+}
+
+// Stop stops watching for changes.
+func (w *Watcher) Stop() {
+	select {
+	case w.stop <- true:
+	default:
+	}
+}
+
+// Failed returns true if changes can't be detected, so that originals must be scanned periodically.
+func (w *Watcher) Failed() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.failed
+}
+
+// fail flags the watcher as failed and returns the error.
+func (w *Watcher) fail(err error) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.failed = true
+
+	return err
+}
+
+// addDir adds watches for a directory and all its subdirectories, except ignored ones.
+func (w *Watcher) addDir(dir string) error {
+	return filepath.Walk(dir, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+
+		if w.ignore.Ignore(fileName, true) {
+			return filepath.SkipDir
+		}
+
+		if err := w.fsw.Add(fileName); err != nil {
+			if errors.Is(err, syscall.ENOSPC) {
+				return fmt.Errorf("watch: max number of watches reached, increase fs.inotify.max_user_watches (%s)", err)
+			}
+
+			log.Warnf("watch: %s (%s)", err, fileName)
+		}
+
+		return nil
+	})
+}
+
+// run processes events until the watcher is stopped.
+func (w *Watcher) run() {
+	ticker := time.NewTicker(w.tick())
+
+	defer func() {
+		ticker.Stop()
+		w.fsw.Close()
+	}()
+
+	for {
+		select {
+		case <-w.stop:
+			log.Info("watch: stopped watching for changes")
+			return
+		case ev := <-w.fsw.Events:
+			if err := w.event(ev); err != nil {
+				log.Errorf("%s, falling back to periodic scanning", w.fail(err))
+				return
+			}
+		case err := <-w.fsw.Errors:
+			if err == fsnotify.ErrEventOverflow {
+				// Events were lost, so the whole directory tree must be indexed again.
+				log.Warnf("watch: %s, indexing %s", err, w.root)
+				w.touch(w.changed, w.root)
+			} else {
+				log.Errorf("watch: %s", err)
+			}
+		case <-ticker.C:
+			w.flush(time.Now())
+		}
+	}
+}
+
+// tick returns the interval for checking if changed directories have settled.
+func (w *Watcher) tick() time.Duration {
+	if d := w.settle / 4; d > 50*time.Millisecond {
+		return d
+	}
+
+	return 50 * time.Millisecond
+}
+
+// event remembers the directory affected by a file system event.
+func (w *Watcher) event(ev fsnotify.Event) error {
+	// Events of watches removed before may not have a name.
+	if ev.Name == "" {
+		return nil
+	}
+
+	info, statErr := os.Stat(ev.Name)
+	isDir := statErr == nil && info.IsDir()
+
+	if w.ignore.Ignore(ev.Name, isDir) {
+		return nil
+	}
+
+	if filepath.Base(ev.Name) == fs.IgnoreFileName {
+		// Ignore lists cache patterns, so changes require a new list.
+		w.ignore = fs.NewIgnoreList(w.root, w.patterns)
+	}
+
+	switch {
+	case ev.Op&fsnotify.Create != 0 && isDir:
+		// New directories, including renamed ones, need to be watched and indexed.
+		if err := w.addDir(ev.Name); err != nil {
+			return err
+		}
+
+		w.touch(w.changed, ev.Name)
+	case ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		// Watches of removed or renamed directories are removed, the file names are flagged
+		// as missing unless they exist again once settled.
+		_ = w.fsw.Remove(ev.Name)
+		w.touch(w.removed, ev.Name)
+		w.touch(w.changed, filepath.Dir(ev.Name))
+	case ev.Op&(fsnotify.Create|fsnotify.Write) != 0:
+		w.touch(w.changed, filepath.Dir(ev.Name))
+	}
+
+	return nil
+}
+
+// touch sets the last event time of a path.
+func (w *Watcher) touch(paths map[string]time.Time, fileName string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	paths[fileName] = time.Now()
+}
+
+// settled removes and returns all paths without events since the settle time, sorted by name.
+func (w *Watcher) settled(paths map[string]time.Time, now time.Time) (result []string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for fileName, t := range paths {
+		if now.Sub(t) >= w.settle {
+			result = append(result, fileName)
+			delete(paths, fileName)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// flush processes removed paths and indexes changed directories that have settled. Directories
+// are skipped if a parent directory is indexed as well.
+func (w *Watcher) flush(now time.Time) {
+	w.mutex.Lock()
+
+	if w.running {
+		w.mutex.Unlock()
+		return
+	}
+
+	w.mutex.Unlock()
+
+	removed := w.settled(w.removed, now)
+	changed := w.settled(w.changed, now)
+
+	if len(removed) == 0 && len(changed) == 0 {
+		return
+	}
+
+	w.mutex.Lock()
+	w.running = true
+	w.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			w.mutex.Lock()
+			w.running = false
+			w.mutex.Unlock()
+		}()
+
+		for _, fileName := range removed {
+			if err := w.missing(w.rel(fileName)); err != nil {
+				log.Errorf("watch: %s", err)
+			}
+		}
+
+		var parent string
+
+		for _, dir := range changed {
+			if parent != "" && (dir == parent || strings.HasPrefix(dir, parent+string(os.PathSeparator))) {
+				continue
+			}
+
+			parent = dir
+
+			if !fs.PathExists(dir) {
+				continue
+			}
+
+			log.Infof("watch: indexing changes in %s", dir)
+
+			if err := w.index(w.rel(dir)); err != nil {
+				log.Debugf("watch: %s, retrying %s", err, dir)
+				w.touch(w.changed, dir)
+			}
+		}
+	}()
+}
+
+// rel returns a path relative to root.
+func (w *Watcher) rel(fileName string) string {
+	if rel, err := filepath.Rel(w.root, fileName); err == nil && rel != "." {
+		return rel
+	}
+
+	return ""
+}
@@ -0,0 +1,29 @@
+package photoprism
+
+import (
+	"syscall"
+)
+
+// Magic numbers of network file systems that don't report remote changes to inotify. They are
+// 32 bits wide, while Statfs_t.Type is signed and its size depends on the architecture.
+var networkFileSystems = map[uint32]string{
+	0x6969:     "nfs",
+	0x517B:     "smb",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x01021997: "9p",
+	0x65735546: "fuse",
+}
+
+// networkFileSystem returns true if path is on a network file system.
+func networkFileSystem(path string) bool {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return false
+	}
+
+	_, ok := networkFileSystems[uint32(stat.Type)]
+
+	return ok
+}
@@ -0,0 +1,8 @@
+// +build !linux
+
+package photoprism
+
+// networkFileSystem returns true if path is on a network file system, which is only detected on Linux.
+func networkFileSystem(path string) bool {
+	return false
+}
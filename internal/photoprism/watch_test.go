@@ -0,0 +1,185 @@
+package photoprism
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// watchRecorder records the paths passed to the index and missing functions of a watcher.
+type watchRecorder struct {
+	indexed []string
+	missing []string
+	mutex   sync.Mutex
+}
+
+func (r *watchRecorder) index(subPath string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.indexed = append(r.indexed, filepath.ToSlash(subPath))
+
+	return nil
+}
+
+func (r *watchRecorder) markMissing(subPath string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.missing = append(r.missing, filepath.ToSlash(subPath))
+
+	return nil
+}
+
+// wait returns the recorded paths once no further calls were recorded for a while.
+func (r *watchRecorder) wait(t *testing.T) (indexed, missing []string) {
+	count := -1
+
+	for i := 0; i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+
+		r.mutex.Lock()
+		n := len(r.indexed) + len(r.missing)
+		r.mutex.Unlock()
+
+		if n > 0 && n == count {
+			break
+		}
+
+		count = n
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	indexed, missing = r.indexed, r.missing
+	r.indexed, r.missing = nil, nil
+
+	sort.Strings(indexed)
+	sort.Strings(missing)
+
+	return indexed, missing
+}
+
+func TestWatcher(t *testing.T) {
+	root, err := ioutil.TempDir("", "watch")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"2020/Vacation", "2020/@eaDir", "2019"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile := func(name string) {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte("jpeg"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("2019/IMG_1.jpg")
+	writeFile("2020/Vacation/IMG_1.jpg")
+
+	r := &watchRecorder{}
+	w := newWatcher(root, 200*time.Millisecond, []string{"@eaDir/", ".*"}, r.index, r.markMissing)
+
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer w.Stop()
+
+	t.Run("new files", func(t *testing.T) {
+		writeFile("2020/Vacation/IMG_2.jpg")
+		writeFile("2020/Vacation/IMG_3.jpg")
+		writeFile("2019/IMG_2.jpg")
+
+		indexed, missing := r.wait(t)
+
+		assert.Equal(t, []string{"2019", "2020/Vacation"}, indexed)
+		assert.Empty(t, missing)
+	})
+	t.Run("ignored", func(t *testing.T) {
+		writeFile("2020/@eaDir/IMG_1.jpg")
+		writeFile("2020/.IMG_1.jpg")
+		writeFile("2019/IMG_3.jpg")
+
+		indexed, _ := r.wait(t)
+
+		assert.Equal(t, []string{"2019"}, indexed)
+	})
+	t.Run("new folder", func(t *testing.T) {
+		if err := os.MkdirAll(filepath.Join(root, "2021/Trip"), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile("2021/Trip/IMG_1.jpg")
+
+		indexed, _ := r.wait(t)
+
+		assert.Equal(t, []string{"2021"}, indexed)
+
+		// Files in new folders are watched as well.
+		writeFile("2021/Trip/IMG_2.jpg")
+
+		indexed, _ = r.wait(t)
+
+		assert.Equal(t, []string{"2021/Trip"}, indexed)
+	})
+	t.Run("deleted", func(t *testing.T) {
+		if err := os.Remove(filepath.Join(root, "2019/IMG_1.jpg")); err != nil {
+			t.Fatal(err)
+		}
+
+		indexed, missing := r.wait(t)
+
+		assert.Equal(t, []string{"2019"}, indexed)
+		assert.Equal(t, []string{"2019/IMG_1.jpg"}, missing)
+	})
+	t.Run("renamed", func(t *testing.T) {
+		if err := os.Rename(filepath.Join(root, "2020/Vacation"), filepath.Join(root, "2020/Italy")); err != nil {
+			t.Fatal(err)
+		}
+
+		indexed, missing := r.wait(t)
+
+		assert.Equal(t, []string{"2020"}, indexed)
+		assert.Equal(t, []string{"2020/Vacation"}, missing)
+
+		writeFile("2020/Italy/IMG_4.jpg")
+
+		indexed, _ = r.wait(t)
+
+		assert.Equal(t, []string{"2020/Italy"}, indexed)
+	})
+}
+
+func TestWatcher_flush(t *testing.T) {
+	r := &watchRecorder{}
+	w := newWatcher("/photos", time.Second, nil, r.index, r.markMissing)
+	now := time.Now()
+
+	w.changed["/photos/2020"] = now.Add(-2 * time.Second)
+	w.changed["/photos/2020/Vacation"] = now.Add(-2 * time.Second)
+	w.changed["/photos/2019"] = now
+
+	t.Run("settled", func(t *testing.T) {
+		assert.Equal(t, []string{"/photos/2020", "/photos/2020/Vacation"}, w.settled(w.changed, now))
+		assert.Len(t, w.changed, 1)
+	})
+	t.Run("rel", func(t *testing.T) {
+		assert.Equal(t, "2020/Vacation", w.rel("/photos/2020/Vacation"))
+		assert.Equal(t, "", w.rel("/photos"))
+	})
+}
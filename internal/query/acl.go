@@ -0,0 +1,177 @@
+package query
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+// RoleAdmin is the role of users with unrestricted access.
+const RoleAdmin = entity.RoleAdmin
+
+// ReviewQuality is the minimum quality of photos that don't need to be reviewed.
+const ReviewQuality = entity.ReviewQuality
+
+// Viewer represents the user or pseudo-role that album and photo queries are restricted to.
+// Public is true for anonymous visitors in public mode, who may not see private photos and photos in review.
+type Viewer struct {
+	ID     uint
+	User   string
+	Role   string
+	Public bool
+}
+
+// LinkViewer is the viewer of sharing links. Visitors may see the shared album regardless of its visibility,
+// but no private photos and photos in review.
+var LinkViewer = Viewer{Role: RoleAdmin, Public: true}
+
+// Admin returns true if the viewer has unrestricted access.
+func (v Viewer) Admin() bool {
+	return v.Role == RoleAdmin
+}
+
+// As returns a copy of the query that only finds albums and photos visible to the viewer.
+// Queries created with New() are not restricted, e.g. for indexing and background workers.
+func (q *Query) As(v Viewer) *Query {
+	return &Query{db: q.db, viewer: &v}
+}
+
+// albumVisibleSql matches albums with the given table alias that are visible to a role and user.
+func albumVisibleSql(alias string) string {
+	return "(" + alias + ".album_visibility IN ('', '" + entity.VisibleEveryone + "') OR EXISTS (SELECT 1 FROM albums_permissions ap WHERE ap.album_uuid = " + alias + ".album_uuid AND (" +
+		"(" + alias + ".album_visibility = '" + entity.VisibleRoles + "' AND ap.perm_type = '" + entity.PermRole + "' AND ap.perm_name = ?) OR " +
+		"(" + alias + ".album_visibility = '" + entity.VisibleUsers + "' AND ap.perm_type = '" + entity.PermUser + "' AND ap.perm_name = ?))))"
+}
+
+// AlbumsVisible returns a scope that limits results from the albums table to albums visible to the viewer.
+// This is the only place album permissions are evaluated, so all queries and handlers should use it.
+func AlbumsVisible(v *Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if v == nil || v.Admin() {
+			return db
+		}
+
+		return db.Where(albumVisibleSql("albums"), v.Role, v.User)
+	}
+}
+
+// PhotosPublic returns a scope that hides private photos and photos in review from the photos table.
+func PhotosPublic() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("photos.photo_private = 0 AND photos.photo_quality >= ?", ReviewQuality)
+	}
+}
+
+// PhotosVisible returns a scope that hides photos from the photos table if they are only
+// part of albums not visible to the viewer. Photos are part of manual albums they were added to
+// and of the folder album of their path. Photos in no album or in at least one visible album
+// are not affected. Smart albums select photos with a search filter, so they can only be viewed
+// if visible, see searchAlbum. Private photos and photos in review are hidden from public viewers.
+func PhotosVisible(v *Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if v == nil {
+			return db
+		}
+
+		if v.Public {
+			db = db.Scopes(PhotosPublic())
+		}
+
+		if v.Admin() {
+			return db
+		}
+
+		visible := "CASE WHEN " + albumVisibleSql("a") + " THEN 1 ELSE 0 END AS visible"
+
+		return db.Where("photos.photo_uuid NOT IN (SELECT m.photo_uuid FROM ("+
+			"SELECT pa.photo_uuid, "+visible+" FROM photos_albums pa "+
+			"JOIN albums a ON a.album_uuid = pa.album_uuid AND a.deleted_at IS NULL "+
+			"UNION ALL SELECT p.photo_uuid, "+visible+" FROM albums a "+
+			"JOIN photos p ON p.photo_root = a.album_root AND p.photo_path = a.album_path "+
+			"WHERE a.album_type = '"+entity.AlbumFolder+"' AND a.deleted_at IS NULL"+
+			") m GROUP BY m.photo_uuid HAVING SUM(m.visible) = 0)", v.Role, v.User, v.Role, v.User)
+	}
+}
+
+// FilesVisible returns a scope that limits results from the files table to files of photos visible to the viewer.
+func FilesVisible(v *Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if v == nil || v.Admin() && !v.Public {
+			return db
+		}
+
+		photos := db.New().Table("photos").Select("photos.id").Scopes(PhotosVisible(v)).QueryExpr()
+
+		return db.Where("files.photo_id IN (?)", photos)
+	}
+}
+
+// AlbumVisible returns true if the album exists and is visible to the viewer.
+func (q *Query) AlbumVisible(albumUUID string) bool {
+	var count int
+
+	q.db.Table("albums").
+		Where("albums.album_uuid = ? AND albums.deleted_at IS NULL", albumUUID).
+		Scopes(AlbumsVisible(q.viewer)).
+		Count(&count)
+
+	return count > 0
+}
+
+// PhotosVisibleCount returns the number of photos with the given UUIDs that are visible to the viewer.
+func (q *Query) PhotosVisibleCount(photoUUIDs []string) int {
+	var count int
+
+	q.db.Table("photos").
+		Where("photos.photo_uuid IN (?) AND photos.deleted_at IS NULL", photoUUIDs).
+		Scopes(PhotosVisible(q.viewer)).
+		Count(&count)
+
+	return count
+}
+
+// PhotoShared returns true if the photo was shared, either by itself or as part of an album, and is
+// visible to the viewer. Smart and folder albums contain the photos matching their filter.
+func (q *Query) PhotoShared(shareUUID, photoUUID string) bool {
+	if shareUUID == "" || photoUUID == "" {
+		return false
+	}
+
+	if shareUUID == photoUUID {
+		return q.PhotosVisibleCount([]string{photoUUID}) > 0
+	}
+
+	f := form.PhotoSearch{Album: shareUUID}
+	s, err := q.photoSearch(&f)
+
+	if err != nil {
+		return false
+	}
+
+	count, err := q.total(s.Where("photos.photo_uuid = ?", photoUUID))
+
+	return err == nil && count > 0
+}
+
+// FileVisible returns true if a file isn't indexed or belongs to a photo visible to the viewer.
+func (q *Query) FileVisible(fileName string) bool {
+	if q.viewer == nil || q.viewer.Admin() && !q.viewer.Public {
+		return true
+	}
+
+	var total, visible int
+
+	q.db.Table("files").Where("files.file_name = ? AND files.deleted_at IS NULL", fileName).Count(&total)
+
+	if total == 0 {
+		return true
+	}
+
+	q.db.Table("files").
+		Joins("JOIN photos ON photos.id = files.photo_id").
+		Where("files.file_name = ? AND files.deleted_at IS NULL", fileName).
+		Scopes(PhotosVisible(q.viewer)).
+		Count(&visible)
+
+	return visible > 0
+}
@@ -0,0 +1,266 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewer_Admin(t *testing.T) {
+	assert.True(t, Viewer{Role: RoleAdmin}.Admin())
+	assert.False(t, Viewer{Role: "guest"}.Admin())
+	assert.False(t, Viewer{}.Admin())
+}
+
+func TestAlbumsVisible(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	restricted := entity.NewAlbum("Restricted Album")
+	public := entity.NewAlbum("Public Album")
+
+	if err := db.Create(restricted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Create(public).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		db.Unscoped().Where("album_uuid IN (?)", []string{restricted.AlbumUUID, public.AlbumUUID}).Delete(&entity.PhotoAlbum{})
+		db.Unscoped().Where("album_uuid IN (?)", []string{restricted.AlbumUUID, public.AlbumUUID}).Delete(&entity.AlbumPermission{})
+		db.Unscoped().Delete(restricted)
+		db.Unscoped().Delete(public)
+	}()
+
+	if err := restricted.SetAccess(db, entity.AlbumAccess{Visibility: entity.VisibleRoles, Roles: []string{"family"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entity.NewPhotoAlbum("659", restricted.AlbumUUID).FirstOrCreate(db)
+
+	admin := New(db).As(Viewer{User: "admin", Role: RoleAdmin})
+	family := New(db).As(Viewer{User: "jane", Role: "family"})
+	guest := New(db).As(Viewer{Role: "guest"})
+
+	t.Run("unrestricted query", func(t *testing.T) {
+		assert.True(t, New(db).AlbumVisible(restricted.AlbumUUID))
+		assert.Equal(t, 1, New(db).PhotosVisibleCount([]string{"659"}))
+	})
+	t.Run("admin", func(t *testing.T) {
+		assert.True(t, admin.AlbumVisible(restricted.AlbumUUID))
+		assert.Equal(t, 1, admin.PhotosVisibleCount([]string{"659"}))
+	})
+	t.Run("role granted", func(t *testing.T) {
+		assert.True(t, family.AlbumVisible(restricted.AlbumUUID))
+		assert.True(t, family.AlbumVisible(public.AlbumUUID))
+		assert.Equal(t, 1, family.PhotosVisibleCount([]string{"659"}))
+
+		_, err := family.AlbumByUUID(restricted.AlbumUUID)
+		assert.Nil(t, err)
+	})
+	t.Run("role not granted", func(t *testing.T) {
+		assert.False(t, guest.AlbumVisible(restricted.AlbumUUID))
+		assert.True(t, guest.AlbumVisible(public.AlbumUUID))
+		assert.Equal(t, 0, guest.PhotosVisibleCount([]string{"659"}))
+
+		_, err := guest.AlbumByUUID(restricted.AlbumUUID)
+		assert.Error(t, err)
+
+		_, err = guest.PhotoByUUID("659")
+		assert.Error(t, err)
+
+		_, err = guest.AlbumThumbByUUID(restricted.AlbumUUID)
+		assert.Error(t, err)
+
+		albums, err := guest.Albums(form.AlbumSearch{Query: "Restricted Album"})
+		assert.Nil(t, err)
+		assert.Len(t, albums, 0)
+
+		photos, _, err := guest.Photos(form.PhotoSearch{ID: "659"})
+		assert.Nil(t, err)
+		assert.Len(t, photos, 0)
+	})
+	t.Run("user granted", func(t *testing.T) {
+		if err := restricted.SetAccess(db, entity.AlbumAccess{Visibility: entity.VisibleUsers, Users: []string{"jane"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, family.AlbumVisible(restricted.AlbumUUID))
+		assert.False(t, New(db).As(Viewer{User: "john", Role: "family"}).AlbumVisible(restricted.AlbumUUID))
+	})
+	t.Run("also in public album", func(t *testing.T) {
+		entity.NewPhotoAlbum("659", public.AlbumUUID).FirstOrCreate(db)
+
+		assert.False(t, guest.AlbumVisible(restricted.AlbumUUID))
+		assert.Equal(t, 1, guest.PhotosVisibleCount([]string{"659"}))
+	})
+	t.Run("everyone", func(t *testing.T) {
+		if err := restricted.SetAccess(db, entity.AlbumAccess{Visibility: entity.VisibleEveryone}); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, guest.AlbumVisible(restricted.AlbumUUID))
+	})
+}
+
+func TestPhotosVisible_Folder(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	photo := &entity.Photo{PhotoTitle: "AclFolderTest", PhotoRoot: entity.RootDefault, PhotoPath: "acl-folder-test",
+		PhotoName: "lake", PhotoQuality: 3, CameraID: entity.UnknownCamera.ID, LensID: entity.UnknownLens.ID}
+
+	if err := db.Create(photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	file := &entity.File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "acl-folder-test/lake.jpg", FileType: "jpg", FilePrimary: true}
+
+	if err := db.Create(file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	folder := entity.NewAlbum("ACL Folder")
+	smart := entity.NewAlbum("ACL Smart")
+
+	if err := folder.SetType(entity.AlbumFolder, "acl-folder-test", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := smart.SetType(entity.AlbumSmart, "", "title:aclfoldertest"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, album := range []*entity.Album{folder, smart} {
+		if err := db.Create(album).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if err := album.SetAccess(db, entity.AlbumAccess{Visibility: entity.VisibleRoles, Roles: []string{"family"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func() {
+		db.Unscoped().Where("album_uuid IN (?)", []string{folder.AlbumUUID, smart.AlbumUUID}).Delete(&entity.AlbumPermission{})
+		db.Unscoped().Delete(folder)
+		db.Unscoped().Delete(smart)
+		db.Unscoped().Delete(file)
+		db.Unscoped().Delete(photo)
+	}()
+
+	family := New(db).As(Viewer{User: "jane", Role: "family"})
+	guest := New(db).As(Viewer{Role: "guest"})
+
+	t.Run("role granted", func(t *testing.T) {
+		assert.Equal(t, 1, family.PhotosVisibleCount([]string{photo.PhotoUUID}))
+
+		for _, album := range []*entity.Album{folder, smart} {
+			photos, _, err := family.Photos(form.PhotoSearch{Album: album.AlbumUUID, Count: 10})
+
+			assert.NoError(t, err)
+			assert.Len(t, photos, 1)
+		}
+	})
+	t.Run("role not granted", func(t *testing.T) {
+		assert.Equal(t, 0, guest.PhotosVisibleCount([]string{photo.PhotoUUID}))
+
+		for _, album := range []*entity.Album{folder, smart} {
+			photos, _, err := guest.Photos(form.PhotoSearch{Album: album.AlbumUUID, Count: 10})
+
+			assert.NoError(t, err)
+			assert.Len(t, photos, 0)
+		}
+	})
+	t.Run("everyone", func(t *testing.T) {
+		if err := folder.SetAccess(db, entity.AlbumAccess{Visibility: entity.VisibleEveryone}); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, guest.PhotosVisibleCount([]string{photo.PhotoUUID}))
+
+		photos, _, err := guest.Photos(form.PhotoSearch{Album: smart.AlbumUUID, Count: 10})
+
+		assert.NoError(t, err)
+		assert.Len(t, photos, 0)
+	})
+}
+
+func TestQuery_FileVisible(t *testing.T) {
+	conf := config.TestConfig()
+
+	guest := New(conf.Db()).As(Viewer{Role: "guest"})
+
+	assert.True(t, guest.FileVisible("not-indexed.jpg"))
+	assert.True(t, guest.FileVisible("exampleFileName.jpg"))
+}
+
+func TestFilesVisible(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	photos := map[string]*entity.Photo{
+		"pt_acl_private": {PhotoTitle: "Private", CameraID: 2, LensID: 2, PhotoQuality: 3, PhotoPrivate: true},
+		"pt_acl_review":  {PhotoTitle: "Review", CameraID: 2, LensID: 2, PhotoQuality: 1},
+	}
+
+	for fileHash, p := range photos {
+		if err := db.Create(p).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		file := entity.File{PhotoID: p.ID, PhotoUUID: p.PhotoUUID, FileName: fileHash + ".jpg", FileHash: fileHash, FileType: "jpg", FilePrimary: true}
+
+		if err := db.Create(&file).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		defer func(id uint) {
+			db.Unscoped().Where("photo_id = ?", id).Delete(&entity.File{})
+			db.Unscoped().Delete(&entity.Photo{ID: id})
+		}(p.ID)
+	}
+
+	public := New(db).As(Viewer{Role: "guest", Public: true})
+	admin := New(db).As(Viewer{User: "admin", Role: RoleAdmin})
+
+	t.Run("public viewer", func(t *testing.T) {
+		_, err := public.FileByHash("pt_acl_private")
+		assert.Error(t, err)
+
+		_, err = public.FileByHash("pt_acl_review")
+		assert.Error(t, err)
+
+		_, err = public.PhotoByUUID(photos["pt_acl_private"].PhotoUUID)
+		assert.Error(t, err)
+
+		f, err := public.FileByHash("123xxx")
+		assert.Nil(t, err)
+		assert.Equal(t, "exampleFileName.jpg", f.FileName)
+
+		assert.False(t, public.FileVisible("pt_acl_private.jpg"))
+	})
+	t.Run("link viewer", func(t *testing.T) {
+		_, err := New(db).As(LinkViewer).FileByHash("pt_acl_private")
+		assert.Error(t, err)
+	})
+	t.Run("admin", func(t *testing.T) {
+		f, err := admin.FileByHash("pt_acl_private")
+		assert.Nil(t, err)
+		assert.Equal(t, "pt_acl_private.jpg", f.FileName)
+
+		_, err = admin.FileByHash("pt_acl_review")
+		assert.Nil(t, err)
+
+		assert.True(t, admin.FileVisible("pt_acl_private.jpg"))
+	})
+	t.Run("unrestricted query", func(t *testing.T) {
+		_, err := New(db).FileByHash("pt_acl_private")
+		assert.Nil(t, err)
+	})
+}
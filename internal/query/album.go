@@ -5,9 +5,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/pkg/capture"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
 // AlbumResult contains found albums
@@ -18,6 +20,10 @@ type AlbumResult struct {
 	DeletedAt        time.Time
 	AlbumUUID        string
 	AlbumSlug        string
+	AlbumType        string
+	AlbumRoot        string
+	AlbumPath        string
+	AlbumFilter      string
 	AlbumName        string
 	AlbumDescription string
 	AlbumNotes       string
@@ -25,24 +31,36 @@ type AlbumResult struct {
 	AlbumTemplate    string
 	AlbumCount       int
 	AlbumFavorite    bool
+	AlbumVisibility  string
 	LinkCount        int
 }
 
 // AlbumByUUID returns a Album based on the UUID.
 func (q *Query) AlbumByUUID(albumUUID string) (album entity.Album, err error) {
-	if err := q.db.Where("album_uuid = ?", albumUUID).Preload("Links").First(&album).Error; err != nil {
+	if err := q.db.Where("album_uuid = ?", albumUUID).Scopes(AlbumsVisible(q.viewer)).Preload("Links").First(&album).Error; err != nil {
 		return album, err
 	}
 
 	return album, nil
 }
 
-// AlbumThumbByUUID returns a album preview file based on the uuid.
+// AlbumThumbByUUID returns a album preview file based on the uuid, if the album is visible to the viewer.
 func (q *Query) AlbumThumbByUUID(albumUUID string) (file entity.File, err error) {
+	var album entity.Album
+
+	if err := q.db.Where("album_uuid = ?", albumUUID).Scopes(AlbumsVisible(q.viewer)).First(&album).Error; err != nil {
+		return file, err
+	}
+
+	if !album.IsManual() {
+		return q.albumSearchThumb(albumUUID)
+	}
+
 	if err := q.db.Where("files.file_primary = 1 AND files.deleted_at IS NULL").
 		Joins("JOIN albums ON albums.album_uuid = ?", albumUUID).
 		Joins("JOIN photos_albums pa ON pa.album_uuid = albums.album_uuid AND pa.photo_uuid = files.photo_uuid").
 		Joins("JOIN photos ON photos.id = files.photo_id AND photos.photo_private = 0 AND photos.deleted_at IS NULL").
+		Scopes(FilesVisible(q.viewer)).
 		Order("photos.photo_quality DESC, photos.taken_at DESC").
 		First(&file).Error; err != nil {
 		return file, err
@@ -51,15 +69,174 @@ func (q *Query) AlbumThumbByUUID(albumUUID string) (file entity.File, err error)
 	return file, nil
 }
 
+// albumSearchThumb returns the preview file of a folder or smart album, which are evaluated like a photo search.
+func (q *Query) albumSearchThumb(albumUUID string) (file entity.File, err error) {
+	photos, _, err := q.Photos(form.PhotoSearch{Album: albumUUID, Public: true, Order: entity.SortOrderRelevance, Count: 1})
+
+	if err != nil {
+		return file, err
+	} else if len(photos) == 0 {
+		return file, gorm.ErrRecordNotFound
+	}
+
+	err = q.db.Where("id = ?", photos[0].FileID).First(&file).Error
+
+	return file, err
+}
+
+// searchAlbum returns the album of a photo search. The search filter of smart albums is merged
+// into the form, so that they are evaluated when viewed and always contain the current photos.
+// An album without id is returned if the album is unknown or not visible to the viewer.
+func (q *Query) searchAlbum(f *form.PhotoSearch) (album entity.Album, err error) {
+	if f.Album == "" {
+		return album, nil
+	}
+
+	if err := q.db.Where("album_uuid = ?", f.Album).Scopes(AlbumsVisible(q.viewer)).First(&album).Error; err != nil {
+		return entity.Album{AlbumUUID: f.Album, AlbumType: entity.AlbumManual}, nil
+	}
+
+	if !album.IsSmart() {
+		return album, nil
+	}
+
+	f.Album = ""
+
+	if err := f.MergeFilter(album.AlbumFilter); err != nil {
+		return album, fmt.Errorf("album \"%s\" has an invalid search filter (%s)", album.AlbumName, err)
+	}
+
+	if f.Album == "" {
+		return album, nil
+	}
+
+	// Smart albums may be based on other albums, but not on smart albums to prevent loops.
+	if err := q.db.Where("album_uuid = ?", f.Album).Scopes(AlbumsVisible(q.viewer)).First(&album).Error; err != nil {
+		return entity.Album{AlbumUUID: f.Album, AlbumType: entity.AlbumManual}, nil
+	} else if album.IsSmart() {
+		return album, fmt.Errorf("smart album \"%s\" can't be based on another smart album", album.AlbumName)
+	}
+
+	return album, nil
+}
+
 // Albums searches albums based on their name.
 func (q *Query) Albums(f form.AlbumSearch) (results []AlbumResult, err error) {
-	if err := f.ParseQueryString(); err != nil {
+	s, err := q.albumSearch(&f)
+
+	if err != nil {
 		return results, err
 	}
 
 	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("albums: %+v", f)))
 
-	s := q.db.NewScope(nil).DB()
+	if f.ID == "" {
+		s = s.Limit(Limit(f.Count)).Offset(f.Offset)
+	}
+
+	if result := s.Scan(&results); result.Error != nil {
+		return results, result.Error
+	}
+
+	var searched []string
+
+	for _, album := range results {
+		if album.AlbumType != "" && album.AlbumType != entity.AlbumManual {
+			searched = append(searched, album.AlbumUUID)
+		}
+	}
+
+	counts, err := q.albumCounts(searched)
+
+	if err != nil {
+		log.Warnf("albums: %s", err)
+	}
+
+	for i, album := range results {
+		if count, ok := counts[album.AlbumUUID]; ok {
+			results[i].AlbumCount = count
+		}
+	}
+
+	return results, nil
+}
+
+// albumCountsBatch is the maximum number of albums counted in a single query.
+const albumCountsBatch = 50
+
+// albumCounts returns the number of photos in folder and smart albums by album UUID. They have no
+// photo entries, so their photos are counted like search results, with a single query per batch.
+func (q *Query) albumCounts(albumUUIDs []string) (counts map[string]int, err error) {
+	counts = make(map[string]int, len(albumUUIDs))
+
+	for i := 0; i < len(albumUUIDs); i += albumCountsBatch {
+		j := i + albumCountsBatch
+
+		if j > len(albumUUIDs) {
+			j = len(albumUUIDs)
+		}
+
+		var parts []string
+		var args []interface{}
+
+		for _, albumUUID := range albumUUIDs[i:j] {
+			s, err := q.photoSearch(&form.PhotoSearch{Album: albumUUID})
+
+			if err != nil {
+				log.Warnf("albums: %s", err)
+				continue
+			}
+
+			parts = append(parts, "SELECT ? AS album_uuid, COUNT(*) AS album_count FROM ? AS results")
+			args = append(args, albumUUID, s.SubQuery())
+		}
+
+		if len(parts) == 0 {
+			continue
+		}
+
+		rows, err := q.db.Raw(strings.Join(parts, " UNION ALL "), args...).Rows()
+
+		if err != nil {
+			return counts, err
+		}
+
+		for rows.Next() {
+			var albumUUID string
+			var count int
+
+			if err := rows.Scan(&albumUUID, &count); err != nil {
+				rows.Close()
+				return counts, err
+			}
+
+			counts[albumUUID] = count
+		}
+
+		rows.Close()
+	}
+
+	return counts, nil
+}
+
+// AlbumsTotal returns the total number of album search results, ignoring count and offset.
+func (q *Query) AlbumsTotal(f form.AlbumSearch) (total int, err error) {
+	s, err := q.albumSearch(&f)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return q.total(s)
+}
+
+// albumSearch returns the filtered and sorted album search query without limit and offset.
+func (q *Query) albumSearch(f *form.AlbumSearch) (s *gorm.DB, err error) {
+	if err := f.ParseQueryString(); err != nil {
+		return s, err
+	}
+
+	s = q.db.NewScope(nil).DB()
 
 	s = s.Table("albums").
 		Select(`albums.*, 
@@ -68,27 +245,28 @@ func (q *Query) Albums(f form.AlbumSearch) (results []AlbumResult, err error) {
 		Joins("LEFT JOIN photos_albums ON photos_albums.album_uuid = albums.album_uuid").
 		Joins("LEFT JOIN links ON links.share_uuid = albums.album_uuid").
 		Where("albums.deleted_at IS NULL").
-		Group("albums.id")
+		Group("albums.id").
+		Scopes(AlbumsVisible(q.viewer))
 
 	if f.ID != "" {
 		s = s.Where("albums.album_uuid = ?", f.ID)
 
-		if result := s.Scan(&results); result.Error != nil {
-			return results, result.Error
-		}
-
-		return results, nil
+		return s, nil
 	}
 
 	if f.Query != "" {
-		likeString := "%" + strings.ToLower(f.Query) + "%"
-		s = s.Where("LOWER(albums.album_name) LIKE ?", likeString)
+		likeString := "%" + txt.Normalize(f.Query) + "%"
+		s = s.Where("albums.album_search LIKE ?", likeString)
 	}
 
 	if f.Favorites {
 		s = s.Where("albums.album_favorite = 1")
 	}
 
+	if f.Type != "" {
+		s = s.Where("albums.album_type = ?", f.Type)
+	}
+
 	switch f.Order {
 	case "slug":
 		s = s.Order("albums.album_favorite DESC, album_slug ASC")
@@ -96,15 +274,5 @@ func (q *Query) Albums(f form.AlbumSearch) (results []AlbumResult, err error) {
 		s = s.Order("albums.album_favorite DESC, album_count DESC, albums.created_at DESC")
 	}
 
-	if f.Count > 0 && f.Count <= 1000 {
-		s = s.Limit(f.Count).Offset(f.Offset)
-	} else {
-		s = s.Limit(100).Offset(0)
-	}
-
-	if result := s.Scan(&results); result.Error != nil {
-		return results, result.Error
-	}
-
-	return results, nil
+	return s, nil
 }
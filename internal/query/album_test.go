@@ -1,6 +1,7 @@
 package query
 
 import (
+	"github.com/photoprism/photoprism/internal/entity"
 	form "github.com/photoprism/photoprism/internal/form"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -108,3 +109,111 @@ func TestQuery_Albums(t *testing.T) {
 		t.Log(result)
 	})
 }
+
+func TestQuery_SmartAlbum(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	album := entity.NewAlbum("Favorites")
+
+	if err := album.SetType(entity.AlbumSmart, "", "favorite:true"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Db().Create(album).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer entity.DeleteAlbums(conf.Db(), []string{album.AlbumUUID})
+
+	before, err := search.PhotosTotal(form.PhotoSearch{Album: album.AlbumUUID})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	photos, _, err := search.Photos(form.PhotoSearch{Count: 100})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var photo entity.Photo
+
+	for _, p := range photos {
+		if !p.PhotoFavorite {
+			photo.ID = p.ID
+			break
+		}
+	}
+
+	if photo.ID == 0 {
+		t.Skip("no photo that isn't a favorite")
+	}
+
+	if err := conf.Db().Model(&photo).UpdateColumn("photo_favorite", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer conf.Db().Model(&photo).UpdateColumn("photo_favorite", false)
+
+	// Smart albums are evaluated when viewed, so new favorites are included right away.
+	after, err := search.PhotosTotal(form.PhotoSearch{Album: album.AlbumUUID})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, before+1, after)
+
+	result, err := search.Albums(form.AlbumSearch{Type: entity.AlbumSmart, Count: 10})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, after, result[0].AlbumCount)
+	}
+}
+
+func TestQuery_Albums_Normalized(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	album := entity.NewAlbum("Café Crème")
+
+	if err := db.Create(album).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Unscoped().Delete(album)
+
+	search := New(db)
+
+	for _, q := range []string{"cafe creme", "CAFÉ", "Crème"} {
+		t.Run(q, func(t *testing.T) {
+			result, err := search.Albums(form.AlbumSearch{Query: q, Count: 10})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if assert.Len(t, result, 1) {
+				assert.Equal(t, "Café Crème", result[0].AlbumName)
+			}
+		})
+	}
+	t.Run("mixed case", func(t *testing.T) {
+		result, err := search.Albums(form.AlbumSearch{Query: "HOLIDAY", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, result, 1) {
+			assert.Equal(t, "Holiday2030", result[0].AlbumName)
+		}
+	})
+}
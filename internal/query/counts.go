@@ -0,0 +1,79 @@
+package query
+
+import (
+	"github.com/photoprism/photoprism/internal/mutex"
+)
+
+// Conditions matching label and album associations of photos, labels and albums that were deleted.
+const (
+	orphanedPhotoLabels = "photo_id NOT IN (SELECT id FROM photos) OR label_id NOT IN (SELECT id FROM labels WHERE deleted_at IS NULL)"
+	orphanedPhotoAlbums = "photo_uuid NOT IN (SELECT photo_uuid FROM photos) OR album_uuid NOT IN (SELECT album_uuid FROM albums WHERE deleted_at IS NULL)"
+)
+
+// CountsResult contains the number of removed associations and labels whose count was corrected.
+type CountsResult struct {
+	PhotoLabels int
+	PhotoAlbums int
+	Labels      int
+}
+
+// UpdateLabelCounts recomputes the number of photos per label from the photos_labels table, either for
+// the given labels or for all labels, and returns the number of changed labels. Archived photos and labels
+// removed from a photo are not counted.
+func (q *Query) UpdateLabelCounts(labelIDs ...uint) (updated int, err error) {
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	return q.updateLabelCounts(labelIDs)
+}
+
+// updateLabelCounts recomputes label counts, the caller must hold the database lock.
+func (q *Query) updateLabelCounts(labelIDs []uint) (updated int, err error) {
+	sql := "UPDATE labels SET label_count = (SELECT COUNT(DISTINCT pl.photo_id) FROM photos_labels pl " +
+		"JOIN photos p ON p.id = pl.photo_id AND p.deleted_at IS NULL " +
+		"WHERE pl.label_id = labels.id AND pl.uncertainty < 100) WHERE deleted_at IS NULL"
+
+	var values []interface{}
+
+	if len(labelIDs) > 0 {
+		sql += " AND id IN (?)"
+		values = append(values, labelIDs)
+	}
+
+	result := q.db.Exec(sql, values...)
+
+	return int(result.RowsAffected), result.Error
+}
+
+// CleanupCounts removes label and album associations of deleted photos, labels and albums and recomputes
+// all label counts in a single transaction, e.g. for databases where labels were deleted by older versions.
+func (q *Query) CleanupCounts() (result CountsResult, err error) {
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	tx := &Query{db: q.db.Begin()}
+
+	if result.PhotoLabels, err = tx.deleteOrphans("photos_labels", orphanedPhotoLabels); err != nil {
+		tx.db.Rollback()
+		return result, err
+	}
+
+	if result.PhotoAlbums, err = tx.deleteOrphans("photos_albums", orphanedPhotoAlbums); err != nil {
+		tx.db.Rollback()
+		return result, err
+	}
+
+	if result.Labels, err = tx.updateLabelCounts(nil); err != nil {
+		tx.db.Rollback()
+		return result, err
+	}
+
+	return result, tx.db.Commit().Error
+}
+
+// deleteOrphans removes rows matching a condition from an association table and returns their number.
+func (q *Query) deleteOrphans(table, cond string) (count int, err error) {
+	result := q.db.Exec("DELETE FROM " + table + " WHERE " + cond)
+
+	return int(result.RowsAffected), result.Error
+}
@@ -0,0 +1,108 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_UpdateLabelCounts(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	label := entity.NewLabel("Counted Label", 0)
+
+	if err := db.Create(label).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		db.Where("label_id = ?", label.ID).Delete(&entity.PhotoLabel{})
+		db.Unscoped().Delete(label)
+	}()
+
+	// Labels removed from a photo have an uncertainty of 100 and are not counted.
+	db.Create(entity.NewPhotoLabel(1, label.ID, 20, entity.SrcManual))
+	db.Create(entity.NewPhotoLabel(2, label.ID, 20, entity.SrcManual))
+	db.Create(entity.NewPhotoLabel(3, label.ID, 100, entity.SrcManual))
+
+	updated, err := New(db).UpdateLabelCounts(label.ID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, updated)
+
+	var result entity.Label
+
+	db.First(&result, label.ID)
+	assert.Equal(t, 2, result.LabelCount)
+
+	labels, err := New(db).Labels(form.LabelSearch{ID: label.LabelUUID})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, labels, 1) {
+		assert.Equal(t, 2, labels[0].LabelCount)
+	}
+}
+
+func TestQuery_CleanupCounts(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	deletedLabel := entity.NewLabel("Deleted Label", 0)
+	label := entity.NewLabel("Remaining Label", 0)
+	album := entity.NewAlbum("Deleted Album")
+
+	for _, m := range []interface{}{deletedLabel, label, album} {
+		if err := db.Create(m).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func() {
+		db.Where("label_id IN (?)", []uint{deletedLabel.ID, label.ID}).Delete(&entity.PhotoLabel{})
+		db.Where("album_uuid = ?", album.AlbumUUID).Delete(&entity.PhotoAlbum{})
+		db.Unscoped().Delete(deletedLabel)
+		db.Unscoped().Delete(label)
+		db.Unscoped().Delete(album)
+	}()
+
+	db.Create(entity.NewPhotoLabel(1, deletedLabel.ID, 20, entity.SrcManual))
+	db.Create(entity.NewPhotoLabel(1, label.ID, 20, entity.SrcManual))
+	db.Create(entity.NewPhotoLabel(99999, label.ID, 20, entity.SrcManual))
+	db.Create(entity.NewPhotoAlbum("654", album.AlbumUUID))
+
+	// Labels and albums deleted by older versions left their associations behind.
+	db.Delete(deletedLabel)
+	db.Delete(album)
+
+	result, err := New(db).CleanupCounts()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.GreaterOrEqual(t, result.PhotoLabels, 2)
+	assert.GreaterOrEqual(t, result.PhotoAlbums, 1)
+
+	var count int
+
+	db.Model(&entity.PhotoLabel{}).Where("label_id IN (?)", []uint{deletedLabel.ID, label.ID}).Count(&count)
+	assert.Equal(t, 1, count)
+
+	db.Model(&entity.PhotoAlbum{}).Where("album_uuid = ?", album.AlbumUUID).Count(&count)
+	assert.Equal(t, 0, count)
+
+	var remaining entity.Label
+
+	db.First(&remaining, label.ID)
+	assert.Equal(t, 1, remaining.LabelCount)
+}
@@ -0,0 +1,159 @@
+package query
+
+import (
+	"sort"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/phash"
+)
+
+// DuplicateFile contains the file fields needed to compare perceptual hashes.
+type DuplicateFile struct {
+	FileUUID   string
+	PhotoUUID  string
+	FileName   string
+	FilePhash  string
+	FileWidth  int
+	FileHeight int
+	TakenAt    time.Time
+}
+
+// DuplicateCluster contains visually identical files.
+type DuplicateCluster []DuplicateFile
+
+// Duplicates returns clusters of visually identical primary files. The perceptual hashes of files in
+// a cluster differ by at most distance bits. Photos taken less than burst apart, but not at the
+// same time, are not grouped so that burst shots don't show up as duplicates.
+func (q *Query) Duplicates(distance int, burst time.Duration) (results []DuplicateCluster, err error) {
+	var files []DuplicateFile
+
+	s := q.db.NewScope(nil).DB()
+
+	s = s.Table("files").
+		Select("files.file_uuid, files.photo_uuid, files.file_name, files.file_phash, files.file_width, files.file_height, photos.taken_at").
+		Joins("JOIN photos ON photos.id = files.photo_id").
+		Where("files.file_primary = 1 AND files.file_phash <> '' AND files.deleted_at IS NULL AND photos.deleted_at IS NULL").
+		Order("photos.taken_at, files.file_name")
+
+	if result := s.Scan(&files); result.Error != nil {
+		return results, result.Error
+	}
+
+	return ClusterDuplicates(files, distance, burst), nil
+}
+
+// ClusterDuplicates groups files with similar perceptual hashes, clusters with a single file are omitted.
+// Similar hashes are found with a BK-tree, so that not all files must be compared with each other. If two
+// clusters contain burst shots, they are not merged, even if other files of both clusters are similar.
+func ClusterDuplicates(files []DuplicateFile, distance int, burst time.Duration) (results []DuplicateCluster) {
+	var tree phash.Tree
+
+	hashes := make([]phash.Hash, len(files))
+	parent := make([]int, len(files))
+	members := make(map[int][]int, len(files))
+
+	for i, f := range files {
+		if h, err := phash.Parse(f.FilePhash); err == nil {
+			hashes[i] = h
+			parent[i] = i
+			members[i] = []int{i}
+			tree.Add(h, i)
+		} else {
+			parent[i] = -1
+		}
+	}
+
+	var root func(i int) int
+
+	root = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+
+		return i
+	}
+
+	// isBurst returns true if both files were taken less than burst apart, but not at the same time.
+	isBurst := func(i, j int) bool {
+		diff := files[i].TakenAt.Sub(files[j].TakenAt)
+
+		if diff < 0 {
+			diff = -diff
+		}
+
+		return diff > 0 && diff < burst
+	}
+
+	// union merges the clusters of both files, unless this would group burst shots.
+	union := func(i, j int) {
+		ri, rj := root(i), root(j)
+
+		if ri == rj {
+			return
+		}
+
+		if burst > 0 {
+			for _, a := range members[ri] {
+				for _, b := range members[rj] {
+					if isBurst(a, b) {
+						return
+					}
+				}
+			}
+		}
+
+		if len(members[ri]) < len(members[rj]) {
+			ri, rj = rj, ri
+		}
+
+		parent[rj] = ri
+		members[ri] = append(members[ri], members[rj]...)
+		delete(members, rj)
+	}
+
+	for i := range files {
+		if parent[i] < 0 {
+			continue
+		}
+
+		similar := tree.Find(hashes[i], distance)
+
+		// Merge in file order, so that results don't depend on the tree layout.
+		sort.Ints(similar)
+
+		for _, j := range similar {
+			if j > i {
+				union(i, j)
+			}
+		}
+	}
+
+	clusters := make(map[int]int)
+
+	for i, f := range files {
+		if parent[i] < 0 {
+			continue
+		}
+
+		r := root(i)
+
+		if n, ok := clusters[r]; ok {
+			results[n] = append(results[n], f)
+		} else {
+			clusters[r] = len(results)
+			results = append(results, DuplicateCluster{f})
+		}
+	}
+
+	n := 0
+
+	for _, c := range results {
+		if len(c) > 1 {
+			results[n] = c
+			n++
+		}
+	}
+
+	return results[:n]
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Duplicates(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	results, err := search.Duplicates(conf.DuplicateDistance(), 0)
+
+	assert.Nil(t, err)
+
+	for _, cluster := range results {
+		assert.GreaterOrEqual(t, len(cluster), 2)
+	}
+}
+
+func TestClusterDuplicates(t *testing.T) {
+	taken := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	files := []DuplicateFile{
+		{FileUUID: "original", FilePhash: "1519145d5856d6d6", TakenAt: taken},
+		{FileUUID: "resized", FilePhash: "1519145d5856d6d7", TakenAt: taken},
+		{FileUUID: "burst", FilePhash: "1519145d5856d6d4", TakenAt: taken.Add(time.Second)},
+		{FileUUID: "other", FilePhash: "e6e6a2b3a7a92929", TakenAt: taken},
+		{FileUUID: "invalid", FilePhash: "xyz", TakenAt: taken},
+	}
+
+	t.Run("all similar", func(t *testing.T) {
+		results := ClusterDuplicates(files, 4, 0)
+
+		if assert.Len(t, results, 1) {
+			assert.Len(t, results[0], 3)
+			assert.Equal(t, "original", results[0][0].FileUUID)
+		}
+	})
+	t.Run("skip burst shots", func(t *testing.T) {
+		results := ClusterDuplicates(files, 4, 5*time.Second)
+
+		if assert.Len(t, results, 1) {
+			assert.Len(t, results[0], 2)
+			assert.Equal(t, "resized", results[0][1].FileUUID)
+		}
+	})
+	t.Run("burst shots via other file", func(t *testing.T) {
+		series := []DuplicateFile{
+			{FileUUID: "first", FilePhash: "1519145d5856d6d6", TakenAt: taken},
+			{FileUUID: "later", FilePhash: "1519145d5856d6d7", TakenAt: taken.Add(10 * time.Second)},
+			{FileUUID: "burst", FilePhash: "1519145d5856d6d4", TakenAt: taken.Add(12 * time.Second)},
+		}
+
+		// The first file is similar to both, but the others are burst shots.
+		results := ClusterDuplicates(series, 4, 5*time.Second)
+
+		if assert.Len(t, results, 1) {
+			assert.Len(t, results[0], 2)
+			assert.Equal(t, "first", results[0][0].FileUUID)
+			assert.Equal(t, "later", results[0][1].FileUUID)
+		}
+	})
+	t.Run("exact match only", func(t *testing.T) {
+		results := ClusterDuplicates(files, 0, 0)
+
+		assert.Len(t, results, 0)
+	})
+}
@@ -1,7 +1,10 @@
 package query
 
 import (
+	"fmt"
+
 	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 // Files finds files returning maximum results defined by limit
@@ -14,9 +17,9 @@ func (q *Query) Files(limit int, offset int) (files []entity.File, err error) {
 	return files, nil
 }
 
-// FilesByUUID
+// FilesByUUID returns the primary files of photos and files with the given UUIDs that are visible to the viewer.
 func (q *Query) FilesByUUID(u []string, limit int, offset int) (files []entity.File, err error) {
-	if err := q.db.Where("(photo_uuid IN (?) AND file_primary = 1) OR file_uuid IN (?)", u, u).Preload("Photo").Limit(limit).Offset(offset).Find(&files).Error; err != nil {
+	if err := q.db.Where("(photo_uuid IN (?) AND file_primary = 1) OR file_uuid IN (?)", u, u).Scopes(FilesVisible(q.viewer)).Preload("Photo").Limit(limit).Offset(offset).Find(&files).Error; err != nil {
 		return files, err
 	}
 
@@ -41,17 +44,145 @@ func (q *Query) FileByUUID(uuid string) (file entity.File, err error) {
 	return file, nil
 }
 
-// FirstFileByHash finds a file with a given hash string.
+// FileByHash finds a file with a given hash string, if the viewer is allowed to see the photo it belongs to.
 func (q *Query) FileByHash(fileHash string) (file entity.File, err error) {
-	if err := q.db.Where("file_hash = ?", fileHash).Preload("Links").Preload("Photo").First(&file).Error; err != nil {
+	if err := q.db.Where("file_hash = ?", fileHash).Scopes(FilesVisible(q.viewer)).Preload("Links").Preload("Photo").First(&file).Error; err != nil {
 		return file, err
 	}
 
 	return file, nil
 }
 
-// SetPhotoPrimary sets a new primary image file for a photo.
+// SetPhotoPrimary sets a new primary image file for a photo, it must be a JPEG belonging to the photo.
 func (q *Query) SetPhotoPrimary(photoUUID, fileUUID string) error {
-	q.db.Model(entity.File{}).Where("photo_uuid = ? AND file_uuid <> ?", photoUUID, fileUUID).UpdateColumn("file_primary", false)
-	return q.db.Model(entity.File{}).Where("photo_uuid = ? AND file_uuid = ?", photoUUID, fileUUID).UpdateColumn("file_primary", true).Error
+	var file entity.File
+
+	if err := q.db.Where("photo_uuid = ? AND file_uuid = ?", photoUUID, fileUUID).First(&file).Error; err != nil {
+		return err
+	}
+
+	if file.FileType != string(fs.TypeJpeg) {
+		return fmt.Errorf("%s is not a jpeg file", file.FileName)
+	}
+
+	tx := q.db.Begin()
+
+	if err := tx.Model(entity.File{}).Where("photo_uuid = ? AND file_uuid <> ?", photoUUID, fileUUID).UpdateColumn("file_primary", false).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(entity.File{}).Where("photo_uuid = ? AND file_uuid = ?", photoUUID, fileUUID).UpdateColumn("file_primary", true).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UnstackFile moves a file of a stack to a new photo, which is returned. The new photo has the same
+// time, location and camera as the original photo. JPEG files become the primary file of the new photo.
+// If the file was the primary file of the original photo, another JPEG becomes primary instead.
+func (q *Query) UnstackFile(photoUUID, fileUUID string) (photo entity.Photo, err error) {
+	var original entity.Photo
+	var file entity.File
+	var count int
+
+	if err := q.db.Where("photo_uuid = ?", photoUUID).First(&original).Error; err != nil {
+		return photo, err
+	}
+
+	if err := q.db.Where("photo_id = ? AND file_uuid = ?", original.ID, fileUUID).First(&file).Error; err != nil {
+		return photo, err
+	}
+
+	if err := q.db.Model(entity.File{}).Where("photo_id = ?", original.ID).Count(&count).Error; err != nil {
+		return photo, err
+	}
+
+	if count < 2 {
+		return photo, fmt.Errorf("%s is the only file of the photo", file.FileName)
+	}
+
+	photo = entity.Photo{
+		TakenAt:            original.TakenAt,
+		TakenAtLocal:       original.TakenAtLocal,
+		TakenSrc:           original.TakenSrc,
+		PhotoTitle:         original.PhotoTitle,
+		TitleSrc:           original.TitleSrc,
+		PhotoRoot:          original.PhotoRoot,
+		PhotoPath:          original.PhotoPath,
+		PhotoName:          fs.Base(file.FileName, false),
+		PhotoPrivate:       original.PhotoPrivate,
+		PhotoLat:           original.PhotoLat,
+		PhotoLng:           original.PhotoLng,
+		PhotoAltitude:      original.PhotoAltitude,
+		PhotoIso:           original.PhotoIso,
+		PhotoFocalLength:   original.PhotoFocalLength,
+		PhotoFocalLength35: original.PhotoFocalLength35,
+		PhotoFNumber:       original.PhotoFNumber,
+		PhotoExposure:      original.PhotoExposure,
+		CameraID:           original.CameraID,
+		CameraSerial:       original.CameraSerial,
+		CameraSrc:          original.CameraSrc,
+		LensID:             original.LensID,
+		PlaceID:            original.PlaceID,
+		LocationID:         original.LocationID,
+		LocationSrc:        original.LocationSrc,
+		TimeZone:           original.TimeZone,
+		PhotoCountry:       original.PhotoCountry,
+		PhotoYear:          original.PhotoYear,
+		PhotoMonth:         original.PhotoMonth,
+	}
+
+	isJpeg := file.FileType == string(fs.TypeJpeg)
+	tx := q.db.Begin()
+
+	if err := tx.Create(&photo).Error; err != nil {
+		tx.Rollback()
+		return photo, err
+	}
+
+	if err := tx.Model(&file).Updates(map[string]interface{}{"photo_id": photo.ID, "photo_uuid": photo.PhotoUUID, "file_primary": isJpeg}).Error; err != nil {
+		tx.Rollback()
+		return photo, err
+	}
+
+	var primary int
+
+	if err := tx.Model(entity.File{}).Where("photo_id = ? AND file_primary = 1", original.ID).Count(&primary).Error; err != nil {
+		tx.Rollback()
+		return photo, err
+	}
+
+	if primary == 0 {
+		var next entity.File
+
+		if err := tx.Where("photo_id = ? AND file_type = ?", original.ID, fs.TypeJpeg).Order("file_name").First(&next).Error; err == nil {
+			if err := tx.Model(&next).UpdateColumn("file_primary", true).Error; err != nil {
+				tx.Rollback()
+				return photo, err
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return photo, err
+	}
+
+	return photo, nil
+}
+
+// FileByName returns the file entity for a given file name relative to the default originals root.
+func (q *Query) FileByName(fileName string) (file entity.File, err error) {
+	return q.FileByRootName(entity.RootDefault, fileName)
+}
+
+// FileByRootName returns the file entity for a given file name relative to an originals root.
+func (q *Query) FileByRootName(root, fileName string) (file entity.File, err error) {
+	if err := q.db.Where("file_root = ? AND file_name = ?", root, fileName).Preload("Photo").First(&file).Error; err != nil {
+		return file, err
+	}
+
+	return file, nil
 }
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/capture"
+)
+
+// FileErrorResult contains a file with the error recorded when it was last processed.
+type FileErrorResult struct {
+	FileUUID       string
+	PhotoUUID      string
+	FileRoot       string
+	FileName       string
+	FileType       string
+	FileError      string
+	FileErrorStage string
+	FileErrorAt    *time.Time
+}
+
+// FileErrors returns files with recorded errors, most recent first. Results can be filtered by
+// stage, originals root and folder, including sub-folders.
+func (q *Query) FileErrors(f form.FileErrorSearch) (results []FileErrorResult, err error) {
+	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("errors: %+v", f)))
+
+	s := q.db.NewScope(nil).DB()
+
+	s = s.Table("files").
+		Select("file_uuid, photo_uuid, file_root, file_name, file_type, file_error, file_error_stage, file_error_at").
+		Where("files.deleted_at IS NULL AND files.file_error <> ''")
+
+	if f.Stage != "" {
+		s = s.Where("files.file_error_stage = ?", f.Stage)
+	}
+
+	if f.Root != "" {
+		s = s.Where("files.file_root = ?", f.Root)
+	}
+
+	if folder := strings.Trim(f.Folder, "/"); folder != "" {
+		s = s.Where("files.file_name LIKE ?", folder+"/%")
+	}
+
+	s = s.Order("files.file_error_at DESC, files.file_name").Limit(Limit(f.Count)).Offset(f.Offset)
+
+	if err := s.Scan(&results).Error; err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// FileErrorNames returns the names of all files in an originals root with recorded errors,
+// relative to the root.
+func (q *Query) FileErrorNames(root string) (fileNames []string, err error) {
+	err = q.db.Table("files").
+		Where("deleted_at IS NULL AND file_error <> '' AND file_root = ?", root).
+		Pluck("file_name", &fileNames).Error
+
+	return fileNames, err
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_FileErrors(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	broken := &entity.File{FileRoot: entity.RootDefault, FileName: "errors/2020/broken.jpg", FileType: "jpg", FileHash: "errors-broken"}
+	broken.SetError(entity.StageThumbnail, errors.New("unexpected EOF"))
+
+	raw := &entity.File{FileRoot: "archive", FileName: "errors/raw.cr2", FileType: "raw", FileHash: "errors-raw"}
+	raw.SetError(entity.StageConvert, errors.New("exit status 1"))
+
+	for _, f := range []*entity.File{broken, raw} {
+		if err := db.Create(f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func() {
+		db.Unscoped().Delete(broken)
+		db.Unscoped().Delete(raw)
+	}()
+
+	q := New(db)
+
+	t.Run("all", func(t *testing.T) {
+		results, err := q.FileErrors(form.FileErrorSearch{})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.GreaterOrEqual(t, len(results), 2)
+	})
+	t.Run("stage", func(t *testing.T) {
+		results, err := q.FileErrors(form.FileErrorSearch{Stage: entity.StageConvert, Folder: "errors"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "errors/raw.cr2", results[0].FileName)
+			assert.Equal(t, "exit status 1", results[0].FileError)
+			assert.NotNil(t, results[0].FileErrorAt)
+		}
+	})
+	t.Run("folder", func(t *testing.T) {
+		results, err := q.FileErrors(form.FileErrorSearch{Root: entity.RootDefault, Folder: "/errors/2020/"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "errors/2020/broken.jpg", results[0].FileName)
+			assert.Equal(t, entity.StageThumbnail, results[0].FileErrorStage)
+		}
+	})
+	t.Run("names", func(t *testing.T) {
+		names, err := q.FileErrorNames("archive")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Contains(t, names, "errors/raw.cr2")
+		assert.NotContains(t, names, "errors/2020/broken.jpg")
+	})
+}
@@ -30,3 +30,27 @@ func (q *Query) FileSyncs(accountId uint, status string, limit int) (result []en
 
 	return result, nil
 }
+
+// SyncedFiles returns downloaded and uploaded files of an account with a remote name after the
+// cursor, so that reconciling can be resumed where it stopped.
+func (q *Query) SyncedFiles(accountId uint, cursor string, limit int) (result []entity.FileSync, err error) {
+	s := q.db.Where("account_id = ? AND status IN (?)", accountId, []string{entity.FileSyncDownloaded, entity.FileSyncUploaded})
+
+	if cursor != "" {
+		s = s.Where("remote_name > ?", cursor)
+	}
+
+	s = s.Order("remote_name ASC")
+
+	if limit > 0 {
+		s = s.Limit(limit).Offset(0)
+	}
+
+	s = s.Preload("File")
+
+	if err := s.Find(&result).Error; err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_SyncedFiles(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+	q := New(db)
+
+	var accountID uint = 1000011
+
+	for name, status := range map[string]string{
+		"/sync/a.jpg": entity.FileSyncDownloaded,
+		"/sync/b.jpg": entity.FileSyncUploaded,
+		"/sync/c.jpg": entity.FileSyncNew,
+		"/sync/d.jpg": entity.FileSyncUploaded,
+	} {
+		f := entity.NewFileSync(accountID, name)
+		f.Status = status
+
+		if err := db.Save(f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer db.Where("account_id = ?", accountID).Delete(entity.FileSync{})
+
+	t.Run("all", func(t *testing.T) {
+		results, err := q.SyncedFiles(accountID, "", 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, results, 3) {
+			assert.Equal(t, "/sync/a.jpg", results[0].RemoteName)
+			assert.Equal(t, "/sync/b.jpg", results[1].RemoteName)
+			assert.Equal(t, "/sync/d.jpg", results[2].RemoteName)
+		}
+	})
+	t.Run("after cursor", func(t *testing.T) {
+		results, err := q.SyncedFiles(accountID, "/sync/b.jpg", 10)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "/sync/d.jpg", results[0].RemoteName)
+		}
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"testing"
 
+	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 )
 
 func TestQuery_Files(t *testing.T) {
@@ -100,3 +102,120 @@ func TestQuery_FileByHash(t *testing.T) {
 		t.Log(file)
 	})
 }
+
+func TestQuery_FileByName(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("file found", func(t *testing.T) {
+		file, err := search.FileByName("exampleFileName.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "fq8es39w45bnlqdw", file.FileUUID)
+	})
+
+	t.Run("no file found", func(t *testing.T) {
+		_, err := search.FileByName("111")
+
+		assert.Error(t, err, "record not found")
+	})
+}
+
+func TestQuery_UnstackFile(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	search := New(db)
+
+	photo := entity.Photo{PhotoName: "IMG_1234", PhotoTitle: "Stack", CameraID: 2, PhotoLat: 1.5, PhotoLng: 2.5}
+
+	if err := db.Create(&photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	files := []entity.File{
+		{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "stack/IMG_1234.CR2", FileType: "raw"},
+		{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "stack/IMG_1234.jpg", FileType: "jpg", FilePrimary: true},
+		{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "stack/IMG_1234_edit.jpg", FileType: "jpg"},
+	}
+
+	for i := range files {
+		if err := db.Create(&files[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var unstacked []entity.Photo
+
+	defer func() {
+		for _, p := range append(unstacked, photo) {
+			db.Unscoped().Where("photo_id = ?", p.ID).Delete(&entity.File{})
+			db.Unscoped().Delete(&entity.Photo{ID: p.ID})
+		}
+	}()
+
+	primary := func(photoID uint) string {
+		var file entity.File
+
+		if err := db.Where("photo_id = ? AND file_primary = 1", photoID).First(&file).Error; err != nil {
+			return ""
+		}
+
+		return file.FileName
+	}
+
+	t.Run("set primary", func(t *testing.T) {
+		assert.Nil(t, search.SetPhotoPrimary(photo.PhotoUUID, files[2].FileUUID))
+		assert.Equal(t, "stack/IMG_1234_edit.jpg", primary(photo.ID))
+	})
+	t.Run("raw can't be primary", func(t *testing.T) {
+		assert.Error(t, search.SetPhotoPrimary(photo.PhotoUUID, files[0].FileUUID))
+		assert.Equal(t, "stack/IMG_1234_edit.jpg", primary(photo.ID))
+	})
+	t.Run("file of other photo", func(t *testing.T) {
+		assert.Error(t, search.SetPhotoPrimary(photo.PhotoUUID, "fq8es39w45bnlqdw"))
+		assert.Equal(t, "stack/IMG_1234_edit.jpg", primary(photo.ID))
+	})
+	t.Run("unstack primary", func(t *testing.T) {
+		result, err := search.UnstackFile(photo.PhotoUUID, files[2].FileUUID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		unstacked = append(unstacked, result)
+
+		assert.NotEqual(t, photo.ID, result.ID)
+		assert.Equal(t, "IMG_1234_edit", result.PhotoName)
+		assert.Equal(t, uint(2), result.CameraID)
+		assert.Equal(t, float32(1.5), result.PhotoLat)
+		assert.Equal(t, "stack/IMG_1234_edit.jpg", primary(result.ID))
+		assert.Equal(t, "stack/IMG_1234.jpg", primary(photo.ID))
+	})
+	t.Run("unstack raw", func(t *testing.T) {
+		result, err := search.UnstackFile(photo.PhotoUUID, files[0].FileUUID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		unstacked = append(unstacked, result)
+
+		assert.Equal(t, "", primary(result.ID))
+		assert.Equal(t, "stack/IMG_1234.jpg", primary(photo.ID))
+	})
+	t.Run("only file", func(t *testing.T) {
+		_, err := search.UnstackFile(photo.PhotoUUID, files[1].FileUUID)
+
+		assert.Error(t, err)
+	})
+	t.Run("file not found", func(t *testing.T) {
+		_, err := search.UnstackFile(photo.PhotoUUID, "xxx")
+
+		assert.True(t, gorm.IsRecordNotFoundError(err))
+	})
+}
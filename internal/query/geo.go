@@ -2,7 +2,6 @@ package query
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/photoprism/photoprism/internal/form"
@@ -51,18 +50,19 @@ func (q *Query) Geo(f form.GeoSearch) (results []GeoResult, err error) {
 		AND files.file_missing = 0 AND files.file_primary AND files.deleted_at IS NULL`).
 		Where("photos.deleted_at IS NULL").
 		Where("photos.photo_lat <> 0").
-		Group("photos.id, files.id")
+		Group("photos.id, files.id").
+		Scopes(PhotosVisible(q.viewer))
 
 	f.Query = txt.Clip(f.Query, txt.ClipKeyword)
 
 	if f.Query != "" {
 		s = s.Joins("LEFT JOIN photos_keywords ON photos_keywords.photo_id = photos.id").
 			Joins("LEFT JOIN keywords ON photos_keywords.keyword_id = keywords.id").
-			Where("keywords.keyword LIKE ?", strings.ToLower(f.Query)+"%")
+			Where("keywords.keyword LIKE ?", txt.Normalize(f.Query)+"%")
 	}
 
 	if f.Review {
-		s = s.Where("photos.photo_quality < 3")
+		s = s.Where("photos.photo_quality < ?", ReviewQuality)
 	} else if f.Quality != 0 {
 		s = s.Where("photos.photo_quality >= ?", f.Quality)
 	}
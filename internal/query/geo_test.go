@@ -18,7 +18,7 @@ func TestQuery_Geo(t *testing.T) {
 		result, err := search.Geo(query)
 
 		assert.Nil(t, err)
-		assert.Equal(t, 4, len(result))
+		assert.Equal(t, 5, len(result))
 
 	})
 
@@ -2,13 +2,14 @@ package query
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/gosimple/slug"
+	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/pkg/capture"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
 // LabelResult contains found labels
@@ -56,12 +57,13 @@ func (q *Query) LabelByUUID(labelUUID string) (label entity.Label, err error) {
 	return label, nil
 }
 
-// LabelThumbBySlug returns a label preview file based on the slug name.
+// LabelThumbBySlug returns a label preview file based on the slug name, photos not visible to the viewer are skipped.
 func (q *Query) LabelThumbBySlug(labelSlug string) (file entity.File, err error) {
 	if err := q.db.Where("files.file_primary AND files.deleted_at IS NULL").
 		Joins("JOIN labels ON labels.label_slug = ?", labelSlug).
 		Joins("JOIN photos_labels ON photos_labels.label_id = labels.id AND photos_labels.photo_id = files.photo_id").
 		Joins("JOIN photos ON photos.id = files.photo_id AND photos.photo_private = 0 AND photos.deleted_at IS NULL").
+		Scopes(PhotosVisible(q.viewer)).
 		Order("photos.photo_quality DESC, photos_labels.uncertainty ASC").
 		First(&file).Error; err != nil {
 		return file, err
@@ -70,13 +72,14 @@ func (q *Query) LabelThumbBySlug(labelSlug string) (file entity.File, err error)
 	return file, nil
 }
 
-// LabelThumbByUUID returns a label preview file based on the label UUID.
+// LabelThumbByUUID returns a label preview file based on the label UUID, photos not visible to the viewer are skipped.
 func (q *Query) LabelThumbByUUID(labelUUID string) (file entity.File, err error) {
 	// Search matching label
 	err = q.db.Where("files.file_primary AND files.deleted_at IS NULL").
 		Joins("JOIN labels ON labels.label_uuid = ?", labelUUID).
 		Joins("JOIN photos_labels ON photos_labels.label_id = labels.id AND photos_labels.photo_id = files.photo_id").
 		Joins("JOIN photos ON photos.id = files.photo_id AND photos.photo_private = 0 AND photos.deleted_at IS NULL").
+		Scopes(PhotosVisible(q.viewer)).
 		Order("photos.photo_quality DESC, photos_labels.uncertainty ASC").
 		First(&file).Error
 
@@ -90,6 +93,7 @@ func (q *Query) LabelThumbByUUID(labelUUID string) (file entity.File, err error)
 		Joins("JOIN categories c ON photos_labels.label_id = c.label_id").
 		Joins("JOIN labels ON c.category_id = labels.id AND labels.label_uuid= ?", labelUUID).
 		Joins("JOIN photos ON photos.id = files.photo_id AND photos.photo_private = 0 AND photos.deleted_at IS NULL").
+		Scopes(PhotosVisible(q.viewer)).
 		Order("photos.photo_quality DESC, photos_labels.uncertainty ASC").
 		First(&file).Error
 
@@ -98,13 +102,43 @@ func (q *Query) LabelThumbByUUID(labelUUID string) (file entity.File, err error)
 
 // Labels searches labels based on their name.
 func (q *Query) Labels(f form.LabelSearch) (results []LabelResult, err error) {
-	if err := f.ParseQueryString(); err != nil {
+	s, err := q.labelSearch(&f)
+
+	if err != nil {
 		return results, err
 	}
 
 	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("labels: %+v", f)))
 
-	s := q.db.NewScope(nil).DB()
+	if f.ID == "" {
+		s = s.Limit(Limit(f.Count)).Offset(f.Offset)
+	}
+
+	if result := s.Scan(&results); result.Error != nil {
+		return results, result.Error
+	}
+
+	return results, nil
+}
+
+// LabelsTotal returns the total number of label search results, ignoring count and offset.
+func (q *Query) LabelsTotal(f form.LabelSearch) (total int, err error) {
+	s, err := q.labelSearch(&f)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return q.total(s)
+}
+
+// labelSearch returns the filtered and sorted label search query without limit and offset.
+func (q *Query) labelSearch(f *form.LabelSearch) (s *gorm.DB, err error) {
+	if err := f.ParseQueryString(); err != nil {
+		return s, err
+	}
+
+	s = q.db.NewScope(nil).DB()
 
 	// s.LogMode(true)
 
@@ -116,11 +150,7 @@ func (q *Query) Labels(f form.LabelSearch) (results []LabelResult, err error) {
 	if f.ID != "" {
 		s = s.Where("labels.label_uuid = ?", f.ID)
 
-		if result := s.Scan(&results); result.Error != nil {
-			return results, result.Error
-		}
-
-		return results, nil
+		return s, nil
 	}
 
 	if f.Query != "" {
@@ -129,12 +159,12 @@ func (q *Query) Labels(f form.LabelSearch) (results []LabelResult, err error) {
 		var label entity.Label
 
 		slugString := slug.Make(f.Query)
-		likeString := "%" + strings.ToLower(f.Query) + "%"
+		likeString := "%" + txt.Normalize(f.Query) + "%"
 
 		if result := q.db.First(&label, "label_slug = ? OR custom_slug = ?", slugString, slugString); result.Error != nil {
 			log.Infof("search: label \"%s\" not found", f.Query)
 
-			s = s.Where("LOWER(labels.label_name) LIKE ?", likeString)
+			s = s.Where("labels.label_search LIKE ?", likeString)
 		} else {
 			labelIds = append(labelIds, label.ID)
 
@@ -165,15 +195,5 @@ func (q *Query) Labels(f form.LabelSearch) (results []LabelResult, err error) {
 		s = s.Order("labels.label_favorite DESC, custom_slug ASC")
 	}
 
-	if f.Count > 0 && f.Count <= 1000 {
-		s = s.Limit(f.Count).Offset(f.Offset)
-	} else {
-		s = s.Limit(100).Offset(0)
-	}
-
-	if result := s.Scan(&results); result.Error != nil {
-		return results, result.Error
-	}
-
-	return results, nil
+	return s, nil
 }
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -86,6 +87,62 @@ func TestQuery_LabelThumbByUUID(t *testing.T) {
 		assert.Error(t, err, "record not found")
 		t.Log(file)
 	})
+
+	t.Run("restricted album", func(t *testing.T) {
+		db := conf.Db()
+
+		photo := &entity.Photo{PhotoTitle: "Label Thumb", CameraID: 2, LensID: 2, PhotoQuality: 3}
+
+		if err := db.Create(photo).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		file := &entity.File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "label-thumb.jpg", FileType: "jpg", FilePrimary: true}
+
+		if err := db.Create(file).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		label := entity.NewLabel("Restricted Label Thumb", 0)
+
+		if err := db.Create(label).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		album := entity.NewAlbum("Restricted Label Thumb")
+
+		if err := db.Create(album).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		defer func() {
+			db.Unscoped().Where("album_uuid = ?", album.AlbumUUID).Delete(&entity.PhotoAlbum{})
+			db.Unscoped().Where("album_uuid = ?", album.AlbumUUID).Delete(&entity.AlbumPermission{})
+			db.Unscoped().Where("photo_id = ?", photo.ID).Delete(&entity.PhotoLabel{})
+			db.Unscoped().Delete(album)
+			db.Unscoped().Delete(label)
+			db.Unscoped().Delete(file)
+			db.Unscoped().Delete(photo)
+		}()
+
+		if err := db.Create(entity.NewPhotoLabel(photo.ID, label.ID, 10, "manual")).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if err := album.SetAccess(db, entity.AlbumAccess{Visibility: entity.VisibleRoles, Roles: []string{"family"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		entity.NewPhotoAlbum(photo.PhotoUUID, album.AlbumUUID).FirstOrCreate(db)
+
+		result, err := q.As(Viewer{Role: "family"}).LabelThumbByUUID(label.LabelUUID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "label-thumb.jpg", result.FileName)
+
+		_, err = q.As(Viewer{Role: "guest"}).LabelThumbByUUID(label.LabelUUID)
+		assert.Error(t, err)
+	})
 }
 
 func TestQuery_Labels(t *testing.T) {
@@ -121,7 +178,7 @@ func TestQuery_Labels(t *testing.T) {
 		query := form.NewLabelSearch("")
 		result, err := q.Labels(query)
 		assert.Nil(t, err)
-		assert.Equal(t, 3, len(result))
+		assert.Equal(t, 4, len(result))
 	})
 
 	t.Run("search with invalid query string", func(t *testing.T) {
@@ -131,3 +188,38 @@ func TestQuery_Labels(t *testing.T) {
 		t.Log(result)
 	})
 }
+
+func TestQuery_Labels_Normalized(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	label := entity.NewLabel("Zürich", 0).FirstOrCreate(db)
+	defer db.Unscoped().Delete(label)
+
+	search := New(db)
+
+	for _, q := range []string{"zurich", "ZÜRICH", "Zuri"} {
+		t.Run(q, func(t *testing.T) {
+			result, err := search.Labels(form.LabelSearch{Query: q, Count: 10})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if assert.Len(t, result, 1) {
+				assert.Equal(t, "Zürich", result[0].LabelName)
+			}
+		})
+	}
+	t.Run("mixed case", func(t *testing.T) {
+		result, err := search.Labels(form.LabelSearch{Query: "fLoWeR", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, result, 1) {
+			assert.Equal(t, "Flower", result[0].LabelName)
+		}
+	})
+}
@@ -1,10 +1,18 @@
 package query
 
+import (
+	"sort"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
 // MomentsTimeResult contains photo counts per month and year
 type MomentsTimeResult struct {
 	PhotoYear  int
 	PhotoMonth int
 	Count      int
+	Title      string `gorm:"-"`
 }
 
 // GetMomentsTime counts photos per month and year
@@ -23,3 +31,95 @@ func (q *Query) GetMomentsTime() (results []MomentsTimeResult, err error) {
 
 	return results, nil
 }
+
+// MomentsWeekResult contains photo counts per week, the start is the first day of the week.
+type MomentsWeekResult struct {
+	Year  int
+	Week  int
+	Start time.Time
+	Count int
+	Title string    `gorm:"-"`
+	First time.Time `json:"-"`
+}
+
+// momentsWeeksBatch is the number of photos loaded at once to count photos per week.
+const momentsWeeksBatch = 10000
+
+// GetMomentsWeeks counts photos per week based on the local time they were taken. Weeks begin on weekStart
+// and are numbered like txt.WeekNumber, the most recent week is returned first.
+func (q *Query) GetMomentsWeeks(weekStart time.Weekday) (results []MomentsWeekResult, err error) {
+	var photos []struct {
+		ID           uint
+		TakenAtLocal time.Time
+	}
+
+	weeks := make(map[[2]int]int)
+	lastID := uint(0)
+
+	// Photos are loaded in batches, so that memory usage doesn't grow with the library size.
+	for {
+		if err := q.db.Table("photos").Select("id, taken_at_local").Where("deleted_at IS NULL AND id > ?", lastID).
+			Order("id").Limit(momentsWeeksBatch).Scan(&photos).Error; err != nil {
+			return results, err
+		}
+
+		for _, p := range photos {
+			results = countWeek(results, weeks, p.TakenAtLocal, weekStart)
+		}
+
+		if len(photos) < momentsWeeksBatch {
+			break
+		}
+
+		lastID = photos[len(photos)-1].ID
+	}
+
+	return sortWeeks(results), nil
+}
+
+// groupWeeks counts dates per week, the most recent week is returned first.
+func groupWeeks(dates []time.Time, weekStart time.Weekday) (results []MomentsWeekResult) {
+	weeks := make(map[[2]int]int)
+
+	for _, date := range dates {
+		results = countWeek(results, weeks, date, weekStart)
+	}
+
+	return sortWeeks(results)
+}
+
+// countWeek adds a date to the count of its week, weeks maps year and week number to the result index.
+func countWeek(results []MomentsWeekResult, weeks map[[2]int]int, date time.Time, weekStart time.Weekday) []MomentsWeekResult {
+	year, week := txt.WeekNumber(date, weekStart)
+	key := [2]int{year, week}
+
+	if i, ok := weeks[key]; ok {
+		results[i].Count++
+
+		if date.Before(results[i].First) {
+			results[i].First = date
+		}
+
+		return results
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	start := day.AddDate(0, 0, -((int(day.Weekday()) - int(weekStart) + 7) % 7))
+
+	weeks[key] = len(results)
+
+	return append(results, MomentsWeekResult{Year: year, Week: week, Start: start, Count: 1, First: date})
+}
+
+// sortWeeks sorts week results so that the most recent week is first.
+func sortWeeks(results []MomentsWeekResult) []MomentsWeekResult {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Year != results[j].Year {
+			return results[i].Year > results[j].Year
+		}
+
+		return results[i].Week > results[j].Week
+	})
+
+	return results
+}
@@ -3,6 +3,7 @@ package query
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
 )
@@ -20,3 +21,49 @@ func TestQuery_GetMomentsTime(t *testing.T) {
 		assert.Equal(t, 2, result[0].Count)
 	})
 }
+
+func TestQuery_GetMomentsWeeks(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	result, err := search.GetMomentsWeeks(time.Monday)
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, result)
+}
+
+func TestGroupWeeks(t *testing.T) {
+	dates := []time.Time{
+		time.Date(2020, 12, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 12, 29, 9, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("monday", func(t *testing.T) {
+		// Thursday, Dec 31 belongs to ISO week 53 of 2020, just like Sunday, Jan 3.
+		weeks := groupWeeks(dates, time.Monday)
+
+		if assert.Len(t, weeks, 1) {
+			assert.Equal(t, 2020, weeks[0].Year)
+			assert.Equal(t, 53, weeks[0].Week)
+			assert.Equal(t, 4, weeks[0].Count)
+			assert.Equal(t, "2020-12-28", weeks[0].Start.Format("2006-01-02"))
+			assert.Equal(t, dates[3], weeks[0].First)
+		}
+	})
+	t.Run("sunday", func(t *testing.T) {
+		// The week from Sunday, Dec 27 belongs to 2020, the next one is the first of 2021.
+		weeks := groupWeeks(dates, time.Sunday)
+
+		if assert.Len(t, weeks, 2) {
+			assert.Equal(t, []int{2021, 2020}, []int{weeks[0].Year, weeks[1].Year})
+			assert.Equal(t, []int{1, 53}, []int{weeks[0].Week, weeks[1].Week})
+			assert.Equal(t, []int{1, 3}, []int{weeks[0].Count, weeks[1].Count})
+			assert.Equal(t, "2021-01-03", weeks[0].Start.Format("2006-01-02"))
+			assert.Equal(t, "2020-12-27", weeks[1].Start.Format("2006-01-02"))
+			assert.Equal(t, dates[3], weeks[1].First)
+		}
+	})
+}
@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,7 +12,9 @@ import (
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/pkg/capture"
+	"github.com/photoprism/photoprism/pkg/colors"
 	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/s2"
 	"github.com/photoprism/photoprism/pkg/txt"
 	"github.com/ulule/deepcopier"
 )
@@ -18,33 +22,38 @@ import (
 // PhotoResult contains found photos and their main file plus other meta data.
 type PhotoResult struct {
 	// Photo
-	ID               uint
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
-	DeletedAt        time.Time
-	TakenAt          time.Time
-	TakenAtLocal     time.Time
-	TakenSrc         string
-	TimeZone         string
-	PhotoUUID        string
-	PhotoPath        string
-	PhotoName        string
-	PhotoTitle       string
-	PhotoYear        int
-	PhotoMonth       int
-	PhotoCountry     string
-	PhotoFavorite    bool
-	PhotoPrivate     bool
-	PhotoLat         float32
-	PhotoLng         float32
-	PhotoAltitude    int
-	PhotoIso         int
-	PhotoFocalLength int
-	PhotoFNumber     float32
-	PhotoExposure    string
-	PhotoQuality     int
-	PhotoResolution  int
-	Merged           bool
+	ID                 uint
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          time.Time
+	TakenAt            time.Time
+	TakenAtLocal       time.Time
+	TakenSrc           string
+	TimeZone           string
+	PhotoUUID          string
+	PhotoRoot          string
+	PhotoPath          string
+	PhotoName          string
+	PhotoTitle         string
+	PhotoYear          int
+	PhotoMonth         int
+	PhotoCountry       string
+	PhotoFavorite      bool
+	PhotoPrivate       bool
+	PhotoLat           float32
+	PhotoLng           float32
+	PhotoAltitude      int
+	PhotoIso           int
+	PhotoFocalLength   int
+	PhotoFocalLength35 int
+	PhotoFNumber       float32
+	PhotoExposure      string
+	PhotoColors        string
+	PhotoLuminance     uint8
+	PhotoMono          bool
+	PhotoQuality       int
+	PhotoResolution    int
+	Merged             bool
 
 	// Camera
 	CameraID    uint
@@ -69,6 +78,7 @@ type PhotoResult struct {
 	FileUUID        string
 	FilePrimary     bool
 	FileMissing     bool
+	FileRoot        string
 	FileName        string
 	FileHash        string
 	FileType        string
@@ -87,9 +97,9 @@ type PhotoResult struct {
 
 type PhotoResults []PhotoResult
 
+// Merged combines the file rows of photos into a single result and returns the number of photos.
 func (m PhotoResults) Merged() (PhotoResults, int, error) {
-	count := len(m)
-	merged := make([]PhotoResult, 0, count)
+	merged := make([]PhotoResult, 0, len(m))
 
 	var lastId uint
 	var i int
@@ -98,7 +108,7 @@ func (m PhotoResults) Merged() (PhotoResults, int, error) {
 		file := entity.File{}
 
 		if err := deepcopier.Copy(&file).From(res); err != nil {
-			return merged, count, err
+			return merged, len(merged), err
 		}
 
 		file.ID = res.FileID
@@ -117,7 +127,7 @@ func (m PhotoResults) Merged() (PhotoResults, int, error) {
 		i++
 	}
 
-	return merged, count, nil
+	return merged, len(merged), nil
 }
 
 func (m *PhotoResult) ShareFileName() string {
@@ -139,19 +149,124 @@ func (m *PhotoResult) ShareFileName() string {
 
 // Photos searches for photos based on a Form and returns a PhotoResult slice.
 func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err error) {
-	if err := f.ParseQueryString(); err != nil {
+	s, err := q.photoSearch(&f)
+
+	if err != nil {
 		return results, 0, err
 	}
 
 	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("photos: %+v", f)))
 
-	s := q.db.NewScope(nil).DB()
+	if f.ID == "" && f.Merged {
+		// Merged results are paginated by photo, so that count, offset and total refer to photos.
+		ids, err := q.photoIDs(s, f.Offset, Limit(f.Count))
+
+		if err != nil {
+			return results, 0, err
+		}
+
+		if len(ids) == 0 {
+			return results, 0, nil
+		}
+
+		s = s.Where("photos.id IN (?)", ids)
+	} else if f.ID == "" {
+		s = s.Limit(Limit(f.Count)).Offset(f.Offset)
+	}
+
+	if result := s.Scan(&results); result.Error != nil {
+		return results, 0, result.Error
+	}
+
+	if f.Merged {
+		return results.Merged()
+	}
+
+	return results, len(results), nil
+}
+
+// PhotosTotal returns the total number of photo search results, ignoring count and offset. Merged
+// results are counted by photo, otherwise by file.
+func (q *Query) PhotosTotal(f form.PhotoSearch) (total int, err error) {
+	s, err := q.photoSearch(&f)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if f.Merged {
+		err = q.db.Raw("SELECT COUNT(DISTINCT results.id) FROM ? AS results", s.SubQuery()).Row().Scan(&total)
+
+		return total, err
+	}
+
+	return q.total(s)
+}
+
+// photoIDs returns the ids of the photos on a result page in search order, skipping additional files.
+func (q *Query) photoIDs(s *gorm.DB, offset, limit int) (ids []uint, err error) {
+	rows, err := s.Select("photos.id").Rows()
+
+	if err != nil {
+		return ids, err
+	}
+
+	defer rows.Close()
+
+	seen := make(map[uint]bool)
+
+	for rows.Next() && len(ids) < limit {
+		var id uint
+
+		if err := rows.Scan(&id); err != nil {
+			return ids, err
+		}
+
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		if len(seen) > offset {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, rows.Err()
+}
+
+// photoSearch returns the filtered and sorted photo search query without limit and offset.
+func (q *Query) photoSearch(f *form.PhotoSearch) (s *gorm.DB, err error) {
+	if err := f.ParseQueryString(); err != nil {
+		return s, err
+	}
+
+	album, err := q.searchAlbum(f)
+
+	if err != nil {
+		return s, err
+	}
+
+	takenFrom, takenTo, err := f.TakenRange()
+
+	if err != nil {
+		return s, err
+	}
+
+	focalMin, focalMax, err := f.FocalRange()
+
+	if err != nil {
+		return s, err
+	}
+
+	s = q.db.NewScope(nil).DB()
 
 	// s.LogMode(true)
 
 	s = s.Table("photos").
 		Select(`photos.*,
-		files.id AS file_id, files.file_uuid, files.file_primary, files.file_missing, files.file_name, files.file_hash, 
+		files.id AS file_id, files.file_uuid, files.file_primary, files.file_missing, files.file_root, files.file_name, files.file_hash, 
 		files.file_type, files.file_mime, files.file_width, files.file_height, files.file_aspect_ratio, 
 		files.file_orientation, files.file_main_color, files.file_colors, files.file_luminance, files.file_chroma,
 		files.file_diff,
@@ -164,21 +279,14 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		Joins("JOIN lenses ON lenses.id = photos.lens_id").
 		Joins("JOIN places ON photos.place_id = places.id").
 		Joins("LEFT JOIN photos_labels ON photos_labels.photo_id = photos.id AND photos_labels.uncertainty < 100").
-		Group("photos.id, files.id")
+		Group("photos.id, files.id").
+		Scopes(PhotosVisible(q.viewer))
 
 	if f.ID != "" {
 		s = s.Where("photos.photo_uuid = ?", f.ID)
 		s = s.Order("files.file_primary DESC")
 
-		if result := s.Scan(&results); result.Error != nil {
-			return results, 0, result.Error
-		}
-
-		if f.Merged {
-			return results.Merged()
-		}
-
-		return results, len(results), nil
+		return s, nil
 	}
 
 	var categories []entity.Category
@@ -189,7 +297,7 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		slugString := strings.ToLower(f.Label)
 		if result := q.db.First(&label, "label_slug =? OR custom_slug = ?", slugString, slugString); result.Error != nil {
 			log.Errorf("search: label \"%s\" not found", f.Label)
-			return results, 0, fmt.Errorf("label \"%s\" not found", f.Label)
+			return s, fmt.Errorf("label \"%s\" not found", f.Label)
 		} else {
 			labelIds = append(labelIds, label.ID)
 
@@ -203,22 +311,36 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		}
 	}
 
+	if f.Person != "" {
+		var slugs []string
+
+		for _, name := range strings.Split(f.Person, "|") {
+			if slugString := slug.Make(name); slugString != "" {
+				slugs = append(slugs, slugString)
+			}
+		}
+
+		// Person labels are only added for names found in face regions of the metadata.
+		s = s.Where("photos.id IN (SELECT pl.photo_id FROM photos_labels pl JOIN labels l ON l.id = pl.label_id WHERE pl.label_src = ? AND (l.label_slug IN (?) OR l.custom_slug IN (?)))", entity.SrcXmp, slugs, slugs)
+	}
+
 	if f.Location == true {
 		s = s.Where("location_id > 0")
 
 		if f.Query != "" {
+			normalized := txt.Normalize(txt.Clip(f.Query, txt.ClipKeyword))
+
 			s = s.Joins("LEFT JOIN photos_keywords ON photos_keywords.photo_id = photos.id").
 				Joins("LEFT JOIN keywords ON photos_keywords.keyword_id = keywords.id").
-				Where("keywords.keyword LIKE ?", strings.ToLower(txt.Clip(f.Query, txt.ClipKeyword))+"%")
+				Where("keywords.keyword LIKE ? OR places.loc_search LIKE ?", normalized+"%", "%"+normalized+"%")
 		}
 	} else if f.Query != "" {
 		if len(f.Query) < 2 {
-			return results, 0, fmt.Errorf("query too short")
+			return s, fmt.Errorf("query too short")
 		}
 
 		slugString := slug.Make(f.Query)
-		lowerString := strings.ToLower(f.Query)
-		likeString := txt.Clip(lowerString, txt.ClipKeyword) + "%"
+		likeString := txt.Normalize(txt.Clip(f.Query, txt.ClipKeyword)) + "%"
 
 		s = s.Joins("LEFT JOIN photos_keywords ON photos_keywords.photo_id = photos.id").
 			Joins("LEFT JOIN keywords ON photos_keywords.keyword_id = keywords.id")
@@ -242,8 +364,10 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		}
 	}
 
-	if f.Archived {
-		s = s.Where("photos.deleted_at IS NOT NULL")
+	if f.Trashed {
+		s = s.Where("photos.trashed_at IS NOT NULL")
+	} else if f.Archived {
+		s = s.Where("photos.deleted_at IS NOT NULL AND photos.trashed_at IS NULL")
 	} else {
 		s = s.Where("photos.deleted_at IS NULL")
 
@@ -254,7 +378,7 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		}
 
 		if f.Review {
-			s = s.Where("photos.photo_quality < 3")
+			s = s.Where("photos.photo_quality < ?", ReviewQuality)
 		} else if f.Quality != 0 && f.Private == false {
 			s = s.Where("photos.photo_quality >= ?", f.Quality)
 		}
@@ -265,15 +389,43 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 	}
 
 	if f.Album != "" {
-		s = s.Joins("JOIN photos_albums ON photos_albums.photo_uuid = photos.photo_uuid").Where("photos_albums.album_uuid = ?", f.Album)
+		if album.ID == 0 {
+			// Unknown albums and albums not visible to the viewer have no photos.
+			s = s.Where("1 = 0")
+		} else if album.IsFolder() {
+			s = s.Where("photos.photo_root = ? AND photos.photo_path = ?", album.AlbumRoot, album.AlbumPath)
+		} else {
+			s = s.Joins("JOIN photos_albums ON photos_albums.photo_uuid = photos.photo_uuid").Where("photos_albums.album_uuid = ?", f.Album)
+		}
+	}
+
+	if f.Root != "" {
+		s = s.Where("photos.photo_root = ?", f.Root)
 	}
 
 	if f.Camera > 0 {
 		s = s.Where("photos.camera_id = ?", f.Camera)
 	}
 
-	if f.Lens > 0 {
-		s = s.Where("photos.lens_id = ?", f.Lens)
+	if f.Lens != "" {
+		if lensId, err := strconv.Atoi(f.Lens); err == nil {
+			s = s.Where("photos.lens_id = ?", lensId)
+		} else {
+			// Every word must be part of the lens make or model, e.g. "canon 35mm".
+			for _, word := range strings.Fields(strings.ToLower(f.Lens)) {
+				likeString := "%" + word + "%"
+				s = s.Where("LOWER(lenses.lens_make) LIKE ? OR LOWER(lenses.lens_model) LIKE ?", likeString, likeString)
+			}
+		}
+	}
+
+	if f.Serial != "" {
+		// Search values are lowercase while serials are mostly uppercase and compared as binary strings.
+		if prefix := strings.TrimSuffix(f.Serial, "*"); prefix != f.Serial {
+			s = s.Where("photos.camera_serial LIKE ? OR photos.camera_serial LIKE ?", prefix+"%", strings.ToUpper(prefix)+"%")
+		} else {
+			s = s.Where("photos.camera_serial IN (?, ?)", f.Serial, strings.ToUpper(f.Serial))
+		}
 	}
 
 	if f.Year > 0 {
@@ -285,10 +437,14 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 	}
 
 	if f.Color != "" {
-		s = s.Where("files.file_main_color = ?", strings.ToLower(f.Color))
+		// Photos indexed before dominant colors were added only have a main color. Dominant colors are
+		// stored as comma separated list, so that only complete names must be matched.
+		color := strings.ToLower(f.Color)
+		s = s.Where("files.file_main_color = ? OR photos.photo_colors = ? OR photos.photo_colors LIKE ? OR photos.photo_colors LIKE ? OR photos.photo_colors LIKE ?",
+			color, color, color+",%", "%,"+color, "%,"+color+",%")
 	}
 
-	if f.Favorites {
+	if f.Favorites || f.Favorite {
 		s = s.Where("photos.photo_favorite = 1")
 	}
 
@@ -301,7 +457,7 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 	}
 
 	if f.Title != "" {
-		s = s.Where("LOWER(photos.photo_title) LIKE ?", fmt.Sprintf("%%%s%%", strings.ToLower(f.Title)))
+		s = s.Where("photos.title_search LIKE ?", fmt.Sprintf("%%%s%%", txt.Normalize(f.Title)))
 	}
 
 	if f.Hash != "" {
@@ -324,6 +480,10 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		s = s.Where("files.file_chroma > 0 AND files.file_chroma <= ?", f.Chroma)
 	}
 
+	if f.Dark {
+		s = s.Where("photos.photo_colors <> '' AND photos.photo_luminance < ?", colors.DarkBrightness)
+	}
+
 	if f.Diff != 0 {
 		s = s.Where("files.file_diff = ?", f.Diff)
 	}
@@ -355,10 +515,76 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		s = s.Where("photos.photo_lng BETWEEN ? AND ?", lngMin, lngMax)
 	}
 
+	if f.S2 != "" {
+		s2Min, s2Max := s2.CellRange(f.S2)
+
+		if s2Min == "" {
+			return s, fmt.Errorf("invalid s2 cell \"%s\"", f.S2)
+		}
+
+		s = s.Where("photos.location_id BETWEEN ? AND ?", s2Min, s2Max)
+	}
+
+	if f.Bbox != "" {
+		west, south, east, north, err := f.Bounds()
+
+		if err != nil {
+			return s, err
+		}
+
+		// Cell ranges use the location index, coordinates make sure results are inside the box.
+		cellSql, cellArgs := cellRanges(s2.BBoxRanges(west, south, east, north))
+
+		s = s.Where(cellSql, cellArgs...).Where("photos.photo_lat BETWEEN ? AND ?", south, north)
+
+		if west <= east {
+			s = s.Where("photos.photo_lng BETWEEN ? AND ?", west, east)
+		} else {
+			s = s.Where("photos.photo_lng >= ? OR photos.photo_lng <= ?", west, east)
+		}
+	}
+
+	var distSql string
+	var distArgs []interface{}
+
+	if f.Center != "" {
+		lat, lng, err := f.CenterLatLng()
+
+		if err != nil {
+			return s, err
+		}
+
+		distSql, distArgs = distance(lat, lng)
+
+		if f.Radius > 0 {
+			// Degrees are compared, so photos across the antimeridian are not found.
+			maxDist := f.Radius / (s2.EarthRadius * math.Pi / 180)
+			cellSql, cellArgs := cellRanges(s2.CapRanges(lat, lng, f.Radius))
+
+			s = s.Where(cellSql, cellArgs...).Where(distSql+" <= ?", append(distArgs, maxDist*maxDist)...)
+		}
+	}
+
 	if !f.Before.IsZero() {
 		s = s.Where("photos.taken_at <= ?", f.Before.Format("2006-01-02"))
 	}
 
+	if !takenFrom.IsZero() {
+		s = s.Where("photos.taken_at >= ?", takenFrom.Format("2006-01-02"))
+	}
+
+	if !takenTo.IsZero() {
+		s = s.Where("photos.taken_at < ?", takenTo.AddDate(0, 0, 1).Format("2006-01-02"))
+	}
+
+	if focalMin > 0 {
+		s = s.Where("photos.photo_focal_length35 >= ?", focalMin)
+	}
+
+	if focalMax > 0 {
+		s = s.Where("photos.photo_focal_length35 <= ?", focalMax)
+	}
+
 	if !f.After.IsZero() {
 		s = s.Where("photos.taken_at >= ?", f.After.Format("2006-01-02"))
 	}
@@ -376,27 +602,44 @@ func (q *Query) Photos(f form.PhotoSearch) (results PhotoResults, count int, err
 		s = s.Order("taken_at, photos.photo_uuid, files.file_primary DESC")
 	case entity.SortOrderImported:
 		s = s.Order("photos.id DESC, files.file_primary DESC")
+	case entity.SortOrderDistance:
+		if distSql == "" {
+			return s, fmt.Errorf("sorting by distance requires a center")
+		}
+
+		s = s.Order(gorm.Expr(distSql+", taken_at DESC, files.file_primary DESC", distArgs...))
 	case entity.SortOrderSimilar:
 		s = s.Order("files.file_main_color, photos.location_id, files.file_diff, taken_at DESC, files.file_primary DESC")
 	default:
 		s = s.Order("taken_at DESC, photos.photo_uuid, files.file_primary DESC")
 	}
 
-	if f.Count > 0 && f.Count <= 1000 {
-		s = s.Limit(f.Count).Offset(f.Offset)
-	} else {
-		s = s.Limit(100).Offset(0)
-	}
+	return s, nil
+}
 
-	if result := s.Scan(&results); result.Error != nil {
-		return results, 0, result.Error
+// cellRanges returns a condition matching photos with a location id in one of the token ranges.
+func cellRanges(ranges [][2]string) (sql string, args []interface{}) {
+	if len(ranges) == 0 {
+		return "1 = 0", args
 	}
 
-	if f.Merged {
-		return results.Merged()
+	conditions := make([]string, len(ranges))
+
+	for i, r := range ranges {
+		conditions[i] = "photos.location_id BETWEEN ? AND ?"
+		args = append(args, r[0], r[1])
 	}
 
-	return results, len(results), nil
+	return strings.Join(conditions, " OR "), args
+}
+
+// distance returns an expression for the squared distance of photos from coordinates in degrees. It
+// uses an equirectangular approximation, which is accurate enough for sorting and nearby photos.
+func distance(lat, lng float64) (sql string, args []interface{}) {
+	scale := math.Cos(lat * math.Pi / 180)
+
+	return "((photos.photo_lat - ?) * (photos.photo_lat - ?) + (photos.photo_lng - ?) * (photos.photo_lng - ?) * ?)",
+		[]interface{}{lat, lat, lng, lng, scale * scale}
 }
 
 // PhotoByID returns a Photo based on the ID.
@@ -419,7 +662,7 @@ func (q *Query) PhotoByID(photoID uint64) (photo entity.Photo, err error) {
 
 // PhotoByUUID returns a Photo based on the UUID.
 func (q *Query) PhotoByUUID(photoUUID string) (photo entity.Photo, err error) {
-	if err := q.db.Unscoped().Where("photo_uuid = ?", photoUUID).
+	if err := q.db.Unscoped().Where("photo_uuid = ?", photoUUID).Scopes(PhotosVisible(q.viewer)).
 		Preload("Links").
 		Preload("Description").
 		Preload("Location").
@@ -437,7 +680,7 @@ func (q *Query) PhotoByUUID(photoUUID string) (photo entity.Photo, err error) {
 
 // PreloadPhotoByUUID returns a Photo based on the UUID with all dependencies preloaded.
 func (q *Query) PreloadPhotoByUUID(photoUUID string) (photo entity.Photo, err error) {
-	if err := q.db.Unscoped().Where("photo_uuid = ?", photoUUID).
+	if err := q.db.Unscoped().Where("photo_uuid = ?", photoUUID).Scopes(PhotosVisible(q.viewer)).
 		Preload("Labels", func(db *gorm.DB) *gorm.DB {
 			return db.Order("photos_labels.uncertainty ASC, photos_labels.label_id DESC")
 		}).
@@ -456,3 +699,14 @@ func (q *Query) PreloadPhotoByUUID(photoUUID string) (photo entity.Photo, err er
 
 	return photo, nil
 }
+
+// PhotosByUUIDs returns all photos with the given UUIDs that are visible to the viewer, including archived photos.
+func (q *Query) PhotosByUUIDs(photoUUIDs []string) (result []entity.Photo, err error) {
+	if len(photoUUIDs) == 0 {
+		return result, nil
+	}
+
+	err = q.db.Unscoped().Where("photo_uuid IN (?)", photoUUIDs).Scopes(PhotosVisible(q.viewer)).Find(&result).Error
+
+	return result, err
+}
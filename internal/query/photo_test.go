@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
 )
 
@@ -64,6 +65,24 @@ func TestQuery_PhotoByUUID(t *testing.T) {
 	})
 }
 
+func TestQuery_PhotosByUUIDs(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("photos found", func(t *testing.T) {
+		result, err := search.PhotosByUUIDs([]string{"659", "660", "99999"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := search.PhotosByUUIDs(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
 func TestQuery_PreloadPhotoByUUID(t *testing.T) {
 	conf := config.TestConfig()
 
@@ -175,6 +194,19 @@ func TestSearch_Photos(t *testing.T) {
 
 		t.Logf("results: %+v", photos)
 	})
+	t.Run("form.color partial name", func(t *testing.T) {
+		var f form.PhotoSearch
+		f.Color = "re"
+
+		photos, _, err := search.Photos(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Names like "green" and "red" must not match.
+		assert.Len(t, photos, 0)
+	})
 	t.Run("form.favorites", func(t *testing.T) {
 		var f form.PhotoSearch
 		f.Query = "favorites:true"
@@ -343,5 +375,310 @@ func TestSearch_Photos(t *testing.T) {
 
 		t.Logf("results: %+v", photos)
 	})
+	t.Run("form.taken range", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Query: "taken:2014-07-17..2015-11-11", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 2)
+	})
+	t.Run("form.taken open end", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Query: "taken:2015-01-01..", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 2)
+		assert.Equal(t, "660", photos[0].PhotoUUID)
+		assert.Equal(t, "659", photos[1].PhotoUUID)
+	})
+	t.Run("form.taken open start", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Query: "taken:..2014-07-17", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "658", photos[0].PhotoUUID)
+	})
+	t.Run("form.taken invalid date", func(t *testing.T) {
+		_, _, err := search.Photos(form.PhotoSearch{Query: "taken:2014-07-32..", Count: 10})
+
+		assert.EqualError(t, err, "invalid date \"2014-07-32\", use YYYY-MM-DD")
+	})
+	t.Run("form.serial", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Query: "serial:F17QK1ABGRY6", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "659", photos[0].PhotoUUID)
+	})
+	t.Run("form.serial prefix", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Query: "serial:0320*", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
 
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "658", photos[0].PhotoUUID)
+
+		photos, _, err = search.Photos(form.PhotoSearch{Query: "serial:0320", Count: 10})
+
+		assert.Nil(t, err)
+		assert.Len(t, photos, 0)
+	})
+	t.Run("form.lens", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Query: "lens:\"Canon 35mm\"", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "EF35mm f/2 IS USM", photos[0].LensModel)
+	})
+	t.Run("form.lens id", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Lens: "3", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "Apple", photos[0].LensMake)
+	})
+	t.Run("form.lens and taken and serial", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Query: "lens:iphone taken:2015-11-11 serial:f17qk*", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "Reunion", photos[0].PhotoTitle)
+
+		photos, _, err = search.Photos(form.PhotoSearch{Query: "lens:iphone taken:2014-07-17", Count: 10})
+
+		assert.Nil(t, err)
+		assert.Len(t, photos, 0)
+	})
+	t.Run("form.s2", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{S2: "4799fc", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "658", photos[0].PhotoUUID)
+
+		_, _, err = search.Photos(form.PhotoSearch{S2: "xyz", Count: 10})
+
+		assert.EqualError(t, err, "invalid s2 cell \"xyz\"")
+	})
+	t.Run("form.bbox", func(t *testing.T) {
+		// Neckarbrücke is at 48.519235, 9.05799666.
+		photos, _, err := search.Photos(form.PhotoSearch{Bbox: "9.05,48.51,9.06,48.52", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "658", photos[0].PhotoUUID)
+
+		// Just outside the east and north edges.
+		photos, _, err = search.Photos(form.PhotoSearch{Bbox: "9.05,48.51,9.0579,48.52", Count: 10})
+
+		assert.Nil(t, err)
+		assert.Len(t, photos, 0)
+
+		photos, _, err = search.Photos(form.PhotoSearch{Bbox: "9.05,48.51,9.06,48.5192", Count: 10})
+
+		assert.Nil(t, err)
+		assert.Len(t, photos, 0)
+	})
+	t.Run("form.bbox antimeridian", func(t *testing.T) {
+		// Taveuni is at -16.8, 179.95.
+		photos, _, err := search.Photos(form.PhotoSearch{Bbox: "179.9,-17,-179.9,-16.5", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "660", photos[0].PhotoUUID)
+
+		photos, _, err = search.Photos(form.PhotoSearch{Bbox: "179.96,-17,-179.9,-16.5", Count: 10})
+
+		assert.Nil(t, err)
+		assert.Len(t, photos, 0)
+	})
+	t.Run("form.bbox invalid", func(t *testing.T) {
+		_, _, err := search.Photos(form.PhotoSearch{Bbox: "9.05,48.51,9.06", Count: 10})
+
+		assert.EqualError(t, err, "invalid bounding box \"9.05,48.51,9.06\", use west,south,east,north")
+
+		_, _, err = search.Photos(form.PhotoSearch{Bbox: "9.05,48.52,9.06,48.51", Count: 10})
+
+		assert.EqualError(t, err, "invalid bounding box \"9.05,48.52,9.06,48.51\", coordinates out of range")
+	})
+	t.Run("form.center and radius", func(t *testing.T) {
+		// About 1.1 km north of Neckarbrücke.
+		photos, _, err := search.Photos(form.PhotoSearch{Center: "48.529235,9.05799666", Radius: 1.2, Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+		assert.Equal(t, "658", photos[0].PhotoUUID)
+
+		photos, _, err = search.Photos(form.PhotoSearch{Center: "48.529235,9.05799666", Radius: 1, Count: 10})
+
+		assert.Nil(t, err)
+		assert.Len(t, photos, 0)
+	})
+	t.Run("form.center order by distance", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Center: "-20,50", Order: entity.SortOrderDistance, Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, photos, 3) {
+			assert.Equal(t, "659", photos[0].PhotoUUID)
+			assert.Equal(t, "658", photos[1].PhotoUUID)
+			assert.Equal(t, "660", photos[2].PhotoUUID)
+		}
+
+		_, _, err = search.Photos(form.PhotoSearch{Order: entity.SortOrderDistance, Count: 10})
+
+		assert.EqualError(t, err, "sorting by distance requires a center")
+	})
+}
+
+func TestLimit(t *testing.T) {
+	assert.Equal(t, DefaultResults, Limit(0))
+	assert.Equal(t, 25, Limit(25))
+	assert.Equal(t, MaxResults, Limit(5000))
+}
+
+func TestQuery_Photos_Normalized(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("title", func(t *testing.T) {
+		for _, title := range []string{"neckarbrucke", "NECKARBRÜCKE", "Brücke"} {
+			photos, _, err := search.Photos(form.PhotoSearch{Title: title, Count: 10})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if assert.Len(t, photos, 1, title) {
+				assert.Equal(t, "Neckarbrücke", photos[0].PhotoTitle)
+			}
+		}
+	})
+	t.Run("keyword", func(t *testing.T) {
+		for _, q := range []string{"neckarbrucke", "Neckarbrücke"} {
+			photos, _, err := search.Photos(form.PhotoSearch{Query: q, Count: 10})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if assert.Len(t, photos, 1, q) {
+				assert.Equal(t, "658", photos[0].PhotoUUID)
+			}
+		}
+	})
+}
+
+func TestQuery_Photos_Person(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("found", func(t *testing.T) {
+		for _, person := range []string{"Jane Doe", "jane doe", "John Doe|Jane Doe"} {
+			photos, _, err := search.Photos(form.PhotoSearch{Person: person, Count: 10})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if assert.Len(t, photos, 1, person) {
+				assert.Equal(t, "654", photos[0].PhotoUUID)
+			}
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		photos, _, err := search.Photos(form.PhotoSearch{Person: "Cake", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Empty(t, photos)
+	})
+}
+
+func TestQuery_PhotosTotal(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("merged", func(t *testing.T) {
+		f := form.PhotoSearch{Count: 1000, Merged: true}
+
+		photos, count, err := search.Photos(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		total, err := search.PhotosTotal(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, len(photos), count)
+		assert.Equal(t, count, total)
+	})
+	t.Run("merged page", func(t *testing.T) {
+		f := form.PhotoSearch{Count: 1, Offset: 1, Merged: true}
+
+		photos, count, err := search.Photos(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, count)
+
+		assert.Len(t, photos, 1)
+
+		all, _, err := search.Photos(form.PhotoSearch{Count: 1000, Merged: true})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.True(t, len(all) > 1) && assert.Len(t, photos, 1) {
+			assert.Equal(t, all[1].PhotoUUID, photos[0].PhotoUUID)
+			assert.Len(t, photos[0].Files, len(all[1].Files))
+		}
+	})
 }
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/maps"
+)
+
+// PlacesResult contains the number of locations that were refreshed, and that failed with an error.
+type PlacesResult struct {
+	Updated int
+	Failed  int
+}
+
+// RefreshPlaces resolves locations found by the offline geocoder again using the api and updates the
+// places of their photos. Locations stay offline while the api can't be reached. Errors are logged and
+// the remaining locations are refreshed anyway, so that a single failure doesn't stop a long run.
+func (q *Query) RefreshPlaces(api string) (result PlacesResult, err error) {
+	var locations []entity.Location
+
+	if err := q.db.Where("loc_source = ?", entity.LocSourceOffline).Order("id").Find(&locations).Error; err != nil {
+		return result, err
+	}
+
+	for _, l := range locations {
+		ok, err := l.Refresh(q.db, api)
+
+		if err != nil {
+			if maps.NotFound(err) {
+				continue
+			}
+
+			log.Errorf("places: could not refresh location %s (%s)", l.ID, err)
+			result.Failed++
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := q.db.Model(&entity.Photo{}).Where("location_id = ?", l.ID).UpdateColumns(map[string]interface{}{
+			"place_id":      l.PlaceID,
+			"photo_country": l.CountryCode(),
+		}).Error; err != nil {
+			log.Errorf("places: could not update photos of location %s (%s)", l.ID, err)
+			result.Failed++
+			continue
+		}
+
+		result.Updated++
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/maps/places"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_RefreshPlaces(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	available := false
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"id": %q, "name": "Brandenburger Tor", "category": "tourism", "place": {"id": "de:stub-mitte", "label": "Mitte, Berlin, Germany", "city": "Mitte", "state": "Berlin", "country": "de"}}`, path.Base(r.URL.Path))
+	}))
+
+	defer srv.Close()
+
+	defaultURL := places.ReverseLookupURL
+	places.SetEndpoint(srv.URL)
+	defer func() { places.ReverseLookupURL = defaultURL }()
+
+	photo := &entity.Photo{PhotoLat: 52.5163, PhotoLng: 13.3777}
+	photo.UpdateLocation(db, "places")
+
+	if err := db.Create(photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Unscoped().Delete(photo)
+	defer db.Unscoped().Delete(&entity.Location{ID: photo.LocationID})
+
+	t.Run("offline", func(t *testing.T) {
+		assert.Equal(t, entity.LocSourceOffline, photo.Location.Source())
+		assert.Equal(t, "Berlin", photo.Location.City())
+		assert.Equal(t, "de", photo.PhotoCountry)
+	})
+	t.Run("still unreachable", func(t *testing.T) {
+		refreshed, err := New(db).RefreshPlaces("places")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 0, refreshed.Updated)
+		assert.Equal(t, 0, refreshed.Failed)
+	})
+	t.Run("upgraded", func(t *testing.T) {
+		available = true
+
+		refreshed, err := New(db).RefreshPlaces("places")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, refreshed.Updated)
+		assert.Equal(t, 0, refreshed.Failed)
+
+		var result entity.Photo
+
+		if err := db.Preload("Location").Preload("Place").First(&result, "id = ?", photo.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "places", result.Location.Source())
+		assert.Equal(t, "Brandenburger Tor", result.Location.Name())
+		assert.Equal(t, "Mitte, Berlin, Germany", result.Place.Label())
+		assert.Equal(t, "de", result.PhotoCountry)
+	})
+}
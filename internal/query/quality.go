@@ -0,0 +1,102 @@
+package query
+
+import (
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+// qualityBatchSize is the number of photos loaded at once when quality scores are calculated again.
+const qualityBatchSize = 500
+
+// UpdateQuality calculates the quality score of photos again, e.g. after old scans were geotagged or the
+// weights were changed. All photos including archived ones are updated if the filter is empty, otherwise
+// only photos matching the search filter, like "year:2010 review:true". Returns the UUIDs of changed photos.
+func (q *Query) UpdateQuality(filter string) (updated []string, err error) {
+	if filter == "" {
+		for lastID := uint(0); ; {
+			var photos []entity.Photo
+
+			if err := q.db.Unscoped().Preload("Description").
+				Where("id > ?", lastID).Order("id").Limit(qualityBatchSize).
+				Find(&photos).Error; err != nil {
+				return updated, err
+			}
+
+			if len(photos) == 0 {
+				return updated, nil
+			}
+
+			lastID = photos[len(photos)-1].ID
+
+			if updated, err = q.updateQuality(photos, updated); err != nil {
+				return updated, err
+			}
+		}
+	}
+
+	f := form.NewPhotoSearch(filter)
+
+	s, err := q.photoSearch(&f)
+
+	if err != nil {
+		return updated, err
+	}
+
+	var found []uint
+
+	if err := s.Pluck("photos.id", &found).Error; err != nil {
+		return updated, err
+	}
+
+	// Search results contain a row per file, so photo ids may be listed more than once.
+	ids := make([]uint, 0, len(found))
+	seen := make(map[uint]bool, len(found))
+
+	for _, id := range found {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for i := 0; i < len(ids); i += qualityBatchSize {
+		j := i + qualityBatchSize
+
+		if j > len(ids) {
+			j = len(ids)
+		}
+
+		var photos []entity.Photo
+
+		if err := q.db.Unscoped().Preload("Description").
+			Where("id IN (?)", ids[i:j]).Order("id").
+			Find(&photos).Error; err != nil {
+			return updated, err
+		}
+
+		if updated, err = q.updateQuality(photos, updated); err != nil {
+			return updated, err
+		}
+	}
+
+	return updated, nil
+}
+
+// updateQuality saves the quality score of photos if it has changed and appends their UUIDs to updated.
+func (q *Query) updateQuality(photos []entity.Photo, updated []string) ([]string, error) {
+	for _, photo := range photos {
+		quality := photo.QualityScore()
+
+		if quality == photo.PhotoQuality {
+			continue
+		}
+
+		if err := q.db.Unscoped().Model(&photo).UpdateColumn("photo_quality", quality).Error; err != nil {
+			return updated, err
+		}
+
+		updated = append(updated, photo.PhotoUUID)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_UpdateQuality(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	taken := time.Date(2015, 11, 11, 9, 7, 18, 0, time.UTC)
+	photo := &entity.Photo{PhotoTitle: "Old Scan", TakenAt: taken, TakenAtLocal: taken, TakenSrc: entity.SrcAuto, CameraID: 2, LensID: 2, PhotoQuality: 1}
+
+	if err := db.Create(photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Unscoped().Delete(photo)
+
+	// Search results only contain photos with a file.
+	file := &entity.File{PhotoID: photo.ID, PhotoUUID: photo.PhotoUUID, FileName: "quality/old-scan.jpg", FileHash: "quality-old-scan", FileType: "jpg", FilePrimary: true}
+
+	if err := db.Create(file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Unscoped().Delete(file)
+
+	// Only the test photo is updated, so that fixtures used by other tests remain unchanged.
+	filter := "id:" + photo.PhotoUUID
+
+	quality := func() int {
+		var result entity.Photo
+
+		if err := db.Unscoped().Where("id = ?", photo.ID).First(&result).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		return result.PhotoQuality
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		updated, err := New(db).UpdateQuality(filter)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotContains(t, updated, photo.PhotoUUID)
+		assert.Equal(t, 1, quality())
+	})
+	t.Run("location added", func(t *testing.T) {
+		if err := db.Model(photo).UpdateColumns(map[string]interface{}{"photo_lat": -21.342636, "photo_lng": 55.466944}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		updated, err := New(db).UpdateQuality(filter)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Contains(t, updated, photo.PhotoUUID)
+		assert.Equal(t, 2, quality())
+	})
+	t.Run("weights changed", func(t *testing.T) {
+		defer func() { entity.QualityWeight = entity.DefaultQualityWeights }()
+
+		entity.QualityWeight.Location = 3
+
+		updated, err := New(db).UpdateQuality(filter)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Contains(t, updated, photo.PhotoUUID)
+		assert.Equal(t, 4, quality())
+	})
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := New(db).UpdateQuality("label:xxx")
+
+		assert.EqualError(t, err, "label \"xxx\" not found")
+	})
+}
@@ -18,9 +18,27 @@ var log = event.Log
 // About 1km ('good enough' for now)
 const SearchRadius = 0.009
 
+// Default and max number of photo search results.
+const (
+	DefaultResults = 100
+	MaxResults     = 1000
+)
+
+// Limit returns the number of results returned for a requested count.
+func Limit(count int) int {
+	if count <= 0 {
+		return DefaultResults
+	} else if count > MaxResults {
+		return MaxResults
+	}
+
+	return count
+}
+
 // Query searches given an originals path and a db instance.
 type Query struct {
-	db *gorm.DB
+	db     *gorm.DB
+	viewer *Viewer
 }
 
 // SearchCount is the total number of search hits.
@@ -28,6 +46,13 @@ type SearchCount struct {
 	Total int
 }
 
+// total returns the number of rows a search query returns without limit and offset.
+func (q *Query) total(s *gorm.DB) (count int, err error) {
+	err = q.db.Raw("SELECT COUNT(*) FROM ? AS results", s.SubQuery()).Row().Scan(&count)
+
+	return count, err
+}
+
 // New returns a new Query type with a given path and db instance.
 func New(db *gorm.DB) *Query {
 	q := &Query{
@@ -0,0 +1,100 @@
+package query
+
+// StatsYear contains the number of photos taken in a year, 0 means unknown.
+type StatsYear struct {
+	PhotoYear int
+	Count     int
+}
+
+// StatsCamera contains the number of photos taken with a camera.
+type StatsCamera struct {
+	CameraID    uint
+	CameraMake  string
+	CameraModel string
+	Count       int
+}
+
+// StatsIndex contains the number of index problems that may need attention.
+type StatsIndex struct {
+	FileErrors      int
+	FilesMissing    int
+	WithoutLocation int
+}
+
+// Stats contains photo counts and storage statistics of the library.
+type Stats struct {
+	Photos         int
+	Videos         int
+	Files          int
+	Favorites      int
+	Private        int
+	OriginalsBytes int64
+	CacheBytes     int64
+	Years          []StatsYear
+	Cameras        []StatsCamera
+	Index          StatsIndex
+}
+
+// Stats returns photo counts and storage statistics, deleted photos and files are not counted.
+// Photos without year or camera are counted with year and camera id 0.
+// The cache size is not known to the database and must be set by the caller.
+func (q *Query) Stats() (result Stats, err error) {
+	db := q.db.NewScope(nil).DB()
+
+	if err := db.Table("photos").
+		Select("COUNT(*) AS photos, COALESCE(SUM(photo_favorite), 0) AS favorites, COALESCE(SUM(photo_private), 0) AS private").
+		Where("deleted_at IS NULL").
+		Take(&result).Error; err != nil {
+		return result, err
+	}
+
+	if err := db.Table("files").
+		Select("COUNT(*) AS files, COALESCE(SUM(file_size), 0) AS originals_bytes").
+		Where("deleted_at IS NULL").
+		Take(&result).Error; err != nil {
+		return result, err
+	}
+
+	if err := db.Table("files").
+		Select("COUNT(DISTINCT files.photo_id) AS videos").
+		Joins("JOIN photos ON photos.id = files.photo_id").
+		Where("files.file_video = 1 AND files.deleted_at IS NULL AND photos.deleted_at IS NULL").
+		Take(&result).Error; err != nil {
+		return result, err
+	}
+
+	if err := db.Table("photos").
+		Select("COALESCE(photo_year, 0) AS photo_year, COUNT(*) AS count").
+		Where("deleted_at IS NULL").
+		Group("COALESCE(photo_year, 0)").
+		Order("photo_year DESC").
+		Scan(&result.Years).Error; err != nil {
+		return result, err
+	}
+
+	if err := db.Table("photos").
+		Select("COALESCE(photos.camera_id, 0) AS camera_id, COALESCE(cameras.camera_make, '') AS camera_make, COALESCE(cameras.camera_model, '') AS camera_model, COUNT(*) AS count").
+		Joins("LEFT JOIN cameras ON cameras.id = photos.camera_id").
+		Where("photos.deleted_at IS NULL").
+		Group("COALESCE(photos.camera_id, 0), cameras.camera_make, cameras.camera_model").
+		Order("count DESC, camera_id").
+		Scan(&result.Cameras).Error; err != nil {
+		return result, err
+	}
+
+	if err := db.Table("files").
+		Select("COALESCE(SUM(file_error <> ''), 0) AS file_errors, COALESCE(SUM(file_missing), 0) AS files_missing").
+		Where("deleted_at IS NULL").
+		Take(&result.Index).Error; err != nil {
+		return result, err
+	}
+
+	if err := db.Table("photos").
+		Select("COUNT(*) AS without_location").
+		Where("deleted_at IS NULL AND photo_lat = 0 AND photo_lng = 0").
+		Take(&result.Index).Error; err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Stats(t *testing.T) {
+	conf := config.TestConfig()
+
+	q := New(conf.Db())
+
+	result, err := q.Stats()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 7, result.Photos)
+	assert.Equal(t, 0, result.Videos)
+	assert.Equal(t, 6, result.Files)
+	assert.Equal(t, 0, result.Favorites)
+	assert.Equal(t, 0, result.Private)
+	assert.Equal(t, int64(0), result.OriginalsBytes)
+	assert.Equal(t, int64(0), result.CacheBytes)
+
+	assert.Equal(t, []StatsYear{
+		{PhotoYear: 2790, Count: 2},
+		{PhotoYear: 1990, Count: 2},
+		{PhotoYear: 0, Count: 3},
+	}, result.Years)
+
+	if assert.Len(t, result.Cameras, 4) {
+		assert.Equal(t, StatsCamera{CameraID: 0, Count: 4}, result.Cameras[0])
+		assert.Equal(t, StatsCamera{CameraID: 1, CameraModel: "Unknown", Count: 1}, result.Cameras[1])
+		assert.Equal(t, StatsCamera{CameraID: 2, CameraMake: "Apple", CameraModel: "iPhone SE", Count: 1}, result.Cameras[2])
+		assert.Equal(t, StatsCamera{CameraID: 5, CameraMake: "Canon", CameraModel: "EOS 6D", Count: 1}, result.Cameras[3])
+	}
+
+	assert.Equal(t, StatsIndex{FileErrors: 0, FilesMissing: 0, WithoutLocation: 0}, result.Index)
+}
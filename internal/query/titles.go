@@ -0,0 +1,65 @@
+package query
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// titleBatchSize is the number of photos loaded at once when titles are generated again.
+const titleBatchSize = 500
+
+// UpdateTitles generates new titles for all photos with generated titles, e.g. after the title
+// template was changed. Manually edited titles are never changed. Returns the number of updated photos.
+func (q *Query) UpdateTitles() (updated int, err error) {
+	for offset := 0; ; offset += titleBatchSize {
+		var photos []entity.Photo
+
+		// Titles remain generated after they were updated, so the offset is stable.
+		if err := q.db.Where("title_src = ?", entity.SrcAuto).
+			Preload("Location").
+			Preload("Location.Place").
+			Preload("Labels", func(db *gorm.DB) *gorm.DB {
+				return db.Order("photos_labels.uncertainty ASC, photos_labels.label_id DESC")
+			}).
+			Preload("Labels.Label").
+			Order("id").Limit(titleBatchSize).Offset(offset).
+			Find(&photos).Error; err != nil {
+			return updated, err
+		}
+
+		for _, photo := range photos {
+			title := photo.PhotoTitle
+
+			if err := photo.UpdateTitle(titleLabels(photo)); err != nil || photo.PhotoTitle == title {
+				continue
+			}
+
+			if err := q.db.Model(&photo).UpdateColumns(map[string]interface{}{
+				"photo_title":  photo.PhotoTitle,
+				"title_search": txt.Normalize(photo.PhotoTitle),
+				"title_src":    photo.TitleSrc,
+			}).Error; err != nil {
+				return updated, err
+			}
+
+			updated++
+		}
+
+		if len(photos) < titleBatchSize {
+			return updated, nil
+		}
+	}
+}
+
+// titleLabels returns the labels of a photo, ignoring labels that don't exist anymore.
+func titleLabels(photo entity.Photo) (labels classify.Labels) {
+	for _, l := range photo.Labels {
+		if l.Label != nil {
+			labels = append(labels, l.ClassifyLabel())
+		}
+	}
+
+	return labels
+}
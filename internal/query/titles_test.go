@@ -0,0 +1,60 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/txt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_UpdateTitles(t *testing.T) {
+	conf := config.TestConfig()
+	db := conf.Db()
+
+	template := entity.TitleTemplate
+	entity.TitleTemplate = "Updated {year}"
+
+	defer func() { entity.TitleTemplate = template }()
+
+	taken := time.Date(2020, 8, 6, 12, 0, 0, 0, time.UTC)
+	auto := &entity.Photo{PhotoTitle: "Unknown / 2020", TitleSrc: entity.SrcAuto, TakenAt: taken, TakenAtLocal: taken}
+	manual := &entity.Photo{PhotoTitle: "Our Trip", TitleSrc: entity.SrcManual, TakenAt: taken, TakenAtLocal: taken}
+
+	for _, p := range []*entity.Photo{auto, manual} {
+		if err := db.Create(p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func() {
+		db.Unscoped().Delete(auto)
+		db.Unscoped().Delete(manual)
+	}()
+
+	updated, err := New(db).UpdateTitles()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.GreaterOrEqual(t, updated, 1)
+
+	var result entity.Photo
+
+	if err := db.Where("id = ?", auto.ID).First(&result).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, txt.TitleTemplate("Updated {year}").Format(nil, taken, entity.TitleWeekStart, entity.TitleLanguage), result.PhotoTitle)
+	assert.Equal(t, entity.SrcAuto, result.TitleSrc)
+
+	if err := db.Where("id = ?", manual.ID).First(&result).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Our Trip", result.PhotoTitle)
+	assert.Equal(t, entity.SrcManual, result.TitleSrc)
+}
@@ -0,0 +1,137 @@
+package remote
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Conflict policies for two-way sync, used if a file was changed locally and remotely since the last sync.
+const (
+	ConflictNewestWins = "newest-wins"
+	ConflictKeepBoth   = "keep-both"
+	ConflictLocalWins  = "local-wins"
+)
+
+// SyncAction is the action required to bring a local and a remote file in sync again.
+type SyncAction string
+
+const (
+	SyncNone         SyncAction = ""
+	SyncDownload     SyncAction = "download"
+	SyncUpload       SyncAction = "upload"
+	SyncKeepBoth     SyncAction = "keep-both"
+	SyncDeleteLocal  SyncAction = "delete-local"
+	SyncDeleteRemote SyncAction = "delete-remote"
+	SyncForget       SyncAction = "forget"
+)
+
+// Version describes the state of a local or remote file, the entity tag is optional.
+type Version struct {
+	ETag string
+	Date time.Time
+	Size int64
+}
+
+// Changed returns true if a file changed compared to the version seen at the last sync. Entity tags
+// are compared if both versions have one, otherwise modification time and size.
+func (v Version) Changed(last Version) bool {
+	if v.ETag != "" && last.ETag != "" {
+		return v.ETag != last.ETag
+	}
+
+	return v.Size != last.Size || v.Date.Unix() != last.Date.Unix()
+}
+
+// Unknown returns true if the version wasn't recorded, e.g. for files synced before two-way sync was supported.
+func (v Version) Unknown() bool {
+	return v.ETag == "" && v.Date.IsZero() && v.Size == 0
+}
+
+// ValidConflictPolicy returns true if the conflict policy is supported, an empty policy
+// defaults to newest-wins.
+func ValidConflictPolicy(policy string) bool {
+	switch policy {
+	case "", ConflictNewestWins, ConflictKeepBoth, ConflictLocalWins:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reconcile compares the local and the remote version of a file with the versions seen at the last sync
+// and returns the action required to get both in sync again. Nil means the file doesn't exist anymore.
+// Deletions are only propagated if deletes is true, changes always win over deletions. If the local version
+// seen at the last sync is unknown, the local file is considered unchanged, so that only remote changes are synced.
+func Reconcile(lastLocal, lastRemote Version, local, remote *Version, policy string, deletes bool) SyncAction {
+	if lastLocal.Unknown() && local != nil {
+		lastLocal = *local
+	}
+
+	switch {
+	case local == nil && remote == nil:
+		return SyncForget
+	case local == nil:
+		if remote.Changed(lastRemote) {
+			return SyncDownload
+		} else if deletes {
+			return SyncDeleteRemote
+		}
+
+		return SyncNone
+	case remote == nil:
+		if local.Changed(lastLocal) {
+			return SyncUpload
+		} else if deletes {
+			return SyncDeleteLocal
+		}
+
+		return SyncNone
+	}
+
+	localChanged := local.Changed(lastLocal)
+	remoteChanged := remote.Changed(lastRemote)
+
+	switch {
+	case localChanged && remoteChanged:
+		return resolveConflict(local, remote, policy)
+	case localChanged:
+		return SyncUpload
+	case remoteChanged:
+		return SyncDownload
+	default:
+		return SyncNone
+	}
+}
+
+// resolveConflict returns the action for a file that was changed on both sides.
+func resolveConflict(local, remote *Version, policy string) SyncAction {
+	switch policy {
+	case ConflictKeepBoth:
+		return SyncKeepBoth
+	case ConflictLocalWins:
+		return SyncUpload
+	default:
+		// The local file wins if both were modified at the same time.
+		if remote.Date.After(local.Date) {
+			return SyncDownload
+		}
+
+		return SyncUpload
+	}
+}
+
+// ConflictName returns the file name for keeping a conflicting version, for example
+// "/Photos/IMG_1234-conflict-20200501-230000.jpg". The suffix is added before the
+// first dot so that the copy is not grouped with the original as related file.
+func ConflictName(fileName string, modified time.Time) string {
+	dir, base := path.Split(fileName)
+	suffix := fmt.Sprintf("-conflict-%s", modified.UTC().Format("20060102-150405"))
+
+	if i := strings.Index(base, "."); i > 0 {
+		return dir + base[:i] + suffix + base[i:]
+	}
+
+	return dir + base + suffix
+}
@@ -0,0 +1,106 @@
+package remote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion_Changed(t *testing.T) {
+	date := time.Date(2020, 5, 1, 23, 0, 0, 0, time.UTC)
+
+	t.Run("etag", func(t *testing.T) {
+		last := Version{ETag: `"1"`, Date: date, Size: 10}
+
+		assert.False(t, Version{ETag: `"1"`, Date: date.Add(time.Hour), Size: 10}.Changed(last))
+		assert.True(t, Version{ETag: `"2"`, Date: date, Size: 10}.Changed(last))
+	})
+	t.Run("date and size", func(t *testing.T) {
+		last := Version{Date: date, Size: 10}
+
+		assert.False(t, Version{ETag: `"1"`, Date: date.Add(500 * time.Millisecond), Size: 10}.Changed(last))
+		assert.True(t, Version{Date: date.Add(time.Second), Size: 10}.Changed(last))
+		assert.True(t, Version{Date: date, Size: 11}.Changed(last))
+	})
+}
+
+func TestVersion_Unknown(t *testing.T) {
+	assert.True(t, Version{}.Unknown())
+	assert.False(t, Version{Size: 10}.Unknown())
+	assert.False(t, Version{Date: time.Now()}.Unknown())
+	assert.False(t, Version{ETag: `"1"`}.Unknown())
+}
+
+func TestValidConflictPolicy(t *testing.T) {
+	assert.True(t, ValidConflictPolicy(""))
+	assert.True(t, ValidConflictPolicy(ConflictNewestWins))
+	assert.True(t, ValidConflictPolicy(ConflictKeepBoth))
+	assert.True(t, ValidConflictPolicy(ConflictLocalWins))
+	assert.False(t, ValidConflictPolicy("remote-wins"))
+}
+
+func TestReconcile(t *testing.T) {
+	date := time.Date(2020, 5, 1, 23, 0, 0, 0, time.UTC)
+	lastLocal := Version{Date: date, Size: 100}
+	lastRemote := Version{ETag: `"a"`, Date: date, Size: 100}
+
+	localEdit := &Version{Date: date.Add(time.Hour), Size: 120}
+	remoteEdit := &Version{ETag: `"b"`, Date: date.Add(2 * time.Hour), Size: 130}
+
+	t.Run("unchanged", func(t *testing.T) {
+		local := lastLocal
+		remote := Version{ETag: `"a"`, Date: date.Add(time.Minute), Size: 100}
+
+		assert.Equal(t, SyncNone, Reconcile(lastLocal, lastRemote, &local, &remote, "", true))
+	})
+	t.Run("local changed", func(t *testing.T) {
+		remote := lastRemote
+		assert.Equal(t, SyncUpload, Reconcile(lastLocal, lastRemote, localEdit, &remote, "", false))
+	})
+	t.Run("remote changed", func(t *testing.T) {
+		local := lastLocal
+		assert.Equal(t, SyncDownload, Reconcile(lastLocal, lastRemote, &local, remoteEdit, "", false))
+	})
+	t.Run("conflict", func(t *testing.T) {
+		assert.Equal(t, SyncDownload, Reconcile(lastLocal, lastRemote, localEdit, remoteEdit, ConflictNewestWins, false))
+		assert.Equal(t, SyncDownload, Reconcile(lastLocal, lastRemote, localEdit, remoteEdit, "", false))
+		assert.Equal(t, SyncUpload, Reconcile(lastLocal, lastRemote, localEdit, remoteEdit, ConflictLocalWins, false))
+		assert.Equal(t, SyncKeepBoth, Reconcile(lastLocal, lastRemote, localEdit, remoteEdit, ConflictKeepBoth, false))
+
+		newerLocal := &Version{Date: date.Add(3 * time.Hour), Size: 120}
+		assert.Equal(t, SyncUpload, Reconcile(lastLocal, lastRemote, newerLocal, remoteEdit, ConflictNewestWins, false))
+	})
+	t.Run("remote deleted", func(t *testing.T) {
+		local := lastLocal
+
+		assert.Equal(t, SyncNone, Reconcile(lastLocal, lastRemote, &local, nil, "", false))
+		assert.Equal(t, SyncDeleteLocal, Reconcile(lastLocal, lastRemote, &local, nil, "", true))
+		assert.Equal(t, SyncUpload, Reconcile(lastLocal, lastRemote, localEdit, nil, "", true))
+	})
+	t.Run("local deleted", func(t *testing.T) {
+		remote := lastRemote
+
+		assert.Equal(t, SyncNone, Reconcile(lastLocal, lastRemote, nil, &remote, "", false))
+		assert.Equal(t, SyncDeleteRemote, Reconcile(lastLocal, lastRemote, nil, &remote, "", true))
+		assert.Equal(t, SyncDownload, Reconcile(lastLocal, lastRemote, nil, remoteEdit, "", true))
+	})
+	t.Run("both deleted", func(t *testing.T) {
+		assert.Equal(t, SyncForget, Reconcile(lastLocal, lastRemote, nil, nil, "", false))
+	})
+	t.Run("local version unknown", func(t *testing.T) {
+		remote := lastRemote
+
+		assert.Equal(t, SyncNone, Reconcile(Version{}, lastRemote, localEdit, &remote, "", true))
+		assert.Equal(t, SyncDownload, Reconcile(Version{}, lastRemote, localEdit, remoteEdit, "", true))
+		assert.Equal(t, SyncNone, Reconcile(Version{}, lastRemote, localEdit, nil, "", false))
+	})
+}
+
+func TestConflictName(t *testing.T) {
+	date := time.Date(2020, 5, 1, 23, 0, 5, 0, time.UTC)
+
+	assert.Equal(t, "/Photos/IMG_1234-conflict-20200501-230005.jpg", ConflictName("/Photos/IMG_1234.jpg", date))
+	assert.Equal(t, "/Photos/IMG_1234-conflict-20200501-230005.jpg.xmp", ConflictName("/Photos/IMG_1234.jpg.xmp", date))
+	assert.Equal(t, "/README-conflict-20200501-230005", ConflictName("/README", date))
+}
@@ -0,0 +1,244 @@
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/remote"
+	"github.com/stretchr/testify/assert"
+	dav "golang.org/x/net/webdav"
+)
+
+// scriptedServer is a WebDAV server whose files can be edited and deleted by tests,
+// as if another client changed them.
+type scriptedServer struct {
+	*httptest.Server
+	fs dav.FileSystem
+}
+
+func newScriptedServer() *scriptedServer {
+	memFS := dav.NewMemFS()
+
+	return &scriptedServer{
+		Server: httptest.NewServer(&dav.Handler{FileSystem: memFS, LockSystem: dav.NewMemLS()}),
+		fs:     memFS,
+	}
+}
+
+func (s *scriptedServer) edit(t *testing.T, name string, data []byte) {
+	f, err := s.fs.OpenFile(context.Background(), name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *scriptedServer) delete(t *testing.T, name string) {
+	if err := s.fs.RemoveAll(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// remoteVersion returns the version of a remote file as listed by the client, or nil if it doesn't exist.
+func remoteVersion(t *testing.T, c Client, dir, name string) *remote.Version {
+	files, err := c.Files(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range files {
+		if f.Name == name {
+			return &remote.Version{ETag: f.ETag, Date: f.Date, Size: f.Size}
+		}
+	}
+
+	return nil
+}
+
+// localVersion returns the version of a local file, or nil if it doesn't exist.
+func localVersion(t *testing.T, fileName string) *remote.Version {
+	info, err := os.Stat(fileName)
+
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	return &remote.Version{Date: info.ModTime(), Size: info.Size()}
+}
+
+func TestClient_TwoWaySync(t *testing.T) {
+	srv := newScriptedServer()
+
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "webdav-sync")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	c := New(srv.URL, "", "")
+	localName := filepath.Join(dir, "IMG_1234.jpg")
+	remoteName := "/Photos/IMG_1234.jpg"
+
+	if err := ioutil.WriteFile(localName, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.CreateDir("/Photos"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Upload(localName, remoteName); err != nil {
+		t.Fatal(err)
+	}
+
+	// Remember the versions seen after the initial sync.
+	var lastLocal, lastRemote remote.Version
+
+	synced := func() {
+		lastLocal = *localVersion(t, localName)
+
+		info, err := c.Stat(remoteName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lastRemote = remote.Version{ETag: info.ETag, Date: info.Date, Size: info.Size}
+	}
+
+	synced()
+
+	t.Run("stat", func(t *testing.T) {
+		info, err := c.Stat(remoteName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, remoteName, info.Abs)
+		assert.Equal(t, int64(8), info.Size)
+		assert.NotEmpty(t, info.ETag)
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		action := remote.Reconcile(lastLocal, lastRemote, localVersion(t, localName), remoteVersion(t, c, "/Photos", "IMG_1234.jpg"), "", true)
+
+		assert.Equal(t, remote.SyncNone, action)
+	})
+	t.Run("remote edit", func(t *testing.T) {
+		srv.edit(t, remoteName, []byte("edited remotely"))
+
+		action := remote.Reconcile(lastLocal, lastRemote, localVersion(t, localName), remoteVersion(t, c, "/Photos", "IMG_1234.jpg"), "", false)
+
+		if assert.Equal(t, remote.SyncDownload, action) {
+			assert.NoError(t, c.Download(remoteName, localName, true))
+
+			data, err := ioutil.ReadFile(localName)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "edited remotely", string(data))
+		}
+
+		synced()
+	})
+	t.Run("conflict keep both", func(t *testing.T) {
+		// Make sure the local modification time differs from the last sync.
+		time.Sleep(time.Second)
+
+		srv.edit(t, remoteName, []byte("edited remotely again"))
+
+		if err := ioutil.WriteFile(localName, []byte("edited locally"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		rv := remoteVersion(t, c, "/Photos", "IMG_1234.jpg")
+		action := remote.Reconcile(lastLocal, lastRemote, localVersion(t, localName), rv, remote.ConflictKeepBoth, false)
+
+		if !assert.Equal(t, remote.SyncKeepBoth, action) {
+			return
+		}
+
+		conflictName := remote.ConflictName(remoteName, rv.Date)
+
+		assert.NoError(t, c.Rename(remoteName, conflictName))
+		assert.NoError(t, c.Upload(localName, remoteName))
+
+		files, err := c.Files("/Photos")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, files, 2)
+
+		conflictFile := filepath.Join(dir, filepath.Base(conflictName))
+
+		assert.NoError(t, c.Download(conflictName, conflictFile, false))
+
+		data, err := ioutil.ReadFile(conflictFile)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "edited remotely again", string(data))
+
+		// Renaming must never overwrite existing files.
+		assert.Error(t, c.Rename(remoteName, conflictName))
+
+		synced()
+	})
+	t.Run("local wins", func(t *testing.T) {
+		time.Sleep(time.Second)
+
+		srv.edit(t, remoteName, []byte("edited remotely, lost"))
+
+		if err := ioutil.WriteFile(localName, []byte("edited locally, kept"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		action := remote.Reconcile(lastLocal, lastRemote, localVersion(t, localName), remoteVersion(t, c, "/Photos", "IMG_1234.jpg"), remote.ConflictLocalWins, false)
+
+		if assert.Equal(t, remote.SyncUpload, action) {
+			assert.NoError(t, c.Upload(localName, remoteName))
+
+			var buf bytes.Buffer
+
+			stream, err := c.client.ReadStream(remoteName)
+
+			if assert.NoError(t, err) {
+				_, _ = buf.ReadFrom(stream)
+				stream.Close()
+				assert.Equal(t, "edited locally, kept", buf.String())
+			}
+		}
+
+		synced()
+	})
+	t.Run("remote delete", func(t *testing.T) {
+		srv.delete(t, remoteName)
+
+		rv := remoteVersion(t, c, "/Photos", "IMG_1234.jpg")
+
+		assert.Nil(t, rv)
+		assert.Equal(t, remote.SyncNone, remote.Reconcile(lastLocal, lastRemote, localVersion(t, localName), rv, "", false))
+		assert.Equal(t, remote.SyncDeleteLocal, remote.Reconcile(lastLocal, lastRemote, localVersion(t, localName), rv, "", true))
+
+		_, err := c.Stat(remoteName)
+		assert.Error(t, err)
+	})
+}
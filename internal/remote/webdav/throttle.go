@@ -0,0 +1,159 @@
+package webdav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrDeadline is returned if a transfer was interrupted because the sync window closed.
+var ErrDeadline = errors.New("webdav: transfer interrupted, sync window closed")
+
+// maxChunk is the max number of bytes read or written at once if there's no rate limit.
+const maxChunk = 32 * 1024
+
+// Throttle limits the transfer rate of readers and writers and interrupts transfers
+// once a deadline was reached.
+type Throttle struct {
+	limiter  *rate.Limiter
+	deadline time.Time
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+// NewThrottle returns a new throttle with a rate limit in KB/s and an optional deadline,
+// a limit of 0 disables rate limiting.
+func NewThrottle(limit int, deadline time.Time) *Throttle {
+	t := &Throttle{
+		deadline: deadline,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+
+	if limit > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(limit*1024), limit*1024)
+	}
+
+	return t
+}
+
+// Expired returns true if the deadline was reached.
+func (t *Throttle) Expired() bool {
+	return !t.deadline.IsZero() && !t.now().Before(t.deadline)
+}
+
+// chunk returns the max number of bytes transferred between waits.
+func (t *Throttle) chunk() int {
+	if t.limiter != nil && t.limiter.Burst() < maxChunk {
+		return t.limiter.Burst()
+	}
+
+	return maxChunk
+}
+
+// wait blocks until n bytes may be transferred without exceeding the rate limit.
+func (t *Throttle) wait(n int) error {
+	if t.Expired() {
+		return ErrDeadline
+	}
+
+	if t.limiter == nil {
+		return nil
+	}
+
+	now := t.now()
+	r := t.limiter.ReserveN(now, n)
+
+	if !r.OK() {
+		return fmt.Errorf("webdav: can't transfer %d bytes at once", n)
+	}
+
+	delay := r.DelayFrom(now)
+
+	if !t.deadline.IsZero() && now.Add(delay).After(t.deadline) {
+		r.CancelAt(now)
+		return ErrDeadline
+	}
+
+	if delay > 0 {
+		t.sleep(delay)
+	}
+
+	return nil
+}
+
+// Reader returns a reader that is limited by the throttle.
+func (t *Throttle) Reader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+
+	return &throttledReader{r: r, t: t}
+}
+
+// Writer returns a writer that is limited by the throttle.
+func (t *Throttle) Writer(w io.Writer) io.Writer {
+	if t == nil {
+		return w
+	}
+
+	return &throttledWriter{w: w, t: t}
+}
+
+type throttledReader struct {
+	r io.Reader
+	t *Throttle
+}
+
+// Read reads up to one chunk and waits until the rate limit allows the bytes that were read.
+func (tr *throttledReader) Read(p []byte) (int, error) {
+	if chunk := tr.t.chunk(); len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := tr.r.Read(p)
+
+	if n > 0 {
+		if werr := tr.t.wait(n); werr != nil {
+			return n, werr
+		}
+	}
+
+	return n, err
+}
+
+type throttledWriter struct {
+	w io.Writer
+	t *Throttle
+}
+
+// Write writes p in chunks and waits for the rate limit before each chunk.
+func (tw *throttledWriter) Write(p []byte) (written int, err error) {
+	chunk := tw.t.chunk()
+
+	for len(p) > 0 {
+		n := len(p)
+
+		if n > chunk {
+			n = chunk
+		}
+
+		if err := tw.t.wait(n); err != nil {
+			return written, err
+		}
+
+		n, err = tw.w.Write(p[:n])
+		written += n
+
+		if err != nil {
+			return written, err
+		}
+
+		p = p[n:]
+	}
+
+	return written, nil
+}
@@ -0,0 +1,151 @@
+package webdav
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dav "golang.org/x/net/webdav"
+)
+
+// fakeClock replaces time.Now and time.Sleep of a throttle.
+type fakeClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func (c *fakeClock) install(t *Throttle) *Throttle {
+	t.now = func() time.Time { return c.now }
+	t.sleep = func(d time.Duration) {
+		c.slept += d
+		c.now = c.now.Add(d)
+	}
+
+	return t
+}
+
+func TestThrottle_Reader(t *testing.T) {
+	t.Run("limit", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2020, 5, 1, 23, 0, 0, 0, time.UTC)}
+		throttle := clock.install(NewThrottle(10, time.Time{}))
+
+		data := make([]byte, 100*1024)
+		result, err := ioutil.ReadAll(throttle.Reader(bytes.NewReader(data)))
+
+		assert.NoError(t, err)
+		assert.Len(t, result, len(data))
+
+		// The first 10 KB are allowed right away, the remaining 90 KB take 9 seconds.
+		assert.InDelta(t, float64(9*time.Second), float64(clock.slept), float64(time.Millisecond))
+	})
+	t.Run("unlimited", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+		throttle := clock.install(NewThrottle(0, time.Time{}))
+
+		_, err := ioutil.ReadAll(throttle.Reader(bytes.NewReader(make([]byte, 100*1024))))
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), clock.slept)
+	})
+	t.Run("deadline", func(t *testing.T) {
+		start := time.Date(2020, 5, 1, 6, 59, 55, 0, time.UTC)
+		clock := &fakeClock{now: start}
+		throttle := clock.install(NewThrottle(10, start.Add(5*time.Second)))
+
+		data, err := ioutil.ReadAll(throttle.Reader(bytes.NewReader(make([]byte, 100*1024))))
+
+		assert.Equal(t, ErrDeadline, err)
+		assert.True(t, len(data) < 100*1024)
+		assert.True(t, clock.slept <= 5*time.Second)
+	})
+}
+
+func TestThrottle_Writer(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 5, 1, 23, 0, 0, 0, time.UTC)}
+	throttle := clock.install(NewThrottle(20, time.Time{}))
+
+	var buf bytes.Buffer
+
+	n, err := throttle.Writer(&buf).Write(make([]byte, 100*1024))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100*1024, n)
+	assert.InDelta(t, float64(4*time.Second), float64(clock.slept), float64(time.Millisecond))
+}
+
+func TestClient_Throttle(t *testing.T) {
+	srv := httptest.NewServer(&dav.Handler{
+		FileSystem: dav.NewMemFS(),
+		LockSystem: dav.NewMemLS(),
+	})
+
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "webdav")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	localName := filepath.Join(dir, "upload.jpg")
+
+	if err := ioutil.WriteFile(localName, make([]byte, 64*1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(srv.URL, "", "")
+
+	t.Run("upload", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+		c.Throttle(clock.install(NewThrottle(16, time.Time{})), nil)
+
+		assert.NoError(t, c.Upload(localName, "/Photos/upload.jpg"))
+		assert.InDelta(t, float64(3*time.Second), float64(clock.slept), float64(time.Millisecond))
+	})
+	t.Run("download", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+		c.Throttle(nil, clock.install(NewThrottle(32, time.Time{})))
+
+		downloadName := filepath.Join(dir, "download.jpg")
+
+		assert.NoError(t, c.Download("/Photos/upload.jpg", downloadName, false))
+		assert.InDelta(t, float64(time.Second), float64(clock.slept), float64(time.Millisecond))
+
+		info, err := os.Stat(downloadName)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(64*1024), info.Size())
+		}
+	})
+	t.Run("download interrupted", func(t *testing.T) {
+		start := time.Now()
+		clock := &fakeClock{now: start}
+		c.Throttle(nil, clock.install(NewThrottle(16, start.Add(time.Second))))
+
+		downloadName := filepath.Join(dir, "interrupted.jpg")
+
+		assert.Equal(t, ErrDeadline, c.Download("/Photos/upload.jpg", downloadName, false))
+
+		_, err := os.Stat(downloadName)
+		assert.True(t, os.IsNotExist(err))
+
+		_, err = os.Stat(downloadName + ".part")
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("upload interrupted", func(t *testing.T) {
+		start := time.Now()
+		clock := &fakeClock{now: start}
+		throttle := clock.install(NewThrottle(16, start.Add(time.Second)))
+		c.Throttle(throttle, nil)
+
+		assert.Error(t, c.Upload(localName, "/Photos/interrupted.jpg"))
+		assert.True(t, throttle.Expired())
+	})
+}
@@ -9,7 +9,7 @@ package webdav
 
 import (
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"path"
 	"time"
@@ -22,7 +22,9 @@ import (
 var log = event.Log
 
 type Client struct {
-	client *gowebdav.Client
+	client   *gowebdav.Client
+	upload   *Throttle
+	download *Throttle
 }
 
 // New creates a new WebDAV client.
@@ -36,6 +38,12 @@ func New(url, user, pass string) Client {
 	return result
 }
 
+// Throttle limits uploads and downloads, nil disables a throttle.
+func (c *Client) Throttle(upload, download *Throttle) {
+	c.upload = upload
+	c.download = download
+}
+
 func (c Client) readDir(path string) ([]os.FileInfo, error) {
 	if path == "" {
 		path = "/"
@@ -100,7 +108,8 @@ func (c Client) Directories(root string, recursive bool) (result fs.FileInfos, e
 	return result, nil
 }
 
-// Download downloads a single file to the given location.
+// Download downloads a single file to the given location. The file is written to a temporary
+// file first, so that interrupted downloads don't leave incomplete files behind.
 func (c Client) Download(from, to string, force bool) error {
 	if _, err := os.Stat(to); err == nil && !force {
 		return fmt.Errorf("webdav: download skipped, %s already exists", to)
@@ -118,13 +127,34 @@ func (c Client) Download(from, to string, force bool) error {
 		return fmt.Errorf("webdav: %s is not a directory", dir)
 	}
 
-	bytes, err := c.client.Read(from)
+	stream, err := c.client.ReadStream(from)
+
+	if err != nil {
+		return err
+	}
+
+	defer stream.Close()
+
+	tmp := to + ".part"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(to, bytes, 0644)
+	if _, err := io.Copy(f, c.download.Reader(stream)); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, to)
 }
 
 // DownloadDir downloads all files from a remote to a local directory.
@@ -186,7 +216,23 @@ func (c Client) Upload(from, to string) error {
 
 	defer file.Close()
 
-	return c.client.WriteStream(to, file, 0644)
+	return c.client.WriteStream(to, c.upload.Reader(file), 0644)
+}
+
+// Stat returns information about a single remote file, including its entity tag if available.
+func (c Client) Stat(name string) (result fs.FileInfo, err error) {
+	file, err := c.client.Stat(name)
+
+	if err != nil {
+		return result, err
+	}
+
+	return fs.NewFileInfo(file, path.Dir(name)), nil
+}
+
+// Rename renames a single remote file, an existing file with the new name is not overwritten.
+func (c Client) Rename(from, to string) error {
+	return c.client.Rename(from, to, false)
 }
 
 // Delete deletes a single file or directory on a remote server.
@@ -0,0 +1,93 @@
+package remote
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window represents a daily time window like "23:00-07:00" in local time, e.g. for syncing
+// only at night. The zero value is always open.
+type Window struct {
+	start int // minutes after midnight
+	end   int // minutes after midnight
+	set   bool
+}
+
+// ParseWindow parses a time window like "23:00-07:00", an empty string is always open.
+func ParseWindow(s string) (w Window, err error) {
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		return w, nil
+	}
+
+	parts := strings.Split(s, "-")
+
+	if len(parts) != 2 {
+		return w, fmt.Errorf("invalid time window \"%s\", use a format like 23:00-07:00", s)
+	}
+
+	if w.start, err = parseWindowTime(parts[0]); err != nil {
+		return w, fmt.Errorf("invalid time window \"%s\", use a format like 23:00-07:00", s)
+	}
+
+	if w.end, err = parseWindowTime(parts[1]); err != nil {
+		return w, fmt.Errorf("invalid time window \"%s\", use a format like 23:00-07:00", s)
+	}
+
+	w.set = w.start != w.end
+
+	return w, nil
+}
+
+// parseWindowTime returns the minutes after midnight for a time like "07:00".
+func parseWindowTime(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Open returns true if t is inside the window.
+func (w Window) Open(t time.Time) bool {
+	if !w.set {
+		return true
+	}
+
+	m := t.Hour()*60 + t.Minute()
+
+	if w.start < w.end {
+		return m >= w.start && m < w.end
+	}
+
+	// The window spans midnight.
+	return m >= w.start || m < w.end
+}
+
+// End returns the time the window closes after t, or the zero time if it is always open.
+func (w Window) End(t time.Time) time.Time {
+	if !w.set {
+		return time.Time{}
+	}
+
+	end := time.Date(t.Year(), t.Month(), t.Day(), 0, w.end, 0, 0, t.Location())
+
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return end
+}
+
+// String returns the window in the format accepted by ParseWindow.
+func (w Window) String() string {
+	if !w.set {
+		return ""
+	}
+
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.start/60, w.start%60, w.end/60, w.end%60)
+}
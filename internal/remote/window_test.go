@@ -0,0 +1,57 @@
+package remote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWindow(t *testing.T) {
+	t.Run("overnight", func(t *testing.T) {
+		w, err := ParseWindow(" 23:00 - 07:30 ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "23:00-07:30", w.String())
+	})
+	t.Run("empty", func(t *testing.T) {
+		w, err := ParseWindow("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", w.String())
+		assert.True(t, w.Open(time.Now()))
+		assert.True(t, w.End(time.Now()).IsZero())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseWindow("23:00")
+		assert.EqualError(t, err, "invalid time window \"23:00\", use a format like 23:00-07:00")
+
+		_, err = ParseWindow("25:00-07:00")
+		assert.Error(t, err)
+	})
+}
+
+func TestWindow_Open(t *testing.T) {
+	day := func(hour, min int) time.Time {
+		return time.Date(2020, 5, 1, hour, min, 0, 0, time.UTC)
+	}
+
+	t.Run("overnight", func(t *testing.T) {
+		w, _ := ParseWindow("23:00-07:00")
+
+		assert.True(t, w.Open(day(23, 0)))
+		assert.True(t, w.Open(day(2, 15)))
+		assert.False(t, w.Open(day(7, 0)))
+		assert.False(t, w.Open(day(12, 0)))
+		assert.Equal(t, day(7, 0).AddDate(0, 0, 1), w.End(day(23, 30)))
+		assert.Equal(t, day(7, 0), w.End(day(2, 15)))
+	})
+	t.Run("daytime", func(t *testing.T) {
+		w, _ := ParseWindow("09:00-17:00")
+
+		assert.False(t, w.Open(day(8, 59)))
+		assert.True(t, w.Open(day(9, 0)))
+		assert.False(t, w.Open(day(17, 0)))
+		assert.Equal(t, day(17, 0), w.End(day(9, 0)))
+	})
+}
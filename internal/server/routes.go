@@ -19,13 +19,34 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 	v1 := router.Group("/api/v1")
 	{
 		api.GetStatus(v1, conf)
+		api.GetReady(v1, conf)
+
+		// Routes registered below are not available until the database is ready.
+		v1.Use(api.RequireReady(conf, config.SubsystemDatabase))
+
+		// Until setup is completed, only the setup wizard is available.
+		api.GetSetup(v1, conf)
+		api.CompleteSetup(v1, conf)
+
+		v1.Use(api.RequireSetup(conf))
+
+		// API tokens may be used instead of a session, viewers and uploaders may not modify photos, albums and labels.
+		v1.Use(api.ApiTokenAuth(conf), api.RoleAccess(conf))
+
+		// Background workers pause while authorized api requests are served.
+		v1.Use(api.TrackActivity(conf))
+
+		api.GetDoctor(v1, conf)
+		api.GetAbout(v1, conf)
 
 		api.CreateSession(v1, conf)
 		api.DeleteSession(v1, conf)
 
 		api.GetPreview(v1, conf)
 		api.GetThumbnail(v1, conf)
+		api.GetVideo(v1, conf)
 		api.GetDownload(v1, conf)
+		api.GetDownloadToken(v1, conf)
 		api.CreateZip(v1, conf)
 		api.DownloadZip(v1, conf)
 
@@ -41,9 +62,14 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.RemovePhotoLabel(v1, conf)
 		api.UpdatePhotoLabel(v1, conf)
 		api.GetMomentsTime(v1, conf)
+		api.GetMomentsWeeks(v1, conf)
 		api.GetFile(v1, conf)
 		api.LinkFile(v1, conf)
+		api.GetFileTimeline(v1, conf)
+		api.GetDuplicates(v1, conf)
+		api.GetStats(v1, conf)
 		api.SetPhotoPrimary(v1, conf)
+		api.UnstackFile(v1, conf)
 
 		api.GetLabels(v1, conf)
 		api.UpdateLabel(v1, conf)
@@ -52,17 +78,33 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.DislikeLabel(v1, conf)
 		api.LabelThumbnail(v1, conf)
 
-		api.Upload(v1, conf)
-		api.StartImport(v1, conf)
-		api.CancelImport(v1, conf)
-		api.StartIndexing(v1, conf)
-		api.CancelIndexing(v1, conf)
+		api.CreateUpload(v1, conf)
+		api.UploadOffset(v1, conf)
+		api.AppendUpload(v1, conf)
+		api.DeleteUpload(v1, conf)
+
+		// Importing and indexing requires TensorFlow models to be loaded.
+		models := v1.Group("", api.RequireReady(conf, config.SubsystemModels))
+		{
+			api.Upload(models, conf)
+			api.CompleteUpload(models, conf)
+			api.StartImport(models, conf)
+			api.CancelImport(v1, conf)
+			api.StartIndexing(models, conf)
+			api.CancelIndexing(v1, conf)
+			api.StartPurge(v1, conf)
+			api.UpdateTitles(v1, conf)
+			api.UpdateQuality(v1, conf)
+		}
 
 		api.BatchPhotosArchive(v1, conf)
 		api.BatchPhotosRestore(v1, conf)
+		api.BatchPhotosDelete(v1, conf)
 		api.BatchPhotosPrivate(v1, conf)
 		api.BatchPhotosStory(v1, conf)
+		api.BatchPhotosEdit(v1, conf)
 		api.BatchAlbumsDelete(v1, conf)
+		api.BatchAlbumsAccess(v1, conf)
 		api.BatchLabelsDelete(v1, conf)
 
 		api.GetAlbum(v1, conf)
@@ -70,6 +112,7 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.UpdateAlbum(v1, conf)
 		api.DeleteAlbum(v1, conf)
 		api.DownloadAlbum(v1, conf)
+		api.ExportAlbum(v1, conf)
 		api.GetAlbums(v1, conf)
 		api.LinkAlbum(v1, conf)
 		api.LikeAlbum(v1, conf)
@@ -77,6 +120,8 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.AlbumThumbnail(v1, conf)
 		api.AddPhotosToAlbum(v1, conf)
 		api.RemovePhotosFromAlbum(v1, conf)
+		api.GetAlbumAccess(v1, conf)
+		api.UpdateAlbumAccess(v1, conf)
 
 		api.GetAccounts(v1, conf)
 		api.GetAccount(v1, conf)
@@ -86,35 +131,44 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.DeleteAccount(v1, conf)
 		api.UpdateAccount(v1, conf)
 
+		api.GetShare(v1, conf)
+		api.UnlockShare(v1, conf)
+		api.GetSharePhotos(v1, conf)
+
 		api.GetSettings(v1, conf)
 		api.SaveSettings(v1, conf)
+		api.GetUserSettings(v1, conf)
+		api.SaveUserSettings(v1, conf)
+
+		api.GetApiTokens(v1, conf)
+		api.CreateApiToken(v1, conf)
+		api.DeleteApiToken(v1, conf)
+
+		api.GetLogs(v1, conf)
+		api.GetErrors(v1, conf)
 
 		api.GetSvg(v1)
 
 		api.Websocket(v1, conf)
 	}
 
-	// WebDAV server for file management / sharing
+	// WebDAV server for file management / sharing, users log in with their name and password.
 	if conf.WebDAVPassword() != "" {
 		log.Info("webdav: enabled, username: photoprism")
+	} else {
+		log.Info("webdav: enabled for users with a password")
+	}
 
-		WebDAV(conf.OriginalsPath(), router.Group("/originals", gin.BasicAuth(gin.Accounts{
-			"photoprism": conf.WebDAVPassword(),
-		})), conf)
-
-		log.Info("webdav: /originals/ available")
+	WebDAV(conf.OriginalsPath, router.Group("/originals", api.RequireReady(conf, config.SubsystemDatabase), api.RequireSetup(conf), webdavAuth(conf, false)), conf)
 
-		if conf.ReadOnly() {
-			log.Info("webdav: /import/ not available in read-only mode")
-		} else {
-			WebDAV(conf.ImportPath(), router.Group("/import", gin.BasicAuth(gin.Accounts{
-				"photoprism": conf.WebDAVPassword(),
-			})), conf)
+	log.Info("webdav: /originals/ available")
 
-			log.Info("webdav: /import/ available")
-		}
+	if conf.ReadOnly() {
+		log.Info("webdav: /import/ not available in read-only mode")
 	} else {
-		log.Info("webdav: disabled (no password set)")
+		WebDAV(conf.ImportPath, router.Group("/import", api.RequireReady(conf, config.SubsystemDatabase), api.RequireSetup(conf), webdavAuth(conf, true)), conf)
+
+		log.Info("webdav: /import/ available")
 	}
 
 	// Default HTML page (client-side routing implemented via Vue.js)
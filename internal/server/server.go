@@ -34,22 +34,38 @@ func Start(ctx context.Context, conf *config.Config) {
 
 	registerRoutes(router, conf)
 
+	// The same server can listen on a TCP port and a unix socket at the same time, e.g. for migrations.
 	server := &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", conf.HttpServerHost(), conf.HttpServerPort()),
 		Handler: router,
 	}
 
-	go func() {
-		log.Infof("starting web server at %s", server.Addr)
+	if conf.HttpServerTcp() {
+		server.Addr = fmt.Sprintf("%s:%d", conf.HttpServerHost(), conf.HttpServerPort())
 
-		if err := server.ListenAndServe(); err != nil {
-			if err == http.ErrServerClosed {
-				log.Info("web server shutdown complete")
+		go func() {
+			if conf.HttpServerTls() {
+				log.Infof("starting web server at %s with tls", server.Addr)
+				serveError(server.ListenAndServeTLS(conf.HttpServerCert(), conf.HttpServerKey()))
 			} else {
-				log.Errorf("web server closed unexpect: %s", err)
+				log.Infof("starting web server at %s", server.Addr)
+				serveError(server.ListenAndServe())
 			}
+		}()
+	}
+
+	if socket := conf.HttpServerSocket(); socket != "" {
+		if listener, err := ListenSocket(socket, conf.HttpSocketMode()); err != nil && !conf.HttpServerTcp() {
+			// The server would not be reachable at all without the socket.
+			log.Fatalf("web server: %s", err)
+		} else if err != nil {
+			log.Errorf("web server: %s", err)
+		} else {
+			go func() {
+				log.Infof("starting web server at unix socket %s", socket)
+				serveError(server.Serve(listener))
+			}()
 		}
-	}()
+	}
 
 	<-ctx.Done()
 	log.Info("shutting down web server")
@@ -58,3 +74,12 @@ func Start(ctx context.Context, conf *config.Config) {
 		log.Errorf("web server shutdown failed: %v", err)
 	}
 }
+
+// serveError logs the error returned once a listener of the web server stopped.
+func serveError(err error) {
+	if err == http.ErrServerClosed {
+		log.Info("web server shutdown complete")
+	} else if err != nil {
+		log.Errorf("web server closed unexpect: %s", err)
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// ListenSocket listens on a unix socket with the given file permissions. A stale socket left by a
+// previous process is removed, while sockets in use and other files are never replaced.
+func ListenSocket(fileName string, mode os.FileMode) (net.Listener, error) {
+	if info, err := os.Lstat(fileName); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", fileName)
+		}
+
+		if conn, err := net.Dial("unix", fileName); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is already in use", fileName)
+		}
+
+		if err := os.Remove(fileName); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(fileName, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
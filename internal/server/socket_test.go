@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/api"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "photoprism-socket")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "run", "http.sock")
+
+	t.Run("stale", func(t *testing.T) {
+		if err := os.MkdirAll(filepath.Dir(socket), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		stale, err := net.Listen("unix", socket)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stale.(*net.UnixListener).SetUnlinkOnClose(false)
+		stale.Close()
+
+		listener, err := ListenSocket(socket, 0660)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer listener.Close()
+
+		info, err := os.Stat(socket)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, os.FileMode(0660), info.Mode().Perm())
+
+		_, err = ListenSocket(socket, 0660)
+		assert.EqualError(t, err, socket+" is already in use")
+	})
+	t.Run("file", func(t *testing.T) {
+		fileName := filepath.Join(dir, "http.sock")
+
+		if err := ioutil.WriteFile(fileName, []byte("photoprism"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := ListenSocket(fileName, 0660)
+		assert.EqualError(t, err, fileName+" exists and is not a socket")
+	})
+	t.Run("status", func(t *testing.T) {
+		listener, err := ListenSocket(socket, 0660)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		api.GetStatus(router.Group("/api/v1"), config.NewConfig(config.CliTestContext()))
+
+		server := &http.Server{Handler: router}
+		go server.Serve(listener)
+		defer server.Close()
+
+		client := &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}}
+
+		resp, err := client.Get("http://localhost/api/v1/status")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"status": "operational"}`, string(body))
+	})
+}
@@ -1,15 +1,42 @@
 package server
 
 import (
+	"context"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
 	"golang.org/x/net/webdav"
 )
 
+// webdavDir is a webdav.Dir that resolves its path on every call, so that it follows the
+// originals and import paths after they were changed in the setup wizard.
+type webdavDir func() string
+
+func (d webdavDir) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return webdav.Dir(d()).Mkdir(ctx, name, perm)
+}
+
+func (d webdavDir) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	return webdav.Dir(d()).OpenFile(ctx, name, flag, perm)
+}
+
+func (d webdavDir) RemoveAll(ctx context.Context, name string) error {
+	return webdav.Dir(d()).RemoveAll(ctx, name)
+}
+
+func (d webdavDir) Rename(ctx context.Context, oldName, newName string) error {
+	return webdav.Dir(d()).Rename(ctx, oldName, newName)
+}
+
+func (d webdavDir) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	return webdav.Dir(d()).Stat(ctx, name)
+}
+
 // ANY /webdav/*
-func WebDAV(path string, router *gin.RouterGroup, conf *config.Config) {
+func WebDAV(path func() string, router *gin.RouterGroup, conf *config.Config) {
 	if router == nil {
 		log.Error("webdav: router is nil")
 		return
@@ -20,7 +47,12 @@ func WebDAV(path string, router *gin.RouterGroup, conf *config.Config) {
 		return
 	}
 
-	f := webdav.Dir(path)
+	var f webdav.FileSystem = webdavDir(path)
+
+	if path() == conf.OriginalsPath() {
+		// Files only part of albums hidden from the user or WebDAV role are not shown.
+		f = &aclFileSystem{FileSystem: f, conf: conf, viewer: query.Viewer{Role: conf.WebDAVRole()}}
+	}
 
 	srv := &webdav.Handler{
 		Prefix:     router.BasePath(),
@@ -42,17 +74,28 @@ func WebDAV(path string, router *gin.RouterGroup, conf *config.Config) {
 		srv.ServeHTTP(w, r)
 	}
 
+	// Methods that modify files are rejected in read-only mode.
+	writeHandler := func(c *gin.Context) {
+		if conf.ReadOnly() {
+			log.Printf("webdav: %s %s, ERROR: %s\n", c.Request.Method, c.Request.URL, config.ErrReadOnly)
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		handler(c)
+	}
+
 	router.Handle("OPTIONS", "/*path", handler)
 	router.Handle("GET", "/*path", handler)
 	router.Handle("HEAD", "/*path", handler)
-	router.Handle("POST", "/*path", handler)
-	router.Handle("DELETE", "/*path", handler)
-	router.Handle("PUT", "/*path", handler)
-	router.Handle("MKCOL", "/*path", handler)
-	router.Handle("COPY", "/*path", handler)
-	router.Handle("MOVE", "/*path", handler)
-	router.Handle("LOCK", "/*path", handler)
-	router.Handle("UNLOCK", "/*path", handler)
+	router.Handle("POST", "/*path", writeHandler)
+	router.Handle("DELETE", "/*path", writeHandler)
+	router.Handle("PUT", "/*path", writeHandler)
+	router.Handle("MKCOL", "/*path", writeHandler)
+	router.Handle("COPY", "/*path", writeHandler)
+	router.Handle("MOVE", "/*path", writeHandler)
+	router.Handle("LOCK", "/*path", writeHandler)
+	router.Handle("UNLOCK", "/*path", writeHandler)
 	router.Handle("PROPFIND", "/*path", handler)
-	router.Handle("PROPPATCH", "/*path", handler)
+	router.Handle("PROPPATCH", "/*path", writeHandler)
 }
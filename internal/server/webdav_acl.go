@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
+	"golang.org/x/net/webdav"
+)
+
+// aclFileSystem hides indexed files that are only part of albums not visible to the viewer.
+type aclFileSystem struct {
+	webdav.FileSystem
+	conf   *config.Config
+	viewer query.Viewer
+}
+
+// aclFile filters directory listings of an aclFileSystem.
+type aclFile struct {
+	webdav.File
+	fs     *aclFileSystem
+	viewer query.Viewer
+	name   string
+}
+
+// webdavViewerKey is the request context key of the authenticated WebDAV viewer.
+type webdavViewerKey struct{}
+
+// viewerFromContext returns the viewer of a WebDAV request, or the default viewer if not authenticated as user.
+func (fs *aclFileSystem) viewerFromContext(ctx context.Context) query.Viewer {
+	if v, ok := ctx.Value(webdavViewerKey{}).(query.Viewer); ok {
+		return v
+	}
+
+	return fs.viewer
+}
+
+func (fs *aclFileSystem) visible(v query.Viewer, name string) bool {
+	if v.Admin() {
+		return true
+	}
+
+	// The database connection is resolved per request, as it may not be ready when routes are registered.
+	q := query.New(fs.conf.Db()).As(v)
+
+	return q.FileVisible(strings.TrimPrefix(path.Clean("/"+name), "/"))
+}
+
+func (fs *aclFileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	v := fs.viewerFromContext(ctx)
+
+	if !fs.visible(v, name) {
+		return nil, os.ErrNotExist
+	}
+
+	f, err := fs.FileSystem.OpenFile(ctx, name, flag, perm)
+
+	if err != nil {
+		return f, err
+	}
+
+	return &aclFile{File: f, fs: fs, viewer: v, name: name}, nil
+}
+
+func (fs *aclFileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	if !fs.visible(fs.viewerFromContext(ctx), name) {
+		return nil, os.ErrNotExist
+	}
+
+	return fs.FileSystem.Stat(ctx, name)
+}
+
+func (fs *aclFileSystem) RemoveAll(ctx context.Context, name string) error {
+	if !fs.visible(fs.viewerFromContext(ctx), name) {
+		return os.ErrNotExist
+	}
+
+	return fs.FileSystem.RemoveAll(ctx, name)
+}
+
+func (fs *aclFileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	if !fs.visible(fs.viewerFromContext(ctx), oldName) {
+		return os.ErrNotExist
+	}
+
+	return fs.FileSystem.Rename(ctx, oldName, newName)
+}
+
+func (f *aclFile) Readdir(count int) (result []os.FileInfo, err error) {
+	infos, err := f.File.Readdir(count)
+
+	for _, info := range infos {
+		if info.IsDir() || f.fs.visible(f.viewer, path.Join(f.name, info.Name())) {
+			result = append(result, info)
+		}
+	}
+
+	return result, err
+}
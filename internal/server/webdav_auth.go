@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/query"
+)
+
+// webdavUserName is the name of the WebDAV account using the WebDAV password.
+const webdavUserName = "photoprism"
+
+// webdavReadMethods contains WebDAV methods that don't modify files.
+var webdavReadMethods = map[string]bool{
+	http.MethodOptions: true,
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	"PROPFIND":         true,
+}
+
+// webdavAuth returns a middleware that checks basic auth credentials against the WebDAV password
+// and user accounts, the default admin may use the admin password until a password was set. Viewers have read-only access, uploaders may only write to the import mount.
+func webdavAuth(conf *config.Config, importMount bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var viewer query.Viewer
+		var canWrite bool
+
+		name, password, _ := c.Request.BasicAuth()
+
+		if webdavPassword := conf.WebDAVPassword(); webdavPassword != "" && name == webdavUserName && subtle.ConstantTimeCompare([]byte(password), []byte(webdavPassword)) == 1 {
+			viewer = query.Viewer{User: name, Role: conf.WebDAVRole()}
+			canWrite = true
+		} else if user, err := conf.Authenticate(name, password); name != "" && err == nil {
+			viewer = query.Viewer{ID: user.ID, User: user.UserName, Role: user.UserRole}
+			canWrite = user.CanModify() || importMount && user.CanUpload()
+		} else {
+			c.Header("WWW-Authenticate", `Basic realm="PhotoPrism"`)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		if !canWrite && !webdavReadMethods[c.Request.Method] {
+			log.Printf("webdav: %s %s, ERROR: permission denied for %s\n", c.Request.Method, c.Request.URL, viewer.User)
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), webdavViewerKey{}, viewer))
+
+		c.Next()
+	}
+}
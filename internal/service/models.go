@@ -0,0 +1,15 @@
+package service
+
+// InitModels loads TensorFlow models in advance, so that the first request
+// doesn't have to wait for them.
+func InitModels() error {
+	if err := Classify().Init(); err != nil {
+		return err
+	}
+
+	if Config().DetectNSFW() || !Config().UploadNSFW() {
+		return NsfwDetector().Init()
+	}
+
+	return nil
+}
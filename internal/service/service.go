@@ -6,6 +6,7 @@ import (
 	"github.com/photoprism/photoprism/internal/nsfw"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/internal/upload"
 )
 
 var conf *config.Config
@@ -18,6 +19,7 @@ var services struct {
 	Resample *photoprism.Resample
 	Classify *classify.TensorFlow
 	Session  *session.Session
+	Upload   *upload.Store
 }
 
 func SetConfig(c *config.Config) {
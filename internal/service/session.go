@@ -2,7 +2,6 @@ package service
 
 import (
 	"sync"
-	"time"
 
 	"github.com/photoprism/photoprism/internal/session"
 )
@@ -10,8 +9,8 @@ import (
 var onceSession sync.Once
 
 func initSession() {
-	// keep sessions for 7 days by default
-	services.Session = session.New(168*time.Hour, Config().CachePath())
+	conf := Config()
+	services.Session = session.New(conf.Db(), conf.Cache(), conf.SessionTimeout(), conf.SessionMaxAge())
 }
 
 func Session() *session.Session {
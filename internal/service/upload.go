@@ -0,0 +1,19 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/photoprism/photoprism/internal/upload"
+)
+
+var onceUpload sync.Once
+
+func initUpload() {
+	services.Upload = upload.New(Config().UploadsPath(), int64(Config().UploadLimit())*1024*1024, upload.DefaultTTL)
+}
+
+func Upload() *upload.Store {
+	onceUpload.Do(initUpload)
+
+	return services.Upload
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTokenInvalid = errors.New("session: invalid download token")
+	ErrTokenExpired = errors.New("session: download token expired")
+)
+
+// Subject prefixes of download tokens, tokens are bound to a session or a sharing link.
+const (
+	SubjectSession = "s"
+	SubjectLink    = "l"
+)
+
+// NewDownloadToken returns a download token for the subject, e.g. a session hash with prefix
+// SubjectSession, that expires at the given time. Tokens are signed with the secret, the subject
+// and expiry are not encrypted.
+func NewDownloadToken(secret []byte, subject string, expires time.Time) string {
+	payload := subject + "." + strconv.FormatInt(expires.Unix(), 36)
+
+	return payload + "." + downloadSignature(secret, payload)
+}
+
+// ParseDownloadToken checks the signature and expiry of a download token, and returns its subject.
+func ParseDownloadToken(secret []byte, token string, now time.Time) (subject string, err error) {
+	i := strings.LastIndex(token, ".")
+
+	if i <= 0 {
+		return "", ErrTokenInvalid
+	}
+
+	payload, signature := token[:i], token[i+1:]
+
+	if !hmac.Equal([]byte(signature), []byte(downloadSignature(secret, payload))) {
+		return "", ErrTokenInvalid
+	}
+
+	j := strings.LastIndex(payload, ".")
+
+	if j <= 0 {
+		return "", ErrTokenInvalid
+	}
+
+	expires, err := strconv.ParseInt(payload[j+1:], 36, 64)
+
+	if err != nil {
+		return "", ErrTokenInvalid
+	}
+
+	if now.Unix() >= expires {
+		return "", ErrTokenExpired
+	}
+
+	return payload[:j], nil
+}
+
+// downloadSignature returns the URL-safe signature of a download token payload.
+func downloadSignature(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
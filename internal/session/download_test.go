@@ -0,0 +1,73 @@
+package session
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadToken(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	subject := SubjectSession + "ab12cd34"
+
+	t.Run("valid", func(t *testing.T) {
+		token := NewDownloadToken(secret, subject, now.Add(time.Hour))
+		t.Logf("token: %s", token)
+
+		result, err := ParseDownloadToken(secret, token, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, subject, result)
+	})
+	t.Run("expired", func(t *testing.T) {
+		token := NewDownloadToken(secret, subject, now.Add(time.Hour))
+
+		_, err := ParseDownloadToken(secret, token, now.Add(time.Hour))
+		assert.Equal(t, ErrTokenExpired, err)
+
+		_, err = ParseDownloadToken(secret, token, now.Add(2*time.Hour))
+		assert.Equal(t, ErrTokenExpired, err)
+	})
+	t.Run("tampered signature", func(t *testing.T) {
+		token := NewDownloadToken(secret, subject, now.Add(time.Hour))
+		last := token[len(token)-1:]
+		replacement := "A"
+
+		if last == replacement {
+			replacement = "B"
+		}
+
+		_, err := ParseDownloadToken(secret, token[:len(token)-1]+replacement, now)
+		assert.Equal(t, ErrTokenInvalid, err)
+	})
+	t.Run("tampered subject", func(t *testing.T) {
+		token := NewDownloadToken(secret, subject, now.Add(time.Hour))
+
+		_, err := ParseDownloadToken(secret, strings.Replace(token, subject, SubjectSession+"ffffffff", 1), now)
+		assert.Equal(t, ErrTokenInvalid, err)
+	})
+	t.Run("tampered expiry", func(t *testing.T) {
+		token := NewDownloadToken(secret, subject, now.Add(-time.Hour))
+		parts := strings.Split(token, ".")
+		parts[1] = strconv.FormatInt(now.Add(time.Hour).Unix(), 36)
+
+		_, err := ParseDownloadToken(secret, strings.Join(parts, "."), now)
+		assert.Equal(t, ErrTokenInvalid, err)
+	})
+	t.Run("other secret", func(t *testing.T) {
+		token := NewDownloadToken(secret, subject, now.Add(time.Hour))
+
+		_, err := ParseDownloadToken([]byte("other secret"), token, now)
+		assert.Equal(t, ErrTokenInvalid, err)
+	})
+	t.Run("malformed", func(t *testing.T) {
+		for _, token := range []string{"", ".", "abc", "..", subject + ".xyz"} {
+			_, err := ParseDownloadToken(secret, token, now)
+			assert.Equal(t, ErrTokenInvalid, err, token)
+		}
+	})
+}
@@ -1,6 +1,11 @@
 /*
 This package encapsulates session storage.
 
+Sessions are stored in the database, so that they survive restarts. Only a hash of the
+session token is stored. A cache is used for decoded session data, it may be shared with other
+instances, so cached sessions are serialized as JSON in this case. The database is still checked on
+every request, so that a logout or revoked session applies to all instances immediately.
+
 Additional information can be found in our Developer Guide:
 
 https://github.com/photoprism/photoprism/wiki
@@ -8,12 +13,11 @@ https://github.com/photoprism/photoprism/wiki
 package session
 
 import (
-	"encoding/json"
-	"io/ioutil"
-	"path"
+	"sync"
 	"time"
 
-	gc "github.com/patrickmn/go-cache"
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/cache"
 	"github.com/photoprism/photoprism/internal/event"
 )
 
@@ -21,33 +25,20 @@ var log = event.Log
 
 // Session represents a session store.
 type Session struct {
-	cacheFile string
-	cache     *gc.Cache
+	db      *gorm.DB
+	cache   cache.Cache
+	timeout time.Duration
+	maxAge  time.Duration
+	mutex   sync.Mutex
 }
 
-// New returns a new session store with an optional cachePath.
-func New(expiration time.Duration, cachePath string) *Session {
-	s := &Session{}
-
-	cleanupInterval := 15 * time.Minute
-
-	if cachePath != "" {
-		var items map[string]gc.Item
-
-		s.cacheFile = path.Join(cachePath, "sessions.json")
-
-		if cached, err := ioutil.ReadFile(s.cacheFile); err != nil {
-			log.Infof("session: %s", err)
-		} else if err := json.Unmarshal(cached, &items); err != nil {
-			log.Errorf("session: %s", err)
-		} else {
-			s.cache = gc.NewFrom(expiration, cleanupInterval, items)
-		}
-	}
-
-	if s.cache == nil {
-		s.cache = gc.New(expiration, cleanupInterval)
+// New returns a new session store. Sessions expire if they were inactive for longer than timeout
+// or were created before maxAge, the cache may be shared with other packages.
+func New(db *gorm.DB, c cache.Cache, timeout, maxAge time.Duration) *Session {
+	return &Session{
+		db:      db,
+		cache:   c,
+		timeout: timeout,
+		maxAge:  maxAge,
 	}
-
-	return s
 }
@@ -2,50 +2,166 @@ package session
 
 import (
 	"encoding/json"
-	"io/ioutil"
+	"time"
 
-	gc "github.com/patrickmn/go-cache"
+	"github.com/photoprism/photoprism/internal/cache"
+	"github.com/photoprism/photoprism/internal/entity"
 )
 
+// activeInterval is the min interval for updating the last activity of a session in the database.
+const activeInterval = time.Minute
+
+// cached represents a session in the cache, fields are exported so that it can be serialized.
+type cached struct {
+	Session entity.Session `json:"session"`
+	Data    interface{}    `json:"data"`
+}
+
+// cacheKey returns the cache key for a token.
+func cacheKey(token string) string {
+	return hashKey(entity.SessionHash(token))
+}
+
+// hashKey returns the cache key for a token hash.
+func hashKey(hash string) string {
+	return "session:" + hash
+}
+
+// Create stores the session data and returns a new session token.
 func (s *Session) Create(data interface{}) string {
 	token := Token()
-	s.cache.Set(token, data, gc.DefaultExpiration)
-	log.Debugf("session: created")
+	now := time.Now()
+
+	item := &cached{Data: data, Session: entity.Session{
+		SessionHash: entity.SessionHash(token),
+		LastActive:  now,
+		CreatedAt:   now,
+	}}
 
-	if err := s.Save(); err != nil {
+	if serialized, err := json.Marshal(data); err != nil {
 		log.Errorf("session: %s", err)
+	} else {
+		item.Session.SessionData = string(serialized)
+
+		if err := s.db.Create(&item.Session).Error; err != nil {
+			log.Errorf("session: %s", err)
+		}
 	}
 
+	s.cache.Set(cacheKey(token), item, s.timeout)
+	log.Debugf("session: created")
+
 	return token
 }
 
+// Delete removes the session from the cache and the database.
 func (s *Session) Delete(token string) {
-	s.cache.Delete(token)
-	log.Debugf("session: deleted")
+	s.cache.Delete(cacheKey(token))
 
-	if err := s.Save(); err != nil {
+	if err := entity.DeleteSession(s.db, token); err != nil {
 		log.Errorf("session: %s", err)
 	}
+
+	log.Debugf("session: deleted")
 }
 
+// Get returns the session data, sessions not found in the cache are loaded from the database.
+// Data loaded from the database was decoded from JSON, so numbers are float64.
 func (s *Session) Get(token string) (data interface{}, exists bool) {
-	return s.cache.Get(token)
+	if token == "" {
+		return nil, false
+	}
+
+	key := cacheKey(token)
+
+	item, found := s.load(entity.SessionHash(token), true)
+
+	if !found {
+		return nil, false
+	}
+
+	now := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if item.Session.Expired(now, s.timeout, s.maxAge) {
+		s.cache.Delete(key)
+
+		if err := entity.DeleteSession(s.db, token); err != nil {
+			log.Errorf("session: %s", err)
+		}
+
+		return nil, false
+	}
+
+	if now.Sub(item.Session.LastActive) >= activeInterval {
+		item.Session.LastActive = now
+
+		if err := s.db.Model(&item.Session).Update("last_active", now).Error; err != nil {
+			log.Errorf("session: %s", err)
+		}
+	}
+
+	s.cache.Set(key, item, s.timeout)
+
+	return item.Data, true
+}
+
+// load returns the session with the token hash. The database is checked on cache hits as well, so that
+// sessions deleted by other instances, e.g. after logout, are revoked immediately. The session data is
+// only decoded if it isn't cached and decode is true.
+func (s *Session) load(hash string, decode bool) (item *cached, found bool) {
+	m, err := entity.FindSessionHash(s.db, hash)
+
+	if err != nil {
+		s.cache.Delete(hashKey(hash))
+		return nil, false
+	}
+
+	if cache.Load(s.cache, hashKey(hash), &item) {
+		item.Session = *m
+		return item, true
+	}
+
+	item = &cached{Session: *m}
+
+	if !decode {
+		return item, true
+	}
+
+	if err := json.Unmarshal([]byte(m.SessionData), &item.Data); err != nil {
+		log.Errorf("session: %s", err)
+		return nil, false
+	}
+
+	return item, true
 }
 
+// Exists returns true if the session exists and didn't expire.
 func (s *Session) Exists(token string) bool {
-	_, found := s.cache.Get(token)
+	_, found := s.Get(token)
 
 	return found
 }
 
-func (s *Session) Save() error {
-	if s.cacheFile == "" {
-		return nil
-	} else if serialized, err := json.MarshalIndent(s.cache.Items(), "", " "); err != nil {
-		return err
-	} else if err = ioutil.WriteFile(s.cacheFile, serialized, 0600); err != nil {
-		return err
+// ExistsHash returns true if the session with the token hash exists and didn't expire. Unlike
+// Get, it doesn't update the last activity, so that it can be used to check download tokens.
+func (s *Session) ExistsHash(hash string) bool {
+	if hash == "" {
+		return false
+	}
+
+	item, found := s.load(hash, false)
+
+	if !found {
+		return false
 	}
 
-	return nil
+	return !item.Session.Expired(time.Now(), s.timeout, s.maxAge)
+}
+
+// DeleteExpired deletes expired sessions from the database.
+func (s *Session) DeleteExpired() (int64, error) {
+	return entity.DeleteExpiredSessions(s.db, s.timeout, s.maxAge)
 }
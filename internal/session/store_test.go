@@ -4,23 +4,41 @@ import (
 	"testing"
 	"time"
 
+	gc "github.com/patrickmn/go-cache"
+	"github.com/photoprism/photoprism/internal/cache"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/stretchr/testify/assert"
 )
 
+// testSession returns a new session store using the test database and a new cache.
+func testSession(timeout, maxAge time.Duration) *Session {
+	return New(config.TestConfig().Db(), gc.New(time.Hour, time.Minute), timeout, maxAge)
+}
+
 func TestSession_Create(t *testing.T) {
-	s := New(time.Hour, "testdata")
+	s := testSession(time.Hour, 24*time.Hour)
 	token := s.Create(23)
 	t.Logf("token: %s", token)
 	assert.Equal(t, 48, len(token))
+
+	m, err := entity.FindSession(s.db, token)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEqual(t, token, m.SessionHash)
+	assert.Equal(t, "23", m.SessionData)
 }
 
 func TestSession_Delete(t *testing.T) {
-	s := New(time.Hour, "testdata")
+	s := testSession(time.Hour, 24*time.Hour)
 	s.Delete("abc")
 }
 
 func TestSession_Get(t *testing.T) {
-	s := New(time.Hour, "testdata")
+	s := testSession(time.Hour, 24*time.Hour)
 	token := s.Create(42)
 	t.Logf("token: %s", token)
 	assert.Equal(t, 48, len(token))
@@ -39,7 +57,7 @@ func TestSession_Get(t *testing.T) {
 }
 
 func TestSession_Exists(t *testing.T) {
-	s := New(time.Hour, "testdata")
+	s := testSession(time.Hour, 24*time.Hour)
 	assert.False(t, s.Exists("xyz"))
 	token := s.Create(23)
 	t.Logf("token: %s", token)
@@ -48,3 +66,131 @@ func TestSession_Exists(t *testing.T) {
 	s.Delete(token)
 	assert.False(t, s.Exists(token))
 }
+
+func TestSession_Restart(t *testing.T) {
+	s := testSession(time.Hour, 24*time.Hour)
+	token := s.Create(map[string]interface{}{"UserName": "admin", "ID": 1})
+
+	t.Run("restarted", func(t *testing.T) {
+		restarted := testSession(time.Hour, 24*time.Hour)
+
+		data, exists := restarted.Get(token)
+
+		assert.True(t, exists)
+		assert.Equal(t, map[string]interface{}{"UserName": "admin", "ID": float64(1)}, data)
+	})
+	t.Run("logout", func(t *testing.T) {
+		s.Delete(token)
+
+		restarted := testSession(time.Hour, 24*time.Hour)
+
+		assert.False(t, restarted.Exists(token))
+		assert.False(t, s.Exists(token))
+	})
+	t.Run("logout other instance", func(t *testing.T) {
+		token := s.Create(42)
+		other := testSession(time.Hour, 24*time.Hour)
+
+		assert.True(t, s.Exists(token))
+		assert.True(t, other.Exists(token))
+
+		other.Delete(token)
+
+		// Cached sessions are revoked as soon as they were deleted from the database.
+		assert.False(t, s.Exists(token))
+		assert.False(t, s.ExistsHash(entity.SessionHash(token)))
+	})
+}
+
+func TestSession_Expired(t *testing.T) {
+	s := testSession(time.Hour, 24*time.Hour)
+	token := s.Create(23)
+
+	t.Run("timeout", func(t *testing.T) {
+		if err := s.db.Model(&entity.Session{}).Where("session_hash = ?", entity.SessionHash(token)).
+			UpdateColumn("last_active", time.Now().Add(-2*time.Hour)).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		restarted := testSession(time.Hour, 24*time.Hour)
+
+		assert.False(t, restarted.Exists(token))
+
+		_, err := entity.FindSession(s.db, token)
+		assert.Error(t, err)
+	})
+	t.Run("max age", func(t *testing.T) {
+		short := testSession(time.Hour, time.Millisecond)
+		token := short.Create(23)
+
+		time.Sleep(5 * time.Millisecond)
+
+		assert.False(t, short.Exists(token))
+	})
+	t.Run("delete expired", func(t *testing.T) {
+		token := s.Create(23)
+
+		if err := s.db.Model(&entity.Session{}).Where("session_hash = ?", entity.SessionHash(token)).
+			UpdateColumn("created_at", time.Now().Add(-48*time.Hour)).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := s.DeleteExpired()
+
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, n, int64(1))
+	})
+}
+
+// sharedCache serializes values like a cache shared with other instances.
+type sharedCache struct {
+	*gc.Cache
+}
+
+func (c sharedCache) Set(key string, value interface{}, ttl time.Duration) {
+	data, err := cache.Encode(value)
+
+	if err != nil {
+		panic(err)
+	}
+
+	c.Cache.Set(key, data, ttl)
+}
+
+func TestSession_SharedCache(t *testing.T) {
+	shared := sharedCache{gc.New(time.Hour, time.Minute)}
+	s := New(config.TestConfig().Db(), shared, time.Hour, 24*time.Hour)
+	token := s.Create(map[string]interface{}{"UserName": "admin", "ID": 1})
+
+	// Sessions created by one instance can be read by another.
+	other := New(s.db, shared, time.Hour, 24*time.Hour)
+
+	data, exists := other.Get(token)
+
+	assert.True(t, exists)
+	assert.Equal(t, map[string]interface{}{"UserName": "admin", "ID": float64(1)}, data)
+
+	other.Delete(token)
+
+	assert.False(t, s.Exists(token))
+}
+
+func TestSession_ExistsHash(t *testing.T) {
+	s := testSession(time.Hour, 24*time.Hour)
+	token := s.Create(23)
+	hash := entity.SessionHash(token)
+
+	assert.True(t, s.ExistsHash(hash))
+	assert.False(t, s.ExistsHash(""))
+	assert.False(t, s.ExistsHash(entity.SessionHash("xyz")))
+
+	t.Run("restarted", func(t *testing.T) {
+		assert.True(t, testSession(time.Hour, 24*time.Hour).ExistsHash(hash))
+	})
+	t.Run("deleted", func(t *testing.T) {
+		s.Delete(token)
+
+		assert.False(t, s.ExistsHash(hash))
+		assert.False(t, testSession(time.Hour, 24*time.Hour).ExistsHash(hash))
+	})
+}
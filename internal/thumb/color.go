@@ -0,0 +1,57 @@
+package thumb
+
+import (
+	"image"
+
+	"github.com/photoprism/photoprism/pkg/icc"
+)
+
+// Open opens an image file and applies its Exif orientation. If color management is enabled,
+// pixel values are converted to sRGB according to the embedded color profile. Files that
+// exceed the size or resolution limit are not decoded, see CheckLimits.
+func Open(fileName string) (image.Image, error) {
+	if err := CheckLimits(fileName); err != nil {
+		return nil, err
+	}
+
+	img, err := openOriented(fileName)
+
+	if err != nil {
+		return img, err
+	}
+
+	return ToSRGB(img, fileName), nil
+}
+
+// ToSRGB converts an image to sRGB if color management is enabled and the file
+// has an embedded wide gamut color profile. The color conversion does not depend on
+// the pixel position, so it can be applied before or after rotation.
+func ToSRGB(img image.Image, fileName string) image.Image {
+	if !ColorManagement {
+		return img
+	}
+
+	data, err := icc.Extract(fileName)
+
+	if err == icc.ErrNotFound {
+		return img
+	} else if err != nil {
+		log.Debugf("thumbs: %s in %s", err, fileName)
+		return img
+	}
+
+	profile, err := icc.Parse(data)
+
+	if err != nil {
+		log.Warnf("thumbs: can't convert %s to srgb (%s)", fileName, err)
+		return img
+	}
+
+	if profile.SRGB() {
+		return img
+	}
+
+	log.Debugf("thumbs: converting %s from %s to srgb", fileName, profile.Description)
+
+	return profile.ToSRGB(img)
+}
@@ -17,16 +17,26 @@ import (
 func ResampleOptions(opts ...ResampleOption) (method ResampleOption, filter imaging.ResampleFilter, format fs.FileType) {
 	method = ResampleFit
 	filter = imaging.Lanczos
-	format = fs.TypeJpeg
+	format = Format
 
 	for _, option := range opts {
 		switch option {
 		case ResamplePng:
 			format = fs.TypePng
+		case ResampleJpeg:
+			format = fs.TypeJpeg
 		case ResampleNearestNeighbor:
 			filter = imaging.NearestNeighbor
 		case ResampleDefault:
 			filter = Filter.Imaging()
+		case ResampleFilterBlackman:
+			filter = imaging.Blackman
+		case ResampleFilterLanczos:
+			filter = imaging.Lanczos
+		case ResampleFilterCubic:
+			filter = imaging.CatmullRom
+		case ResampleFilterLinear:
+			filter = imaging.Linear
 		case ResampleFillTopLeft:
 			method = ResampleFillTopLeft
 		case ResampleFillCenter:
@@ -46,12 +56,19 @@ func ResampleOptions(opts ...ResampleOption) (method ResampleOption, filter imag
 }
 
 func Resample(img *image.Image, width, height int, opts ...ResampleOption) *image.Image {
+	return ResampleCrop(img, width, height, Crop{}, opts...)
+}
+
+// ResampleCrop resamples an image like Resample, centered fills use the crop window if one was chosen.
+func ResampleCrop(img *image.Image, width, height int, crop Crop, opts ...ResampleOption) *image.Image {
 	var resImg image.Image
 
 	method, filter, _ := ResampleOptions(opts...)
 
 	if method == ResampleFit {
 		resImg = imaging.Fit(*img, width, height, filter)
+	} else if method == ResampleFillCenter && !crop.IsZero() {
+		resImg = fillCrop(*img, width, height, crop, filter)
 	} else if method == ResampleFillCenter {
 		resImg = imaging.Fill(*img, width, height, imaging.Center, filter)
 	} else if method == ResampleFillTopLeft {
@@ -103,6 +120,11 @@ func Filename(hash string, thumbPath string, width, height int, opts ...Resample
 }
 
 func FromFile(imageFilename string, hash string, thumbPath string, width, height int, opts ...ResampleOption) (fileName string, err error) {
+	return FromFileCrop(imageFilename, hash, thumbPath, width, height, Crop{}, opts...)
+}
+
+// FromFileCrop creates a thumbnail like FromFile, centered fills use the crop window if one was chosen.
+func FromFileCrop(imageFilename string, hash string, thumbPath string, width, height int, crop Crop, opts ...ResampleOption) (fileName string, err error) {
 	if len(hash) < 4 {
 		return "", fmt.Errorf("thumbs: file hash is empty or too short (\"%s\")", hash)
 	}
@@ -118,18 +140,28 @@ func FromFile(imageFilename string, hash string, thumbPath string, width, height
 		return "", err
 	}
 
-	if fs.FileExists(fileName) {
-		return fileName, nil
+	if existing, ok := Existing(hash, thumbPath, width, height, opts...); ok {
+		return existing, nil
+	}
+
+	if UseEmbedded {
+		if img, err := Embedded(imageFilename); err == nil && SourceFits(img, width, height, opts...) {
+			if _, err := CreateCrop(&img, fileName, width, height, crop, opts...); err != nil {
+				return "", err
+			}
+
+			return fileName, nil
+		}
 	}
 
-	img, err := imaging.Open(imageFilename, imaging.AutoOrientation(true))
+	img, err := Open(imageFilename)
 
 	if err != nil {
 		log.Errorf("thumbs: can't open \"%s\" (%s)", imageFilename, err.Error())
 		return "", err
 	}
 
-	if _, err := Create(&img, fileName, width, height, opts...); err != nil {
+	if _, err := CreateCrop(&img, fileName, width, height, crop, opts...); err != nil {
 		return "", err
 	}
 
@@ -137,6 +169,11 @@ func FromFile(imageFilename string, hash string, thumbPath string, width, height
 }
 
 func Create(img *image.Image, fileName string, width, height int, opts ...ResampleOption) (result *image.Image, err error) {
+	return CreateCrop(img, fileName, width, height, Crop{}, opts...)
+}
+
+// CreateCrop creates a thumbnail like Create, centered fills use the crop window if one was chosen.
+func CreateCrop(img *image.Image, fileName string, width, height int, crop Crop, opts ...ResampleOption) (result *image.Image, err error) {
 	if width < 0 || width > MaxRenderSize {
 		return img, fmt.Errorf("thumbs: width has an invalid value (%d)", width)
 	}
@@ -145,20 +182,34 @@ func Create(img *image.Image, fileName string, width, height int, opts ...Resamp
 		return img, fmt.Errorf("thumbs: height has an invalid value (%d)", height)
 	}
 
-	result = Resample(img, width, height, opts...)
+	result = ResampleCrop(img, width, height, crop, opts...)
 
-	var saveOption imaging.EncodeOption
+	if Sharpen > 0 && (*result).Bounds().Dx() < (*img).Bounds().Dx() && (Type{Options: opts}).Filter() != "" {
+		var sharpened image.Image = imaging.Sharpen(*result, Sharpen)
+		result = &sharpened
+	}
+
+	quality := JpegQuality
+
+	if width <= 150 && height <= 150 {
+		quality = JpegQualitySmall
+	}
+
+	if filepath.Ext(fileName) == "."+string(fs.TypeWebP) {
+		if err := encodeWebP(*result, fileName, WebpQuality(quality)); err != nil {
+			log.Errorf("thumbs: failed to save %s (%s)", fileName, err)
+			return result, err
+		}
+
+		return result, nil
+	}
 
 	if filepath.Ext(fileName) == "."+string(fs.TypePng) {
-		saveOption = imaging.PNGCompressionLevel(png.DefaultCompression)
-	} else if width <= 150 && height <= 150 {
-		saveOption = imaging.JPEGQuality(JpegQualitySmall)
+		err = imaging.Save(*result, fileName, imaging.PNGCompressionLevel(png.DefaultCompression))
 	} else {
-		saveOption = imaging.JPEGQuality(JpegQuality)
+		err = encodeJpeg(*result, fileName, quality)
 	}
 
-	err = imaging.Save(*result, fileName, saveOption)
-
 	if err != nil {
 		log.Errorf("thumbs: failed to save %s", fileName)
 		return result, err
@@ -0,0 +1,208 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// SmartCrop enables choosing the crop window of centered fill thumbnails based on the image content.
+var SmartCrop = false
+
+// Max width and height in pixels of the image buffer used to find the salient region.
+const cropAnalysisSize = 64
+
+// Crop windows must score at least this much better than the center to be used.
+const cropMinGain = 1.05
+
+// Crop is the center of a fill thumbnail crop window relative to the image size, e.g. 0.5 for the center.
+// The zero value means no crop was chosen, thumbnails are center cropped in this case.
+type Crop struct {
+	X float64
+	Y float64
+}
+
+// IsZero returns true if no crop window was chosen.
+func (c Crop) IsZero() bool {
+	return c.X == 0 && c.Y == 0
+}
+
+// String returns the crop center in a format suitable for storing it, e.g. "0.500,0.250".
+func (c Crop) String() string {
+	if c.IsZero() {
+		return ""
+	}
+
+	return fmt.Sprintf("%.3f,%.3f", c.X, c.Y)
+}
+
+// ParseCrop returns the crop center stored as string, see Crop.String.
+func ParseCrop(s string) (c Crop, ok bool) {
+	if s == "" {
+		return c, false
+	}
+
+	if _, err := fmt.Sscanf(s, "%f,%f", &c.X, &c.Y); err != nil {
+		return Crop{}, false
+	}
+
+	if c.X < 0 || c.X > 1 || c.Y < 0 || c.Y > 1 {
+		return Crop{}, false
+	}
+
+	return c, true
+}
+
+// FindCrop returns the center of the square crop window containing the most salient part of an image.
+// If faces are detected, the crop window is centered on them. Otherwise, edge density is used as a cheap
+// saliency measure. The image should already be downscaled, e.g. a fit_720 thumbnail, to keep this fast.
+func FindCrop(img image.Image, faces []image.Rectangle) Crop {
+	b := img.Bounds()
+
+	if b.Dx() <= 0 || b.Dy() <= 0 {
+		return Crop{}
+	}
+
+	if len(faces) > 0 {
+		r := faces[0]
+
+		for _, f := range faces[1:] {
+			r = r.Union(f)
+		}
+
+		return Crop{
+			X: clampUnit(float64((r.Min.X+r.Max.X)/2-b.Min.X) / float64(b.Dx())),
+			Y: clampUnit(float64((r.Min.Y+r.Max.Y)/2-b.Min.Y) / float64(b.Dy())),
+		}
+	}
+
+	center := Crop{X: 0.5, Y: 0.5}
+
+	if b.Dx() == b.Dy() {
+		return center
+	}
+
+	small := imaging.Fit(img, cropAnalysisSize, cropAnalysisSize, imaging.Box)
+	edges := edgeDensity(small)
+	w, h := small.Bounds().Dx(), small.Bounds().Dy()
+
+	// Project edge density on the long side, the crop window covers the short side completely.
+	landscape := w > h
+	length, size := h, w
+
+	if landscape {
+		length, size = w, h
+	}
+
+	profile := make([]float64, length)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if landscape {
+				profile[x] += edges[y*w+x]
+			} else {
+				profile[y] += edges[y*w+x]
+			}
+		}
+	}
+
+	sum := func(start int) (result float64) {
+		for i := start; i < start+size; i++ {
+			result += profile[i]
+		}
+
+		return result
+	}
+
+	centerStart := (length - size) / 2
+	centerScore := sum(centerStart)
+	best, bestScore := centerStart, centerScore
+
+	for start := 0; start <= length-size; start++ {
+		if score := sum(start); score > bestScore {
+			best, bestScore = start, score
+		}
+	}
+
+	if bestScore <= centerScore*cropMinGain {
+		return center
+	}
+
+	pos := (float64(best) + float64(size)/2) / float64(length)
+
+	if landscape {
+		return Crop{X: pos, Y: 0.5}
+	}
+
+	return Crop{X: 0.5, Y: pos}
+}
+
+// edgeDensity returns the gradient magnitude of the luminance for each pixel.
+func edgeDensity(img *image.NRGBA) []float64 {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	lum := make([]float64, w*h)
+
+	for i := range lum {
+		p := img.Pix[i*4 : i*4+3]
+		lum[i] = 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+	}
+
+	result := make([]float64, w*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+
+			if x+1 < w {
+				result[i] += math.Abs(lum[i+1] - lum[i])
+			}
+
+			if y+1 < h {
+				result[i] += math.Abs(lum[i+w] - lum[i])
+			}
+		}
+	}
+
+	return result
+}
+
+// fillCrop crops an image to the aspect ratio of width and height around the crop center and resizes it.
+func fillCrop(img image.Image, width, height int, crop Crop, filter imaging.ResampleFilter) image.Image {
+	b := img.Bounds()
+	srcW, srcH := b.Dx(), b.Dy()
+
+	if width <= 0 || height <= 0 || srcW <= 0 || srcH <= 0 {
+		return imaging.Fill(img, width, height, imaging.Center, filter)
+	}
+
+	cropW := srcW
+	cropH := int(math.Round(float64(srcW) * float64(height) / float64(width)))
+
+	if cropH > srcH {
+		cropH = srcH
+		cropW = int(math.Round(float64(srcH) * float64(width) / float64(height)))
+	}
+
+	x := clampInt(int(math.Round(crop.X*float64(srcW)-float64(cropW)/2)), 0, srcW-cropW)
+	y := clampInt(int(math.Round(crop.Y*float64(srcH)-float64(cropH)/2)), 0, srcH-cropH)
+
+	cropped := imaging.Crop(img, image.Rect(b.Min.X+x, b.Min.Y+y, b.Min.X+x+cropW, b.Min.Y+y+cropH))
+
+	return imaging.Resize(cropped, width, height, filter)
+}
+
+func clampUnit(f float64) float64 {
+	return math.Max(0, math.Min(1, f))
+}
+
+func clampInt(i, min, max int) int {
+	if i < min {
+		return min
+	} else if i > max {
+		return max
+	}
+
+	return i
+}
@@ -0,0 +1,67 @@
+package thumb
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+
+	"github.com/photoprism/photoprism/internal/meta"
+)
+
+// Embedded returns the largest preview image embedded in a file. The Exif orientation
+// of the file is applied unless the preview has already been rotated by the camera.
+// Previews with a different aspect ratio than the original are skipped, so that the
+// original is decoded instead of creating letterboxed or cropped thumbnails.
+func Embedded(fileName string) (img image.Image, err error) {
+	data, exifErr := meta.Exif(fileName)
+
+	// The aspect ratio can't be checked without the size of the original.
+	if exifErr != nil {
+		data = meta.Data{}
+	}
+
+	preview, err := meta.EmbeddedPreviewAspect(fileName, data.Width, data.Height)
+
+	if err != nil {
+		return nil, err
+	}
+
+	img, err = jpeg.Decode(bytes.NewReader(preview.Data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Embedded previews use the color space of the original.
+	img = ToSRGB(img, fileName)
+
+	if exifErr != nil || data.Orientation <= 1 {
+		return img, nil
+	}
+
+	// Orientations 5 to 8 swap width and height, so a preview with the aspect of the
+	// displayed original must already be rotated.
+	if data.Orientation > 4 && data.Width > 0 && data.Height > 0 && data.Width != data.Height &&
+		(data.Width > data.Height) == (preview.Width > preview.Height) {
+		return img, nil
+	}
+
+	return Rotate(img, data.Orientation), nil
+}
+
+// SourceFits returns true if the image is large enough to be resampled to the given size without upscaling.
+func SourceFits(img image.Image, width, height int, opts ...ResampleOption) bool {
+	if img == nil {
+		return false
+	}
+
+	method, _, _ := ResampleOptions(opts...)
+	size := img.Bounds().Size()
+
+	switch method {
+	case ResampleFit:
+		return size.X >= width || size.Y >= height
+	default:
+		return size.X >= width && size.Y >= height
+	}
+}
@@ -0,0 +1,99 @@
+package thumb
+
+import (
+	"strings"
+)
+
+// TypeFilters contains resample filters for individual thumbnail types, other types use Filter.
+var TypeFilters = map[string]ResampleFilter{}
+
+// Sharpen is the sigma of an unsharp mask applied after downscaling, 0 disables sharpening.
+var Sharpen = 0.0
+
+var filterOptions = map[ResampleFilter]ResampleOption{
+	ResampleBlackman: ResampleFilterBlackman,
+	ResampleLanczos:  ResampleFilterLanczos,
+	ResampleCubic:    ResampleFilterCubic,
+	ResampleLinear:   ResampleFilterLinear,
+}
+
+// ParseFilter returns the resample filter for a name like "lanczos".
+func ParseFilter(name string) (ResampleFilter, bool) {
+	f := ResampleFilter(strings.ToLower(strings.TrimSpace(name)))
+
+	if _, ok := filterOptions[f]; ok {
+		return f, true
+	}
+
+	return "", false
+}
+
+// ParseFilters parses a list of resample filters like "tile_224:lanczos,fit_2048:cubic,default:cubic".
+// A bare filter name sets the default filter. Entries with an unknown filter are ignored.
+func ParseFilters(s string) (defaultFilter ResampleFilter, types map[string]ResampleFilter) {
+	types = make(map[string]ResampleFilter)
+
+	for _, entry := range strings.Split(s, ",") {
+		name, filterName := "default", entry
+
+		if i := strings.Index(entry, ":"); i >= 0 {
+			name, filterName = strings.TrimSpace(entry[:i]), entry[i+1:]
+		}
+
+		filter, ok := ParseFilter(filterName)
+
+		if !ok {
+			if strings.TrimSpace(entry) != "" {
+				log.Warnf("thumbs: unknown resample filter \"%s\"", strings.TrimSpace(entry))
+			}
+
+			continue
+		}
+
+		if name == "default" || name == "" {
+			defaultFilter = filter
+		} else {
+			types[name] = filter
+		}
+	}
+
+	return defaultFilter, types
+}
+
+// Filter returns the resample filter used for this type, or an empty string if it uses a fixed
+// filter like nearest neighbor.
+func (t Type) Filter() (result ResampleFilter) {
+	result = ResampleLanczos
+
+	for _, option := range t.Options {
+		switch option {
+		case ResampleDefault:
+			result = Filter
+		case ResampleNearestNeighbor:
+			result = ""
+		}
+
+		for f, o := range filterOptions {
+			if o == option {
+				result = f
+			}
+		}
+	}
+
+	return result
+}
+
+// applyFilter returns the options of a type with the default filter replaced by filter.
+func applyFilter(opts []ResampleOption, filter ResampleFilter) []ResampleOption {
+	result := make([]ResampleOption, len(opts))
+
+	for i, option := range opts {
+		if option == ResampleDefault {
+			result[i] = filterOptions[filter]
+		} else {
+			result[i] = option
+		}
+	}
+
+	return result
+}
@@ -7,7 +7,12 @@ import (
 )
 
 func Jpeg(srcFilename, jpgFilename string) (result image.Image, err error) {
-	img, err := imaging.Open(srcFilename, imaging.AutoOrientation(true))
+	if err := CheckLimits(srcFilename); err != nil {
+		log.Warnf("thumbs: can't convert %s (%s)", srcFilename, err)
+		return result, err
+	}
+
+	img, err := openOriented(srcFilename)
 
 	if err != nil {
 		log.Errorf("thumbs: can't open %s", srcFilename)
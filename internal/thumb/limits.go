@@ -0,0 +1,69 @@
+package thumb
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+)
+
+var (
+	SizeLimit       = 0 // Max original file size in MB, 0 means unlimited.
+	ResolutionLimit = 0 // Max original resolution in megapixels, 0 means unlimited.
+)
+
+// ErrTooLarge is returned for files that exceed the size or resolution limit.
+var ErrTooLarge = errors.New("too large")
+
+// Resolution returns the image dimensions from the file header without decoding the image.
+func Resolution(fileName string) (width, height int, err error) {
+	file, err := os.Open(fileName)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return cfg.Width, cfg.Height, nil
+}
+
+// CheckLimits returns an error wrapping ErrTooLarge if a file exceeds the size or resolution limit.
+// Files with an unknown header format are only checked against the size limit.
+func CheckLimits(fileName string) error {
+	if SizeLimit <= 0 && ResolutionLimit <= 0 {
+		return nil
+	}
+
+	if SizeLimit > 0 {
+		info, err := os.Stat(fileName)
+
+		if err != nil {
+			return err
+		}
+
+		if mb := info.Size() / 1048576; mb > int64(SizeLimit) {
+			return fmt.Errorf("%w, %d MB exceed the limit of %d MB", ErrTooLarge, mb, SizeLimit)
+		}
+	}
+
+	if ResolutionLimit > 0 {
+		width, height, err := Resolution(fileName)
+
+		if err != nil {
+			return nil
+		}
+
+		if mp := int(int64(width) * int64(height) / 1000000); mp > ResolutionLimit {
+			return fmt.Errorf("%w, %d megapixels exceed the limit of %d", ErrTooLarge, mp, ResolutionLimit)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package thumb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// SettingsHash returns a hash of the settings that affect the default thumbnails of a file, so that
+// thumbnails can be regenerated if any of them changed, e.g. the JPEG quality or the resample filter.
+func SettingsHash() string {
+	h := sha1.New()
+
+	fmt.Fprintf(h, "%d %d %d %d %s %s %t %t %t %t %g %q", PreRenderSize, MaxRenderSize, JpegQuality, JpegQualitySmall,
+		Format, Filter, UseEmbedded, ColorManagement, Progressive, SmartCrop, Sharpen, Copyright)
+
+	for _, name := range DefaultTypes {
+		t := Types[name]
+		fmt.Fprintf(h, " %s:%dx%d:%s:%v", name, t.Width, t.Height, t.Source, t.Options)
+	}
+
+	// Map iteration order is random, so resample filters of individual types are added sorted by type.
+	filters := make([]string, 0, len(TypeFilters))
+
+	for name, filter := range TypeFilters {
+		filters = append(filters, name+":"+string(filter))
+	}
+
+	sort.Strings(filters)
+
+	fmt.Fprintf(h, " %s", strings.Join(filters, ","))
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// MarkerFilename returns the name of the file recording the settings hash of complete default thumbnails.
+func MarkerFilename(hash string, thumbPath string) (string, error) {
+	if len(hash) < 4 {
+		return "", fmt.Errorf("thumbs: file hash is empty or too short (\"%s\")", hash)
+	}
+
+	if len(thumbPath) == 0 {
+		return "", errors.New("thumbs: path is empty")
+	}
+
+	return path.Join(thumbPath, hash[0:1], hash[1:2], hash[2:3], hash+".done"), nil
+}
+
+// Marker returns the settings hash of the default thumbnails of a file, or an empty string if they
+// were not recorded as complete yet.
+func Marker(hash string, thumbPath string) string {
+	fileName, err := MarkerFilename(hash, thumbPath)
+
+	if err != nil {
+		return ""
+	}
+
+	data, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+// SetMarker records that the default thumbnails of a file are complete for the current settings.
+func SetMarker(hash string, thumbPath string) error {
+	fileName, err := MarkerFilename(hash, thumbPath)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(fileName), os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, []byte(SettingsHash()), 0644)
+}
@@ -0,0 +1,190 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io/ioutil"
+	"os"
+	"os/exec"
+)
+
+// ProgressiveSize is the min thumbnail size in pixels for progressive encoding.
+const ProgressiveSize = 720
+
+// Exif tags written to thumbnails.
+const (
+	exifOrientation = 0x0112
+	exifCopyright   = 0x8298
+)
+
+// encodeJpeg saves an image as JPEG without meta data, except the Copyright tag if configured.
+// Large images are encoded as progressive JPEG if enabled and jpegtran is installed.
+func encodeJpeg(img image.Image, fileName string, quality int) error {
+	var buf bytes.Buffer
+
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	size := img.Bounds().Size()
+
+	if Progressive && JpegTranBin != "" && (size.X >= ProgressiveSize || size.Y >= ProgressiveSize) {
+		var out bytes.Buffer
+
+		cmd := exec.Command(JpegTranBin, "-progressive", "-optimize", "-copy", "none")
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Stdout = &out
+
+		if err := cmd.Run(); err != nil {
+			log.Warnf("thumbs: jpegtran failed, using baseline encoding for %s (%s)", fileName, err)
+		} else {
+			data = out.Bytes()
+		}
+	}
+
+	data, err := StripMetadata(data, 0, Copyright)
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, data, os.ModePerm)
+}
+
+// StripMetadata removes Exif, XMP, IPTC and comment segments from JPEG data, so that
+// no location or camera details are exposed. Color profiles and the Adobe color transform
+// marker are kept. A minimal Exif segment containing only the orientation (if greater than 1)
+// and the copyright (if not empty) is added instead, after the JFIF segment if there is one,
+// as JFIF requires its APP0 segment to follow the start of image marker.
+func StripMetadata(data []byte, orientation int, copyright string) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("thumbs: not a jpeg image")
+	}
+
+	result := make([]byte, 0, len(data))
+	result = append(result, data[0:2]...)
+
+	exif := exifSegment(orientation, copyright)
+
+	i := 2
+
+	for {
+		if i+4 > len(data) || data[i] != 0xFF {
+			return nil, errors.New("thumbs: invalid jpeg segment")
+		}
+
+		marker := data[i+1]
+
+		// The Exif segment is added before the first segment that isn't APP0.
+		if exif != nil && marker != 0xE0 {
+			result = append(result, exif...)
+			exif = nil
+		}
+
+		// Entropy coded image data follows the start of scan marker.
+		if marker == 0xDA {
+			result = append(result, data[i:]...)
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+
+		if length < 2 || i+2+length > len(data) {
+			return nil, errors.New("thumbs: invalid jpeg segment length")
+		}
+
+		segment := data[i : i+2+length]
+
+		if keepSegment(marker, segment[4:]) {
+			result = append(result, segment...)
+		}
+
+		i += 2 + length
+	}
+
+	return result, nil
+}
+
+// keepSegment returns true if a JPEG segment is required to decode the image correctly.
+func keepSegment(marker byte, content []byte) bool {
+	switch {
+	case marker == 0xE0:
+		// JFIF.
+		return true
+	case marker == 0xE2:
+		return bytes.HasPrefix(content, []byte("ICC_PROFILE\x00"))
+	case marker == 0xEE:
+		return bytes.HasPrefix(content, []byte("Adobe"))
+	case marker >= 0xE1 && marker <= 0xEF, marker == 0xFE:
+		// Other application segments and comments.
+		return false
+	default:
+		return true
+	}
+}
+
+// exifSegment returns an APP1 segment with the orientation and copyright tags, or nil if both are empty.
+func exifSegment(orientation int, copyright string) []byte {
+	type entry struct {
+		tag, kind uint16
+		count     uint32
+		value     []byte
+	}
+
+	var entries []entry
+
+	if orientation > 1 {
+		entries = append(entries, entry{tag: exifOrientation, kind: 3, count: 1, value: []byte{0, byte(orientation), 0, 0}})
+	}
+
+	if copyright != "" {
+		entries = append(entries, entry{tag: exifCopyright, kind: 2, count: uint32(len(copyright) + 1), value: append([]byte(copyright), 0)})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// Big endian TIFF header followed by IFD0 at offset 8.
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8}
+	ifd := make([]byte, 2, 2+len(entries)*12+4)
+	binary.BigEndian.PutUint16(ifd, uint16(len(entries)))
+
+	var values []byte
+	valuesOffset := uint32(8 + 2 + len(entries)*12 + 4)
+
+	for _, e := range entries {
+		b := make([]byte, 12)
+		binary.BigEndian.PutUint16(b[0:2], e.tag)
+		binary.BigEndian.PutUint16(b[2:4], e.kind)
+		binary.BigEndian.PutUint32(b[4:8], e.count)
+
+		if len(e.value) <= 4 {
+			copy(b[8:12], e.value)
+		} else {
+			binary.BigEndian.PutUint32(b[8:12], valuesOffset+uint32(len(values)))
+			values = append(values, e.value...)
+		}
+
+		ifd = append(ifd, b...)
+	}
+
+	// No next IFD.
+	ifd = append(ifd, 0, 0, 0, 0)
+
+	payload := bytes.Join([][]byte{[]byte("Exif\x00\x00"), tiff, ifd, values}, nil)
+
+	if len(payload)+2 > 0xFFFF {
+		log.Warnf("thumbs: copyright exceeds max exif size (%d bytes)", len(copyright))
+		return exifSegment(orientation, "")
+	}
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:4], uint16(len(payload)+2))
+
+	return append(segment, payload...)
+}
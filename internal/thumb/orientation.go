@@ -0,0 +1,52 @@
+package thumb
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// openOriented decodes an image file and applies its Exif orientation. The imaging library
+// only reads the orientation of JPEG files, so it's read from the Exif data for other formats.
+func openOriented(fileName string) (image.Image, error) {
+	if fs.GetFileType(fileName) == fs.TypeJpeg {
+		return imaging.Open(fileName, imaging.AutoOrientation(true))
+	}
+
+	img, err := imaging.Open(fileName)
+
+	if err != nil {
+		return img, err
+	}
+
+	if data, err := meta.Exif(fileName); err == nil {
+		return Rotate(img, data.Orientation), nil
+	}
+
+	return img, nil
+}
+
+// Rotate returns the image rotated and flipped according to the Exif orientation. The mirrored
+// orientations 2, 4, 5 and 7 are flipped in addition to being rotated, 5 to 8 swap width and height.
+func Rotate(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return imaging.FlipH(img)
+	case 3:
+		return imaging.Rotate180(img)
+	case 4:
+		return imaging.FlipV(img)
+	case 5:
+		return imaging.Transpose(img)
+	case 6:
+		return imaging.Rotate270(img)
+	case 7:
+		return imaging.Transverse(img)
+	case 8:
+		return imaging.Rotate90(img)
+	default:
+		return img
+	}
+}
@@ -0,0 +1,170 @@
+package thumb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size is a user-defined thumbnail size that adds, overrides or removes a type.
+type Size struct {
+	Name   string
+	Width  int
+	Height int
+	Public bool
+	Method ResampleOption
+	Remove bool
+}
+
+// Internal lists types required for indexing, they can't be changed or removed.
+var Internal = []string{"tile_224", "left_224", "right_224", "colors", "fit_720"}
+
+var defaultTypes = copyTypes(Types)
+var defaultOrder = append([]string{}, DefaultTypes...)
+
+func copyTypes(types map[string]Type) map[string]Type {
+	result := make(map[string]Type, len(types))
+
+	for name, t := range types {
+		result[name] = t
+	}
+
+	return result
+}
+
+func isInternal(name string) bool {
+	for _, n := range Internal {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseSizes parses a comma separated list of thumbnail sizes like "tv_1080:1920x1080:fit:public".
+// A leading dash removes a default size, e.g. "-fit_3840". Sizes must not exceed limit.
+func ParseSizes(s string, limit int) (result []Size, err error) {
+	for _, def := range strings.Split(s, ",") {
+		def = strings.TrimSpace(def)
+
+		if def == "" {
+			continue
+		}
+
+		if strings.HasPrefix(def, "-") {
+			name := def[1:]
+
+			if name == "" {
+				return result, fmt.Errorf("thumbs: missing name in \"%s\"", def)
+			} else if isInternal(name) {
+				return result, fmt.Errorf("thumbs: %s is required and can't be removed", name)
+			}
+
+			result = append(result, Size{Name: name, Remove: true})
+			continue
+		}
+
+		parts := strings.Split(def, ":")
+
+		if len(parts) < 2 || parts[0] == "" {
+			return result, fmt.Errorf("thumbs: invalid size \"%s\", expected name:WIDTHxHEIGHT", def)
+		}
+
+		size := Size{Name: parts[0], Method: ResampleFit}
+
+		if isInternal(size.Name) {
+			return result, fmt.Errorf("thumbs: %s is required and can't be changed", size.Name)
+		}
+
+		dim := strings.Split(strings.ToLower(parts[1]), "x")
+
+		if len(dim) != 2 {
+			return result, fmt.Errorf("thumbs: invalid dimensions \"%s\" for %s", parts[1], size.Name)
+		}
+
+		if size.Width, err = strconv.Atoi(dim[0]); err != nil {
+			return result, fmt.Errorf("thumbs: invalid width \"%s\" for %s", dim[0], size.Name)
+		}
+
+		if size.Height, err = strconv.Atoi(dim[1]); err != nil {
+			return result, fmt.Errorf("thumbs: invalid height \"%s\" for %s", dim[1], size.Name)
+		}
+
+		if size.Width < 1 || size.Height < 1 || size.Width > limit || size.Height > limit {
+			return result, fmt.Errorf("thumbs: %s must be between 1 and %d pixels", size.Name, limit)
+		}
+
+		for _, token := range parts[2:] {
+			switch strings.ToLower(token) {
+			case "public":
+				size.Public = true
+			case "fit":
+				size.Method = ResampleFit
+			case "center":
+				size.Method = ResampleFillCenter
+			case "left":
+				size.Method = ResampleFillTopLeft
+			case "right":
+				size.Method = ResampleFillBottomRight
+			case "resize":
+				size.Method = ResampleResize
+			default:
+				return result, fmt.Errorf("thumbs: unknown option \"%s\" for %s", token, size.Name)
+			}
+		}
+
+		result = append(result, size)
+	}
+
+	return result, nil
+}
+
+// Install resets Types and DefaultTypes to the built-in sizes and applies the user-defined sizes.
+// It must be called before thumbnails are rendered.
+func Install(sizes []Size) {
+	types := copyTypes(defaultTypes)
+	order := append([]string{}, defaultOrder...)
+
+	for _, s := range sizes {
+		if s.Remove {
+			delete(types, s.Name)
+			continue
+		}
+
+		// New sizes are rendered last, so that existing sources can still be reused.
+		if _, ok := types[s.Name]; !ok {
+			order = append(order, s.Name)
+		}
+
+		types[s.Name] = Type{Source: "", Width: s.Width, Height: s.Height, Public: s.Public, Options: []ResampleOption{s.Method, ResampleDefault}}
+	}
+
+	for name, filter := range TypeFilters {
+		if t, ok := types[name]; ok {
+			t.Options = applyFilter(t.Options, filter)
+			types[name] = t
+		} else {
+			log.Warnf("thumbs: can't set resample filter of unknown type %s", name)
+		}
+	}
+
+	// Render from the original if a source was removed or became too small.
+	for name, t := range types {
+		if src, ok := types[t.Source]; t.Source != "" && (!ok || src.Width < t.Width || src.Height < t.Height) {
+			t.Source = ""
+			types[name] = t
+		}
+	}
+
+	result := make([]string, 0, len(order))
+
+	for _, name := range order {
+		if _, ok := types[name]; ok {
+			result = append(result, name)
+		}
+	}
+
+	Types = types
+	DefaultTypes = result
+}
@@ -1,13 +1,23 @@
 package thumb
 
-import "github.com/disintegration/imaging"
+import (
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
 
 var (
 	PreRenderSize    = 3840
 	MaxRenderSize    = 3840
 	JpegQuality      = 95
 	JpegQualitySmall = 80
+	Format           = fs.TypeJpeg
+	CWebPBin         = ""
 	Filter           = ResampleLanczos
+	UseEmbedded      = false
+	ColorManagement  = false
+	Progressive      = false
+	JpegTranBin      = ""
+	Copyright        = ""
 )
 
 const (
@@ -43,6 +53,11 @@ const (
 	ResampleNearestNeighbor
 	ResampleDefault
 	ResamplePng
+	ResampleJpeg
+	ResampleFilterBlackman
+	ResampleFilterLanczos
+	ResampleFilterCubic
+	ResampleFilterLinear
 )
 
 type ResampleOption int
@@ -90,3 +105,10 @@ func (t Type) ExceedsLimit() bool {
 func (t Type) SkipPreRender() bool {
 	return t.Width > PreRenderSize || t.Height > PreRenderSize
 }
+
+// Centered returns true if thumbnails of this type are center cropped, see SmartCrop.
+func (t Type) Centered() bool {
+	method, _, _ := ResampleOptions(t.Options...)
+
+	return method == ResampleFillCenter
+}
@@ -0,0 +1,89 @@
+package thumb
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/pkg/fs"
+
+	_ "golang.org/x/image/webp"
+)
+
+// WebpQuality maps a JPEG quality setting to a WebP quality with a similar visual result.
+// WebP looks as good at lower values, so 95 becomes 85 and 80 becomes 71.
+func WebpQuality(jpegQuality int) int {
+	if jpegQuality < 25 {
+		jpegQuality = 25
+	} else if jpegQuality > 100 {
+		jpegQuality = 100
+	}
+
+	return 20 + (jpegQuality-25)*70/75
+}
+
+// ContentType returns the mime type of a thumbnail file based on its extension.
+func ContentType(fileName string) string {
+	switch filepath.Ext(fileName) {
+	case "." + string(fs.TypeWebP):
+		return "image/webp"
+	case "." + string(fs.TypePng):
+		return "image/png"
+	default:
+		return "image/jpeg"
+	}
+}
+
+// Existing returns the name of an existing thumbnail file. If the configured format is not JPEG,
+// a JPEG thumbnail created with an earlier configuration is returned as fallback.
+func Existing(hash string, thumbPath string, width, height int, opts ...ResampleOption) (fileName string, ok bool) {
+	fileName, err := Filename(hash, thumbPath, width, height, opts...)
+
+	if err != nil {
+		return "", false
+	}
+
+	if fs.FileExists(fileName) {
+		return fileName, true
+	}
+
+	if _, _, format := ResampleOptions(opts...); format != fs.TypeWebP {
+		return fileName, false
+	}
+
+	jpegName, err := Filename(hash, thumbPath, width, height, append(opts, ResampleJpeg)...)
+
+	if err == nil && fs.FileExists(jpegName) {
+		return jpegName, true
+	}
+
+	return fileName, false
+}
+
+// encodeWebP saves an image as WebP using cwebp, as there is no native Go encoder.
+func encodeWebP(img image.Image, fileName string, quality int) error {
+	if CWebPBin == "" {
+		return errors.New("thumbs: cwebp not found, can't create webp images")
+	}
+
+	tmpName := fileName + ".tmp.png"
+
+	defer os.Remove(tmpName)
+
+	if err := imaging.Save(img, tmpName); err != nil {
+		return err
+	}
+
+	cmd := exec.Command(CWebPBin, "-quiet", "-q", strconv.Itoa(quality), "-metadata", "none", tmpName, "-o", fileName)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("thumbs: cwebp failed (%s: %s)", err, string(out))
+	}
+
+	return nil
+}
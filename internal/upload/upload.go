@@ -0,0 +1,368 @@
+/*
+Package upload implements resumable chunked uploads of large files like videos.
+
+An upload session is created with the file name and size, chunks are then appended at the
+current offset and streamed straight to a temporary file, so that memory usage doesn't depend
+on the file size. Interrupted uploads can be resumed from the offset reported for the session.
+Sessions are stored as files, so that they survive restarts, and expire if they were inactive
+for too long.
+
+Additional information can be found in our Developer Guide:
+
+https://github.com/photoprism/photoprism/wiki
+*/
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+var log = event.Log
+
+// DefaultTTL is the time after which inactive upload sessions expire.
+const DefaultTTL = 24 * time.Hour
+
+var (
+	ErrNotFound    = errors.New("upload not found")
+	ErrInvalidName = errors.New("invalid file name")
+	ErrInvalidSize = errors.New("invalid file size")
+	ErrTooLarge    = errors.New("file size exceeds limit")
+	ErrOffset      = errors.New("offset does not match")
+	ErrBusy        = errors.New("upload in progress")
+	ErrIncomplete  = errors.New("upload incomplete")
+)
+
+// Session represents an unfinished upload, the offset is the number of bytes received so far. Only the owner,
+// the id of the user who created it, may access the upload.
+type Session struct {
+	ID        string    `json:"id"`
+	Owner     uint      `json:"owner"`
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Offset    int64     `json:"offset"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Complete returns true if all bytes were received.
+func (s Session) Complete() bool {
+	return s.Offset == s.Size
+}
+
+// Store manages upload sessions in a directory.
+type Store struct {
+	dir   string
+	limit int64
+	ttl   time.Duration
+	mutex sync.Mutex
+	busy  map[string]bool
+}
+
+// New returns a new upload store. Uploads larger than limit bytes are rejected, 0 means unlimited.
+func New(dir string, limit int64, ttl time.Duration) *Store {
+	return &Store{
+		dir:   dir,
+		limit: limit,
+		ttl:   ttl,
+		busy:  make(map[string]bool),
+	}
+}
+
+// Create starts a new upload of a file with the given name and size. The path is the sub folder
+// the file should be imported from, e.g. to group files uploaded together. If it is empty, the
+// upload id is used so that the file is imported separately.
+func (s *Store) Create(owner uint, name, path string, size int64) (result Session, err error) {
+	name = filepath.Base(strings.TrimSpace(name))
+
+	if name == "" || name == "." || name == string(filepath.Separator) || strings.HasPrefix(name, ".") {
+		return result, ErrInvalidName
+	}
+
+	if size <= 0 {
+		return result, ErrInvalidSize
+	}
+
+	if s.limit > 0 && size > s.limit {
+		return result, ErrTooLarge
+	}
+
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return result, err
+	}
+
+	result = Session{
+		ID:        rnd.PPID('u'),
+		Owner:     owner,
+		Name:      name,
+		Path:      filepath.Clean("/" + path)[1:],
+		Size:      size,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if result.Path == "" {
+		result.Path = result.ID
+	}
+
+	data, err := json.Marshal(result)
+
+	if err != nil {
+		return result, err
+	}
+
+	if err := ioutil.WriteFile(s.partName(result.ID), nil, 0644); err != nil {
+		return result, err
+	}
+
+	if err := ioutil.WriteFile(s.infoName(result.ID), data, 0644); err != nil {
+		os.Remove(s.partName(result.ID))
+		return result, err
+	}
+
+	return result, nil
+}
+
+// Find returns the upload session with the given id, including the current offset. Uploads of other
+// owners are not found.
+func (s *Store) Find(owner uint, id string) (result Session, err error) {
+	if result, err = s.find(id); err != nil {
+		return result, err
+	}
+
+	if result.Owner != owner {
+		return Session{}, ErrNotFound
+	}
+
+	return result, nil
+}
+
+// find returns the upload session with the given id regardless of its owner.
+func (s *Store) find(id string) (result Session, err error) {
+	if !validID(id) {
+		return result, ErrNotFound
+	}
+
+	data, err := ioutil.ReadFile(s.infoName(id))
+
+	if err != nil {
+		return result, ErrNotFound
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, err
+	}
+
+	info, err := os.Stat(s.partName(id))
+
+	if err != nil {
+		return result, ErrNotFound
+	}
+
+	if s.ttl > 0 && time.Since(info.ModTime()) > s.ttl {
+		return result, ErrNotFound
+	}
+
+	result.Offset = info.Size()
+
+	return result, nil
+}
+
+// Append writes data to the upload, starting at the given offset which must match the number of bytes
+// received so far. Data received before an error, e.g. if the connection was interrupted, is kept so
+// that the upload can be resumed. Appending to the same upload concurrently is not possible.
+func (s *Store) Append(owner uint, id string, offset int64, r io.Reader) (result Session, err error) {
+	if err := s.lock(id); err != nil {
+		return result, err
+	}
+
+	defer s.unlock(id)
+
+	if result, err = s.Find(owner, id); err != nil {
+		return result, err
+	}
+
+	if offset != result.Offset {
+		return result, ErrOffset
+	}
+
+	f, err := os.OpenFile(s.partName(id), os.O_WRONLY|os.O_APPEND, 0644)
+
+	if err != nil {
+		return result, err
+	}
+
+	// Read one more byte than expected to detect if the file is larger than announced.
+	n, err := io.Copy(f, io.LimitReader(r, result.Size-result.Offset+1))
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	result.Offset += n
+
+	if result.Offset > result.Size {
+		if truncErr := os.Truncate(s.partName(id), result.Size); truncErr != nil {
+			log.Errorf("upload: %s", truncErr)
+		}
+
+		result.Offset = result.Size
+
+		return result, ErrTooLarge
+	}
+
+	return result, err
+}
+
+// Complete moves the uploaded file to a sub folder of dir, as given when the upload was created, and
+// returns its name. Existing files are not overwritten.
+func (s *Store) Complete(owner uint, id, dir string) (fileName string, err error) {
+	if err := s.lock(id); err != nil {
+		return "", err
+	}
+
+	defer s.unlock(id)
+
+	u, err := s.Find(owner, id)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !u.Complete() {
+		return "", ErrIncomplete
+	}
+
+	dir = filepath.Join(dir, u.Path)
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	fileName = filepath.Join(dir, u.Name)
+
+	// Add a suffix if a file with the same name was uploaded before.
+	if fs.FileExists(fileName) {
+		ext := filepath.Ext(u.Name)
+		fileName = filepath.Join(dir, strings.TrimSuffix(u.Name, ext)+"."+u.ID+ext)
+	}
+
+	if err := os.Rename(s.partName(id), fileName); err != nil {
+		return "", err
+	}
+
+	if err := os.Remove(s.infoName(id)); err != nil {
+		log.Errorf("upload: %s", err)
+	}
+
+	return fileName, nil
+}
+
+// Delete cancels an upload and removes the data received so far.
+func (s *Store) Delete(owner uint, id string) error {
+	if err := s.lock(id); err != nil {
+		return err
+	}
+
+	defer s.unlock(id)
+
+	if _, err := s.Find(owner, id); err != nil {
+		return err
+	}
+
+	s.remove(id)
+
+	return nil
+}
+
+// Cleanup removes uploads that were inactive for longer than the ttl and returns their number.
+func (s *Store) Cleanup() (removed int, err error) {
+	infos, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, info := range infos {
+		id := strings.TrimSuffix(filepath.Base(info), ".json")
+
+		if !validID(id) || s.lock(id) != nil {
+			continue
+		}
+
+		if _, err := s.find(id); err == ErrNotFound {
+			s.remove(id)
+			removed++
+		}
+
+		s.unlock(id)
+	}
+
+	return removed, nil
+}
+
+// remove deletes the files of an upload.
+func (s *Store) remove(id string) {
+	for _, fileName := range []string{s.partName(id), s.infoName(id)} {
+		if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+			log.Errorf("upload: %s", err)
+		}
+	}
+}
+
+// lock marks an upload as busy, an error is returned if it already is.
+func (s *Store) lock(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.busy[id] {
+		return ErrBusy
+	}
+
+	s.busy[id] = true
+
+	return nil
+}
+
+// unlock marks an upload as no longer busy.
+func (s *Store) unlock(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.busy, id)
+}
+
+// partName returns the name of the file containing the data received so far.
+func (s *Store) partName(id string) string {
+	return filepath.Join(s.dir, id+".part")
+}
+
+// infoName returns the name of the file containing the session information.
+func (s *Store) infoName(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// validID returns true if the id can safely be used as file name.
+func validID(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+
+	return true
+}
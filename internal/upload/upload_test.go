@@ -0,0 +1,376 @@
+package upload
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+// interruptedReader returns an error after reading n bytes, like a connection that was closed.
+type interruptedReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *interruptedReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, errors.New("connection reset by peer")
+	}
+
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+
+	n, err := r.r.Read(p)
+	r.n -= n
+
+	return n, err
+}
+
+func testData(size int) []byte {
+	data := make([]byte, size)
+	rand.New(rand.NewSource(42)).Read(data)
+	return data
+}
+
+func sha1Hash(data []byte) string {
+	h := sha1.Sum(data)
+	return hex.EncodeToString(h[:])
+}
+
+func testStore(t *testing.T, limit int64) (*Store, string) {
+	dir, err := ioutil.TempDir("", "upload")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(filepath.Join(dir, ".uploads"), limit, DefaultTTL), dir
+}
+
+func TestStore_Create(t *testing.T) {
+	s, dir := testStore(t, 1000)
+	defer os.RemoveAll(dir)
+
+	t.Run("success", func(t *testing.T) {
+		u, err := s.Create(1, "../../video.mp4", "../foo", 500)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotEmpty(t, u.ID)
+		assert.Equal(t, "video.mp4", u.Name)
+		assert.Equal(t, "foo", u.Path)
+		assert.Equal(t, int64(500), u.Size)
+		assert.Equal(t, int64(0), u.Offset)
+
+		found, err := s.Find(1, u.ID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, u.Name, found.Name)
+		assert.Equal(t, int64(0), found.Offset)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := s.Create(1, ".htaccess", "", 500)
+		assert.Equal(t, ErrInvalidName, err)
+
+		_, err = s.Create(1, "", "", 500)
+		assert.Equal(t, ErrInvalidName, err)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := s.Create(1, "video.mp4", "", 0)
+		assert.Equal(t, ErrInvalidSize, err)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		_, err := s.Create(1, "video.mp4", "", 1001)
+		assert.Equal(t, ErrTooLarge, err)
+	})
+}
+
+func TestStore_Find(t *testing.T) {
+	s, dir := testStore(t, 0)
+	defer os.RemoveAll(dir)
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := s.Find(1, "uqxetse3cy5eo9z2")
+		assert.Equal(t, ErrNotFound, err)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := s.Find(1, "../config")
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestStore_Append(t *testing.T) {
+	t.Run("interrupted and resumed", func(t *testing.T) {
+		s, dir := testStore(t, 0)
+		defer os.RemoveAll(dir)
+		data := testData(100000)
+
+		u, err := s.Create(1, "video.mp4", "2020", int64(len(data)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The connection fails after 30000 bytes.
+		u, err = s.Append(1, u.ID, 0, &interruptedReader{r: bytes.NewReader(data), n: 30000})
+
+		assert.Error(t, err)
+		assert.Equal(t, int64(30000), u.Offset)
+
+		// Completing an incomplete upload fails.
+		_, err = s.Complete(1, u.ID, dir)
+		assert.Equal(t, ErrIncomplete, err)
+
+		// A restarted client asks the server for the offset.
+		s = New(s.dir, 0, DefaultTTL)
+
+		u, err = s.Find(1, u.ID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, int64(30000), u.Offset)
+
+		// Appending at the wrong offset fails.
+		_, err = s.Append(1, u.ID, 0, bytes.NewReader(data))
+		assert.Equal(t, ErrOffset, err)
+
+		// Resume from the reported offset.
+		u, err = s.Append(1, u.ID, u.Offset, bytes.NewReader(data[u.Offset:]))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, u.Complete())
+
+		fileName, err := s.Complete(1, u.ID, dir)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, filepath.Join(dir, "2020", "video.mp4"), fileName)
+
+		result, err := ioutil.ReadFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, sha1Hash(data), sha1Hash(result))
+
+		_, err = s.Find(1, u.ID)
+		assert.Equal(t, ErrNotFound, err)
+	})
+
+	t.Run("larger than announced", func(t *testing.T) {
+		s, dir := testStore(t, 0)
+		defer os.RemoveAll(dir)
+		data := testData(1000)
+
+		u, err := s.Create(1, "video.mp4", "", 500)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		u, err = s.Append(1, u.ID, 0, bytes.NewReader(data))
+
+		assert.Equal(t, ErrTooLarge, err)
+		assert.Equal(t, int64(500), u.Offset)
+	})
+
+	t.Run("concurrent chunks", func(t *testing.T) {
+		s, dir := testStore(t, 0)
+		defer os.RemoveAll(dir)
+
+		u, err := s.Create(1, "video.mp4", "", 500)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Simulate a chunk that is still being received.
+		if err := s.lock(u.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = s.Append(1, u.ID, 0, bytes.NewReader(testData(500)))
+		assert.Equal(t, ErrBusy, err)
+
+		_, err = s.Complete(1, u.ID, s.dir)
+		assert.Equal(t, ErrBusy, err)
+
+		s.unlock(u.ID)
+
+		u, err = s.Append(1, u.ID, 0, bytes.NewReader(testData(500)))
+
+		assert.NoError(t, err)
+		assert.True(t, u.Complete())
+	})
+}
+
+func TestStore_Complete(t *testing.T) {
+	t.Run("existing file", func(t *testing.T) {
+		s, dir := testStore(t, 0)
+		defer os.RemoveAll(dir)
+
+		if err := os.MkdirAll(filepath.Join(dir, "2020"), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, "2020", "photo.jpg"), []byte("foo"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := s.Create(1, "photo.jpg", "2020", 3)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := s.Append(1, u.ID, 0, bytes.NewReader([]byte("bar"))); err != nil {
+			t.Fatal(err)
+		}
+
+		fileName, err := s.Complete(1, u.ID, dir)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, filepath.Join(dir, "2020", "photo."+u.ID+".jpg"), fileName)
+	})
+
+	t.Run("empty path", func(t *testing.T) {
+		s, dir := testStore(t, 0)
+		defer os.RemoveAll(dir)
+
+		u, err := s.Create(1, "photo.jpg", "", 3)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, u.ID, u.Path)
+
+		if _, err := s.Append(1, u.ID, 0, bytes.NewReader([]byte("bar"))); err != nil {
+			t.Fatal(err)
+		}
+
+		fileName, err := s.Complete(1, u.ID, dir)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, filepath.Join(dir, u.ID, "photo.jpg"), fileName)
+	})
+}
+
+func TestStore_Delete(t *testing.T) {
+	s, dir := testStore(t, 0)
+	defer os.RemoveAll(dir)
+
+	u, err := s.Create(1, "video.mp4", "", 500)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ErrNotFound, s.Delete(2, u.ID))
+	assert.NoError(t, s.Delete(1, u.ID))
+	assert.Equal(t, ErrNotFound, s.Delete(1, u.ID))
+}
+
+func TestStore_Owner(t *testing.T) {
+	s, dir := testStore(t, 0)
+	defer os.RemoveAll(dir)
+
+	u, err := s.Create(1, "photo.jpg", "", 3)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads of other users are not found, even if they know the id.
+	_, err = s.Find(2, u.ID)
+	assert.Equal(t, ErrNotFound, err)
+
+	_, err = s.Append(2, u.ID, 0, bytes.NewReader([]byte("bar")))
+	assert.Equal(t, ErrNotFound, err)
+
+	if _, err := s.Append(1, u.ID, 0, bytes.NewReader([]byte("bar"))); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Complete(2, u.ID, dir)
+	assert.Equal(t, ErrNotFound, err)
+
+	found, err := s.Find(1, u.ID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(1), found.Owner)
+	assert.True(t, found.Complete())
+}
+
+func TestStore_Cleanup(t *testing.T) {
+	s, dir := testStore(t, 0)
+	defer os.RemoveAll(dir)
+
+	expired, err := s.Create(1, "expired.mp4", "", 500)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := s.Create(1, "active.mp4", "", 500)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Now().Add(-2 * DefaultTTL)
+
+	if err := os.Chtimes(s.partName(expired.ID), past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := s.Cleanup()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	_, err = s.Find(1, expired.ID)
+	assert.Equal(t, ErrNotFound, err)
+	assert.False(t, fs.FileExists(s.partName(expired.ID)))
+
+	_, err = s.Find(1, active.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,218 @@
+/*
+Package webhook notifies external services like home automation of events, for example when an import
+is completed, by sending a JSON POST request to one or more URLs.
+
+Events are received from the event hub and delivered by a separate goroutine, so that workers
+publishing an event are never blocked by slow or unavailable webhook targets. Requests are signed
+with HMAC-SHA256 if a secret is configured.
+
+Additional information can be found in our Developer Guide:
+
+https://github.com/photoprism/photoprism/wiki
+*/
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+var log = event.Log
+
+// Request headers containing the event name and the request body signature.
+const (
+	HeaderEvent     = "X-PhotoPrism-Event"
+	HeaderSignature = "X-PhotoPrism-Signature"
+)
+
+// Payload is the JSON request body sent to webhooks.
+type Payload struct {
+	Event string     `json:"event"`
+	Time  time.Time  `json:"time"`
+	Data  event.Data `json:"data"`
+}
+
+// Options configure targets, events and delivery of a webhook dispatcher.
+type Options struct {
+	URLs       []string
+	Secret     string
+	Events     []string
+	Buffer     int           // Max number of queued events, further events are dropped.
+	Retries    int           // Max number of retries after a failed request.
+	Backoff    time.Duration // Delay before the first retry, doubled for each further retry.
+	MaxBackoff time.Duration
+	Timeout    time.Duration
+}
+
+// DefaultOptions returns options with default delivery settings for the given targets and events.
+func DefaultOptions(urls []string, secret string, events []string) Options {
+	return Options{
+		URLs:       urls,
+		Secret:     secret,
+		Events:     events,
+		Buffer:     100,
+		Retries:    5,
+		Backoff:    2 * time.Second,
+		MaxBackoff: time.Minute,
+		Timeout:    15 * time.Second,
+	}
+}
+
+// Dispatcher sends events published to a hub to webhook targets.
+type Dispatcher struct {
+	opt    Options
+	hub    *event.Hub
+	client *http.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// New returns a new dispatcher for events published to the hub.
+func New(h *event.Hub, opt Options) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		opt:    opt,
+		hub:    h,
+		client: &http.Client{Timeout: opt.Timeout},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start subscribes to the configured events and starts delivering them. Nothing happens if there are no targets.
+func (d *Dispatcher) Start() {
+	if len(d.opt.URLs) == 0 || len(d.opt.Events) == 0 {
+		return
+	}
+
+	// Non-blocking, so that publishers are never slowed down if the buffer is full.
+	sub := d.hub.NonBlockingSubscribe(d.opt.Buffer, d.opt.Events...)
+
+	d.done.Add(1)
+
+	go func() {
+		defer d.done.Done()
+		defer d.hub.Unsubscribe(sub)
+
+		for {
+			select {
+			case <-d.ctx.Done():
+				return
+			case msg, ok := <-sub.Receiver:
+				if !ok {
+					return
+				}
+
+				d.Send(Payload{Event: msg.Name, Time: time.Now().UTC(), Data: msg.Fields})
+			}
+		}
+	}()
+
+	log.Infof("webhook: sending %v events to %d targets", d.opt.Events, len(d.opt.URLs))
+}
+
+// Stop cancels pending requests and waits until delivery is stopped.
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.done.Wait()
+}
+
+// Send delivers the payload to all targets, failed requests are logged.
+func (d *Dispatcher) Send(p Payload) {
+	body, err := json.Marshal(p)
+
+	if err != nil {
+		log.Errorf("webhook: %s", err)
+		return
+	}
+
+	for _, url := range d.opt.URLs {
+		if err := d.deliver(url, p.Event, body); err != nil {
+			log.Errorf("webhook: %s event not delivered to %s (%s)", p.Event, url, err)
+		}
+	}
+}
+
+// deliver sends the request body to the url, failed requests are retried with exponential backoff.
+func (d *Dispatcher) deliver(url, name string, body []byte) error {
+	backoff := d.opt.Backoff
+
+	for attempt := 0; ; attempt++ {
+		retry, err := d.post(url, name, body)
+
+		if err == nil || !retry || attempt >= d.opt.Retries {
+			return err
+		}
+
+		log.Debugf("webhook: %s, retrying in %s", err, backoff)
+
+		select {
+		case <-d.ctx.Done():
+			return fmt.Errorf("%s, canceled", err)
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; d.opt.MaxBackoff > 0 && backoff > d.opt.MaxBackoff {
+			backoff = d.opt.MaxBackoff
+		}
+	}
+}
+
+// post sends a single request and returns true if it may be retried in case of an error.
+func (d *Dispatcher) post(url, name string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+
+	if err != nil {
+		return false, err
+	}
+
+	req = req.WithContext(d.ctx)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, name)
+
+	if d.opt.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(d.opt.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+
+	if err != nil {
+		return true, err
+	}
+
+	// Read the response body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("status %d", resp.StatusCode)
+	case resp.StatusCode >= 300:
+		return false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	return false, nil
+}
+
+// Sign returns the signature of a request body, e.g. "sha256=6f3c...", as sent in the signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+// request is a webhook request received by a test server.
+type request struct {
+	Header http.Header
+	Body   []byte
+}
+
+// testServer returns a server recording received requests, which responds with the given status codes
+// in order. The last status code is repeated for further requests.
+func testServer(t *testing.T, status ...int) (*httptest.Server, func() []request) {
+	var mutex sync.Mutex
+	var requests []request
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		mutex.Lock()
+		requests = append(requests, request{Header: r.Header, Body: body})
+		n := len(requests)
+		mutex.Unlock()
+
+		if n > len(status) {
+			n = len(status)
+		}
+
+		w.WriteHeader(status[n-1])
+	}))
+
+	return s, func() []request {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]request(nil), requests...)
+	}
+}
+
+// testOptions returns options with short delays for testing.
+func testOptions(urls ...string) Options {
+	opt := DefaultOptions(urls, "secret", []string{"import.completed", "notify.*"})
+	opt.Backoff = time.Millisecond
+	opt.MaxBackoff = 4 * time.Millisecond
+	opt.Retries = 3
+
+	return opt
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=f9e66e179b6747ae54108f82f8ade8b3c25d76fd30afde6c395822c530196169", Sign("secret", []byte("")))
+	assert.NotEqual(t, Sign("secret", []byte("foo")), Sign("other", []byte("foo")))
+}
+
+func TestDispatcher_Send(t *testing.T) {
+	t.Run("payload", func(t *testing.T) {
+		s, requests := testServer(t, http.StatusOK)
+		defer s.Close()
+
+		d := New(event.NewHub(), testOptions(s.URL))
+		takenAt := time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)
+
+		d.Send(Payload{Event: "import.completed", Time: takenAt, Data: event.Data{"path": "/import", "seconds": 3, "added": 12, "duplicates": 2}})
+
+		result := requests()
+
+		if assert.Len(t, result, 1) {
+			r := result[0]
+
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "import.completed", r.Header.Get(HeaderEvent))
+			assert.Equal(t, Sign("secret", r.Body), r.Header.Get(HeaderSignature))
+			assert.JSONEq(t, `{"event":"import.completed","time":"2020-05-01T12:30:00Z","data":{"path":"/import","seconds":3,"added":12,"duplicates":2}}`, string(r.Body))
+		}
+	})
+	t.Run("no secret", func(t *testing.T) {
+		s, requests := testServer(t, http.StatusOK)
+		defer s.Close()
+
+		opt := testOptions(s.URL)
+		opt.Secret = ""
+
+		New(event.NewHub(), opt).Send(Payload{Event: "import.completed"})
+
+		if result := requests(); assert.Len(t, result, 1) {
+			assert.Equal(t, "", result[0].Header.Get(HeaderSignature))
+		}
+	})
+	t.Run("multiple targets", func(t *testing.T) {
+		s1, requests1 := testServer(t, http.StatusOK)
+		defer s1.Close()
+
+		s2, requests2 := testServer(t, http.StatusNoContent)
+		defer s2.Close()
+
+		New(event.NewHub(), testOptions(s1.URL, s2.URL)).Send(Payload{Event: "import.completed"})
+
+		assert.Len(t, requests1(), 1)
+		assert.Len(t, requests2(), 1)
+	})
+	t.Run("retry on 500", func(t *testing.T) {
+		s, requests := testServer(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+		defer s.Close()
+
+		d := New(event.NewHub(), testOptions(s.URL))
+
+		assert.Nil(t, d.deliver(s.URL, "import.completed", []byte("{}")))
+		assert.Len(t, requests(), 3)
+	})
+	t.Run("retries are capped", func(t *testing.T) {
+		s, requests := testServer(t, http.StatusInternalServerError)
+		defer s.Close()
+
+		d := New(event.NewHub(), testOptions(s.URL))
+
+		assert.Error(t, d.deliver(s.URL, "import.completed", []byte("{}")))
+		assert.Len(t, requests(), 4)
+	})
+	t.Run("no retry on 400", func(t *testing.T) {
+		s, requests := testServer(t, http.StatusBadRequest, http.StatusOK)
+		defer s.Close()
+
+		d := New(event.NewHub(), testOptions(s.URL))
+
+		assert.Error(t, d.deliver(s.URL, "import.completed", []byte("{}")))
+		assert.Len(t, requests(), 1)
+	})
+}
+
+func TestDispatcher_Start(t *testing.T) {
+	t.Run("events", func(t *testing.T) {
+		s, requests := testServer(t, http.StatusOK)
+		defer s.Close()
+
+		h := event.NewHub()
+		d := New(h, testOptions(s.URL))
+		d.Start()
+		defer d.Stop()
+
+		h.Publish(event.Message{Name: "index.indexing", Fields: event.Data{"fileName": "foo.jpg"}})
+		h.Publish(event.Message{Name: "notify.error", Fields: event.Data{"msg": "failed"}})
+		h.Publish(event.Message{Name: "import.completed", Fields: event.Data{"path": "/import"}})
+
+		assert.Eventually(t, func() bool { return len(requests()) == 2 }, time.Second, 5*time.Millisecond)
+
+		var names []string
+
+		for _, r := range requests() {
+			var p Payload
+
+			if err := json.Unmarshal(r.Body, &p); err != nil {
+				t.Fatal(err)
+			}
+
+			names = append(names, p.Event)
+			assert.False(t, p.Time.IsZero())
+		}
+
+		assert.Equal(t, []string{"notify.error", "import.completed"}, names)
+	})
+	t.Run("publishers are not blocked", func(t *testing.T) {
+		release := make(chan bool)
+
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+
+		defer s.Close()
+
+		h := event.NewHub()
+		opt := testOptions(s.URL)
+		opt.Buffer = 2
+		d := New(h, opt)
+		d.Start()
+		defer d.Stop()
+		defer close(release)
+
+		done := make(chan bool)
+
+		go func() {
+			for i := 0; i < 100; i++ {
+				h.Publish(event.Message{Name: "import.completed", Fields: event.Data{}})
+			}
+
+			done <- true
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("publishing events was blocked by a slow webhook target")
+		}
+	})
+	t.Run("no targets", func(t *testing.T) {
+		d := New(event.NewHub(), testOptions())
+		d.Start()
+		d.Stop()
+	})
+}
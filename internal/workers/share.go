@@ -2,7 +2,6 @@ package workers
 
 import (
 	"fmt"
-	"path"
 	"path/filepath"
 
 	"github.com/photoprism/photoprism/internal/config"
@@ -84,7 +83,7 @@ func (s *Share) Start() (err error) {
 				}
 			}
 
-			srcFileName := path.Join(s.conf.OriginalsPath(), file.File.FileName)
+			srcFileName := s.conf.OriginalsFileName(file.File.FileRoot, file.File.FileName)
 
 			if a.ShareSize != "" {
 				thumbType, ok := thumb.Types[a.ShareSize]
@@ -94,7 +93,17 @@ func (s *Share) Start() (err error) {
 					continue
 				}
 
-				srcFileName, err = thumb.FromFile(srcFileName, file.File.FileHash, s.conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...)
+				// Remote file names have a .jpg extension, so shared thumbnails are always JPEG.
+				opts := append([]thumb.ResampleOption{}, thumbType.Options...)
+				opts = append(opts, thumb.ResampleJpeg)
+
+				var crop thumb.Crop
+
+				if thumb.SmartCrop {
+					crop, _ = thumb.ParseCrop(file.File.FileCrop)
+				}
+
+				srcFileName, err = thumb.FromFileCrop(srcFileName, file.File.FileHash, s.conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, crop, opts...)
 
 				if err != nil {
 					log.Errorf("share: %s", err)
@@ -55,6 +55,14 @@ func (s *Sync) Start() (err error) {
 			continue
 		}
 
+		if window, err := remote.ParseWindow(a.SyncWindow); err != nil {
+			log.Warnf("sync: %s (%s)", err, a.AccName)
+			continue
+		} else if !window.Open(time.Now()) {
+			log.Debugf("sync: skipped %s outside of sync window %s", a.AccName, window)
+			continue
+		}
+
 		// Values updated in account: AccError, AccErrors, SyncStatus, SyncDate
 		accError := a.AccError
 		accErrors := a.AccErrors
@@ -137,3 +145,10 @@ func (s *Sync) Start() (err error) {
 
 	return err
 }
+
+// syncDeadline returns the time the sync window of an account closes, or the zero time if it's always open.
+func syncDeadline(a entity.Account) time.Time {
+	window, _ := remote.ParseWindow(a.SyncWindow)
+
+	return window.End(time.Now())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/mutex"
@@ -48,6 +49,10 @@ func (s *Sync) relatedDownloads(a entity.Account) (result Downloads, err error)
 
 // Downloads remote files in batches and imports / indexes them
 func (s *Sync) download(a entity.Account) (complete bool, err error) {
+	if s.conf.ReadOnly() {
+		return false, config.ErrReadOnly
+	}
+
 	db := s.conf.Db()
 
 	// Set up index worker
@@ -75,7 +80,9 @@ func (s *Sync) download(a entity.Account) (complete bool, err error) {
 
 	log.Infof("sync: downloading from %s", a.AccName)
 
+	throttle := webdav.NewThrottle(a.SyncDownloadLimit, syncDeadline(a))
 	client := webdav.New(a.AccURL, a.AccUser, a.AccPass)
+	client.Throttle(nil, throttle)
 
 	var baseDir string
 
@@ -88,11 +95,22 @@ func (s *Sync) download(a entity.Account) (complete bool, err error) {
 	done := make(map[string]bool)
 
 	for _, files := range relatedFiles {
+		if throttle.Expired() {
+			log.Infof("sync: window closed, resuming download from %s on next run", a.AccName)
+			return false, nil
+		}
+
 		for i, file := range files {
+			mutex.Interactive.Pause(mutex.Sync.Canceled)
+
 			if mutex.Sync.Canceled() {
 				return false, nil
 			}
 
+			if throttle.Expired() {
+				break
+			}
+
 			if file.Errors > a.RetryLimit {
 				log.Debugf("sync: downloading %s failed more than %d times", file.RemoteName, a.RetryLimit)
 				continue
@@ -104,13 +122,21 @@ func (s *Sync) download(a entity.Account) (complete bool, err error) {
 				log.Warnf("sync: download skipped, %s already exists", localName)
 				file.Status = entity.FileSyncExists
 			} else {
-				if err := client.Download(file.RemoteName, localName, false); err != nil {
+				if err := client.Download(file.RemoteName, localName, false); err == webdav.ErrDeadline {
+					// Downloaded again on the next run, files already downloaded are imported below.
+					log.Infof("sync: window closed, resuming download of %s on next run", file.RemoteName)
+					break
+				} else if err != nil {
 					log.Errorf("sync: %s", err.Error())
 					file.Errors++
 					file.Error = err.Error()
 				} else {
 					log.Infof("sync: downloaded %s from %s", file.RemoteName, a.AccName)
 					file.Status = entity.FileSyncDownloaded
+
+					if info, err := os.Stat(localName); err == nil {
+						file.SetLocal(info.ModTime(), info.Size())
+					}
 				}
 
 				if mutex.Sync.Canceled() {
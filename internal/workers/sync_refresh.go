@@ -8,7 +8,8 @@ import (
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
-// Updates the local list of remote files so that they can be downloaded in batches
+// Updates the local list of remote files so that they can be downloaded in batches, changes
+// of files that were synced before are reconciled if files are downloaded and uploaded
 func (s *Sync) refresh(a entity.Account) (complete bool, err error) {
 	if a.AccType != remote.ServiceWebDAV {
 		return false, nil
@@ -25,6 +26,7 @@ func (s *Sync) refresh(a entity.Account) (complete bool, err error) {
 	}
 
 	dirs := append(subDirs.Abs(), a.SyncPath)
+	listing := make(map[string]fs.FileInfo)
 
 	for _, dir := range dirs {
 		if mutex.Sync.Canceled() {
@@ -43,11 +45,12 @@ func (s *Sync) refresh(a entity.Account) (complete bool, err error) {
 				return false, nil
 			}
 
+			listing[file.Abs] = file
+
 			f := entity.NewFileSync(a.ID, file.Abs)
 
 			f.Status = entity.FileSyncIgnore
-			f.RemoteDate = file.Date
-			f.RemoteSize = file.Size
+			f.SetRemote(file.ETag, file.Date, file.Size)
 
 			// Select supported types for download
 			mediaType := fs.GetMediaType(file.Name)
@@ -69,14 +72,18 @@ func (s *Sync) refresh(a entity.Account) (complete bool, err error) {
 				db.Save(&f)
 			}
 
-			if f.Status == entity.FileSyncDownloaded && !f.RemoteDate.Equal(file.Date) {
+			// Changes of two-way synced files are reconciled below.
+			if !a.TwoWaySync() && f.Status == entity.FileSyncDownloaded && !f.RemoteDate.Equal(file.Date) {
 				f.Status = entity.FileSyncNew
-				f.RemoteDate = file.Date
-				f.RemoteSize = file.Size
+				f.SetRemote(file.ETag, file.Date, file.Size)
 				db.Save(&f)
 			}
 		}
 	}
 
+	if a.TwoWaySync() {
+		return s.reconcile(a, listing)
+	}
+
 	return true, nil
 }
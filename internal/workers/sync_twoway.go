@@ -0,0 +1,241 @@
+package workers
+
+import (
+	"os"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/remote"
+	"github.com/photoprism/photoprism/internal/remote/webdav"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// localName returns the local file name of a synced file, or an empty string if it's unknown.
+func (s *Sync) localName(a entity.Account, f entity.FileSync) string {
+	if f.File != nil && f.File.FileName != "" {
+		return s.conf.OriginalsFileName(f.File.FileRoot, f.File.FileName)
+	} else if a.SyncFilenames {
+		return s.conf.OriginalsPath() + f.RemoteName
+	}
+
+	return ""
+}
+
+// localVersion returns the current version of a local file, or nil if it doesn't exist.
+func localVersion(fileName string) *remote.Version {
+	info, err := os.Stat(fileName)
+
+	if err != nil {
+		return nil
+	}
+
+	return &remote.Version{Date: info.ModTime(), Size: info.Size()}
+}
+
+// Compares files changed locally and remotely since the last sync and resolves conflicts
+// according to the account policy. The cursor is stored after every file, so that an
+// interrupted run continues where it stopped.
+func (s *Sync) reconcile(a entity.Account, listing map[string]fs.FileInfo) (complete bool, err error) {
+	if s.conf.ReadOnly() {
+		return true, nil
+	}
+
+	db := s.conf.Db()
+	maxResults := 250
+
+	files, err := s.q.SyncedFiles(a.ID, a.SyncCursor, maxResults)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(files) == 0 {
+		if a.SyncCursor != "" {
+			if err := db.Model(&a).UpdateColumn("sync_cursor", "").Error; err != nil {
+				return false, err
+			}
+		}
+
+		log.Infof("sync: reconciled local and remote changes for %s", a.AccName)
+
+		return true, nil
+	}
+
+	// Set up index worker
+	indexJobs := make(chan photoprism.IndexJob)
+	go photoprism.IndexWorker(indexJobs)
+	defer close(indexJobs)
+
+	throttle := webdav.NewThrottle(a.SyncUploadLimit, syncDeadline(a))
+	client := webdav.New(a.AccURL, a.AccUser, a.AccPass)
+	client.Throttle(throttle, webdav.NewThrottle(a.SyncDownloadLimit, syncDeadline(a)))
+
+	for _, file := range files {
+		if mutex.Sync.Canceled() {
+			return false, nil
+		}
+
+		if throttle.Expired() {
+			log.Infof("sync: window closed, resuming reconcile for %s on next run", a.AccName)
+			return false, nil
+		}
+
+		localName := s.localName(a, file)
+
+		if localName == "" {
+			// Imported files can't be matched with their remote name, so they are only synced one way.
+			log.Debugf("sync: no local file known for %s, skipped", file.RemoteName)
+		} else if err := s.reconcileFile(a, client, file, localName, listing, indexJobs); err != nil {
+			if err == webdav.ErrDeadline || throttle.Expired() {
+				log.Infof("sync: window closed, resuming reconcile of %s on next run", file.RemoteName)
+				return false, nil
+			}
+
+			log.Errorf("sync: %s", err.Error())
+		}
+
+		a.SyncCursor = file.RemoteName
+
+		if err := db.Model(&a).UpdateColumn("sync_cursor", a.SyncCursor).Error; err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// reconcileFile syncs a single local file with its remote version.
+func (s *Sync) reconcileFile(a entity.Account, client webdav.Client, file entity.FileSync, localName string, listing map[string]fs.FileInfo, indexJobs chan photoprism.IndexJob) error {
+	db := s.conf.Db()
+
+	local := localVersion(localName)
+
+	var remoteFile *remote.Version
+
+	info, found := listing[file.RemoteName]
+
+	if found {
+		remoteFile = &remote.Version{ETag: info.ETag, Date: info.Date, Size: info.Size}
+	}
+
+	action := remote.Reconcile(file.LocalVersion(), file.RemoteVersion(), local, remoteFile, a.SyncConflicts, a.SyncDeletes)
+
+	switch action {
+	case remote.SyncNone:
+		// Files synced before two-way sync was supported have no local version yet.
+		if file.LocalVersion().Unknown() && local != nil {
+			file.SetLocal(local.Date, local.Size)
+
+			return db.Save(&file).Error
+		}
+
+		return nil
+	case remote.SyncForget:
+		log.Infof("sync: %s was deleted locally and on %s", file.RemoteName, a.AccName)
+		return db.Delete(&file).Error
+	case remote.SyncDeleteLocal:
+		if err := os.Remove(localName); err != nil {
+			return err
+		}
+
+		if file.File != nil {
+			if err := db.Model(file.File).UpdateColumn("file_missing", true).Error; err != nil {
+				return err
+			}
+		}
+
+		log.Infof("sync: deleted %s, it was deleted on %s", localName, a.AccName)
+
+		return db.Delete(&file).Error
+	case remote.SyncDeleteRemote:
+		if err := client.Delete(file.RemoteName); err != nil {
+			return err
+		}
+
+		log.Infof("sync: deleted %s on %s, it was deleted locally", file.RemoteName, a.AccName)
+
+		return db.Delete(&file).Error
+	case remote.SyncKeepBoth:
+		conflictName := remote.ConflictName(file.RemoteName, info.Date)
+
+		if err := client.Rename(file.RemoteName, conflictName); err != nil {
+			return err
+		}
+
+		// The remote version is downloaded with its new name, like any other new remote file.
+		conflict := entity.NewFileSync(a.ID, conflictName)
+		conflict.SetRemote(info.ETag, info.Date, info.Size)
+
+		if err := db.Save(conflict).Error; err != nil {
+			return err
+		}
+
+		log.Infof("sync: both versions of %s changed, kept remote version as %s", file.RemoteName, conflictName)
+
+		return s.reconcileUpload(client, file, localName, local)
+	case remote.SyncUpload:
+		return s.reconcileUpload(client, file, localName, local)
+	case remote.SyncDownload:
+		if err := client.Download(file.RemoteName, localName, true); err != nil {
+			return err
+		}
+
+		if local = localVersion(localName); local == nil {
+			return os.ErrNotExist
+		}
+
+		file.SetLocal(local.Date, local.Size)
+		file.SetRemote(info.ETag, info.Date, info.Size)
+		file.Status = entity.FileSyncDownloaded
+
+		if err := db.Save(&file).Error; err != nil {
+			return err
+		}
+
+		log.Infof("sync: downloaded changes of %s from %s", file.RemoteName, a.AccName)
+
+		mf, err := photoprism.NewMediaFile(localName)
+
+		if err != nil || !mf.IsPhoto() {
+			return nil
+		}
+
+		related, err := mf.RelatedFiles(s.conf.Settings().Library.GroupRelated)
+
+		if err != nil {
+			return err
+		}
+
+		indexJobs <- photoprism.IndexJob{
+			FileName: mf.FileName(),
+			Related:  related,
+			IndexOpt: photoprism.IndexOptionsAll(),
+			Ind:      service.Index(),
+		}
+	}
+
+	return nil
+}
+
+// reconcileUpload uploads a changed local file and remembers both versions.
+func (s *Sync) reconcileUpload(client webdav.Client, file entity.FileSync, localName string, local *remote.Version) error {
+	if err := client.Upload(localName, file.RemoteName); err != nil {
+		return err
+	}
+
+	file.SetLocal(local.Date, local.Size)
+	file.Status = entity.FileSyncUploaded
+
+	if info, err := client.Stat(file.RemoteName); err != nil {
+		log.Warnf("sync: %s", err.Error())
+		file.SetRemote("", local.Date, local.Size)
+	} else {
+		file.SetRemote(info.ETag, info.Date, info.Size)
+	}
+
+	log.Infof("sync: uploaded changes of %s", localName)
+
+	return s.conf.Db().Save(&file).Error
+}
@@ -1,6 +1,7 @@
 package workers
 
 import (
+	"os"
 	"path"
 	"path/filepath"
 	"time"
@@ -30,15 +31,24 @@ func (s *Sync) upload(a entity.Account) (complete bool, err error) {
 		return true, nil
 	}
 
+	throttle := webdav.NewThrottle(a.SyncUploadLimit, syncDeadline(a))
 	client := webdav.New(a.AccURL, a.AccUser, a.AccPass)
+	client.Throttle(throttle, nil)
 	existingDirs := make(map[string]string)
 
 	for _, file := range files {
+		mutex.Interactive.Pause(mutex.Sync.Canceled)
+
 		if mutex.Sync.Canceled() {
 			return false, nil
 		}
 
-		fileName := path.Join(s.conf.OriginalsPath(), file.FileName)
+		if throttle.Expired() {
+			log.Infof("sync: window closed, resuming upload to %s on next run", a.AccName)
+			return false, nil
+		}
+
+		fileName := s.conf.OriginalsFileName(file.FileRoot, file.FileName)
 		remoteName := path.Join(a.SyncPath, file.FileName)
 		remoteDir := filepath.Dir(remoteName)
 
@@ -50,6 +60,12 @@ func (s *Sync) upload(a entity.Account) (complete bool, err error) {
 		}
 
 		if err := client.Upload(fileName, remoteName); err != nil {
+			if throttle.Expired() {
+				// Incomplete remote files are overwritten when the upload is resumed.
+				log.Infof("sync: window closed, resuming upload of %s to %s on next run", fileName, a.AccName)
+				return false, nil
+			}
+
 			log.Errorf("sync: %s", err.Error())
 			continue // try again next time
 		}
@@ -61,6 +77,15 @@ func (s *Sync) upload(a entity.Account) (complete bool, err error) {
 		fileSync.RemoteDate = time.Now()
 		fileSync.RemoteSize = file.FileSize
 		fileSync.FileID = file.ID
+
+		// Remember both versions, so that later changes can be detected.
+		if info, err := os.Stat(fileName); err == nil {
+			fileSync.SetLocal(info.ModTime(), info.Size())
+		}
+
+		if info, err := client.Stat(remoteName); err == nil {
+			fileSync.SetRemote(info.ETag, info.Date, info.Size)
+		}
 		fileSync.Error = ""
 		fileSync.Errors = 0
 
@@ -4,8 +4,12 @@ import (
 	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/internal/webhook"
 )
 
 var log = event.Log
@@ -15,18 +19,66 @@ var stop = make(chan bool, 1)
 func Start(conf *config.Config) {
 	ticker := time.NewTicker(conf.WakeupInterval())
 
+	// Webhooks are notified of events by a separate goroutine, so that workers are never blocked.
+	hooks := webhook.New(event.SharedHub(), webhook.DefaultOptions(conf.WebhookURLs(), conf.WebhookSecret(), conf.WebhookEvents()))
+	hooks.Start()
+
+	var watcher photoprism.Watchers
+
+	startWatcher := func() {
+		if !conf.AutoIndexWatch() {
+			return
+		}
+
+		watcher = photoprism.NewWatchers(conf, service.Index())
+
+		if err := watcher.Start(); err != nil {
+			log.Warnf("%s, falling back to periodic scanning", err)
+		}
+	}
+
+	startWatcher()
+
+	// Originals paths may change when setup is completed.
+	setup := event.Subscribe("config.setup")
+
 	go func() {
+		defer event.Unsubscribe(setup)
+
 		for {
 			select {
+			case <-setup.Receiver:
+				if watcher != nil {
+					watcher.Stop()
+				}
+
+				startWatcher()
 			case <-stop:
 				log.Info("shutting down workers")
 				ticker.Stop()
 				mutex.Share.Cancel()
 				mutex.Sync.Cancel()
+				mutex.Worker.Cancel()
+
+				if watcher != nil {
+					watcher.Stop()
+				}
+
+				hooks.Stop()
+
 				return
 			case <-ticker.C:
 				StartShare(conf)
 				StartSync(conf)
+				PurgeLinks(conf)
+				PurgeSessions(conf)
+				PurgeRuns(conf)
+				PurgeTrash(conf)
+				PurgeUploads()
+
+				if watcher != nil && watcher.Failed() {
+					StartIndex(conf)
+				}
 			}
 		}
 	}()
@@ -60,3 +112,64 @@ func StartSync(conf *config.Config) {
 		}()
 	}
 }
+
+// PurgeLinks deletes expired sharing links once.
+func PurgeLinks(conf *config.Config) {
+	if n, err := entity.DeleteExpiredLinks(conf.Db()); err != nil {
+		log.Errorf("links: %s", err)
+	} else if n > 0 {
+		log.Infof("links: deleted %d expired sharing links", n)
+	}
+}
+
+// PurgeSessions deletes expired user sessions once.
+func PurgeSessions(conf *config.Config) {
+	if n, err := entity.DeleteExpiredSessions(conf.Db(), conf.SessionTimeout(), conf.SessionMaxAge()); err != nil {
+		log.Errorf("session: %s", err)
+	} else if n > 0 {
+		log.Infof("session: deleted %d expired sessions", n)
+	}
+}
+
+// PurgeRuns deletes the progress of import and index runs older than entity.RunMaxAge once.
+func PurgeRuns(conf *config.Config) {
+	if n, err := entity.DeleteExpiredRuns(conf.Db(), entity.RunMaxAge); err != nil {
+		log.Errorf("runs: %s", err)
+	} else if n > 0 {
+		log.Infof("runs: deleted the progress of %d old runs", n)
+	}
+}
+
+// PurgeUploads removes chunked uploads that were inactive for too long once.
+func PurgeUploads() {
+	if n, err := service.Upload().Cleanup(); err != nil {
+		log.Errorf("upload: %s", err)
+	} else if n > 0 {
+		log.Infof("upload: removed %d expired uploads", n)
+	}
+}
+
+// PurgeTrash permanently removes photos from the trash after the retention period, if no other worker is running.
+func PurgeTrash(conf *config.Config) {
+	if mutex.Worker.Busy() {
+		return
+	}
+
+	if removed, err := photoprism.NewTrash(conf).Cleanup(); err != nil {
+		log.Errorf("trash: %s", err)
+	} else if len(removed) > 0 {
+		log.Infof("trash: permanently removed %d photos", len(removed))
+		event.EntitiesDeleted("photos", removed)
+	}
+}
+
+// StartIndex runs an incremental index of all originals once, if no other worker is running.
+func StartIndex(conf *config.Config) {
+	if !mutex.Worker.Busy() {
+		go func() {
+			start := time.Now()
+			service.Index().Start(photoprism.IndexOptionsNone())
+			event.Publish("index.completed", event.Data{"path": conf.OriginalsPath(), "seconds": int(time.Since(start).Seconds())})
+		}()
+	}
+}
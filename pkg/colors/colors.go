@@ -10,6 +10,7 @@ package colors
 import (
 	"fmt"
 	"image/color"
+	"sort"
 	"strings"
 )
 
@@ -120,6 +121,42 @@ func (c Colors) List() []map[string]string {
 	return result
 }
 
+// Dominant returns up to n distinct colors ordered by their weighted frequency,
+// colors found first come first if the frequency is the same.
+func (c Colors) Dominant(n int) Colors {
+	count := make(map[Color]uint16)
+	result := make(Colors, 0, len(c))
+
+	for _, i := range c {
+		if _, ok := count[i]; !ok {
+			result = append(result, i)
+		}
+
+		count[i] += Weights[i]
+	}
+
+	sort.SliceStable(result, func(a, b int) bool {
+		return count[result[a]] > count[result[b]]
+	})
+
+	if len(result) > n {
+		result = result[:n]
+	}
+
+	return result
+}
+
+// Names returns the color names as comma separated string.
+func (c Colors) Names() string {
+	names := make([]string, len(c))
+
+	for i, indexedColor := range c {
+		names[i] = indexedColor.Name()
+	}
+
+	return strings.Join(names, ",")
+}
+
 func (c Chroma) Hex() string {
 	return fmt.Sprintf("%X", c)
 }
@@ -9,3 +9,31 @@ func TestColors_List(t *testing.T) {
 
 	t.Logf("colors: %+v", allColors)
 }
+
+func TestColors_Dominant(t *testing.T) {
+	t.Run("red", func(t *testing.T) {
+		c := Colors{Red, Red, Red, Black, Black, Brown, Red, Black, Grey}
+		result := c.Dominant(3)
+
+		if result.Names() != "red,black,brown" {
+			t.Errorf("result should be red,black,brown: %s", result.Names())
+		}
+	})
+
+	t.Run("weights", func(t *testing.T) {
+		c := Colors{Grey, Grey, Grey, Lime}
+		result := c.Dominant(2)
+
+		if result.Names() != "lime,grey" {
+			t.Errorf("result should be lime,grey: %s", result.Names())
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result := Colors{}.Dominant(3)
+
+		if len(result) != 0 {
+			t.Errorf("result should be empty: %+v", result)
+		}
+	})
+}
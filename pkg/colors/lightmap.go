@@ -1,5 +1,7 @@
 package colors
 
+import "math"
+
 type LightMap []Luminance
 
 // Hex returns all luminance value as a hex encoded string.
@@ -56,6 +58,21 @@ var diffValues = []diffValue{
 	https://jenssegers.com/perceptual-image-hashes
 */
 
+// Brightness returns the average luminance in percent.
+func (m LightMap) Brightness() uint8 {
+	if len(m) == 0 {
+		return 0
+	}
+
+	sum := 0
+
+	for _, luminance := range m {
+		sum += int(luminance)
+	}
+
+	return uint8(math.Round(float64(sum) * 100 / float64(len(m)*15)))
+}
+
 // Diff returns an integer that can be used to find similar images.
 func (m LightMap) Diff() (result uint32) {
 	if len(m) != 9 {
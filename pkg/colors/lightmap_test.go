@@ -73,3 +73,29 @@ func TestLightMap_Diff(t *testing.T) {
 		t.Logf("values: %d, %d, %d, %d", d1, d2, d3, d4)
 	})
 }
+
+func TestLightMap_Brightness(t *testing.T) {
+	t.Run("white", func(t *testing.T) {
+		result := LightMap{15, 15, 15}.Brightness()
+
+		if result != 100 {
+			t.Errorf("result should be 100: %d", result)
+		}
+	})
+
+	t.Run("dark", func(t *testing.T) {
+		result := LightMap{1, 2, 2, 1, 1, 1, 1, 1, 1}.Brightness()
+
+		if result != 8 {
+			t.Errorf("result should be 8: %d", result)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result := LightMap{}.Brightness()
+
+		if result != 0 {
+			t.Errorf("result should be 0: %d", result)
+		}
+	})
+}
@@ -1,5 +1,8 @@
 package colors
 
+// DarkBrightness is the average luminance in percent below which an image is considered dark.
+const DarkBrightness = 15
+
 // Information on how an image looks like in terms of colors and light.
 type ColorPerception struct {
 	Colors    Colors
@@ -7,3 +10,13 @@ type ColorPerception struct {
 	Luminance LightMap
 	Chroma    Chroma
 }
+
+// Mono returns true if the image is monochrome.
+func (p ColorPerception) Mono() bool {
+	return p.Chroma == 0
+}
+
+// Dark returns true if the average luminance is below DarkBrightness.
+func (p ColorPerception) Dark() bool {
+	return len(p.Luminance) > 0 && p.Luminance.Brightness() < DarkBrightness
+}
@@ -13,6 +13,7 @@ type FileInfo struct {
 	Size int64     `json:"size"`
 	Date time.Time `json:"date"`
 	Dir  bool      `json:"dir"`
+	ETag string    `json:"etag,omitempty"`
 }
 
 func NewFileInfo(info os.FileInfo, dir string) FileInfo {
@@ -36,6 +37,11 @@ func NewFileInfo(info os.FileInfo, dir string) FileInfo {
 		Dir:  info.IsDir(),
 	}
 
+	// Remote file systems like WebDAV may provide an entity tag that changes with the content.
+	if tagged, ok := info.(interface{ ETag() string }); ok {
+		result.ETag = tagged.ETag()
+	}
+
 	return result
 }
 
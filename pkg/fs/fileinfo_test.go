@@ -23,6 +23,28 @@ func TestNewFileInfo(t *testing.T) {
 	assert.Equal(t, int64(10990), result.Size)
 	assert.IsType(t, time.Time{}, result.Date)
 	assert.Equal(t, false, result.Dir)
+	assert.Equal(t, "", result.ETag)
+}
+
+type taggedFileInfo struct {
+	os.FileInfo
+}
+
+func (taggedFileInfo) ETag() string {
+	return `"5e9a-1f"`
+}
+
+func TestNewFileInfo_ETag(t *testing.T) {
+	info, err := os.Stat("testdata/test.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewFileInfo(taggedFileInfo{info}, "/")
+
+	assert.Equal(t, "/test.jpg", result.Abs)
+	assert.Equal(t, `"5e9a-1f"`, result.ETag)
 }
 
 func TestNewFileInfos(t *testing.T) {
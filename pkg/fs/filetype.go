@@ -14,6 +14,7 @@ type FileType string
 const (
 	TypeJpeg     FileType = "jpg"  // JPEG image file.
 	TypePng      FileType = "png"  // PNG image file.
+	TypeWebP     FileType = "webp" // WebP image file, only used for thumbnails.
 	TypeGif      FileType = "gif"  // GIF image file.
 	TypeTiff     FileType = "tiff" // TIFF image file.
 	TypeBitmap   FileType = "bmp"  // BMP image file.
@@ -22,6 +23,7 @@ const (
 	TypeMov      FileType = "mov"  // Video files.
 	TypeMP4      FileType = "mp4"
 	TypeAvi      FileType = "avi"
+	TypeAvc      FileType = "avc"  // H.264 video transcoded for playback in browsers.
 	TypeXMP      FileType = "xmp"  // Adobe XMP sidecar file (XML).
 	TypeAAE      FileType = "aae"  // Apple sidecar file (XML).
 	TypeXML      FileType = "xml"  // XML metadata / config / sidecar file.
@@ -51,6 +53,7 @@ var FileExt = FileExtensions{
 	".mov":  TypeMov,
 	".avi":  TypeAvi,
 	".mp4":  TypeMP4,
+	".avc":  TypeAvc,
 	".yml":  TypeYaml,
 	".yaml": TypeYaml,
 	".jpg":  TypeJpeg,
@@ -38,6 +38,30 @@ func PathExists(path string) bool {
 	return m&os.ModeDir != 0 || m&os.ModeSymlink != 0
 }
 
+// SubPath returns the absolute name of a path inside root, which may be given relative to root
+// or as absolute path. An error is returned if the resulting path is not inside root.
+func SubPath(root, name string) (string, error) {
+	root = filepath.Clean(root)
+
+	if name == "" {
+		return root, nil
+	}
+
+	result := filepath.Clean(name)
+
+	if !filepath.IsAbs(result) {
+		result = filepath.Join(root, result)
+	}
+
+	rel, err := filepath.Rel(root, result)
+
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("path %s is outside %s", name, filepath.Base(root))
+	}
+
+	return result, nil
+}
+
 // Overwrite overwrites the file with data. Creates file if not present.
 func Overwrite(fileName string, data []byte) bool {
 	f, err := os.Create(fileName)
@@ -163,3 +187,20 @@ func IsEmpty(path string) bool {
 
 	return false
 }
+
+// DirSize returns the total size of all files in a directory and its subdirectories.
+func DirSize(path string) (size int64, err error) {
+	err = filepath.Walk(path, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
@@ -19,6 +19,44 @@ func TestPathExists(t *testing.T) {
 	assert.False(t, PathExists("./testdata3ggdtgdg"))
 }
 
+func TestSubPath(t *testing.T) {
+	t.Run("relative", func(t *testing.T) {
+		result, err := SubPath("/photos/originals", "2023/2023-08 Vacation")
+		assert.Nil(t, err)
+		assert.Equal(t, "/photos/originals/2023/2023-08 Vacation", result)
+	})
+	t.Run("absolute", func(t *testing.T) {
+		result, err := SubPath("/photos/originals/", "/photos/originals/2023/")
+		assert.Nil(t, err)
+		assert.Equal(t, "/photos/originals/2023", result)
+	})
+	t.Run("empty", func(t *testing.T) {
+		result, err := SubPath("/photos/originals", "")
+		assert.Nil(t, err)
+		assert.Equal(t, "/photos/originals", result)
+	})
+	t.Run("traversal", func(t *testing.T) {
+		result, err := SubPath("/photos/originals", "2023/../../import")
+		assert.EqualError(t, err, "path 2023/../../import is outside originals")
+		assert.Empty(t, result)
+
+		_, err = SubPath("/photos/originals", "..")
+		assert.Error(t, err)
+	})
+	t.Run("outside", func(t *testing.T) {
+		_, err := SubPath("/photos/originals", "/etc")
+		assert.EqualError(t, err, "path /etc is outside originals")
+
+		_, err = SubPath("/photos/originals", "/photos/originals2")
+		assert.Error(t, err)
+	})
+	t.Run("dots in name", func(t *testing.T) {
+		result, err := SubPath("/photos/originals", "..2023")
+		assert.Nil(t, err)
+		assert.Equal(t, "/photos/originals/..2023", result)
+	})
+}
+
 func TestOverwrite(t *testing.T) {
 	data := make([]byte, 3)
 	data[1] = 3
@@ -65,3 +103,27 @@ func TestDirectoryIsEmpty(t *testing.T) {
 		assert.Equal(t, true, IsEmpty("./testdata/emptyDir"))
 	})
 }
+
+func TestDirSize(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		dir := "./testdata/sizeDir"
+
+		if err := os.MkdirAll(dir+"/sub", 0777); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(dir)
+
+		Overwrite(dir+"/a.txt", []byte("12345"))
+		Overwrite(dir+"/sub/b.txt", []byte("123"))
+
+		size, err := DirSize(dir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), size)
+	})
+	t.Run("not existing path", func(t *testing.T) {
+		_, err := DirSize("./xxx")
+		assert.Error(t, err)
+	})
+}
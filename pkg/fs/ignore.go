@@ -0,0 +1,198 @@
+package fs
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// IgnoreFileName is the name of files containing patterns for files and directories to be ignored.
+const IgnoreFileName = ".ppignore"
+
+// IgnorePattern represents a single gitignore-style pattern.
+type IgnorePattern struct {
+	parts    []string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// NewIgnorePattern parses a gitignore-style pattern and returns false if the line is empty or a comment.
+func NewIgnorePattern(line string) (p IgnorePattern, ok bool) {
+	line = strings.TrimRight(line, " \t\r")
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return p, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		// Escaped leading "#" or "!".
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// Patterns with a slash are relative to the directory of the ignore file.
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if line == "" {
+		return p, false
+	}
+
+	p.parts = strings.Split(line, "/")
+
+	return p, true
+}
+
+// Match returns true if the pattern matches a path, given as list of names relative to the pattern base.
+func (p IgnorePattern) Match(names []string, isDir bool) bool {
+	if len(names) == 0 || p.dirOnly && !isDir {
+		return false
+	}
+
+	if !p.anchored {
+		ok, _ := path.Match(p.parts[0], names[len(names)-1])
+		return ok
+	}
+
+	return matchNames(p.parts, names)
+}
+
+// matchNames matches path names against pattern parts, "**" matches any number of directories.
+func matchNames(parts, names []string) bool {
+	if len(parts) == 0 {
+		return len(names) == 0
+	}
+
+	if parts[0] == "**" {
+		for i := 0; i <= len(names); i++ {
+			if matchNames(parts[1:], names[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(names) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(parts[0], names[0]); !ok {
+		return false
+	}
+
+	return matchNames(parts[1:], names[1:])
+}
+
+// IgnoreList decides which files and directories below a root directory should be ignored,
+// based on default patterns and the IgnoreFileName files found in each directory.
+// Like with gitignore, the last matching pattern wins and patterns in subdirectories
+// take precedence. Files can't be included again if their parent directory is ignored.
+type IgnoreList struct {
+	root     string
+	defaults []IgnorePattern
+	dirs     map[string][]IgnorePattern
+	mutex    sync.Mutex
+}
+
+// NewIgnoreList returns a new ignore list for a root directory and default patterns.
+func NewIgnoreList(root string, defaults []string) *IgnoreList {
+	l := &IgnoreList{
+		root: filepath.Clean(root),
+		dirs: make(map[string][]IgnorePattern),
+	}
+
+	for _, line := range defaults {
+		if p, ok := NewIgnorePattern(line); ok {
+			l.defaults = append(l.defaults, p)
+		}
+	}
+
+	return l
+}
+
+// Ignore returns true if a file or directory should be ignored. Files outside the root
+// directory and the root itself are never ignored.
+func (l *IgnoreList) Ignore(fileName string, isDir bool) bool {
+	rel, err := filepath.Rel(l.root, fileName)
+
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return false
+	}
+
+	names := strings.Split(filepath.ToSlash(rel), "/")
+
+	for i := range names {
+		if l.match(names[:i+1], isDir || i < len(names)-1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match applies all patterns to a path relative to the root directory.
+func (l *IgnoreList) match(names []string, isDir bool) (ignored bool) {
+	for _, p := range l.defaults {
+		if p.Match(names, isDir) {
+			ignored = !p.negate
+		}
+	}
+
+	dir := l.root
+
+	for i := range names {
+		if i > 0 {
+			dir = filepath.Join(dir, names[i-1])
+		}
+
+		for _, p := range l.patterns(dir) {
+			if p.Match(names[i:], isDir) {
+				ignored = !p.negate
+			}
+		}
+	}
+
+	return ignored
+}
+
+// patterns returns the patterns found in the ignore file of a directory.
+func (l *IgnoreList) patterns(dir string) []IgnorePattern {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if result, ok := l.dirs[dir]; ok {
+		return result
+	}
+
+	var result []IgnorePattern
+
+	if f, err := os.Open(filepath.Join(dir, IgnoreFileName)); err == nil {
+		scanner := bufio.NewScanner(f)
+
+		for scanner.Scan() {
+			if p, ok := NewIgnorePattern(scanner.Text()); ok {
+				result = append(result, p)
+			}
+		}
+
+		f.Close()
+	}
+
+	l.dirs[dir] = result
+
+	return result
+}
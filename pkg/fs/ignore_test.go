@@ -0,0 +1,151 @@
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIgnorePattern(t *testing.T) {
+	t.Run("comment", func(t *testing.T) {
+		_, ok := NewIgnorePattern("# exports")
+		assert.False(t, ok)
+
+		_, ok = NewIgnorePattern("   ")
+		assert.False(t, ok)
+	})
+	t.Run("directory", func(t *testing.T) {
+		p, ok := NewIgnorePattern("@eaDir/")
+		assert.True(t, ok)
+		assert.True(t, p.Match([]string{"2020", "@eaDir"}, true))
+		assert.False(t, p.Match([]string{"2020", "@eaDir"}, false))
+	})
+	t.Run("anchored", func(t *testing.T) {
+		p, ok := NewIgnorePattern("/exports/*.jpg")
+		assert.True(t, ok)
+		assert.True(t, p.Match([]string{"exports", "IMG_1.jpg"}, false))
+		assert.False(t, p.Match([]string{"2020", "exports", "IMG_1.jpg"}, false))
+	})
+	t.Run("double star", func(t *testing.T) {
+		p, ok := NewIgnorePattern("**/cache/*.tmp")
+		assert.True(t, ok)
+		assert.True(t, p.Match([]string{"cache", "a.tmp"}, false))
+		assert.True(t, p.Match([]string{"2020", "05", "cache", "a.tmp"}, false))
+		assert.False(t, p.Match([]string{"2020", "a.tmp"}, false))
+	})
+	t.Run("escaped", func(t *testing.T) {
+		p, ok := NewIgnorePattern(`\#1.jpg`)
+		assert.True(t, ok)
+		assert.False(t, p.negate)
+		assert.True(t, p.Match([]string{"#1.jpg"}, false))
+	})
+}
+
+func writeIgnoreFile(t *testing.T, dir, content string) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, IgnoreFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIgnoreList_Ignore(t *testing.T) {
+	root, err := ioutil.TempDir("", "ignore")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	writeIgnoreFile(t, root, "# Exports\nexport/\n*.png\n")
+	writeIgnoreFile(t, filepath.Join(root, "2020"), "!keep.png\nraw/*.jpg\n")
+	writeIgnoreFile(t, filepath.Join(root, "2020", "raw"), "!IMG_2.jpg\n")
+
+	list := NewIgnoreList(root, []string{"@eaDir/", ".*", "*.tmp"})
+
+	t.Run("defaults", func(t *testing.T) {
+		assert.True(t, list.Ignore(filepath.Join(root, "2020", "@eaDir"), true))
+		assert.True(t, list.Ignore(filepath.Join(root, "2020", "@eaDir", "IMG_1.jpg"), false))
+		assert.True(t, list.Ignore(filepath.Join(root, ".thumbnails"), true))
+		assert.True(t, list.Ignore(filepath.Join(root, "2020", "IMG_1.tmp"), false))
+		assert.False(t, list.Ignore(filepath.Join(root, "2020", "IMG_1.jpg"), false))
+	})
+	t.Run("nested", func(t *testing.T) {
+		assert.True(t, list.Ignore(filepath.Join(root, "export"), true))
+		assert.True(t, list.Ignore(filepath.Join(root, "2020", "export", "IMG_1.jpg"), false))
+		assert.True(t, list.Ignore(filepath.Join(root, "IMG_1.png"), false))
+		assert.True(t, list.Ignore(filepath.Join(root, "2020", "raw", "IMG_1.jpg"), false))
+		assert.False(t, list.Ignore(filepath.Join(root, "2020", "raw", "IMG_1.dng"), false))
+		assert.False(t, list.Ignore(filepath.Join(root, "raw", "IMG_1.jpg"), false))
+	})
+	t.Run("negation", func(t *testing.T) {
+		assert.False(t, list.Ignore(filepath.Join(root, "2020", "keep.png"), false))
+		assert.False(t, list.Ignore(filepath.Join(root, "2020", "raw", "keep.png"), false))
+		assert.True(t, list.Ignore(filepath.Join(root, "2019", "keep.png"), false))
+		assert.False(t, list.Ignore(filepath.Join(root, "2020", "raw", "IMG_2.jpg"), false))
+	})
+	t.Run("parent ignored", func(t *testing.T) {
+		writeIgnoreFile(t, filepath.Join(root, "export"), "!*.jpg\n")
+		assert.True(t, list.Ignore(filepath.Join(root, "export", "IMG_1.jpg"), false))
+	})
+	t.Run("outside root", func(t *testing.T) {
+		assert.False(t, list.Ignore(root, true))
+		assert.False(t, list.Ignore(filepath.Join(filepath.Dir(root), "IMG_1.png"), false))
+	})
+}
+
+func TestIgnoreList_Walk(t *testing.T) {
+	root, err := ioutil.TempDir("", "ignore")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"2020/@eaDir/IMG_1.jpg", "2020/.thumbnails", "2020/export"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"2020/IMG_1.jpg", "2020/@eaDir/IMG_1.jpg/SYNOPHOTO_THUMB_XL.jpg", "2020/export/IMG_1.jpg", "2020/.thumbnails/a.png"} {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeIgnoreFile(t, filepath.Join(root, "2020"), "export/\n")
+
+	list := NewIgnoreList(root, []string{"@eaDir/", ".*"})
+
+	var visited []string
+
+	err = filepath.Walk(root, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if list.Ignore(fileName, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, fileName)
+		visited = append(visited, filepath.ToSlash(rel))
+
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{".", "2020", "2020/IMG_1.jpg"}, visited)
+}
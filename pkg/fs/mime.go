@@ -7,8 +7,24 @@ import (
 
 const (
 	MimeTypeJpeg = "image/jpeg"
+	MimeTypeHEIC = "image/heic"
+	MimeTypeHEIF = "image/heif"
+	MimeTypeMov  = "video/quicktime"
 )
 
+// isoBrands maps ISO base media file brands to mime types that are not detected by http.DetectContentType.
+var isoBrands = map[string]string{
+	"heic": MimeTypeHEIC,
+	"heix": MimeTypeHEIC,
+	"heim": MimeTypeHEIC,
+	"heis": MimeTypeHEIC,
+	"hevc": MimeTypeHEIC,
+	"hevx": MimeTypeHEIC,
+	"mif1": MimeTypeHEIF,
+	"msf1": MimeTypeHEIF,
+	"qt  ": MimeTypeMov,
+}
+
 // MimeType returns the mime type of a file, empty string if unknown.
 func MimeType(filename string) string {
 	handle, err := os.Open(filename)
@@ -22,11 +38,18 @@ func MimeType(filename string) string {
 	// Only the first 512 bytes are used to sniff the content type.
 	buffer := make([]byte, 512)
 
-	_, err = handle.Read(buffer)
+	n, err := handle.Read(buffer)
 
 	if err != nil {
 		return ""
 	}
 
+	// The major brand follows the type of the leading ftyp box.
+	if n >= 12 && string(buffer[4:8]) == "ftyp" {
+		if mimeType, ok := isoBrands[string(buffer[8:12])]; ok {
+			return mimeType
+		}
+	}
+
 	return http.DetectContentType(buffer)
 }
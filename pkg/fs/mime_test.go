@@ -12,6 +12,11 @@ func TestMimeType(t *testing.T) {
 		mimeType := MimeType(filename)
 		assert.Equal(t, "image/jpeg", mimeType)
 	})
+	t.Run("heic", func(t *testing.T) {
+		filename := Abs("../../internal/meta/testdata/iphone_7.heic")
+		mimeType := MimeType(filename)
+		assert.Equal(t, "image/heic", mimeType)
+	})
 	t.Run("not existing filename", func(t *testing.T) {
 		filename := Abs("./testdata/xxx.jpg")
 		mimeType := MimeType(filename)
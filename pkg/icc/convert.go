@@ -0,0 +1,105 @@
+package icc
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// xyzToSRGB converts XYZ relative to D50 to linear sRGB (inverse of srgbMatrix).
+var xyzToSRGB = [3][3]float64{
+	{3.1338561, -1.6168667, -0.4906146},
+	{-0.9787684, 1.9161415, 0.0334540},
+	{0.0719453, -0.2289914, 1.4052427},
+}
+
+// encodeSteps is the resolution of the lookup table used to encode linear light as sRGB.
+const encodeSteps = 4096
+
+var srgbEncode = func() (result [encodeSteps + 1]uint8) {
+	for i := range result {
+		v := float64(i) / encodeSteps
+
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+
+		result[i] = uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+
+	return result
+}()
+
+// ToSRGB returns a copy of the image with pixel values converted from the profile to sRGB.
+// Colors outside the sRGB gamut are clipped, alpha values are not changed.
+func (p *Profile) ToSRGB(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+
+	var linear [3][256]float64
+
+	for c := 0; c < 3; c++ {
+		for v := 0; v < 256; v++ {
+			linear[c][v] = p.Curves[c].Linear(float64(v) / 255)
+		}
+	}
+
+	var m [3][3]float64
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				m[i][j] += xyzToSRGB[i][k] * p.Matrix[k][j]
+			}
+		}
+	}
+
+	encode := func(v float64) uint8 {
+		if v <= 0 {
+			return 0
+		} else if v >= 1 {
+			return 255
+		}
+
+		return srgbEncode[int(v*encodeSteps+0.5)]
+	}
+
+	rows := make(chan int, dst.Rect.Dy())
+
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		rows <- y
+	}
+
+	close(rows)
+
+	var wg sync.WaitGroup
+
+	for n := 0; n < runtime.NumCPU(); n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for y := range rows {
+				row := dst.Pix[y*dst.Stride : y*dst.Stride+dst.Rect.Dx()*4]
+
+				for i := 0; i < len(row); i += 4 {
+					r, g, b := linear[0][row[i]], linear[1][row[i+1]], linear[2][row[i+2]]
+
+					row[i] = encode(m[0][0]*r + m[0][1]*g + m[0][2]*b)
+					row[i+1] = encode(m[1][0]*r + m[1][1]*g + m[1][2]*b)
+					row[i+2] = encode(m[2][0]*r + m[2][1]*g + m[2][2]*b)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return dst
+}
@@ -0,0 +1,431 @@
+/*
+Package icc reads embedded ICC color profiles and converts pixel data to sRGB.
+
+Only matrix/TRC based RGB profiles are supported. These are used by cameras, phones and
+image editors for Display P3, Adobe RGB and ProPhoto RGB, so that photos in wide gamut
+color spaces can be displayed correctly by browsers that don't support color management.
+*/
+package icc
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+// MaxProfileSize limits the amount of memory used for embedded profiles.
+const MaxProfileSize = 4 * 1024 * 1024
+
+// ErrNotFound is returned if a file does not contain an embedded profile.
+var ErrNotFound = errors.New("icc: no embedded profile")
+
+// Profile represents a parsed matrix/TRC RGB color profile.
+type Profile struct {
+	Description string
+	Matrix      [3][3]float64 // Converts linear RGB to XYZ relative to the D50 white point.
+	Curves      [3]Curve      // Tone reproduction curves of the red, green and blue channels.
+}
+
+// Curve represents a tone reproduction curve that converts encoded values to linear light.
+type Curve struct {
+	Params []float64 // Parametric curve, see ICC.1:2010 section 10.15.
+	Table  []float64 // Sampled curve with values between 0 and 1.
+}
+
+// srgbMatrix converts linear sRGB to XYZ relative to D50 (Bradford adapted).
+var srgbMatrix = [3][3]float64{
+	{0.4360747, 0.3850649, 0.1430804},
+	{0.2225045, 0.7168786, 0.0606169},
+	{0.0139322, 0.0971045, 0.7141733},
+}
+
+// srgbCurve is the parametric representation of the sRGB transfer function.
+var srgbCurve = Curve{Params: []float64{2.4, 1 / 1.055, 0.055 / 1.055, 1 / 12.92, 0.04045}}
+
+// Extract returns the ICC profile embedded in a JPEG or PNG file.
+func Extract(fileName string) ([]byte, error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	header := make([]byte, 8)
+
+	if _, err := f.Read(header); err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte{0xFF, 0xD8}):
+		return jpegProfile(f)
+	case bytes.Equal(header, []byte("\x89PNG\r\n\x1a\n")):
+		return pngProfile(f)
+	default:
+		return nil, ErrNotFound
+	}
+}
+
+// jpegProfile concatenates the APP2 ICC_PROFILE segments found before the image data.
+func jpegProfile(f *os.File) ([]byte, error) {
+	var chunks [][]byte
+	var size int
+
+	header := make([]byte, 4)
+
+	if _, err := f.Seek(2, 0); err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, err := f.Read(header); err != nil {
+			break
+		}
+
+		if header[0] != 0xFF {
+			return nil, errors.New("icc: invalid jpeg marker")
+		}
+
+		marker := header[1]
+
+		// Start of scan or end of image, no more meta data segments will follow.
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(header[2:4])) - 2
+
+		if length < 0 {
+			return nil, errors.New("icc: invalid jpeg segment length")
+		}
+
+		segment := make([]byte, length)
+
+		if _, err := f.Read(segment); err != nil {
+			return nil, err
+		}
+
+		if marker != 0xE2 || len(segment) < 14 || string(segment[0:12]) != "ICC_PROFILE\x00" {
+			continue
+		}
+
+		seq, count := int(segment[12]), int(segment[13])
+
+		if seq < 1 || seq > count {
+			return nil, errors.New("icc: invalid profile sequence number")
+		}
+
+		if chunks == nil {
+			chunks = make([][]byte, count)
+		}
+
+		if seq > len(chunks) {
+			return nil, errors.New("icc: inconsistent profile chunk count")
+		}
+
+		size += len(segment) - 14
+
+		if size > MaxProfileSize {
+			return nil, fmt.Errorf("icc: profile exceeds %d bytes", MaxProfileSize)
+		}
+
+		chunks[seq-1] = segment[14:]
+	}
+
+	if chunks == nil {
+		return nil, ErrNotFound
+	}
+
+	for _, c := range chunks {
+		if c == nil {
+			return nil, errors.New("icc: incomplete profile")
+		}
+	}
+
+	return bytes.Join(chunks, nil), nil
+}
+
+// pngProfile returns the uncompressed content of the iCCP chunk.
+func pngProfile(f *os.File) ([]byte, error) {
+	header := make([]byte, 8)
+
+	if _, err := f.Seek(8, 0); err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, err := f.Read(header); err != nil {
+			return nil, ErrNotFound
+		}
+
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		chunkType := string(header[4:8])
+
+		switch chunkType {
+		case "IDAT", "IEND":
+			return nil, ErrNotFound
+		case "iCCP":
+			if length > MaxProfileSize {
+				return nil, fmt.Errorf("icc: profile exceeds %d bytes", MaxProfileSize)
+			}
+
+			data := make([]byte, length)
+
+			if _, err := f.Read(data); err != nil {
+				return nil, err
+			}
+
+			// Profile name, null separator and compression method precede the compressed profile.
+			i := bytes.IndexByte(data, 0)
+
+			if i < 0 || i+2 > len(data) {
+				return nil, errors.New("icc: invalid png profile chunk")
+			}
+
+			r, err := zlib.NewReader(bytes.NewReader(data[i+2:]))
+
+			if err != nil {
+				return nil, err
+			}
+
+			defer r.Close()
+
+			return ioutil.ReadAll(r)
+		}
+
+		// Skip chunk data and checksum.
+		if _, err := f.Seek(length+4, 1); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Parse parses an ICC profile and returns an error if it isn't a matrix/TRC RGB profile.
+func Parse(data []byte) (p *Profile, err error) {
+	if len(data) < 132 || string(data[36:40]) != "acsp" {
+		return nil, errors.New("icc: invalid profile header")
+	}
+
+	if cs := string(data[16:20]); cs != "RGB " {
+		return nil, fmt.Errorf("icc: unsupported color space \"%s\"", strings.TrimSpace(cs))
+	}
+
+	if pcs := string(data[20:24]); pcs != "XYZ " {
+		return nil, fmt.Errorf("icc: unsupported connection space \"%s\"", strings.TrimSpace(pcs))
+	}
+
+	tags := make(map[string][]byte)
+	count := int(binary.BigEndian.Uint32(data[128:132]))
+
+	for i := 0; i < count; i++ {
+		entry := 132 + i*12
+
+		if entry+12 > len(data) {
+			return nil, errors.New("icc: truncated tag table")
+		}
+
+		offset := int(binary.BigEndian.Uint32(data[entry+4 : entry+8]))
+		size := int(binary.BigEndian.Uint32(data[entry+8 : entry+12]))
+
+		if offset < 0 || size < 8 || offset+size > len(data) {
+			return nil, errors.New("icc: invalid tag offset")
+		}
+
+		tags[string(data[entry:entry+4])] = data[offset : offset+size]
+	}
+
+	p = &Profile{Description: description(tags["desc"])}
+
+	for i, name := range []string{"rXYZ", "gXYZ", "bXYZ"} {
+		b, ok := tags[name]
+
+		if !ok || len(b) < 20 || string(b[0:4]) != "XYZ " {
+			return nil, fmt.Errorf("icc: missing %s tag", name)
+		}
+
+		for j := 0; j < 3; j++ {
+			p.Matrix[j][i] = s15Fixed16(b[8+j*4:])
+		}
+	}
+
+	for i, name := range []string{"rTRC", "gTRC", "bTRC"} {
+		b, ok := tags[name]
+
+		if !ok {
+			return nil, fmt.Errorf("icc: missing %s tag", name)
+		}
+
+		if p.Curves[i], err = parseCurve(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func s15Fixed16(b []byte) float64 {
+	return float64(int32(binary.BigEndian.Uint32(b[0:4]))) / 65536
+}
+
+// parameterCount lists the number of parameters of each parametric curve type.
+var parameterCount = []int{1, 3, 4, 5, 7}
+
+func parseCurve(b []byte) (c Curve, err error) {
+	if len(b) < 12 {
+		return c, errors.New("icc: invalid curve")
+	}
+
+	switch string(b[0:4]) {
+	case "curv":
+		n := int(binary.BigEndian.Uint32(b[8:12]))
+
+		switch {
+		case n == 0:
+			c.Params = []float64{1}
+		case n == 1 && len(b) >= 14:
+			c.Params = []float64{float64(binary.BigEndian.Uint16(b[12:14])) / 256}
+		case len(b) >= 12+n*2:
+			c.Table = make([]float64, n)
+
+			for i := range c.Table {
+				c.Table[i] = float64(binary.BigEndian.Uint16(b[12+i*2:])) / 65535
+			}
+		default:
+			return c, errors.New("icc: truncated curve")
+		}
+	case "para":
+		fn := int(binary.BigEndian.Uint16(b[8:10]))
+
+		if fn >= len(parameterCount) || len(b) < 12+parameterCount[fn]*4 {
+			return c, fmt.Errorf("icc: unsupported parametric curve type %d", fn)
+		}
+
+		c.Params = make([]float64, parameterCount[fn])
+
+		for i := range c.Params {
+			c.Params[i] = s15Fixed16(b[12+i*4:])
+		}
+	default:
+		return c, fmt.Errorf("icc: unsupported curve type \"%s\"", string(b[0:4]))
+	}
+
+	return c, nil
+}
+
+// description returns the profile description in English if available.
+func description(b []byte) string {
+	if len(b) < 12 {
+		return ""
+	}
+
+	switch string(b[0:4]) {
+	case "desc":
+		n := int(binary.BigEndian.Uint32(b[8:12]))
+
+		if n > 0 && 12+n <= len(b) {
+			return strings.TrimRight(string(b[12:12+n]), "\x00")
+		}
+	case "mluc":
+		count := int(binary.BigEndian.Uint32(b[8:12]))
+
+		if count < 1 || len(b) < 28 {
+			return ""
+		}
+
+		n := int(binary.BigEndian.Uint32(b[20:24]))
+		offset := int(binary.BigEndian.Uint32(b[24:28]))
+
+		if offset+n > len(b) {
+			return ""
+		}
+
+		text := make([]uint16, n/2)
+
+		for i := range text {
+			text[i] = binary.BigEndian.Uint16(b[offset+i*2:])
+		}
+
+		return strings.TrimRight(string(utf16.Decode(text)), "\x00")
+	}
+
+	return ""
+}
+
+// Linear converts an encoded value between 0 and 1 to linear light.
+func (c Curve) Linear(v float64) float64 {
+	if len(c.Table) > 0 {
+		if len(c.Table) == 1 {
+			return c.Table[0]
+		}
+
+		pos := v * float64(len(c.Table)-1)
+		i := int(pos)
+
+		if i >= len(c.Table)-1 {
+			return c.Table[len(c.Table)-1]
+		}
+
+		return c.Table[i] + (c.Table[i+1]-c.Table[i])*(pos-float64(i))
+	}
+
+	p := c.Params
+
+	switch len(p) {
+	case 1:
+		return math.Pow(v, p[0])
+	case 3:
+		if v >= -p[2]/p[1] {
+			return math.Pow(p[1]*v+p[2], p[0])
+		}
+		return 0
+	case 4:
+		if v >= -p[2]/p[1] {
+			return math.Pow(p[1]*v+p[2], p[0]) + p[3]
+		}
+		return p[3]
+	case 5:
+		if v >= p[4] {
+			return math.Pow(p[1]*v+p[2], p[0])
+		}
+		return p[3] * v
+	case 7:
+		if v >= p[4] {
+			return math.Pow(p[1]*v+p[2], p[0]) + p[5]
+		}
+		return p[3]*v + p[6]
+	}
+
+	return v
+}
+
+// SRGB returns true if the profile is equivalent to sRGB, so that no conversion is required.
+func (p *Profile) SRGB() bool {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(p.Matrix[i][j]-srgbMatrix[i][j]) > 0.002 {
+				return false
+			}
+		}
+
+		for v := 0.0; v <= 1; v += 0.125 {
+			if math.Abs(p.Curves[i].Linear(v)-srgbCurve.Linear(v)) > 0.004 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
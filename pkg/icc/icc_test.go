@@ -0,0 +1,210 @@
+package icc
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"testing"
+
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// srgb encodes linear light using the sRGB transfer function.
+func srgb(v float64) float64 {
+	v = math.Max(0, math.Min(1, v))
+
+	if v <= 0.0031308 {
+		return v * 12.92 * 255
+	}
+
+	return (1.055*math.Pow(v, 1/2.4) - 0.055) * 255
+}
+
+// expected converts a color using a linear RGB to linear sRGB matrix and source transfer function.
+func expected(c color.NRGBA, m [3][3]float64, linear func(float64) float64) [3]float64 {
+	in := []float64{linear(float64(c.R) / 255), linear(float64(c.G) / 255), linear(float64(c.B) / 255)}
+
+	var result [3]float64
+
+	for i := 0; i < 3; i++ {
+		result[i] = srgb(m[i][0]*in[0] + m[i][1]*in[1] + m[i][2]*in[2])
+	}
+
+	return result
+}
+
+func open(t *testing.T, fileName string) image.Image {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return img
+}
+
+func assertColor(t *testing.T, want [3]float64, got color.NRGBA) {
+	assert.InDelta(t, want[0], float64(got.R), 2)
+	assert.InDelta(t, want[1], float64(got.G), 2)
+	assert.InDelta(t, want[2], float64(got.B), 2)
+	assert.Equal(t, uint8(255), got.A)
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("jpeg", func(t *testing.T) {
+		data, err := Extract("testdata/display_p3.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "acsp", string(data[36:40]))
+	})
+	t.Run("png", func(t *testing.T) {
+		data, err := Extract("testdata/adobe_rgb.png")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "acsp", string(data[36:40]))
+	})
+	t.Run("no profile", func(t *testing.T) {
+		_, err := Extract("../../internal/meta/testdata/tweethog.png")
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := Extract("testdata/missing.jpg")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("display p3", func(t *testing.T) {
+		data, _ := Extract("testdata/display_p3.jpg")
+		p, err := Parse(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Display P3", p.Description)
+		assert.InDelta(t, 0.515121, p.Matrix[0][0], 0.0001)
+		assert.False(t, p.SRGB())
+	})
+	t.Run("adobe rgb", func(t *testing.T) {
+		data, _ := Extract("testdata/adobe_rgb.png")
+		p, err := Parse(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Adobe RGB (1998)", p.Description)
+		assert.Equal(t, []float64{563.0 / 256}, p.Curves[0].Params)
+		assert.False(t, p.SRGB())
+	})
+	t.Run("srgb", func(t *testing.T) {
+		p := &Profile{Matrix: srgbMatrix, Curves: [3]Curve{srgbCurve, srgbCurve, srgbCurve}}
+
+		assert.True(t, p.SRGB())
+	})
+	t.Run("photoshop srgb", func(t *testing.T) {
+		data, _ := Extract("../../internal/meta/testdata/photoshop.jpg")
+		p, err := Parse(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "sRGB IEC61966-2.1", p.Description)
+		assert.NotEmpty(t, p.Curves[0].Table)
+		assert.True(t, p.SRGB())
+	})
+	t.Run("lightroom adobe rgb", func(t *testing.T) {
+		data, _ := Extract("../../internal/meta/testdata/ladybug.jpg")
+		p, err := Parse(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Adobe RGB (1998)", p.Description)
+		assert.False(t, p.SRGB())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Parse([]byte("foo"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCurve_Linear(t *testing.T) {
+	assert.Equal(t, 0.25, Curve{Params: []float64{2}}.Linear(0.5))
+	assert.Equal(t, 0.5, Curve{Table: []float64{0, 1}}.Linear(0.5))
+	assert.InDelta(t, 0.2140, srgbCurve.Linear(0.5), 0.0001)
+	assert.InDelta(t, 0.0031, srgbCurve.Linear(0.04), 0.0001)
+}
+
+func TestProfile_ToSRGB(t *testing.T) {
+	source := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	t.Run("display p3", func(t *testing.T) {
+		data, _ := Extract("testdata/display_p3.jpg")
+		p, err := Parse(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img := open(t, "testdata/display_p3.jpg")
+		result := p.ToSRGB(img)
+
+		// Display P3 to sRGB conversion matrix for linear light.
+		m := [3][3]float64{
+			{1.2249, -0.2247, 0},
+			{-0.0420, 1.0419, 0},
+			{-0.0197, -0.0786, 1.0979},
+		}
+
+		assert.Equal(t, img.Bounds().Size(), result.Bounds().Size())
+		assertColor(t, expected(source, m, srgbCurve.Linear), result.NRGBAAt(8, 8))
+		assert.Greater(t, float64(result.NRGBAAt(8, 8).R)-float64(source.R), 10.0)
+	})
+	t.Run("adobe rgb", func(t *testing.T) {
+		data, _ := Extract("testdata/adobe_rgb.png")
+		p, err := Parse(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img := open(t, "testdata/adobe_rgb.png")
+		result := p.ToSRGB(img)
+
+		// Adobe RGB (1998) to sRGB conversion matrix for linear light.
+		m := [3][3]float64{
+			{1.3982, -0.3982, 0},
+			{0, 1, 0},
+			{0, -0.0429, 1.0429},
+		}
+
+		gamma := func(v float64) float64 { return math.Pow(v, 563.0/256) }
+
+		assertColor(t, expected(source, m, gamma), result.NRGBAAt(8, 8))
+	})
+}
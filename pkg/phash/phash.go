@@ -0,0 +1,63 @@
+/*
+Package phash computes perceptual image hashes to find visually identical photos.
+
+Unlike cryptographic hashes, perceptual hashes of resized or re-compressed copies
+only differ in a few bits, so the Hamming distance can be used to compare images.
+*/
+package phash
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+
+	"github.com/disintegration/imaging"
+)
+
+// Hash represents a 64-bit difference hash (dHash) of an image.
+type Hash uint64
+
+// DHash returns the difference hash of an image. The image is reduced to 9x8 grayscale
+// pixels and each bit encodes whether a pixel is brighter than its right neighbour.
+func DHash(img image.Image) Hash {
+	small := imaging.Grayscale(imaging.Resize(img, 9, 8, imaging.Box))
+
+	var h Hash
+
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			left := small.Pix[small.PixOffset(x, y)]
+			right := small.Pix[small.PixOffset(x+1, y)]
+
+			h <<= 1
+
+			if left > right {
+				h |= 1
+			}
+		}
+	}
+
+	return h
+}
+
+// Distance returns the number of bits that differ between both hashes.
+func (h Hash) Distance(other Hash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// String returns the hash as 16 digit hex string.
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// Parse returns the hash for a hex string as returned by Hash.String().
+func Parse(s string) (Hash, error) {
+	h, err := strconv.ParseUint(s, 16, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("phash: invalid hash \"%s\"", s)
+	}
+
+	return Hash(h), nil
+}
@@ -0,0 +1,54 @@
+package phash
+
+import (
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func hashFile(t *testing.T, fileName string) Hash {
+	img, err := imaging.Open(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return DHash(img)
+}
+
+func TestDHash(t *testing.T) {
+	original := hashFile(t, "testdata/ladybug.jpg")
+
+	t.Run("resized and compressed copy", func(t *testing.T) {
+		copied := hashFile(t, "testdata/ladybug_small.jpg")
+
+		assert.LessOrEqual(t, original.Distance(copied), 4)
+	})
+	t.Run("different photo", func(t *testing.T) {
+		other := hashFile(t, "testdata/gopro_hd2.jpg")
+
+		assert.Greater(t, original.Distance(other), 10)
+	})
+}
+
+func TestHash_Distance(t *testing.T) {
+	assert.Equal(t, 0, Hash(0xff).Distance(0xff))
+	assert.Equal(t, 8, Hash(0xff).Distance(0))
+	assert.Equal(t, 64, Hash(0).Distance(0xffffffffffffffff))
+}
+
+func TestParse(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		h, err := Parse("00000000000000ff")
+
+		assert.NoError(t, err)
+		assert.Equal(t, Hash(0xff), h)
+		assert.Equal(t, "00000000000000ff", h.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Parse("xyz")
+
+		assert.EqualError(t, err, "phash: invalid hash \"xyz\"")
+	})
+}
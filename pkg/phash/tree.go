@@ -0,0 +1,79 @@
+package phash
+
+// Tree is a BK-tree of hashes that finds similar hashes without comparing all of them, as only
+// subtrees within the distance range can contain matches according to the triangle inequality.
+type Tree struct {
+	root *node
+	size int
+}
+
+type node struct {
+	hash     Hash
+	ids      []int
+	children map[int]*node
+}
+
+// Add stores a hash with an id, e.g. the index of the file it belongs to.
+func (t *Tree) Add(h Hash, id int) {
+	t.size++
+
+	if t.root == nil {
+		t.root = &node{hash: h, ids: []int{id}}
+		return
+	}
+
+	for n := t.root; ; {
+		d := n.hash.Distance(h)
+
+		if d == 0 {
+			n.ids = append(n.ids, id)
+			return
+		}
+
+		child, ok := n.children[d]
+
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[int]*node)
+			}
+
+			n.children[d] = &node{hash: h, ids: []int{id}}
+			return
+		}
+
+		n = child
+	}
+}
+
+// Len returns the number of hashes in the tree.
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Find returns the ids of all hashes that differ by at most distance bits.
+func (t *Tree) Find(h Hash, distance int) (ids []int) {
+	if t.root == nil {
+		return ids
+	}
+
+	stack := []*node{t.root}
+
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := n.hash.Distance(h)
+
+		if d <= distance {
+			ids = append(ids, n.ids...)
+		}
+
+		for cd, child := range n.children {
+			if cd >= d-distance && cd <= d+distance {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	return ids
+}
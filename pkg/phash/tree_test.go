@@ -0,0 +1,64 @@
+package phash
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_Find(t *testing.T) {
+	var tree Tree
+
+	assert.Empty(t, tree.Find(0, 64))
+
+	hashes := []Hash{0x1519145d5856d6d6, 0x1519145d5856d6d7, 0x1519145d5856d6d6, 0xe6e6a2b3a7a92929, 0x1519145d5856d6f4}
+
+	for i, h := range hashes {
+		tree.Add(h, i)
+	}
+
+	assert.Equal(t, len(hashes), tree.Len())
+
+	t.Run("exact", func(t *testing.T) {
+		ids := tree.Find(hashes[0], 0)
+		sort.Ints(ids)
+
+		assert.Equal(t, []int{0, 2}, ids)
+	})
+	t.Run("similar", func(t *testing.T) {
+		ids := tree.Find(hashes[0], 4)
+		sort.Ints(ids)
+
+		assert.Equal(t, []int{0, 1, 2, 4}, ids)
+	})
+	t.Run("same as linear search", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+
+		var random Tree
+		var all []Hash
+
+		for i := 0; i < 1000; i++ {
+			// Flip a few bits of a common base, so that many hashes are similar.
+			h := Hash(0x1519145d5856d6d6) ^ Hash(r.Uint64()&r.Uint64()&r.Uint64())
+			random.Add(h, i)
+			all = append(all, h)
+		}
+
+		for _, distance := range []int{0, 4, 8} {
+			var expected []int
+
+			for i, h := range all {
+				if all[0].Distance(h) <= distance {
+					expected = append(expected, i)
+				}
+			}
+
+			ids := random.Find(all[0], distance)
+			sort.Ints(ids)
+
+			assert.Equal(t, expected, ids)
+		}
+	})
+}
@@ -13,12 +13,20 @@ https://s2geometry.io/
 package s2
 
 import (
+	"github.com/golang/geo/r1"
+	"github.com/golang/geo/s1"
 	gs2 "github.com/golang/geo/s2"
 )
 
 // Default cell level, see https://s2geometry.io/resources/s2cell_statistics.html.
 var DefaultLevel = 21
 
+// Max number of cells used to cover a region, more cells result in a more accurate but slower search.
+var MaxCoverCells = 8
+
+// Mean earth radius in km.
+const EarthRadius = 6371.0
+
 // Token returns the S2 cell token for coordinates using the default level.
 func Token(lat, lng float64) string {
 	return TokenLevel(lat, lng, DefaultLevel)
@@ -78,3 +86,53 @@ func Range(token string, levelUp int) (min, max string) {
 
 	return parent.Prev().ChildBeginAtLevel(lvl).ToToken(), parent.Next().ChildBeginAtLevel(lvl).ToToken()
 }
+
+// CellRange returns the token range of all cells contained in a cell.
+func CellRange(token string) (min, max string) {
+	c := gs2.CellIDFromToken(token)
+
+	if !c.IsValid() {
+		return min, max
+	}
+
+	return c.RangeMin().ToToken(), c.RangeMax().ToToken()
+}
+
+// BBoxRanges returns token ranges of cells covering a bounding box in degrees. Boxes crossing
+// the antimeridian have a west longitude greater than the east longitude.
+func BBoxRanges(west, south, east, north float64) [][2]string {
+	lo, hi := gs2.LatLngFromDegrees(south, west), gs2.LatLngFromDegrees(north, east)
+
+	rect := gs2.Rect{
+		Lat: r1.Interval{Lo: lo.Lat.Radians(), Hi: hi.Lat.Radians()},
+		Lng: s1.IntervalFromEndpoints(lo.Lng.Radians(), hi.Lng.Radians()),
+	}
+
+	if !rect.IsValid() || rect.IsEmpty() {
+		return nil
+	}
+
+	return coverRanges(rect)
+}
+
+// CapRanges returns token ranges of cells covering a circle with a radius in km around the coordinates.
+func CapRanges(lat, lng, radius float64) [][2]string {
+	if radius <= 0 {
+		return nil
+	}
+
+	center := gs2.PointFromLatLng(gs2.LatLngFromDegrees(lat, lng))
+
+	return coverRanges(gs2.CapFromCenterAngle(center, s1.Angle(radius/EarthRadius)))
+}
+
+// coverRanges returns the token ranges of cells covering a region.
+func coverRanges(region gs2.Region) (result [][2]string) {
+	coverer := &gs2.RegionCoverer{MaxLevel: DefaultLevel, MaxCells: MaxCoverCells}
+
+	for _, c := range coverer.Covering(region) {
+		result = append(result, [2]string{c.RangeMin().ToToken(), c.RangeMax().ToToken()})
+	}
+
+	return result
+}
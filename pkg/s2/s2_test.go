@@ -152,3 +152,66 @@ func TestRange(t *testing.T) {
 		assert.Equal(t, "", max)
 	})
 }
+
+// covered returns true if a token is within one of the ranges.
+func covered(token string, ranges [][2]string) bool {
+	for _, r := range ranges {
+		if token >= r[0] && token <= r[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestCellRange(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		min, max := CellRange("4799e370")
+		assert.Equal(t, "4799e36000000001", min)
+		assert.Equal(t, "4799e37fffffffff", max)
+		assert.True(t, covered(Token(48.56344833333333, 8.996878333333333), [][2]string{{min, max}}))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		min, max := CellRange("4799e370ca5q")
+		assert.Equal(t, "", min)
+		assert.Equal(t, "", max)
+	})
+}
+
+func TestBBoxRanges(t *testing.T) {
+	t.Run("berlin", func(t *testing.T) {
+		ranges := BBoxRanges(13.0, 52.3, 13.8, 52.7)
+
+		assert.NotEmpty(t, ranges)
+		assert.LessOrEqual(t, len(ranges), MaxCoverCells)
+		assert.True(t, covered(Token(52.5163, 13.3777), ranges))
+		assert.True(t, covered(Token(52.31, 13.01), ranges))
+		assert.False(t, covered(Token(48.519234, 9.057997), ranges))
+	})
+	t.Run("antimeridian", func(t *testing.T) {
+		ranges := BBoxRanges(170.0, -20.0, -170.0, -10.0)
+
+		assert.NotEmpty(t, ranges)
+		assert.True(t, covered(Token(-15.0, 179.5), ranges))
+		assert.True(t, covered(Token(-15.0, -179.5), ranges))
+		assert.False(t, covered(Token(-15.0, 0.0), ranges))
+		assert.False(t, covered(Token(-15.0, 160.0), ranges))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert.Empty(t, BBoxRanges(13.0, 52.7, 13.8, 52.3))
+	})
+}
+
+func TestCapRanges(t *testing.T) {
+	t.Run("5km", func(t *testing.T) {
+		ranges := CapRanges(52.5163, 13.3777, 5)
+
+		assert.NotEmpty(t, ranges)
+		assert.True(t, covered(Token(52.5163, 13.3777), ranges))
+		assert.True(t, covered(Token(52.54, 13.38), ranges))
+		assert.False(t, covered(Token(52.7, 13.3777), ranges))
+	})
+	t.Run("zero radius", func(t *testing.T) {
+		assert.Empty(t, CapRanges(52.5163, 13.3777, 0))
+	})
+}
@@ -0,0 +1,72 @@
+package txt
+
+import (
+	"strings"
+)
+
+// Locale contains localized month and weekday names as well as default name templates.
+type Locale struct {
+	Months        [13]string
+	Weekdays      [7]string
+	MonthTemplate string
+	WeekTemplate  string
+}
+
+// DefaultLocale is used if no translation exists for a language.
+const DefaultLocale = "en"
+
+// Locales contains translations for languages supported by the user interface, see frontend/src/resources.
+var Locales = map[string]Locale{
+	"en": {
+		Months:        Months,
+		Weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		MonthTemplate: "{month} {year}",
+		WeekTemplate:  "Week {week} {year}",
+	},
+	"de": {
+		Months:        [13]string{"Unbekannt", "Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		Weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		MonthTemplate: "{month} {year}",
+		WeekTemplate:  "KW {week} {year}",
+	},
+	"nl": {
+		Months:        [13]string{"Onbekend", "januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		Weekdays:      [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		MonthTemplate: "{month} {year}",
+		WeekTemplate:  "Week {week} {year}",
+	},
+	"ru": {
+		Months:        [13]string{"Неизвестно", "Январь", "Февраль", "Март", "Апрель", "Май", "Июнь", "Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"},
+		Weekdays:      [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+		MonthTemplate: "{month} {year}",
+		WeekTemplate:  "Неделя {week}, {year}",
+	},
+	"ja": {
+		Months:        [13]string{"不明", "1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		Weekdays:      [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		MonthTemplate: "{year}年{m}月",
+		WeekTemplate:  "{year}年 第{week}週",
+	},
+}
+
+// GetLocale returns the locale for a language code like "de" or "de-DE", English is the fallback.
+func GetLocale(lang string) Locale {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+
+	if l, ok := Locales[lang]; ok {
+		return l
+	}
+
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		if l, ok := Locales[lang[:i]]; ok {
+			return l
+		}
+	}
+
+	return Locales[DefaultLocale]
+}
+
+// Unknown returns the translation of "Unknown", e.g. for photos without title or date.
+func (l Locale) Unknown() string {
+	return l.Months[0]
+}
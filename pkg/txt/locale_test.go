@@ -0,0 +1,39 @@
+package txt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLocale(t *testing.T) {
+	t.Run("de", func(t *testing.T) {
+		assert.Equal(t, "März", GetLocale("de").Months[3])
+	})
+	t.Run("de-DE", func(t *testing.T) {
+		assert.Equal(t, "Montag", GetLocale("de-DE").Weekdays[1])
+	})
+	t.Run("ja_JP", func(t *testing.T) {
+		assert.Equal(t, "{year}年{m}月", GetLocale("ja_JP").MonthTemplate)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		assert.Equal(t, "August", GetLocale("xx").Months[8])
+	})
+	t.Run("complete", func(t *testing.T) {
+		for lang, l := range Locales {
+			for i, name := range l.Months {
+				assert.NotEmpty(t, name, "%s month %d", lang, i)
+			}
+
+			for i, name := range l.Weekdays {
+				assert.NotEmpty(t, name, "%s weekday %d", lang, i)
+			}
+
+			_, err := ParseDateTemplate(l.MonthTemplate)
+			assert.NoError(t, err, lang)
+
+			_, err = ParseDateTemplate(l.WeekTemplate)
+			assert.NoError(t, err, lang)
+		}
+	})
+}
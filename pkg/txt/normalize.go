@@ -0,0 +1,28 @@
+package txt
+
+import (
+	"strings"
+
+	"github.com/rainycape/unidecode"
+)
+
+// Normalize returns a lowercase ASCII version of a string for search, e.g. "zurich" for "Zürich" and
+// "sao paulo" for "São Paulo". Repeated whitespace is replaced by a single space.
+func Normalize(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(strings.ToLower(unidecode.Unidecode(s))), " ")
+}
+
+// NormalizedWords returns the normalized versions of words that contain uppercase or non-ASCII characters.
+func NormalizedWords(words []string) (results []string) {
+	for _, w := range words {
+		if n := Normalize(w); n != "" && n != w {
+			results = append(results, n)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,33 @@
+package txt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "", Normalize(""))
+	})
+	t.Run("German umlauts", func(t *testing.T) {
+		assert.Equal(t, "zurich", Normalize("Zürich"))
+		assert.Equal(t, "koln", Normalize("Köln"))
+		assert.Equal(t, "strasse", Normalize("Straße"))
+	})
+	t.Run("French accents", func(t *testing.T) {
+		assert.Equal(t, "ile-de-france", Normalize("Île-de-France"))
+		assert.Equal(t, "cafe creme", Normalize("Café Crème"))
+	})
+	t.Run("Portuguese", func(t *testing.T) {
+		assert.Equal(t, "sao paulo", Normalize("São Paulo"))
+	})
+	t.Run("mixed case", func(t *testing.T) {
+		assert.Equal(t, "new york city", Normalize("  New  YORK city "))
+	})
+}
+
+func TestNormalizedWords(t *testing.T) {
+	assert.Equal(t, []string{"zurich", "munchen"}, NormalizedWords([]string{"zürich", "berlin", "München"}))
+	assert.Empty(t, NormalizedWords([]string{"berlin", "paris"}))
+}
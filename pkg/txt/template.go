@@ -0,0 +1,126 @@
+package txt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateTemplate is a name template with date tokens like "{month} {year}".
+type DateTemplate string
+
+// DateTokens lists the tokens supported in date templates.
+var DateTokens = []string{"year", "month", "m", "mm", "week", "weekday", "day", "dd"}
+
+// ParseDateTemplate validates a date template and returns an error if it contains unknown tokens.
+func ParseDateTemplate(s string) (DateTemplate, error) {
+	_, tokens, err := splitTemplate(s)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, token := range tokens {
+		if !isDateToken(token) {
+			return "", fmt.Errorf("unknown token \"{%s}\" in template \"%s\"", token, s)
+		}
+	}
+
+	return DateTemplate(s), nil
+}
+
+// splitTemplate returns the literal text and the tokens of a template, the literal text at
+// index i precedes the token at index i and the last literal follows the last token.
+func splitTemplate(s string) (literals, tokens []string, err error) {
+	rest := s
+
+	for {
+		start := strings.IndexAny(rest, "{}")
+
+		if start < 0 {
+			break
+		}
+
+		if rest[start] == '}' {
+			return nil, nil, fmt.Errorf("unexpected \"}\" in template \"%s\"", s)
+		}
+
+		end := strings.IndexAny(rest[start+1:], "{}")
+
+		if end < 0 || rest[start+1+end] != '}' {
+			return nil, nil, fmt.Errorf("unclosed \"{\" in template \"%s\"", s)
+		}
+
+		literals = append(literals, rest[:start])
+		tokens = append(tokens, rest[start+1:start+1+end])
+
+		rest = rest[start+end+2:]
+	}
+
+	return append(literals, rest), tokens, nil
+}
+
+func isDateToken(token string) bool {
+	for _, t := range DateTokens {
+		if t == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Format returns the template with tokens replaced by localized values for date.
+// The week number depends on the first day of the week, see WeekNumber.
+func (t DateTemplate) Format(date time.Time, weekStart time.Weekday, lang string) string {
+	l := GetLocale(lang)
+	year, week := WeekNumber(date, weekStart)
+
+	if !strings.Contains(string(t), "{week}") {
+		year = date.Year()
+	}
+
+	r := strings.NewReplacer(
+		"{year}", strconv.Itoa(year),
+		"{month}", l.Months[date.Month()],
+		"{m}", strconv.Itoa(int(date.Month())),
+		"{mm}", fmt.Sprintf("%02d", int(date.Month())),
+		"{week}", strconv.Itoa(week),
+		"{weekday}", l.Weekdays[date.Weekday()],
+		"{day}", strconv.Itoa(date.Day()),
+		"{dd}", fmt.Sprintf("%02d", date.Day()),
+	)
+
+	return r.Replace(string(t))
+}
+
+// WeekNumber returns the year and week number of a date.
+// Weeks starting on Monday are numbered according to ISO 8601, so the year may differ
+// from the calendar year. Weeks starting on other days belong to the year of their fourth day,
+// just like ISO weeks belong to the year of their Thursday, so no week is split at New Year.
+func WeekNumber(date time.Time, weekStart time.Weekday) (year, week int) {
+	if weekStart == time.Monday {
+		return date.ISOWeek()
+	}
+
+	start := date.AddDate(0, 0, -((int(date.Weekday()) - int(weekStart) + 7) % 7))
+	mid := start.AddDate(0, 0, 3)
+
+	return mid.Year(), (mid.YearDay()-1)/7 + 1
+}
+
+// ParseWeekday returns the weekday for an English name like "monday" or "sun".
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if len(s) >= 3 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), s) {
+				return d, nil
+			}
+		}
+	}
+
+	return time.Monday, fmt.Errorf("unknown weekday \"%s\"", s)
+}
@@ -0,0 +1,113 @@
+package txt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateTemplate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tpl, err := ParseDateTemplate("{weekday}, {dd}.{mm}.{year}")
+
+		assert.NoError(t, err)
+		assert.Equal(t, DateTemplate("{weekday}, {dd}.{mm}.{year}"), tpl)
+	})
+	t.Run("no tokens", func(t *testing.T) {
+		_, err := ParseDateTemplate("Holidays")
+
+		assert.NoError(t, err)
+	})
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := ParseDateTemplate("{month} {yyyy}")
+
+		assert.EqualError(t, err, "unknown token \"{yyyy}\" in template \"{month} {yyyy}\"")
+	})
+	t.Run("unclosed", func(t *testing.T) {
+		_, err := ParseDateTemplate("{month {year}")
+
+		assert.EqualError(t, err, "unclosed \"{\" in template \"{month {year}\"")
+	})
+	t.Run("unexpected", func(t *testing.T) {
+		_, err := ParseDateTemplate("month} {year}")
+
+		assert.EqualError(t, err, "unexpected \"}\" in template \"month} {year}\"")
+	})
+}
+
+func TestDateTemplate_Format(t *testing.T) {
+	date := time.Date(2020, 8, 6, 12, 0, 0, 0, time.UTC)
+
+	t.Run("en", func(t *testing.T) {
+		assert.Equal(t, "August 2020", DateTemplate(GetLocale("en").MonthTemplate).Format(date, time.Monday, "en"))
+		assert.Equal(t, "Week 32 2020", DateTemplate(GetLocale("en").WeekTemplate).Format(date, time.Monday, "en"))
+	})
+	t.Run("de", func(t *testing.T) {
+		assert.Equal(t, "Donnerstag, 06.08.2020", DateTemplate("{weekday}, {dd}.{mm}.{year}").Format(date, time.Monday, "de"))
+	})
+	t.Run("ja", func(t *testing.T) {
+		assert.Equal(t, "2020年8月", DateTemplate(GetLocale("ja").MonthTemplate).Format(date, time.Monday, "ja"))
+		assert.Equal(t, "2020年8月6日", DateTemplate("{year}年{m}月{day}日").Format(date, time.Sunday, "ja"))
+		assert.Equal(t, "2020年 第32週", DateTemplate(GetLocale("ja").WeekTemplate).Format(date, time.Sunday, "ja"))
+	})
+	t.Run("iso year", func(t *testing.T) {
+		jan1 := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		assert.Equal(t, "Week 53 2020", DateTemplate("Week {week} {year}").Format(jan1, time.Monday, "en"))
+		assert.Equal(t, "Week 53 2020", DateTemplate("Week {week} {year}").Format(jan1, time.Sunday, "en"))
+		assert.Equal(t, "January 2021", DateTemplate("{month} {year}").Format(jan1, time.Monday, "en"))
+	})
+}
+
+func TestWeekNumber(t *testing.T) {
+	t.Run("monday", func(t *testing.T) {
+		year, week := WeekNumber(time.Date(2020, 8, 2, 0, 0, 0, 0, time.UTC), time.Monday)
+
+		assert.Equal(t, 2020, year)
+		assert.Equal(t, 31, week)
+	})
+	t.Run("sunday", func(t *testing.T) {
+		year, week := WeekNumber(time.Date(2020, 8, 2, 0, 0, 0, 0, time.UTC), time.Sunday)
+
+		assert.Equal(t, 2020, year)
+		assert.Equal(t, 32, week)
+	})
+	t.Run("saturday", func(t *testing.T) {
+		// The week from Saturday, Dec 28 belongs to 2019, so the next one is the first of 2020.
+		year, week := WeekNumber(time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC), time.Saturday)
+
+		assert.Equal(t, 2020, year)
+		assert.Equal(t, 1, week)
+
+		year, week = WeekNumber(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), time.Saturday)
+
+		assert.Equal(t, 2019, year)
+		assert.Equal(t, 53, week)
+	})
+	t.Run("sunday new year", func(t *testing.T) {
+		// Thursday, Dec 31 and Saturday, Jan 2 are in the same week.
+		year, week := WeekNumber(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), time.Sunday)
+
+		assert.Equal(t, 2020, year)
+		assert.Equal(t, 53, week)
+
+		year, week = WeekNumber(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), time.Sunday)
+
+		assert.Equal(t, 2020, year)
+		assert.Equal(t, 53, week)
+	})
+}
+
+func TestParseWeekday(t *testing.T) {
+	d, err := ParseWeekday("Sunday")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Sunday, d)
+
+	d, err = ParseWeekday("sat")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Saturday, d)
+
+	_, err = ParseWeekday("xx")
+	assert.EqualError(t, err, "unknown weekday \"xx\"")
+}
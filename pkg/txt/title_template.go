@@ -0,0 +1,105 @@
+package txt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TitleTemplate is a template for generated photo titles like "{label} / {city}, {country} {year}".
+// Alternative tokens are separated by "|", e.g. "{label|name}" uses the first value that isn't empty.
+// Empty tokens are removed together with the separator that follows them.
+type TitleTemplate string
+
+// TitleTokens lists the tokens supported in title templates in addition to date tokens.
+var TitleTokens = []string{"label", "name", "city", "state", "country"}
+
+// ParseTitleTemplate validates a title template and returns an error if it contains unknown tokens.
+func ParseTitleTemplate(s string) (TitleTemplate, error) {
+	_, tokens, err := splitTemplate(s)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, token := range tokens {
+		for _, alt := range strings.Split(token, "|") {
+			if !isTitleToken(alt) && !isDateToken(alt) {
+				return "", fmt.Errorf("unknown token \"{%s}\" in template \"%s\"", alt, s)
+			}
+		}
+	}
+
+	return TitleTemplate(s), nil
+}
+
+func isTitleToken(token string) bool {
+	for _, t := range TitleTokens {
+		if t == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Format returns the title with tokens replaced by values, e.g. values["city"]. Date tokens are
+// replaced by localized values for date, with week numbers depending on weekStart. They are empty
+// if the date is unknown. The result is empty if all tokens are.
+func (t TitleTemplate) Format(values map[string]string, date time.Time, weekStart time.Weekday, lang string) string {
+	literals, tokens, err := splitTemplate(string(t))
+
+	if err != nil || len(tokens) == 0 {
+		return strings.TrimSpace(string(t))
+	}
+
+	var result strings.Builder
+
+	sep := ""
+	found := false
+	last := ""
+
+	for i, token := range tokens {
+		last = ""
+
+		for _, alt := range strings.Split(token, "|") {
+			if isDateToken(alt) {
+				if !date.IsZero() {
+					last = DateTemplate("{"+alt+"}").Format(date, weekStart, lang)
+				}
+			} else {
+				last = strings.TrimSpace(values[alt])
+			}
+
+			if last != "" {
+				break
+			}
+		}
+
+		// The separator before an empty token is kept for the next value.
+		if last == "" {
+			continue
+		}
+
+		if found {
+			result.WriteString(sep)
+		} else {
+			result.WriteString(literals[0])
+		}
+
+		result.WriteString(last)
+		found = true
+		sep = literals[i+1]
+	}
+
+	if !found {
+		return ""
+	}
+
+	// The text after the last token is only kept if it was replaced by a value.
+	if last != "" {
+		result.WriteString(literals[len(literals)-1])
+	}
+
+	return strings.TrimSpace(result.String())
+}
@@ -0,0 +1,78 @@
+package txt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTitleTemplate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tpl, err := ParseTitleTemplate("{label|name} / {city}, {country} {year}")
+
+		assert.NoError(t, err)
+		assert.Equal(t, TitleTemplate("{label|name} / {city}, {country} {year}"), tpl)
+	})
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := ParseTitleTemplate("{label|town} {year}")
+
+		assert.EqualError(t, err, "unknown token \"{town}\" in template \"{label|town} {year}\"")
+	})
+	t.Run("unclosed", func(t *testing.T) {
+		_, err := ParseTitleTemplate("{label / {year}")
+
+		assert.EqualError(t, err, "unclosed \"{\" in template \"{label / {year}\"")
+	})
+}
+
+func TestTitleTemplate_Format(t *testing.T) {
+	date := time.Date(2020, 8, 6, 12, 0, 0, 0, time.UTC)
+	tpl := TitleTemplate("{label} / {city}, {country} {year}")
+
+	t.Run("all values", func(t *testing.T) {
+		values := map[string]string{"label": "Beach", "city": "Berlin", "country": "Germany"}
+
+		assert.Equal(t, "Beach / Berlin, Germany 2020", tpl.Format(values, date, time.Monday, "en"))
+	})
+	t.Run("empty first token", func(t *testing.T) {
+		values := map[string]string{"city": "Berlin", "country": "Germany"}
+
+		assert.Equal(t, "Berlin, Germany 2020", tpl.Format(values, date, time.Monday, "en"))
+	})
+	t.Run("empty token", func(t *testing.T) {
+		values := map[string]string{"label": "Beach", "country": "Germany"}
+
+		assert.Equal(t, "Beach / Germany 2020", tpl.Format(values, date, time.Monday, "en"))
+	})
+	t.Run("empty last token", func(t *testing.T) {
+		values := map[string]string{"label": "Beach", "city": "Berlin", "country": "Germany"}
+
+		assert.Equal(t, "Beach / Berlin, Germany", tpl.Format(values, time.Time{}, time.Monday, "en"))
+	})
+	t.Run("all empty", func(t *testing.T) {
+		assert.Equal(t, "", tpl.Format(nil, time.Time{}, time.Monday, "en"))
+	})
+	t.Run("fallback chain", func(t *testing.T) {
+		chain := TitleTemplate("{label|name|city} / {year}")
+
+		assert.Equal(t, "Brandenburger Tor / 2020", chain.Format(map[string]string{"name": "Brandenburger Tor", "city": "Berlin"}, date, time.Monday, "en"))
+		assert.Equal(t, "Berlin / 2020", chain.Format(map[string]string{"city": "Berlin"}, date, time.Monday, "en"))
+		assert.Equal(t, "2020", chain.Format(nil, date, time.Monday, "en"))
+	})
+	t.Run("localized", func(t *testing.T) {
+		assert.Equal(t, "Strand / August 2020", TitleTemplate("{label} / {month} {year}").Format(map[string]string{"label": "Strand"}, date, time.Monday, "de"))
+		assert.Equal(t, "Berlin, 6. August 2020", TitleTemplate("{city}, {day}. {month} {year}").Format(map[string]string{"city": "Berlin"}, date, time.Monday, "de"))
+		assert.Equal(t, "2020年8月", TitleTemplate("{year}年{m}月").Format(nil, date, time.Monday, "ja"))
+	})
+	t.Run("week start", func(t *testing.T) {
+		sunday := time.Date(2020, 8, 9, 12, 0, 0, 0, time.UTC)
+		week := TitleTemplate("{city} {year}/{week}")
+
+		assert.Equal(t, "Berlin 2020/32", week.Format(map[string]string{"city": "Berlin"}, sunday, time.Monday, "en"))
+		assert.Equal(t, "Berlin 2020/33", week.Format(map[string]string{"city": "Berlin"}, sunday, time.Sunday, "en"))
+	})
+	t.Run("no tokens", func(t *testing.T) {
+		assert.Equal(t, "Holidays", TitleTemplate("Holidays").Format(nil, date, time.Monday, "en"))
+	})
+}
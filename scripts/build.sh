@@ -2,6 +2,8 @@
 
 PHOTOPRISM_DATE=`date -u +%y%m%d`
 PHOTOPRISM_VERSION=`git describe --always`
+PHOTOPRISM_COMMIT=`git rev-parse --short HEAD`
+PHOTOPRISM_BUILT=`date -u +%Y-%m-%dT%H:%M:%SZ`
 
 if [[ -z $1 ]] || [[ -z $2 ]]; then
     echo "Please provide build mode and output file name" 1>&2
@@ -27,17 +29,17 @@ fi
 
 if [[ $1 == "debug" ]]; then
   echo "Building development binary..."
-	go build -ldflags "-X main.version=${PHOTOPRISM_DATE}-${PHOTOPRISM_VERSION}-${PHOTOPRISM_OS}-${PHOTOPRISM_ARCH}-DEBUG" -o $2 cmd/photoprism/photoprism.go
+	go build -ldflags "-X main.version=${PHOTOPRISM_DATE}-${PHOTOPRISM_VERSION}-${PHOTOPRISM_OS}-${PHOTOPRISM_ARCH}-DEBUG -X main.commit=${PHOTOPRISM_COMMIT} -X main.built=${PHOTOPRISM_BUILT}" -o $2 cmd/photoprism/photoprism.go
 	du -h $2
 	echo "Done."
 elif [[ $1 == "static" ]]; then
   echo "Building static production binary..."
-	go build -a -v -ldflags "-linkmode external -extldflags \"-static -L /usr/lib -ltensorflow\" -s -w -X main.version=${PHOTOPRISM_DATE}-${PHOTOPRISM_VERSION}-${PHOTOPRISM_OS}-${PHOTOPRISM_ARCH}" -o $2 cmd/photoprism/photoprism.go
+	go build -a -v -ldflags "-linkmode external -extldflags \"-static -L /usr/lib -ltensorflow\" -s -w -X main.version=${PHOTOPRISM_DATE}-${PHOTOPRISM_VERSION}-${PHOTOPRISM_OS}-${PHOTOPRISM_ARCH} -X main.commit=${PHOTOPRISM_COMMIT} -X main.built=${PHOTOPRISM_BUILT}" -o $2 cmd/photoprism/photoprism.go
 	du -h $2
 	echo "Done."
 else
   echo "Building production binary..."
-	go build -ldflags "-s -w -X main.version=${PHOTOPRISM_DATE}-${PHOTOPRISM_VERSION}-${PHOTOPRISM_OS}-${PHOTOPRISM_ARCH}" -o $2 cmd/photoprism/photoprism.go
+	go build -ldflags "-s -w -X main.version=${PHOTOPRISM_DATE}-${PHOTOPRISM_VERSION}-${PHOTOPRISM_OS}-${PHOTOPRISM_ARCH} -X main.commit=${PHOTOPRISM_COMMIT} -X main.built=${PHOTOPRISM_BUILT}" -o $2 cmd/photoprism/photoprism.go
 	du -h $2
 	echo "Done."
 fi